@@ -0,0 +1,201 @@
+// Package anonymize replaces the personal data in a sandbox copied from production.
+// It reads the records of the configured entity sets, applies a [Rule] to each field
+// and writes the changed fields back in batches:
+//
+//	result, err := anonymize.Run(ctx, client, anonymize.Options{Key: []byte(secret)})
+//
+// The replacements are deterministic for a Key, so the same email on a customer and
+// a contact gets the same replacement and they still match after anonymizing.
+package anonymize
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"maps"
+	mathrand "math/rand/v2"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/google/uuid"
+)
+
+// ErrProduction is returned by [Run] for a client of the Production environment
+// without [Options.AllowProduction].
+var ErrProduction = errors.New("anonymize: refusing to write to the Production environment")
+
+// EntityRules are the rules of the fields of an entity set.
+type EntityRules struct {
+	EntitySetName string
+	// Fields maps the field names to their rules. Fields without a rule are kept.
+	Fields map[string]Rule
+	// Filter limits the records to anonymize, e.g. to keep the internal employees.
+	Filter string
+}
+
+// Options configure [Run].
+type Options struct {
+	// Rules are applied in order. Defaults to [DefaultRules].
+	Rules []EntityRules
+	// Key seeds the replacements. Use the same key to get the same replacements across
+	// runs and sandboxes. Defaults to a random key.
+	Key []byte
+	// DryRun counts the changes without writing them.
+	DryRun bool
+	// AllowProduction allows a client of the Production environment.
+	AllowProduction bool
+	// MaxPageSize is the page size to read the records. Defaults to 1000.
+	MaxPageSize int
+	// ChunkSize is the number of updates per batch. Defaults to bc.MaxBatchSize.
+	ChunkSize int
+}
+
+// RecordError is an update that BC rejected.
+type RecordError struct {
+	EntitySetName string
+	ID            uuid.UUID
+	Err           error
+}
+
+func (re RecordError) Error() string {
+	return fmt.Sprintf("%s(%s): %s", re.EntitySetName, re.ID, re.Err)
+}
+
+func (re RecordError) Unwrap() error {
+	return re.Err
+}
+
+// EntitySetResult counts the records of an entity set.
+type EntitySetResult struct {
+	EntitySetName string
+	// Read is the number of records read.
+	Read int
+	// Changed is the number of records with at least one replaced field.
+	Changed int
+	// Updated is the number of changed records that were written, 0 for a dry run.
+	Updated int
+}
+
+// Result describes what [Run] changed.
+type Result struct {
+	EntitySets []EntitySetResult
+	Errors     []RecordError
+}
+
+// record is a record of any entity set.
+type record map[string]any
+
+func (record) Validate() error { return nil }
+
+// Run anonymizes the records of every entity set in the rules. Records that changed
+// since they were read are not overwritten and are in the [Result] errors, run again
+// to anonymize them. The error is only non-nil if an entity set cannot be read or a
+// whole batch fails to send.
+func Run(ctx context.Context, client *bc.Client, opts Options) (Result, error) {
+	if !opts.AllowProduction && strings.EqualFold(client.Config().Environment, "Production") {
+		return Result{}, ErrProduction
+	}
+	rules := opts.Rules
+	if rules == nil {
+		rules = DefaultRules
+	}
+	key := opts.Key
+	if len(key) == 0 {
+		key = make([]byte, 32)
+		rand.Read(key)
+	}
+
+	var result Result
+	for _, er := range rules {
+		res, errs, err := runEntitySet(ctx, client, er, key, opts)
+		result.EntitySets = append(result.EntitySets, res)
+		result.Errors = append(result.Errors, errs...)
+		if err != nil {
+			return result, fmt.Errorf("anonymize %s: %w", er.EntitySetName, err)
+		}
+	}
+	return result, nil
+}
+
+func runEntitySet(ctx context.Context, client *bc.Client, er EntityRules, key []byte, opts Options) (EntitySetResult, []RecordError, error) {
+	res := EntitySetResult{EntitySetName: er.EntitySetName}
+	fields := slices.Sorted(maps.Keys(er.Fields))
+
+	page := bc.NewAPIPage[record](client, er.EntitySetName)
+	listOpts := bc.ListOptions{Filter: er.Filter, Select: append([]string{"id"}, fields...), MaxPageSize: opts.MaxPageSize}
+	if listOpts.MaxPageSize <= 0 {
+		listOpts.MaxPageSize = 1000
+	}
+
+	var requests []bc.RequestOptions
+	var nextLink string
+	for {
+		list, err := page.ListPage(ctx, nextLink, listOpts)
+		if err != nil {
+			return res, nil, err
+		}
+		for _, r := range list.Value {
+			res.Read++
+			body := Record(r, er.Fields, key)
+			if len(body) == 0 {
+				continue
+			}
+			s, _ := r["id"].(string)
+			id, err := uuid.Parse(s)
+			if err != nil {
+				return res, nil, fmt.Errorf("record has no id: %w", err)
+			}
+			etag, _ := r["@odata.etag"].(string)
+			res.Changed++
+			requests = append(requests, bc.RequestOptions{Method: http.MethodPatch, EntitySetName: er.EntitySetName, RecordID: id, Body: body, ETag: etag})
+		}
+		if list.NextLink == "" {
+			break
+		}
+		nextLink = list.NextLink
+	}
+	if opts.DryRun || len(requests) == 0 {
+		return res, nil, nil
+	}
+
+	var errs []RecordError
+	responses, err := client.Bulk(ctx, requests, bc.BulkOptions{ChunkSize: opts.ChunkSize})
+	for i, r := range responses {
+		if err := r.Err(); err != nil {
+			errs = append(errs, RecordError{EntitySetName: er.EntitySetName, ID: requests[i].RecordID, Err: err})
+			continue
+		}
+		res.Updated++
+	}
+	return res, errs, err
+}
+
+// Record returns the fields of r that the rules replace with a different value.
+// Only non-empty string fields are replaced.
+func Record(r map[string]any, rules map[string]Rule, key []byte) map[string]any {
+	changed := map[string]any{}
+	for field, rule := range rules {
+		v, ok := r[field].(string)
+		if !ok || v == "" {
+			continue
+		}
+		if nv := rule(v, seeded(key, v)); nv != v {
+			changed[field] = nv
+		}
+	}
+	return changed
+}
+
+// seeded returns a rand seeded from the HMAC of the value.
+func seeded(key []byte, value string) *mathrand.Rand {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(value))
+	sum := mac.Sum(nil)
+	return mathrand.New(mathrand.NewPCG(binary.BigEndian.Uint64(sum[:8]), binary.BigEndian.Uint64(sum[8:16])))
+}
@@ -0,0 +1,76 @@
+package anonymize_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/erlorenz/bc-go/anonymize"
+	"github.com/erlorenz/bc-go/bcfake"
+	"github.com/erlorenz/bc-go/internal/bctest"
+)
+
+func TestRun(t *testing.T) {
+	server := bcfake.New()
+	server.Seed("customers",
+		map[string]any{"displayName": "Adatum", "email": "info@adatum.com", "phoneNumber": "555-0100"},
+		map[string]any{"displayName": "Fabrikam", "email": "", "phoneNumber": "555-0101"},
+	)
+	server.Seed("contacts", map[string]any{"displayName": "John Smith", "email": "info@adatum.com"})
+	client := bctest.NewClient(t, server)
+
+	opts := anonymize.Options{
+		Key:         []byte("key"),
+		MaxPageSize: 1,
+		Rules: []anonymize.EntityRules{
+			{EntitySetName: "customers", Fields: map[string]anonymize.Rule{
+				"displayName": anonymize.CompanyName(), "email": anonymize.Email(), "phoneNumber": anonymize.Digits(),
+			}},
+			{EntitySetName: "contacts", Fields: map[string]anonymize.Rule{
+				"displayName": anonymize.Name(), "email": anonymize.Email(),
+			}},
+		},
+	}
+
+	result, err := anonymize.Run(context.Background(), client, anonymize.Options{Rules: opts.Rules, Key: opts.Key, DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.EntitySets) != 2 || result.EntitySets[0].Changed != 2 || result.EntitySets[0].Updated != 0 {
+		t.Errorf("unexpected dry run result %+v", result)
+	}
+	if server.Records("customers")[0]["displayName"] != "Adatum" {
+		t.Error("wanted the dry run not to write")
+	}
+
+	result, err = anonymize.Run(context.Background(), client, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Errors) != 0 || result.EntitySets[0].Read != 2 || result.EntitySets[0].Updated != 2 || result.EntitySets[1].Updated != 1 {
+		t.Errorf("unexpected result %+v", result)
+	}
+
+	customers, contacts := server.Records("customers"), server.Records("contacts")
+	for _, c := range customers {
+		if c["displayName"] == "Adatum" || c["displayName"] == "Fabrikam" {
+			t.Errorf("wanted the name to be replaced, got %v", c)
+		}
+	}
+	if customers[1]["email"] != "" {
+		t.Errorf("wanted the empty email to stay empty, got %v", customers[1])
+	}
+	if contacts[0]["email"] != customers[0]["email"] {
+		t.Errorf("wanted the same email to get the same replacement, got %v and %v", contacts[0]["email"], customers[0]["email"])
+	}
+}
+
+func TestRunProduction(t *testing.T) {
+	server := bcfake.New()
+	config := bctest.ClientConfig()
+	config.Environment = "production"
+	_, err := anonymize.Run(context.Background(), bctest.NewClientConfig(t, config, server), anonymize.Options{})
+	if !errors.Is(err, anonymize.ErrProduction) {
+		t.Errorf("wanted ErrProduction, got %v", err)
+	}
+}
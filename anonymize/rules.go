@@ -0,0 +1,151 @@
+package anonymize
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"strings"
+)
+
+// Rule returns the anonymized value of a field. The rand is seeded from the original
+// value and the [Options] Key, so the same value always gets the same replacement
+// and a name or email stays consistent across entity sets. Empty values are not
+// passed to rules and stay empty.
+type Rule func(value string, rnd *rand.Rand) string
+
+var (
+	firstNames = []string{"Alex", "Bailey", "Casey", "Devon", "Emery", "Finley", "Harper", "Jordan", "Kai", "Logan",
+		"Morgan", "Noel", "Parker", "Quinn", "Riley", "Rowan", "Sage", "Skyler", "Taylor", "Jamie"}
+	lastNames = []string{"Adams", "Brooks", "Carter", "Dalton", "Ellis", "Foster", "Grant", "Hayes", "Iverson", "Jensen",
+		"Keller", "Lambert", "Mercer", "Nolan", "Owens", "Porter", "Reyes", "Sutton", "Turner", "Walsh"}
+	companyWords = []string{"Alpine", "Beacon", "Cedar", "Delta", "Summit", "Harbor", "Maple", "Northwind", "Orbit", "Pioneer",
+		"Quarry", "Redwood", "Silver", "Trident", "Union", "Vertex", "Willow", "Zenith", "Granite", "Horizon"}
+	companySuffixes = []string{"Ltd.", "Inc.", "GmbH", "Group", "Trading", "Supplies", "Partners", "Industries"}
+	streets         = []string{"Main Street", "Station Road", "Church Lane", "Park Avenue", "Mill Road", "High Street", "Oak Drive", "Lake View"}
+)
+
+func pick(rnd *rand.Rand, list []string) string {
+	return list[rnd.IntN(len(list))]
+}
+
+// FirstName replaces the value with a first name.
+func FirstName() Rule {
+	return func(_ string, rnd *rand.Rand) string {
+		return pick(rnd, firstNames)
+	}
+}
+
+// LastName replaces the value with a last name.
+func LastName() Rule {
+	return func(_ string, rnd *rand.Rand) string {
+		return pick(rnd, lastNames)
+	}
+}
+
+// Name replaces the value with a first and last name.
+func Name() Rule {
+	return func(_ string, rnd *rand.Rand) string {
+		return pick(rnd, firstNames) + " " + pick(rnd, lastNames)
+	}
+}
+
+// CompanyName replaces the value with a company name.
+func CompanyName() Rule {
+	return func(_ string, rnd *rand.Rand) string {
+		return pick(rnd, companyWords) + " " + pick(rnd, companyWords) + " " + pick(rnd, companySuffixes)
+	}
+}
+
+// Email replaces the value with an address at example.com, which never receives mail.
+func Email() Rule {
+	return func(_ string, rnd *rand.Rand) string {
+		return fmt.Sprintf("%s.%s.%04x@example.com", strings.ToLower(pick(rnd, firstNames)), strings.ToLower(pick(rnd, lastNames)), rnd.IntN(0x10000))
+	}
+}
+
+// Street replaces the value with a house number and a street.
+func Street() Rule {
+	return func(_ string, rnd *rand.Rand) string {
+		return fmt.Sprintf("%d %s", 1+rnd.IntN(200), pick(rnd, streets))
+	}
+}
+
+// Digits replaces every digit with a random one and keeps the rest, so phone numbers
+// and VAT numbers like "DE123456789" keep their format and country prefix.
+func Digits() Rule {
+	return func(value string, rnd *rand.Rand) string {
+		b := []byte(value)
+		for i, c := range b {
+			if '0' <= c && c <= '9' {
+				b[i] = byte('0' + rnd.IntN(10))
+			}
+		}
+		return string(b)
+	}
+}
+
+// Mask replaces the characters with "*" except the first keepStart and the last keepEnd,
+// e.g. Mask(2, 0) for a VAT number keeps the country code.
+func Mask(keepStart, keepEnd int) Rule {
+	return func(value string, _ *rand.Rand) string {
+		r := []rune(value)
+		for i := keepStart; i < len(r)-keepEnd; i++ {
+			r[i] = '*'
+		}
+		return string(r)
+	}
+}
+
+// Fixed replaces the value with v.
+func Fixed(v string) Rule {
+	return func(string, *rand.Rand) string {
+		return v
+	}
+}
+
+// Clear replaces the value with an empty string.
+func Clear() Rule {
+	return Fixed("")
+}
+
+// DefaultRules anonymize the personal data of the standard API entities.
+var DefaultRules = []EntityRules{
+	{EntitySetName: "customers", Fields: map[string]Rule{
+		"displayName":           CompanyName(),
+		"email":                 Email(),
+		"phoneNumber":           Digits(),
+		"addressLine1":          Street(),
+		"addressLine2":          Clear(),
+		"website":               Clear(),
+		"taxRegistrationNumber": Digits(),
+	}},
+	{EntitySetName: "vendors", Fields: map[string]Rule{
+		"displayName":           CompanyName(),
+		"email":                 Email(),
+		"phoneNumber":           Digits(),
+		"addressLine1":          Street(),
+		"addressLine2":          Clear(),
+		"website":               Clear(),
+		"taxRegistrationNumber": Digits(),
+	}},
+	{EntitySetName: "contacts", Fields: map[string]Rule{
+		"displayName":       Name(),
+		"companyName":       CompanyName(),
+		"email":             Email(),
+		"phoneNumber":       Digits(),
+		"mobilePhoneNumber": Digits(),
+		"addressLine1":      Street(),
+		"addressLine2":      Clear(),
+	}},
+	{EntitySetName: "employees", Fields: map[string]Rule{
+		"givenName":     FirstName(),
+		"middleName":    Clear(),
+		"surname":       LastName(),
+		"email":         Email(),
+		"personalEmail": Email(),
+		"phoneNumber":   Digits(),
+		"mobilePhone":   Digits(),
+		"addressLine1":  Street(),
+		"addressLine2":  Clear(),
+		"birthDate":     Fixed("0001-01-01"),
+	}},
+}
@@ -0,0 +1,49 @@
+package anonymize_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/erlorenz/bc-go/anonymize"
+)
+
+func TestRules(t *testing.T) {
+	key := []byte("key")
+	tests := []struct {
+		name  string
+		rule  anonymize.Rule
+		value string
+		match string
+	}{
+		{"Name", anonymize.Name(), "John Smith", `^[A-Z][a-z]+ [A-Z][a-z]+$`},
+		{"CompanyName", anonymize.CompanyName(), "Contoso Ltd.", `^\w+ \w+ \S+$`},
+		{"Email", anonymize.Email(), "john@contoso.com", `^[a-z]+\.[a-z]+\.[0-9a-f]{4}@example\.com$`},
+		{"Street", anonymize.Street(), "1 Microsoft Way", `^[0-9]+ [A-Z]`},
+		{"Digits", anonymize.Digits(), "DE 123-456", `^DE [0-9]{3}-[0-9]{3}$`},
+		{"Mask", anonymize.Mask(2, 1), "GB123456", `^GB\*\*\*\*\*6$`},
+		{"Fixed", anonymize.Fixed("x"), "y", `^x$`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := anonymize.Record(map[string]any{"f": tt.value}, map[string]anonymize.Rule{"f": tt.rule}, key)["f"]
+			s, _ := got.(string)
+			if !regexp.MustCompile(tt.match).MatchString(s) {
+				t.Errorf("wanted %s to match %s, got %q", tt.value, tt.match, got)
+			}
+		})
+	}
+}
+
+func TestRecordDeterministic(t *testing.T) {
+	rules := map[string]anonymize.Rule{"email": anonymize.Email(), "phoneNumber": anonymize.Digits(), "count": anonymize.Clear()}
+	r := map[string]any{"email": "john@contoso.com", "phoneNumber": "", "count": 4}
+
+	a := anonymize.Record(r, rules, []byte("key"))
+	b := anonymize.Record(r, rules, []byte("key"))
+	if len(a) != 1 || a["email"] != b["email"] {
+		t.Errorf("wanted the same replacement for the same key, got %v and %v", a, b)
+	}
+	if c := anonymize.Record(r, rules, []byte("other")); c["email"] == a["email"] {
+		t.Errorf("wanted another replacement for another key, got %v", c)
+	}
+}
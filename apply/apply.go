@@ -0,0 +1,445 @@
+// Package apply converges configuration entity sets, such as payment terms, posting
+// groups or currencies, to a declarative [Config]. [NewPlan] diffs the config against the
+// environment and [Apply] creates, updates and deletes the records in the plan, like
+// terraform plan and apply. This seeds sandboxes reproducibly and keeps setup in version control.
+//
+// A config is JSON:
+//
+//	{"entitySets": [
+//		{"name": "paymentTerms", "key": ["code"], "records": [
+//			{"code": "NET30", "displayName": "Net 30 days", "dueDateCalculation": "30D"}
+//		]}
+//	]}
+//
+// Then:
+//
+//	cfg, err := apply.LoadFile("setup.json")
+//	plan, err := apply.NewPlan(ctx, client, cfg, apply.Options{})
+//	fmt.Print(plan)
+//	result, err := apply.Apply(ctx, client, plan, apply.Options{})
+package apply
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/google/uuid"
+)
+
+// DefaultKey identifies the records of an entity set without a Key.
+// Most configuration entities have a unique code.
+var DefaultKey = []string{"code"}
+
+// Config is the desired state of the entity sets.
+type Config struct {
+	EntitySets []EntitySet `json:"entitySets"`
+}
+
+// EntitySet has the desired records of an entity set.
+type EntitySet struct {
+	// Name is the entity set name, e.g. "paymentTerms".
+	Name string `json:"name"`
+	// Key are the fields that identify a record. Defaults to DefaultKey.
+	// String keys are matched ignoring case, as BC stores codes in upper case.
+	Key []string `json:"key,omitempty"`
+	// Prune deletes the records that are not in Records.
+	Prune bool `json:"prune,omitempty"`
+	// Records are compared only on the fields they set, other fields are left as they are.
+	Records []map[string]any `json:"records"`
+}
+
+func (es EntitySet) key() []string {
+	if len(es.Key) == 0 {
+		return DefaultKey
+	}
+	return es.Key
+}
+
+// Load decodes a Config and validates it.
+func Load(r io.Reader) (Config, error) {
+	var cfg Config
+	d := json.NewDecoder(r)
+	d.DisallowUnknownFields()
+	if err := d.Decode(&cfg); err != nil {
+		return cfg, fmt.Errorf("load config: %w", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return cfg, fmt.Errorf("load config: %w", err)
+	}
+	return cfg, nil
+}
+
+// LoadFile loads the Config from a file.
+func LoadFile(path string) (Config, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Config{}, fmt.Errorf("load config: %w", err)
+	}
+	defer f.Close()
+	return Load(f)
+}
+
+// Validate checks that the entity sets are unique and every record has a unique key.
+func (c Config) Validate() error {
+	var errs []error
+	seen := map[string]bool{}
+	for i, es := range c.EntitySets {
+		if es.Name == "" {
+			errs = append(errs, fmt.Errorf("entity set %d: name is empty", i))
+			continue
+		}
+		if seen[es.Name] {
+			errs = append(errs, fmt.Errorf("%s: entity set is repeated", es.Name))
+		}
+		seen[es.Name] = true
+
+		keys := map[string]bool{}
+		for j, r := range es.Records {
+			k, err := recordKey(es.key(), r)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: record %d: %w", es.Name, j, err))
+				continue
+			}
+			if keys[k] {
+				errs = append(errs, fmt.Errorf("%s: record %d: repeated key %s", es.Name, j, formatKey(es.key(), r)))
+			}
+			keys[k] = true
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// recordKey returns the key values of the record for matching. It fails if one is missing.
+func recordKey(key []string, r map[string]any) (string, error) {
+	values := make([]string, len(key))
+	for i, field := range key {
+		v, ok := r[field]
+		if !ok || v == nil {
+			return "", fmt.Errorf("key field %s is missing", field)
+		}
+		if s, ok := v.(string); ok {
+			v = strings.ToUpper(s)
+		}
+		values[i] = fmt.Sprint(v)
+	}
+	return strings.Join(values, "\x00"), nil
+}
+
+// formatKey formats the key of the record as "code=NET30".
+func formatKey(key []string, r map[string]any) string {
+	parts := make([]string, len(key))
+	for i, field := range key {
+		parts[i] = fmt.Sprintf("%s=%v", field, r[field])
+	}
+	return strings.Join(parts, ",")
+}
+
+// Action is what a [Change] does.
+type Action int
+
+const (
+	Create Action = iota
+	Update
+	Delete
+)
+
+func (a Action) String() string {
+	switch a {
+	case Create:
+		return "create"
+	case Update:
+		return "update"
+	case Delete:
+		return "delete"
+	}
+	return fmt.Sprintf("Action(%d)", int(a))
+}
+
+// symbol is the prefix of the change in the plan output.
+func (a Action) symbol() string {
+	return [...]string{"+", "~", "-"}[a]
+}
+
+// Change is a single request of a [Plan].
+type Change struct {
+	EntitySetName string
+	Action        Action
+	// Key identifies the record, e.g. "code=NET30".
+	Key string
+	// ID and ETag are those of the existing record for an Update or Delete.
+	// The ETag makes the change fail if the record changed after the plan.
+	ID   uuid.UUID
+	ETag string
+	// Fields is the body of a Create, or the changed fields of an Update.
+	Fields map[string]any
+	// Current has the current values of the changed fields of an Update.
+	Current map[string]any
+}
+
+// Plan has the changes to converge the environment to a Config,
+// the creates and updates first and then the deletes in reverse order of the entity sets.
+type Plan struct {
+	Changes []Change
+}
+
+// Empty reports whether the environment already matches the config.
+func (p Plan) Empty() bool {
+	return len(p.Changes) == 0
+}
+
+// Count returns the number of changes with the action.
+func (p Plan) Count(action Action) int {
+	n := 0
+	for _, c := range p.Changes {
+		if c.Action == action {
+			n++
+		}
+	}
+	return n
+}
+
+// String formats the plan for review, with the fields of each change
+// and the current and desired values of updates.
+func (p Plan) String() string {
+	var b strings.Builder
+	for _, c := range p.Changes {
+		fmt.Fprintf(&b, "%s %s %s\n", c.Action.symbol(), c.EntitySetName, c.Key)
+		for _, field := range slices.Sorted(maps.Keys(c.Fields)) {
+			if c.Action == Update {
+				fmt.Fprintf(&b, "    %s: %s -> %s\n", field, formatValue(c.Current[field]), formatValue(c.Fields[field]))
+			} else {
+				fmt.Fprintf(&b, "    %s: %s\n", field, formatValue(c.Fields[field]))
+			}
+		}
+	}
+	fmt.Fprintf(&b, "Plan: %d to create, %d to update, %d to delete.\n", p.Count(Create), p.Count(Update), p.Count(Delete))
+	return b.String()
+}
+
+func formatValue(v any) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
+
+// Options configure [NewPlan] and [Apply].
+type Options struct {
+	// MaxPageSize is the page size to read the current records. Defaults to 1000.
+	MaxPageSize int
+	// ChunkSize is the number of changes per batch. Defaults to bc.MaxBatchSize.
+	ChunkSize int
+}
+
+// record is a record of any entity set.
+type record map[string]any
+
+func (record) Validate() error { return nil }
+
+// NewPlan reads the current records of every entity set in the config and returns the
+// changes to converge them. Nothing is changed.
+func NewPlan(ctx context.Context, client *bc.Client, cfg Config, opts Options) (Plan, error) {
+	if err := cfg.Validate(); err != nil {
+		return Plan{}, fmt.Errorf("plan: %w", err)
+	}
+
+	var plan Plan
+	var deletes [][]Change
+	for _, es := range cfg.EntitySets {
+		current, err := readAll(ctx, client, es.Name, opts)
+		if err != nil {
+			return Plan{}, fmt.Errorf("plan %s: %w", es.Name, err)
+		}
+		changes, pruned, err := diff(es, current)
+		if err != nil {
+			return Plan{}, fmt.Errorf("plan %s: %w", es.Name, err)
+		}
+		plan.Changes = append(plan.Changes, changes...)
+		deletes = append(deletes, pruned)
+	}
+
+	// Later entity sets may refer to earlier ones, so they are deleted first
+	for _, pruned := range slices.Backward(deletes) {
+		plan.Changes = append(plan.Changes, pruned...)
+	}
+	return plan, nil
+}
+
+// readAll reads every record of the entity set, following the nextLinks.
+func readAll(ctx context.Context, client *bc.Client, entitySetName string, opts Options) ([]record, error) {
+	page := bc.NewAPIPage[record](client, entitySetName)
+	listOpts := bc.ListOptions{MaxPageSize: opts.MaxPageSize}
+	if listOpts.MaxPageSize <= 0 {
+		listOpts.MaxPageSize = 1000
+	}
+
+	var records []record
+	var nextLink string
+	for {
+		res, err := page.ListPage(ctx, nextLink, listOpts)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, res.Value...)
+		if res.NextLink == "" {
+			return records, nil
+		}
+		nextLink = res.NextLink
+	}
+}
+
+// diff returns the creates and updates for the desired records, and the deletes
+// of the current records that are not desired if the entity set is pruned.
+func diff(es EntitySet, current []record) (changes, deletes []Change, err error) {
+	key := es.key()
+	byKey := make(map[string]record, len(current))
+	for _, r := range current {
+		k, err := recordKey(key, r)
+		if err != nil {
+			return nil, nil, fmt.Errorf("record %v: %w", r["id"], err)
+		}
+		byKey[k] = r
+	}
+
+	desired := map[string]bool{}
+	for _, want := range es.Records {
+		k, _ := recordKey(key, want)
+		desired[k] = true
+
+		have, ok := byKey[k]
+		if !ok {
+			changes = append(changes, Change{EntitySetName: es.Name, Action: Create, Key: formatKey(key, want), Fields: want})
+			continue
+		}
+
+		fields, values := map[string]any{}, map[string]any{}
+		for field, v := range want {
+			if slices.Contains(key, field) || equal(have[field], v) {
+				continue
+			}
+			fields[field] = v
+			values[field] = have[field]
+		}
+		if len(fields) > 0 {
+			id, etag, err := identity(have)
+			if err != nil {
+				return nil, nil, err
+			}
+			changes = append(changes, Change{EntitySetName: es.Name, Action: Update, Key: formatKey(key, want),
+				ID: id, ETag: etag, Fields: fields, Current: values})
+		}
+	}
+
+	if !es.Prune {
+		return changes, nil, nil
+	}
+	for _, r := range current {
+		if k, _ := recordKey(key, r); desired[k] {
+			continue
+		}
+		id, etag, err := identity(r)
+		if err != nil {
+			return nil, nil, err
+		}
+		deletes = append(deletes, Change{EntitySetName: es.Name, Action: Delete, Key: formatKey(key, r), ID: id, ETag: etag})
+	}
+	return changes, deletes, nil
+}
+
+// identity returns the id and the etag of a current record.
+func identity(r record) (uuid.UUID, string, error) {
+	s, _ := r["id"].(string)
+	id, err := uuid.Parse(s)
+	if err != nil {
+		return uuid.Nil, "", fmt.Errorf("record has no id: %w", err)
+	}
+	etag, _ := r["@odata.etag"].(string)
+	return id, etag, nil
+}
+
+// equal compares the JSON values, so a number from the config equals the same number from BC.
+func equal(have, want any) bool {
+	if reflect.DeepEqual(have, want) {
+		return true
+	}
+	a, errA := json.Marshal(have)
+	b, errB := json.Marshal(want)
+	return errA == nil && errB == nil && bytes.Equal(a, b)
+}
+
+// ChangeError is a change that BC rejected.
+type ChangeError struct {
+	Change Change
+	Err    error
+}
+
+func (ce ChangeError) Error() string {
+	return fmt.Sprintf("%s %s %s: %s", ce.Change.Action, ce.Change.EntitySetName, ce.Change.Key, ce.Err)
+}
+
+func (ce ChangeError) Unwrap() error {
+	return ce.Err
+}
+
+// Result describes what [Apply] changed.
+type Result struct {
+	Created int
+	Updated int
+	Deleted int
+	Errors  []ChangeError
+}
+
+// Apply sends the changes of the plan in batches. A change fails if its record changed
+// since the plan. Rejected changes are in the [Result], the error is only non-nil if a
+// whole batch fails to send. Run [NewPlan] again to see what is left.
+func Apply(ctx context.Context, client *bc.Client, plan Plan, opts Options) (Result, error) {
+	var result Result
+	if plan.Empty() {
+		return result, nil
+	}
+
+	requests := make([]bc.RequestOptions, len(plan.Changes))
+	for i, c := range plan.Changes {
+		switch c.Action {
+		case Create:
+			requests[i] = bc.RequestOptions{Method: http.MethodPost, EntitySetName: c.EntitySetName, Body: c.Fields}
+		case Update:
+			requests[i] = bc.RequestOptions{Method: http.MethodPatch, EntitySetName: c.EntitySetName, RecordID: c.ID, Body: c.Fields, ETag: c.ETag}
+		case Delete:
+			requests[i] = bc.RequestOptions{Method: http.MethodDelete, EntitySetName: c.EntitySetName, RecordID: c.ID, ETag: c.ETag}
+		}
+	}
+
+	responses, err := client.Bulk(ctx, requests, bc.BulkOptions{ChunkSize: opts.ChunkSize})
+	for i, res := range responses {
+		c := plan.Changes[i]
+		if err := res.Err(); err != nil {
+			result.Errors = append(result.Errors, ChangeError{Change: c, Err: err})
+			continue
+		}
+		switch c.Action {
+		case Create:
+			result.Created++
+		case Update:
+			result.Updated++
+		case Delete:
+			result.Deleted++
+		}
+	}
+	if err != nil {
+		return result, fmt.Errorf("apply: %w", err)
+	}
+	return result, nil
+}
@@ -0,0 +1,132 @@
+package apply_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/erlorenz/bc-go/apply"
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/erlorenz/bc-go/bcfake"
+	"github.com/erlorenz/bc-go/internal/bctest"
+)
+
+const setup = `{"entitySets": [
+	{"name": "paymentTerms", "prune": true, "records": [
+		{"code": "net30", "displayName": "Net 30 days", "dueDateCalculation": "30D", "discountPercent": 0},
+		{"code": "COD", "displayName": "Cash on delivery", "dueDateCalculation": "0D"}
+	]},
+	{"name": "currencies", "records": [
+		{"code": "EUR", "displayName": "Euro", "amountRoundingPrecision": 0.01}
+	]}
+]}`
+
+func TestPlanAndApply(t *testing.T) {
+	server := bcfake.New()
+	server.Seed("paymentTerms",
+		map[string]any{"code": "NET30", "displayName": "Net 30", "dueDateCalculation": "30D", "discountPercent": 0},
+		map[string]any{"code": "OLD", "displayName": "Old terms"},
+	)
+	server.Seed("currencies", map[string]any{"code": "USD", "displayName": "US Dollar"})
+	client := bctest.NewClient(t, server)
+
+	cfg, err := apply.Load(strings.NewReader(setup))
+	if err != nil {
+		t.Fatal(err)
+	}
+	plan, err := apply.NewPlan(context.Background(), client, cfg, apply.Options{MaxPageSize: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `~ paymentTerms code=net30
+    displayName: "Net 30" -> "Net 30 days"
++ paymentTerms code=COD
+    code: "COD"
+    displayName: "Cash on delivery"
+    dueDateCalculation: "0D"
++ currencies code=EUR
+    amountRoundingPrecision: 0.01
+    code: "EUR"
+    displayName: "Euro"
+- paymentTerms code=OLD
+Plan: 2 to create, 1 to update, 1 to delete.
+`
+	if got := plan.String(); got != want {
+		t.Errorf("wanted plan\n%s\ngot\n%s", want, got)
+	}
+
+	result, err := apply.Apply(context.Background(), client, plan, apply.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Created != 2 || result.Updated != 1 || result.Deleted != 1 || len(result.Errors) != 0 {
+		t.Errorf("unexpected result %+v", result)
+	}
+
+	plan, err = apply.NewPlan(context.Background(), client, cfg, apply.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !plan.Empty() {
+		t.Errorf("wanted an empty plan after apply, got\n%s", plan)
+	}
+	if records := server.Records("currencies"); len(records) != 2 {
+		t.Errorf("wanted USD to be kept without prune, got %v", records)
+	}
+}
+
+func TestApplyStaleETag(t *testing.T) {
+	server := bcfake.New()
+	server.Seed("paymentTerms", map[string]any{"code": "NET30", "displayName": "Net 30"})
+	client := bctest.NewClient(t, server)
+
+	cfg := apply.Config{EntitySets: []apply.EntitySet{{Name: "paymentTerms", Records: []map[string]any{
+		{"code": "NET30", "displayName": "Net 30 days"},
+	}}}}
+	plan, err := apply.NewPlan(context.Background(), client, cfg, apply.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Someone else changes the record after the plan
+	page := bc.NewAPIPage[record](client, "paymentTerms")
+	if _, err := page.Update(context.Background(), plan.Changes[0].ID, nil, map[string]any{"displayName": "Thirty"}); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := apply.Apply(context.Background(), client, plan, apply.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var apiErr bc.APIError
+	if len(result.Errors) != 1 || !errors.As(result.Errors[0], &apiErr) || result.Updated != 0 {
+		t.Errorf("wanted the update to fail, got %+v", result)
+	}
+}
+
+type record map[string]any
+
+func (record) Validate() error { return nil }
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+	}{
+		{"UnknownField", `{"entitySets": [{"name": "paymentTerms", "keys": ["code"]}]}`},
+		{"EmptyName", `{"entitySets": [{"records": []}]}`},
+		{"RepeatedEntitySet", `{"entitySets": [{"name": "currencies"}, {"name": "currencies"}]}`},
+		{"MissingKey", `{"entitySets": [{"name": "currencies", "records": [{"displayName": "Euro"}]}]}`},
+		{"RepeatedKey", `{"entitySets": [{"name": "currencies", "records": [{"code": "EUR"}, {"code": "eur"}]}]}`},
+		{"CompositeKey", `{"entitySets": [{"name": "taxAreas", "key": ["code", "taxType"], "records": [{"code": "A"}]}]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := apply.Load(strings.NewReader(tt.config)); err == nil {
+				t.Error("wanted error")
+			}
+		})
+	}
+}
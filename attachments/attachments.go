@@ -0,0 +1,162 @@
+// Package attachments mirrors a folder of files to the document attachments of a BC record.
+// The folder is an fs.FS, so it can be a local directory with os.DirFS, a prefix of an
+// object store with fs.Sub, or an fstest.MapFS in tests.
+//
+// For example, keeping the scanned documents of an invoice attached:
+//
+//	result, err := attachments.Mirror(ctx, client, os.DirFS("scans/"+number), attachments.Options{
+//		ParentType: "Sales Invoice",
+//		ParentID:   invoiceID,
+//	})
+package attachments
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io/fs"
+	"slices"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/erlorenz/bc-go/filter"
+	"github.com/erlorenz/bc-go/models"
+	"github.com/google/uuid"
+)
+
+// Options configure [Mirror].
+type Options struct {
+	// ParentType is the parentType of the record, e.g. "Sales Invoice", "Item" or "Customer".
+	ParentType string
+	// ParentID is the id of the record.
+	ParentID uuid.UUID
+	// KeepRemoved keeps attachments that have no file instead of deleting them.
+	KeepRemoved bool
+}
+
+// Result has the file names of each outcome, sorted.
+type Result struct {
+	Uploaded  []string
+	Updated   []string
+	Unchanged []string
+	Deleted   []string
+}
+
+// Mirror makes the attachments of the record match the files in the root of fsys by file name.
+// New files are uploaded and attachments without a file are deleted. A file with an attachment
+// of the same size is compared by SHA-256 with the downloaded content and only uploaded if it changed.
+// Subdirectories are ignored. It stops at the first error and returns the result so far.
+func Mirror(ctx context.Context, client *bc.Client, fsys fs.FS, opts Options) (Result, error) {
+	var result Result
+
+	if opts.ParentType == "" || opts.ParentID == uuid.Nil {
+		return result, errors.New("mirror attachments: ParentType and ParentID are required")
+	}
+
+	page := models.NewClient(client).DocumentAttachments()
+	existing, err := page.List(ctx, bc.ListOptions{
+		Filter: fmt.Sprintf("parentType eq %s and parentId eq %s", filter.Literal(opts.ParentType), filter.Literal(opts.ParentID)),
+	})
+	if err != nil {
+		return result, fmt.Errorf("list attachments: %w", err)
+	}
+
+	remote := map[string]models.DocumentAttachment{}
+	for _, a := range existing {
+		remote[a.FileName] = a
+	}
+
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return result, fmt.Errorf("read files: %w", err)
+	}
+
+	local := map[string]bool{}
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() {
+			continue
+		}
+		name := entry.Name()
+		local[name] = true
+
+		b, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return result, fmt.Errorf("read file %s: %w", name, err)
+		}
+
+		a, ok := remote[name]
+		if !ok {
+			a, err = page.Create(ctx, map[string]any{
+				"fileName":   name,
+				"parentType": opts.ParentType,
+				"parentId":   opts.ParentID,
+			}, bc.GetOptions{})
+			if err != nil {
+				return result, fmt.Errorf("create attachment %s: %w", name, err)
+			}
+			if err := upload(ctx, client, a, b); err != nil {
+				return result, err
+			}
+			result.Uploaded = append(result.Uploaded, name)
+			continue
+		}
+
+		changed, err := contentChanged(ctx, client, a, b)
+		if err != nil {
+			return result, err
+		}
+		if !changed {
+			result.Unchanged = append(result.Unchanged, name)
+			continue
+		}
+		if err := upload(ctx, client, a, b); err != nil {
+			return result, err
+		}
+		result.Updated = append(result.Updated, name)
+	}
+
+	if !opts.KeepRemoved {
+		for _, a := range existing {
+			if local[a.FileName] {
+				continue
+			}
+			if err := page.Delete(ctx, a.ID); err != nil {
+				return result, fmt.Errorf("delete attachment %s: %w", a.FileName, err)
+			}
+			result.Deleted = append(result.Deleted, a.FileName)
+		}
+	}
+
+	slices.Sort(result.Uploaded)
+	slices.Sort(result.Updated)
+	slices.Sort(result.Unchanged)
+	slices.Sort(result.Deleted)
+	return result, nil
+}
+
+// contentPath returns the path of the attachmentContent stream.
+func contentPath(a models.DocumentAttachment) string {
+	return "documentAttachments(" + a.ID.String() + ")/attachmentContent"
+}
+
+func upload(ctx context.Context, client *bc.Client, a models.DocumentAttachment, b []byte) error {
+	if err := client.UploadMedia(ctx, contentPath(a), bytes.NewReader(b), "application/octet-stream"); err != nil {
+		return fmt.Errorf("upload attachment %s: %w", a.FileName, err)
+	}
+	return nil
+}
+
+// contentChanged compares the file with the attachment, downloading it only if the size is the same.
+func contentChanged(ctx context.Context, client *bc.Client, a models.DocumentAttachment, b []byte) (bool, error) {
+	if a.ByteSize != int64(len(b)) {
+		return true, nil
+	}
+
+	h := sha256.New()
+	if _, err := client.DownloadMedia(ctx, contentPath(a), h, bc.MediaOptions{}); err != nil {
+		return false, fmt.Errorf("download attachment %s: %w", a.FileName, err)
+	}
+	sum := sha256.Sum256(b)
+	return !bytes.Equal(h.Sum(nil), sum[:]), nil
+}
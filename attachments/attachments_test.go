@@ -0,0 +1,111 @@
+package attachments_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/erlorenz/bc-go/attachments"
+	"github.com/erlorenz/bc-go/internal/bctest"
+	"github.com/google/uuid"
+)
+
+func contentResponse(body string) *http.Response {
+	return &http.Response{
+		StatusCode:    http.StatusOK,
+		Body:          io.NopCloser(strings.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Header:        http.Header{},
+	}
+}
+
+func TestMirror(t *testing.T) {
+	parentID := uuid.New()
+	newID, sameID, changedID, removedID := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+
+	files := fstest.MapFS{
+		"new.pdf":         {Data: []byte("new")},
+		"same.pdf":        {Data: []byte("same")},
+		"changed.pdf":     {Data: []byte("v2")},
+		"scans/other.pdf": {Data: []byte("ignored")},
+	}
+
+	st := &bctest.SequenceTransport{Responses: []*http.Response{
+		bctest.NewResponse(200, map[string]any{"value": []map[string]any{
+			{"id": sameID, "fileName": "same.pdf", "byteSize": 4},
+			{"id": changedID, "fileName": "changed.pdf", "byteSize": 2},
+			{"id": removedID, "fileName": "removed.pdf", "byteSize": 1},
+		}}),
+		// Files are read in name order: changed.pdf, new.pdf, same.pdf
+		contentResponse("v1"),
+		bctest.NewResponse(http.StatusNoContent, nil),
+		bctest.NewResponse(http.StatusCreated, map[string]any{"id": newID, "fileName": "new.pdf"}),
+		bctest.NewResponse(http.StatusNoContent, nil),
+		contentResponse("same"),
+		bctest.NewResponse(http.StatusNoContent, nil),
+	}}
+
+	result, err := attachments.Mirror(context.Background(), bctest.NewClient(t, st), files, attachments.Options{
+		ParentType: "Sales Invoice",
+		ParentID:   parentID,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := attachments.Result{
+		Uploaded:  []string{"new.pdf"},
+		Updated:   []string{"changed.pdf"},
+		Unchanged: []string{"same.pdf"},
+		Deleted:   []string{"removed.pdf"},
+	}
+	if !slices.Equal(result.Uploaded, want.Uploaded) || !slices.Equal(result.Updated, want.Updated) ||
+		!slices.Equal(result.Unchanged, want.Unchanged) || !slices.Equal(result.Deleted, want.Deleted) {
+		t.Errorf("wanted %+v, got %+v", want, result)
+	}
+
+	if got, want := st.Requests[0].URL.Query().Get("$filter"), "parentType eq 'Sales Invoice' and parentId eq "+parentID.String(); got != want {
+		t.Errorf("wanted $filter %s, got %s", want, got)
+	}
+
+	var calls []string
+	for _, r := range st.Requests[1:] {
+		path := r.URL.Path[strings.LastIndex(r.URL.Path, "/documentAttachments"):]
+		calls = append(calls, r.Method+" "+path)
+	}
+	wantCalls := []string{
+		"GET /documentAttachments(" + changedID.String() + ")/attachmentContent",
+		"PATCH /documentAttachments(" + changedID.String() + ")/attachmentContent",
+		"POST /documentAttachments",
+		"PATCH /documentAttachments(" + newID.String() + ")/attachmentContent",
+		"GET /documentAttachments(" + sameID.String() + ")/attachmentContent",
+		"DELETE /documentAttachments(" + removedID.String() + ")",
+	}
+	if !slices.Equal(calls, wantCalls) {
+		t.Errorf("wanted calls\n%s\ngot\n%s", strings.Join(wantCalls, "\n"), strings.Join(calls, "\n"))
+	}
+}
+
+func TestMirrorKeepRemoved(t *testing.T) {
+	st := &bctest.SequenceTransport{Responses: []*http.Response{
+		bctest.NewResponse(200, map[string]any{"value": []map[string]any{
+			{"id": uuid.New(), "fileName": "removed.pdf", "byteSize": 1},
+		}}),
+	}}
+
+	result, err := attachments.Mirror(context.Background(), bctest.NewClient(t, st), fstest.MapFS{}, attachments.Options{
+		ParentType:  "Item",
+		ParentID:    uuid.New(),
+		KeepRemoved: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Deleted) != 0 || st.Count() != 1 {
+		t.Errorf("wanted nothing deleted, got %+v after %d requests", result, st.Count())
+	}
+}
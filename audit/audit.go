@@ -0,0 +1,155 @@
+// Package audit provides a [bc.Middleware] that writes an append-only record of every
+// mutating request (POST, PUT, PATCH, DELETE) to a [Sink].
+package audit
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/erlorenz/bc-go/bc"
+)
+
+// Record is a single audited request.
+type Record struct {
+	Time          time.Time     `json:"time"`
+	Actor         string        `json:"actor,omitempty"`
+	Method        string        `json:"method"`
+	URL           string        `json:"url"`
+	EntitySetName string        `json:"entitySetName"`
+	RecordID      string        `json:"recordId,omitempty"`
+	PayloadHash   string        `json:"payloadHash,omitempty"`
+	StatusCode    int           `json:"statusCode,omitempty"`
+	Error         string        `json:"error,omitempty"`
+	Duration      time.Duration `json:"duration"`
+}
+
+// Sink stores audit records. Implementations must be safe for concurrent use.
+type Sink interface {
+	Write(context.Context, Record) error
+}
+
+// SinkFunc adapts a function to a [Sink].
+type SinkFunc func(context.Context, Record) error
+
+// Write calls f.
+func (f SinkFunc) Write(ctx context.Context, r Record) error {
+	return f(ctx, r)
+}
+
+type actorKey struct{}
+
+// WithActor returns a context that records actor as the Actor of requests made with it.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFrom returns the actor set with [WithActor] or an empty string.
+func ActorFrom(ctx context.Context) string {
+	actor, _ := ctx.Value(actorKey{}).(string)
+	return actor
+}
+
+// Options configure the [Middleware].
+type Options struct {
+	// OnError is called when the Sink fails to write a record.
+	// Defaults to logging with slog.Default.
+	OnError func(error, Record)
+	// Clock times the records. Defaults to the [bc.SystemClock]; the middleware is
+	// created before the client, so pass the same clock as [bc.WithClock].
+	Clock bc.Clock
+}
+
+// Middleware returns a [bc.Middleware] that writes a [Record] to the sink after each
+// mutating request. The request is not failed if the sink returns an error,
+// as the change may already have been made in BC.
+func Middleware(sink Sink, opts Options) bc.Middleware {
+	onError := opts.OnError
+	if onError == nil {
+		onError = func(err error, r Record) {
+			slog.Default().Error("Failed to write audit record.", "error", err, "method", r.Method, "url", r.URL)
+		}
+	}
+	clock := opts.Clock
+	if clock == nil {
+		clock = bc.SystemClock
+	}
+
+	return func(next http.RoundTripper) http.RoundTripper {
+		return bc.RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			if r.Method == http.MethodGet || r.Method == http.MethodHead || r.Method == http.MethodOptions {
+				return next.RoundTrip(r)
+			}
+
+			hash, err := payloadHash(r)
+			if err != nil {
+				return nil, err
+			}
+
+			entitySetName, recordID := bc.SplitEntityPath(r.URL.Path)
+			record := Record{
+				Time:          clock.Now().UTC(),
+				Actor:         ActorFrom(r.Context()),
+				Method:        r.Method,
+				URL:           r.URL.String(),
+				EntitySetName: entitySetName,
+				RecordID:      recordID,
+				PayloadHash:   hash,
+			}
+
+			start := clock.Now()
+			res, err := next.RoundTrip(r)
+			record.Duration = clock.Now().Sub(start)
+
+			if err != nil {
+				record.Error = err.Error()
+			}
+			if res != nil {
+				record.StatusCode = res.StatusCode
+			}
+
+			// Write even if canceled so the attempt is never lost.
+			if werr := sink.Write(context.WithoutCancel(r.Context()), record); werr != nil {
+				onError(werr, record)
+			}
+
+			return res, err
+		})
+	}
+}
+
+// payloadHash returns the hex SHA-256 of the request body without consuming it.
+func payloadHash(r *http.Request) (string, error) {
+	if r.Body == nil || r.Body == http.NoBody {
+		return "", nil
+	}
+
+	var body io.ReadCloser
+	if r.GetBody != nil {
+		b, err := r.GetBody()
+		if err != nil {
+			return "", err
+		}
+		body = b
+	} else {
+		b, err := io.ReadAll(r.Body)
+		r.Body.Close()
+		if err != nil {
+			return "", err
+		}
+		r.Body = io.NopCloser(bytes.NewReader(b))
+		body = io.NopCloser(bytes.NewReader(b))
+	}
+	defer body.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, body); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,101 @@
+package audit_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/erlorenz/bc-go/audit"
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/erlorenz/bc-go/bcmock"
+	"github.com/erlorenz/bc-go/internal/bctest"
+	"github.com/google/uuid"
+)
+
+func TestMiddleware(t *testing.T) {
+	var records []audit.Record
+	sink := audit.SinkFunc(func(_ context.Context, r audit.Record) error {
+		records = append(records, r)
+		return nil
+	})
+
+	st := &bctest.SequenceTransport{Responses: []*http.Response{bctest.NewResponse(http.StatusNoContent, nil)}}
+	clock := bcmock.NewClock(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	client := bctest.NewClient(t, st, bc.WithClock(clock), bc.WithMiddleware(audit.Middleware(sink, audit.Options{Clock: clock})))
+
+	id := uuid.New()
+	ctx := audit.WithActor(context.Background(), "jane@example.com")
+
+	for _, method := range []string{http.MethodGet, http.MethodPatch} {
+		opts := bc.RequestOptions{Method: method, EntitySetName: "customers", RecordID: id}
+		if method == http.MethodPatch {
+			opts.Body = map[string]string{"displayName": "New"}
+		}
+		req, err := client.NewRequest(ctx, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := client.Do(req); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(records) != 1 {
+		t.Fatalf("wanted 1 record, got %d", len(records))
+	}
+
+	r := records[0]
+	if r.Actor != "jane@example.com" || r.Method != http.MethodPatch || r.EntitySetName != "customers" || r.RecordID != id.String() || r.StatusCode != http.StatusNoContent {
+		t.Errorf("unexpected record %+v", r)
+	}
+	if !r.Time.Equal(clock.Now()) {
+		t.Errorf("wanted the record timed by the clock, got %s", r.Time)
+	}
+	if len(r.PayloadHash) != 64 {
+		t.Errorf("expected sha256 hex payload hash, got %q", r.PayloadHash)
+	}
+
+	// Body must still be sent intact.
+	var sent map[string]string
+	if err := json.NewDecoder(st.Requests[1].Body).Decode(&sent); err != nil || sent["displayName"] != "New" {
+		t.Errorf("body not forwarded: %v %v", sent, err)
+	}
+}
+
+func TestWriterSink(t *testing.T) {
+	buf := &bytes.Buffer{}
+	sink := audit.NewWriterSink(buf)
+
+	if err := sink.Write(context.Background(), audit.Record{Method: "POST"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := sink.Write(context.Background(), audit.Record{Method: "DELETE"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := bytes.Count(buf.Bytes(), []byte("\n")); got != 2 {
+		t.Errorf("wanted 2 lines, got %d", got)
+	}
+}
+
+func TestWebhookSink(t *testing.T) {
+	var got audit.Record
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	sink := audit.WebhookSink{URL: srv.URL}
+	if err := sink.Write(context.Background(), audit.Record{Method: "POST", EntitySetName: "items"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if got.EntitySetName != "items" {
+		t.Errorf("unexpected record %+v", got)
+	}
+}
@@ -0,0 +1,127 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+)
+
+// WriterSink writes each record as a line of JSON (NDJSON) to an io.Writer.
+type WriterSink struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewWriterSink creates a [WriterSink].
+func NewWriterSink(w io.Writer) *WriterSink {
+	return &WriterSink{w: w}
+}
+
+// Write implements [Sink].
+func (s *WriterSink) Write(_ context.Context, r Record) error {
+	b, err := json.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("marshal audit record: %w", err)
+	}
+	b = append(b, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.w.Write(b); err != nil {
+		return fmt.Errorf("write audit record: %w", err)
+	}
+	return nil
+}
+
+// FileSink appends records as NDJSON to a file. Call Close when done.
+type FileSink struct {
+	*WriterSink
+	file *os.File
+}
+
+// NewFileSink opens the file in append-only mode, creating it if it does not exist.
+func NewFileSink(path string) (*FileSink, error) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("open audit file: %w", err)
+	}
+	return &FileSink{WriterSink: NewWriterSink(f), file: f}, nil
+}
+
+// Close closes the file.
+func (s *FileSink) Close() error {
+	return s.file.Close()
+}
+
+// SQLSink inserts records with a caller provided statement, so it works with
+// any driver's placeholder syntax. The statement receives the arguments
+// time, actor, method, url, entitySetName, recordID, payloadHash, statusCode, error, durationMs
+// in that order, e.g.
+//
+//	INSERT INTO bc_audit (time, actor, method, url, entity_set, record_id, payload_hash, status, error, duration_ms)
+//	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+type SQLSink struct {
+	DB    *sql.DB
+	Query string
+}
+
+// Write implements [Sink].
+func (s SQLSink) Write(ctx context.Context, r Record) error {
+	_, err := s.DB.ExecContext(ctx, s.Query,
+		r.Time, r.Actor, r.Method, r.URL, r.EntitySetName, r.RecordID,
+		r.PayloadHash, r.StatusCode, r.Error, r.Duration.Milliseconds(),
+	)
+	if err != nil {
+		return fmt.Errorf("insert audit record: %w", err)
+	}
+	return nil
+}
+
+// WebhookSink POSTs each record as JSON to a URL.
+type WebhookSink struct {
+	URL        string
+	HTTPClient *http.Client
+	// Header is added to each request, e.g. for authorization.
+	Header http.Header
+}
+
+// Write implements [Sink].
+func (s WebhookSink) Write(ctx context.Context, r Record) error {
+	b, err := json.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("marshal audit record: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.URL, bytes.NewReader(b))
+	if err != nil {
+		return fmt.Errorf("create audit request: %w", err)
+	}
+	for k, v := range s.Header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	hc := s.HTTPClient
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+
+	res, err := hc.Do(req)
+	if err != nil {
+		return fmt.Errorf("send audit record: %w", err)
+	}
+	defer res.Body.Close()
+	io.Copy(io.Discard, res.Body)
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("send audit record: status %d", res.StatusCode)
+	}
+	return nil
+}
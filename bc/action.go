@@ -0,0 +1,54 @@
+package bc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// InvokeAction makes a POST request to a bound action of a record, e.g.
+// "salesInvoices({id})/Microsoft.NAV.post". The action is the name without
+// the "Microsoft.NAV." namespace. Body is optional and expects a 204 No Content.
+func (c *Client) InvokeAction(ctx context.Context, entitySetName string, id uuid.UUID, action string, body any) error {
+	if id == uuid.Nil {
+		return fmt.Errorf("invoke action %s: id is empty", action)
+	}
+	if err := validateIdentifier(action); err != nil {
+		return fmt.Errorf("invoke action: %w", err)
+	}
+
+	opts := RequestOptions{
+		Method:        http.MethodPost,
+		EntitySetName: fmt.Sprintf("%s(%s)/Microsoft.NAV.%s", entitySetName, id, action),
+		Body:          body,
+	}
+	req, err := c.NewRequest(ctx, opts)
+	if err != nil {
+		return fmt.Errorf("failed to create Request: %w", err)
+	}
+
+	c.logger.Debug("Sending request...", "url", req.URL.String(), "method", req.Method)
+
+	res, err := c.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed during request: %w", err)
+	}
+
+	err = DecodeNoContent(res)
+	if err != nil {
+		var srvErr APIError
+		if errors.As(err, &srvErr) {
+			c.logger.Debug("API server returned error response.", "error", srvErr)
+			return fmt.Errorf("error from BC API: %w", srvErr)
+		}
+
+		c.logger.Debug("Failed to decode response.", "error", err)
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	c.logger.Debug("Successfully invoked action.", "action", action, "id", id)
+
+	return nil
+}
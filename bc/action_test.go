@@ -0,0 +1,33 @@
+package bc_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/erlorenz/bc-go/internal/bctest"
+	"github.com/google/uuid"
+)
+
+func TestInvokeAction(t *testing.T) {
+	st := &bctest.SequenceTransport{Responses: []*http.Response{bctest.NewResponse(http.StatusNoContent, nil)}}
+	client := newSequenceClient(t, st)
+	page := bc.NewAPIPage[fakeEntity](client, "salesInvoices")
+
+	id := uuid.New()
+	if err := page.InvokeAction(context.Background(), id, "post", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	req := st.Requests[0]
+	want := "/salesInvoices(" + id.String() + ")/Microsoft.NAV.post"
+	if req.Method != http.MethodPost || !strings.HasSuffix(req.URL.Path, want) {
+		t.Errorf("unexpected request %s %s", req.Method, req.URL.Path)
+	}
+
+	if err := page.InvokeAction(context.Background(), uuid.Nil, "post", nil); err == nil {
+		t.Error("expected error for nil id, got nil")
+	}
+}
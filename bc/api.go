@@ -0,0 +1,39 @@
+package bc
+
+import (
+	"context"
+	"io"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// Doer sends a request built by [Client.NewRequest], e.g. for a function that only
+// needs to send requests. *Client and *http.Client implement it.
+type Doer interface {
+	Do(r *http.Request) (*http.Response, error)
+}
+
+// API is the part of *[Client] that talks to BC, so code that depends on it can be
+// tested without HTTP with a stub like bcmock.Client. The typed entity sets have
+// the [Repository] interface.
+type API interface {
+	Doer
+	NewRequest(ctx context.Context, opts RequestOptions) (*http.Request, error)
+	NewRequestURL(ctx context.Context, method string, rawURL string, body any) (*http.Request, error)
+	InvokeAction(ctx context.Context, entitySetName string, id uuid.UUID, action string, body any) error
+	DeleteIfExists(ctx context.Context, entitySetName string, id uuid.UUID) (bool, error)
+	DeleteIfMatch(ctx context.Context, entitySetName string, id uuid.UUID, etag string) error
+	Batch(ctx context.Context, requests []RequestOptions, opts BatchOptions) ([]BatchResponse, error)
+	Bulk(ctx context.Context, requests []RequestOptions, opts BulkOptions) ([]BatchResponse, error)
+	Capabilities(ctx context.Context) (Capabilities, error)
+	DownloadMedia(ctx context.Context, path string, w io.Writer, opts MediaOptions) (MediaResult, error)
+	UploadMedia(ctx context.Context, path string, r io.Reader, contentType string) error
+	Ping(ctx context.Context) (HealthReport, error)
+	Snapshot(ctx context.Context, specs []EntitySpec) (Snapshot, error)
+}
+
+var (
+	_ API  = (*Client)(nil)
+	_ Doer = (*http.Client)(nil)
+)
@@ -27,6 +27,10 @@ type APIPage[T Validator] struct {
 // have a RecordID. The Value field has a slice of T.
 type APIListResponse[T any] struct {
 	Value []T `json:"value" validate:"required,dive"`
+	// NextLink is the URL of the next page if the response is paged by the server.
+	NextLink string `json:"@odata.nextLink,omitempty"`
+	// DeltaLink is the URL to get changes since this response when change tracking is requested.
+	DeltaLink string `json:"@odata.deltaLink,omitempty"`
 }
 
 // Validate implements the Validator interface. It validates
@@ -51,6 +55,21 @@ func NewAPIPage[T Validator](client *Client, entitySetName string) *APIPage[T] {
 	}
 }
 
+// EntitySetName returns the entity set name, including the parent path for navigations.
+func (a *APIPage[T]) EntitySetName() string {
+	return a.entitySetName
+}
+
+// Client returns the [Client] used to make requests.
+func (a *APIPage[T]) Client() *Client {
+	return a.client
+}
+
+// InvokeAction calls [Client.InvokeAction] for this entity set.
+func (a *APIPage[T]) InvokeAction(ctx context.Context, id uuid.UUID, action string, body any) error {
+	return a.client.InvokeAction(ctx, a.entitySetName, id, action, body)
+}
+
 // Adds a new string to the baseExpand slice. This will be added
 // to all request expand expressions.
 func (a *APIPage[T]) AddBaseExpand(expand string) {
@@ -59,6 +78,7 @@ func (a *APIPage[T]) AddBaseExpand(expand string) {
 
 // Get makes a GET request to the endpoint and retrieves a single record T.
 // Requires the ID and  takes an optional slice of expand strings.
+// If the record does not exist the error matches [ErrNotFound].
 func (a *APIPage[T]) Get(ctx context.Context, id uuid.UUID, opts GetOptions) (T, error) {
 	var v T
 
@@ -70,7 +90,7 @@ func (a *APIPage[T]) Get(ctx context.Context, id uuid.UUID, opts GetOptions) (T,
 		expands = slices.Concat(a.BaseExpand, opts.Expand)
 	}
 
-	qp["$expand"] = strings.Join(expands, ",")
+	qp.Set("$expand", strings.Join(expands, ","))
 
 	reqOpts := RequestOptions{
 		Method:        http.MethodGet,
@@ -88,7 +108,7 @@ func (a *APIPage[T]) Get(ctx context.Context, id uuid.UUID, opts GetOptions) (T,
 		return v, fmt.Errorf("failed during request: %w", err)
 	}
 
-	v, err = Decode[T](res)
+	v, err = decode[T](a.client, res)
 	if err != nil {
 		var srvErr APIError
 		if errors.As(err, &srvErr) {
@@ -99,14 +119,36 @@ func (a *APIPage[T]) Get(ctx context.Context, id uuid.UUID, opts GetOptions) (T,
 		a.client.logger.Debug("Failed to decode response.", "error", err)
 		return v, fmt.Errorf("failed to decode response: %w", err)
 	}
+
+	if err := validateRecord(a.client, a.entitySetName, v); err != nil {
+		return v, err
+	}
 	return v, nil
 }
 
+// GetOptional is the same as Get but returns the zero value of T and false
+// instead of an error if the record does not exist.
+func (a *APIPage[T]) GetOptional(ctx context.Context, id uuid.UUID, opts GetOptions) (T, bool, error) {
+	v, err := a.Get(ctx, id, opts)
+	if errors.Is(err, ErrNotFound) {
+		var zero T
+		return zero, false, nil
+	}
+	if err != nil {
+		return v, false, err
+	}
+	return v, true, nil
+}
+
 // List makes a GET request to the endpoint and returns []T.
 // It takes optional struct of query options.
 func (a *APIPage[T]) List(ctx context.Context, queryOpts ListOptions) ([]T, error) {
 	var v []T
 
+	if _, err := queryOpts.Cursor.SkipToken(); err != nil {
+		return v, err
+	}
+
 	qp := queryOpts.BuildQueryParams(a.BaseFilter, a.BaseExpand)
 
 	opts := RequestOptions{
@@ -124,7 +166,7 @@ func (a *APIPage[T]) List(ctx context.Context, queryOpts ListOptions) ([]T, erro
 		return v, fmt.Errorf("failed during request: %w", err)
 	}
 
-	list, err := Decode[APIListResponse[T]](res)
+	list, err := decode[APIListResponse[T]](a.client, res)
 	if err != nil {
 		var srvErr APIError
 		if errors.As(err, &srvErr) {
@@ -136,12 +178,30 @@ func (a *APIPage[T]) List(ctx context.Context, queryOpts ListOptions) ([]T, erro
 		return v, fmt.Errorf("decode response: %w", err)
 	}
 	v = list.Value
+
+	if err := validateList(a.client, a.entitySetName, v); err != nil {
+		return v, err
+	}
 	return v, nil
 }
 
 // Update makes a Patch request to the endpoint and returns T.
 // It requires a body and a RecordID.
 func (a *APIPage[T]) Update(ctx context.Context, id uuid.UUID, expand []string, body any) (T, error) {
+	return a.update(ctx, id, expand, body, "")
+}
+
+// UpdateIfMatch is the same as Update but only succeeds if the record still has the ETag.
+// If the record has changed the error matches [ErrPreconditionFailed].
+func (a *APIPage[T]) UpdateIfMatch(ctx context.Context, id uuid.UUID, expand []string, body any, etag string) (T, error) {
+	if err := stringNotEmpty(etag); err != nil {
+		var v T
+		return v, fmt.Errorf("invalid etag: %w", err)
+	}
+	return a.update(ctx, id, expand, body, etag)
+}
+
+func (a *APIPage[T]) update(ctx context.Context, id uuid.UUID, expand []string, body any, etag string) (T, error) {
 	var v T
 
 	qp := QueryParams{}
@@ -153,10 +213,10 @@ func (a *APIPage[T]) Update(ctx context.Context, id uuid.UUID, expand []string,
 	}
 
 	if len(expands) > 0 {
-		qp["$expand"] = strings.Join(expands, ",")
+		qp.Set("$expand", strings.Join(expands, ","))
 	}
 
-	a.client.logger.Debug("Query params initialized.", "expand", qp["$expand"])
+	a.client.logger.Debug("Query params initialized.", "expand", qp.Get("$expand"))
 
 	opts := RequestOptions{
 		Method:        http.MethodPatch,
@@ -164,6 +224,7 @@ func (a *APIPage[T]) Update(ctx context.Context, id uuid.UUID, expand []string,
 		RecordID:      id,
 		QueryParams:   qp,
 		Body:          body,
+		ETag:          etag,
 	}
 	req, err := a.client.NewRequest(ctx, opts)
 	if err != nil {
@@ -177,7 +238,7 @@ func (a *APIPage[T]) Update(ctx context.Context, id uuid.UUID, expand []string,
 		return v, fmt.Errorf("failed during request: %w", err)
 	}
 
-	v, err = Decode[T](res)
+	v, err = decode[T](a.client, res)
 	if err != nil {
 		var srvErr APIError
 		if errors.As(err, &srvErr) {
@@ -189,6 +250,10 @@ func (a *APIPage[T]) Update(ctx context.Context, id uuid.UUID, expand []string,
 		return v, fmt.Errorf("failed to decode response: %w", err)
 	}
 
+	if err := validateRecord(a.client, a.entitySetName, v); err != nil {
+		return v, err
+	}
+
 	a.client.logger.Debug(fmt.Sprintf("Successfully created %T record.", v), "record", fmt.Sprintf("%#v", v))
 	return v, nil
 }
@@ -207,9 +272,9 @@ func (a *APIPage[T]) Create(ctx context.Context, body any, opts GetOptions) (T,
 	}
 
 	if len(expands) > 0 {
-		qp["$expand"] = strings.Join(expands, ",")
+		qp.Set("$expand", strings.Join(expands, ","))
 	}
-	a.client.logger.Debug("Query params initialized.", "expand", qp["$expand"])
+	a.client.logger.Debug("Query params initialized.", "expand", qp.Get("$expand"))
 
 	reqOpts := RequestOptions{
 		Method:        http.MethodPost,
@@ -229,7 +294,7 @@ func (a *APIPage[T]) Create(ctx context.Context, body any, opts GetOptions) (T,
 		return v, fmt.Errorf("failed during request: %w", err)
 	}
 
-	v, err = Decode[T](res)
+	v, err = decode[T](a.client, res)
 	if err != nil {
 		var srvErr APIError
 		if errors.As(err, &srvErr) {
@@ -240,45 +305,31 @@ func (a *APIPage[T]) Create(ctx context.Context, body any, opts GetOptions) (T,
 		a.client.logger.Debug("Failed to decode response.", "error", err)
 		return v, fmt.Errorf("failed to decode response: %w", err)
 	}
+
+	if err := validateRecord(a.client, a.entitySetName, v); err != nil {
+		return v, err
+	}
 	a.client.logger.Debug(fmt.Sprintf("Successfully created %T record.", v), "record", fmt.Sprintf("%#v", v))
 	return v, nil
 
 }
 
-// Delete makes a DELETE request to the endpoint and returns a string message.
+// Delete makes a DELETE request to the endpoint.
 // It requires a RecordID.
 func (a *APIPage[T]) Delete(ctx context.Context, id uuid.UUID) error {
-	opts := RequestOptions{
+	return a.client.delete(ctx, RequestOptions{
 		Method:        http.MethodDelete,
 		EntitySetName: a.entitySetName,
 		RecordID:      id,
-	}
-	req, err := a.client.NewRequest(ctx, opts)
-	if err != nil {
-		return fmt.Errorf("failed to create Request: %w", err)
-	}
-
-	a.client.logger.Debug("Sending request...", "url", req.URL.String(), "method", req.Method)
-
-	res, err := a.client.Do(req)
-	if err != nil {
-		return fmt.Errorf("failed during request: %w", err)
-	}
-
-	// Expects a 204 No Content
-	err = DecodeNoContent(res)
-
-	if err != nil {
-		var srvErr APIError
-		if errors.As(err, &srvErr) {
-			a.client.logger.Debug("API server returned error response.", "error", srvErr)
-			return fmt.Errorf("error from BC API: %w", srvErr)
-		}
+	})
+}
 
-		a.client.logger.Debug("Failed to decode response.", "error", err)
-		return fmt.Errorf("failed to decode response: %w", err)
-	}
-	a.client.logger.Debug("Succesfully deleted record.", "id", id)
+// DeleteIfExists calls [Client.DeleteIfExists] for this entity set.
+func (a *APIPage[T]) DeleteIfExists(ctx context.Context, id uuid.UUID) (bool, error) {
+	return a.client.DeleteIfExists(ctx, a.entitySetName, id)
+}
 
-	return nil
+// DeleteIfMatch calls [Client.DeleteIfMatch] for this entity set.
+func (a *APIPage[T]) DeleteIfMatch(ctx context.Context, id uuid.UUID, etag string) error {
+	return a.client.DeleteIfMatch(ctx, a.entitySetName, id, etag)
 }
@@ -1,10 +1,13 @@
 package bc_test
 
 import (
+	"context"
+	"errors"
 	"strings"
 	"testing"
 
 	"github.com/erlorenz/bc-go/bc"
+	"github.com/google/uuid"
 )
 
 func TestAPIPageExpand(t *testing.T) {
@@ -40,3 +43,41 @@ func TestAPIPageExpand(t *testing.T) {
 	}
 
 }
+
+func TestAPIPageGetNotFound(t *testing.T) {
+	body := bc.ErrorResponse{Error: bc.ErrorResponseError{Code: "BadRequest_NotFound", Message: "not found"}}
+
+	client := newMockClient(t, 404, body)
+	page := bc.NewAPIPage[fakeEntity](client, "fakeEntities")
+
+	_, err := page.Get(context.Background(), uuid.New(), bc.GetOptions{})
+	if !errors.Is(err, bc.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestAPIPageGetOptional(t *testing.T) {
+	body := bc.ErrorResponse{Error: bc.ErrorResponseError{Code: "BadRequest_NotFound", Message: "not found"}}
+
+	client := newMockClient(t, 404, body)
+	page := bc.NewAPIPage[fakeEntity](client, "fakeEntities")
+
+	v, ok, err := page.GetOptional(context.Background(), uuid.New(), bc.GetOptions{})
+	if err != nil {
+		t.Fatalf("expected nil error, got %s", err)
+	}
+	if ok {
+		t.Error("expected ok false, got true")
+	}
+	if v != (fakeEntity{}) {
+		t.Errorf("expected zero value, got %+v", v)
+	}
+
+	client = newMockClient(t, 200, map[string]any{"ID": validGUID})
+	page = bc.NewAPIPage[fakeEntity](client, "fakeEntities")
+
+	_, ok, err = page.GetOptional(context.Background(), uuid.New(), bc.GetOptions{})
+	if err != nil || !ok {
+		t.Errorf("expected ok true and nil error, got %t, %v", ok, err)
+	}
+}
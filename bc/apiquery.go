@@ -55,12 +55,12 @@ func (q *APIQuery[T]) List(ctx context.Context, opts ListOptions) ([]T, error) {
 	qp := QueryParams{}
 
 	if filter != "" {
-		qp["$filter"] = filter
+		qp.Set("$filter", filter)
 	}
 
 	// Set $top if exists
 	if opts.Top > 0 {
-		qp["$top"] = strconv.Itoa(opts.Top)
+		qp.Set("$top", strconv.Itoa(opts.Top))
 	}
 
 	ropts := RequestOptions{
@@ -78,7 +78,7 @@ func (q *APIQuery[T]) List(ctx context.Context, opts ListOptions) ([]T, error) {
 		return v, fmt.Errorf("failed during request: %w", err)
 	}
 
-	list, err := Decode[APIListResponse[T]](res)
+	list, err := decode[APIListResponse[T]](q.client, res)
 	if err != nil {
 		var srvErr APIError
 		if errors.As(err, &srvErr) {
@@ -90,5 +90,9 @@ func (q *APIQuery[T]) List(ctx context.Context, opts ListOptions) ([]T, error) {
 		return v, fmt.Errorf("failed to decode response: %w", err)
 	}
 	v = list.Value
+
+	if err := validateList(q.client, q.entitySetName, v); err != nil {
+		return v, err
+	}
 	return v, nil
 }
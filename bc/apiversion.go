@@ -0,0 +1,84 @@
+package bc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+)
+
+// ErrAPIVersionNotSupported is returned by [Client.VerifyAPIVersion] when the
+// environment does not serve the API version of the APIEndpoint.
+var ErrAPIVersionNotSupported = errors.New("api version not supported by environment")
+
+// APIVersion returns the version the client is pinned to, the last segment
+// of the APIEndpoint, e.g. "v2.0" or "1.0" for an extension API.
+func (c *Client) APIVersion() string {
+	return path.Base(c.config.APIEndpoint)
+}
+
+// VerifyAPIVersion checks that the environment serves the pinned API version, so an
+// upgraded environment fails fast at startup instead of with 404s at runtime.
+// If it does not the error matches [ErrAPIVersionNotSupported].
+func (c *Client) VerifyAPIVersion(ctx context.Context) error {
+	return c.checkAPIVersion(ctx, c.APIVersion())
+}
+
+// NegotiateAPIVersion returns the first of the versions the environment serves,
+// e.g. to create a client for "v2.0" and fall back to "v1.0" on older environments.
+// The versions replace the last segment of the APIEndpoint.
+func (c *Client) NegotiateAPIVersion(ctx context.Context, versions ...string) (string, error) {
+	var errs []error
+	for _, version := range versions {
+		err := c.checkAPIVersion(ctx, version)
+		if err == nil {
+			c.logger.Debug("Negotiated API version.", "version", version)
+			return version, nil
+		}
+		if !errors.Is(err, ErrAPIVersionNotSupported) {
+			return "", err
+		}
+		errs = append(errs, err)
+	}
+	return "", fmt.Errorf("no api version supported: %w", errors.Join(errs...))
+}
+
+// checkAPIVersion lists one company with the version, which every API has.
+func (c *Client) checkAPIVersion(ctx context.Context, version string) error {
+	if err := validateIdentifier(version); err != nil {
+		return fmt.Errorf("invalid api version: %w", err)
+	}
+
+	u := *c.baseURL
+	u.Path = path.Dir(c.apiRootPath()) + "/" + version + "/companies"
+	u.RawQuery = "$top=1&$select=id"
+
+	req, err := c.newRequest(ctx, u.String(), RequestOptions{Method: http.MethodGet})
+	if err != nil {
+		return fmt.Errorf("failed to create Request: %w", err)
+	}
+
+	c.logger.Debug("Sending request...", "url", req.URL.String(), "method", req.Method)
+
+	res, err := c.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed during request: %w", err)
+	}
+
+	err = DecodeNoContent(res)
+	if err != nil {
+		var srvErr APIError
+		if !errors.As(err, &srvErr) {
+			return fmt.Errorf("failed to decode response: %w", err)
+		}
+		c.logger.Debug("API server returned error response.", "error", srvErr)
+		// A missing tenant or environment is not a version problem
+		if srvErr.StatusCode == http.StatusNotFound && srvErr.Code != ErrorCodeTenantNotFound && srvErr.Code != ErrorCodeEnvironmentNotFound {
+			return fmt.Errorf("%w: %s: %w", ErrAPIVersionNotSupported, version, srvErr)
+		}
+		return fmt.Errorf("error from BC API: %w", srvErr)
+	}
+
+	return nil
+}
@@ -0,0 +1,63 @@
+package bc_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/erlorenz/bc-go/internal/bctest"
+)
+
+func TestVerifyAPIVersion(t *testing.T) {
+	table := []struct {
+		name        string
+		response    *http.Response
+		unsupported bool
+		wantErr     bool
+	}{
+		{"Supported", bctest.NewResponse(200, map[string]any{"value": []any{}}), false, false},
+		{"NotServed", errorResponse(404, "BadRequest_NotFound"), true, true},
+		{"EnvironmentNotFound", errorResponse(404, "Internal_EnvironmentNotFound"), false, true},
+		{"Unauthorized", errorResponse(401, "Authentication_InvalidCredentials"), false, true},
+	}
+
+	for _, v := range table {
+		t.Run(v.name, func(t *testing.T) {
+			st := &bctest.SequenceTransport{Responses: []*http.Response{v.response}}
+			client := newSequenceClient(t, st)
+
+			err := client.VerifyAPIVersion(context.Background())
+			if (err != nil) != v.wantErr || errors.Is(err, bc.ErrAPIVersionNotSupported) != v.unsupported {
+				t.Errorf("unexpected error %v", err)
+			}
+			if got := st.Requests[0].URL.Path; !strings.HasSuffix(got, "/api/publisher/group/1.0/companies") {
+				t.Errorf("unexpected path %s", got)
+			}
+		})
+	}
+}
+
+func TestNegotiateAPIVersion(t *testing.T) {
+	st := &bctest.SequenceTransport{Responses: []*http.Response{
+		errorResponse(404, "BadRequest_NotFound"),
+		bctest.NewResponse(200, map[string]any{"value": []any{}}),
+	}}
+	client := newSequenceClient(t, st)
+
+	version, err := client.NegotiateAPIVersion(context.Background(), "2.0", "1.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version != "1.0" {
+		t.Errorf("wanted 1.0, got %s", version)
+	}
+	if got := st.Requests[0].URL.Path; !strings.HasSuffix(got, "/api/publisher/group/2.0/companies") {
+		t.Errorf("unexpected path %s", got)
+	}
+	if client.APIVersion() != "1.0" {
+		t.Errorf("wanted pinned version 1.0, got %s", client.APIVersion())
+	}
+}
@@ -0,0 +1,95 @@
+package bc
+
+import (
+	"cmp"
+	"net/http"
+	"strings"
+)
+
+// Authority hosts of the Azure clouds, for [WithAuthorityHost].
+const (
+	AuthorityHostPublic       = "https://login.microsoft.com"
+	AuthorityHostUSGovernment = "https://login.microsoftonline.us"
+	AuthorityHostChina        = "https://login.chinacloudapi.cn"
+)
+
+// DefaultScope is the scope of the Business Central API in the public cloud.
+const DefaultScope = "https://api.businesscentral.dynamics.com/.default"
+
+// AuthOption configures the built-in TokenGetters: [Auth], [InteractiveAuth]
+// and the workload identity of [ManagedIdentityAuth].
+type AuthOption func(*authSettings)
+
+type authSettings struct {
+	cache         TokenCache
+	authorityHost string
+	tenantID      string
+	scope         string
+	httpClient    *http.Client
+	clock         Clock
+}
+
+// newAuthSettings applies the options over the defaults.
+func newAuthSettings(tenantID string, opts []AuthOption) authSettings {
+	s := authSettings{}
+	for _, opt := range opts {
+		opt(&s)
+	}
+	s.authorityHost = strings.TrimSuffix(cmp.Or(s.authorityHost, AuthorityHostPublic), "/")
+	s.tenantID = cmp.Or(s.tenantID, tenantID)
+	s.scope = cmp.Or(s.scope, DefaultScope)
+	s.clock = clockOrSystem(s.clock)
+	return s
+}
+
+func (s authSettings) authority() string {
+	return s.authorityHost + "/" + s.tenantID
+}
+
+// resource is the scope without the "/.default" suffix, as used by managed identity endpoints.
+func (s authSettings) resource() string {
+	return strings.TrimSuffix(s.scope, "/.default")
+}
+
+// WithAuthTokenCache checks the [TokenCache] before requesting a token and stores new tokens in it.
+// Errors from the cache are logged and otherwise ignored. Only used by [Auth].
+func WithAuthTokenCache(cache TokenCache) AuthOption {
+	return func(s *authSettings) {
+		s.cache = cache
+	}
+}
+
+// WithAuthorityHost overrides the Entra ID host for sovereign clouds, e.g. [AuthorityHostUSGovernment].
+func WithAuthorityHost(host string) AuthOption {
+	return func(s *authSettings) {
+		s.authorityHost = host
+	}
+}
+
+// WithTenant overrides the tenant of the authority, e.g. to sign in to a guest tenant.
+func WithTenant(tenantID string) AuthOption {
+	return func(s *authSettings) {
+		s.tenantID = tenantID
+	}
+}
+
+// WithAuthHTTPClient sets the http.Client used to request tokens, e.g. one with a proxy.
+func WithAuthHTTPClient(hc *http.Client) AuthOption {
+	return func(s *authSettings) {
+		s.httpClient = hc
+	}
+}
+
+// WithScope overrides [DefaultScope], for clouds that host the API on a different domain.
+func WithScope(scope string) AuthOption {
+	return func(s *authSettings) {
+		s.scope = scope
+	}
+}
+
+// WithAuthClock replaces the [SystemClock] that decides if a cached token has expired.
+func WithAuthClock(clock Clock) AuthOption {
+	return func(s *authSettings) {
+		s.clock = clock
+	}
+}
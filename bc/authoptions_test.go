@@ -0,0 +1,62 @@
+package bc_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/erlorenz/bc-go/bc"
+)
+
+func TestAuthOptions(t *testing.T) {
+	const guestTenant = "11111111-2222-3333-4444-555555555555"
+	const scope = "https://api.businesscentral.dynamics.us/.default"
+
+	cache := &recordingCache{}
+	auth, err := bc.NewAuth(validGUID, validGUID, "SECRET",
+		bc.WithAuthTokenCache(cache),
+		bc.WithAuthorityHost(bc.AuthorityHostUSGovernment+"/"),
+		bc.WithTenant(guestTenant),
+		bc.WithScope(scope),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := auth.GetToken(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	want := strings.Join([]string{bc.AuthorityHostUSGovernment + "/" + guestTenant, validGUID, scope}, "|")
+	if len(cache.keys) != 1 || cache.keys[0] != want {
+		t.Errorf("wanted cache key %s, got %v", want, cache.keys)
+	}
+}
+
+func TestManagedIdentityScope(t *testing.T) {
+	clearManagedIdentityEnv(t)
+
+	var resource string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resource = r.URL.Query().Get("resource")
+		fmt.Fprintf(w, `{"access_token":"MITOKEN","expires_on":"%d"}`, time.Now().Add(time.Hour).Unix())
+	}))
+	defer srv.Close()
+
+	mi, err := bc.NewManagedIdentityAuth(bc.ManagedIdentityOptions{
+		IMDSEndpoint: srv.URL,
+		AuthOptions:  []bc.AuthOption{bc.WithScope("https://api.businesscentral.dynamics.cn/.default")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := mi.GetToken(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if resource != "https://api.businesscentral.dynamics.cn" {
+		t.Errorf("wanted resource without /.default, got %s", resource)
+	}
+}
@@ -0,0 +1,157 @@
+package bc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// ErrUnknownTenant is returned by [ResolveTenantID] for a domain that Entra ID does not know.
+var ErrUnknownTenant = errors.New("unknown tenant")
+
+// ErrUnknownEnvironment is returned by [ResolveBaseURL] for an environment that the
+// tenant does not have.
+var ErrUnknownEnvironment = errors.New("unknown environment")
+
+// environmentName is the format of a BC environment name.
+var environmentName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]{0,29}$`)
+
+// ResolveBaseURL returns the root URL of the common API of the environment, e.g.
+// "https://api.businesscentral.dynamics.com/v2.0/{tenantID}/Production/api/v2.0",
+// so users configure a tenant and an environment name instead of a long URL. The tenant
+// is a tenant ID or a domain like "contoso.onmicrosoft.com", which is resolved to its
+// ID with [ResolveTenantID]. The host is that of the scope of [WithScope], for clouds
+// that host the API on a different domain. Pass the URL to [ClientConfig.SetBaseURL].
+//
+// The environment is checked with the deployment URL endpoint of the web client, which
+// needs no token, so a typo returns an error matching [ErrUnknownEnvironment] instead
+// of a 404 on the first request.
+func ResolveBaseURL(ctx context.Context, tenant, environment string, opts ...AuthOption) (*url.URL, error) {
+	if !environmentName.MatchString(environment) {
+		return nil, fmt.Errorf("resolve base url: invalid environment name %q", environment)
+	}
+	tenantID, err := ResolveTenantID(ctx, tenant, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("resolve base url: %w", err)
+	}
+
+	s := newAuthSettings(tenantID, opts)
+	u, err := url.Parse(s.resource())
+	if err != nil || u.Scheme != "https" || u.Host == "" {
+		return nil, fmt.Errorf("resolve base url: invalid scope %q", s.scope)
+	}
+	if err := checkEnvironment(ctx, s.httpClient, u.Host, tenantID, environment); err != nil {
+		return nil, fmt.Errorf("resolve base url: %w", err)
+	}
+	u.Path = "/v2.0/" + tenantID + "/" + environment + "/api/v2.0"
+	return u, nil
+}
+
+// deploymentURL is the response of the deployment URL endpoint of the web client.
+type deploymentURL struct {
+	Data   string `json:"data"`
+	Status string `json:"status"`
+}
+
+// checkEnvironment returns an error matching [ErrUnknownEnvironment] if the tenant does
+// not have the environment. The web client is on the host of the API without "api.".
+func checkEnvironment(ctx context.Context, hc *http.Client, apiHost, tenantID, environment string) error {
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+	webHost := strings.TrimPrefix(apiHost, "api.")
+	rawURL := "https://" + webHost + "/" + tenantID + "/" + url.PathEscape(environment) + "/deployment/url"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return fmt.Errorf("check environment: %w", err)
+	}
+	res, err := hc.Do(req)
+	if err != nil {
+		return fmt.Errorf("check environment: %w", err)
+	}
+	defer res.Body.Close()
+
+	switch {
+	case res.StatusCode == http.StatusNotFound:
+		return fmt.Errorf("check environment: %w: %s", ErrUnknownEnvironment, environment)
+	case res.StatusCode != http.StatusOK:
+		return fmt.Errorf("check environment: unexpected status %d", res.StatusCode)
+	}
+	var deployment deploymentURL
+	if err := json.NewDecoder(res.Body).Decode(&deployment); err != nil {
+		return fmt.Errorf("check environment: %w", err)
+	}
+
+	switch deployment.Status {
+	case "Success":
+		return nil
+	case "DoesNotExist":
+		return fmt.Errorf("check environment: %w: %s", ErrUnknownEnvironment, environment)
+	default:
+		return fmt.Errorf("check environment: unexpected deployment status %q", deployment.Status)
+	}
+}
+
+// openIDConfiguration is the subset of the OpenID discovery document of a tenant.
+type openIDConfiguration struct {
+	Issuer string `json:"issuer"`
+}
+
+// ResolveTenantID returns the tenant ID of a domain like "contoso.onmicrosoft.com" from
+// the OpenID discovery document of the authority of [WithAuthorityHost]. A tenant ID is
+// returned as is, without a request. It returns an error matching [ErrUnknownTenant] if
+// the authority does not know the domain.
+func ResolveTenantID(ctx context.Context, tenant string, opts ...AuthOption) (string, error) {
+	tenant = strings.TrimSpace(tenant)
+	if id, err := uuid.Parse(tenant); err == nil {
+		return id.String(), nil
+	}
+	if tenant == "" || strings.ContainsAny(tenant, "/?#") || !strings.Contains(tenant, ".") {
+		return "", fmt.Errorf("resolve tenant: invalid tenant %q", tenant)
+	}
+
+	s := newAuthSettings(tenant, opts)
+	hc := s.httpClient
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.authority()+"/v2.0/.well-known/openid-configuration", nil)
+	if err != nil {
+		return "", fmt.Errorf("resolve tenant: %w", err)
+	}
+	res, err := hc.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("resolve tenant: %w", err)
+	}
+	defer res.Body.Close()
+
+	switch {
+	case res.StatusCode == http.StatusBadRequest || res.StatusCode == http.StatusNotFound:
+		return "", fmt.Errorf("resolve tenant: %w: %s", ErrUnknownTenant, tenant)
+	case res.StatusCode != http.StatusOK:
+		return "", fmt.Errorf("resolve tenant: unexpected status %d", res.StatusCode)
+	}
+	var config openIDConfiguration
+	if err := json.NewDecoder(res.Body).Decode(&config); err != nil {
+		return "", fmt.Errorf("resolve tenant: %w", err)
+	}
+
+	// The issuer is "https://login.microsoftonline.com/{tenantID}/v2.0".
+	issuer, err := url.Parse(config.Issuer)
+	if err != nil {
+		return "", fmt.Errorf("resolve tenant: invalid issuer %q", config.Issuer)
+	}
+	segment, _, _ := strings.Cut(strings.TrimPrefix(issuer.Path, "/"), "/")
+	id, err := uuid.Parse(segment)
+	if err != nil {
+		return "", fmt.Errorf("resolve tenant: no tenant ID in issuer %q", config.Issuer)
+	}
+	return id.String(), nil
+}
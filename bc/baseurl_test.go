@@ -0,0 +1,126 @@
+package bc_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/erlorenz/bc-go/internal/bctest"
+)
+
+// discoveryClient answers the OpenID discovery of contoso.onmicrosoft.com and the
+// deployment URL of every environment but Missing.
+func discoveryClient(requested *[]string) *http.Client {
+	return &http.Client{Transport: bc.RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		*requested = append(*requested, r.URL.String())
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/Missing/deployment/url"):
+			return bctest.NewResponse(http.StatusOK, map[string]any{"data": nil, "status": "DoesNotExist"}), nil
+		case strings.HasSuffix(r.URL.Path, "/deployment/url"):
+			return bctest.NewResponse(http.StatusOK, map[string]any{"data": "https://" + r.URL.Host + strings.TrimSuffix(r.URL.Path, "/deployment/url"), "status": "Success"}), nil
+		case r.URL.Path == "/contoso.onmicrosoft.com/v2.0/.well-known/openid-configuration":
+			return bctest.NewResponse(http.StatusOK, map[string]any{"issuer": "https://login.microsoftonline.com/" + validGUID + "/v2.0"}), nil
+		default:
+			return bctest.NewResponse(http.StatusBadRequest, map[string]any{"error": "invalid_tenant"}), nil
+		}
+	})}
+}
+
+func TestResolveBaseURL(t *testing.T) {
+	var requested []string
+	hc := discoveryClient(&requested)
+	ctx := context.Background()
+	want := "https://api.businesscentral.dynamics.com/v2.0/" + validGUID + "/Production/api/v2.0"
+
+	u, err := bc.ResolveBaseURL(ctx, validGUID, "Production", bc.WithAuthHTTPClient(hc))
+	if err != nil || u.String() != want {
+		t.Errorf("wanted %s, got %v %v", want, u, err)
+	}
+	if len(requested) != 1 || requested[0] != "https://businesscentral.dynamics.com/"+validGUID+"/Production/deployment/url" {
+		t.Errorf("wanted only the environment checked, got %v", requested)
+	}
+
+	requested = nil
+	u, err = bc.ResolveBaseURL(ctx, "contoso.onmicrosoft.com", "Production", bc.WithAuthHTTPClient(hc))
+	if err != nil || u.String() != want {
+		t.Errorf("wanted %s for the domain, got %v %v", want, u, err)
+	}
+	if len(requested) != 2 || requested[0] != "https://login.microsoft.com/contoso.onmicrosoft.com/v2.0/.well-known/openid-configuration" {
+		t.Errorf("unexpected discovery requests %v", requested)
+	}
+
+	requested = nil
+	u, err = bc.ResolveBaseURL(ctx, validGUID, "Sandbox", bc.WithScope("https://api.businesscentral.dynamics.us/.default"), bc.WithAuthHTTPClient(hc))
+	if err != nil || u.Host != "api.businesscentral.dynamics.us" {
+		t.Errorf("wanted the host of the scope, got %v %v", u, err)
+	}
+	if len(requested) != 1 || !strings.HasPrefix(requested[0], "https://businesscentral.dynamics.us/") {
+		t.Errorf("wanted the environment checked on the host of the scope, got %v", requested)
+	}
+
+	if _, err := bc.ResolveBaseURL(ctx, "fabrikam.com", "Production", bc.WithAuthHTTPClient(hc)); !errors.Is(err, bc.ErrUnknownTenant) {
+		t.Errorf("wanted ErrUnknownTenant, got %v", err)
+	}
+	for _, env := range []string{"", "Prod/../x", "a name", "ThisEnvironmentNameIsFarTooLongForBC"} {
+		if _, err := bc.ResolveBaseURL(ctx, validGUID, env, bc.WithAuthHTTPClient(hc)); err == nil {
+			t.Errorf("wanted error for environment %q", env)
+		}
+	}
+	if _, err := bc.ResolveBaseURL(ctx, "contoso", "Production", bc.WithAuthHTTPClient(hc)); err == nil {
+		t.Error("wanted error for a tenant that is neither an ID nor a domain")
+	}
+}
+
+func TestSetBaseURL(t *testing.T) {
+	var checked []string
+	u, err := bc.ResolveBaseURL(context.Background(), validGUID, "Sandbox",
+		bc.WithScope("https://api.businesscentral.dynamics.us/.default"), bc.WithAuthHTTPClient(discoveryClient(&checked)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := fakeConfig
+	config.TenantID, config.Environment = "", ""
+	if err := config.SetBaseURL(u); err != nil {
+		t.Fatal(err)
+	}
+
+	var requested string
+	hc := &http.Client{Transport: bc.RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		requested = r.URL.String()
+		return bctest.NewResponse(http.StatusOK, map[string]any{"id": validGUID}), nil
+	})}
+	client, err := bc.NewClient(config, bc.WithAuthClient(fakeTokenGetter{}), bc.WithHTTPClient(hc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Ping(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	want := "https://api.businesscentral.dynamics.us/v2.0/" + validGUID + "/Sandbox/api/publisher/group/1.0/companies("
+	if !strings.HasPrefix(requested, want) {
+		t.Errorf("wanted a request to %s, got %s", want, requested)
+	}
+
+	if err := config.SetBaseURL(&url.URL{Scheme: "https", Host: "example.com", Path: "/companies"}); err == nil {
+		t.Error("wanted error for a URL that is not from ResolveBaseURL")
+	}
+}
+
+func TestResolveBaseURLUnknownEnvironment(t *testing.T) {
+	var requested []string
+	_, err := bc.ResolveBaseURL(context.Background(), validGUID, "Missing", bc.WithAuthHTTPClient(discoveryClient(&requested)))
+	if !errors.Is(err, bc.ErrUnknownEnvironment) {
+		t.Errorf("wanted ErrUnknownEnvironment, got %v", err)
+	}
+
+	hc := &http.Client{Transport: bc.RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		return bctest.NewResponse(http.StatusNotFound, nil), nil
+	})}
+	if _, err := bc.ResolveBaseURL(context.Background(), validGUID, "Missing", bc.WithAuthHTTPClient(hc)); !errors.Is(err, bc.ErrUnknownEnvironment) {
+		t.Errorf("wanted ErrUnknownEnvironment for a 404, got %v", err)
+	}
+}
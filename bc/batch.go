@@ -0,0 +1,240 @@
+package bc
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// MaxBatchSize is the maximum number of requests BC accepts in a single $batch.
+const MaxBatchSize = 100
+
+// BatchOptions configure [Client.Batch].
+type BatchOptions struct {
+	// Atomic puts all requests in one atomicity group so they either all succeed or all fail.
+	Atomic bool
+}
+
+// BatchResponse is the response to a single request in a batch.
+type BatchResponse struct {
+	// ID is the index of the request in the batch as a string.
+	ID         string            `json:"id"`
+	StatusCode int               `json:"status"`
+	Header     map[string]string `json:"headers,omitempty"`
+	Body       json.RawMessage   `json:"body,omitempty"`
+}
+
+// Err returns an [APIError] if the status is an error status, otherwise nil.
+func (br BatchResponse) Err() error {
+	if br.StatusCode >= 200 && br.StatusCode < 300 {
+		return nil
+	}
+
+	var data ErrorResponse
+	if err := json.Unmarshal(br.Body, &data); err != nil {
+		return fmt.Errorf("failed decoding batch response %s into ErrorResponse: %s", br.ID, string(br.Body))
+	}
+	return newBCAPIError(br.StatusCode, data.Error.Code, data.Error.Message, nil)
+}
+
+// DecodeBatchResponse decodes the body of a successful [BatchResponse] into T,
+// or returns the [APIError] of a failed one.
+func DecodeBatchResponse[T Validator](br BatchResponse) (T, error) {
+	var data T
+
+	if err := br.Err(); err != nil {
+		return data, err
+	}
+
+	if err := json.Unmarshal(br.Body, &data); err != nil {
+		return data, fmt.Errorf("could not decode %T: %w", data, err)
+	}
+
+	if err := data.Validate(); err != nil {
+		return data, fmt.Errorf("failed validation of %T: %w", data, err)
+	}
+	return data, nil
+}
+
+type batchRequestItem struct {
+	ID             string            `json:"id"`
+	AtomicityGroup string            `json:"atomicityGroup,omitempty"`
+	Method         string            `json:"method"`
+	URL            string            `json:"url"`
+	Headers        map[string]string `json:"headers,omitempty"`
+	Body           json.RawMessage   `json:"body,omitempty"`
+}
+
+type batchRequestBody struct {
+	Requests []batchRequestItem `json:"requests"`
+}
+
+type batchResponseBody struct {
+	Responses []BatchResponse `json:"responses"`
+}
+
+func (b batchResponseBody) Validate() error {
+	return nil
+}
+
+// Batch sends up to [MaxBatchSize] requests in a single OData JSON $batch request.
+// The responses are returned in the same order as the requests. A failed request
+// in a non-atomic batch does not fail the others, check each [BatchResponse.Err].
+func (c *Client) Batch(ctx context.Context, requests []RequestOptions, opts BatchOptions) ([]BatchResponse, error) {
+	if len(requests) == 0 {
+		return nil, nil
+	}
+	if len(requests) > MaxBatchSize {
+		return nil, fmt.Errorf("batch: %d requests exceeds the max of %d", len(requests), MaxBatchSize)
+	}
+
+	body := batchRequestBody{Requests: make([]batchRequestItem, len(requests))}
+	for i, r := range requests {
+		// Marshaling large bodies takes a while, so stop as soon as the context is done.
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("batch: %w", err)
+		}
+		item, err := c.batchItem(strconv.Itoa(i), r)
+		if err != nil {
+			return nil, fmt.Errorf("batch request %d: %w", i, err)
+		}
+		if opts.Atomic {
+			item.AtomicityGroup = "group1"
+		}
+		body.Requests[i] = item
+	}
+
+	req, err := c.newRequest(ctx, c.batchURL(), RequestOptions{Method: http.MethodPost, Body: body})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Request: %w", err)
+	}
+	req.Header.Set("Accept", ContentTypeJSON)
+
+	c.logger.Debug("Sending batch request...", "url", req.URL.String(), "count", len(requests))
+
+	res, err := c.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed during request: %w", err)
+	}
+
+	data, err := decode[batchResponseBody](c, res)
+	if err != nil {
+		var srvErr APIError
+		if errors.As(err, &srvErr) {
+			c.logger.Debug("API server returned error response.", "error", srvErr)
+			return nil, fmt.Errorf("error from BC API: %w", srvErr)
+		}
+
+		c.logger.Debug("Failed to decode response.", "error", err)
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	// Responses may be in any order so sort them by ID.
+	responses := make([]BatchResponse, len(requests))
+	for _, r := range data.Responses {
+		i, err := strconv.Atoi(r.ID)
+		if err != nil || i < 0 || i >= len(responses) {
+			return nil, fmt.Errorf("batch: unexpected response id %q", r.ID)
+		}
+		responses[i] = r
+	}
+	return responses, nil
+}
+
+// BulkOptions configure [Client.Bulk].
+type BulkOptions struct {
+	// ChunkSize is the number of requests per batch. Defaults to MaxBatchSize.
+	ChunkSize int
+	// Atomic makes each chunk atomic.
+	Atomic bool
+	// OnChunk is called after each chunk with the index of the first request in the chunk.
+	OnChunk func(offset int, responses []BatchResponse)
+}
+
+// Bulk splits the requests into batches and sends them in order, returning a response
+// per request. It stops at the first batch that fails as a whole, or when the context is
+// done, and returns the responses received so far.
+func (c *Client) Bulk(ctx context.Context, requests []RequestOptions, opts BulkOptions) ([]BatchResponse, error) {
+	size := opts.ChunkSize
+	if size <= 0 || size > MaxBatchSize {
+		size = MaxBatchSize
+	}
+
+	responses := make([]BatchResponse, 0, len(requests))
+	for offset := 0; offset < len(requests); offset += size {
+		chunk := requests[offset:min(offset+size, len(requests))]
+		if err := ctx.Err(); err != nil {
+			return responses, fmt.Errorf("bulk requests %d-%d: %w", offset, offset+len(chunk)-1, err)
+		}
+
+		res, err := c.Batch(ctx, chunk, BatchOptions{Atomic: opts.Atomic})
+		if err != nil {
+			return responses, fmt.Errorf("bulk requests %d-%d: %w", offset, offset+len(chunk)-1, err)
+		}
+		if opts.OnChunk != nil {
+			opts.OnChunk(offset, res)
+		}
+		responses = append(responses, res...)
+	}
+	return responses, nil
+}
+
+// batchItem validates the options and converts them to a request relative to the API root.
+func (c *Client) batchItem(id string, opts RequestOptions) (batchRequestItem, error) {
+	if err := c.validateRequest(opts); err != nil {
+		return batchRequestItem{}, err
+	}
+
+	u := BuildRequestURL(*c.baseURL, opts.EntitySetName, opts.RecordID, opts.QueryParams)
+	rel := strings.TrimPrefix(u.Path, c.apiRootPath()+"/")
+	if u.RawQuery != "" {
+		rel += "?" + u.RawQuery
+	}
+
+	item := batchRequestItem{
+		ID:      id,
+		Method:  opts.Method,
+		URL:     rel,
+		Headers: map[string]string{},
+	}
+
+	if opts.Body != nil {
+		b, err := c.codec.Marshal(opts.Body)
+		if err != nil {
+			return item, fmt.Errorf("cannot marshal body %s: %w", opts.Body, err)
+		}
+		item.Body = b
+		item.Headers["Content-Type"] = ContentTypeJSON
+	}
+
+	if opts.Method == http.MethodDelete || opts.Method == http.MethodPut || opts.Method == http.MethodPatch {
+		item.Headers["If-Match"] = cmp.Or(opts.ETag, "*")
+	}
+
+	for k := range opts.Header {
+		item.Headers[k] = opts.Header.Get(k)
+	}
+
+	return item, nil
+}
+
+// apiRootPath is the base URL path without the "/companies(...)" segment.
+func (c *Client) apiRootPath() string {
+	path := c.baseURL.Path
+	if i := strings.LastIndex(path, "/companies("); i >= 0 {
+		return path[:i]
+	}
+	return path
+}
+
+func (c *Client) batchURL() string {
+	u := *c.baseURL
+	u.Path = c.apiRootPath() + "/$batch"
+	u.RawQuery = ""
+	return u.String()
+}
@@ -0,0 +1,127 @@
+package bc_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/erlorenz/bc-go/internal/bctest"
+	"github.com/google/uuid"
+)
+
+type batchRequest struct {
+	Requests []struct {
+		ID             string            `json:"id"`
+		AtomicityGroup string            `json:"atomicityGroup"`
+		Method         string            `json:"method"`
+		URL            string            `json:"url"`
+		Headers        map[string]string `json:"headers"`
+		Body           json.RawMessage   `json:"body"`
+	} `json:"requests"`
+}
+
+func batchResponse(responses ...bc.BatchResponse) *http.Response {
+	return bctest.NewResponse(http.StatusOK, map[string]any{"responses": responses})
+}
+
+func TestBatch(t *testing.T) {
+	id := uuid.New()
+
+	st := &bctest.SequenceTransport{Responses: []*http.Response{batchResponse(
+		bc.BatchResponse{ID: "1", StatusCode: http.StatusBadRequest, Body: json.RawMessage(`{"error":{"code":"Application_DialogException","message":"Blocked."}}`)},
+		bc.BatchResponse{ID: "0", StatusCode: http.StatusCreated, Body: json.RawMessage(`{"ID":"` + validGUID + `"}`)},
+	)}}
+
+	client := newSequenceClient(t, st)
+
+	responses, err := client.Batch(context.Background(), []bc.RequestOptions{
+		{Method: http.MethodPost, EntitySetName: "customers", Body: map[string]any{"displayName": "A"}},
+		{Method: http.MethodPatch, EntitySetName: "customers", RecordID: id, Body: map[string]any{"blocked": true}, ETag: "W/1"},
+	}, bc.BatchOptions{Atomic: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := st.Requests[0]
+	if !strings.HasSuffix(req.URL.Path, "/api/publisher/group/1.0/$batch") {
+		t.Errorf("wrong batch url %s", req.URL)
+	}
+
+	var sent batchRequest
+	if err := json.Unmarshal([]byte(readBody(t, req)), &sent); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Requests", func(t *testing.T) {
+		if len(sent.Requests) != 2 {
+			t.Fatalf("wanted 2 requests, got %d", len(sent.Requests))
+		}
+		wantURL := "companies(" + validGUID + ")/customers(" + id.String() + ")"
+		if got := sent.Requests[1].URL; got != wantURL {
+			t.Errorf("wanted url %s, got %s", wantURL, got)
+		}
+		if got := sent.Requests[1].Headers["If-Match"]; got != "W/1" {
+			t.Errorf("wanted If-Match W/1, got %s", got)
+		}
+		if sent.Requests[0].AtomicityGroup == "" {
+			t.Error("wanted atomicity group")
+		}
+	})
+
+	t.Run("Responses", func(t *testing.T) {
+		if _, err := bc.DecodeBatchResponse[fakeEntity](responses[0]); err != nil {
+			t.Errorf("expected no error, got %s", err)
+		}
+
+		_, err := bc.DecodeBatchResponse[fakeEntity](responses[1])
+		var apiErr bc.APIError
+		if !errors.As(err, &apiErr) || apiErr.Code != bc.ErrorCodeDialogException {
+			t.Errorf("wanted DialogException, got %v", err)
+		}
+	})
+}
+
+func TestBatchTooMany(t *testing.T) {
+	client := newSequenceClient(t, &bctest.SequenceTransport{})
+
+	requests := make([]bc.RequestOptions, bc.MaxBatchSize+1)
+	if _, err := client.Batch(context.Background(), requests, bc.BatchOptions{}); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestBulk(t *testing.T) {
+	st := &bctest.SequenceTransport{Responses: []*http.Response{
+		batchResponse(bc.BatchResponse{ID: "0", StatusCode: 201}, bc.BatchResponse{ID: "1", StatusCode: 201}),
+		batchResponse(bc.BatchResponse{ID: "0", StatusCode: 201}),
+	}}
+	client := newSequenceClient(t, st)
+
+	requests := make([]bc.RequestOptions, 3)
+	for i := range requests {
+		requests[i] = bc.RequestOptions{Method: http.MethodPost, EntitySetName: "items", Body: map[string]any{"number": i}}
+	}
+
+	var offsets []int
+	responses, err := client.Bulk(context.Background(), requests, bc.BulkOptions{
+		ChunkSize: 2,
+		OnChunk:   func(offset int, _ []bc.BatchResponse) { offsets = append(offsets, offset) },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(responses) != 3 {
+		t.Errorf("wanted 3 responses, got %d", len(responses))
+	}
+	if st.Count() != 2 {
+		t.Errorf("wanted 2 batches, got %d", st.Count())
+	}
+	if len(offsets) != 2 || offsets[1] != 2 {
+		t.Errorf("wrong offsets %v", offsets)
+	}
+}
@@ -0,0 +1,186 @@
+package bc
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// GetBuilder builds the [RequestOptions] of a GET request. Create one with [Get]
+// and chain the option methods. There is no body, so a GET with a body cannot be built.
+type GetBuilder struct {
+	opts    RequestOptions
+	selects []string
+	expands []string
+	orders  []string
+	filter  string
+	top     int
+	skip    int
+}
+
+// Get returns a [GetBuilder] for the entity set.
+func Get(entitySetName string) GetBuilder {
+	return GetBuilder{opts: RequestOptions{Method: http.MethodGet, EntitySetName: entitySetName}}
+}
+
+// ID gets a single record instead of a list.
+func (b GetBuilder) ID(id uuid.UUID) GetBuilder {
+	b.opts.RecordID = id
+	return b
+}
+
+// Select adds fields to $select.
+func (b GetBuilder) Select(fields ...string) GetBuilder {
+	b.selects = append(b.selects[:len(b.selects):len(b.selects)], fields...)
+	return b
+}
+
+// Expand adds navigation properties to $expand, e.g. from [RenderExpands].
+func (b GetBuilder) Expand(expands ...string) GetBuilder {
+	b.expands = append(b.expands[:len(b.expands):len(b.expands)], expands...)
+	return b
+}
+
+// Filter sets the $filter expression.
+func (b GetBuilder) Filter(filter string) GetBuilder {
+	b.filter = filter
+	return b
+}
+
+// OrderBy adds fields to $orderby, e.g. "number desc".
+func (b GetBuilder) OrderBy(fields ...string) GetBuilder {
+	b.orders = append(b.orders[:len(b.orders):len(b.orders)], fields...)
+	return b
+}
+
+// Top sets $top.
+func (b GetBuilder) Top(n int) GetBuilder {
+	b.top = n
+	return b
+}
+
+// Skip sets $skip.
+func (b GetBuilder) Skip(n int) GetBuilder {
+	b.skip = n
+	return b
+}
+
+// Header adds a request header, e.g. "Prefer".
+func (b GetBuilder) Header(key, value string) GetBuilder {
+	b.opts.Header = addHeader(b.opts.Header, key, value)
+	return b
+}
+
+// Build validates the options and returns the [RequestOptions].
+func (b GetBuilder) Build() (RequestOptions, error) {
+	var errs []string
+	for _, field := range b.selects {
+		if err := validateIdentifier(field); err != nil {
+			errs = append(errs, fmt.Sprintf("select: %s", err))
+		}
+	}
+	if b.top < 0 || b.skip < 0 {
+		errs = append(errs, "top and skip cannot be negative")
+	}
+	if b.opts.RecordID != uuid.Nil && (b.filter != "" || b.top != 0 || b.skip != 0 || len(b.orders) > 0) {
+		errs = append(errs, "invalid combination: cannot have filter, orderby, top or skip with an ID")
+	}
+	if len(errs) > 0 {
+		return RequestOptions{}, fmt.Errorf("invalid requestoptions: [ %s ]", strings.Join(errs, ", "))
+	}
+
+	qp := QueryParams{}
+	if len(b.selects) > 0 {
+		qp.Set("$select", strings.Join(b.selects, ","))
+	}
+	if len(b.expands) > 0 {
+		qp.Set("$expand", strings.Join(b.expands, ","))
+	}
+	if b.filter != "" {
+		qp.Set("$filter", b.filter)
+	}
+	if len(b.orders) > 0 {
+		qp.Set("$orderby", strings.Join(b.orders, ","))
+	}
+	if b.top > 0 {
+		qp.Set("$top", strconv.Itoa(b.top))
+	}
+	if b.skip > 0 {
+		qp.Set("$skip", strconv.Itoa(b.skip))
+	}
+	if len(qp) > 0 {
+		b.opts.QueryParams = qp
+	}
+
+	if err := b.opts.Validate(); err != nil {
+		return RequestOptions{}, err
+	}
+	return b.opts, nil
+}
+
+// WriteBuilder builds the [RequestOptions] of a POST, PATCH, PUT or DELETE request.
+// Create one with [Post], [Patch], [Put] or [Delete]. There is no $filter, so a write
+// with a filter cannot be built.
+type WriteBuilder struct {
+	opts RequestOptions
+}
+
+// Post returns a [WriteBuilder] that creates a record in the entity set.
+func Post(entitySetName string, body any) WriteBuilder {
+	return WriteBuilder{opts: RequestOptions{Method: http.MethodPost, EntitySetName: entitySetName, Body: body}}
+}
+
+// Patch returns a [WriteBuilder] that updates the fields of the body on the record.
+func Patch(entitySetName string, id uuid.UUID, body any) WriteBuilder {
+	return WriteBuilder{opts: RequestOptions{Method: http.MethodPatch, EntitySetName: entitySetName, RecordID: id, Body: body}}
+}
+
+// Put returns a [WriteBuilder] that replaces the record.
+func Put(entitySetName string, id uuid.UUID, body any) WriteBuilder {
+	return WriteBuilder{opts: RequestOptions{Method: http.MethodPut, EntitySetName: entitySetName, RecordID: id, Body: body}}
+}
+
+// Delete returns a [WriteBuilder] that deletes the record.
+func Delete(entitySetName string, id uuid.UUID) WriteBuilder {
+	return WriteBuilder{opts: RequestOptions{Method: http.MethodDelete, EntitySetName: entitySetName, RecordID: id}}
+}
+
+// IfMatch sets the ETag, so the request fails with [ErrPreconditionFailed] if the record changed.
+// Not valid for [Post].
+func (b WriteBuilder) IfMatch(etag string) WriteBuilder {
+	b.opts.ETag = etag
+	return b
+}
+
+// Header adds a request header, e.g. "Prefer".
+func (b WriteBuilder) Header(key, value string) WriteBuilder {
+	b.opts.Header = addHeader(b.opts.Header, key, value)
+	return b
+}
+
+// Build validates the options and returns the [RequestOptions].
+func (b WriteBuilder) Build() (RequestOptions, error) {
+	if b.opts.Method != http.MethodPost && b.opts.RecordID == uuid.Nil {
+		return RequestOptions{}, fmt.Errorf("invalid requestoptions: [ invalid combination: cannot have method %s with no RecordID ]", b.opts.Method)
+	}
+	if b.opts.Method == http.MethodPost && b.opts.ETag != "" {
+		return RequestOptions{}, fmt.Errorf("invalid requestoptions: [ invalid combination: cannot have ETag with method %s ]", b.opts.Method)
+	}
+	if err := b.opts.Validate(); err != nil {
+		return RequestOptions{}, err
+	}
+	return b.opts, nil
+}
+
+// addHeader returns a copy of the header with the value added, so builders do not share it.
+func addHeader(h http.Header, key, value string) http.Header {
+	h = h.Clone()
+	if h == nil {
+		h = http.Header{}
+	}
+	h.Add(key, value)
+	return h
+}
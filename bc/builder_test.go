@@ -0,0 +1,100 @@
+package bc_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/google/uuid"
+)
+
+func TestGetBuilder(t *testing.T) {
+	id := uuid.New()
+
+	opts, err := bc.Get("customers").
+		Select("id", "number").
+		Expand("paymentTerm").
+		Filter("balance gt 0").
+		OrderBy("number desc").
+		Top(10).
+		Skip(20).
+		Header("Prefer", "odata.maxpagesize=5").
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "$select=id,number&$expand=paymentTerm&$filter=balance%20gt%200&$orderby=number%20desc&$top=10&$skip=20"
+	if opts.Method != http.MethodGet || opts.EntitySetName != "customers" {
+		t.Fatalf("unexpected options %+v", opts)
+	}
+	if got := opts.QueryParams.Encode(); got != want {
+		t.Errorf("wanted %s, got %s", want, got)
+	}
+	if opts.Header.Get("Prefer") != "odata.maxpagesize=5" {
+		t.Errorf("unexpected header %v", opts.Header)
+	}
+
+	single, err := bc.Get("customers").ID(id).Select("id").Build()
+	if err != nil || single.RecordID != id {
+		t.Errorf("wanted record %s, got %+v %v", id, single, err)
+	}
+}
+
+func TestGetBuilderDoesNotShareState(t *testing.T) {
+	base := bc.Get("customers").Select("id")
+	a, _ := base.Select("number").Header("Prefer", "a").Build()
+	b, _ := base.Select("name").Build()
+
+	if a.QueryParams.Get("$select") != "id,number" || b.QueryParams.Get("$select") != "id,name" {
+		t.Errorf("builders share selects: %s, %s", a.QueryParams.Get("$select"), b.QueryParams.Get("$select"))
+	}
+	if b.Header != nil {
+		t.Errorf("builders share headers: %v", b.Header)
+	}
+}
+
+func TestBuilderInvalid(t *testing.T) {
+	id := uuid.New()
+
+	table := []struct {
+		name  string
+		build func() (bc.RequestOptions, error)
+	}{
+		{"NoEntitySet", bc.Get("").Build},
+		{"BadSelect", bc.Get("customers").Select("id,number").Build},
+		{"NegativeTop", bc.Get("customers").Top(-1).Build},
+		{"FilterWithID", bc.Get("customers").ID(id).Filter("number eq '1'").Build},
+		{"PatchNoID", bc.Patch("customers", uuid.Nil, map[string]any{}).Build},
+		{"DeleteNoID", bc.Delete("customers", uuid.Nil).Build},
+		{"PostWithETag", bc.Post("customers", map[string]any{}).IfMatch(`W/"1"`).Build},
+	}
+
+	for _, v := range table {
+		t.Run(v.name, func(t *testing.T) {
+			if _, err := v.build(); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestWriteBuilder(t *testing.T) {
+	id := uuid.New()
+	body := map[string]any{"displayName": "New"}
+
+	opts, err := bc.Patch("customers", id, body).IfMatch(`W/"1"`).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opts.Method != http.MethodPatch || opts.RecordID != id || opts.ETag != `W/"1"` || opts.Body == nil {
+		t.Errorf("unexpected options %+v", opts)
+	}
+
+	if _, err := bc.Post("customers", body).Build(); err != nil {
+		t.Errorf("unexpected error for post: %v", err)
+	}
+	if _, err := bc.Delete("customers", id).Build(); err != nil {
+		t.Errorf("unexpected error for delete: %v", err)
+	}
+}
@@ -0,0 +1,177 @@
+package bc_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/erlorenz/bc-go/internal/bctest"
+	"github.com/google/uuid"
+)
+
+// checkGoroutines fails if the number of goroutines does not return to n.
+func checkGoroutines(t *testing.T, n int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > n {
+		if time.Now().After(deadline) {
+			t.Fatalf("goroutine leak: %d goroutines, wanted %d", runtime.NumGoroutine(), n)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func newCancelClient(t *testing.T, tg bc.TokenGetter, rt bc.RoundTripperFunc) *bc.Client {
+	t.Helper()
+	client, err := bc.NewClient(fakeConfig, bc.WithAuthClient(tg), bc.WithHTTPClient(&http.Client{Transport: rt}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+func TestCancelBetweenPages(t *testing.T) {
+	goroutines := runtime.NumGoroutine()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var requests atomic.Int32
+	client := newCancelClient(t, fakeTokenGetter{}, func(r *http.Request) (*http.Response, error) {
+		requests.Add(1)
+		// The sync is shut down while the first page is in flight.
+		cancel()
+		res := bctest.NewResponse(http.StatusOK, map[string]any{
+			"value":           []map[string]any{{"ID": validGUID}},
+			"@odata.nextLink": r.URL.String() + "&$skiptoken=next",
+		})
+		res.Request = r
+		return res, nil
+	})
+
+	_, err := client.Snapshot(ctx, []bc.EntitySpec{{EntitySetName: "customers"}})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("wanted context.Canceled, got %v", err)
+	}
+	if n := requests.Load(); n != 1 {
+		t.Errorf("wanted no request after the cancel, got %d", n)
+	}
+	checkGoroutines(t, goroutines)
+}
+
+// blockingTokenGetter waits for the context like a token request that hangs.
+type blockingTokenGetter struct{}
+
+func (blockingTokenGetter) GetToken(ctx context.Context) (bc.AccessToken, error) {
+	<-ctx.Done()
+	return "", ctx.Err()
+}
+
+// cancelingTokenGetter ignores the context and cancels it before returning a token.
+type cancelingTokenGetter struct {
+	cancel context.CancelFunc
+}
+
+func (tg cancelingTokenGetter) GetToken(context.Context) (bc.AccessToken, error) {
+	tg.cancel()
+	return "token", nil
+}
+
+func TestCancelTokenAcquisition(t *testing.T) {
+	goroutines := runtime.NumGoroutine()
+	opts := bc.RequestOptions{Method: http.MethodGet, EntitySetName: "customers"}
+	noRequest := func(r *http.Request) (*http.Response, error) {
+		t.Error("wanted no request to be sent")
+		return nil, errors.New("unexpected request")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	client := newCancelClient(t, blockingTokenGetter{}, noRequest)
+	if _, err := client.NewRequest(ctx, opts); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("wanted context.DeadlineExceeded, got %v", err)
+	}
+
+	ctx, cancel = context.WithCancel(context.Background())
+	client = newCancelClient(t, cancelingTokenGetter{cancel}, noRequest)
+	if _, err := client.NewRequest(ctx, opts); !errors.Is(err, context.Canceled) {
+		t.Errorf("wanted context.Canceled for a token getter that ignores the context, got %v", err)
+	}
+
+	if _, err := client.NewRequest(ctx, opts); !errors.Is(err, context.Canceled) {
+		t.Errorf("wanted context.Canceled before acquiring a token, got %v", err)
+	}
+	checkGoroutines(t, goroutines)
+}
+
+// cancelingBody cancels the context when it is marshaled.
+type cancelingBody struct {
+	cancel context.CancelFunc
+	count  *atomic.Int32
+}
+
+func (b cancelingBody) MarshalJSON() ([]byte, error) {
+	b.count.Add(1)
+	b.cancel()
+	return []byte(`{}`), nil
+}
+
+func TestCancelBatchAssembly(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client := newCancelClient(t, fakeTokenGetter{}, func(r *http.Request) (*http.Response, error) {
+		t.Error("wanted no batch to be sent")
+		return nil, errors.New("unexpected request")
+	})
+
+	var marshaled atomic.Int32
+	body := cancelingBody{cancel: cancel, count: &marshaled}
+	requests := []bc.RequestOptions{
+		{Method: http.MethodPost, EntitySetName: "customers", Body: body},
+		{Method: http.MethodPost, EntitySetName: "customers", Body: body},
+		{Method: http.MethodPost, EntitySetName: "customers", Body: body},
+	}
+
+	if _, err := client.Batch(ctx, requests, bc.BatchOptions{}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("wanted context.Canceled, got %v", err)
+	}
+	if n := marshaled.Load(); n != 1 {
+		t.Errorf("wanted the assembly to stop after the first body, got %d", n)
+	}
+}
+
+func TestCancelBulkBetweenChunks(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var batches atomic.Int32
+	client := newCancelClient(t, fakeTokenGetter{}, func(r *http.Request) (*http.Response, error) {
+		batches.Add(1)
+		res := bctest.NewResponse(http.StatusOK, map[string]any{
+			"responses": []map[string]any{{"id": "0", "status": http.StatusNoContent}},
+		})
+		res.Request = r
+		return res, nil
+	})
+
+	requests := []bc.RequestOptions{
+		{Method: http.MethodDelete, EntitySetName: "customers", RecordID: uuid.New()},
+		{Method: http.MethodDelete, EntitySetName: "customers", RecordID: uuid.New()},
+		{Method: http.MethodDelete, EntitySetName: "customers", RecordID: uuid.New()},
+	}
+	responses, err := client.Bulk(ctx, requests, bc.BulkOptions{
+		ChunkSize: 1,
+		OnChunk:   func(int, []bc.BatchResponse) { cancel() },
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("wanted context.Canceled, got %v", err)
+	}
+	if len(responses) != 1 || batches.Load() != 1 {
+		t.Errorf("wanted to stop after the first chunk, got %d responses and %d batches", len(responses), batches.Load())
+	}
+}
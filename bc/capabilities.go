@@ -0,0 +1,354 @@
+package bc
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Capabilities describe what the API of the environment supports, from its $metadata.
+// Use them to check for entities and fields that only exist in some BC versions.
+type Capabilities struct {
+	// APIVersion is the version segment of the APIEndpoint, e.g. "v2.0" or "1.0" for an extension API.
+	APIVersion string
+	// EntitySets maps each entity set name to the names of its fields.
+	EntitySets map[string][]string
+	// Properties maps each entity set name to its structural properties with their types,
+	// without the navigation properties.
+	Properties map[string][]Property
+	// NavigationProperties maps each entity set name to its navigation properties.
+	NavigationProperties map[string][]NavigationProperty
+	// Actions maps each entity set name to its bound actions, without the "Microsoft.NAV." namespace.
+	Actions map[string][]string
+	// Header has the response headers of the $metadata request, which include the
+	// platform and application version headers when BC sends them.
+	Header http.Header
+	// Deprecated has a [Warning] for each entity set and field with a deprecated revision annotation.
+	Deprecated []Warning
+}
+
+// Property is a structural property of an entity type in the $metadata.
+type Property struct {
+	Name string
+	// Type is the EDM type, e.g. "Edm.String", "Edm.Decimal" or "Edm.Date",
+	// or the qualified name of an enum type like "Microsoft.NAV.contactType".
+	Type     string
+	Nullable bool
+	// MaxLength, Precision and Scale are 0 if they are not set. Scale is -1 if it is "Variable",
+	// which BC uses for most decimals.
+	MaxLength int
+	Precision int
+	Scale     int
+}
+
+// NavigationProperty is a navigation property of an entity type in the $metadata.
+type NavigationProperty struct {
+	Name string
+	// EntitySetName is the entity set of the target, from the navigation property binding
+	// or the only entity set of the target type. It is empty if there is none.
+	EntitySetName string
+	// Collection is true for a list, like the lines of a document.
+	Collection bool
+	// Constraints map the properties of the entity to the ones of the target they
+	// reference, e.g. "customerId" to "id".
+	Constraints map[string]string
+}
+
+// Property returns the property of the entity set, if it exists.
+func (c Capabilities) Property(entitySetName, field string) (Property, bool) {
+	i := slices.IndexFunc(c.Properties[entitySetName], func(p Property) bool { return p.Name == field })
+	if i < 0 {
+		return Property{}, false
+	}
+	return c.Properties[entitySetName][i], true
+}
+
+// NavigationProperty returns the navigation property of the entity set, if it exists.
+func (c Capabilities) NavigationProperty(entitySetName, name string) (NavigationProperty, bool) {
+	i := slices.IndexFunc(c.NavigationProperties[entitySetName], func(p NavigationProperty) bool { return p.Name == name })
+	if i < 0 {
+		return NavigationProperty{}, false
+	}
+	return c.NavigationProperties[entitySetName][i], true
+}
+
+// HasEntitySet returns true if the entity set exists.
+func (c Capabilities) HasEntitySet(entitySetName string) bool {
+	_, ok := c.EntitySets[entitySetName]
+	return ok
+}
+
+// HasField returns true if the entity set has the field.
+func (c Capabilities) HasField(entitySetName, field string) bool {
+	return slices.Contains(c.EntitySets[entitySetName], field)
+}
+
+// HasAction returns true if the entity set has the bound action, e.g. "post".
+func (c Capabilities) HasAction(entitySetName, action string) bool {
+	return slices.Contains(c.Actions[entitySetName], action)
+}
+
+// edmx is the subset of the $metadata document used for [Capabilities].
+type edmx struct {
+	Schemas []struct {
+		Namespace   string `xml:"Namespace,attr"`
+		EntityTypes []struct {
+			Name        string          `xml:"Name,attr"`
+			Annotations []edmAnnotation `xml:"Annotation"`
+			Properties  []struct {
+				Name        string          `xml:"Name,attr"`
+				Type        string          `xml:"Type,attr"`
+				Nullable    string          `xml:"Nullable,attr"`
+				MaxLength   string          `xml:"MaxLength,attr"`
+				Precision   string          `xml:"Precision,attr"`
+				Scale       string          `xml:"Scale,attr"`
+				Annotations []edmAnnotation `xml:"Annotation"`
+			} `xml:"Property"`
+			NavigationProperties []struct {
+				Name        string `xml:"Name,attr"`
+				Type        string `xml:"Type,attr"`
+				Constraints []struct {
+					Property           string `xml:"Property,attr"`
+					ReferencedProperty string `xml:"ReferencedProperty,attr"`
+				} `xml:"ReferentialConstraint"`
+			} `xml:"NavigationProperty"`
+		} `xml:"EntityType"`
+		Actions []struct {
+			Name       string `xml:"Name,attr"`
+			IsBound    bool   `xml:"IsBound,attr"`
+			Parameters []struct {
+				Type string `xml:"Type,attr"`
+			} `xml:"Parameter"`
+		} `xml:"Action"`
+		EntitySets []struct {
+			Name       string `xml:"Name,attr"`
+			EntityType string `xml:"EntityType,attr"`
+			Bindings   []struct {
+				Path   string `xml:"Path,attr"`
+				Target string `xml:"Target,attr"`
+			} `xml:"NavigationPropertyBinding"`
+		} `xml:"EntityContainer>EntitySet"`
+		Annotations []struct {
+			Target      string          `xml:"Target,attr"`
+			Annotations []edmAnnotation `xml:"Annotation"`
+		} `xml:"Annotations"`
+	} `xml:"DataServices>Schema"`
+}
+
+// edmAnnotation is an annotation like Org.OData.Core.V1.Revisions.
+type edmAnnotation struct {
+	Term    string `xml:"Term,attr"`
+	Records []struct {
+		PropertyValues []struct {
+			Property   string `xml:"Property,attr"`
+			String     string `xml:"String,attr"`
+			EnumMember string `xml:"EnumMember,attr"`
+		} `xml:"PropertyValue"`
+	} `xml:"Collection>Record"`
+}
+
+// facet parses a MaxLength, Precision or Scale attribute. "Variable" is -1.
+func facet(s string) int {
+	if s == "Variable" {
+		return -1
+	}
+	n, _ := strconv.Atoi(s)
+	return n
+}
+
+// deprecation returns the description of a deprecated revision and true if there is one.
+func deprecation(annotations []edmAnnotation) (string, bool) {
+	for _, a := range annotations {
+		if !strings.HasSuffix(a.Term, ".Revisions") {
+			continue
+		}
+		for _, record := range a.Records {
+			var deprecated bool
+			var description string
+			for _, pv := range record.PropertyValues {
+				switch pv.Property {
+				case "Kind":
+					deprecated = strings.HasSuffix(pv.EnumMember, "/Deprecated")
+				case "Description":
+					description = pv.String
+				}
+			}
+			if deprecated {
+				return description, true
+			}
+		}
+	}
+	return "", false
+}
+
+// Capabilities requests the $metadata of the API and reports the entity sets,
+// their fields and bound actions. Deprecated entity sets and fields are also reported
+// to the [WarningHandler]. The document can be large, so call it once at startup.
+func (c *Client) Capabilities(ctx context.Context) (Capabilities, error) {
+	u := *c.baseURL
+	u.Path = c.apiRootPath() + "/$metadata"
+	u.RawQuery = ""
+
+	req, err := c.newRequest(ctx, u.String(), RequestOptions{
+		Method: http.MethodGet,
+		Header: http.Header{"Accept": {"application/xml"}},
+	})
+	if err != nil {
+		return Capabilities{}, fmt.Errorf("failed to create Request: %w", err)
+	}
+
+	c.logger.Debug("Sending request...", "url", req.URL.String(), "method", req.Method)
+
+	res, err := c.Do(req)
+	if err != nil {
+		return Capabilities{}, fmt.Errorf("failed during request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		err := decodeErrorResponse(res)
+		var srvErr APIError
+		if errors.As(err, &srvErr) {
+			c.logger.Debug("API server returned error response.", "error", srvErr)
+			return Capabilities{}, fmt.Errorf("error from BC API: %w", srvErr)
+		}
+		return Capabilities{}, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	caps, err := ParseCapabilities(res.Body)
+	if err != nil {
+		return Capabilities{}, fmt.Errorf("failed to decode response: %w", err)
+	}
+	caps.APIVersion = c.APIVersion()
+	caps.Header = res.Header
+
+	for _, w := range caps.Deprecated {
+		c.warnings.report(ctx, w)
+	}
+
+	c.logger.Debug("Successfully read capabilities.", "entitySets", len(caps.EntitySets))
+	return caps, nil
+}
+
+// ParseCapabilities reads a $metadata document, e.g. one saved to a file.
+// APIVersion and Header are not set, and the deprecations are not reported.
+func ParseCapabilities(r io.Reader) (Capabilities, error) {
+	var doc edmx
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return Capabilities{}, fmt.Errorf("could not decode $metadata: %w", err)
+	}
+
+	caps := Capabilities{
+		EntitySets:           map[string][]string{},
+		Properties:           map[string][]Property{},
+		NavigationProperties: map[string][]NavigationProperty{},
+		Actions:              map[string][]string{},
+	}
+
+	// Index the fields and entity sets by the qualified type name
+	fields := map[string][]string{}
+	properties := map[string][]Property{}
+	setsByType := map[string][]string{}
+	bindings := map[string]string{}
+	for _, schema := range doc.Schemas {
+		for _, et := range schema.EntityTypes {
+			var names []string
+			var props []Property
+			for _, p := range et.Properties {
+				names = append(names, p.Name)
+				props = append(props, Property{
+					Name:      p.Name,
+					Type:      p.Type,
+					Nullable:  p.Nullable != "false",
+					MaxLength: facet(p.MaxLength),
+					Precision: facet(p.Precision),
+					Scale:     facet(p.Scale),
+				})
+			}
+			for _, p := range et.NavigationProperties {
+				names = append(names, p.Name)
+			}
+			fields[schema.Namespace+"."+et.Name] = names
+			properties[schema.Namespace+"."+et.Name] = props
+		}
+		for _, es := range schema.EntitySets {
+			setsByType[es.EntityType] = append(setsByType[es.EntityType], es.Name)
+			for _, b := range es.Bindings {
+				bindings[es.Name+"/"+b.Path] = b.Target
+			}
+		}
+	}
+
+	for typeName, sets := range setsByType {
+		for _, set := range sets {
+			caps.EntitySets[set] = fields[typeName]
+			caps.Properties[set] = properties[typeName]
+		}
+	}
+
+	// Navigation properties need the entity sets of their target types
+	for _, schema := range doc.Schemas {
+		for _, et := range schema.EntityTypes {
+			for _, set := range setsByType[schema.Namespace+"."+et.Name] {
+				for _, p := range et.NavigationProperties {
+					target, collection := strings.CutPrefix(p.Type, "Collection(")
+					target = strings.TrimSuffix(target, ")")
+					nav := NavigationProperty{Name: p.Name, Collection: collection, EntitySetName: bindings[set+"/"+p.Name]}
+					if nav.EntitySetName == "" && len(setsByType[target]) == 1 {
+						nav.EntitySetName = setsByType[target][0]
+					}
+					for _, c := range p.Constraints {
+						if nav.Constraints == nil {
+							nav.Constraints = map[string]string{}
+						}
+						nav.Constraints[c.Property] = c.ReferencedProperty
+					}
+					caps.NavigationProperties[set] = append(caps.NavigationProperties[set], nav)
+				}
+			}
+		}
+	}
+
+	// The first parameter of a bound action is the entity type it is bound to
+	for _, schema := range doc.Schemas {
+		for _, action := range schema.Actions {
+			if !action.IsBound || len(action.Parameters) == 0 {
+				continue
+			}
+			for _, set := range setsByType[strings.TrimSpace(action.Parameters[0].Type)] {
+				caps.Actions[set] = append(caps.Actions[set], action.Name)
+			}
+		}
+	}
+
+	// Deprecated revisions are annotated inline or with a Target of "{type}" or "{type}/{field}"
+	addDeprecated := func(typeName, field string, annotations []edmAnnotation) {
+		description, ok := deprecation(annotations)
+		if !ok {
+			return
+		}
+		for _, set := range setsByType[typeName] {
+			caps.Deprecated = append(caps.Deprecated, Warning{Kind: WarningMetadata, Message: description, EntitySetName: set, Field: field})
+		}
+	}
+	for _, schema := range doc.Schemas {
+		for _, et := range schema.EntityTypes {
+			typeName := schema.Namespace + "." + et.Name
+			addDeprecated(typeName, "", et.Annotations)
+			for _, p := range et.Properties {
+				addDeprecated(typeName, p.Name, p.Annotations)
+			}
+		}
+		for _, a := range schema.Annotations {
+			typeName, field, _ := strings.Cut(a.Target, "/")
+			addDeprecated(typeName, field, a.Annotations)
+		}
+	}
+	return caps, nil
+}
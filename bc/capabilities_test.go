@@ -0,0 +1,123 @@
+package bc_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/erlorenz/bc-go/internal/bctest"
+)
+
+const metadataXML = `<?xml version="1.0" encoding="utf-8"?>
+<edmx:Edmx Version="4.0" xmlns:edmx="http://docs.oasis-open.org/odata/ns/edmx">
+  <edmx:DataServices>
+    <Schema Namespace="Microsoft.NAV" xmlns="http://docs.oasis-open.org/odata/ns/edm">
+      <EntityType Name="customer">
+        <Key><PropertyRef Name="id" /></Key>
+        <Property Name="id" Type="Edm.Guid" Nullable="false" />
+        <Property Name="number" Type="Edm.String" MaxLength="20" />
+        <Property Name="balance" Type="Edm.Decimal" Scale="Variable" />
+        <Property Name="paymentTermsId" Type="Edm.Guid" />
+        <NavigationProperty Name="paymentTerm" Type="Microsoft.NAV.paymentTerm">
+          <ReferentialConstraint Property="paymentTermsId" ReferencedProperty="id" />
+        </NavigationProperty>
+      </EntityType>
+      <EntityType Name="salesInvoice">
+        <Property Name="id" Type="Edm.Guid" Nullable="false" />
+        <NavigationProperty Name="salesInvoiceLines" Type="Collection(Microsoft.NAV.salesInvoiceLine)" />
+      </EntityType>
+      <Action Name="post" IsBound="true">
+        <Parameter Name="bindingParameter" Type="Microsoft.NAV.salesInvoice" />
+      </Action>
+      <Action Name="unbound" />
+      <EntityContainer Name="default">
+        <EntitySet Name="customers" EntityType="Microsoft.NAV.customer" />
+        <EntitySet Name="salesInvoices" EntityType="Microsoft.NAV.salesInvoice">
+          <NavigationPropertyBinding Path="salesInvoiceLines" Target="salesInvoiceLines" />
+        </EntitySet>
+      </EntityContainer>
+    </Schema>
+  </edmx:DataServices>
+</edmx:Edmx>`
+
+func TestCapabilities(t *testing.T) {
+	res := &http.Response{StatusCode: 200, Body: io.NopCloser(strings.NewReader(metadataXML)), Header: http.Header{"Content-Type": {"application/xml"}}}
+	st := &bctest.SequenceTransport{Responses: []*http.Response{res}}
+	client := newSequenceClient(t, st)
+
+	caps, err := client.Capabilities(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := st.Requests[0]
+	if !strings.HasSuffix(req.URL.Path, "/api/publisher/group/1.0/$metadata") || req.Header.Get("Accept") != "application/xml" {
+		t.Errorf("unexpected request %s with Accept %s", req.URL.Path, req.Header.Get("Accept"))
+	}
+
+	table := []struct {
+		name string
+		got  bool
+		want bool
+	}{
+		{"HasEntitySet", caps.HasEntitySet("customers"), true},
+		{"MissingEntitySet", caps.HasEntitySet("vendors"), false},
+		{"HasField", caps.HasField("customers", "number"), true},
+		{"HasNavigationField", caps.HasField("customers", "paymentTerm"), true},
+		{"MissingField", caps.HasField("customers", "email"), false},
+		{"HasAction", caps.HasAction("salesInvoices", "post"), true},
+		{"MissingAction", caps.HasAction("customers", "post"), false},
+		{"APIVersion", caps.APIVersion == "1.0", true},
+	}
+	if p, ok := caps.Property("customers", "id"); !ok || p.Type != "Edm.Guid" || p.Nullable {
+		t.Errorf("unexpected id property %+v", p)
+	}
+	if p, _ := caps.Property("customers", "number"); p.MaxLength != 20 || !p.Nullable {
+		t.Errorf("unexpected number property %+v", p)
+	}
+	if p, _ := caps.Property("customers", "balance"); p.Type != "Edm.Decimal" || p.Scale != -1 {
+		t.Errorf("unexpected balance property %+v", p)
+	}
+	if _, ok := caps.Property("customers", "paymentTerm"); ok {
+		t.Error("wanted no property for a navigation property")
+	}
+	if p, ok := caps.NavigationProperty("customers", "paymentTerm"); !ok || p.Collection || p.EntitySetName != "" || p.Constraints["paymentTermsId"] != "id" {
+		t.Errorf("unexpected paymentTerm navigation property %+v", p)
+	}
+	if p, _ := caps.NavigationProperty("salesInvoices", "salesInvoiceLines"); !p.Collection || p.EntitySetName != "salesInvoiceLines" {
+		t.Errorf("unexpected salesInvoiceLines navigation property %+v", p)
+	}
+	for _, v := range table {
+		t.Run(v.name, func(t *testing.T) {
+			if v.got != v.want {
+				t.Errorf("wanted %t, got %t", v.want, v.got)
+			}
+		})
+	}
+}
+
+func TestCapabilitiesError(t *testing.T) {
+	st := &bctest.SequenceTransport{Responses: []*http.Response{errorResponse(401, "Authentication_InvalidCredentials")}}
+	client := newSequenceClient(t, st)
+
+	if _, err := client.Capabilities(context.Background()); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestParseCapabilities(t *testing.T) {
+	caps, err := bc.ParseCapabilities(strings.NewReader(metadataXML))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !caps.HasField("customers", "paymentTerm") || !caps.HasAction("salesInvoices", "post") || caps.APIVersion != "" {
+		t.Errorf("unexpected capabilities %+v", caps)
+	}
+
+	if _, err := bc.ParseCapabilities(strings.NewReader("<edmx:Edmx")); err == nil {
+		t.Error("wanted error for a truncated document")
+	}
+}
@@ -0,0 +1,152 @@
+package bc
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/AzureAD/microsoft-authentication-library-for-go/apps/confidential"
+	"github.com/google/uuid"
+)
+
+// CertificateLoader returns the certificate chain, leaf first, and its RSA private key.
+type CertificateLoader func(ctx context.Context) ([]*x509.Certificate, crypto.PrivateKey, error)
+
+// PEMFileLoader loads a certificate and key from PEM files. They may be the same file.
+// The password is only needed for an encrypted key.
+func PEMFileLoader(certFile, keyFile, password string) CertificateLoader {
+	return func(context.Context) ([]*x509.Certificate, crypto.PrivateKey, error) {
+		certPEM, err := os.ReadFile(certFile)
+		if err != nil {
+			return nil, nil, fmt.Errorf("read certificate: %w", err)
+		}
+		data := certPEM
+		if keyFile != certFile {
+			keyPEM, err := os.ReadFile(keyFile)
+			if err != nil {
+				return nil, nil, fmt.Errorf("read key: %w", err)
+			}
+			data = slices.Concat(certPEM, []byte("\n"), keyPEM)
+		}
+		return confidential.CertFromPEM(data, password)
+	}
+}
+
+// CertificateCredential signs client assertions with an X.509 certificate, for tenants
+// that do not allow client secrets. Call [CertificateCredential.Reload] when the certificate
+// is rotated, e.g. from a file watcher or on SIGHUP. Tokens already issued stay valid.
+type CertificateCredential struct {
+	load CertificateLoader
+
+	mu    sync.RWMutex
+	certs []*x509.Certificate
+	key   *rsa.PrivateKey
+}
+
+// NewCertificateCredential loads the certificate with the loader.
+func NewCertificateCredential(ctx context.Context, load CertificateLoader) (*CertificateCredential, error) {
+	cc := &CertificateCredential{load: load}
+	if err := cc.Reload(ctx); err != nil {
+		return nil, err
+	}
+	return cc, nil
+}
+
+// Reload loads the certificate again. The previous certificate is kept if it fails.
+func (cc *CertificateCredential) Reload(ctx context.Context) error {
+	certs, key, err := cc.load(ctx)
+	if err != nil {
+		return fmt.Errorf("load certificate: %w", err)
+	}
+	if len(certs) == 0 {
+		return errors.New("load certificate: no certificate found")
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return fmt.Errorf("load certificate: key must be RSA, got %T", key)
+	}
+	if !rsaKey.PublicKey.Equal(certs[0].PublicKey) {
+		return errors.New("load certificate: key does not match certificate")
+	}
+
+	cc.mu.Lock()
+	cc.certs, cc.key = certs, rsaKey
+	cc.mu.Unlock()
+	return nil
+}
+
+// Certificate returns the current leaf certificate.
+func (cc *CertificateCredential) Certificate() *x509.Certificate {
+	cc.mu.RLock()
+	defer cc.mu.RUnlock()
+	return cc.certs[0]
+}
+
+// Assertion returns a signed JWT client assertion for the token endpoint, valid for 10 minutes.
+// The header has the x5t thumbprint and the x5c chain so Entra ID can match a rotated certificate.
+func (cc *CertificateCredential) Assertion(clientID, tokenEndpoint string) (string, error) {
+	cc.mu.RLock()
+	certs, key := cc.certs, cc.key
+	cc.mu.RUnlock()
+
+	thumbprint := sha1.Sum(certs[0].Raw)
+	chain := make([]string, len(certs))
+	for i, c := range certs {
+		chain[i] = base64.StdEncoding.EncodeToString(c.Raw)
+	}
+
+	now := time.Now()
+	header := map[string]any{
+		"alg": "RS256",
+		"typ": "JWT",
+		"x5t": base64.RawURLEncoding.EncodeToString(thumbprint[:]),
+		"x5c": chain,
+	}
+	claims := map[string]any{
+		"aud": tokenEndpoint,
+		"iss": clientID,
+		"sub": clientID,
+		"jti": uuid.NewString(),
+		"nbf": now.Unix(),
+		"iat": now.Unix(),
+		"exp": now.Add(10 * time.Minute).Unix(),
+	}
+
+	h, err := json.Marshal(header)
+	if err != nil {
+		return "", err
+	}
+	c, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	unsigned := base64.RawURLEncoding.EncodeToString(h) + "." + base64.RawURLEncoding.EncodeToString(c)
+	digest := sha256.Sum256([]byte(unsigned))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("sign assertion: %w", err)
+	}
+
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(sig), nil
+}
+
+// NewCertificateAuth creates an [Auth] that authenticates with the certificate instead of a secret.
+func NewCertificateAuth(tenantID, clientID string, cc *CertificateCredential, opts ...AuthOption) (*Auth, error) {
+	cred := confidential.NewCredFromAssertionCallback(func(_ context.Context, o confidential.AssertionRequestOptions) (string, error) {
+		return cc.Assertion(o.ClientID, o.TokenEndpoint)
+	})
+	return newAuth(tenantID, clientID, cred, opts...)
+}
@@ -0,0 +1,118 @@
+package bc_test
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/erlorenz/bc-go/bc"
+)
+
+// writeCertificate writes a self-signed certificate and key to PEM files.
+func writeCertificate(t *testing.T, dir string) (string, string, *rsa.PrivateKey) {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: "bc-go test"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600)
+	os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: mustPKCS8(t, key)}), 0o600)
+	return certFile, keyFile, key
+}
+
+func mustPKCS8(t *testing.T, key *rsa.PrivateKey) []byte {
+	t.Helper()
+	b, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestCertificateCredential(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	certFile, keyFile, key := writeCertificate(t, dir)
+
+	cc, err := bc.NewCertificateCredential(ctx, bc.PEMFileLoader(certFile, keyFile, ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Assertion", func(t *testing.T) {
+		jwt, err := cc.Assertion(validGUID, "https://login.microsoftonline.com/token")
+		if err != nil {
+			t.Fatal(err)
+		}
+		parts := strings.Split(jwt, ".")
+		if len(parts) != 3 {
+			t.Fatalf("wanted 3 parts, got %d", len(parts))
+		}
+
+		sig, _ := base64.RawURLEncoding.DecodeString(parts[2])
+		digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+		if err := rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, digest[:], sig); err != nil {
+			t.Errorf("invalid signature: %s", err)
+		}
+
+		var claims map[string]any
+		b, _ := base64.RawURLEncoding.DecodeString(parts[1])
+		json.Unmarshal(b, &claims)
+		if claims["iss"] != validGUID || claims["aud"] != "https://login.microsoftonline.com/token" {
+			t.Errorf("unexpected claims %v", claims)
+		}
+	})
+
+	t.Run("Reload", func(t *testing.T) {
+		before := cc.Certificate().SerialNumber
+		writeCertificate(t, dir)
+		if err := cc.Reload(ctx); err != nil {
+			t.Fatal(err)
+		}
+		if cc.Certificate().SerialNumber.Cmp(before) == 0 {
+			t.Error("expected a new certificate after reload")
+		}
+	})
+
+	t.Run("MismatchedKey", func(t *testing.T) {
+		otherCert, _, _ := writeCertificate(t, t.TempDir())
+		_, err := bc.NewCertificateCredential(ctx, bc.PEMFileLoader(otherCert, keyFile, ""))
+		if err == nil {
+			t.Error("expected error")
+		}
+	})
+
+	t.Run("NewClient", func(t *testing.T) {
+		config := fakeConfig
+		config.ClientSecret = ""
+		if _, err := bc.NewClient(config, bc.WithCertificate(cc)); err != nil {
+			t.Errorf("expected no error without secret, got %s", err)
+		}
+	})
+}
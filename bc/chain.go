@@ -0,0 +1,209 @@
+package bc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/AzureAD/microsoft-authentication-library-for-go/apps/public"
+)
+
+// ChainedTokenGetter tries each [TokenGetter] in order until one returns a token,
+// then keeps using that one. Implements the TokenGetter interface.
+type ChainedTokenGetter struct {
+	providers []TokenGetter
+	logger    *slog.Logger
+
+	mu       sync.Mutex
+	selected TokenGetter
+}
+
+// NewChainedTokenGetter creates a [ChainedTokenGetter] from the providers, tried in order.
+func NewChainedTokenGetter(providers ...TokenGetter) *ChainedTokenGetter {
+	return &ChainedTokenGetter{providers: providers, logger: slog.Default()}
+}
+
+// GetToken returns a token from the provider that succeeded before, or tries each
+// provider in order. The error joins the errors of every provider.
+func (ct *ChainedTokenGetter) GetToken(ctx context.Context) (AccessToken, error) {
+	ct.mu.Lock()
+	selected := ct.selected
+	ct.mu.Unlock()
+
+	if selected != nil {
+		return selected.GetToken(ctx)
+	}
+
+	var errs []error
+	for i, p := range ct.providers {
+		token, err := p.GetToken(ctx)
+		if err != nil {
+			ct.logger.Debug("Token provider failed, trying next.", "provider", fmt.Sprintf("%T", p), "error", err)
+			errs = append(errs, fmt.Errorf("%d %T: %w", i, p, err))
+			continue
+		}
+
+		ct.mu.Lock()
+		ct.selected = p
+		ct.mu.Unlock()
+		return token, nil
+	}
+
+	return "", fmt.Errorf("no token provider succeeded: %w", errors.Join(errs...))
+}
+
+// Selected returns the provider that succeeded, or nil if none has yet.
+func (ct *ChainedTokenGetter) Selected() TokenGetter {
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
+	return ct.selected
+}
+
+// unavailableTokenGetter is a placeholder in a chain for a provider that could not be created.
+type unavailableTokenGetter struct {
+	err error
+}
+
+func (u unavailableTokenGetter) GetToken(context.Context) (AccessToken, error) {
+	return "", u.err
+}
+
+// NewEnvironmentAuth creates an [Auth] from the environment variables used by the Azure SDKs:
+// AZURE_TENANT_ID, AZURE_CLIENT_ID and either AZURE_CLIENT_SECRET or
+// AZURE_CLIENT_CERTIFICATE_PATH with an optional AZURE_CLIENT_CERTIFICATE_PASSWORD.
+func NewEnvironmentAuth(opts ...AuthOption) (*Auth, error) {
+	tenantID, clientID := os.Getenv("AZURE_TENANT_ID"), os.Getenv("AZURE_CLIENT_ID")
+	if tenantID == "" || clientID == "" {
+		return nil, errors.New("environment auth: AZURE_TENANT_ID and AZURE_CLIENT_ID must be set")
+	}
+
+	if secret := os.Getenv("AZURE_CLIENT_SECRET"); secret != "" {
+		return NewAuth(tenantID, clientID, secret, opts...)
+	}
+
+	if path := os.Getenv("AZURE_CLIENT_CERTIFICATE_PATH"); path != "" {
+		cc, err := NewCertificateCredential(context.Background(), PEMFileLoader(path, path, os.Getenv("AZURE_CLIENT_CERTIFICATE_PASSWORD")))
+		if err != nil {
+			return nil, fmt.Errorf("environment auth: %w", err)
+		}
+		return NewCertificateAuth(tenantID, clientID, cc, opts...)
+	}
+
+	return nil, errors.New("environment auth: AZURE_CLIENT_SECRET or AZURE_CLIENT_CERTIFICATE_PATH must be set")
+}
+
+// InteractiveAuth signs in a user with the browser, or with a device code if
+// DeviceCodePrompt is set, and then refreshes the token silently.
+// Implements the TokenGetter interface.
+type InteractiveAuth struct {
+	client public.Client
+	scopes []string
+	prompt func(message string)
+	logger *slog.Logger
+}
+
+// NewInteractiveAuth creates an [InteractiveAuth] for a public client application.
+// For a device code, deviceCodePrompt is called with the message to show the user.
+// Pass nil to use the browser.
+func NewInteractiveAuth(tenantID, clientID string, deviceCodePrompt func(message string), opts ...AuthOption) (*InteractiveAuth, error) {
+	settings := newAuthSettings(tenantID, opts)
+
+	clientOpts := []public.Option{public.WithAuthority(settings.authority())}
+	if settings.httpClient != nil {
+		clientOpts = append(clientOpts, public.WithHTTPClient(settings.httpClient))
+	}
+
+	client, err := public.New(clientID, clientOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("interactive auth: %w", err)
+	}
+	return &InteractiveAuth{
+		client: client,
+		scopes: []string{settings.scope},
+		prompt: deviceCodePrompt,
+		logger: slog.Default(),
+	}, nil
+}
+
+func (ia *InteractiveAuth) GetToken(ctx context.Context) (AccessToken, error) {
+	accounts, err := ia.client.Accounts(ctx)
+	if err == nil && len(accounts) > 0 {
+		result, err := ia.client.AcquireTokenSilent(ctx, ia.scopes, public.WithSilentAccount(accounts[0]))
+		if err == nil {
+			return AccessToken(result.AccessToken), nil
+		}
+	}
+
+	if ia.prompt == nil {
+		ia.logger.Debug("Opening browser to sign in...")
+		result, err := ia.client.AcquireTokenInteractive(ctx, ia.scopes)
+		if err != nil {
+			return "", fmt.Errorf("error getting access token: %w", err)
+		}
+		return AccessToken(result.AccessToken), nil
+	}
+
+	dc, err := ia.client.AcquireTokenByDeviceCode(ctx, ia.scopes)
+	if err != nil {
+		return "", fmt.Errorf("error getting device code: %w", err)
+	}
+	ia.prompt(dc.Result.Message)
+
+	result, err := dc.AuthenticationResult(ctx)
+	if err != nil {
+		return "", fmt.Errorf("error getting access token: %w", err)
+	}
+	return AccessToken(result.AccessToken), nil
+}
+
+// DefaultTokenGetterOptions configure [NewDefaultTokenGetter].
+type DefaultTokenGetterOptions struct {
+	// Interactive adds an [InteractiveAuth] as the last provider, for local development.
+	Interactive bool
+	// DeviceCodePrompt is passed to [NewInteractiveAuth].
+	DeviceCodePrompt func(message string)
+	// AuthOptions are used for every provider in the chain, e.g. [WithAuthorityHost].
+	AuthOptions []AuthOption
+}
+
+// NewDefaultTokenGetter creates a chain of managed identity, environment variables,
+// the ClientSecret of the config if set, and optionally interactive sign in,
+// like DefaultAzureCredential in the Azure SDK. Providers that cannot be created
+// are kept in the chain and return the reason they are unavailable.
+func NewDefaultTokenGetter(config ClientConfig, opts DefaultTokenGetterOptions) *ChainedTokenGetter {
+	var providers []TokenGetter
+
+	mi, err := NewManagedIdentityAuth(ManagedIdentityOptions{
+		ClientID:    os.Getenv("AZURE_CLIENT_ID"),
+		HTTPClient:  &http.Client{Timeout: time.Second},
+		AuthOptions: opts.AuthOptions,
+	})
+	providers = append(providers, tokenGetterOrUnavailable(mi, err))
+
+	env, err := NewEnvironmentAuth(opts.AuthOptions...)
+	providers = append(providers, tokenGetterOrUnavailable(env, err))
+
+	if config.ClientSecret != "" {
+		secret, err := NewAuth(config.TenantID, config.ClientID, config.ClientSecret, opts.AuthOptions...)
+		providers = append(providers, tokenGetterOrUnavailable(secret, err))
+	}
+
+	if opts.Interactive {
+		ia, err := NewInteractiveAuth(config.TenantID, config.ClientID, opts.DeviceCodePrompt, opts.AuthOptions...)
+		providers = append(providers, tokenGetterOrUnavailable(ia, err))
+	}
+
+	return NewChainedTokenGetter(providers...)
+}
+
+func tokenGetterOrUnavailable[T TokenGetter](tg T, err error) TokenGetter {
+	if err != nil {
+		return unavailableTokenGetter{err: err}
+	}
+	return tg
+}
@@ -0,0 +1,71 @@
+package bc_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/erlorenz/bc-go/bc"
+)
+
+type countingTokenGetter struct {
+	token bc.AccessToken
+	err   error
+	calls int
+}
+
+func (c *countingTokenGetter) GetToken(context.Context) (bc.AccessToken, error) {
+	c.calls++
+	return c.token, c.err
+}
+
+func TestChainedTokenGetter(t *testing.T) {
+	failing := &countingTokenGetter{err: errors.New("unavailable")}
+	working := &countingTokenGetter{token: "TOKEN"}
+	last := &countingTokenGetter{token: "LAST"}
+
+	chain := bc.NewChainedTokenGetter(failing, working, last)
+
+	for range 2 {
+		token, err := chain.GetToken(context.Background())
+		if err != nil || token != "TOKEN" {
+			t.Fatalf("wanted TOKEN, got %s %v", token, err)
+		}
+	}
+
+	if failing.calls != 1 || working.calls != 2 || last.calls != 0 {
+		t.Errorf("wanted selected provider reused, got calls %d %d %d", failing.calls, working.calls, last.calls)
+	}
+	if chain.Selected() != working {
+		t.Errorf("wanted working provider selected, got %T", chain.Selected())
+	}
+}
+
+func TestChainedTokenGetterAllFail(t *testing.T) {
+	errA, errB := errors.New("a"), errors.New("b")
+	chain := bc.NewChainedTokenGetter(&countingTokenGetter{err: errA}, &countingTokenGetter{err: errB})
+
+	_, err := chain.GetToken(context.Background())
+	if !errors.Is(err, errA) || !errors.Is(err, errB) {
+		t.Errorf("wanted both errors, got %v", err)
+	}
+	if chain.Selected() != nil {
+		t.Error("wanted no provider selected")
+	}
+}
+
+func TestNewEnvironmentAuth(t *testing.T) {
+	t.Setenv("AZURE_TENANT_ID", validGUID)
+	t.Setenv("AZURE_CLIENT_ID", validGUID)
+	t.Setenv("AZURE_CLIENT_SECRET", "")
+	t.Setenv("AZURE_CLIENT_CERTIFICATE_PATH", "")
+
+	if _, err := bc.NewEnvironmentAuth(); err == nil {
+		t.Error("expected error without a secret or certificate")
+	}
+
+	t.Setenv("AZURE_CLIENT_SECRET", "SECRET")
+	if _, err := bc.NewEnvironmentAuth(); err != nil {
+		t.Errorf("expected no error, got %s", err)
+	}
+}
@@ -12,8 +12,6 @@ import (
 	"github.com/google/uuid"
 )
 
-var Version = "0.14.0"
-
 // Client is used to send and receive HTTP requests/responses to the
 // API server. There should be one client created per publisher/group/version
 // combination as these can each have their own schemas. Clients can
@@ -22,8 +20,38 @@ type Client struct {
 	authClient TokenGetter
 	baseClient *http.Client
 	baseURL    *url.URL
+	urls       *urlCache
 	config     ClientConfig
 	logger     *slog.Logger
+
+	strictDecoding     bool
+	responseValidators map[string][]ResponseValidator
+	requestValidators  map[string][]RequestValidator
+	retryClassifier    RetryClassifier
+	middleware         []Middleware
+	dryRun             bool
+	readOnly           bool
+	codec              Codec
+	authOptions        []AuthOption
+	certificate        *CertificateCredential
+	signer             Signer
+	proxy              *ProxyOptions
+	warningHandler     WarningHandler
+	warnings           *warnings
+	maxURLLength       int
+	rateLimiter        *RateLimiter
+	concurrencyLimiter *ConcurrencyLimiter
+	tiebreaker         string
+	traceEnabled       bool
+	timingHandler      TimingHandler
+	slowRequests       *SlowRequestOptions
+	errorReporter      ErrorReporter
+	clock              Clock
+	userAgent          string
+	telemetryHeader    http.Header
+	requestIDs         RequestIDFunc
+	maintenance        *MaintenanceSchedule
+	rebaser            *rebaser
 }
 
 // The required configuration options for the Client.
@@ -41,11 +69,34 @@ type ClientConfig struct {
 	// ClientID is also known as the application ID.
 	ClientID string
 	//ClientSecret is the MSAL client secret for the application.
+	// It can be empty with [WithCertificate] or [WithAuthClient].
 	ClientSecret string
+	// APIHost is the host of the API, for clouds that host it on a different domain.
+	// Defaults to "api.businesscentral.dynamics.com".
+	APIHost string
+}
+
+// SetBaseURL sets the APIHost, TenantID and Environment from a root URL of
+// [ResolveBaseURL], so a config can be built from a tenant and an environment name.
+func (cc *ClientConfig) SetBaseURL(baseURL *url.URL) error {
+	segments := strings.Split(strings.TrimPrefix(baseURL.Path, "/"), "/")
+	if baseURL.Scheme != "https" || baseURL.Host == "" || len(segments) < 3 || segments[0] != "v2.0" {
+		return fmt.Errorf("set base url: invalid url %q", baseURL)
+	}
+	if _, err := uuid.Parse(segments[1]); err != nil {
+		return fmt.Errorf("set base url: invalid tenant in %q", baseURL)
+	}
+	cc.APIHost, cc.TenantID, cc.Environment = baseURL.Host, segments[1], segments[2]
+	return nil
 }
 
 // Validates that the params are all in correct format.
 func (cc ClientConfig) Validate() error {
+	return cc.validate(true)
+}
+
+// validate only checks the ClientSecret if requireSecret is true.
+func (cc ClientConfig) validate(requireSecret bool) error {
 	var errs []string
 
 	if _, err := uuid.Parse(cc.TenantID); err != nil {
@@ -64,7 +115,7 @@ func (cc ClientConfig) Validate() error {
 		errs = append(errs, fmt.Sprintf("ClientID: %s", err))
 	}
 
-	if err := stringNotEmpty(cc.ClientSecret); err != nil {
+	if err := stringNotEmpty(cc.ClientSecret); err != nil && requireSecret {
 		errs = append(errs, fmt.Sprintf("ClientSecret: %s", err))
 	}
 
@@ -89,11 +140,20 @@ func (cc ClientConfig) Validate() error {
 }
 
 // NewClient creates a [Client] with configuration params and optional configuration with functional options.
-// Available options are [WithAuthClient], [WithLogger], [WithHTTPClient].
+// Available options are the [ClientOption] functions, e.g. [WithAuthClient], [WithLogger], [WithHTTPClient].
 func NewClient(config ClientConfig, opts ...ClientOption) (*Client, error) {
 
-	// Validate params
-	if err := config.Validate(); err != nil {
+	client := &Client{
+		config: config,
+	}
+
+	// Apply the optional functions to the client
+	for _, opt := range opts {
+		opt(client)
+	}
+
+	// Validate params, the secret is not needed with another credential
+	if err := config.validate(client.authClient == nil && client.certificate == nil); err != nil {
 		return nil, fmt.Errorf("validate config: \n%w", err)
 	}
 
@@ -102,19 +162,26 @@ func NewClient(config ClientConfig, opts ...ClientOption) (*Client, error) {
 	if err != nil {
 		return nil, err
 	}
+	client.baseURL = baseURL
+	client.urls = &urlCache{}
 
-	client := &Client{
-		baseURL: baseURL,
-		config:  config,
-	}
-
-	// Apply the optional functions to the client
-	for _, opt := range opts {
-		opt(client)
+	client.baseClient = cmp.Or(client.baseClient, &http.Client{Timeout: 20 * time.Second})
+	if client.proxy != nil {
+		client.baseClient, err = withProxy(client.baseClient, *client.proxy)
+		if err != nil {
+			return nil, err
+		}
+		// Token requests go through the same proxy
+		client.authOptions = append(client.authOptions, WithAuthHTTPClient(client.baseClient))
 	}
 
 	if client.authClient == nil {
-		ac, err := NewAuth(config.TenantID, config.ClientID, config.ClientSecret)
+		var ac *Auth
+		if client.certificate != nil {
+			ac, err = NewCertificateAuth(config.TenantID, config.ClientID, client.certificate, client.authOptions...)
+		} else {
+			ac, err = NewAuth(config.TenantID, config.ClientID, config.ClientSecret, client.authOptions...)
+		}
 		if err != nil {
 			return nil, err
 		}
@@ -122,8 +189,26 @@ func NewClient(config ClientConfig, opts ...ClientOption) (*Client, error) {
 	}
 
 	client.logger = cmp.Or(client.logger, slog.Default())
-	client.baseClient = cmp.Or(client.baseClient, &http.Client{Timeout: 20 * time.Second})
+	client.userAgent = strings.TrimSpace(defaultUserAgent() + " " + client.userAgent)
+	if client.codec == nil {
+		client.codec = JSONCodec{}
+	}
+	client.warnings = &warnings{client: client}
+	if client.rebaser != nil {
+		client.middleware = append(client.middleware, client.rebaser.middleware)
+	}
+	if client.rateLimiter != nil {
+		client.middleware = append(client.middleware, client.rateLimiter.Middleware())
+	}
+	if client.concurrencyLimiter != nil {
+		client.middleware = append(client.middleware, client.concurrencyLimiter.Middleware())
+	}
+	if client.traceEnabled {
+		client.middleware = append(client.middleware, client.traceMiddleware())
+	}
+	client.baseClient = applyMiddleware(client.baseClient, client.middleware)
 
+	client.logger.Debug("Created client.", "version", Version(), "userAgent", client.userAgent, "baseURL", baseURL.String())
 	return client, nil
 }
 
@@ -143,6 +228,17 @@ func (c *Client) Config() ClientConfig {
 	return c.config
 }
 
+// UserAgent returns the User-Agent header sent with every request: "bcgo/<version>"
+// followed by the product of [WithUserAgent].
+func (c *Client) UserAgent() string {
+	return c.userAgent
+}
+
+// defaultUserAgent identifies the library in the User-Agent header.
+func defaultUserAgent() string {
+	return "bcgo/" + Version()
+}
+
 // BaseClient returns the baseClient [http.Client].
 func (c *Client) BaseClient() *http.Client {
 	return c.baseClient
@@ -152,3 +248,8 @@ func (c *Client) BaseClient() *http.Client {
 func (c *Client) Logger() *slog.Logger {
 	return c.logger
 }
+
+// Clock returns the Clock of [WithClock], or the [SystemClock].
+func (c *Client) Clock() Clock {
+	return clockOrSystem(c.clock)
+}
@@ -2,6 +2,7 @@ package bc_test
 
 import (
 	"context"
+	"io"
 	"log/slog"
 	"net/http"
 	"os"
@@ -9,6 +10,7 @@ import (
 	"time"
 
 	"github.com/erlorenz/bc-go/bc"
+	"github.com/erlorenz/bc-go/internal/bctest"
 	"github.com/google/uuid"
 )
 
@@ -182,3 +184,30 @@ func TestConfig(t *testing.T) {
 	}
 
 }
+
+// newMockClient creates a Client whose HTTP client always returns a response with the
+// status and JSON body.
+func newMockClient(t *testing.T, status int, body any, opts ...bc.ClientOption) *bc.Client {
+	t.Helper()
+
+	res := &http.Response{StatusCode: status, Body: bctest.NewRequestBody(body), Header: http.Header{}}
+	httpClient := &http.Client{Transport: bctest.MockTransport{Response: res}}
+
+	opts = append([]bc.ClientOption{bc.WithAuthClient(fakeTokenGetter{}), bc.WithHTTPClient(httpClient)}, opts...)
+
+	client, err := bc.NewClient(fakeConfig, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+// readBody reads the body of a request sent through a test transport.
+func readBody(t *testing.T, r *http.Request) string {
+	t.Helper()
+	b, err := io.ReadAll(r.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
@@ -1,6 +1,7 @@
 package bc
 
 import (
+	"cmp"
 	"log/slog"
 	"net/http"
 )
@@ -29,3 +30,216 @@ func WithAuthClient(authClient TokenGetter) ClientOption {
 		client.authClient = authClient
 	}
 }
+
+// WithTokenCache sets the [TokenCache] of the default [Auth].
+// It is ignored if [WithAuthClient] is used.
+func WithTokenCache(cache TokenCache) ClientOption {
+	return func(client *Client) {
+		client.authOptions = append(client.authOptions, WithAuthTokenCache(cache))
+	}
+}
+
+// WithAuthOptions passes the [AuthOption] functions to the default [Auth],
+// e.g. [WithAuthorityHost] and [WithScope] for sovereign clouds.
+// They are ignored if [WithAuthClient] is used.
+func WithAuthOptions(opts ...AuthOption) ClientOption {
+	return func(client *Client) {
+		client.authOptions = append(client.authOptions, opts...)
+	}
+}
+
+// WithCertificate makes the default [Auth] use the certificate instead of the ClientSecret,
+// which can then be empty. It is ignored if [WithAuthClient] is used.
+func WithCertificate(cc *CertificateCredential) ClientOption {
+	return func(client *Client) {
+		client.certificate = cc
+	}
+}
+
+// WithStrictDecoding makes [APIPage] and [APIQuery] decode responses with [DecodeStrict],
+// so fields returned by BC that are missing from the model cause an [UnknownFieldsError].
+// This is primarily for testing.
+func WithStrictDecoding() ClientOption {
+	return func(client *Client) {
+		client.strictDecoding = true
+	}
+}
+
+// WithResponseValidator registers a [ResponseValidator] that runs on every record decoded
+// by an [APIPage] or [APIQuery] for the entity set. Failures return a [ValidationError].
+// Multiple validators can be registered for the same entity set.
+func WithResponseValidator(entitySetName string, fn ResponseValidator) ClientOption {
+	return func(client *Client) {
+		if client.responseValidators == nil {
+			client.responseValidators = map[string][]ResponseValidator{}
+		}
+		client.responseValidators[entitySetName] = append(client.responseValidators[entitySetName], fn)
+	}
+}
+
+// WithRequestValidator registers a [RequestValidator] that runs in [Client.NewRequest] and
+// [Client.Batch] for requests to the entity set, or to every entity set if the name is empty.
+// Failures are returned before anything is sent.
+func WithRequestValidator(entitySetName string, fn RequestValidator) ClientOption {
+	return func(client *Client) {
+		if client.requestValidators == nil {
+			client.requestValidators = map[string][]RequestValidator{}
+		}
+		client.requestValidators[entitySetName] = append(client.requestValidators[entitySetName], fn)
+	}
+}
+
+// WithRetryClassifier enables retries in [Client.Do] using the [RetryClassifier],
+// e.g. a [DefaultRetryClassifier]. Requests are not retried by default.
+func WithRetryClassifier(rc RetryClassifier) ClientOption {
+	return func(client *Client) {
+		client.retryClassifier = rc
+	}
+}
+
+// WithMiddleware wraps the transport of the http.Client with each [Middleware].
+// The first middleware is the outermost. The http.Client set by [WithHTTPClient] is not modified.
+func WithMiddleware(middleware ...Middleware) ClientOption {
+	return func(client *Client) {
+		client.middleware = append(client.middleware, middleware...)
+	}
+}
+
+// WithSigner calls the [Signer] at the end of [Client.NewRequest], after all headers are set,
+// so it can sign the final request. Retries send the same signed request.
+func WithSigner(signer Signer) ClientOption {
+	return func(client *Client) {
+		client.signer = signer
+	}
+}
+
+// WithProxy sends all requests, including token requests of the default [Auth], through the proxy.
+// The transport of the http.Client set by [WithHTTPClient] must be an *http.Transport; it is copied, not modified.
+func WithProxy(opts ProxyOptions) ClientOption {
+	return func(client *Client) {
+		client.proxy = &opts
+	}
+}
+
+// WithWarningHandler calls the [WarningHandler] for each deprecation [Warning] in a response
+// or the $metadata. Warnings are logged even without a handler.
+func WithWarningHandler(handler WarningHandler) ClientOption {
+	return func(client *Client) {
+		client.warningHandler = handler
+	}
+}
+
+// WithMaxURLLength changes the [DefaultMaxURLLength]. Use -1 for no limit.
+func WithMaxURLLength(n int) ClientOption {
+	return func(client *Client) {
+		client.maxURLLength = n
+	}
+}
+
+// WithRateLimiter makes every request, including retries, wait for the [RateLimiter]
+// with the [Priority] of its context. The limiter is inside the [WithMiddleware]
+// middleware and the rebaser of [WithRebase], and outside the [ConcurrencyLimiter]
+// and the trace of [WithHTTPTrace].
+func WithRateLimiter(rl *RateLimiter) ClientOption {
+	return func(client *Client) {
+		client.rateLimiter = rl
+	}
+}
+
+// WithConcurrencyLimiter limits the requests in flight, including retries, with the
+// [ConcurrencyLimiter]. It is inside the [RateLimiter], so waiting for the rate does not hold a slot.
+func WithConcurrencyLimiter(cl *ConcurrencyLimiter) ClientOption {
+	return func(client *Client) {
+		client.concurrencyLimiter = cl
+	}
+}
+
+// WithStableOrdering appends the tiebreaker field, e.g. "id", to the $orderby of every list request
+// that does not already order by it, so BC pages in a deterministic order and does not
+// skip or duplicate rows. The default tiebreaker is "id".
+func WithStableOrdering(tiebreaker string) ClientOption {
+	return func(client *Client) {
+		client.tiebreaker = cmp.Or(tiebreaker, "id")
+	}
+}
+
+// WithCodec replaces encoding/json for request and response bodies with the [Codec].
+func WithCodec(codec Codec) ClientOption {
+	return func(client *Client) {
+		client.codec = codec
+	}
+}
+
+// WithHTTPTrace measures the connection-level [Timings] of every request, including
+// retries. They are logged at debug level, recorded in the [Diagnostics] of a context
+// from [WithDiagnostics] and passed to the handler, which can be nil. The trace is the
+// innermost middleware, so the wait for a [RateLimiter] is not included.
+func WithHTTPTrace(handler TimingHandler) ClientOption {
+	return func(client *Client) {
+		client.traceEnabled = true
+		client.timingHandler = handler
+	}
+}
+
+// WithSlowRequestHook reports every request attempt, including retries, that is slower
+// than the threshold as a [SlowRequest] with its [Timings]. It enables the trace of
+// [WithHTTPTrace].
+func WithSlowRequestHook(opts SlowRequestOptions) ClientOption {
+	return func(client *Client) {
+		if opts.Threshold <= 0 {
+			opts.Threshold = DefaultSlowThreshold
+		}
+		if opts.MaxBodySize == 0 {
+			opts.MaxBodySize = defaultSlowBodySize
+		}
+		client.traceEnabled = true
+		client.slowRequests = &opts
+	}
+}
+
+// WithErrorReporter reports every request that fails after any retries to the
+// [ErrorReporter], so failures reach alerting without every caller wrapping errors.
+func WithErrorReporter(reporter ErrorReporter) ClientOption {
+	return func(client *Client) {
+		client.errorReporter = reporter
+	}
+}
+
+// WithClock replaces the [SystemClock] for the retry backoff and the token expiry of the
+// default [Auth], so tests can advance time instead of sleeping.
+func WithClock(clock Clock) ClientOption {
+	return func(client *Client) {
+		client.clock = clock
+		client.authOptions = append(client.authOptions, WithAuthClock(clock))
+	}
+}
+
+// WithUserAgent appends the product, e.g. "invoice-sync/1.2.0", to the "bcgo/<version>"
+// User-Agent header, so the traffic of the application can be told apart in BC
+// telemetry and support cases.
+func WithUserAgent(product string) ClientOption {
+	return func(client *Client) {
+		client.userAgent = product
+	}
+}
+
+// WithTelemetryHeader sends the header with every request, e.g. a partner or
+// application ID that is requested for support cases. Headers of the [RequestOptions]
+// override it.
+func WithTelemetryHeader(name, value string) ClientOption {
+	return func(client *Client) {
+		if client.telemetryHeader == nil {
+			client.telemetryHeader = http.Header{}
+		}
+		client.telemetryHeader.Set(name, value)
+	}
+}
+
+// WithRequestIDs sends the id of the [RequestIDFunc] as the [HeaderClientRequestID] of
+// every request, e.g. [RandomRequestIDs]. Retries send the same id. Without it BC
+// generates the id, unless the context has one from [WithRequestID].
+func WithRequestIDs(fn RequestIDFunc) ClientOption {
+	return func(client *Client) {
+		client.requestIDs = fn
+	}
+}
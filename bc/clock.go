@@ -0,0 +1,30 @@
+package bc
+
+import "time"
+
+// Clock tells the time and waits. The resilience features use it for retry backoff,
+// token expiry and cache TTLs, so tests can replace the [SystemClock] with a fake
+// that advances synthetically instead of sleeping, e.g. a bcmock.Clock.
+type Clock interface {
+	Now() time.Time
+	// After sends the time on the channel once the duration has passed.
+	After(d time.Duration) <-chan time.Time
+}
+
+// SystemClock is the [Clock] of the time package. It is the default everywhere.
+var SystemClock Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+func (systemClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// clockOrSystem returns the clock, or the [SystemClock] if it is nil, so a Clock
+// field of an options struct can be left empty.
+func clockOrSystem(c Clock) Clock {
+	if c == nil {
+		return SystemClock
+	}
+	return c
+}
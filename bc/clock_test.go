@@ -0,0 +1,87 @@
+package bc_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/erlorenz/bc-go/bcmock"
+	"github.com/erlorenz/bc-go/internal/bctest"
+	"github.com/google/uuid"
+)
+
+func TestClockRetryBackoff(t *testing.T) {
+	st := &bctest.SequenceTransport{Responses: []*http.Response{
+		errorResponse(http.StatusServiceUnavailable, "ServiceUnavailable"),
+		bctest.NewResponse(http.StatusOK, map[string]any{"ID": validGUID}),
+	}}
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := bcmock.NewClock(start)
+	rc := bc.DefaultRetryClassifier{BaseDelay: time.Hour, MaxDelay: time.Hour}
+	client := newSequenceClient(t, st, bc.WithClock(clock), bc.WithRetryClassifier(rc))
+	page := bc.NewAPIPage[fakeEntity](client, "fakeEntities")
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := page.Get(context.Background(), uuid.New(), bc.GetOptions{})
+		done <- err
+	}()
+
+	clock.BlockUntil(1)
+	if got := st.Count(); got != 1 {
+		t.Fatalf("wanted 1 attempt before the backoff, got %d", got)
+	}
+	clock.Advance(time.Hour)
+
+	if err := <-done; err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+	if got := st.Count(); got != 2 {
+		t.Errorf("wanted 2 attempts, got %d", got)
+	}
+}
+
+func TestClockRetryCanceled(t *testing.T) {
+	st := &bctest.SequenceTransport{Responses: []*http.Response{
+		errorResponse(http.StatusServiceUnavailable, "ServiceUnavailable"),
+	}}
+
+	clock := bcmock.NewClock(time.Now())
+	client := newSequenceClient(t, st, bc.WithClock(clock), bc.WithRetryClassifier(bc.DefaultRetryClassifier{}))
+	page := bc.NewAPIPage[fakeEntity](client, "fakeEntities")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		_, err := page.Get(ctx, uuid.New(), bc.GetOptions{})
+		done <- err
+	}()
+
+	clock.BlockUntil(1)
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("wanted context.Canceled, got %v", err)
+	}
+}
+
+func TestClockMemoryTokenCache(t *testing.T) {
+	clock := bcmock.NewClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	cache := &bc.MemoryTokenCache{Clock: clock}
+	ctx := context.Background()
+
+	if err := cache.Set(ctx, "key", "token", clock.Now().Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if token, _, err := cache.Get(ctx, "key"); err != nil || token != "token" {
+		t.Fatalf("wanted the token, got %q %v", token, err)
+	}
+
+	clock.Advance(time.Hour)
+	if _, _, err := cache.Get(ctx, "key"); !errors.Is(err, bc.ErrTokenCacheMiss) {
+		t.Errorf("wanted ErrTokenCacheMiss after expiry, got %v", err)
+	}
+}
@@ -0,0 +1,66 @@
+package bc
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// Codec marshals request bodies and unmarshals response bodies.
+// Set it with [WithCodec] to use a faster JSON library, e.g. github.com/bytedance/sonic,
+// github.com/goccy/go-json or encoding/json/v2. The default is [JSONCodec].
+//
+// The codec must honor encoding/json struct tags and the json.Marshaler and
+// json.Unmarshaler interfaces, which [Date] and the models rely on.
+// [DecodeStrict] always uses encoding/json as it walks the raw JSON.
+type Codec interface {
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+}
+
+// JSONCodec is the default [Codec] using encoding/json.
+type JSONCodec struct{}
+
+// Marshal calls json.Marshal.
+func (JSONCodec) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// Unmarshal calls json.Unmarshal.
+func (JSONCodec) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
+
+// Codec returns the [Codec] of the client.
+func (c *Client) Codec() Codec {
+	return c.codec
+}
+
+// decodeCodec is the same as Decode but unmarshals with the codec.
+func decodeCodec[T Validator](codec Codec, r *http.Response) (T, error) {
+	defer r.Body.Close()
+
+	var data T
+
+	// If error status call decodeErrorResponse() to return an error
+	if r.StatusCode < 200 || r.StatusCode >= 300 {
+		err := decodeErrorResponse(r)
+		return data, err
+	}
+
+	b, err := io.ReadAll(r.Body)
+	if err != nil {
+		return data, fmt.Errorf("failed to read Response.Body: %w", err)
+	}
+
+	if err := codec.Unmarshal(b, &data); err != nil {
+		return data, fmt.Errorf("could not decode %T: %w", data, err)
+	}
+
+	if err := data.Validate(); err != nil {
+		return data, fmt.Errorf("failed validation of %T: %w", data, err)
+	}
+
+	return data, nil
+}
@@ -0,0 +1,53 @@
+package bc_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/erlorenz/bc-go/internal/bctest"
+)
+
+type countingCodec struct {
+	bc.JSONCodec
+	marshals, unmarshals int
+}
+
+func (cc *countingCodec) Marshal(v any) ([]byte, error) {
+	cc.marshals++
+	return cc.JSONCodec.Marshal(v)
+}
+
+func (cc *countingCodec) Unmarshal(data []byte, v any) error {
+	cc.unmarshals++
+	return cc.JSONCodec.Unmarshal(data, v)
+}
+
+func TestWithCodec(t *testing.T) {
+	codec := &countingCodec{}
+	st := &bctest.SequenceTransport{Responses: []*http.Response{
+		bctest.NewResponse(http.StatusCreated, map[string]any{"ID": validGUID, "Quantity": 2}),
+	}}
+	client := newSequenceClient(t, st, bc.WithCodec(codec))
+	page := bc.NewAPIPage[fakeEntity](client, "fakeEntities")
+
+	v, err := page.Create(context.Background(), map[string]any{"Quantity": 2}, bc.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.Quantity != 2 {
+		t.Errorf("wanted Quantity 2, got %d", v.Quantity)
+	}
+
+	if codec.marshals != 1 || codec.unmarshals != 1 {
+		t.Errorf("wanted codec used once each way, got %d marshals and %d unmarshals", codec.marshals, codec.unmarshals)
+	}
+}
+
+func TestDefaultCodec(t *testing.T) {
+	client := newSequenceClient(t, &bctest.SequenceTransport{})
+	if _, ok := client.Codec().(bc.JSONCodec); !ok {
+		t.Errorf("wanted JSONCodec, got %T", client.Codec())
+	}
+}
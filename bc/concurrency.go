@@ -0,0 +1,152 @@
+package bc
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+)
+
+// ConcurrencyOptions configure [NewConcurrencyLimiter].
+type ConcurrencyOptions struct {
+	// Min is the lowest limit. Defaults to 1.
+	Min int
+	// Max is the highest limit. Defaults to 10.
+	Max int
+	// Initial is the starting limit. Defaults to Min.
+	Initial int
+	// LatencyTarget is the response time above which a response counts as throttled. Defaults to 5 seconds.
+	LatencyTarget time.Duration
+	// Backoff multiplies the limit when a response is throttled. Defaults to 0.5.
+	Backoff float64
+}
+
+// ConcurrencyLimiter limits the requests in flight with AIMD, like TCP congestion control.
+// The limit grows by 1 after each limit of fast responses and is multiplied by Backoff
+// after a 429, 503 or 504 or a response slower than LatencyTarget, so bulk jobs find the
+// parallelism a tenant allows without tuning. Only requests started after a backoff can
+// cause another one, so a burst of 429s backs off once.
+//
+// Jobs can start a goroutine per item and let the limiter decide how many run at once.
+// Use with [WithConcurrencyLimiter]. It is safe for concurrent use.
+type ConcurrencyLimiter struct {
+	opts ConcurrencyOptions
+
+	mu          sync.Mutex
+	limit       float64
+	inFlight    int
+	waiters     []chan struct{}
+	lastBackoff time.Time
+}
+
+// NewConcurrencyLimiter creates a [ConcurrencyLimiter].
+func NewConcurrencyLimiter(opts ConcurrencyOptions) *ConcurrencyLimiter {
+	opts.Min = max(opts.Min, 1)
+	if opts.Max <= 0 {
+		opts.Max = 10
+	}
+	opts.Max = max(opts.Max, opts.Min)
+	opts.Initial = min(max(opts.Initial, opts.Min), opts.Max)
+	if opts.LatencyTarget <= 0 {
+		opts.LatencyTarget = 5 * time.Second
+	}
+	if opts.Backoff <= 0 || opts.Backoff >= 1 {
+		opts.Backoff = 0.5
+	}
+
+	return &ConcurrencyLimiter{opts: opts, limit: float64(opts.Initial)}
+}
+
+// Limit returns the current limit.
+func (cl *ConcurrencyLimiter) Limit() int {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+	return int(cl.limit)
+}
+
+// InFlight returns the number of requests holding a slot.
+func (cl *ConcurrencyLimiter) InFlight() int {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+	return cl.inFlight
+}
+
+// Middleware returns a [Middleware] that holds a slot until the response headers are
+// received and adjusts the limit from the status code and latency.
+func (cl *ConcurrencyLimiter) Middleware() Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			if err := cl.acquire(r.Context()); err != nil {
+				return nil, err
+			}
+
+			start := time.Now()
+			res, err := next.RoundTrip(r)
+			if err != nil {
+				cl.release(start, false, false)
+				return res, err
+			}
+
+			throttled := time.Since(start) > cl.opts.LatencyTarget
+			switch res.StatusCode {
+			case http.StatusTooManyRequests, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+				throttled = true
+			}
+			cl.release(start, true, throttled)
+			return res, nil
+		})
+	}
+}
+
+// acquire waits for a slot, FIFO, or until the context is done.
+func (cl *ConcurrencyLimiter) acquire(ctx context.Context) error {
+	cl.mu.Lock()
+	if len(cl.waiters) == 0 && cl.inFlight < int(cl.limit) {
+		cl.inFlight++
+		cl.mu.Unlock()
+		return nil
+	}
+
+	ready := make(chan struct{})
+	cl.waiters = append(cl.waiters, ready)
+	cl.mu.Unlock()
+
+	select {
+	case <-ready:
+		return nil
+	case <-ctx.Done():
+		cl.mu.Lock()
+		if i := slices.Index(cl.waiters, ready); i >= 0 {
+			cl.waiters = slices.Delete(cl.waiters, i, i+1)
+			cl.mu.Unlock()
+			return ctx.Err()
+		}
+		cl.mu.Unlock()
+		// Given a slot at the same time
+		cl.release(time.Time{}, false, false)
+		return ctx.Err()
+	}
+}
+
+// release frees the slot of a request started at start. The limit is only adjusted
+// if there was a response.
+func (cl *ConcurrencyLimiter) release(start time.Time, responded, throttled bool) {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+
+	cl.inFlight--
+	switch {
+	case responded && throttled && start.After(cl.lastBackoff):
+		cl.limit = max(cl.limit*cl.opts.Backoff, float64(cl.opts.Min))
+		cl.lastBackoff = time.Now()
+	case responded && !throttled:
+		cl.limit = min(cl.limit+1/cl.limit, float64(cl.opts.Max))
+	}
+
+	for len(cl.waiters) > 0 && cl.inFlight < int(cl.limit) {
+		cl.inFlight++
+		close(cl.waiters[0])
+		cl.waiters = cl.waiters[1:]
+	}
+}
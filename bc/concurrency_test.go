@@ -0,0 +1,140 @@
+package bc_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/erlorenz/bc-go/internal/bctest"
+)
+
+// statusTransport returns the status after release is closed, or at once if it is nil.
+func statusTransport(cl *bc.ConcurrencyLimiter, status int, release chan struct{}) http.RoundTripper {
+	return cl.Middleware()(bc.RoundTripperFunc(func(*http.Request) (*http.Response, error) {
+		if release != nil {
+			<-release
+		}
+		return bctest.NewResponse(status, map[string]any{}), nil
+	}))
+}
+
+func roundTrip(t *testing.T, rt http.RoundTripper, ctx context.Context) error {
+	t.Helper()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://example.com", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = rt.RoundTrip(req)
+	return err
+}
+
+func TestConcurrencyLimiterIncrease(t *testing.T) {
+	cl := bc.NewConcurrencyLimiter(bc.ConcurrencyOptions{Initial: 1, Max: 3})
+	rt := statusTransport(cl, http.StatusOK, nil)
+
+	for range 10 {
+		if err := roundTrip(t, rt, context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if cl.Limit() != 3 {
+		t.Errorf("wanted limit 3, got %d", cl.Limit())
+	}
+	if cl.InFlight() != 0 {
+		t.Errorf("wanted 0 in flight, got %d", cl.InFlight())
+	}
+}
+
+func TestConcurrencyLimiterBackoff(t *testing.T) {
+	cl := bc.NewConcurrencyLimiter(bc.ConcurrencyOptions{Initial: 8, Max: 8})
+	release := make(chan struct{})
+	rt := statusTransport(cl, http.StatusTooManyRequests, release)
+
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := roundTrip(t, rt, context.Background()); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	for cl.InFlight() < 4 {
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	wg.Wait()
+
+	if cl.Limit() != 4 {
+		t.Errorf("wanted one backoff to limit 4 for a burst of 429s, got %d", cl.Limit())
+	}
+
+	if err := roundTrip(t, rt, context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if cl.Limit() != 2 {
+		t.Errorf("wanted limit 2, got %d", cl.Limit())
+	}
+}
+
+func TestConcurrencyLimiterLatency(t *testing.T) {
+	cl := bc.NewConcurrencyLimiter(bc.ConcurrencyOptions{Initial: 4, Max: 8, LatencyTarget: time.Millisecond})
+	rt := cl.Middleware()(bc.RoundTripperFunc(func(*http.Request) (*http.Response, error) {
+		time.Sleep(5 * time.Millisecond)
+		return bctest.NewResponse(http.StatusOK, map[string]any{}), nil
+	}))
+
+	if err := roundTrip(t, rt, context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if cl.Limit() != 2 {
+		t.Errorf("wanted slow response to back off to limit 2, got %d", cl.Limit())
+	}
+}
+
+func TestConcurrencyLimiterWait(t *testing.T) {
+	cl := bc.NewConcurrencyLimiter(bc.ConcurrencyOptions{Max: 1})
+	release := make(chan struct{})
+	rt := statusTransport(cl, http.StatusOK, release)
+
+	done := make(chan error)
+	go func() { done <- roundTrip(t, rt, context.Background()) }()
+	for cl.InFlight() < 1 {
+		time.Sleep(time.Millisecond)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := roundTrip(t, rt, ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("wanted DeadlineExceeded, got %v", err)
+	}
+
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if cl.InFlight() != 0 {
+		t.Errorf("wanted 0 in flight, got %d", cl.InFlight())
+	}
+}
+
+func TestWithConcurrencyLimiter(t *testing.T) {
+	cl := bc.NewConcurrencyLimiter(bc.ConcurrencyOptions{Initial: 4, Max: 4})
+	st := &bctest.SequenceTransport{Responses: []*http.Response{
+		errorResponse(http.StatusTooManyRequests, "Application_TooManyRequests"),
+	}}
+	client := newSequenceClient(t, st, bc.WithConcurrencyLimiter(cl))
+	page := bc.NewAPIPage[fakeEntity](client, "fakeEntities")
+
+	if _, err := page.List(context.Background(), bc.ListOptions{}); err == nil {
+		t.Fatal("wanted error")
+	}
+	if cl.Limit() != 2 {
+		t.Errorf("wanted limit 2, got %d", cl.Limit())
+	}
+}
@@ -0,0 +1,93 @@
+package bc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// ErrCreateUncertain is returned by [APIPage.CreateOnce] when a create failed in a way
+// that it may have succeeded, and checking for the record failed as well.
+var ErrCreateUncertain = errors.New("create may have succeeded")
+
+// CreateOnceOptions configure [APIPage.CreateOnce].
+type CreateOnceOptions struct {
+	// KeyFilter matches the record of the body and no other by a natural key that the
+	// caller sets, e.g. "externalDocumentNumber eq 'WEB-1001'". It is required.
+	KeyFilter string
+	// Expand is used for the create and the check.
+	Expand []string
+	// MaxAttempts is the number of creates, including the first. Defaults to 3.
+	MaxAttempts int
+	// Backoff is waited before checking for the record, as BC may still be committing
+	// the create when the connection fails. Defaults to 1s.
+	Backoff time.Duration
+}
+
+// CreateOnce creates the record like [APIPage.Create], but when the POST fails in a way
+// that it may have landed, a network error or a gateway timeout, it lists the record by the
+// KeyFilter before sending it again, so a flaky connection never creates a duplicate sales
+// order. It returns the existing record if the earlier attempt landed. BC errors like a
+// validation error are returned as is.
+func (a *APIPage[T]) CreateOnce(ctx context.Context, body any, opts CreateOnceOptions) (T, error) {
+	var v T
+	if opts.KeyFilter == "" {
+		return v, errors.New("create once: KeyFilter is required")
+	}
+	maxAttempts := opts.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = 3
+	}
+	backoff := opts.Backoff
+	if backoff <= 0 {
+		backoff = time.Second
+	}
+
+	for attempt := 1; ; attempt++ {
+		created, err := a.Create(ctx, body, GetOptions{Expand: opts.Expand})
+		if err == nil || !createUncertain(err) {
+			return created, err
+		}
+		a.client.logger.Debug("Create failed, checking if it landed.", "entitySetName", a.entitySetName, "attempt", attempt, "filter", opts.KeyFilter, "error", err)
+
+		if serr := sleepContext(ctx, a.client.clock, backoff); serr != nil {
+			return v, fmt.Errorf("create once: %w: %w", ErrCreateUncertain, errors.Join(err, serr))
+		}
+		records, lerr := a.List(ctx, ListOptions{Filter: opts.KeyFilter, Expand: opts.Expand, Top: 2})
+		if lerr != nil {
+			return v, fmt.Errorf("create once: %w: %w", ErrCreateUncertain, errors.Join(err, lerr))
+		}
+		switch len(records) {
+		case 1:
+			a.client.logger.Debug("Create had landed, using the existing record.", "entitySetName", a.entitySetName, "attempt", attempt)
+			return records[0], nil
+		case 2:
+			return v, fmt.Errorf("create once: KeyFilter %q matches more than one record", opts.KeyFilter)
+		}
+
+		if attempt >= maxAttempts {
+			return v, fmt.Errorf("create once: not created after %d attempts: %w", attempt, err)
+		}
+	}
+}
+
+// createUncertain returns true if a failed create may have been committed by BC:
+// the connection failed after the request was sent, or a gateway timed out waiting for BC.
+func createUncertain(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var apiErr APIError
+	if errors.As(err, &apiErr) {
+		return !apiErr.Gateway().Rejected() && (apiErr.StatusCode == http.StatusBadGateway || apiErr.StatusCode == http.StatusGatewayTimeout)
+	}
+	var gwErr GatewayError
+	if errors.As(err, &gwErr) {
+		return !gwErr.Kind.Rejected() && gwErr.StatusCode != http.StatusServiceUnavailable
+	}
+	var urlErr *url.Error
+	return errors.As(err, &urlErr)
+}
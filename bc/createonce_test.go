@@ -0,0 +1,104 @@
+package bc_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/erlorenz/bc-go/internal/bctest"
+)
+
+// createOnceTransport fails the first POSTs with a network error and answers the
+// checks with the records.
+type createOnceTransport struct {
+	failPosts int
+	found     []map[string]any
+	posts     int
+	checks    []string
+}
+
+func (ct *createOnceTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	var res *http.Response
+	switch r.Method {
+	case http.MethodPost:
+		ct.posts++
+		if ct.posts <= ct.failPosts {
+			return nil, errors.New("connection reset by peer")
+		}
+		res = bctest.NewResponse(http.StatusCreated, map[string]any{"ID": validGUID, "Number": "NEW"})
+	case http.MethodGet:
+		ct.checks = append(ct.checks, r.URL.Query().Get("$filter"))
+		res = bctest.NewResponse(http.StatusOK, map[string]any{"value": append([]map[string]any{}, ct.found...)})
+	}
+	res.Request = r
+	return res, nil
+}
+
+func TestCreateOnce(t *testing.T) {
+	opts := bc.CreateOnceOptions{KeyFilter: "Number eq 'WEB-1001'", Backoff: time.Millisecond}
+
+	table := []struct {
+		name       string
+		transport  *createOnceTransport
+		wantNumber string
+		wantPosts  int
+		wantChecks int
+		wantErr    bool
+	}{
+		{"Success", &createOnceTransport{}, "NEW", 1, 0, false},
+		{"Landed", &createOnceTransport{failPosts: 1, found: []map[string]any{{"ID": validGUID, "Number": "WEB-1001"}}}, "WEB-1001", 1, 1, false},
+		{"NotLanded", &createOnceTransport{failPosts: 1}, "NEW", 2, 1, false},
+		{"GiveUp", &createOnceTransport{failPosts: 3}, "", 3, 3, true},
+		{"Ambiguous", &createOnceTransport{failPosts: 1, found: []map[string]any{{"ID": validGUID}, {"ID": validGUID}}}, "", 1, 1, true},
+	}
+	for _, v := range table {
+		t.Run(v.name, func(t *testing.T) {
+			client, err := bc.NewClient(fakeConfig, bc.WithAuthClient(fakeTokenGetter{}), bc.WithHTTPClient(&http.Client{Transport: v.transport}))
+			if err != nil {
+				t.Fatal(err)
+			}
+			page := bc.NewAPIPage[fakeEntity](client, "fakeEntities")
+
+			got, err := page.CreateOnce(context.Background(), map[string]any{"Number": "WEB-1001"}, opts)
+			if (err != nil) != v.wantErr {
+				t.Fatalf("wanted error %t, got %v", v.wantErr, err)
+			}
+			if got.Number != v.wantNumber {
+				t.Errorf("wanted number %q, got %q", v.wantNumber, got.Number)
+			}
+			if v.transport.posts != v.wantPosts || len(v.transport.checks) != v.wantChecks {
+				t.Errorf("wanted %d posts and %d checks, got %d and %d", v.wantPosts, v.wantChecks, v.transport.posts, len(v.transport.checks))
+			}
+			for _, filter := range v.transport.checks {
+				if filter != opts.KeyFilter {
+					t.Errorf("wanted the check to filter by %q, got %q", opts.KeyFilter, filter)
+				}
+			}
+		})
+	}
+}
+
+func TestCreateOnceNotUncertain(t *testing.T) {
+	st := &bctest.SequenceTransport{Responses: []*http.Response{
+		errorResponse(http.StatusBadRequest, "BadRequest"),
+	}}
+	client := newSequenceClient(t, st)
+	page := bc.NewAPIPage[fakeEntity](client, "fakeEntities")
+
+	_, err := page.CreateOnce(context.Background(), map[string]any{}, bc.CreateOnceOptions{KeyFilter: "Number eq 'X'"})
+	var apiErr bc.APIError
+	if !errors.As(err, &apiErr) || errors.Is(err, bc.ErrCreateUncertain) {
+		t.Errorf("wanted the APIError as is, got %v", err)
+	}
+	if st.Count() != 1 {
+		t.Errorf("wanted no check after a validation error, got %d requests", st.Count())
+	}
+
+	if _, err := page.CreateOnce(context.Background(), map[string]any{}, bc.CreateOnceOptions{}); err == nil || !strings.Contains(err.Error(), "KeyFilter") {
+		t.Errorf("wanted an error for a missing KeyFilter, got %v", err)
+	}
+}
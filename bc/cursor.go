@@ -0,0 +1,52 @@
+package bc
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+)
+
+// ErrInvalidCursor is returned when a [Cursor] cannot be decoded.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor is an opaque, URL safe position in a paged listing that can be stored or
+// returned to consumers and later set as [ListOptions].Cursor to continue the listing.
+// It only has the $skiptoken of the nextLink, so it must be used with the same ListOptions.
+type Cursor string
+
+// NewCursor creates the [Cursor] of a nextLink. It is empty if the link has no $skiptoken.
+func NewCursor(nextLink string) Cursor {
+	u, err := url.Parse(nextLink)
+	if err != nil {
+		return ""
+	}
+	token := u.Query().Get("$skiptoken")
+	if token == "" {
+		return ""
+	}
+	return Cursor(base64.RawURLEncoding.EncodeToString([]byte(token)))
+}
+
+// ParseCursor validates a [Cursor] received from a consumer.
+func ParseCursor(s string) (Cursor, error) {
+	c := Cursor(s)
+	if _, err := c.SkipToken(); err != nil {
+		return "", err
+	}
+	return c, nil
+}
+
+// SkipToken returns the $skiptoken of the cursor.
+func (c Cursor) SkipToken() (string, error) {
+	b, err := base64.RawURLEncoding.DecodeString(string(c))
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrInvalidCursor, err)
+	}
+	return string(b), nil
+}
+
+// Cursor returns the [Cursor] of the next page, or an empty Cursor on the last page.
+func (a APIListResponse[T]) Cursor() Cursor {
+	return NewCursor(a.NextLink)
+}
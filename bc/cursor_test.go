@@ -0,0 +1,79 @@
+package bc_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/erlorenz/bc-go/internal/bctest"
+)
+
+func TestCursor(t *testing.T) {
+	c := bc.NewCursor("https://api.businesscentral.dynamics.com/v2.0/next?$skiptoken=" + validGUID + "&$filter=x")
+	if c == "" {
+		t.Fatal("expected cursor")
+	}
+
+	parsed, err := bc.ParseCursor(string(c))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token, _ := parsed.SkipToken(); token != validGUID {
+		t.Errorf("wanted skiptoken %s, got %s", validGUID, token)
+	}
+
+	if got := bc.NewCursor("https://api.businesscentral.dynamics.com/v2.0/next"); got != "" {
+		t.Errorf("wanted empty cursor without skiptoken, got %s", got)
+	}
+
+	if _, err := bc.ParseCursor("not base64!"); !errors.Is(err, bc.ErrInvalidCursor) {
+		t.Errorf("wanted ErrInvalidCursor, got %v", err)
+	}
+}
+
+func TestListCursor(t *testing.T) {
+	next := "https://api.businesscentral.dynamics.com/v2.0/next?$skiptoken=abc"
+	st := &bctest.SequenceTransport{Responses: []*http.Response{
+		bctest.NewResponse(200, map[string]any{"value": []map[string]any{{"ID": validGUID}}, "@odata.nextLink": next}),
+		bctest.NewResponse(200, map[string]any{"value": []map[string]any{{"ID": validGUID}}}),
+	}}
+	client := newSequenceClient(t, st)
+	page := bc.NewAPIPage[fakeEntity](client, "fakeEntities")
+	opts := bc.ListOptions{MaxPageSize: 1, Filter: "Quantity gt 0"}
+
+	first, err := page.ListPage(context.Background(), "", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The cursor survives a round trip through JSON
+	b, err := json.Marshal(map[string]bc.Cursor{"cursor": first.Cursor()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var body map[string]bc.Cursor
+	if err := json.Unmarshal(b, &body); err != nil {
+		t.Fatal(err)
+	}
+
+	opts.Cursor = body["cursor"]
+	if _, err := page.List(context.Background(), opts); err != nil {
+		t.Fatal(err)
+	}
+
+	q := st.Requests[1].URL.Query()
+	if q.Get("$skiptoken") != "abc" || q.Get("$filter") != "Quantity gt 0" {
+		t.Errorf("unexpected query %s", st.Requests[1].URL.RawQuery)
+	}
+
+	opts.Cursor = "%%%"
+	if _, err := page.List(context.Background(), opts); !errors.Is(err, bc.ErrInvalidCursor) {
+		t.Errorf("wanted ErrInvalidCursor, got %v", err)
+	}
+	if _, err := page.ListPage(context.Background(), "", opts); !errors.Is(err, bc.ErrInvalidCursor) {
+		t.Errorf("wanted ErrInvalidCursor, got %v", err)
+	}
+}
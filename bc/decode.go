@@ -1,7 +1,9 @@
 package bc
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
@@ -9,6 +11,14 @@ import (
 	"strings"
 )
 
+var (
+	// ErrNotFound is matched by errors.Is for an [APIError] with status 404.
+	ErrNotFound = errors.New("not found")
+	// ErrPreconditionFailed is matched by errors.Is for an [APIError] with status 412,
+	// returned when the If-Match ETag does not match the current version of the record.
+	ErrPreconditionFailed = errors.New("precondition failed")
+)
+
 // ErrorResponse is the body of the response returned from
 // Business Central when the status is an error status.
 type ErrorResponse struct {
@@ -25,26 +35,45 @@ type ErrorResponseError struct {
 // returned by the BC server when responding with an error status.
 // It meets the Error interface.
 type APIError struct {
-	Code          string
+	Code          ErrorCode
 	Message       string
 	StatusCode    int
 	CorrelationID GUID
 	Request       *http.Request
+	// Details are the fields and reasons parsed from the Message on a best-effort basis.
+	// It is empty if the Message is not in a recognized format.
+	Details []FieldError
 }
 
 func (err APIError) Error() string {
 	return fmt.Sprintf("[%d %s] %s", err.StatusCode, err.Code, err.Message)
 }
 
+// Is returns true if target is ErrNotFound and the StatusCode is 404,
+// ErrPreconditionFailed and the StatusCode is 412, or the error of its [APIError.Gateway]
+// kind, e.g. ErrTenantMoving.
+func (err APIError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return err.StatusCode == http.StatusNotFound
+	case ErrPreconditionFailed:
+		return err.StatusCode == http.StatusPreconditionFailed
+	case ErrTenantMoving, ErrEnvironmentUpdating, ErrCapacity, ErrGatewayTimeout:
+		return target == err.Gateway().sentinel()
+	}
+	return false
+}
+
 func newBCAPIError(statusCode int, code string, message string, request *http.Request) APIError {
 	msg, id := extractCorrelationID(message)
 
 	return APIError{
-		Code:          code,
+		Code:          ErrorCode(code),
 		Message:       msg,
 		StatusCode:    statusCode,
 		CorrelationID: id,
 		Request:       request,
+		Details:       parseFieldErrors(msg),
 	}
 }
 
@@ -99,19 +128,22 @@ func DecodeNoContent(r *http.Response) error {
 // MakeErrorFromResponse decodes the http.Response into an ErrorResponse struct
 // and returns either an error with a failure to decode, or a BCServerError.
 func decodeErrorResponse(r *http.Response) error {
-	var data ErrorResponse
+	b, err := io.ReadAll(r.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read Response.Body: %s", err)
+	}
 
 	// Very strict response type, error on different structure.
-	d := json.NewDecoder(r.Body)
+	var data ErrorResponse
+	d := json.NewDecoder(bytes.NewReader(b))
 	d.DisallowUnknownFields()
 
-	err := d.Decode(&data)
-	if err != nil {
-		b, err := io.ReadAll(r.Body)
-		slog.Default().Error(string(b))
-		if err != nil {
-			return fmt.Errorf("failed to read Response.Body: %s", err)
+	if err := d.Decode(&data); err != nil {
+		// The gateway answers with its own pages when BC is unreachable.
+		if kind := FingerprintGateway(r.StatusCode, b); kind != "" {
+			return newGatewayError(r.StatusCode, b, r.Request)
 		}
+		slog.Default().Error(string(b))
 		return fmt.Errorf("failed decoding Response.Body into ErrorResponse: %s", string(b))
 	}
 
@@ -0,0 +1,76 @@
+package bc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// DeleteIfExists makes a DELETE request and treats a 404 as success.
+// It returns true if a record was deleted. Useful for cleanup jobs.
+func (c *Client) DeleteIfExists(ctx context.Context, entitySetName string, id uuid.UUID) (bool, error) {
+	err := c.delete(ctx, RequestOptions{
+		Method:        http.MethodDelete,
+		EntitySetName: entitySetName,
+		RecordID:      id,
+	})
+	if errors.Is(err, ErrNotFound) {
+		c.logger.Debug("Record did not exist.", "id", id)
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// DeleteIfMatch makes a DELETE request that only succeeds if the record still has the ETag,
+// usually the "@odata.etag" field of a previous response.
+// If the record has changed the error matches [ErrPreconditionFailed].
+func (c *Client) DeleteIfMatch(ctx context.Context, entitySetName string, id uuid.UUID, etag string) error {
+	if err := stringNotEmpty(etag); err != nil {
+		return fmt.Errorf("invalid etag: %w", err)
+	}
+
+	return c.delete(ctx, RequestOptions{
+		Method:        http.MethodDelete,
+		EntitySetName: entitySetName,
+		RecordID:      id,
+		ETag:          etag,
+	})
+}
+
+// delete sends the DELETE request and expects a 204 No Content.
+func (c *Client) delete(ctx context.Context, opts RequestOptions) error {
+	req, err := c.NewRequest(ctx, opts)
+	if err != nil {
+		return fmt.Errorf("failed to create Request: %w", err)
+	}
+
+	c.logger.Debug("Sending request...", "url", req.URL.String(), "method", req.Method)
+
+	res, err := c.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed during request: %w", err)
+	}
+
+	// Expects a 204 No Content
+	err = DecodeNoContent(res)
+
+	if err != nil {
+		var srvErr APIError
+		if errors.As(err, &srvErr) {
+			c.logger.Debug("API server returned error response.", "error", srvErr)
+			return fmt.Errorf("error from BC API: %w", srvErr)
+		}
+
+		c.logger.Debug("Failed to decode response.", "error", err)
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	c.logger.Debug("Succesfully deleted record.", "id", opts.RecordID)
+
+	return nil
+}
@@ -0,0 +1,84 @@
+package bc_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/google/uuid"
+)
+
+func TestDeleteIfExists(t *testing.T) {
+	notFound := bc.ErrorResponse{Error: bc.ErrorResponseError{Code: "BadRequest_NotFound", Message: "not found"}}
+
+	type testCase struct {
+		name   string
+		status int
+		body   any
+		want   bool
+	}
+
+	tests := []testCase{
+		{"deleted", http.StatusNoContent, nil, true},
+		{"not found", http.StatusNotFound, notFound, false},
+	}
+
+	for _, test := range tests {
+		client := newMockClient(t, test.status, test.body)
+
+		got, err := client.DeleteIfExists(context.Background(), "fakeEntities", uuid.New())
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %s", test.name, err)
+		}
+		if got != test.want {
+			t.Errorf("%s: wanted %t, got %t", test.name, test.want, got)
+		}
+	}
+}
+
+func TestDeleteIfMatch(t *testing.T) {
+	client := newMockClient(t, http.StatusPreconditionFailed, bc.ErrorResponse{
+		Error: bc.ErrorResponseError{Code: "Request_EntityChanged", Message: "changed"},
+	})
+
+	if err := client.DeleteIfMatch(context.Background(), "fakeEntities", uuid.New(), ""); err == nil {
+		t.Error("expected error for empty etag, got nil")
+	}
+
+	err := client.DeleteIfMatch(context.Background(), "fakeEntities", uuid.New(), `W/"etag"`)
+	if !errors.Is(err, bc.ErrPreconditionFailed) {
+		t.Errorf("expected ErrPreconditionFailed, got %v", err)
+	}
+}
+
+func TestNewRequestETag(t *testing.T) {
+	client, err := bc.NewClient(fakeConfig, bc.WithAuthClient(fakeTokenGetter{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req, err := client.NewRequest(context.Background(), bc.RequestOptions{
+		Method:        http.MethodDelete,
+		EntitySetName: "fakeEntities",
+		RecordID:      uuid.New(),
+		ETag:          `W/"etag"`,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := req.Header.Get("If-Match"); got != `W/"etag"` {
+		t.Errorf("wanted %s, got %s", `W/"etag"`, got)
+	}
+
+	_, err = client.NewRequest(context.Background(), bc.RequestOptions{
+		Method:        http.MethodGet,
+		EntitySetName: "fakeEntities",
+		ETag:          `W/"etag"`,
+	})
+	if err == nil {
+		t.Error("expected error for ETag with GET, got nil")
+	}
+}
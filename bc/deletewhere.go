@@ -0,0 +1,93 @@
+package bc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// ErrDeleteNotConfirmed is returned by [Client.DeleteWhere] without Confirm or MaxCount,
+// or when more records match than MaxCount.
+var ErrDeleteNotConfirmed = errors.New("delete not confirmed")
+
+// DeleteWhereOptions configure [Client.DeleteWhere]. Either Confirm or MaxCount is required.
+type DeleteWhereOptions struct {
+	// Confirm deletes every matching record, however many there are.
+	Confirm bool
+	// MaxCount deletes nothing if more records match, so a wrong filter in a cleanup
+	// script fails instead of emptying the entity set.
+	MaxCount int
+	// ChunkSize is the number of deletes per batch. Defaults to MaxBatchSize.
+	ChunkSize int
+}
+
+// DeleteWhereResult is the outcome of [Client.DeleteWhere].
+type DeleteWhereResult struct {
+	// Matched is the number of records that matched the filter.
+	Matched int
+	// Deleted is the number of records deleted, including those already gone.
+	Deleted int
+	// Failed are the records that could not be deleted, by id.
+	Failed map[uuid.UUID]error
+}
+
+// DeleteWhere deletes the records of the entity set that match the filter. It reads all
+// the matching ids first, so deletes do not shift the pages, and then deletes them with
+// [Client.Bulk]. A record that is already gone counts as deleted. An empty filter
+// matches every record and always needs Confirm.
+func (c *Client) DeleteWhere(ctx context.Context, entitySetName string, filter string, opts DeleteWhereOptions) (DeleteWhereResult, error) {
+	result := DeleteWhereResult{Failed: map[uuid.UUID]error{}}
+	if !opts.Confirm && (opts.MaxCount <= 0 || filter == "") {
+		return result, fmt.Errorf("delete where %s: %w: set Confirm or MaxCount", entitySetName, ErrDeleteNotConfirmed)
+	}
+
+	page := NewAPIPage[idRecord](c, entitySetName)
+	listOpts := ListOptions{Filter: filter, Select: []string{"id"}}
+	var ids []uuid.UUID
+	for link := ""; ; {
+		list, err := page.ListPage(ctx, link, listOpts)
+		if err != nil {
+			return result, fmt.Errorf("delete where %s: list ids: %w", entitySetName, err)
+		}
+		for _, r := range list.Value {
+			ids = append(ids, r.ID)
+		}
+		if opts.MaxCount > 0 && !opts.Confirm && len(ids) > opts.MaxCount {
+			return result, fmt.Errorf("delete where %s: %w: more than %d records match %q", entitySetName, ErrDeleteNotConfirmed, opts.MaxCount, filter)
+		}
+		if link = list.NextLink; link == "" {
+			break
+		}
+	}
+	result.Matched = len(ids)
+	c.logger.Debug("Deleting matching records.", "entitySetName", entitySetName, "filter", filter, "count", len(ids))
+
+	requests := make([]RequestOptions, len(ids))
+	for i, id := range ids {
+		requests[i] = RequestOptions{Method: http.MethodDelete, EntitySetName: entitySetName, RecordID: id}
+	}
+	responses, err := c.Bulk(ctx, requests, BulkOptions{ChunkSize: opts.ChunkSize})
+	for i, res := range responses {
+		if rerr := res.Err(); rerr != nil && !errors.Is(rerr, ErrNotFound) {
+			result.Failed[ids[i]] = rerr
+			continue
+		}
+		result.Deleted++
+	}
+	if err != nil {
+		return result, fmt.Errorf("delete where %s: %w", entitySetName, err)
+	}
+	return result, nil
+}
+
+// idRecord is a record read with $select=id.
+type idRecord struct {
+	ID uuid.UUID `json:"id"`
+}
+
+func (idRecord) Validate() error {
+	return nil
+}
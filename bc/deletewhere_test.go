@@ -0,0 +1,82 @@
+package bc_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/erlorenz/bc-go/internal/bctest"
+	"github.com/google/uuid"
+)
+
+func TestDeleteWhere(t *testing.T) {
+	ids := []uuid.UUID{uuid.New(), uuid.New(), uuid.New()}
+	nextLink := "https://api.businesscentral.dynamics.com/v2.0/" + validGUID + "/Sandbox/api/publisher/group/1.0/companies(" + validGUID + ")/customers?$skiptoken=2"
+	st := &bctest.SequenceTransport{Responses: []*http.Response{
+		bctest.NewResponse(200, map[string]any{"value": []map[string]any{{"id": ids[0]}, {"id": ids[1]}}, "@odata.nextLink": nextLink}),
+		bctest.NewResponse(200, map[string]any{"value": []map[string]any{{"id": ids[2]}}}),
+		bctest.NewResponse(200, map[string]any{"responses": []map[string]any{
+			{"id": "0", "status": http.StatusNoContent},
+			{"id": "1", "status": http.StatusNotFound, "body": map[string]any{"error": map[string]any{"code": "Internal_RecordNotFound", "message": "gone"}}},
+			{"id": "2", "status": http.StatusBadRequest, "body": map[string]any{"error": map[string]any{"code": "Internal_RecordLocked", "message": "locked"}}},
+		}}),
+	}}
+	client := newSequenceClient(t, st)
+
+	result, err := client.DeleteWhere(context.Background(), "customers", "displayName eq 'Test'", bc.DeleteWhereOptions{MaxCount: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Matched != 3 || result.Deleted != 2 || len(result.Failed) != 1 || result.Failed[ids[2]] == nil {
+		t.Errorf("unexpected result %+v", result)
+	}
+
+	q := st.Requests[0].URL.Query()
+	if q.Get("$filter") != "displayName eq 'Test'" || q.Get("$select") != "id" {
+		t.Errorf("unexpected query %s", st.Requests[0].URL.RawQuery)
+	}
+	body, _ := io.ReadAll(st.Requests[2].Body)
+	var batch struct {
+		Requests []struct {
+			Method string `json:"method"`
+			URL    string `json:"url"`
+		} `json:"requests"`
+	}
+	if err := json.Unmarshal(body, &batch); err != nil {
+		t.Fatal(err)
+	}
+	if len(batch.Requests) != 3 || batch.Requests[2].Method != http.MethodDelete || !strings.Contains(batch.Requests[2].URL, ids[2].String()) {
+		t.Errorf("unexpected batch %s", body)
+	}
+}
+
+func TestDeleteWhereNotConfirmed(t *testing.T) {
+	st := &bctest.SequenceTransport{Responses: []*http.Response{
+		bctest.NewResponse(200, map[string]any{"value": []map[string]any{{"id": uuid.New()}, {"id": uuid.New()}}}),
+	}}
+	client := newSequenceClient(t, st)
+	ctx := context.Background()
+
+	table := []struct {
+		name   string
+		filter string
+		opts   bc.DeleteWhereOptions
+	}{
+		{"no confirmation", "number eq '1'", bc.DeleteWhereOptions{}},
+		{"empty filter", "", bc.DeleteWhereOptions{MaxCount: 10}},
+		{"too many", "number ne ''", bc.DeleteWhereOptions{MaxCount: 1}},
+	}
+	for _, tt := range table {
+		if _, err := client.DeleteWhere(ctx, "customers", tt.filter, tt.opts); !errors.Is(err, bc.ErrDeleteNotConfirmed) {
+			t.Errorf("%s: wanted ErrDeleteNotConfirmed, got %v", tt.name, err)
+		}
+	}
+	if st.Count() != 1 {
+		t.Errorf("wanted only the list of the last case, got %d requests", st.Count())
+	}
+}
@@ -0,0 +1,131 @@
+package bc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// PreferTrackChanges is the "Prefer" header value that requests change tracking,
+// so the last page of the response has an "@odata.deltaLink".
+const PreferTrackChanges = "odata.track-changes"
+
+// DeltaPage is a single page of a change tracking response.
+type DeltaPage[T any] struct {
+	// Records are the records created or updated since the previous delta link.
+	Records []T
+	// Deleted are the IDs of the records deleted since the previous delta link.
+	Deleted []uuid.UUID
+	// NextLink is set if there are more pages. Pass it to the next call of Delta.
+	NextLink string
+	// DeltaLink is set on the last page. Store it and pass it to Delta to get the next changes.
+	DeltaLink string
+}
+
+// Link returns the NextLink if there are more pages, otherwise the DeltaLink.
+// It is the value to store as a checkpoint.
+func (dp DeltaPage[T]) Link() string {
+	if dp.NextLink != "" {
+		return dp.NextLink
+	}
+	return dp.DeltaLink
+}
+
+type deltaResponse struct {
+	Value     []json.RawMessage `json:"value"`
+	NextLink  string            `json:"@odata.nextLink,omitempty"`
+	DeltaLink string            `json:"@odata.deltaLink,omitempty"`
+}
+
+func (d deltaResponse) Validate() error {
+	return nil
+}
+
+// deltaEntry is the shape of a deleted record in a delta response.
+type deltaEntry struct {
+	ID      uuid.UUID       `json:"id"`
+	Removed json.RawMessage `json:"@removed,omitempty"`
+	Reason  string          `json:"reason,omitempty"`
+	Context string          `json:"@odata.context,omitempty"`
+}
+
+func (de deltaEntry) isDeleted() bool {
+	return len(de.Removed) > 0 || de.Reason == "deleted"
+}
+
+// Delta gets a single page of changes. With an empty link it starts change tracking
+// with the list options and the first response contains every record.
+// Otherwise it requests the link, a NextLink or DeltaLink from a previous [DeltaPage],
+// and the options are ignored.
+func (a *APIPage[T]) Delta(ctx context.Context, link string, opts ListOptions) (DeltaPage[T], error) {
+	var page DeltaPage[T]
+
+	var req *http.Request
+	var err error
+	if link == "" {
+		req, err = a.client.NewRequest(ctx, RequestOptions{
+			Method:        http.MethodGet,
+			EntitySetName: a.entitySetName,
+			QueryParams:   opts.BuildQueryParams(a.BaseFilter, a.BaseExpand),
+			Header:        http.Header{"Prefer": {PreferTrackChanges}},
+		})
+	} else {
+		req, err = a.client.NewRequestURL(ctx, http.MethodGet, link, nil)
+		if err == nil {
+			req.Header.Set("Prefer", PreferTrackChanges)
+		}
+	}
+	if err != nil {
+		return page, fmt.Errorf("failed to create Request: %w", err)
+	}
+
+	res, err := a.client.Do(req)
+	if err != nil {
+		return page, fmt.Errorf("failed during request: %w", err)
+	}
+
+	data, err := decode[deltaResponse](a.client, res)
+	if err != nil {
+		var srvErr APIError
+		if errors.As(err, &srvErr) {
+			a.client.logger.Debug("API server returned error response.", "error", srvErr)
+			return page, fmt.Errorf("error from BC API: %w", srvErr)
+		}
+
+		a.client.logger.Debug("Unable to decode response.", "error", err)
+		return page, fmt.Errorf("decode response: %w", err)
+	}
+
+	page.NextLink = data.NextLink
+	page.DeltaLink = data.DeltaLink
+
+	for i, raw := range data.Value {
+		var entry deltaEntry
+		if err := a.client.codec.Unmarshal(raw, &entry); err != nil {
+			return page, fmt.Errorf("decode delta entry %d: %w", i, err)
+		}
+		if entry.isDeleted() {
+			page.Deleted = append(page.Deleted, entry.ID)
+			continue
+		}
+
+		var v T
+		if err := a.client.codec.Unmarshal(raw, &v); err != nil {
+			return page, fmt.Errorf("decode delta entry %d: %w", i, err)
+		}
+		if err := v.Validate(); err != nil {
+			return page, fmt.Errorf("failed validation of %T: %w", v, err)
+		}
+		page.Records = append(page.Records, v)
+	}
+
+	if err := validateList(a.client, a.entitySetName, page.Records); err != nil {
+		return page, err
+	}
+
+	return page, nil
+}
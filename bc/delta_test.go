@@ -0,0 +1,63 @@
+package bc_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/erlorenz/bc-go/internal/bctest"
+	"github.com/google/uuid"
+)
+
+func TestDelta(t *testing.T) {
+	deletedID := uuid.New()
+	st := &bctest.SequenceTransport{Responses: []*http.Response{
+		bctest.NewResponse(200, map[string]any{
+			"value":           []map[string]any{{"ID": validGUID, "Number": "1"}},
+			"@odata.nextLink": "https://api.businesscentral.dynamics.com/v2.0/next?$skiptoken=1",
+		}),
+		bctest.NewResponse(200, map[string]any{
+			"value": []map[string]any{
+				{"ID": validGUID, "Number": "2"},
+				{"@odata.context": "$metadata#fakeEntities/$deletedEntity", "id": deletedID, "reason": "deleted"},
+			},
+			"@odata.deltaLink": "https://api.businesscentral.dynamics.com/v2.0/delta?$deltatoken=2",
+		}),
+	}}
+	client := newSequenceClient(t, st)
+	page := bc.NewAPIPage[fakeEntity](client, "fakeEntities")
+
+	first, err := page.Delta(context.Background(), "", bc.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(first.Records) != 1 || first.NextLink == "" || first.Link() != first.NextLink {
+		t.Fatalf("unexpected first page %+v", first)
+	}
+	if got := st.Requests[0].Header.Get("Prefer"); got != bc.PreferTrackChanges {
+		t.Errorf("wanted Prefer %s, got %s", bc.PreferTrackChanges, got)
+	}
+
+	second, err := page.Delta(context.Background(), first.Link(), bc.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(second.Records) != 1 || len(second.Deleted) != 1 || second.Deleted[0] != deletedID {
+		t.Fatalf("unexpected second page %+v", second)
+	}
+	if second.Link() != "https://api.businesscentral.dynamics.com/v2.0/delta?$deltatoken=2" {
+		t.Errorf("unexpected link %s", second.Link())
+	}
+	if st.Requests[1].URL.Path != "/v2.0/next" {
+		t.Errorf("unexpected path %s", st.Requests[1].URL.Path)
+	}
+}
+
+func TestNewRequestURLHost(t *testing.T) {
+	client := newSequenceClient(t, &bctest.SequenceTransport{})
+
+	if _, err := client.NewRequestURL(context.Background(), http.MethodGet, "https://evil.example.com/v2.0", nil); err == nil {
+		t.Error("expected error for different host, got nil")
+	}
+}
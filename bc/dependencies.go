@@ -0,0 +1,80 @@
+package bc
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ErrDependencyCycle is returned by [Capabilities.CreationOrder] when entity sets
+// reference each other.
+var ErrDependencyCycle = errors.New("entity set dependency cycle")
+
+// Dependencies returns the entity sets that the records of the entity set reference, by
+// the referential constraints of its navigation properties in the $metadata, e.g.
+// "customers" and "currencies" for "salesOrders". A reference to itself is left out.
+// The lines of a document are not a dependency, they are created with it by a deep insert.
+func (c Capabilities) Dependencies(entitySetName string) []string {
+	var deps []string
+	for _, nav := range c.NavigationProperties[entitySetName] {
+		if nav.Collection || len(nav.Constraints) == 0 || nav.EntitySetName == "" || nav.EntitySetName == entitySetName {
+			continue
+		}
+		if !slices.Contains(deps, nav.EntitySetName) {
+			deps = append(deps, nav.EntitySetName)
+		}
+	}
+	return deps
+}
+
+// CreationOrder sorts the entity sets so that each comes after the ones it depends on,
+// see [Capabilities.Dependencies], e.g. to load or copy customers before sales orders.
+// Dependencies on entity sets that are not in entitySetNames are ignored, and entity sets
+// without dependencies between them keep their order. It returns an error matching
+// [ErrDependencyCycle] if they reference each other.
+func (c Capabilities) CreationOrder(entitySetNames ...string) ([]string, error) {
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := make(map[string]int, len(entitySetNames))
+	for _, name := range entitySetNames {
+		state[name] = unvisited
+	}
+	ordered := make([]string, 0, len(entitySetNames))
+	var path []string
+
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch state[name] {
+		case done:
+			return nil
+		case visiting:
+			return fmt.Errorf("%w: %s -> %s", ErrDependencyCycle, strings.Join(path, " -> "), name)
+		}
+		state[name] = visiting
+		path = append(path, name)
+
+		for _, dep := range c.Dependencies(name) {
+			if _, ok := state[dep]; !ok {
+				continue
+			}
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+
+		path = path[:len(path)-1]
+		state[name] = done
+		ordered = append(ordered, name)
+		return nil
+	}
+	for _, name := range entitySetNames {
+		if err := visit(name); err != nil {
+			return nil, err
+		}
+	}
+	return ordered, nil
+}
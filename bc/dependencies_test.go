@@ -0,0 +1,43 @@
+package bc_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/erlorenz/bc-go/bc"
+)
+
+func TestCreationOrder(t *testing.T) {
+	reference := func(name, entitySetName string) bc.NavigationProperty {
+		return bc.NavigationProperty{Name: name, EntitySetName: entitySetName, Constraints: map[string]string{name + "Id": "id"}}
+	}
+	caps := bc.Capabilities{NavigationProperties: map[string][]bc.NavigationProperty{
+		"salesOrders": {
+			reference("customer", "customers"),
+			reference("currency", "currencies"),
+			{Name: "salesOrderLines", EntitySetName: "salesOrderLines", Collection: true},
+		},
+		"salesOrderLines": {reference("item", "items"), reference("salesOrder", "salesOrders")},
+		"customers":       {reference("currency", "currencies"), reference("customer", "customers")},
+		"items":           {{Name: "picture", EntitySetName: "pictures"}},
+	}}
+
+	if deps := caps.Dependencies("customers"); !slices.Equal(deps, []string{"currencies"}) {
+		t.Errorf("wanted customers to depend on currencies, got %v", deps)
+	}
+
+	got, err := caps.CreationOrder("salesOrderLines", "salesOrders", "items", "customers", "currencies")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"items", "currencies", "customers", "salesOrders", "salesOrderLines"}
+	if !slices.Equal(got, want) {
+		t.Errorf("wanted %v, got %v", want, got)
+	}
+
+	caps.NavigationProperties["currencies"] = []bc.NavigationProperty{reference("salesOrder", "salesOrders")}
+	if _, err := caps.CreationOrder("salesOrders", "currencies"); !errors.Is(err, bc.ErrDependencyCycle) {
+		t.Errorf("wanted ErrDependencyCycle, got %v", err)
+	}
+}
@@ -0,0 +1,146 @@
+package bc
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Diagnostics are the identifiers and throttling information of the responses to the
+// requests sent with a context from [WithDiagnostics], so application logs can include
+// the ids that Microsoft support asks for. The fields are from the last response, as a
+// retried request has one per attempt.
+type Diagnostics struct {
+	// CorrelationID is the "ms-correlation-id" header, or "ms-correlation-x", which
+	// identifies the request in the telemetry of the environment.
+	CorrelationID string
+	// RequestID is the "request-id" header.
+	RequestID string
+	// ClientRequestID is the "client-request-id" header, set by BC if the request had none.
+	ClientRequestID string
+	// ServerTiming is the "Server-Timing" header, if BC sent one.
+	ServerTiming string
+	StatusCode   int
+	// Throttled is true if any attempt was answered with a 429.
+	Throttled bool
+	// RetryAfter is the "Retry-After" header of the last response that had one.
+	RetryAfter time.Duration
+	// Attempts is the number of responses received.
+	Attempts int
+	// Timings are those of the last attempt, if the client has [WithHTTPTrace].
+	Timings Timings
+}
+
+type diagnosticsKey struct{}
+
+// DiagnosticsRecorder collects the [Diagnostics] of a context. It is filled by the
+// [CaptureDiagnostics] middleware and is safe for concurrent use.
+type DiagnosticsRecorder struct {
+	mu sync.Mutex
+	d  Diagnostics
+}
+
+// WithDiagnostics returns a context that records the [Diagnostics] of the requests sent
+// with it, including requests made by [APIPage] methods, and the recorder to read them
+// after the call returns. The client needs the [CaptureDiagnostics] middleware.
+func WithDiagnostics(ctx context.Context) (context.Context, *DiagnosticsRecorder) {
+	rec := &DiagnosticsRecorder{}
+	return context.WithValue(ctx, diagnosticsKey{}, rec), rec
+}
+
+// DiagnosticsFrom returns the Diagnostics recorded in a context from [WithDiagnostics].
+// It is false if the context has no recorder.
+func DiagnosticsFrom(ctx context.Context) (Diagnostics, bool) {
+	rec, ok := ctx.Value(diagnosticsKey{}).(*DiagnosticsRecorder)
+	if !ok {
+		return Diagnostics{}, false
+	}
+	return rec.Diagnostics(), true
+}
+
+// CaptureDiagnostics returns a [Middleware] that records the response headers in the
+// [DiagnosticsRecorder] of the request context. Requests without one are not changed.
+func CaptureDiagnostics() Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			res, err := next.RoundTrip(r)
+			if rec, ok := r.Context().Value(diagnosticsKey{}).(*DiagnosticsRecorder); ok && err == nil {
+				rec.record(res)
+			}
+			return res, err
+		})
+	}
+}
+
+func (rec *DiagnosticsRecorder) record(res *http.Response) {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+
+	d := &rec.d
+	d.Attempts++
+	d.StatusCode = res.StatusCode
+	d.CorrelationID = res.Header.Get("ms-correlation-id")
+	if d.CorrelationID == "" {
+		d.CorrelationID = res.Header.Get("ms-correlation-x")
+	}
+	d.RequestID = res.Header.Get("request-id")
+	d.ClientRequestID = res.Header.Get("client-request-id")
+	d.ServerTiming = res.Header.Get("Server-Timing")
+	if res.StatusCode == http.StatusTooManyRequests {
+		d.Throttled = true
+	}
+	if after, ok := retryAfter(res.Header); ok {
+		d.RetryAfter = after
+	}
+}
+
+func (rec *DiagnosticsRecorder) recordTimings(t Timings) {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	rec.d.Timings = t
+}
+
+// Diagnostics returns the recorded Diagnostics.
+func (rec *DiagnosticsRecorder) Diagnostics() Diagnostics {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	return rec.d
+}
+
+// LogValue implements [slog.LogValuer], so the recorder can be logged as a group of
+// the fields that are set.
+func (rec *DiagnosticsRecorder) LogValue() slog.Value {
+	return rec.Diagnostics().LogValue()
+}
+
+// LogValue implements [slog.LogValuer] with the fields that are set.
+func (d Diagnostics) LogValue() slog.Value {
+	var attrs []slog.Attr
+	add := func(key, value string) {
+		if value != "" {
+			attrs = append(attrs, slog.String(key, value))
+		}
+	}
+	add("correlationId", d.CorrelationID)
+	add("requestId", d.RequestID)
+	add("clientRequestId", d.ClientRequestID)
+	add("serverTiming", d.ServerTiming)
+	if d.StatusCode != 0 {
+		attrs = append(attrs, slog.Int("status", d.StatusCode))
+	}
+	if d.Attempts > 1 {
+		attrs = append(attrs, slog.Int("attempts", d.Attempts))
+	}
+	if d.Throttled {
+		attrs = append(attrs, slog.Bool("throttled", true))
+	}
+	if d.RetryAfter > 0 {
+		attrs = append(attrs, slog.Duration("retryAfter", d.RetryAfter))
+	}
+	if d.Timings != (Timings{}) {
+		attrs = append(attrs, slog.Any("timings", d.Timings))
+	}
+	return slog.GroupValue(attrs...)
+}
@@ -0,0 +1,50 @@
+package bc_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/erlorenz/bc-go/internal/bctest"
+	"github.com/google/uuid"
+)
+
+func TestCaptureDiagnostics(t *testing.T) {
+	throttled := errorResponse(http.StatusTooManyRequests, "TooManyRequests")
+	throttled.Header.Set("Retry-After", "0")
+	ok := bctest.NewResponse(http.StatusOK, map[string]any{"ID": validGUID})
+	ok.Header.Set("ms-correlation-x", "correlation")
+	ok.Header.Set("request-id", "request")
+	ok.Header.Set("Server-Timing", "total;dur=12")
+
+	st := &bctest.SequenceTransport{Responses: []*http.Response{throttled, ok}}
+	client := newSequenceClient(t, st, bc.WithMiddleware(bc.CaptureDiagnostics()),
+		bc.WithRetryClassifier(bc.DefaultRetryClassifier{BaseDelay: time.Millisecond}))
+	page := bc.NewAPIPage[fakeEntity](client, "fakeEntities")
+
+	ctx, rec := bc.WithDiagnostics(context.Background())
+	if _, err := page.Get(ctx, uuid.New(), bc.GetOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	got, _ := bc.DiagnosticsFrom(ctx)
+	if got.CorrelationID != "correlation" || got.RequestID != "request" || got.ServerTiming != "total;dur=12" ||
+		got.StatusCode != http.StatusOK || !got.Throttled || got.Attempts != 2 {
+		t.Errorf("unexpected diagnostics %+v", got)
+	}
+
+	var buf bytes.Buffer
+	slog.New(slog.NewTextHandler(&buf, nil)).Info("Done.", "bc", rec)
+	if want := "bc.correlationId=correlation bc.requestId=request"; !strings.Contains(buf.String(), want) {
+		t.Errorf("wanted %q in the log, got %s", want, buf.String())
+	}
+
+	if _, ok := bc.DiagnosticsFrom(context.Background()); ok {
+		t.Error("wanted no diagnostics without WithDiagnostics")
+	}
+}
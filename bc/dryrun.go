@@ -0,0 +1,64 @@
+package bc
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// ErrDryRun is matched by errors.Is for a [DryRunError].
+var ErrDryRun = errors.New("dry run")
+
+// DryRunError is returned by [Client.Do] on a client created with [Client.DryRun]
+// instead of sending a mutating request. It describes the request that would have been sent.
+type DryRunError struct {
+	Method string
+	URL    string
+	// Header has the Authorization and Cookie headers redacted.
+	Header http.Header
+	Body   []byte
+}
+
+func (err DryRunError) Error() string {
+	return fmt.Sprintf("%s: %s %s", ErrDryRun, err.Method, err.URL)
+}
+
+// Is returns true if target is ErrDryRun.
+func (err DryRunError) Is(target error) bool {
+	return target == ErrDryRun
+}
+
+// DryRun returns a copy of the client that builds and validates POST, PUT, PATCH and
+// DELETE requests but does not send them. [Client.Do] returns a [DryRunError] describing
+// the request instead. GET requests are sent as normal so lookups still work.
+func (c *Client) DryRun() *Client {
+	dry := *c
+	dry.dryRun = true
+	return &dry
+}
+
+// IsDryRun returns true if the client was created with [Client.DryRun].
+func (c *Client) IsDryRun() bool {
+	return c.dryRun
+}
+
+// newDryRunError reads the request body and returns the [DryRunError].
+func newDryRunError(r *http.Request) error {
+	var body []byte
+	if r.Body != nil && r.Body != http.NoBody {
+		b, err := io.ReadAll(r.Body)
+		r.Body.Close()
+		if err != nil {
+			return fmt.Errorf("dry run read body: %w", err)
+		}
+		body = b
+	}
+
+	return DryRunError{
+		Method: r.Method,
+		URL:    r.URL.String(),
+		Header: redactHeader(r.Header),
+		Body:   body,
+	}
+}
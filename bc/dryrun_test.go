@@ -0,0 +1,49 @@
+package bc_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/erlorenz/bc-go/internal/bctest"
+)
+
+func TestDryRun(t *testing.T) {
+	st := &bctest.SequenceTransport{Responses: []*http.Response{bctest.NewResponse(200, map[string]any{"value": []any{}})}}
+	client := newSequenceClient(t, st)
+
+	dry := client.DryRun()
+	if !dry.IsDryRun() || client.IsDryRun() {
+		t.Fatal("expected only the copy to be dry run")
+	}
+
+	page := bc.NewAPIPage[fakeEntity](dry, "fakeEntities")
+
+	_, err := page.Create(context.Background(), map[string]any{"Number": "1000"}, bc.GetOptions{})
+
+	var dryErr bc.DryRunError
+	if !errors.As(err, &dryErr) || !errors.Is(err, bc.ErrDryRun) {
+		t.Fatalf("expected DryRunError, got %v", err)
+	}
+
+	if dryErr.Method != http.MethodPost || string(dryErr.Body) != `{"Number":"1000"}` {
+		t.Errorf("unexpected dry run %+v", dryErr)
+	}
+	if dryErr.Header.Get("Authorization") != "REDACTED" {
+		t.Errorf("expected redacted Authorization, got %s", dryErr.Header.Get("Authorization"))
+	}
+
+	if st.Count() != 0 {
+		t.Errorf("expected no requests sent, got %d", st.Count())
+	}
+
+	// GET still goes through
+	if _, err := page.List(context.Background(), bc.ListOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if st.Count() != 1 {
+		t.Errorf("expected 1 request sent, got %d", st.Count())
+	}
+}
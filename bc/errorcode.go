@@ -0,0 +1,106 @@
+package bc
+
+import (
+	"slices"
+	"strings"
+)
+
+// ErrorCode is the error.code returned by Business Central in an error response.
+// Codes are prefixed by their category, e.g. "Application_", "Internal_",
+// "BadRequest_", "Authentication_" or "Request_".
+type ErrorCode string
+
+// Error codes commonly returned by the Business Central API.
+// This is not an exhaustive list, unknown codes are classified by their prefix.
+const (
+	ErrorCodeNotFound              ErrorCode = "BadRequest_NotFound"
+	ErrorCodeResourceNotFound      ErrorCode = "BadRequest_ResourceNotFound"
+	ErrorCodeMethodNotAllowed      ErrorCode = "BadRequest_MethodNotAllowed"
+	ErrorCodeInvalidRequestURL     ErrorCode = "BadRequest_InvalidRequestUrl"
+	ErrorCodeInvalidToken          ErrorCode = "BadRequest_InvalidToken"
+	ErrorCodeRecordNotFound        ErrorCode = "Internal_RecordNotFound"
+	ErrorCodeEntityWithSameKey     ErrorCode = "Internal_EntityWithSameKeyExists"
+	ErrorCodeEntityChanged         ErrorCode = "Request_EntityChanged"
+	ErrorCodeDialogException       ErrorCode = "Application_DialogException"
+	ErrorCodeInvalidCredentials    ErrorCode = "Authentication_InvalidCredentials"
+	ErrorCodePermissionDenied      ErrorCode = "Internal_PermissionDenied"
+	ErrorCodeNotAuthorized         ErrorCode = "Unauthorized"
+	ErrorCodeTooManyRequests       ErrorCode = "Application_TooManyRequests"
+	ErrorCodeServiceUnavailable    ErrorCode = "Internal_ServiceUnavailable"
+	ErrorCodeRequestTimeout        ErrorCode = "Internal_RequestTimeout"
+	ErrorCodeRecordLocked          ErrorCode = "Internal_LockTimeout"
+	ErrorCodeDeadlock              ErrorCode = "Internal_Deadlock"
+	ErrorCodeServerError           ErrorCode = "Internal_ServerError"
+	ErrorCodeCompanyNotFound       ErrorCode = "Internal_CompanyNotFound"
+	ErrorCodeTenantNotFound        ErrorCode = "Internal_TenantNotFound"
+	ErrorCodeEnvironmentNotFound   ErrorCode = "Internal_EnvironmentNotFound"
+	ErrorCodeInvalidRequestBody    ErrorCode = "BadRequest_InvalidRequestBody"
+	ErrorCodeUnsupportedMediaType  ErrorCode = "BadRequest_UnsupportedMediaType"
+	ErrorCodeODataParseError       ErrorCode = "BadRequest_ODataParseError"
+	ErrorCodeInvalidFieldValue     ErrorCode = "Application_InvalidFieldValue"
+	ErrorCodeFieldValidationFailed ErrorCode = "Application_FieldValidationException"
+)
+
+var transientErrorCodes = []ErrorCode{
+	ErrorCodeTooManyRequests,
+	ErrorCodeServiceUnavailable,
+	ErrorCodeRequestTimeout,
+	ErrorCodeRecordLocked,
+	ErrorCodeDeadlock,
+	"TooManyRequests",
+}
+
+var notFoundErrorCodes = []ErrorCode{
+	ErrorCodeNotFound,
+	ErrorCodeResourceNotFound,
+	ErrorCodeRecordNotFound,
+	ErrorCodeCompanyNotFound,
+	ErrorCodeTenantNotFound,
+	ErrorCodeEnvironmentNotFound,
+}
+
+// Category returns the prefix of the code before the first underscore,
+// e.g. "Application" or "Internal". It returns the whole code if there is no prefix.
+func (ec ErrorCode) Category() string {
+	category, _, _ := strings.Cut(string(ec), "_")
+	return category
+}
+
+// IsTransient returns true for throttling, timeout and locking errors
+// that are likely to succeed if retried.
+func (ec ErrorCode) IsTransient() bool {
+	return slices.Contains(transientErrorCodes, ec)
+}
+
+// IsPermission returns true for authentication and authorization errors.
+func (ec ErrorCode) IsPermission() bool {
+	switch ec.Category() {
+	case "Authentication", "Authorization":
+		return true
+	}
+	return ec == ErrorCodePermissionDenied || ec == ErrorCodeNotAuthorized || ec == ErrorCodeInvalidToken
+}
+
+// IsNotFound returns true if the record, resource or environment does not exist.
+func (ec ErrorCode) IsNotFound() bool {
+	return slices.Contains(notFoundErrorCodes, ec)
+}
+
+// IsConflict returns true if the record changed since it was read or
+// a record with the same key already exists.
+func (ec ErrorCode) IsConflict() bool {
+	return ec == ErrorCodeEntityChanged || ec == ErrorCodeEntityWithSameKey
+}
+
+// IsValidation returns true for business logic and request validation errors
+// that will fail again unless the request changes.
+func (ec ErrorCode) IsValidation() bool {
+	if ec.IsTransient() || ec.IsPermission() || ec.IsNotFound() || ec.IsConflict() {
+		return false
+	}
+	switch ec.Category() {
+	case "Application", "BadRequest":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,68 @@
+package bc_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/google/uuid"
+)
+
+func TestErrorCodeClassification(t *testing.T) {
+	type testCase struct {
+		code       bc.ErrorCode
+		transient  bool
+		permission bool
+		validation bool
+		notFound   bool
+	}
+
+	tests := []testCase{
+		{bc.ErrorCodeRecordLocked, true, false, false, false},
+		{bc.ErrorCodeTooManyRequests, true, false, false, false},
+		{bc.ErrorCodeInvalidCredentials, false, true, false, false},
+		{"Authorization_Something", false, true, false, false},
+		{bc.ErrorCodeDialogException, false, false, true, false},
+		{"Application_UnknownNewCode", false, false, true, false},
+		{bc.ErrorCodeNotFound, false, false, false, true},
+		{bc.ErrorCodeEntityChanged, false, false, false, false},
+		{"Internal_Unknown", false, false, false, false},
+	}
+
+	for _, test := range tests {
+		if got := test.code.IsTransient(); got != test.transient {
+			t.Errorf("%s IsTransient: wanted %t, got %t", test.code, test.transient, got)
+		}
+		if got := test.code.IsPermission(); got != test.permission {
+			t.Errorf("%s IsPermission: wanted %t, got %t", test.code, test.permission, got)
+		}
+		if got := test.code.IsValidation(); got != test.validation {
+			t.Errorf("%s IsValidation: wanted %t, got %t", test.code, test.validation, got)
+		}
+		if got := test.code.IsNotFound(); got != test.notFound {
+			t.Errorf("%s IsNotFound: wanted %t, got %t", test.code, test.notFound, got)
+		}
+	}
+
+	if got := bc.ErrorCodeDialogException.Category(); got != "Application" {
+		t.Errorf("wanted Application, got %s", got)
+	}
+}
+
+func TestAPIErrorCode(t *testing.T) {
+	client := newMockClient(t, 400, bc.ErrorResponse{
+		Error: bc.ErrorResponseError{Code: "Application_DialogException", Message: "Posting Date is not within your range of allowed posting dates."},
+	})
+	page := bc.NewAPIPage[fakeEntity](client, "fakeEntities")
+
+	_, err := page.Get(context.Background(), uuid.New(), bc.GetOptions{})
+
+	var apiErr bc.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected APIError, got %v", err)
+	}
+	if apiErr.Code != bc.ErrorCodeDialogException || !apiErr.Code.IsValidation() {
+		t.Errorf("unexpected code %s", apiErr.Code)
+	}
+}
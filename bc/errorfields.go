@@ -0,0 +1,73 @@
+package bc
+
+import (
+	"regexp"
+	"strings"
+)
+
+// fieldErrorPatterns match common BC validation messages. The first submatch is
+// the field and the last is the reason.
+var fieldErrorPatterns = []*regexp.Regexp{
+	// The field Customer No. of table Sales Header contains a value (X) that cannot be found in the related table (Customer).
+	regexp.MustCompile(`^The field (.+?) of table .+? ((?:contains|must|cannot|is) .+)$`),
+	// Customer No. must have a value in Sales Header: Document Type=Order, No.=1001.
+	// Quantity must not be negative.
+	regexp.MustCompile(`^([^,:]{1,50}?) (must (?:have|not|be) .+?)(?: in [^:]+:.*)?$`),
+	// Posting Date is not within your range of allowed posting dates.
+	regexp.MustCompile(`^([^,:]{1,50}?) (is not (?:within|valid|allowed).*)$`),
+	// You cannot change Currency Code because ...
+	regexp.MustCompile(`^You cannot (?:change|modify|rename) (?:the )?([^,:]{1,50}?) (because .+|when .+|.*)$`),
+}
+
+// parseFieldErrors extracts the field and reason from each sentence of a BC error
+// message. It is best-effort and returns nil if no known pattern matches.
+func parseFieldErrors(message string) []FieldError {
+	var fields []FieldError
+
+	for _, sentence := range splitSentences(message) {
+		for _, re := range fieldErrorPatterns {
+			m := re.FindStringSubmatch(sentence)
+			if m == nil {
+				continue
+			}
+			fields = append(fields, FieldError{
+				Field:  strings.TrimSpace(m[1]),
+				Reason: strings.TrimSpace(m[len(m)-1]),
+			})
+			break
+		}
+	}
+
+	return fields
+}
+
+// splitSentences splits on ". " while keeping abbreviations that end a field name,
+// like "No.", attached to the following word.
+func splitSentences(message string) []string {
+	message = strings.TrimSpace(message)
+	var sentences []string
+
+	start := 0
+	for i := 0; i < len(message); i++ {
+		if message[i] != '.' {
+			continue
+		}
+		end := i + 1
+		if end < len(message) && message[end] != ' ' {
+			continue
+		}
+		// "No." and single capitals are usually part of a field name.
+		word := message[strings.LastIndexByte(message[:i], ' ')+1 : i]
+		if word == "No" || (len(word) == 1 && word[0] >= 'A' && word[0] <= 'Z') {
+			continue
+		}
+		sentences = append(sentences, strings.TrimSpace(message[start:i]))
+		start = end
+	}
+
+	if rest := strings.TrimSpace(strings.TrimSuffix(message[start:], ".")); rest != "" {
+		sentences = append(sentences, rest)
+	}
+
+	return sentences
+}
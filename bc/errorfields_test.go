@@ -0,0 +1,43 @@
+package bc
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseFieldErrors(t *testing.T) {
+	type testCase struct {
+		message string
+		want    []FieldError
+	}
+
+	tests := []testCase{
+		{
+			"Posting Date is not within your range of allowed posting dates.",
+			[]FieldError{{"Posting Date", "is not within your range of allowed posting dates"}},
+		},
+		{
+			"Customer No. must have a value in Sales Header: Document Type=Order, No.=1001. It cannot be zero or empty.",
+			[]FieldError{{"Customer No.", "must have a value"}},
+		},
+		{
+			"The field Customer No. of table Sales Header contains a value (X) that cannot be found in the related table (Customer).",
+			[]FieldError{{"Customer No.", "contains a value (X) that cannot be found in the related table (Customer)"}},
+		},
+		{
+			"Quantity must not be negative.",
+			[]FieldError{{"Quantity", "must not be negative"}},
+		},
+		{
+			"Something unexpected happened",
+			nil,
+		},
+	}
+
+	for _, test := range tests {
+		got := parseFieldErrors(test.message)
+		if !slices.Equal(got, test.want) {
+			t.Errorf("%q: wanted %+v, got %+v", test.message, test.want, got)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package bc
+
+import (
+	"context"
+	"net/http"
+)
+
+// ErrorReport is a request that failed after any retries, with the context an alert needs.
+type ErrorReport struct {
+	Method string
+	URL    string
+	// EntitySetName and RecordID are parsed from the URL, see [SplitEntityPath].
+	EntitySetName string
+	RecordID      string
+	// StatusCode, Code and Message are empty if the request failed without a response.
+	StatusCode int
+	Code       ErrorCode
+	Message    string
+	// CorrelationID is from the error message, or the "ms-correlation-id" or
+	// "ms-correlation-x" header.
+	CorrelationID string
+	// Err is the [APIError] of the response, or the error of the request.
+	Err error
+}
+
+// ErrorReporter receives the failures of a [Client], e.g. to send them to an error
+// tracker. Set it with [WithErrorReporter]. It is called on the goroutine of the
+// request, so it should not block.
+type ErrorReporter interface {
+	ReportError(ctx context.Context, report ErrorReport)
+}
+
+// ErrorReporterFunc adapts a function to an [ErrorReporter].
+type ErrorReporterFunc func(ctx context.Context, report ErrorReport)
+
+// ReportError calls f.
+func (f ErrorReporterFunc) ReportError(ctx context.Context, report ErrorReport) {
+	f(ctx, report)
+}
+
+// reportError reports the result of [Client.Do] if it failed. Responses with a 404 are
+// not reported as they are expected, e.g. when checking if a record exists, and neither
+// are requests whose context is done.
+func (c *Client) reportError(r *http.Request, res *http.Response, err error) {
+	if c.errorReporter == nil || r.Context().Err() != nil {
+		return
+	}
+	if err == nil && (res.StatusCode < 400 || res.StatusCode == http.StatusNotFound) {
+		return
+	}
+
+	report := ErrorReport{Method: r.Method, URL: r.URL.String(), Err: err}
+	report.EntitySetName, report.RecordID = SplitEntityPath(r.URL.Path)
+	if err == nil {
+		code, message := peekErrorResponse(res)
+		apiErr := newBCAPIError(res.StatusCode, string(code), message, r)
+		report.StatusCode, report.Code, report.Message, report.Err = res.StatusCode, apiErr.Code, apiErr.Message, apiErr
+		report.CorrelationID = string(apiErr.CorrelationID)
+		if report.CorrelationID == "" {
+			report.CorrelationID = res.Header.Get("ms-correlation-id")
+		}
+		if report.CorrelationID == "" {
+			report.CorrelationID = res.Header.Get("ms-correlation-x")
+		}
+	}
+	c.errorReporter.ReportError(r.Context(), report)
+}
@@ -0,0 +1,55 @@
+package bc_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/erlorenz/bc-go/internal/bctest"
+	"github.com/google/uuid"
+)
+
+func TestWithErrorReporter(t *testing.T) {
+	locked := bctest.NewResponse(http.StatusBadRequest, bc.ErrorResponse{Error: bc.ErrorResponseError{
+		Code:    "Internal_RecordLocked",
+		Message: "The record is locked.  CorrelationId:  " + validGUID + ".",
+	}})
+	st := &bctest.SequenceTransport{Responses: []*http.Response{
+		errorResponse(http.StatusNotFound, "BadRequest_NotFound"),
+		locked,
+	}}
+
+	var reports []bc.ErrorReport
+	client := newSequenceClient(t, st, bc.WithErrorReporter(bc.ErrorReporterFunc(func(_ context.Context, r bc.ErrorReport) {
+		reports = append(reports, r)
+	})))
+	page := bc.NewAPIPage[fakeEntity](client, "fakeEntities")
+
+	id := uuid.New()
+	if _, err := page.Get(context.Background(), id, bc.GetOptions{}); !errors.Is(err, bc.ErrNotFound) {
+		t.Fatalf("wanted ErrNotFound, got %v", err)
+	}
+	if len(reports) != 0 {
+		t.Fatalf("wanted a 404 not to be reported, got %+v", reports)
+	}
+
+	// The body is still decoded into the error after the report
+	_, err := page.Update(context.Background(), id, nil, map[string]any{"Quantity": 1})
+	var apiErr bc.APIError
+	if !errors.As(err, &apiErr) || apiErr.Code != "Internal_RecordLocked" {
+		t.Fatalf("wanted the APIError, got %v", err)
+	}
+	if len(reports) != 1 {
+		t.Fatalf("wanted 1 report, got %d", len(reports))
+	}
+	r := reports[0]
+	if r.Method != http.MethodPatch || r.EntitySetName != "fakeEntities" || r.RecordID != id.String() ||
+		r.StatusCode != http.StatusBadRequest || r.Code != "Internal_RecordLocked" || r.CorrelationID != validGUID {
+		t.Errorf("unexpected report %+v", r)
+	}
+	if !errors.As(r.Err, &apiErr) {
+		t.Errorf("wanted the APIError in the report, got %v", r.Err)
+	}
+}
@@ -0,0 +1,188 @@
+package bc
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Expand builds a single $expand item with optional nested query options, e.g.
+// "salesOrderLines($select=lineNo,quantity;$filter=quantity gt 0)".
+// Create one with [NewExpand] and chain the option methods. The zero value
+// with a Name and no options renders as just the navigation property name.
+type Expand struct {
+	Name    string
+	Selects []string
+	Filters string
+	Orders  []string
+	TopN    int
+	Nested  []Expand
+}
+
+// NewExpand returns an [Expand] for the navigation property name.
+func NewExpand(name string) Expand {
+	return Expand{Name: name}
+}
+
+// Select sets the nested $select fields.
+func (e Expand) Select(fields ...string) Expand {
+	e.Selects = append(e.Selects[:len(e.Selects):len(e.Selects)], fields...)
+	return e
+}
+
+// Filter sets the nested $filter expression.
+func (e Expand) Filter(filter string) Expand {
+	e.Filters = filter
+	return e
+}
+
+// OrderBy sets the nested $orderby fields, e.g. "lineNo desc".
+func (e Expand) OrderBy(fields ...string) Expand {
+	e.Orders = append(e.Orders[:len(e.Orders):len(e.Orders)], fields...)
+	return e
+}
+
+// Top sets the nested $top.
+func (e Expand) Top(n int) Expand {
+	e.TopN = n
+	return e
+}
+
+// Expand adds nested expansions.
+func (e Expand) Expand(nested ...Expand) Expand {
+	e.Nested = append(e.Nested[:len(e.Nested):len(e.Nested)], nested...)
+	return e
+}
+
+// Validate checks the name, fields and nested expansions.
+func (e Expand) Validate() error {
+	var errs []string
+
+	if err := validateIdentifier(e.Name); err != nil {
+		errs = append(errs, fmt.Sprintf("name: %s", err))
+	}
+
+	for _, f := range e.Selects {
+		if err := validateIdentifier(f); err != nil {
+			errs = append(errs, fmt.Sprintf("select: %s", err))
+		}
+	}
+
+	for _, o := range e.Orders {
+		fields := strings.Fields(o)
+		var field, dir string
+		if len(fields) > 0 {
+			field, dir = fields[0], strings.Join(fields[1:], " ")
+		}
+		if err := validateIdentifier(field); err != nil {
+			errs = append(errs, fmt.Sprintf("orderby: %s", err))
+		}
+		if dir != "" && dir != "asc" && dir != "desc" {
+			errs = append(errs, fmt.Sprintf("orderby: invalid direction %q", dir))
+		}
+	}
+
+	if hasUnquoted(e.Filters, ';') {
+		errs = append(errs, "filter: cannot contain ';'")
+	}
+
+	if e.TopN < 0 {
+		errs = append(errs, fmt.Sprintf("top: must not be negative, got %d", e.TopN))
+	}
+
+	for _, n := range e.Nested {
+		if err := n.Validate(); err != nil {
+			errs = append(errs, fmt.Sprintf("expand %s: %s", n.Name, err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid expand: [ %s ]", strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+// String renders the expand item in OData syntax. It does not validate.
+func (e Expand) String() string {
+	var opts []string
+
+	if len(e.Selects) > 0 {
+		opts = append(opts, "$select="+strings.Join(e.Selects, ","))
+	}
+	if e.Filters != "" {
+		opts = append(opts, "$filter="+e.Filters)
+	}
+	if len(e.Orders) > 0 {
+		opts = append(opts, "$orderby="+strings.Join(e.Orders, ","))
+	}
+	if e.TopN > 0 {
+		opts = append(opts, "$top="+strconv.Itoa(e.TopN))
+	}
+	if len(e.Nested) > 0 {
+		opts = append(opts, "$expand="+joinExpands(e.Nested))
+	}
+
+	if len(opts) == 0 {
+		return e.Name
+	}
+
+	return fmt.Sprintf("%s(%s)", e.Name, strings.Join(opts, ";"))
+}
+
+// RenderExpands validates each expand and returns a slice of rendered strings
+// that can be used in [ListOptions].Expand or [GetOptions].Expand.
+func RenderExpands(expands ...Expand) ([]string, error) {
+	var errs error
+	s := make([]string, 0, len(expands))
+
+	for _, e := range expands {
+		if err := e.Validate(); err != nil {
+			errs = errors.Join(errs, err)
+			continue
+		}
+		s = append(s, e.String())
+	}
+
+	if errs != nil {
+		return nil, errs
+	}
+	return s, nil
+}
+
+func joinExpands(expands []Expand) string {
+	s := make([]string, len(expands))
+	for i, e := range expands {
+		s[i] = e.String()
+	}
+	return strings.Join(s, ",")
+}
+
+// validateIdentifier checks that s is usable as an OData property name.
+func validateIdentifier(s string) error {
+	if err := stringNotEmpty(s); err != nil {
+		return err
+	}
+	if strings.ContainsAny(s, " ,;()='/$") {
+		return fmt.Errorf("%q is not a valid identifier", s)
+	}
+	return nil
+}
+
+// hasUnquoted returns true if s has the byte outside of the quoted string literals,
+// where a quote is escaped by doubling it.
+func hasUnquoted(s string, c byte) bool {
+	quoted := false
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\'':
+			// An escaped quote toggles twice
+			quoted = !quoted
+		case c:
+			if !quoted {
+				return true
+			}
+		}
+	}
+	return false
+}
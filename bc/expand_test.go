@@ -0,0 +1,86 @@
+package bc_test
+
+import (
+	"testing"
+
+	"github.com/erlorenz/bc-go/bc"
+)
+
+func TestExpandString(t *testing.T) {
+	type testCase struct {
+		name   string
+		expand bc.Expand
+		want   string
+	}
+
+	tests := []testCase{
+		{"name only", bc.NewExpand("customer"), "customer"},
+		{
+			"select and filter",
+			bc.NewExpand("salesOrderLines").Select("lineNo", "quantity").Filter("quantity gt 0"),
+			"salesOrderLines($select=lineNo,quantity;$filter=quantity gt 0)",
+		},
+		{
+			"orderby and top",
+			bc.NewExpand("salesOrderLines").OrderBy("lineNo desc").Top(5),
+			"salesOrderLines($orderby=lineNo desc;$top=5)",
+		},
+		{
+			"nested",
+			bc.NewExpand("salesOrderLines").Select("lineNo").Expand(bc.NewExpand("item").Select("number")),
+			"salesOrderLines($select=lineNo;$expand=item($select=number))",
+		},
+	}
+
+	for _, test := range tests {
+		got := test.expand.String()
+		if got != test.want {
+			t.Errorf("%s: wanted %s, got %s", test.name, test.want, got)
+		}
+	}
+}
+
+func TestExpandValidate(t *testing.T) {
+	type testCase struct {
+		name   string
+		expand bc.Expand
+		want   bool //should pass
+	}
+
+	tests := []testCase{
+		{"valid", bc.NewExpand("salesOrderLines").Select("lineNo").OrderBy("lineNo asc"), true},
+		{"empty name", bc.NewExpand(""), false},
+		{"bad select", bc.NewExpand("lines").Select("line No"), false},
+		{"bad direction", bc.NewExpand("lines").OrderBy("lineNo sideways"), false},
+		{"negative top", bc.NewExpand("lines").Top(-1), false},
+		{"semicolon in filter", bc.NewExpand("lines").Filter("a eq 1;$top=1"), false},
+		{"semicolon in filter literal", bc.NewExpand("lines").Filter("name eq 'a;b'"), true},
+		{"semicolon after escaped quote", bc.NewExpand("lines").Filter("name eq 'it''s';$top=1"), false},
+		{"extra spaces in orderby", bc.NewExpand("lines").OrderBy("name  desc"), true},
+		{"extra words in orderby", bc.NewExpand("lines").OrderBy("name desc asc"), false},
+		{"bad nested", bc.NewExpand("lines").Expand(bc.NewExpand("")), false},
+	}
+
+	for _, test := range tests {
+		err := test.expand.Validate()
+		got := err == nil
+		if test.want != got {
+			t.Errorf("%s: wanted %t, got %t: %s", test.name, test.want, got, err)
+		}
+	}
+}
+
+func TestRenderExpands(t *testing.T) {
+	got, err := bc.RenderExpands(bc.NewExpand("customer"), bc.NewExpand("salesOrderLines").Top(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(got) != 2 || got[0] != "customer" || got[1] != "salesOrderLines($top=1)" {
+		t.Errorf("unexpected result %v", got)
+	}
+
+	if _, err := bc.RenderExpands(bc.NewExpand("")); err == nil {
+		t.Error("expected error, got nil")
+	}
+}
@@ -0,0 +1,148 @@
+package bc
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// GatewayKind is the cause of a 502, 503 or 504 response, told apart by the body that
+// the BC gateway or service returns with it.
+type GatewayKind string
+
+const (
+	// GatewayUnknown is a gateway failure without a recognized body.
+	GatewayUnknown GatewayKind = "unknown"
+	// GatewayTenantMoving means the tenant is moved to another cluster, which takes minutes.
+	GatewayTenantMoving GatewayKind = "tenantMoving"
+	// GatewayEnvironmentUpdating means the environment is being updated, which takes hours.
+	GatewayEnvironmentUpdating GatewayKind = "environmentUpdating"
+	// GatewayCapacity means the service is too busy to accept the request.
+	GatewayCapacity GatewayKind = "capacity"
+	// GatewayTimeout means the gateway gave up waiting for BC, which may still finish the request.
+	GatewayTimeout GatewayKind = "timeout"
+)
+
+var (
+	// ErrTenantMoving is matched by errors.Is for a [GatewayError] or [APIError] of [GatewayTenantMoving].
+	ErrTenantMoving = errors.New("tenant moving")
+	// ErrEnvironmentUpdating is matched by errors.Is for a [GatewayError] or [APIError] of [GatewayEnvironmentUpdating].
+	ErrEnvironmentUpdating = errors.New("environment updating")
+	// ErrCapacity is matched by errors.Is for a [GatewayError] or [APIError] of [GatewayCapacity].
+	ErrCapacity = errors.New("service capacity exceeded")
+	// ErrGatewayTimeout is matched by errors.Is for a [GatewayError] or [APIError] of [GatewayTimeout].
+	ErrGatewayTimeout = errors.New("gateway timeout")
+)
+
+// gatewayFingerprints are the phrases of the bodies of each kind, in the order they
+// are checked. They are matched without case in the JSON message or the text of the
+// HTML page. Capacity only has markers of BC, as it is [GatewayKind.Rejected] and a
+// generic "try again later" page of a proxy may come after a POST was processed.
+var gatewayFingerprints = []struct {
+	kind    GatewayKind
+	phrases []string
+}{
+	{GatewayTenantMoving, []string{"tenant is being moved", "tenant is currently being moved", "tenant move", "being relocated", "tenantmove"}},
+	{GatewayEnvironmentUpdating, []string{"being updated", "being upgraded", "upgrade is in progress", "update is in progress", "under maintenance", "environmentupdate", "upgradeinprogress"}},
+	{GatewayCapacity, []string{"serverbusy", "too many concurrent", "maximum number of concurrent"}},
+	{GatewayTimeout, []string{"timed out", "timeout", "time-out"}},
+}
+
+var htmlTag = regexp.MustCompile(`<[^>]*>`)
+
+// FingerprintGateway classifies the body of a 502, 503 or 504 response, a BC error
+// response or an HTML page of the gateway. It returns an empty kind for other status
+// codes, and [GatewayTimeout] for an unrecognized 504.
+func FingerprintGateway(statusCode int, body []byte) GatewayKind {
+	switch statusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+	default:
+		return ""
+	}
+	text := strings.ToLower(htmlTag.ReplaceAllString(string(body), " "))
+	for _, fp := range gatewayFingerprints {
+		for _, phrase := range fp.phrases {
+			if strings.Contains(text, phrase) {
+				return fp.kind
+			}
+		}
+	}
+	if statusCode == http.StatusGatewayTimeout {
+		return GatewayTimeout
+	}
+	return GatewayUnknown
+}
+
+// Retryable returns false for [GatewayEnvironmentUpdating], as the update takes longer
+// than any retry backoff; register the update with [WithMaintenanceSchedule] instead.
+func (k GatewayKind) Retryable() bool {
+	return k != GatewayEnvironmentUpdating
+}
+
+// Rejected returns true if the request was not processed, so even a POST can be sent
+// again. A timeout or an unknown failure may have been processed.
+func (k GatewayKind) Rejected() bool {
+	switch k {
+	case GatewayTenantMoving, GatewayEnvironmentUpdating, GatewayCapacity:
+		return true
+	}
+	return false
+}
+
+// sentinel returns the error matched by errors.Is for the kind.
+func (k GatewayKind) sentinel() error {
+	switch k {
+	case GatewayTenantMoving:
+		return ErrTenantMoving
+	case GatewayEnvironmentUpdating:
+		return ErrEnvironmentUpdating
+	case GatewayCapacity:
+		return ErrCapacity
+	case GatewayTimeout:
+		return ErrGatewayTimeout
+	}
+	return nil
+}
+
+// GatewayError is returned for a 502, 503 or 504 response with a body that is not a
+// BC error response, e.g. the HTML page of the gateway.
+type GatewayError struct {
+	Kind       GatewayKind
+	StatusCode int
+	// Body is the start of the response body, to identify new kinds.
+	Body    string
+	Request *http.Request
+}
+
+func (err GatewayError) Error() string {
+	return fmt.Sprintf("[%d gateway %s] %s", err.StatusCode, err.Kind, err.Body)
+}
+
+// Is returns true if target is the error of the Kind, e.g. ErrEnvironmentUpdating.
+func (err GatewayError) Is(target error) bool {
+	return target != nil && target == err.Kind.sentinel()
+}
+
+// Gateway returns the [GatewayKind] of the error message, or an empty kind if the
+// StatusCode is not 502, 503 or 504.
+func (err APIError) Gateway() GatewayKind {
+	return FingerprintGateway(err.StatusCode, []byte(string(err.Code)+" "+err.Message))
+}
+
+// newGatewayError returns the [GatewayError] of the response body.
+func newGatewayError(statusCode int, body []byte, r *http.Request) GatewayError {
+	const maxBody = 512
+	text := strings.Join(strings.Fields(htmlTag.ReplaceAllString(string(body), " ")), " ")
+	if len(text) > maxBody {
+		// Cut at a rune boundary so the body stays valid UTF-8
+		i := maxBody
+		for i > 0 && !utf8.RuneStart(text[i]) {
+			i--
+		}
+		text = text[:i]
+	}
+	return GatewayError{Kind: FingerprintGateway(statusCode, body), StatusCode: statusCode, Body: text, Request: r}
+}
@@ -0,0 +1,124 @@
+package bc_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/erlorenz/bc-go/internal/bctest"
+	"github.com/google/uuid"
+)
+
+func htmlResponse(status int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: status,
+		Header:     http.Header{"Content-Type": {"text/html"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+	}
+}
+
+func TestFingerprintGateway(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		want   bc.GatewayKind
+	}{
+		{"TenantMoving", 503, `<html><body><h1>Service Unavailable</h1><p>The tenant is currently being moved.</p></body></html>`, bc.GatewayTenantMoving},
+		{"EnvironmentUpdating", 503, `{"error":{"code":"Unavailable","message":"The environment is being updated. Please try again later."}}`, bc.GatewayEnvironmentUpdating},
+		{"Capacity", 503, `{"error":{"code":"Internal_ServerBusy","message":"The maximum number of concurrent requests has been reached."}}`, bc.GatewayCapacity},
+		{"GenericUnavailable", 503, `<html><head><title>503 Service Unavailable</title></head><body><p>The server is temporarily unable to service your request due to capacity problems. Please try again later.</p></body></html>`, bc.GatewayUnknown},
+		{"Timeout", 504, `<html><body>504 Gateway Time-out</body></html>`, bc.GatewayTimeout},
+		{"UnknownTimeout", 504, ``, bc.GatewayTimeout},
+		{"Unknown", 502, `<html><body>Bad Gateway</body></html>`, bc.GatewayUnknown},
+		{"NotGateway", 500, `The environment is being updated.`, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := bc.FingerprintGateway(tt.status, []byte(tt.body)); got != tt.want {
+				t.Errorf("wanted %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestGatewayError(t *testing.T) {
+	st := &bctest.SequenceTransport{Responses: []*http.Response{
+		htmlResponse(http.StatusServiceUnavailable, `<html><body><p>Environment Production is being upgraded.</p></body></html>`),
+	}}
+	client := newSequenceClient(t, st)
+
+	_, err := bc.NewAPIPage[fakeEntity](client, "fakeEntities").Get(context.Background(), uuid.New(), bc.GetOptions{})
+	var gerr bc.GatewayError
+	if !errors.As(err, &gerr) || gerr.Kind != bc.GatewayEnvironmentUpdating || gerr.Body != "Environment Production is being upgraded." {
+		t.Fatalf("wanted a GatewayError, got %v", err)
+	}
+	if !errors.Is(err, bc.ErrEnvironmentUpdating) || errors.Is(err, bc.ErrTenantMoving) {
+		t.Errorf("unexpected sentinel match for %v", err)
+	}
+
+	// The body is cut at 512 bytes, which falls inside the two-byte "é"
+	st = &bctest.SequenceTransport{Responses: []*http.Response{
+		htmlResponse(http.StatusBadGateway, "<p>"+strings.Repeat("a", 511)+"é</p>"),
+	}}
+	client = newSequenceClient(t, st)
+	_, err = bc.NewAPIPage[fakeEntity](client, "fakeEntities").Get(context.Background(), uuid.New(), bc.GetOptions{})
+	if !errors.As(err, &gerr) || !utf8.ValidString(gerr.Body) || gerr.Body != strings.Repeat("a", 511) {
+		t.Errorf("wanted the body cut at a rune boundary, got %q", gerr.Body)
+	}
+
+	apiErr := bc.APIError{StatusCode: http.StatusServiceUnavailable, Message: "The tenant is being moved to another cluster."}
+	if !errors.Is(apiErr, bc.ErrTenantMoving) || apiErr.Gateway() != bc.GatewayTenantMoving {
+		t.Errorf("wanted the APIError to match ErrTenantMoving, got %q", apiErr.Gateway())
+	}
+}
+
+func TestRetryGateway(t *testing.T) {
+	post := &http.Request{Method: http.MethodPost}
+	rc := bc.DefaultRetryClassifier{BaseDelay: time.Second, MaxDelay: time.Minute}
+	tests := []struct {
+		name    string
+		gateway bc.GatewayKind
+		request *http.Request
+		retry   bool
+		backoff time.Duration
+	}{
+		{"Updating", bc.GatewayEnvironmentUpdating, nil, false, 0},
+		{"TenantMoving", bc.GatewayTenantMoving, nil, true, time.Minute},
+		{"CapacityPost", bc.GatewayCapacity, post, true, time.Second},
+		{"TimeoutPost", bc.GatewayTimeout, post, false, 0},
+		{"Timeout", bc.GatewayTimeout, nil, true, time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := rc.Classify(bc.RetryAttempt{Attempt: 1, Request: tt.request, StatusCode: 503, Gateway: tt.gateway, Response: &http.Response{}})
+			if d.Retry != tt.retry || (d.Retry && d.Backoff != tt.backoff) {
+				t.Errorf("wanted retry %t after %s, got %+v", tt.retry, tt.backoff, d)
+			}
+		})
+	}
+
+	st := &bctest.SequenceTransport{Responses: []*http.Response{
+		htmlResponse(http.StatusServiceUnavailable, `<p>Too many concurrent requests.</p>`),
+		bctest.NewResponse(http.StatusCreated, map[string]any{"ID": validGUID}),
+	}}
+	client := newSequenceClient(t, st, bc.WithRetryClassifier(bc.DefaultRetryClassifier{BaseDelay: time.Millisecond}))
+	if _, err := bc.NewAPIPage[fakeEntity](client, "fakeEntities").Create(context.Background(), map[string]any{}, bc.GetOptions{}); err != nil {
+		t.Fatalf("wanted the rejected POST to be retried, got %v", err)
+	}
+
+	st = &bctest.SequenceTransport{Responses: []*http.Response{
+		htmlResponse(http.StatusServiceUnavailable, `<p>Server too busy, please try again later.</p>`),
+		bctest.NewResponse(http.StatusCreated, map[string]any{"ID": validGUID}),
+	}}
+	client = newSequenceClient(t, st, bc.WithRetryClassifier(bc.DefaultRetryClassifier{BaseDelay: time.Millisecond}))
+	if _, err := bc.NewAPIPage[fakeEntity](client, "fakeEntities").Create(context.Background(), map[string]any{}, bc.GetOptions{}); err == nil {
+		t.Fatal("wanted the POST of a generic 503 page not to be retried")
+	}
+}
@@ -0,0 +1,126 @@
+package bc
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+)
+
+// HealthStatus is the result of a [Client.Ping].
+type HealthStatus string
+
+const (
+	HealthOK HealthStatus = "ok"
+	// HealthDNS means the host could not be resolved.
+	HealthDNS HealthStatus = "dns"
+	// HealthTLS means the TLS handshake or certificate verification failed.
+	HealthTLS HealthStatus = "tls"
+	// HealthTimeout means the context or the http.Client timed out.
+	HealthTimeout HealthStatus = "timeout"
+	// HealthNetwork is any other connection error, e.g. a refused connection or a proxy error.
+	HealthNetwork HealthStatus = "network"
+	// HealthAuth means a token could not be acquired or BC rejected it with a 401.
+	HealthAuth HealthStatus = "auth"
+	// HealthPermission means BC returned a 403, usually missing permission sets.
+	HealthPermission HealthStatus = "permission"
+	// HealthNotFound means the environment or company does not exist.
+	HealthNotFound HealthStatus = "notfound"
+	// HealthUnavailable is any other error response, e.g. throttling or maintenance.
+	HealthUnavailable HealthStatus = "unavailable"
+)
+
+// HealthReport is returned by [Client.Ping].
+type HealthReport struct {
+	Status HealthStatus
+	// Latency is the time of the request, including acquiring the token.
+	Latency time.Duration
+	// StatusCode is 0 if no response was received.
+	StatusCode int
+	CheckedAt  time.Time
+	// Err is the error that caused the Status, or nil.
+	Err error
+}
+
+// Healthy returns true if the Status is [HealthOK].
+func (hr HealthReport) Healthy() bool {
+	return hr.Status == HealthOK
+}
+
+// Ping reads the id of the company to check that BC is reachable, the token is accepted
+// and the company exists, e.g. for a readiness probe. The error is the same as
+// the Err of the report, so failures can be handled either way.
+func (c *Client) Ping(ctx context.Context) (HealthReport, error) {
+	u := *c.baseURL
+	u.RawQuery = "$select=id"
+
+	report := HealthReport{CheckedAt: time.Now()}
+	start := time.Now()
+	defer func() {
+		c.logger.Debug("Ping finished.", "status", report.Status, "latency", report.Latency)
+	}()
+
+	req, err := c.newRequest(ctx, u.String(), RequestOptions{Method: http.MethodGet})
+	if err != nil {
+		report.Latency = time.Since(start)
+		report.Status, report.Err = HealthNetwork, fmt.Errorf("failed to create Request: %w", err)
+		if errors.As(err, new(tokenError)) {
+			report.Status = HealthAuth
+		}
+		return report, report.Err
+	}
+
+	res, err := c.Do(req)
+	report.Latency = time.Since(start)
+	if err != nil {
+		report.Status, report.Err = classifyTransportError(err), fmt.Errorf("failed during request: %w", err)
+		return report, report.Err
+	}
+
+	report.StatusCode = res.StatusCode
+	if err := DecodeNoContent(res); err != nil {
+		switch res.StatusCode {
+		case http.StatusUnauthorized:
+			report.Status = HealthAuth
+		case http.StatusForbidden:
+			report.Status = HealthPermission
+		case http.StatusNotFound:
+			report.Status = HealthNotFound
+		default:
+			report.Status = HealthUnavailable
+		}
+		report.Err = fmt.Errorf("error from BC API: %w", err)
+		return report, report.Err
+	}
+
+	report.Status = HealthOK
+	return report, nil
+}
+
+// classifyTransportError returns the HealthStatus for an error from the http.Client.
+func classifyTransportError(err error) HealthStatus {
+	var (
+		dnsErr       *net.DNSError
+		netErr       net.Error
+		recordErr    tls.RecordHeaderError
+		verifyErr    *tls.CertificateVerificationError
+		authorityErr x509.UnknownAuthorityError
+		hostnameErr  x509.HostnameError
+		invalidErr   x509.CertificateInvalidError
+	)
+
+	switch {
+	case errors.As(err, &dnsErr):
+		return HealthDNS
+	case errors.As(err, &recordErr), errors.As(err, &verifyErr), errors.As(err, &authorityErr),
+		errors.As(err, &hostnameErr), errors.As(err, &invalidErr):
+		return HealthTLS
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return HealthTimeout
+	}
+	return HealthNetwork
+}
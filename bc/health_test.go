@@ -0,0 +1,85 @@
+package bc_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/erlorenz/bc-go/internal/bctest"
+)
+
+func TestPing(t *testing.T) {
+	table := []struct {
+		name       string
+		response   *http.Response
+		want       bc.HealthStatus
+		statusCode int
+	}{
+		{"OK", bctest.NewResponse(200, map[string]any{"id": validGUID}), bc.HealthOK, 200},
+		{"Unauthorized", errorResponse(401, "Authentication_InvalidCredentials"), bc.HealthAuth, 401},
+		{"Forbidden", errorResponse(403, "Internal_PermissionDenied"), bc.HealthPermission, 403},
+		{"CompanyNotFound", errorResponse(404, "Internal_CompanyNotFound"), bc.HealthNotFound, 404},
+		{"Throttled", errorResponse(429, "Application_TooManyRequests"), bc.HealthUnavailable, 429},
+	}
+
+	for _, v := range table {
+		t.Run(v.name, func(t *testing.T) {
+			st := &bctest.SequenceTransport{Responses: []*http.Response{v.response}}
+			client := newSequenceClient(t, st)
+
+			report, err := client.Ping(context.Background())
+			if report.Status != v.want || report.StatusCode != v.statusCode {
+				t.Errorf("wanted %s %d, got %s %d", v.want, v.statusCode, report.Status, report.StatusCode)
+			}
+			if report.Healthy() != (err == nil) || !errors.Is(err, report.Err) {
+				t.Errorf("wanted error to match report, got %v and %v", err, report.Err)
+			}
+			if got := st.Requests[0].URL.RawQuery; got != "$select=id" {
+				t.Errorf("wanted $select=id, got %s", got)
+			}
+		})
+	}
+}
+
+func TestPingTransportErrors(t *testing.T) {
+	table := []struct {
+		name string
+		err  error
+		want bc.HealthStatus
+	}{
+		{"DNS", &net.DNSError{Err: "no such host", Name: "api.businesscentral.dynamics.com", IsNotFound: true}, bc.HealthDNS},
+		{"Timeout", context.DeadlineExceeded, bc.HealthTimeout},
+		{"Refused", &net.OpError{Op: "dial", Err: errors.New("connection refused")}, bc.HealthNetwork},
+	}
+
+	for _, v := range table {
+		t.Run(v.name, func(t *testing.T) {
+			httpClient := &http.Client{Transport: bctest.MockTransport{Error: v.err}}
+			client, err := bc.NewClient(fakeConfig, bc.WithAuthClient(fakeTokenGetter{}), bc.WithHTTPClient(httpClient))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			report, err := client.Ping(context.Background())
+			if report.Status != v.want || err == nil {
+				t.Errorf("wanted %s with error, got %s %v", v.want, report.Status, err)
+			}
+		})
+	}
+}
+
+func TestPingTokenError(t *testing.T) {
+	tg := &countingTokenGetter{err: errors.New("invalid client secret")}
+	client, err := bc.NewClient(fakeConfig, bc.WithAuthClient(tg))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := client.Ping(context.Background())
+	if report.Status != bc.HealthAuth || !errors.Is(err, tg.err) {
+		t.Errorf("wanted auth failure, got %s %v", report.Status, err)
+	}
+}
@@ -0,0 +1,116 @@
+package bc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// DefaultMaxURLLength is the longest request URL [Client.NewRequest] creates unless changed
+// with [WithMaxURLLength]. The gateway in front of BC rejects long URLs with an unhelpful
+// error; the exact limit is not documented so this is conservative.
+const DefaultMaxURLLength = 4096
+
+// ErrURLTooLong is returned by [Client.NewRequest] when the URL is longer than the maximum,
+// usually because of a long $filter. Use [APIPage.ListIn] to split it into several requests.
+var ErrURLTooLong = errors.New("request url too long")
+
+// checkURLLength returns an error matching [ErrURLTooLong] if the URL is too long.
+func (c *Client) checkURLLength(rawURL string) error {
+	limit := c.maxURLLength
+	if limit == 0 {
+		limit = DefaultMaxURLLength
+	}
+	if limit > 0 && len(rawURL) > limit {
+		return fmt.Errorf("%w: %d characters, maximum is %d", ErrURLTooLong, len(rawURL), limit)
+	}
+	return nil
+}
+
+// ListIn lists the records where the field is one of the values, e.g. to reconcile thousands
+// of records by number. The values are OData literals, so strings must be quoted like "'10000'".
+// If the URL would be too long the values are split over several requests and the records
+// are merged in the order of the requests. The Filter of the options is combined with "and".
+// Top and Skip cannot be used if the values are split.
+func (a *APIPage[T]) ListIn(ctx context.Context, field string, values []string, opts ListOptions) ([]T, error) {
+	if err := validateIdentifier(strings.ReplaceAll(field, "/", "")); err != nil {
+		return nil, fmt.Errorf("list in: %w", err)
+	}
+
+	values = uniqueValues(values)
+	if len(values) == 0 {
+		return []T{}, nil
+	}
+
+	chunks, err := a.splitIn(field, values, opts)
+	if err != nil {
+		return nil, err
+	}
+	if len(chunks) > 1 && (opts.Top != 0 || opts.Skip != 0) {
+		return nil, fmt.Errorf("list in: cannot use top or skip when %d values are split into %d requests", len(values), len(chunks))
+	}
+	if len(chunks) > 1 {
+		a.client.logger.Debug("Splitting filter into requests.", "values", len(values), "requests", len(chunks))
+	}
+
+	var records []T
+	for _, chunk := range chunks {
+		list, err := a.List(ctx, inListOptions(field, chunk, opts))
+		if err != nil {
+			return records, err
+		}
+		records = append(records, list...)
+	}
+	return records, nil
+}
+
+// splitIn halves the values until the URL of each chunk fits.
+func (a *APIPage[T]) splitIn(field string, values []string, opts ListOptions) ([][]string, error) {
+	chunkOpts := inListOptions(field, values, opts)
+	rawURL := a.client.requestURL(a.entitySetName, uuid.Nil, chunkOpts.BuildQueryParams(a.BaseFilter, a.BaseExpand))
+
+	err := a.client.checkURLLength(rawURL)
+	if err == nil {
+		return [][]string{values}, nil
+	}
+	if len(values) == 1 {
+		return nil, fmt.Errorf("list in: %w", err)
+	}
+
+	mid := len(values) / 2
+	first, err := a.splitIn(field, values[:mid], opts)
+	if err != nil {
+		return nil, err
+	}
+	second, err := a.splitIn(field, values[mid:], opts)
+	if err != nil {
+		return nil, err
+	}
+	return append(first, second...), nil
+}
+
+// uniqueValues returns the values without duplicates, in order.
+func uniqueValues(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	unique := make([]string, 0, len(values))
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			unique = append(unique, v)
+		}
+	}
+	return unique
+}
+
+// inListOptions returns the options with the "in" filter added.
+func inListOptions(field string, values []string, opts ListOptions) ListOptions {
+	in := fmt.Sprintf("%s in (%s)", field, strings.Join(values, ","))
+	if opts.Filter != "" {
+		in = fmt.Sprintf("(%s) and %s", opts.Filter, in)
+	}
+	opts.Filter = in
+	return opts
+}
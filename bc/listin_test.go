@@ -0,0 +1,90 @@
+package bc_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/erlorenz/bc-go/internal/bctest"
+	"github.com/google/uuid"
+)
+
+func TestNewRequestURLTooLong(t *testing.T) {
+	client := newSequenceClient(t, &bctest.SequenceTransport{}, bc.WithMaxURLLength(300))
+
+	_, err := client.NewRequest(context.Background(), bc.RequestOptions{
+		Method:        http.MethodGet,
+		EntitySetName: "customers",
+		QueryParams:   bc.QueryParams{{Key: "$filter", Value: "number eq '" + strings.Repeat("1", 300) + "'"}},
+	})
+	if !errors.Is(err, bc.ErrURLTooLong) {
+		t.Errorf("wanted ErrURLTooLong, got %v", err)
+	}
+}
+
+func TestListIn(t *testing.T) {
+	values := make([]string, 40)
+	for i := range values {
+		values[i] = fmt.Sprintf("'C%04d'", i)
+	}
+	values = append(values, values[0])
+
+	var responses []*http.Response
+	for i := range 8 {
+		responses = append(responses, bctest.NewResponse(200, map[string]any{"value": []map[string]any{{"ID": uuid.NewString(), "Number": fmt.Sprint(i)}}}))
+	}
+	st := &bctest.SequenceTransport{Responses: responses}
+	client := newSequenceClient(t, st, bc.WithMaxURLLength(400))
+	page := bc.NewAPIPage[fakeEntity](client, "fakeEntities")
+
+	records, err := page.ListIn(context.Background(), "number", values, bc.ListOptions{Filter: "blocked eq false"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if st.Count() < 2 || len(records) != st.Count() {
+		t.Fatalf("wanted the values split with records merged, got %d requests and %d records", st.Count(), len(records))
+	}
+
+	seen := map[string]int{}
+	for i, req := range st.Requests {
+		if len(req.URL.String()) > 400 {
+			t.Errorf("request %d is %d characters", i, len(req.URL.String()))
+		}
+		filter := req.URL.Query().Get("$filter")
+		if !strings.HasPrefix(filter, "(blocked eq false) and number in (") {
+			t.Errorf("unexpected filter %s", filter)
+		}
+		for _, v := range values[:40] {
+			if strings.Contains(filter, v) {
+				seen[v]++
+			}
+		}
+		if records[i].Number != fmt.Sprint(i) {
+			t.Errorf("records are not in request order: %d is %s", i, records[i].Number)
+		}
+	}
+	for _, v := range values[:40] {
+		if seen[v] != 1 {
+			t.Errorf("%s was sent %d times", v, seen[v])
+		}
+	}
+}
+
+func TestListInTopWithSplit(t *testing.T) {
+	client := newSequenceClient(t, &bctest.SequenceTransport{}, bc.WithMaxURLLength(300))
+	page := bc.NewAPIPage[fakeEntity](client, "fakeEntities")
+
+	values := make([]string, 100)
+	for i := range values {
+		values[i] = fmt.Sprintf("'A%d'", i)
+	}
+
+	if _, err := page.ListIn(context.Background(), "number", values, bc.ListOptions{Top: 5}); err == nil {
+		t.Error("expected error for top with split values")
+	}
+}
@@ -0,0 +1,102 @@
+package bc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ListPage gets a single page of records. With an empty nextLink it requests the first page
+// with the list options. Otherwise it requests the nextLink of the previous page and
+// only MaxPageSize is used. The returned NextLink is empty on the last page.
+// To return pages to your own consumers, use [APIListResponse.Cursor] and set it as the
+// Cursor of the options instead of the nextLink.
+func (a *APIPage[T]) ListPage(ctx context.Context, nextLink string, opts ListOptions) (APIListResponse[T], error) {
+	var list APIListResponse[T]
+
+	if _, err := opts.Cursor.SkipToken(); err != nil {
+		return list, err
+	}
+
+	header := http.Header{}
+	if opts.MaxPageSize > 0 {
+		header.Set("Prefer", "odata.maxpagesize="+strconv.Itoa(opts.MaxPageSize))
+	}
+
+	var req *http.Request
+	var err error
+	if nextLink == "" {
+		req, err = a.client.NewRequest(ctx, RequestOptions{
+			Method:        http.MethodGet,
+			EntitySetName: a.entitySetName,
+			QueryParams:   opts.BuildQueryParams(a.BaseFilter, a.BaseExpand),
+			Header:        header,
+		})
+	} else {
+		req, err = a.client.NewRequestURL(ctx, http.MethodGet, nextLink, nil)
+		if err == nil {
+			for k, v := range header {
+				req.Header[k] = v
+			}
+		}
+	}
+	if err != nil {
+		return list, fmt.Errorf("failed to create Request: %w", err)
+	}
+
+	res, err := a.client.Do(req)
+	if err != nil {
+		return list, fmt.Errorf("failed during request: %w", err)
+	}
+
+	list, err = decode[APIListResponse[T]](a.client, res)
+	if err != nil {
+		var srvErr APIError
+		if errors.As(err, &srvErr) {
+			a.client.logger.Debug("API server returned error response.", "error", srvErr)
+			return list, fmt.Errorf("error from BC API: %w", srvErr)
+		}
+
+		a.client.logger.Debug("Unable to decode response.", "error", err)
+		return list, fmt.Errorf("decode response: %w", err)
+	}
+
+	if err := validateList(a.client, a.entitySetName, list.Value); err != nil {
+		return list, err
+	}
+
+	if list.NextLink != "" && req.URL.Query().Get("$orderby") == "" {
+		a.client.warnings.report(ctx, Warning{
+			Kind:          WarningOrdering,
+			Message:       "paging without $orderby can skip or duplicate rows",
+			Method:        req.Method,
+			URL:           req.URL.String(),
+			EntitySetName: a.entitySetName,
+		})
+	}
+
+	return list, nil
+}
+
+// withTiebreaker returns a copy of the query params with the field appended to the $orderby,
+// unless it is already ordered by the field.
+func withTiebreaker(qp QueryParams, field string) QueryParams {
+	orderBy := qp.Get("$orderby")
+	var fields []string
+	if orderBy != "" {
+		fields = strings.Split(orderBy, ",")
+	}
+	for _, f := range fields {
+		if name, _, _ := strings.Cut(strings.TrimSpace(f), " "); name == field {
+			return qp
+		}
+	}
+
+	qp = slices.Clone(qp)
+	qp.Set("$orderby", strings.Join(append(fields, field), ","))
+	return qp
+}
@@ -0,0 +1,42 @@
+package bc_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/erlorenz/bc-go/internal/bctest"
+)
+
+func TestListPage(t *testing.T) {
+	next := "https://api.businesscentral.dynamics.com/v2.0/next?$skiptoken=abc"
+	st := &bctest.SequenceTransport{Responses: []*http.Response{
+		bctest.NewResponse(200, map[string]any{"value": []map[string]any{{"ID": validGUID}}, "@odata.nextLink": next}),
+		bctest.NewResponse(200, map[string]any{"value": []map[string]any{{"ID": validGUID}}}),
+	}}
+	client := newSequenceClient(t, st)
+	page := bc.NewAPIPage[fakeEntity](client, "fakeEntities")
+
+	first, err := page.ListPage(context.Background(), "", bc.ListOptions{MaxPageSize: 100, Filter: "Quantity gt 0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.NextLink != next || len(first.Value) != 1 {
+		t.Fatalf("unexpected first page %+v", first)
+	}
+	if got := st.Requests[0].Header.Get("Prefer"); got != "odata.maxpagesize=100" {
+		t.Errorf("unexpected Prefer %s", got)
+	}
+
+	second, err := page.ListPage(context.Background(), first.NextLink, bc.ListOptions{MaxPageSize: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.NextLink != "" {
+		t.Errorf("expected last page, got %s", second.NextLink)
+	}
+	if got := st.Requests[1].URL.Query().Get("$skiptoken"); got != "abc" {
+		t.Errorf("unexpected skiptoken %s", got)
+	}
+}
@@ -0,0 +1,198 @@
+package bc
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrMaintenanceWindow is matched by errors.Is for a [MaintenanceError].
+var ErrMaintenanceWindow = errors.New("bc maintenance window")
+
+// MaintenanceWindow is a planned downtime of the environment, e.g. an update.
+type MaintenanceWindow struct {
+	Start time.Time
+	End   time.Time
+	// Reason is shown in the error, e.g. "update to 25.1".
+	Reason string
+}
+
+// Contains returns true if t is in [Start, End).
+func (w MaintenanceWindow) Contains(t time.Time) bool {
+	return !t.Before(w.Start) && t.Before(w.End)
+}
+
+// MaintenanceError is returned by [Client.Do] instead of sending a request during a
+// [MaintenanceWindow] of the [MaintenanceSchedule] of [WithMaintenanceSchedule], and
+// instead of retrying a request into one.
+type MaintenanceError struct {
+	Window MaintenanceWindow
+}
+
+func (err MaintenanceError) Error() string {
+	msg := fmt.Sprintf("%s until %s", ErrMaintenanceWindow, err.Window.End.UTC().Format(time.RFC3339))
+	if err.Window.Reason != "" {
+		msg += ": " + err.Window.Reason
+	}
+	return msg
+}
+
+// Is returns true if target is ErrMaintenanceWindow.
+func (err MaintenanceError) Is(target error) bool {
+	return target == ErrMaintenanceWindow
+}
+
+// RetryAfter returns the time until the end of the window, so a queue can wait for it.
+func (err MaintenanceError) RetryAfter(now time.Time) time.Duration {
+	return max(err.Window.End.Sub(now), 0)
+}
+
+// MaintenanceSchedule holds the known maintenance windows of an environment. Windows
+// can be registered up front and replaced later, e.g. with the ones read by
+// [Client.MaintenanceWindows] on a timer. It is safe for concurrent use.
+type MaintenanceSchedule struct {
+	mu      sync.RWMutex
+	windows []MaintenanceWindow
+}
+
+// NewMaintenanceSchedule creates a schedule with the windows.
+func NewMaintenanceSchedule(windows ...MaintenanceWindow) *MaintenanceSchedule {
+	s := &MaintenanceSchedule{}
+	s.Set(windows)
+	return s
+}
+
+// Set replaces the windows.
+func (s *MaintenanceSchedule) Set(windows []MaintenanceWindow) {
+	windows = slices.Clone(windows)
+	slices.SortFunc(windows, func(a, b MaintenanceWindow) int { return a.Start.Compare(b.Start) })
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.windows = windows
+}
+
+// Add registers a window.
+func (s *MaintenanceSchedule) Add(w MaintenanceWindow) {
+	s.Set(append(s.Windows(), w))
+}
+
+// Windows returns the windows by Start.
+func (s *MaintenanceSchedule) Windows() []MaintenanceWindow {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return slices.Clone(s.windows)
+}
+
+// Active returns the window that contains t, if any. Of overlapping windows it returns
+// the one that ends last.
+func (s *MaintenanceSchedule) Active(t time.Time) (MaintenanceWindow, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var active MaintenanceWindow
+	var ok bool
+	for _, w := range s.windows {
+		if w.Contains(t) && (!ok || w.End.After(active.End)) {
+			active, ok = w, true
+		}
+	}
+	return active, ok
+}
+
+// Next returns the first window that ends after t, which may be active.
+func (s *MaintenanceSchedule) Next(t time.Time) (MaintenanceWindow, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, w := range s.windows {
+		if w.End.After(t) {
+			return w, true
+		}
+	}
+	return MaintenanceWindow{}, false
+}
+
+// WithMaintenanceSchedule makes [Client.Do] return a [MaintenanceError] instead of
+// sending a request during a window of the schedule, and stop retrying when the backoff
+// would end in one, so planned downtime does not burn retries. The time is of the
+// [Clock] of [WithClock].
+func WithMaintenanceSchedule(s *MaintenanceSchedule) ClientOption {
+	return func(client *Client) {
+		client.maintenance = s
+	}
+}
+
+// checkMaintenance returns a [MaintenanceError] if t is in a window of the schedule.
+// The Admin Center API stays up during a window, so its requests are always sent.
+func (c *Client) checkMaintenance(r *http.Request, t time.Time) error {
+	if c.maintenance == nil || strings.HasPrefix(r.URL.Path, "/admin/") {
+		return nil
+	}
+	if w, ok := c.maintenance.Active(t); ok {
+		return MaintenanceError{Window: w}
+	}
+	return nil
+}
+
+// MaintenanceWindowsOptions configure [Client.MaintenanceWindows].
+type MaintenanceWindowsOptions struct {
+	// AdminAPIVersion is the version of the Admin Center API. Defaults to "v2.24".
+	AdminAPIVersion string
+	// ApplicationFamily defaults to "BusinessCentral".
+	ApplicationFamily string
+	// Duration is the length of the window of a scheduled update, which the Admin
+	// Center API does not report. Defaults to 6 hours, the shortest update window.
+	Duration time.Duration
+}
+
+// adminUpdates is the response of the updates endpoint of the Admin Center API.
+type adminUpdates struct {
+	Value []struct {
+		TargetVersion   string `json:"targetVersion"`
+		Selected        bool   `json:"selected"`
+		ScheduleDetails struct {
+			SelectedDateTime time.Time `json:"selectedDateTime"`
+			RolloutStatus    string    `json:"rolloutStatus"`
+		} `json:"scheduleDetails"`
+	} `json:"value"`
+}
+
+func (adminUpdates) Validate() error {
+	return nil
+}
+
+// MaintenanceWindows reads the scheduled updates of the environment from the Admin
+// Center API and returns a window for each, for a [MaintenanceSchedule]. The app
+// registration needs the permissions of the Admin Center API.
+func (c *Client) MaintenanceWindows(ctx context.Context, opts MaintenanceWindowsOptions) ([]MaintenanceWindow, error) {
+	path := fmt.Sprintf("/admin/%s/applications/%s/environments/%s/updates",
+		cmp.Or(opts.AdminAPIVersion, "v2.24"),
+		url.PathEscape(cmp.Or(opts.ApplicationFamily, "BusinessCentral")),
+		url.PathEscape(c.config.Environment))
+	duration := cmp.Or(opts.Duration, 6*time.Hour)
+
+	res, err := c.Raw(ctx, http.MethodGet, path, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("maintenance windows: %w", err)
+	}
+	updates, err := Decode[adminUpdates](res)
+	if err != nil {
+		return nil, fmt.Errorf("maintenance windows: %w", err)
+	}
+
+	var windows []MaintenanceWindow
+	for _, u := range updates.Value {
+		start := u.ScheduleDetails.SelectedDateTime
+		if !u.Selected || start.IsZero() || u.ScheduleDetails.RolloutStatus == "Completed" {
+			continue
+		}
+		windows = append(windows, MaintenanceWindow{Start: start, End: start.Add(duration), Reason: "update to " + u.TargetVersion})
+	}
+	c.logger.Debug("Read maintenance windows.", "environment", c.config.Environment, "windows", len(windows))
+	return windows, nil
+}
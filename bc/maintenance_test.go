@@ -0,0 +1,110 @@
+package bc_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/erlorenz/bc-go/bcmock"
+	"github.com/erlorenz/bc-go/internal/bctest"
+	"github.com/google/uuid"
+)
+
+func TestMaintenanceSchedule(t *testing.T) {
+	start := time.Date(2024, 5, 1, 22, 0, 0, 0, time.UTC)
+	later := bc.MaintenanceWindow{Start: start.Add(48 * time.Hour), End: start.Add(50 * time.Hour)}
+	s := bc.NewMaintenanceSchedule(later, bc.MaintenanceWindow{Start: start, End: start.Add(6 * time.Hour), Reason: "update"})
+
+	if _, ok := s.Active(start.Add(-time.Minute)); ok {
+		t.Error("wanted no active window before the start")
+	}
+	if w, ok := s.Active(start); !ok || w.Reason != "update" {
+		t.Errorf("wanted the update window at its start, got %+v", w)
+	}
+	if _, ok := s.Active(start.Add(6 * time.Hour)); ok {
+		t.Error("wanted no active window at the end")
+	}
+	if w, ok := s.Next(start.Add(7 * time.Hour)); !ok || w != later {
+		t.Errorf("wanted the next window %+v, got %+v", later, w)
+	}
+	s.Add(bc.MaintenanceWindow{Start: start.Add(time.Hour), End: start.Add(8 * time.Hour)})
+	if w, _ := s.Active(start.Add(2 * time.Hour)); !w.End.Equal(start.Add(8 * time.Hour)) {
+		t.Errorf("wanted the window that ends last, got %+v", w)
+	}
+}
+
+func TestMaintenanceWindow(t *testing.T) {
+	now := time.Date(2024, 5, 1, 23, 0, 0, 0, time.UTC)
+	window := bc.MaintenanceWindow{Start: now.Add(-time.Hour), End: now.Add(5 * time.Hour), Reason: "update to 24.1"}
+	st := &bctest.SequenceTransport{Responses: []*http.Response{
+		bctest.NewResponse(http.StatusOK, map[string]any{"ID": validGUID}),
+	}}
+	schedule := bc.NewMaintenanceSchedule(window)
+	client := newSequenceClient(t, st, bc.WithClock(bcmock.NewClock(now)), bc.WithMaintenanceSchedule(schedule))
+	page := bc.NewAPIPage[fakeEntity](client, "fakeEntities")
+
+	_, err := page.Get(context.Background(), uuid.New(), bc.GetOptions{})
+	var merr bc.MaintenanceError
+	if !errors.Is(err, bc.ErrMaintenanceWindow) || !errors.As(err, &merr) || merr.Window != window {
+		t.Fatalf("wanted a MaintenanceError, got %v", err)
+	}
+	if d := merr.RetryAfter(now); d != 5*time.Hour {
+		t.Errorf("wanted to retry after 5h, got %s", d)
+	}
+	if st.Count() != 0 {
+		t.Errorf("wanted no request during the window, got %d", st.Count())
+	}
+
+	schedule.Set(nil)
+	if _, err := page.Get(context.Background(), uuid.New(), bc.GetOptions{}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMaintenanceWindowStopsRetries(t *testing.T) {
+	now := time.Date(2024, 5, 1, 21, 30, 0, 0, time.UTC)
+	st := &bctest.SequenceTransport{Responses: []*http.Response{
+		errorResponse(http.StatusServiceUnavailable, "ServiceUnavailable"),
+	}}
+	schedule := bc.NewMaintenanceSchedule(bc.MaintenanceWindow{Start: now.Add(30 * time.Minute), End: now.Add(6 * time.Hour)})
+	rc := bc.DefaultRetryClassifier{BaseDelay: time.Hour, MaxDelay: time.Hour}
+	client := newSequenceClient(t, st, bc.WithClock(bcmock.NewClock(now)), bc.WithRetryClassifier(rc), bc.WithMaintenanceSchedule(schedule))
+
+	_, err := bc.NewAPIPage[fakeEntity](client, "fakeEntities").Get(context.Background(), uuid.New(), bc.GetOptions{})
+	if !errors.Is(err, bc.ErrMaintenanceWindow) {
+		t.Fatalf("wanted ErrMaintenanceWindow instead of a retry, got %v", err)
+	}
+	if st.Count() != 1 {
+		t.Errorf("wanted 1 attempt, got %d", st.Count())
+	}
+}
+
+func TestMaintenanceWindows(t *testing.T) {
+	selected := time.Date(2024, 5, 1, 22, 0, 0, 0, time.UTC)
+	st := &bctest.SequenceTransport{Responses: []*http.Response{
+		bctest.NewResponse(http.StatusOK, map[string]any{"value": []map[string]any{
+			{"targetVersion": "24.1", "selected": true, "scheduleDetails": map[string]any{"selectedDateTime": selected, "rolloutStatus": "Scheduled"}},
+			{"targetVersion": "25.0", "selected": false},
+			{"targetVersion": "23.5", "selected": true, "scheduleDetails": map[string]any{"selectedDateTime": selected.Add(-720 * time.Hour), "rolloutStatus": "Completed"}},
+		}}),
+	}}
+	// The schedule does not block the Admin Center API.
+	schedule := bc.NewMaintenanceSchedule(bc.MaintenanceWindow{Start: time.Now().Add(-time.Hour), End: time.Now().Add(time.Hour)})
+	client := newSequenceClient(t, st, bc.WithMaintenanceSchedule(schedule))
+
+	windows, err := client.MaintenanceWindows(context.Background(), bc.MaintenanceWindowsOptions{Duration: 4 * time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path := st.Requests[0].URL.Path; !strings.HasPrefix(path, "/admin/v2.24/applications/BusinessCentral/environments/") || !strings.HasSuffix(path, "/updates") {
+		t.Errorf("unexpected path %s", path)
+	}
+	want := bc.MaintenanceWindow{Start: selected, End: selected.Add(4 * time.Hour), Reason: "update to 24.1"}
+	if len(windows) != 1 || !windows[0].Start.Equal(want.Start) || !windows[0].End.Equal(want.End) || windows[0].Reason != want.Reason {
+		t.Errorf("wanted %+v, got %+v", want, windows)
+	}
+}
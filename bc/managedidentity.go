@@ -0,0 +1,190 @@
+package bc
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AzureAD/microsoft-authentication-library-for-go/apps/confidential"
+)
+
+// ErrNoManagedIdentity is returned by [ManagedIdentityAuth] when no managed identity
+// is available and there is no Fallback.
+var ErrNoManagedIdentity = errors.New("managed identity not available")
+
+const (
+	defaultIMDSEndpoint = "http://169.254.169.254/metadata/identity/oauth2/token"
+)
+
+// ManagedIdentityOptions configure [NewManagedIdentityAuth].
+type ManagedIdentityOptions struct {
+	// ClientID selects a user-assigned identity. Defaults to the system-assigned
+	// identity, or AZURE_CLIENT_ID for workload identity.
+	ClientID string
+	// Fallback is used when no managed identity is available, e.g. an [Auth] with a
+	// secret when running locally.
+	Fallback TokenGetter
+	// HTTPClient is used for the identity endpoint. Defaults to a client with a 5 second timeout.
+	HTTPClient *http.Client
+	// IMDSEndpoint overrides the Azure Instance Metadata Service token endpoint.
+	IMDSEndpoint string
+	// AuthOptions override the scope, and the authority for workload identity.
+	AuthOptions []AuthOption
+}
+
+// ManagedIdentityAuth gets tokens from the Azure managed identity of the host, so no
+// credentials are stored. It detects the environment when created:
+//
+//   - Workload identity (AKS) if AZURE_FEDERATED_TOKEN_FILE, AZURE_CLIENT_ID and AZURE_TENANT_ID are set.
+//   - App Service and Functions if IDENTITY_ENDPOINT and IDENTITY_HEADER are set.
+//   - Otherwise the Instance Metadata Service of VMs, Container Apps and Arc.
+//
+// If the Instance Metadata Service is unreachable the Fallback is used.
+// Implements the TokenGetter interface.
+type ManagedIdentityAuth struct {
+	opts   ManagedIdentityOptions
+	source string
+	// workload is set for workload identity, which exchanges the federated token with MSAL.
+	workload *Auth
+	logger   *slog.Logger
+	clock    Clock
+
+	mu        sync.Mutex
+	token     AccessToken
+	expiresOn time.Time
+}
+
+// NewManagedIdentityAuth detects the managed identity environment.
+func NewManagedIdentityAuth(opts ManagedIdentityOptions) (*ManagedIdentityAuth, error) {
+	mi := &ManagedIdentityAuth{opts: opts, logger: slog.Default(), clock: newAuthSettings("", opts.AuthOptions).clock}
+	if mi.opts.HTTPClient == nil {
+		mi.opts.HTTPClient = &http.Client{Timeout: 5 * time.Second}
+	}
+	mi.opts.IMDSEndpoint = cmp.Or(mi.opts.IMDSEndpoint, defaultIMDSEndpoint)
+
+	tokenFile, tenantID := os.Getenv("AZURE_FEDERATED_TOKEN_FILE"), os.Getenv("AZURE_TENANT_ID")
+	clientID := cmp.Or(opts.ClientID, os.Getenv("AZURE_CLIENT_ID"))
+
+	switch {
+	case tokenFile != "" && tenantID != "" && clientID != "":
+		cred := confidential.NewCredFromAssertionCallback(func(context.Context, confidential.AssertionRequestOptions) (string, error) {
+			b, err := os.ReadFile(tokenFile)
+			if err != nil {
+				return "", fmt.Errorf("read federated token: %w", err)
+			}
+			return strings.TrimSpace(string(b)), nil
+		})
+		ac, err := newAuth(tenantID, clientID, cred, opts.AuthOptions...)
+		if err != nil {
+			return nil, err
+		}
+		mi.source, mi.workload = "workload", ac
+	case os.Getenv("IDENTITY_ENDPOINT") != "" && os.Getenv("IDENTITY_HEADER") != "":
+		mi.source = "appservice"
+	default:
+		mi.source = "imds"
+	}
+
+	return mi, nil
+}
+
+// Source returns the detected environment: "workload", "appservice" or "imds".
+func (mi *ManagedIdentityAuth) Source() string {
+	return mi.source
+}
+
+func (mi *ManagedIdentityAuth) GetToken(ctx context.Context) (AccessToken, error) {
+	if mi.workload != nil {
+		return mi.workload.GetToken(ctx)
+	}
+
+	mi.mu.Lock()
+	defer mi.mu.Unlock()
+
+	if mi.token != "" && mi.expiresOn.Sub(mi.clock.Now()) > tokenExpiryMargin {
+		return mi.token, nil
+	}
+
+	req, err := mi.newTokenRequest(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	mi.logger.Debug("Acquiring managed identity token...", "source", mi.source)
+	res, err := mi.opts.HTTPClient.Do(req)
+	if err != nil {
+		if mi.source == "imds" {
+			if mi.opts.Fallback != nil {
+				mi.logger.Debug("Managed identity not available, using fallback.", "error", err)
+				return mi.opts.Fallback.GetToken(ctx)
+			}
+			return "", fmt.Errorf("%w: %w", ErrNoManagedIdentity, err)
+		}
+		return "", fmt.Errorf("error getting managed identity token: %w", err)
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return "", fmt.Errorf("error getting managed identity token: %w", err)
+	}
+	if res.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("error getting managed identity token: status %d: %s", res.StatusCode, body)
+	}
+
+	var data struct {
+		AccessToken string      `json:"access_token"`
+		ExpiresOn   json.Number `json:"expires_on"`
+		ExpiresIn   json.Number `json:"expires_in"`
+	}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return "", fmt.Errorf("error decoding managed identity token: %w", err)
+	}
+
+	mi.token = AccessToken(data.AccessToken)
+	if secs, err := strconv.ParseInt(data.ExpiresOn.String(), 10, 64); err == nil {
+		mi.expiresOn = time.Unix(secs, 0)
+	} else if secs, err := strconv.ParseInt(data.ExpiresIn.String(), 10, 64); err == nil {
+		mi.expiresOn = mi.clock.Now().Add(time.Duration(secs) * time.Second)
+	} else {
+		mi.expiresOn = time.Time{}
+	}
+
+	mi.logger.Debug("Successfully acquired managed identity token.")
+	return mi.token, nil
+}
+
+// newTokenRequest creates the request for the App Service or IMDS endpoint.
+func (mi *ManagedIdentityAuth) newTokenRequest(ctx context.Context) (*http.Request, error) {
+	query := url.Values{"resource": {newAuthSettings("", mi.opts.AuthOptions).resource()}}
+	if mi.opts.ClientID != "" {
+		query.Set("client_id", mi.opts.ClientID)
+	}
+
+	endpoint := mi.opts.IMDSEndpoint
+	header := http.Header{"Metadata": {"true"}}
+	query.Set("api-version", "2018-02-01")
+	if mi.source == "appservice" {
+		endpoint = os.Getenv("IDENTITY_ENDPOINT")
+		header = http.Header{"X-Identity-Header": {os.Getenv("IDENTITY_HEADER")}}
+		query.Set("api-version", "2019-08-01")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating managed identity request: %w", err)
+	}
+	req.Header = header
+	return req, nil
+}
@@ -0,0 +1,97 @@
+package bc_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/erlorenz/bc-go/bc"
+)
+
+func clearManagedIdentityEnv(t *testing.T) {
+	for _, k := range []string{"AZURE_FEDERATED_TOKEN_FILE", "AZURE_CLIENT_ID", "AZURE_TENANT_ID", "IDENTITY_ENDPOINT", "IDENTITY_HEADER"} {
+		t.Setenv(k, "")
+	}
+}
+
+func TestManagedIdentityIMDS(t *testing.T) {
+	clearManagedIdentityEnv(t)
+
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.Header.Get("Metadata") != "true" || r.URL.Query().Get("resource") != "https://api.businesscentral.dynamics.com" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		fmt.Fprintf(w, `{"access_token":"MITOKEN","expires_on":"%d"}`, time.Now().Add(time.Hour).Unix())
+	}))
+	defer srv.Close()
+
+	mi, err := bc.NewManagedIdentityAuth(bc.ManagedIdentityOptions{IMDSEndpoint: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mi.Source() != "imds" {
+		t.Errorf("wanted imds, got %s", mi.Source())
+	}
+
+	for range 2 {
+		token, err := mi.GetToken(context.Background())
+		if err != nil || token != "MITOKEN" {
+			t.Fatalf("wanted MITOKEN, got %s %v", token, err)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("wanted token cached after 1 call, got %d", calls)
+	}
+}
+
+func TestManagedIdentityAppService(t *testing.T) {
+	clearManagedIdentityEnv(t)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Identity-Header") != "SECRETHEADER" || r.URL.Query().Get("client_id") != validGUID {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		fmt.Fprintf(w, `{"access_token":"APPTOKEN","expires_in":%s}`, strconv.Itoa(3600))
+	}))
+	defer srv.Close()
+	t.Setenv("IDENTITY_ENDPOINT", srv.URL)
+	t.Setenv("IDENTITY_HEADER", "SECRETHEADER")
+
+	mi, err := bc.NewManagedIdentityAuth(bc.ManagedIdentityOptions{ClientID: validGUID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mi.Source() != "appservice" {
+		t.Errorf("wanted appservice, got %s", mi.Source())
+	}
+	if token, err := mi.GetToken(context.Background()); err != nil || token != "APPTOKEN" {
+		t.Errorf("wanted APPTOKEN, got %s %v", token, err)
+	}
+}
+
+func TestManagedIdentityFallback(t *testing.T) {
+	clearManagedIdentityEnv(t)
+
+	// Nothing listens on the closed server.
+	srv := httptest.NewServer(http.NotFoundHandler())
+	srv.Close()
+
+	mi, _ := bc.NewManagedIdentityAuth(bc.ManagedIdentityOptions{IMDSEndpoint: srv.URL})
+	if _, err := mi.GetToken(context.Background()); !errors.Is(err, bc.ErrNoManagedIdentity) {
+		t.Errorf("wanted ErrNoManagedIdentity, got %v", err)
+	}
+
+	mi, _ = bc.NewManagedIdentityAuth(bc.ManagedIdentityOptions{IMDSEndpoint: srv.URL, Fallback: fakeTokenGetter{}})
+	if token, err := mi.GetToken(context.Background()); err != nil || token != "FAKEACCESSTOKEN" {
+		t.Errorf("wanted fallback token, got %s %v", token, err)
+	}
+}
@@ -0,0 +1,279 @@
+package bc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// ErrMediaChanged is returned by [Client.DownloadMedia] when the media changed between
+// the chunks of a download, or is smaller than the Offset. Start again from Offset 0.
+var ErrMediaChanged = errors.New("media changed during download")
+
+// mediaBody is a request body that is sent as is instead of being marshaled.
+type mediaBody []byte
+
+// MediaOptions configure [Client.DownloadMedia].
+type MediaOptions struct {
+	// Offset is the first byte to download, e.g. the size of a partial file to resume it.
+	Offset int64
+	// ChunkSize splits the download into Range requests of at most ChunkSize bytes, so a
+	// failure only repeats one chunk. Defaults to the rest of the media in one request.
+	ChunkSize int64
+	// ETag is the ETag of the media when the download at Offset started, from the
+	// MediaResult of the failed call. It is sent as If-Range, so a changed media is
+	// not appended to the bytes before the Offset.
+	ETag string
+}
+
+// MediaResult is the outcome of [Client.DownloadMedia]. After an error, Offset + Written
+// is where to resume.
+type MediaResult struct {
+	// Written is the number of bytes written.
+	Written int64
+	// Size is the total size of the media, or -1 if BC did not send it.
+	Size        int64
+	ContentType string
+	// ETag of the media, to resume a failed download with [MediaOptions].ETag.
+	ETag string
+}
+
+// DownloadMedia writes the media of a stream property to w, e.g. "items(<id>)/picture/pictureContent",
+// "companyInformation(<id>)/picture" or "documentAttachments(<id>)/attachmentContent" relative to the company.
+// With an Offset or ChunkSize it uses Range requests. Chunks after the first are only
+// accepted if the ETag has not changed, otherwise the error matches [ErrMediaChanged].
+func (c *Client) DownloadMedia(ctx context.Context, path string, w io.Writer, opts MediaOptions) (MediaResult, error) {
+	result := MediaResult{Size: -1, ETag: opts.ETag}
+	pos := opts.Offset
+	etag := opts.ETag
+
+	for {
+		header := http.Header{"Accept": {"*/*"}}
+		if pos > 0 || opts.ChunkSize > 0 {
+			rng := fmt.Sprintf("bytes=%d-", pos)
+			if opts.ChunkSize > 0 {
+				rng += strconv.FormatInt(pos+opts.ChunkSize-1, 10)
+			}
+			header.Set("Range", rng)
+		}
+		if etag != "" {
+			header.Set("If-Range", etag)
+		}
+
+		req, err := c.newMediaRequest(ctx, http.MethodGet, path, header, nil)
+		if err != nil {
+			return result, fmt.Errorf("failed to create Request: %w", err)
+		}
+
+		res, err := c.Do(req)
+		if err != nil {
+			return result, fmt.Errorf("failed during request: %w", err)
+		}
+
+		n, done, err := c.readMediaChunk(res, w, pos, etag != "", &result)
+		result.Written += n
+		pos += n
+		if etag == "" && n > 0 {
+			etag = res.Header.Get("ETag")
+			result.ETag = etag
+		}
+		if err != nil || done {
+			return result, err
+		}
+	}
+}
+
+// newMediaRequest creates a request for the path without the validation and query params of
+// [Client.NewRequest], which are for entities. PATCH has no RecordID and GET is not a list.
+func (c *Client) newMediaRequest(ctx context.Context, method, path string, header http.Header, body any) (*http.Request, error) {
+	if err := c.checkReadOnly(method, path); err != nil {
+		return nil, err
+	}
+	u := BuildRequestURL(*c.baseURL, path, uuid.Nil, nil)
+	return c.newRequest(ctx, u.String(), RequestOptions{Method: method, EntitySetName: path, Header: header, Body: body})
+}
+
+// readMediaChunk copies the body of a media response starting at pos to w and closes it.
+// It returns true when there are no more chunks.
+func (c *Client) readMediaChunk(res *http.Response, w io.Writer, pos int64, hasETag bool, result *MediaResult) (int64, bool, error) {
+	defer res.Body.Close()
+
+	if result.ContentType == "" {
+		result.ContentType = res.Header.Get("Content-Type")
+	}
+
+	switch res.StatusCode {
+	case http.StatusPartialContent:
+		start, size, err := parseContentRange(res.Header.Get("Content-Range"))
+		if err != nil {
+			return 0, true, err
+		}
+		if start != pos {
+			return 0, true, fmt.Errorf("invalid Content-Range: wanted start %d, got %d", pos, start)
+		}
+		result.Size = size
+
+		n, err := io.Copy(w, res.Body)
+		if err != nil {
+			return n, true, fmt.Errorf("failed to read media: %w", err)
+		}
+		return n, n == 0 || (size >= 0 && pos+n >= size), nil
+
+	case http.StatusOK:
+		// The whole media, either without a Range or because BC ignored it
+		if pos > 0 && hasETag {
+			return 0, true, ErrMediaChanged
+		}
+		if res.ContentLength >= 0 {
+			result.Size = res.ContentLength
+		}
+		if _, err := io.CopyN(io.Discard, res.Body, pos); err != nil {
+			return 0, true, fmt.Errorf("failed to read media: %w", err)
+		}
+		n, err := io.Copy(w, res.Body)
+		if err != nil {
+			return n, true, fmt.Errorf("failed to read media: %w", err)
+		}
+		return n, true, nil
+
+	case http.StatusRequestedRangeNotSatisfiable:
+		// Already complete when resuming at the size, changed when it is smaller
+		_, size, err := parseContentRange(res.Header.Get("Content-Range"))
+		if err != nil {
+			return 0, true, err
+		}
+		if size != pos {
+			return 0, true, fmt.Errorf("%w: offset %d is past the size %d", ErrMediaChanged, pos, size)
+		}
+		result.Size = size
+		return 0, true, nil
+	}
+
+	err := decodeErrorResponse(res)
+	var srvErr APIError
+	if errors.As(err, &srvErr) {
+		c.logger.Debug("API server returned error response.", "error", srvErr)
+		return 0, true, fmt.Errorf("error from BC API: %w", srvErr)
+	}
+	c.logger.Debug("Failed to decode response.", "error", err)
+	return 0, true, fmt.Errorf("failed to decode response: %w", err)
+}
+
+// parseContentRange returns the start and total size of "bytes 0-99/1000" or "bytes */1000".
+// The size is -1 if it is "*".
+func parseContentRange(v string) (start, size int64, err error) {
+	rng, total, ok := strings.Cut(strings.TrimPrefix(v, "bytes "), "/")
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid Content-Range %q", v)
+	}
+
+	size = -1
+	if total != "*" {
+		if size, err = strconv.ParseInt(total, 10, 64); err != nil {
+			return 0, 0, fmt.Errorf("invalid Content-Range %q", v)
+		}
+	}
+	if rng == "*" {
+		return 0, size, nil
+	}
+
+	first, _, _ := strings.Cut(rng, "-")
+	if start, err = strconv.ParseInt(first, 10, 64); err != nil {
+		return 0, 0, fmt.Errorf("invalid Content-Range %q", v)
+	}
+	return start, size, nil
+}
+
+// DownloadMediaFile calls [Client.DownloadMedia] and appends to the file, resuming from
+// its size if it exists. A failed download can be resumed by calling it again: the ETag
+// of the media is kept in filename + ".etag" until the download completes, so the
+// resumed part is only appended if the media is unchanged. A partial file without an
+// ETag is downloaded again from the start, as is one of a media that changed after the
+// error matching [ErrMediaChanged].
+func (c *Client) DownloadMediaFile(ctx context.Context, path string, filename string, opts MediaOptions) (MediaResult, error) {
+	etagFile := filename + ".etag"
+	f, err := os.OpenFile(filename, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return MediaResult{Size: -1}, fmt.Errorf("open media file: %w", err)
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return MediaResult{Size: -1}, fmt.Errorf("open media file: %w", err)
+	}
+	opts.Offset = info.Size()
+	if opts.Offset > 0 && opts.ETag == "" {
+		if b, err := os.ReadFile(etagFile); err == nil {
+			opts.ETag = strings.TrimSpace(string(b))
+		}
+	}
+	if opts.ETag == "" && opts.Offset > 0 {
+		// Appending is only safe if If-Range can check the media is unchanged
+		if err := f.Truncate(0); err != nil {
+			f.Close()
+			return MediaResult{Size: -1}, fmt.Errorf("truncate media file: %w", err)
+		}
+		opts.Offset = 0
+	}
+
+	result, err := c.DownloadMedia(ctx, path, f, opts)
+	switch {
+	case err == nil:
+		if rmErr := os.Remove(etagFile); rmErr != nil && !errors.Is(rmErr, fs.ErrNotExist) {
+			err = fmt.Errorf("remove media etag: %w", rmErr)
+		}
+	case errors.Is(err, ErrMediaChanged):
+		if truncErr := f.Truncate(0); truncErr != nil {
+			err = errors.Join(err, fmt.Errorf("truncate media file: %w", truncErr))
+		}
+		os.Remove(etagFile)
+	case result.ETag != "":
+		if writeErr := os.WriteFile(etagFile, []byte(result.ETag), 0o644); writeErr != nil {
+			err = errors.Join(err, fmt.Errorf("write media etag: %w", writeErr))
+		}
+	}
+	if closeErr := f.Close(); err == nil && closeErr != nil {
+		err = fmt.Errorf("close media file: %w", closeErr)
+	}
+	return result, err
+}
+
+// UploadMedia replaces the media of a stream property, see [Client.DownloadMedia] for the path.
+// BC does not support partial uploads, so the whole media is read and sent in one request.
+func (c *Client) UploadMedia(ctx context.Context, path string, r io.Reader, contentType string) error {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("failed to read media: %w", err)
+	}
+
+	req, err := c.newMediaRequest(ctx, http.MethodPatch, path, http.Header{"Content-Type": {contentType}}, mediaBody(b))
+	if err != nil {
+		return fmt.Errorf("failed to create Request: %w", err)
+	}
+
+	res, err := c.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed during request: %w", err)
+	}
+
+	if err := DecodeNoContent(res); err != nil {
+		var srvErr APIError
+		if errors.As(err, &srvErr) {
+			c.logger.Debug("API server returned error response.", "error", srvErr)
+			return fmt.Errorf("error from BC API: %w", srvErr)
+		}
+
+		c.logger.Debug("Failed to decode response.", "error", err)
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
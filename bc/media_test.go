@@ -0,0 +1,201 @@
+package bc_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/erlorenz/bc-go/internal/bctest"
+)
+
+var mediaPath = "items(" + validGUID + ")/picture/pictureContent"
+
+func mediaResponse(status int, body, contentRange, etag string) *http.Response {
+	res := &http.Response{
+		StatusCode:    status,
+		Body:          io.NopCloser(strings.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Header:        http.Header{"Content-Type": {"image/png"}},
+	}
+	if contentRange != "" {
+		res.Header.Set("Content-Range", contentRange)
+	}
+	if etag != "" {
+		res.Header.Set("ETag", etag)
+	}
+	return res
+}
+
+func TestDownloadMediaChunks(t *testing.T) {
+	st := &bctest.SequenceTransport{Responses: []*http.Response{
+		mediaResponse(http.StatusPartialContent, "0123", "bytes 0-3/10", `W/"1"`),
+		mediaResponse(http.StatusPartialContent, "4567", "bytes 4-7/10", `W/"1"`),
+		mediaResponse(http.StatusPartialContent, "89", "bytes 8-9/10", `W/"1"`),
+	}}
+	client := newSequenceClient(t, st, bc.WithStableOrdering("id"))
+
+	var buf bytes.Buffer
+	result, err := client.DownloadMedia(context.Background(), mediaPath, &buf, bc.MediaOptions{ChunkSize: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if buf.String() != "0123456789" || result.Written != 10 || result.Size != 10 || result.ContentType != "image/png" {
+		t.Errorf("unexpected result %+v %q", result, buf.String())
+	}
+	if st.Count() != 3 {
+		t.Fatalf("wanted 3 requests, got %d", st.Count())
+	}
+
+	wantRanges := []string{"bytes=0-3", "bytes=4-7", "bytes=8-11"}
+	for i, r := range st.Requests {
+		if got := r.Header.Get("Range"); got != wantRanges[i] {
+			t.Errorf("wanted Range %s, got %s", wantRanges[i], got)
+		}
+		if r.URL.RawQuery != "" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+	}
+	if got := st.Requests[1].Header.Get("If-Range"); got != `W/"1"` {
+		t.Errorf("wanted If-Range of the first chunk, got %s", got)
+	}
+	if !strings.HasSuffix(st.Requests[0].URL.Path, "/picture/pictureContent") {
+		t.Errorf("unexpected path %s", st.Requests[0].URL.Path)
+	}
+}
+
+func TestDownloadMediaChanged(t *testing.T) {
+	st := &bctest.SequenceTransport{Responses: []*http.Response{
+		mediaResponse(http.StatusPartialContent, "0123", "bytes 0-3/10", `W/"1"`),
+		mediaResponse(http.StatusOK, "abcdefghij", "", `W/"2"`),
+	}}
+	client := newSequenceClient(t, st)
+
+	var buf bytes.Buffer
+	result, err := client.DownloadMedia(context.Background(), mediaPath, &buf, bc.MediaOptions{ChunkSize: 4})
+	if !errors.Is(err, bc.ErrMediaChanged) {
+		t.Fatalf("wanted ErrMediaChanged, got %v", err)
+	}
+	if result.Written != 4 {
+		t.Errorf("wanted 4 bytes written, got %d", result.Written)
+	}
+}
+
+func TestDownloadMediaRangeIgnored(t *testing.T) {
+	st := &bctest.SequenceTransport{Responses: []*http.Response{
+		mediaResponse(http.StatusOK, "0123456789", "", ""),
+	}}
+	client := newSequenceClient(t, st)
+
+	var buf bytes.Buffer
+	if _, err := client.DownloadMedia(context.Background(), mediaPath, &buf, bc.MediaOptions{Offset: 4}); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "456789" {
+		t.Errorf("wanted the bytes after the offset, got %q", buf.String())
+	}
+}
+
+func TestDownloadMediaFile(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "picture.png")
+	st := &bctest.SequenceTransport{Responses: []*http.Response{
+		mediaResponse(http.StatusPartialContent, "0123", "bytes 0-3/10", `W/"1"`),
+		errorResponse(http.StatusInternalServerError, "Internal_ServerError"),
+		mediaResponse(http.StatusPartialContent, "456789", "bytes 4-9/10", `W/"1"`),
+	}}
+	client := newSequenceClient(t, st, bc.WithRetryClassifier(bc.RetryClassifierFunc(func(bc.RetryAttempt) bc.RetryDecision { return bc.RetryDecision{} })))
+
+	// The first chunk is written and its ETag kept for the resume
+	if _, err := client.DownloadMediaFile(context.Background(), mediaPath, filename, bc.MediaOptions{ChunkSize: 4}); err == nil {
+		t.Fatal("wanted the error of the second chunk")
+	}
+	if b, _ := os.ReadFile(filename + ".etag"); string(b) != `W/"1"` {
+		t.Fatalf("wanted the ETag kept, got %q", b)
+	}
+
+	if _, err := client.DownloadMediaFile(context.Background(), mediaPath, filename, bc.MediaOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if r := st.Requests[2]; r.Header.Get("Range") != "bytes=4-" || r.Header.Get("If-Range") != `W/"1"` {
+		t.Errorf("wanted Range bytes=4- with If-Range, got %s %s", r.Header.Get("Range"), r.Header.Get("If-Range"))
+	}
+	b, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "0123456789" {
+		t.Errorf("unexpected file %q", b)
+	}
+	if _, err := os.Stat(filename + ".etag"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("wanted the ETag removed after the download, got %v", err)
+	}
+}
+
+func TestDownloadMediaFileWithoutETag(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "picture.png")
+	if err := os.WriteFile(filename, []byte("abcd"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	st := &bctest.SequenceTransport{Responses: []*http.Response{
+		mediaResponse(http.StatusOK, "0123456789", "", ""),
+	}}
+	client := newSequenceClient(t, st)
+
+	if _, err := client.DownloadMediaFile(context.Background(), mediaPath, filename, bc.MediaOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if got := st.Requests[0].Header.Get("Range"); got != "" {
+		t.Errorf("wanted the partial file without an ETag downloaded again, got Range %s", got)
+	}
+	if b, _ := os.ReadFile(filename); string(b) != "0123456789" {
+		t.Errorf("unexpected file %q", b)
+	}
+}
+
+func TestDownloadMediaFileLarger(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "picture.png")
+	if err := os.WriteFile(filename, []byte("0123456789AB"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filename+".etag", []byte(`W/"1"`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	st := &bctest.SequenceTransport{Responses: []*http.Response{
+		mediaResponse(http.StatusRequestedRangeNotSatisfiable, "", "bytes */10", ""),
+	}}
+	client := newSequenceClient(t, st)
+
+	_, err := client.DownloadMediaFile(context.Background(), mediaPath, filename, bc.MediaOptions{})
+	if !errors.Is(err, bc.ErrMediaChanged) {
+		t.Fatalf("wanted ErrMediaChanged for a file larger than the media, got %v", err)
+	}
+	if info, _ := os.Stat(filename); info.Size() != 0 {
+		t.Errorf("wanted the file truncated to start again, got %d bytes", info.Size())
+	}
+}
+
+func TestUploadMedia(t *testing.T) {
+	st := &bctest.SequenceTransport{Responses: []*http.Response{
+		bctest.NewResponse(http.StatusNoContent, nil),
+	}}
+	client := newSequenceClient(t, st)
+
+	if err := client.UploadMedia(context.Background(), mediaPath, strings.NewReader("PNGDATA"), "image/png"); err != nil {
+		t.Fatal(err)
+	}
+
+	req := st.Requests[0]
+	if req.Method != http.MethodPatch || req.Header.Get("Content-Type") != "image/png" || req.Header.Get("If-Match") != "*" {
+		t.Errorf("unexpected request %s %v", req.Method, req.Header)
+	}
+	if got := readBody(t, req); got != "PNGDATA" {
+		t.Errorf("wanted the raw body, got %q", got)
+	}
+}
@@ -0,0 +1,37 @@
+package bc
+
+import "net/http"
+
+// Middleware wraps the http.RoundTripper of the [Client] so it can inspect or
+// modify every request and response, including retries.
+// Add them with [WithMiddleware].
+type Middleware func(next http.RoundTripper) http.RoundTripper
+
+// RoundTripperFunc adapts a function to an http.RoundTripper.
+type RoundTripperFunc func(*http.Request) (*http.Response, error)
+
+// RoundTrip calls f.
+func (f RoundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+// applyMiddleware returns a copy of the http.Client with the transport wrapped by
+// the middleware, so the first middleware is the outermost.
+func applyMiddleware(hc *http.Client, middleware []Middleware) *http.Client {
+	if len(middleware) == 0 {
+		return hc
+	}
+
+	transport := hc.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	for i := len(middleware) - 1; i >= 0; i-- {
+		transport = middleware[i](transport)
+	}
+
+	wrapped := *hc
+	wrapped.Transport = transport
+	return &wrapped
+}
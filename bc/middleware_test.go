@@ -0,0 +1,69 @@
+package bc_test
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/erlorenz/bc-go/internal/bctest"
+	"github.com/google/uuid"
+)
+
+func TestWithMiddleware(t *testing.T) {
+	var order []string
+
+	mw := func(name string) bc.Middleware {
+		return func(next http.RoundTripper) http.RoundTripper {
+			return bc.RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+				order = append(order, name)
+				return next.RoundTrip(r)
+			})
+		}
+	}
+
+	st := &bctest.SequenceTransport{Responses: []*http.Response{bctest.NewResponse(200, map[string]any{"ID": validGUID})}}
+	httpClient := &http.Client{Transport: st}
+
+	client := newSequenceClient(t, st, bc.WithHTTPClient(httpClient), bc.WithMiddleware(mw("first"), mw("second")))
+	page := bc.NewAPIPage[fakeEntity](client, "fakeEntities")
+
+	if _, err := page.Get(context.Background(), uuid.New(), bc.GetOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if !slices.Equal(order, []string{"first", "second"}) {
+		t.Errorf("unexpected order %v", order)
+	}
+
+	if httpClient.Transport != st {
+		t.Error("expected original http.Client to be unmodified")
+	}
+}
+
+func TestSplitEntityPath(t *testing.T) {
+	id := uuid.NewString()
+	base := "/v2.0/tenant/env/api/v2.0/companies(" + id + ")"
+
+	type testCase struct {
+		path       string
+		wantEntity string
+		wantID     string
+	}
+
+	tests := []testCase{
+		{base + "/customers", "customers", ""},
+		{base + "/customers(" + id + ")", "customers", id},
+		{base + "/salesOrders(x)/salesOrderLines", "salesOrderLines", ""},
+		{base + "/salesInvoices(" + id + ")/Microsoft.NAV.post", "salesInvoices", id},
+		{"/other", "", ""},
+	}
+
+	for _, test := range tests {
+		gotEntity, gotID := bc.SplitEntityPath(test.path)
+		if gotEntity != test.wantEntity || gotID != test.wantID {
+			t.Errorf("%s: wanted %s %s, got %s %s", test.path, test.wantEntity, test.wantID, gotEntity, gotID)
+		}
+	}
+}
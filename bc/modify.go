@@ -0,0 +1,127 @@
+package bc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// ModifyOptions configure [APIPage.Modify].
+type ModifyOptions struct {
+	// MaxAttempts is the number of read-modify-write cycles when the record changed in
+	// between. Defaults to 3.
+	MaxAttempts int
+}
+
+// Modify reads the record, calls fn to change it, and PATCHes only the fields that fn
+// changed with the ETag of the read, so a concurrent change is never overwritten. If the
+// record changed in between, the PATCH fails with a 412 and Modify reads it and calls fn
+// again, so fn must be safe to repeat. Nothing is sent if fn changes nothing, and an
+// error of fn is returned as is. Fields are compared by their JSON, so a field with
+// "omitempty" that fn clears is not sent.
+func (a *APIPage[T]) Modify(ctx context.Context, id uuid.UUID, fn func(current *T) error, opts ModifyOptions) (T, error) {
+	maxAttempts := opts.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = 3
+	}
+
+	for attempt := 1; ; attempt++ {
+		current, etag, err := a.getWithETag(ctx, id)
+		if err != nil {
+			return current, fmt.Errorf("modify: %w", err)
+		}
+		before, err := splitFields(a.client.codec, current)
+		if err != nil {
+			return current, fmt.Errorf("modify: %w", err)
+		}
+		if err := fn(&current); err != nil {
+			return current, err
+		}
+		after, err := splitFields(a.client.codec, current)
+		if err != nil {
+			return current, fmt.Errorf("modify: %w", err)
+		}
+
+		changed := map[string]json.RawMessage{}
+		for k, v := range after {
+			if b, ok := before[k]; k != "@odata.etag" && (!ok || !bytes.Equal(b, v)) {
+				changed[k] = v
+			}
+		}
+		if len(changed) == 0 {
+			a.client.logger.Debug("Modify changed nothing, not sending.", "entitySetName", a.entitySetName, "id", id)
+			return current, nil
+		}
+		if etag == "" {
+			return current, fmt.Errorf("modify: %s(%s) has no @odata.etag", a.entitySetName, id)
+		}
+
+		updated, err := a.UpdateIfMatch(ctx, id, nil, changed, etag)
+		if errors.Is(err, ErrPreconditionFailed) && attempt < maxAttempts {
+			a.client.logger.Debug("Record changed since it was read, modifying again.", "entitySetName", a.entitySetName, "id", id, "attempt", attempt)
+			continue
+		}
+		if err != nil {
+			return updated, fmt.Errorf("modify: %w", err)
+		}
+		return updated, nil
+	}
+}
+
+// Modify calls [APIPage.Modify] for the entity set.
+func Modify[T Validator](ctx context.Context, c *Client, entitySetName string, id uuid.UUID, fn func(current *T) error) (T, error) {
+	return NewAPIPage[T](c, entitySetName).Modify(ctx, id, fn, ModifyOptions{})
+}
+
+// getWithETag gets the record and the "@odata.etag" of its JSON, which T may not have a field for.
+func (a *APIPage[T]) getWithETag(ctx context.Context, id uuid.UUID) (T, string, error) {
+	var v T
+	req, err := a.client.NewRequest(ctx, RequestOptions{Method: http.MethodGet, EntitySetName: a.entitySetName, RecordID: id})
+	if err != nil {
+		return v, "", fmt.Errorf("failed to create Request: %w", err)
+	}
+	res, err := a.client.Do(req)
+	if err != nil {
+		return v, "", fmt.Errorf("failed during request: %w", err)
+	}
+	raw, err := decodeCodec[rawRecord](a.client.codec, res)
+	if err != nil {
+		var srvErr APIError
+		if errors.As(err, &srvErr) {
+			return v, "", fmt.Errorf("error from BC API: %w", srvErr)
+		}
+		return v, "", fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	var meta struct {
+		ETag string `json:"@odata.etag"`
+	}
+	if err := a.client.codec.Unmarshal(raw.RawMessage, &meta); err != nil {
+		return v, "", fmt.Errorf("could not decode %T: %w", v, err)
+	}
+	if err := a.client.codec.Unmarshal(raw.RawMessage, &v); err != nil {
+		return v, "", fmt.Errorf("could not decode %T: %w", v, err)
+	}
+	if err := v.Validate(); err != nil {
+		return v, "", fmt.Errorf("failed validation of %T: %w", v, err)
+	}
+	return v, meta.ETag, validateRecord(a.client, a.entitySetName, v)
+}
+
+// splitFields marshals the record and splits it into its top-level fields.
+func splitFields(codec Codec, v any) (map[string]json.RawMessage, error) {
+	b, err := codec.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("could not marshal %T: %w", v, err)
+	}
+	var fields map[string]json.RawMessage
+	if err := codec.Unmarshal(b, &fields); err != nil {
+		return nil, fmt.Errorf("could not split %T into fields: %w", v, err)
+	}
+	return fields, nil
+}
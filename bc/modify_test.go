@@ -0,0 +1,106 @@
+package bc_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/erlorenz/bc-go/internal/bctest"
+	"github.com/google/uuid"
+)
+
+type modifyEntity struct {
+	ID          string  `json:"id"`
+	DisplayName string  `json:"displayName"`
+	CreditLimit float64 `json:"creditLimit"`
+}
+
+func (modifyEntity) Validate() error { return nil }
+
+func TestModifyRetriesOnPreconditionFailed(t *testing.T) {
+	st := &bctest.SequenceTransport{Responses: []*http.Response{
+		bctest.NewResponse(200, map[string]any{"@odata.etag": `W/"1"`, "id": validGUID, "displayName": "Adatum", "creditLimit": 1000}),
+		errorResponse(http.StatusPreconditionFailed, "Request_EntityChanged"),
+		bctest.NewResponse(200, map[string]any{"@odata.etag": `W/"2"`, "id": validGUID, "displayName": "Adatum Corp", "creditLimit": 1500}),
+		bctest.NewResponse(200, map[string]any{"@odata.etag": `W/"3"`, "id": validGUID, "displayName": "Adatum Corp", "creditLimit": 2500}),
+	}}
+	client := newSequenceClient(t, st)
+
+	calls := 0
+	updated, err := bc.Modify(context.Background(), client, "customers", uuid.MustParse(validGUID), func(c *modifyEntity) error {
+		calls++
+		c.CreditLimit += 1000
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls != 2 || updated.CreditLimit != 2500 || updated.DisplayName != "Adatum Corp" {
+		t.Errorf("unexpected result %+v after %d calls", updated, calls)
+	}
+
+	table := []struct {
+		method  string
+		ifMatch string
+		body    string
+	}{
+		{http.MethodGet, "", ""},
+		{http.MethodPatch, `W/"1"`, `{"creditLimit":2000}`},
+		{http.MethodGet, "", ""},
+		{http.MethodPatch, `W/"2"`, `{"creditLimit":2500}`},
+	}
+	for i, want := range table {
+		r := st.Requests[i]
+		var body []byte
+		if r.Body != nil {
+			body, _ = io.ReadAll(r.Body)
+		}
+		if r.Method != want.method || r.Header.Get("If-Match") != want.ifMatch || string(body) != want.body {
+			t.Errorf("request %d: wanted %s If-Match %s %s, got %s If-Match %s %s", i, want.method, want.ifMatch, want.body, r.Method, r.Header.Get("If-Match"), body)
+		}
+	}
+}
+
+func TestModifyNoChange(t *testing.T) {
+	st := &bctest.SequenceTransport{Responses: []*http.Response{
+		bctest.NewResponse(200, map[string]any{"@odata.etag": `W/"1"`, "id": validGUID, "displayName": "Adatum"}),
+		bctest.NewResponse(200, map[string]any{"@odata.etag": `W/"1"`, "id": validGUID, "displayName": "Adatum"}),
+	}}
+	client := newSequenceClient(t, st)
+	page := bc.NewAPIPage[modifyEntity](client, "customers")
+
+	if _, err := page.Modify(context.Background(), uuid.MustParse(validGUID), func(c *modifyEntity) error {
+		c.DisplayName = "Adatum"
+		return nil
+	}, bc.ModifyOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	errStop := errors.New("stop")
+	if _, err := page.Modify(context.Background(), uuid.MustParse(validGUID), func(*modifyEntity) error { return errStop }, bc.ModifyOptions{}); !errors.Is(err, errStop) {
+		t.Errorf("wanted the error of fn, got %v", err)
+	}
+	if st.Count() != 2 {
+		t.Errorf("wanted only the reads, got %d requests", st.Count())
+	}
+}
+
+func TestModifyGivesUp(t *testing.T) {
+	st := &bctest.SequenceTransport{Responses: []*http.Response{
+		bctest.NewResponse(200, map[string]any{"@odata.etag": `W/"1"`, "id": validGUID}),
+		errorResponse(http.StatusPreconditionFailed, "Request_EntityChanged"),
+	}}
+	client := newSequenceClient(t, st)
+	page := bc.NewAPIPage[modifyEntity](client, "customers")
+
+	_, err := page.Modify(context.Background(), uuid.MustParse(validGUID), func(c *modifyEntity) error {
+		c.CreditLimit++
+		return nil
+	}, bc.ModifyOptions{MaxAttempts: 1})
+	if !errors.Is(err, bc.ErrPreconditionFailed) {
+		t.Errorf("wanted ErrPreconditionFailed, got %v", err)
+	}
+}
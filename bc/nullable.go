@@ -0,0 +1,196 @@
+package bc
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// Nullable is a field of a write body that BC tells apart in three states: not sent,
+// sent as null to clear the field, and sent with a value. The zero value is not sent.
+// encoding/json omits nothing for it on its own, so marshal the body with [MarshalWrite]
+// or pass it as [WriteBody], which leave out the fields that are not set:
+//
+//	type CustomerPatch struct {
+//		DisplayName bc.Nullable[string]    `json:"displayName"`
+//		CurrencyID  bc.Nullable[uuid.UUID] `json:"currencyId"`
+//	}
+//	patch := CustomerPatch{DisplayName: bc.Set("Adatum"), CurrencyID: bc.Null[uuid.UUID]()}
+//	customer, err := customers.Update(ctx, id, nil, bc.WriteBody(patch)) // {"displayName":"Adatum","currencyId":null}
+//
+// With Go 1.24 or later the "omitzero" option of encoding/json omits it as well.
+type Nullable[T any] struct {
+	value T
+	set   bool
+	null  bool
+}
+
+// Set returns a Nullable that sends the value.
+func Set[T any](v T) Nullable[T] {
+	return Nullable[T]{value: v, set: true}
+}
+
+// Null returns a Nullable that sends null.
+func Null[T any]() Nullable[T] {
+	return Nullable[T]{set: true, null: true}
+}
+
+// IsSet returns true if the field is sent, as null or with a value.
+func (n Nullable[T]) IsSet() bool {
+	return n.set
+}
+
+// IsNull returns true if the field is sent as null.
+func (n Nullable[T]) IsNull() bool {
+	return n.null
+}
+
+// Get returns the value and true if the field is sent with a value.
+func (n Nullable[T]) Get() (T, bool) {
+	return n.value, n.set && !n.null
+}
+
+// IsZero returns true if the field is not sent, for the "omitzero" option of encoding/json.
+func (n Nullable[T]) IsZero() bool {
+	return !n.set
+}
+
+// MarshalJSON marshals the value, or null if the field is null or not sent.
+func (n Nullable[T]) MarshalJSON() ([]byte, error) {
+	if !n.set || n.null {
+		return []byte("null"), nil
+	}
+	return json.Marshal(n.value)
+}
+
+// UnmarshalJSON sets the field, to null for a JSON null.
+func (n *Nullable[T]) UnmarshalJSON(data []byte) error {
+	if string(bytes.TrimSpace(data)) == "null" {
+		*n = Null[T]()
+		return nil
+	}
+	var v T
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*n = Set(v)
+	return nil
+}
+
+func (n Nullable[T]) isSet() bool {
+	return n.set
+}
+
+// nullable is implemented by every Nullable.
+type nullable interface {
+	isSet() bool
+}
+
+// WriteBody wraps a struct body so it is marshaled with [MarshalWrite].
+func WriteBody(v any) json.Marshaler {
+	return writeBody{v}
+}
+
+type writeBody struct {
+	v any
+}
+
+func (b writeBody) MarshalJSON() ([]byte, error) {
+	return MarshalWrite(b.v)
+}
+
+// MarshalWrite marshals a struct like json.Marshal but leaves out the [Nullable] fields
+// that are not set. Other fields keep their json tags, including "omitempty", and
+// embedded structs are flattened. Values that are not structs are marshaled as is.
+func MarshalWrite(v any) ([]byte, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return json.Marshal(v)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	if err := writeFields(&buf, rv); err != nil {
+		return nil, err
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// writeFields writes the fields of the struct to buf, each preceded by a comma
+// unless it is the first.
+func writeFields(buf *bytes.Buffer, rv reflect.Value) error {
+	t := rv.Type()
+	for i := range t.NumField() {
+		f := t.Field(i)
+		fv := rv.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+		opts := strings.Split(options, ",")
+
+		if f.Anonymous && name == "" {
+			for fv.Kind() == reflect.Pointer {
+				if fv.IsNil() {
+					break
+				}
+				fv = fv.Elem()
+			}
+			if fv.Kind() == reflect.Struct {
+				if err := writeFields(buf, fv); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if n, ok := fv.Interface().(nullable); ok && !n.isSet() {
+			continue
+		}
+		if slices.Contains(opts, "omitempty") && isEmptyValue(fv) {
+			continue
+		}
+		if slices.Contains(opts, "omitzero") && fv.IsZero() {
+			continue
+		}
+
+		b, err := json.Marshal(fv.Interface())
+		if err != nil {
+			return fmt.Errorf("marshal field %s: %w", f.Name, err)
+		}
+		key, _ := json.Marshal(cmp.Or(name, f.Name))
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(b)
+	}
+	return nil
+}
+
+// isEmptyValue is the "omitempty" rule of encoding/json.
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64,
+		reflect.Interface, reflect.Pointer:
+		return v.IsZero()
+	}
+	return false
+}
@@ -0,0 +1,85 @@
+package bc_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/erlorenz/bc-go/internal/bctest"
+	"github.com/google/uuid"
+)
+
+type addressPatch struct {
+	City string `json:"city,omitempty"`
+}
+
+type customerPatch struct {
+	addressPatch
+	DisplayName bc.Nullable[string]    `json:"displayName"`
+	CreditLimit bc.Nullable[float64]   `json:"creditLimit"`
+	CurrencyID  bc.Nullable[uuid.UUID] `json:"currencyId"`
+	Blocked     string                 `json:"blocked"`
+	Internal    string                 `json:"-"`
+}
+
+func TestMarshalWrite(t *testing.T) {
+	table := []struct {
+		name  string
+		patch customerPatch
+		want  string
+	}{
+		{"untouched", customerPatch{}, `{"blocked":""}`},
+		{"values", customerPatch{DisplayName: bc.Set("Adatum"), CreditLimit: bc.Set(0.0)}, `{"displayName":"Adatum","creditLimit":0,"blocked":""}`},
+		{"nulls", customerPatch{CurrencyID: bc.Null[uuid.UUID](), Blocked: "All", Internal: "x"}, `{"currencyId":null,"blocked":"All"}`},
+		{"embedded", customerPatch{addressPatch: addressPatch{City: "Atlanta"}}, `{"city":"Atlanta","blocked":""}`},
+	}
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := bc.MarshalWrite(&tt.patch)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(b) != tt.want {
+				t.Errorf("wanted %s, got %s", tt.want, b)
+			}
+		})
+	}
+}
+
+func TestNullableUnmarshal(t *testing.T) {
+	var v struct {
+		A bc.Nullable[string] `json:"a"`
+		B bc.Nullable[string] `json:"b"`
+		C bc.Nullable[string] `json:"c"`
+	}
+	if err := json.Unmarshal([]byte(`{"a":"x","b":null}`), &v); err != nil {
+		t.Fatal(err)
+	}
+	if a, ok := v.A.Get(); !ok || a != "x" {
+		t.Errorf("wanted a set to x, got %+v", v.A)
+	}
+	if !v.B.IsSet() || !v.B.IsNull() {
+		t.Errorf("wanted b null, got %+v", v.B)
+	}
+	if v.C.IsSet() || !v.C.IsZero() {
+		t.Errorf("wanted c not set, got %+v", v.C)
+	}
+}
+
+func TestWriteBodyUpdate(t *testing.T) {
+	st := &bctest.SequenceTransport{Responses: []*http.Response{bctest.NewResponse(200, map[string]any{"ID": validGUID})}}
+	client := newSequenceClient(t, st)
+	page := bc.NewAPIPage[fakeEntity](client, "fakeEntities")
+
+	patch := customerPatch{CurrencyID: bc.Null[uuid.UUID](), Blocked: "Invoice"}
+	if _, err := page.Update(context.Background(), uuid.MustParse(validGUID), nil, bc.WriteBody(patch)); err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(st.Requests[0].Body)
+	if want := `{"currencyId":null,"blocked":"Invoice"}`; string(body) != want {
+		t.Errorf("wanted %s, got %s", want, body)
+	}
+}
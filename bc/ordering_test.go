@@ -0,0 +1,70 @@
+package bc_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/erlorenz/bc-go/internal/bctest"
+)
+
+func TestWithStableOrdering(t *testing.T) {
+	table := []struct {
+		name    string
+		orderBy []string
+		want    string
+	}{
+		{"None", nil, "id"},
+		{"Appended", []string{"postingDate desc"}, "postingDate desc,id"},
+		{"AlreadyOrdered", []string{"id desc"}, "id desc"},
+	}
+
+	for _, v := range table {
+		t.Run(v.name, func(t *testing.T) {
+			st := &bctest.SequenceTransport{Responses: []*http.Response{
+				bctest.NewResponse(200, map[string]any{"value": []map[string]any{}}),
+			}}
+			client := newSequenceClient(t, st, bc.WithStableOrdering(""))
+			page := bc.NewAPIPage[fakeEntity](client, "fakeEntities")
+
+			if _, err := page.List(context.Background(), bc.ListOptions{OrderBy: v.orderBy}); err != nil {
+				t.Fatal(err)
+			}
+			if got := st.Requests[0].URL.Query().Get("$orderby"); got != v.want {
+				t.Errorf("wanted $orderby %s, got %s", v.want, got)
+			}
+		})
+	}
+}
+
+func TestWarningOrdering(t *testing.T) {
+	next := "https://api.businesscentral.dynamics.com/v2.0/next?$skiptoken=abc"
+	newPage := func() *http.Response {
+		return bctest.NewResponse(200, map[string]any{"value": []map[string]any{{"ID": validGUID}}, "@odata.nextLink": next})
+	}
+
+	var got []bc.Warning
+	handler := bc.WithWarningHandler(func(ctx context.Context, w bc.Warning) {
+		got = append(got, w)
+	})
+
+	st := &bctest.SequenceTransport{Responses: []*http.Response{newPage()}}
+	page := bc.NewAPIPage[fakeEntity](newSequenceClient(t, st, handler), "fakeEntities")
+	if _, err := page.ListPage(context.Background(), "", bc.ListOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].Kind != bc.WarningOrdering || got[0].EntitySetName != "fakeEntities" {
+		t.Fatalf("wanted an ordering warning, got %+v", got)
+	}
+
+	got = nil
+	st = &bctest.SequenceTransport{Responses: []*http.Response{newPage()}}
+	page = bc.NewAPIPage[fakeEntity](newSequenceClient(t, st, handler, bc.WithStableOrdering("id")), "fakeEntities")
+	if _, err := page.ListPage(context.Background(), "", bc.ListOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 0 {
+		t.Errorf("wanted no warning with stable ordering, got %+v", got)
+	}
+}
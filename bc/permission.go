@@ -0,0 +1,96 @@
+package bc
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// PermissionError is the object and permission parsed from the message of an [APIError]
+// for missing permissions, so the permission set can be assigned to the user or application.
+// Get it with errors.As from any error that wraps the APIError:
+//
+//	var pe bc.PermissionError
+//	if errors.As(err, &pe) {
+//		log.Printf("missing %s on %s %s, assign %s", pe.Permission, pe.ObjectType, pe.ObjectName, pe.PermissionSets)
+//	}
+//
+// The fields are best-effort and empty if the message is not in a recognized format.
+type PermissionError struct {
+	APIError
+	// ObjectType is e.g. "TableData", "Page" or "Codeunit".
+	ObjectType string
+	// ObjectID is 0 if the message only has the name.
+	ObjectID   int
+	ObjectName string
+	// Permission is e.g. "Read", "Insert", "Modify", "Delete" or "Execute".
+	Permission string
+	// PermissionSets are the permission sets BC suggests, as in the message, e.g. "D365 SALES DOC, EDIT".
+	// Names can contain commas, so it is not split.
+	PermissionSets string
+}
+
+func (pe PermissionError) Error() string {
+	if pe.Permission == "" {
+		return pe.APIError.Error()
+	}
+
+	object := strings.TrimSpace(pe.ObjectType + " " + pe.ObjectName)
+	if pe.ObjectID != 0 {
+		object = fmt.Sprintf("%s %d %s", pe.ObjectType, pe.ObjectID, pe.ObjectName)
+	}
+	msg := fmt.Sprintf("missing permission %s on %s", pe.Permission, object)
+	if pe.PermissionSets != "" {
+		msg += ", included in " + pe.PermissionSets
+	}
+	return fmt.Sprintf("[%d %s] %s", pe.StatusCode, pe.Code, msg)
+}
+
+func (pe PermissionError) Unwrap() error {
+	return pe.APIError
+}
+
+// As sets a *PermissionError target if the APIError is for missing permissions.
+func (err APIError) As(target any) bool {
+	pe, ok := target.(*PermissionError)
+	if !ok || !err.isPermissionDenied() {
+		return false
+	}
+	*pe = parsePermissionError(err)
+	return true
+}
+
+// isPermissionDenied is true for authorization errors, not for invalid credentials or tokens.
+func (err APIError) isPermissionDenied() bool {
+	return err.StatusCode == http.StatusForbidden || err.Code == ErrorCodePermissionDenied || err.Code.Category() == "Authorization"
+}
+
+var (
+	objectTypes = `(TableData|Table|Page|Codeunit|Report|XMLport|Query|System)`
+	// Sorry, the current permissions prevented the action. (TableData 36 Sales Header Modify: Base Application)
+	permissionObjectPattern = regexp.MustCompile(`\(` + objectTypes + ` (?:(\d+) )?(.+?) (Read|Insert|Modify|Delete|Execute)(?::[^)]*)?\)`)
+	// You do not have the following permissions on TableData Sales Header: Insert.
+	permissionOnPattern = regexp.MustCompile(`permissions on ` + objectTypes + ` (?:(\d+) )?(.+?): (Read|Insert|Modify|Delete|Execute)`)
+	// The following permission sets include the required permissions: D365 SALES DOC, EDIT.
+	permissionSetsPattern = regexp.MustCompile(`(?i)permission sets?\b[^:\n]*:\s*([^\n]+?)\.?(?:\n|$)`)
+)
+
+func parsePermissionError(err APIError) PermissionError {
+	pe := PermissionError{APIError: err}
+
+	m := permissionObjectPattern.FindStringSubmatch(err.Message)
+	if m == nil {
+		m = permissionOnPattern.FindStringSubmatch(err.Message)
+	}
+	if m != nil {
+		pe.ObjectType, pe.ObjectName, pe.Permission = m[1], strings.TrimSpace(m[3]), m[4]
+		pe.ObjectID, _ = strconv.Atoi(m[2])
+	}
+
+	if m := permissionSetsPattern.FindStringSubmatch(err.Message); m != nil {
+		pe.PermissionSets = strings.TrimSpace(m[1])
+	}
+	return pe
+}
@@ -0,0 +1,97 @@
+package bc_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/erlorenz/bc-go/internal/bctest"
+	"github.com/google/uuid"
+)
+
+func permissionResponse(status int, code, message string) *http.Response {
+	return bctest.NewResponse(status, map[string]any{"error": map[string]any{"code": code, "message": message}})
+}
+
+func TestPermissionError(t *testing.T) {
+	table := []struct {
+		name    string
+		message string
+		want    bc.PermissionError
+	}{
+		{
+			"ObjectWithID",
+			"Sorry, the current permissions prevented the action. (TableData 36 Sales Header Modify: Base Application) The following permission sets include the required permissions: D365 SALES DOC, EDIT.",
+			bc.PermissionError{ObjectType: "TableData", ObjectID: 36, ObjectName: "Sales Header", Permission: "Modify", PermissionSets: "D365 SALES DOC, EDIT"},
+		},
+		{
+			"ObjectName",
+			"You do not have the following permissions on TableData Cust. Ledger Entry: Insert.\n\nTo view details about your permissions, see the Effective Permissions page.",
+			bc.PermissionError{ObjectType: "TableData", ObjectName: "Cust. Ledger Entry", Permission: "Insert"},
+		},
+		{
+			"Unrecognized",
+			"Access denied.",
+			bc.PermissionError{},
+		},
+	}
+
+	for _, v := range table {
+		t.Run(v.name, func(t *testing.T) {
+			st := &bctest.SequenceTransport{Responses: []*http.Response{
+				permissionResponse(http.StatusForbidden, string(bc.ErrorCodePermissionDenied), v.message),
+			}}
+			page := bc.NewAPIPage[fakeEntity](newSequenceClient(t, st), "fakeEntities")
+
+			_, err := page.Get(context.Background(), uuid.New(), bc.GetOptions{})
+
+			var pe bc.PermissionError
+			if !errors.As(err, &pe) {
+				t.Fatalf("wanted PermissionError, got %v", err)
+			}
+			if pe.ObjectType != v.want.ObjectType || pe.ObjectID != v.want.ObjectID || pe.ObjectName != v.want.ObjectName ||
+				pe.Permission != v.want.Permission || pe.PermissionSets != v.want.PermissionSets {
+				t.Errorf("wanted %+v, got %+v", v.want, pe)
+			}
+			if pe.StatusCode != http.StatusForbidden {
+				t.Errorf("wanted the APIError, got %+v", pe.APIError)
+			}
+
+			var apiErr bc.APIError
+			if !errors.As(pe, &apiErr) {
+				t.Error("wanted PermissionError to unwrap to APIError")
+			}
+		})
+	}
+}
+
+func TestPermissionErrorMessage(t *testing.T) {
+	st := &bctest.SequenceTransport{Responses: []*http.Response{
+		permissionResponse(http.StatusForbidden, string(bc.ErrorCodePermissionDenied),
+			"Sorry, the current permissions prevented the action. (TableData 36 Sales Header Modify: Base Application) The following permission sets include the required permissions: D365 SALES DOC, EDIT."),
+	}}
+	page := bc.NewAPIPage[fakeEntity](newSequenceClient(t, st), "fakeEntities")
+	_, err := page.Get(context.Background(), uuid.New(), bc.GetOptions{})
+
+	var pe bc.PermissionError
+	errors.As(err, &pe)
+	if want := "missing permission Modify on TableData 36 Sales Header, included in D365 SALES DOC, EDIT"; !strings.Contains(pe.Error(), want) {
+		t.Errorf("wanted %q in %q", want, pe.Error())
+	}
+}
+
+func TestPermissionErrorNotPermission(t *testing.T) {
+	st := &bctest.SequenceTransport{Responses: []*http.Response{
+		permissionResponse(http.StatusUnauthorized, string(bc.ErrorCodeInvalidCredentials), "The credentials provided are incorrect"),
+	}}
+	page := bc.NewAPIPage[fakeEntity](newSequenceClient(t, st), "fakeEntities")
+	_, err := page.Get(context.Background(), uuid.New(), bc.GetOptions{})
+
+	var pe bc.PermissionError
+	if errors.As(err, &pe) {
+		t.Errorf("wanted no PermissionError for invalid credentials, got %+v", pe)
+	}
+}
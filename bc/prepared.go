@@ -0,0 +1,172 @@
+package bc
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// PreparedRequest is a request that [Client.Prepare] validated and built once, so it can
+// be created many times in a hot loop with only the parts in [PreparedParams] changing.
+// It is safe for concurrent use.
+type PreparedRequest struct {
+	client *Client
+	opts   RequestOptions
+	// prefix is the URL up to the entity set, before any record ID.
+	prefix string
+	// query and listQuery are the encoded static query params without the $filter,
+	// listQuery with the stable ordering for requests without a RecordID.
+	query     string
+	listQuery string
+	filter    string
+}
+
+// PreparedParams are the parts of a [PreparedRequest] that change between requests.
+// They are not checked by the [RequestValidator] functions.
+type PreparedParams struct {
+	// RecordID replaces the RecordID of the prepared options if set.
+	RecordID uuid.UUID
+	// Filter is combined with the prepared $filter with "and". Only for GET.
+	Filter string
+	// QueryParams are added after the prepared query params.
+	QueryParams QueryParams
+	Body        any
+	ETag        string
+}
+
+// Prepare validates the options and builds the URL and encoded query params once.
+// The RecordID can be left empty for methods that need one and set in the [PreparedParams].
+func (c *Client) Prepare(opts RequestOptions) (*PreparedRequest, error) {
+	check := opts
+	if check.RecordID == uuid.Nil {
+		// The RecordID is set for each request
+		check.RecordID = uuid.Max
+	}
+	if err := c.validateRequest(check); err != nil {
+		return nil, err
+	}
+
+	p := &PreparedRequest{client: c, opts: opts}
+
+	p.prefix = c.entitySetPrefix(opts.EntitySetName)
+
+	static := slices.Clone(opts.QueryParams)
+	p.filter = static.Get("$filter")
+	static.Del("$filter")
+	p.query = static.Encode()
+	p.listQuery = p.query
+	if c.tiebreaker != "" && opts.Method == http.MethodGet {
+		p.listQuery = withTiebreaker(static, c.tiebreaker).Encode()
+	}
+	return p, nil
+}
+
+// NewRequest creates the http.Request with the params.
+func (p *PreparedRequest) NewRequest(ctx context.Context, params PreparedParams) (*http.Request, error) {
+	opts := p.opts
+	opts.RecordID = cmp.Or(params.RecordID, opts.RecordID)
+	opts.ETag = cmp.Or(params.ETag, opts.ETag)
+	if params.Body != nil {
+		opts.Body = params.Body
+	}
+	if opts.Method == http.MethodPatch && opts.RecordID == uuid.Nil {
+		return nil, errors.New("invalid prepared params: cannot have method PATCH with no RecordID")
+	}
+	if params.Filter != "" && opts.Method != http.MethodGet {
+		return nil, fmt.Errorf("invalid prepared params: cannot have Filter with method %s", opts.Method)
+	}
+
+	filter := p.filter
+	if params.Filter != "" && filter != "" {
+		filter = "(" + filter + ") and (" + params.Filter + ")"
+	} else if params.Filter != "" {
+		filter = params.Filter
+	}
+
+	extra := params.QueryParams
+	if filter != "" {
+		extra = append(QueryParams{{Key: "$filter", Value: filter}}, extra...)
+	}
+
+	var b strings.Builder
+	b.Grow(len(p.prefix) + encodedRecordIDLen + len(p.listQuery) + 2 + extra.encodedLen())
+	b.WriteString(p.prefix)
+	query := p.listQuery
+	if opts.RecordID != uuid.Nil {
+		writeRecordID(&b, opts.RecordID)
+		query = p.query
+	}
+	sep := "?"
+	if query != "" {
+		b.WriteString(sep)
+		b.WriteString(query)
+		sep = "&"
+	}
+	extra.writeEncoded(&b, sep)
+
+	rawURL := b.String()
+	if err := p.client.checkURLLength(rawURL); err != nil {
+		return nil, err
+	}
+	return p.client.newRequest(ctx, rawURL, opts)
+}
+
+// PreparedList is a list request of an [APIPage] prepared by [APIPage.PrepareList].
+type PreparedList[T Validator] struct {
+	page *APIPage[T]
+	req  *PreparedRequest
+}
+
+// PrepareList prepares a list request with the options, e.g. to look up records by number
+// in a loop with a different filter each time.
+func (a *APIPage[T]) PrepareList(opts ListOptions) (*PreparedList[T], error) {
+	req, err := a.client.Prepare(RequestOptions{
+		Method:        http.MethodGet,
+		EntitySetName: a.entitySetName,
+		QueryParams:   opts.BuildQueryParams(a.BaseFilter, a.BaseExpand),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &PreparedList[T]{page: a, req: req}, nil
+}
+
+// List gets the records with the filter combined with the prepared filter.
+func (pl *PreparedList[T]) List(ctx context.Context, filter string) ([]T, error) {
+	var v []T
+	c := pl.page.client
+
+	req, err := pl.req.NewRequest(ctx, PreparedParams{Filter: filter})
+	if err != nil {
+		return v, fmt.Errorf("failed to create Request: %w", err)
+	}
+
+	res, err := c.Do(req)
+	if err != nil {
+		return v, fmt.Errorf("failed during request: %w", err)
+	}
+
+	list, err := decode[APIListResponse[T]](c, res)
+	if err != nil {
+		var srvErr APIError
+		if errors.As(err, &srvErr) {
+			c.logger.Debug("API server returned error response.", "error", srvErr)
+			return v, fmt.Errorf("error from BC API: %w", srvErr)
+		}
+
+		c.logger.Debug("Unable to decode response.", "error", err)
+		return v, fmt.Errorf("decode response: %w", err)
+	}
+	v = list.Value
+
+	if err := validateList(c, pl.page.entitySetName, v); err != nil {
+		return v, err
+	}
+	return v, nil
+}
@@ -0,0 +1,111 @@
+package bc_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/erlorenz/bc-go/internal/bctest"
+	"github.com/google/uuid"
+)
+
+func TestPrepare(t *testing.T) {
+	calls := 0
+	validator := func(bc.RequestOptions) error {
+		calls++
+		return nil
+	}
+	client, err := bc.NewClient(fakeConfig, bc.WithAuthClient(fakeTokenGetter{}), bc.WithRequestValidator("", validator))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	qp := bc.QueryParams{}
+	qp.Set("$select", "id,number")
+	qp.Set("$filter", "blocked eq false")
+	p, err := client.Prepare(bc.RequestOptions{Method: http.MethodGet, EntitySetName: "fakeEntities", QueryParams: qp})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, number := range []string{"1000", "2000"} {
+		req, err := p.NewRequest(context.Background(), bc.PreparedParams{Filter: "number eq '" + number + "'"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := bc.QueryParams{}
+		want.Set("$select", "id,number")
+		want.Set("$filter", "(blocked eq false) and (number eq '"+number+"')")
+		wantReq, err := client.NewRequest(context.Background(), bc.RequestOptions{Method: http.MethodGet, EntitySetName: "fakeEntities", QueryParams: want})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if req.URL.String() != wantReq.URL.String() {
+			t.Errorf("wanted URL %s, got %s", wantReq.URL, req.URL)
+		}
+		if req.Header.Get("Authorization") == "" {
+			t.Error("expected Authorization header")
+		}
+	}
+
+	// Once by Prepare and once by each NewRequest of the client
+	if calls != 3 {
+		t.Errorf("wanted validators to run once for the prepared request, got %d calls", calls)
+	}
+}
+
+func TestPrepareRecordID(t *testing.T) {
+	client, err := bc.NewClient(fakeConfig, bc.WithAuthClient(fakeTokenGetter{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := client.Prepare(bc.RequestOptions{Method: http.MethodPatch, EntitySetName: "fakeEntities"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := p.NewRequest(context.Background(), bc.PreparedParams{Body: map[string]string{"number": "1"}}); err == nil {
+		t.Error("expected error for PATCH without RecordID")
+	}
+
+	id := uuid.New()
+	req, err := p.NewRequest(context.Background(), bc.PreparedParams{RecordID: id, Body: map[string]string{"number": "1"}, ETag: `W/"1"`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "/api/publisher/group/1.0/companies(" + fakeConfig.CompanyID + ")/fakeEntities(" + id.String() + ")"; req.URL.Path[len(req.URL.Path)-len(want):] != want {
+		t.Errorf("wanted path ending %s, got %s", want, req.URL.Path)
+	}
+	if req.Header.Get("If-Match") != `W/"1"` || readBody(t, req) != `{"number":"1"}` {
+		t.Errorf("unexpected request %v", req.Header)
+	}
+}
+
+func TestPrepareList(t *testing.T) {
+	st := &bctest.SequenceTransport{Responses: []*http.Response{
+		bctest.NewResponse(200, map[string]any{"value": []map[string]any{{"ID": validGUID, "Number": "1000"}}}),
+	}}
+	client := newSequenceClient(t, st, bc.WithStableOrdering("id"))
+	page := bc.NewAPIPage[fakeEntity](client, "fakeEntities")
+	page.BaseFilter = "blocked eq false"
+
+	pl, err := page.PrepareList(bc.ListOptions{Top: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	records, err := pl.List(context.Background(), "number eq '1000'")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 || records[0].Number != "1000" {
+		t.Errorf("unexpected records %+v", records)
+	}
+
+	q := st.Requests[0].URL.Query()
+	if q.Get("$filter") != "(blocked eq false) and (number eq '1000')" || q.Get("$orderby") != "id" || q.Get("$top") != "1" {
+		t.Errorf("unexpected query %s", st.Requests[0].URL.RawQuery)
+	}
+}
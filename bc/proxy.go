@@ -0,0 +1,76 @@
+package bc
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/http/httpproxy"
+)
+
+// ProxyOptions configure an outbound proxy with [WithProxy].
+type ProxyOptions struct {
+	// URL of the proxy, e.g. "http://proxy.corp.example:8080". It is used for
+	// both http and https requests.
+	URL string
+	// Username and Password authenticate with the proxy using basic auth.
+	// They override any user info in the URL.
+	Username string
+	Password string
+	// NoProxy lists the hosts that bypass the proxy, with the same rules as NO_PROXY:
+	// "example.com" matches the host and its subdomains, ".example.com" only the subdomains,
+	// an IP address or CIDR range matches IPs, a ":port" suffix limits the port and "*" matches all.
+	// Requests to localhost never use the proxy.
+	NoProxy []string
+}
+
+// ProxyFunc returns a function for http.Transport.Proxy.
+func (p ProxyOptions) ProxyFunc() (func(*http.Request) (*url.URL, error), error) {
+	u, err := url.Parse(p.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy url: %w", err)
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("invalid proxy url: %q must have a scheme and host", p.URL)
+	}
+	if p.Username != "" {
+		u.User = url.UserPassword(p.Username, p.Password)
+	}
+
+	config := httpproxy.Config{
+		HTTPProxy:  u.String(),
+		HTTPSProxy: u.String(),
+		NoProxy:    strings.Join(p.NoProxy, ","),
+	}
+	proxy := config.ProxyFunc()
+
+	return func(r *http.Request) (*url.URL, error) {
+		return proxy(r.URL)
+	}, nil
+}
+
+// withProxy returns a copy of the http.Client with a copy of its transport that uses the proxy.
+// The transport must be an *http.Transport or nil for http.DefaultTransport.
+func withProxy(hc *http.Client, p ProxyOptions) (*http.Client, error) {
+	proxy, err := p.ProxyFunc()
+	if err != nil {
+		return nil, err
+	}
+
+	var transport *http.Transport
+	switch t := hc.Transport.(type) {
+	case nil:
+		transport = http.DefaultTransport.(*http.Transport).Clone()
+	case *http.Transport:
+		transport = t.Clone()
+	default:
+		return nil, errors.New("proxy: the transport of the http.Client must be an *http.Transport")
+	}
+	transport.Proxy = proxy
+
+	proxied := *hc
+	proxied.Transport = transport
+	return &proxied, nil
+}
@@ -28,9 +28,14 @@ type ListOptions struct {
 	Filter  string   // The filter expression. Combined with the BaseFilter.
 	Expand  []string // The expandable fields. Added to the BaseExpand.
 	OrderBy []string // The fields to order by, e.g. "field1 desc" or "field1". Ascending is default.
-	Select  []string // The fields to return.
+	Select  []string // The fields to return, sent as $select.
 	Skip    int      // The number of records to skip. Do not use for pagination.
 	Top     int      // The number of records to return. Do not use for pagination.
+	// MaxPageSize is sent as the "Prefer: odata.maxpagesize" header by ListPage.
+	// BC defaults to 20000 records per page.
+	MaxPageSize int
+	// Cursor continues a listing from [APIListResponse.Cursor] with the same options.
+	Cursor Cursor
 }
 
 // BuildQueryParams combines the base filter/expand with the provided ListQueryOptions to return QueryParams
@@ -64,23 +69,33 @@ func (q *ListOptions) BuildQueryParams(baseFilter string, baseExpand []string) Q
 
 	// Set $filter if exists
 	if filter != "" {
-		qp["$filter"] = filter
+		qp.Set("$filter", filter)
 	}
 
 	// Set $expand if exists
 	if expand != "" {
-		qp["$expand"] = expand
+		qp.Set("$expand", expand)
+	}
+
+	// Set $select if exists, so a List or an export only gets the fields it needs
+	if len(q.Select) > 0 {
+		qp.Set("$select", strings.Join(q.Select, ","))
 	}
 
 	if len(q.OrderBy) > 0 {
-		qp["$orderby"] = strings.Join(q.OrderBy, ",")
+		qp.Set("$orderby", strings.Join(q.OrderBy, ","))
 	}
 
 	// Set $top if exists
 	if q.Top != 0 {
-		qp["$top"] = strconv.Itoa(q.Top)
+		qp.Set("$top", strconv.Itoa(q.Top))
+
+		qp.Set("$skip", strconv.Itoa(q.Skip))
+	}
 
-		qp["$skip"] = strconv.Itoa(q.Skip)
+	// An invalid cursor is returned as an error by List and ListPage
+	if token, err := q.Cursor.SkipToken(); err == nil && token != "" {
+		qp.Set("$skiptoken", token)
 	}
 
 	return qp
@@ -18,24 +18,24 @@ func TestBuildQueryParams_List(t *testing.T) {
 
 	qp := opts.BuildQueryParams("", nil)
 
-	if qp["$filter"] != filter {
-		t.Errorf(`wrong filter: expected "%s", got "%s"`, filter, qp["$filter"])
+	if qp.Get("$filter") != filter {
+		t.Errorf(`wrong filter: expected "%s", got "%s"`, filter, qp.Get("$filter"))
 	}
 
-	if qp["$expand"] != "salesLines,customer" {
-		t.Errorf(`wrong expand: expected "salesLines,customer", got "%s"`, qp["$expand"])
+	if qp.Get("$expand") != "salesLines,customer" {
+		t.Errorf(`wrong expand: expected "salesLines,customer", got "%s"`, qp.Get("$expand"))
 	}
 
-	if qp["$top"] != "5" {
-		t.Errorf(`wrong top: expected "5", got "%s"`, qp["$top"])
+	if qp.Get("$top") != "5" {
+		t.Errorf(`wrong top: expected "5", got "%s"`, qp.Get("$top"))
 	}
 
-	if qp["$skip"] != "0" {
-		t.Errorf(`wrong skip: expected "0", got "%s"`, qp["$skip"])
+	if qp.Get("$skip") != "0" {
+		t.Errorf(`wrong skip: expected "0", got "%s"`, qp.Get("$skip"))
 	}
 
-	if qp["$orderby"] != "number asc" {
-		t.Errorf(`wrong orderby: expected "number asc", got "%s"`, qp["$orderby"])
+	if qp.Get("$orderby") != "number asc" {
+		t.Errorf(`wrong orderby: expected "number asc", got "%s"`, qp.Get("$orderby"))
 	}
 
 	opts = ListOptions{
@@ -45,16 +45,23 @@ func TestBuildQueryParams_List(t *testing.T) {
 	}
 	qp = opts.BuildQueryParams("", nil)
 
-	if qp["$skip"] != "20" {
-		t.Errorf(`wrong skip: expected "20", got "%s"`, qp["$skip"])
+	if qp.Get("$skip") != "20" {
+		t.Errorf(`wrong skip: expected "20", got "%s"`, qp.Get("$skip"))
 	}
 
-	if qp["$orderby"] != "number desc" {
-		t.Errorf(`wrong orderby: expected "number desc", got "%s"`, qp["$orderby"])
+	if qp.Get("$orderby") != "number desc" {
+		t.Errorf(`wrong orderby: expected "number desc", got "%s"`, qp.Get("$orderby"))
 	}
 
 	opts.OrderBy = []string{"number"}
 
+	opts = ListOptions{Select: []string{"id", "displayName"}}
+	qp = opts.BuildQueryParams("", nil)
+
+	if qp.Get("$select") != "id,displayName" {
+		t.Errorf(`wrong select: expected "id,displayName", got "%s"`, qp.Get("$select"))
+	}
+
 }
 
 func TestBuildQueryParamsWithBase_List(t *testing.T) {
@@ -77,20 +84,20 @@ func TestBuildQueryParamsWithBase_List(t *testing.T) {
 
 	qp := opts.BuildQueryParams(baseFilter, baseExpand)
 
-	if qp["$filter"] != expectedFilter {
-		t.Errorf(`wrong filter: expected "%s", got "%s"`, expectedFilter, qp["$filter"])
+	if qp.Get("$filter") != expectedFilter {
+		t.Errorf(`wrong filter: expected "%s", got "%s"`, expectedFilter, qp.Get("$filter"))
 	}
 
-	if qp["$expand"] != expectedExpand {
-		t.Errorf(`wrong expand: expected "%s", got "%s"`, expectedExpand, qp["$expand"])
+	if qp.Get("$expand") != expectedExpand {
+		t.Errorf(`wrong expand: expected "%s", got "%s"`, expectedExpand, qp.Get("$expand"))
 	}
 
-	if qp["$top"] != "5" {
-		t.Errorf(`wrong top: expected "5", got "%s"`, qp["$top"])
+	if qp.Get("$top") != "5" {
+		t.Errorf(`wrong top: expected "5", got "%s"`, qp.Get("$top"))
 	}
 
-	if qp["$orderby"] != "number asc" {
-		t.Errorf(`wrong orderby: expected "number asc", got "%s"`, qp["$orderby"])
+	if qp.Get("$orderby") != "number asc" {
+		t.Errorf(`wrong orderby: expected "number asc", got "%s"`, qp.Get("$orderby"))
 	}
 
 }
@@ -109,8 +116,12 @@ func TestBuildQueryParamsWithBase_Get(t *testing.T) {
 
 	qp := opts.BuildQueryParams(baseExpand)
 
-	if qp["$expand"] != expectedExpand {
-		t.Errorf(`wrong expand: expected "%s", got "%s"`, expectedExpand, qp["$expand"])
+	if qp.Get("$expand") != expectedExpand {
+		t.Errorf(`wrong expand: expected "%s", got "%s"`, expectedExpand, qp.Get("$expand"))
+	}
+
+	if qp.Get("$select") != "id,number" {
+		t.Errorf(`wrong select: expected "id,number", got "%s"`, qp.Get("$select"))
 	}
 
 }
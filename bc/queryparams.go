@@ -0,0 +1,156 @@
+package bc
+
+import (
+	"slices"
+	"strings"
+)
+
+// QueryParam is a single query parameter of a [QueryParams].
+type QueryParam struct {
+	Key   string
+	Value string
+}
+
+// QueryParams are used to build the http.Request url. They keep their order
+// and a key can repeat, e.g.
+//
+//	bc.QueryParams{{Key: "$select", Value: "id,number"}, {Key: "$top", Value: "10"}}
+//
+// Params with an empty value are skipped when encoded.
+type QueryParams []QueryParam
+
+// Get returns the first value of the key, or an empty string.
+func (q QueryParams) Get(key string) string {
+	for _, p := range q {
+		if p.Key == key {
+			return p.Value
+		}
+	}
+	return ""
+}
+
+// Values returns all the values of the key in order.
+func (q QueryParams) Values(key string) []string {
+	var values []string
+	for _, p := range q {
+		if p.Key == key {
+			values = append(values, p.Value)
+		}
+	}
+	return values
+}
+
+// Has returns true if the key is set, even to an empty value.
+func (q QueryParams) Has(key string) bool {
+	return slices.ContainsFunc(q, func(p QueryParam) bool { return p.Key == key })
+}
+
+// Set replaces the value of the first param with the key and removes the others,
+// or adds the param at the end.
+func (q *QueryParams) Set(key, value string) {
+	found := false
+	params := (*q)[:0]
+	for _, p := range *q {
+		if p.Key == key {
+			if found {
+				continue
+			}
+			p.Value, found = value, true
+		}
+		params = append(params, p)
+	}
+	if !found {
+		params = append(params, QueryParam{Key: key, Value: value})
+	}
+	*q = params
+}
+
+// Add adds the param at the end, after any with the same key.
+func (q *QueryParams) Add(key, value string) {
+	*q = append(*q, QueryParam{Key: key, Value: value})
+}
+
+// Del removes all params with the key.
+func (q *QueryParams) Del(key string) {
+	*q = slices.DeleteFunc(*q, func(p QueryParam) bool { return p.Key == key })
+}
+
+// Encode returns the query string in order. Keys and values are percent-encoded per RFC 3986,
+// with spaces as "%20", and the characters OData uses that are allowed in a query,
+// like "$", "'", "(", ")", ",", ":" and "=", are kept as is so the URL stays readable in logs.
+// The ";" of nested $expand options is encoded because many servers treat it as a separator.
+func (q QueryParams) Encode() string {
+	n := q.encodedLen()
+	if n == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.Grow(n)
+	q.writeEncoded(&b, "")
+	return b.String()
+}
+
+// encodedLen estimates the length of the encoded query, exact if nothing is escaped.
+func (q QueryParams) encodedLen() int {
+	n := 0
+	for _, p := range q {
+		if p.Value != "" {
+			n += len(p.Key) + len(p.Value) + 2
+		}
+	}
+	return n
+}
+
+// writeEncoded writes the encoded query to b, after sep if it is not empty.
+func (q QueryParams) writeEncoded(b *strings.Builder, sep string) {
+	for _, p := range q {
+		if p.Value == "" {
+			continue
+		}
+		b.WriteString(sep)
+		sep = "&"
+		escapeQueryComponent(b, p.Key, false)
+		b.WriteByte('=')
+		escapeQueryComponent(b, p.Value, true)
+	}
+}
+
+// escapeQueryComponent writes s percent-encoded. An "=" is kept in values,
+// where it cannot be confused with the separator of the key.
+func escapeQueryComponent(b *strings.Builder, s string, value bool) {
+	const hex = "0123456789ABCDEF"
+	if !needsQueryEscape(s, value) {
+		b.WriteString(s)
+		return
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if shouldKeepInQuery(c) || (value && c == '=') {
+			b.WriteByte(c)
+			continue
+		}
+		b.WriteByte('%')
+		b.WriteByte(hex[c>>4])
+		b.WriteByte(hex[c&15])
+	}
+}
+
+// needsQueryEscape reports whether s has a character that escapeQueryComponent escapes.
+func needsQueryEscape(s string, value bool) bool {
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; !shouldKeepInQuery(c) && !(value && c == '=') {
+			return true
+		}
+	}
+	return false
+}
+
+// shouldKeepInQuery returns true for the unreserved characters and the sub-delims
+// that are not separators in a query component.
+func shouldKeepInQuery(c byte) bool {
+	switch {
+	case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9':
+		return true
+	}
+	return strings.IndexByte("-._~!$'()*,:@/?", c) >= 0
+}
@@ -0,0 +1,84 @@
+package bc_test
+
+import (
+	"net/url"
+	"slices"
+	"testing"
+
+	"github.com/erlorenz/bc-go/bc"
+)
+
+func TestQueryParamsEncode(t *testing.T) {
+	table := []struct {
+		name string
+		qp   bc.QueryParams
+		want string
+	}{
+		{"Empty", nil, ""},
+		{"KeepsOrder", bc.QueryParams{{Key: "$top", Value: "5"}, {Key: "$select", Value: "id"}}, "$top=5&$select=id"},
+		{"SkipsEmptyValues", bc.QueryParams{{Key: "$filter", Value: ""}, {Key: "$top", Value: "5"}}, "$top=5"},
+		{"RepeatedKey", bc.QueryParams{{Key: "tag", Value: "a"}, {Key: "tag", Value: "b"}}, "tag=a&tag=b"},
+		{"Spaces", bc.QueryParams{{Key: "$filter", Value: "number eq '1000'"}}, "$filter=number%20eq%20'1000'"},
+		{"EscapedQuote", bc.QueryParams{{Key: "$filter", Value: "displayName eq 'O''Brien'"}}, "$filter=displayName%20eq%20'O''Brien'"},
+		{"Ampersand", bc.QueryParams{{Key: "$filter", Value: "displayName eq 'A&B'"}}, "$filter=displayName%20eq%20'A%26B'"},
+		{"Plus", bc.QueryParams{{Key: "$filter", Value: "number eq '1+1'"}}, "$filter=number%20eq%20'1%2B1'"},
+		{"EqualsAndHash", bc.QueryParams{{Key: "$filter", Value: "note eq 'a=b#c'"}}, "$filter=note%20eq%20'a=b%23c'"},
+		{"Percent", bc.QueryParams{{Key: "$filter", Value: "discount eq '10%'"}}, "$filter=discount%20eq%20'10%25'"},
+		{"Unicode", bc.QueryParams{{Key: "$filter", Value: "city eq 'Zürich'"}}, "$filter=city%20eq%20'Z%C3%BCrich'"},
+		{"Functions", bc.QueryParams{{Key: "$filter", Value: "startswith(number,'10') and lastModifiedDateTime gt 2024-01-01T00:00:00Z"}}, "$filter=startswith(number,'10')%20and%20lastModifiedDateTime%20gt%202024-01-01T00:00:00Z"},
+		{"GUID", bc.QueryParams{{Key: "$filter", Value: "id eq 6f6c6a9e-1c2b-4b8a-9f4e-0e2d4c3b2a19"}}, "$filter=id%20eq%206f6c6a9e-1c2b-4b8a-9f4e-0e2d4c3b2a19"},
+		{"NestedExpand", bc.QueryParams{{Key: "$expand", Value: "salesOrderLines($select=lineNo,quantity;$top=5)"}}, "$expand=salesOrderLines($select=lineNo,quantity%3B$top=5)"},
+		{"OrderBy", bc.QueryParams{{Key: "$orderby", Value: "number desc,displayName"}}, "$orderby=number%20desc,displayName"},
+		{"Path", bc.QueryParams{{Key: "$filter", Value: "customer/number eq '1'"}}, "$filter=customer/number%20eq%20'1'"},
+		{"KeyEscaped", bc.QueryParams{{Key: "a b", Value: "c"}}, "a%20b=c"},
+	}
+
+	for _, v := range table {
+		t.Run(v.name, func(t *testing.T) {
+			got := v.qp.Encode()
+			if got != v.want {
+				t.Errorf("wanted %s, got %s", v.want, got)
+			}
+
+			// Every encoding must decode back to the same values
+			values, err := url.ParseQuery(got)
+			if err != nil {
+				t.Fatalf("encoded query does not parse: %s", err)
+			}
+			for _, p := range v.qp {
+				if p.Value != "" && !slices.Contains(values[p.Key], p.Value) {
+					t.Errorf("%s: wanted %q after decoding, got %q", p.Key, p.Value, values[p.Key])
+				}
+			}
+		})
+	}
+}
+
+func TestQueryParamsSet(t *testing.T) {
+	qp := bc.QueryParams{{Key: "$top", Value: "5"}, {Key: "tag", Value: "a"}, {Key: "$select", Value: "id"}, {Key: "tag", Value: "b"}}
+
+	qp.Set("tag", "c")
+	qp.Set("$skip", "10")
+	if got, want := qp.Encode(), "$top=5&tag=c&$select=id&$skip=10"; got != want {
+		t.Errorf("wanted %s, got %s", want, got)
+	}
+
+	qp.Add("tag", "d")
+	if got := qp.Values("tag"); !slices.Equal(got, []string{"c", "d"}) {
+		t.Errorf("wanted [c d], got %v", got)
+	}
+
+	qp.Del("tag")
+	if qp.Has("tag") || qp.Get("$top") != "5" || len(qp) != 3 {
+		t.Errorf("unexpected params after Del: %v", qp)
+	}
+
+	var empty bc.QueryParams
+	if empty.Get("$top") != "" || empty.Has("$top") {
+		t.Error("nil QueryParams should be empty")
+	}
+	empty.Set("$top", "1")
+	if empty.Encode() != "$top=1" {
+		t.Errorf("unexpected %s", empty.Encode())
+	}
+}
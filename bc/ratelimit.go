@@ -0,0 +1,225 @@
+package bc
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Priority orders requests waiting for a [RateLimiter]. Set it with [WithPriority].
+type Priority int
+
+const (
+	// PriorityBulk is for background traffic like syncs and imports.
+	PriorityBulk Priority = iota - 1
+	// PriorityNormal is the default.
+	PriorityNormal
+	// PriorityInteractive is for user-facing lookups. Only these can use the Reserve of the RateLimiter.
+	PriorityInteractive
+)
+
+type priorityKey struct{}
+
+// WithPriority returns a context that makes every request sent with it wait for the
+// [RateLimiter] with the priority, including requests made by [APIPage] methods.
+func WithPriority(ctx context.Context, p Priority) context.Context {
+	return context.WithValue(ctx, priorityKey{}, p)
+}
+
+// PriorityFrom returns the Priority set with [WithPriority] or PriorityNormal.
+func PriorityFrom(ctx context.Context) Priority {
+	p, _ := ctx.Value(priorityKey{}).(Priority)
+	return p
+}
+
+// RateLimitOptions configure [NewRateLimiter]. The defaults match the
+// 6000 requests per 5 minutes that BC online allows per user.
+type RateLimitOptions struct {
+	// Rate is the requests per second. Defaults to 20.
+	Rate float64
+	// Burst is the most requests sent at once after being idle. Defaults to 100.
+	Burst int
+	// Reserve is the number of requests of the Burst kept for PriorityInteractive,
+	// so they are not delayed when bulk traffic has nearly used the quota. Defaults to Burst / 10, use -1 for none.
+	Reserve int
+	// MaxWait is how long a request waits before it is treated as PriorityInteractive,
+	// so lower priorities are not starved. Defaults to 30 seconds.
+	MaxWait time.Duration
+}
+
+// RateLimiter is a token bucket with a queue of waiting requests, served in [Priority] order
+// and FIFO within a priority. Use one per environment and share it between clients
+// with [WithRateLimiter]. It is safe for concurrent use.
+type RateLimiter struct {
+	opts RateLimitOptions
+
+	mu      sync.Mutex
+	tokens  float64
+	last    time.Time
+	queue   []*rateWaiter
+	running bool
+	wake    chan struct{}
+}
+
+type rateWaiter struct {
+	priority Priority
+	enqueued time.Time
+	ready    chan struct{}
+}
+
+// NewRateLimiter creates a full [RateLimiter].
+func NewRateLimiter(opts RateLimitOptions) *RateLimiter {
+	if opts.Rate <= 0 {
+		opts.Rate = 20
+	}
+	if opts.Burst <= 0 {
+		opts.Burst = 100
+	}
+	if opts.Reserve == 0 {
+		opts.Reserve = opts.Burst / 10
+	}
+	opts.Reserve = min(max(opts.Reserve, 0), opts.Burst-1)
+	if opts.MaxWait <= 0 {
+		opts.MaxWait = 30 * time.Second
+	}
+
+	return &RateLimiter{
+		opts:   opts,
+		tokens: float64(opts.Burst),
+		last:   time.Now(),
+		wake:   make(chan struct{}, 1),
+	}
+}
+
+// Wait blocks until a request with the priority can be sent or the context is done.
+func (rl *RateLimiter) Wait(ctx context.Context, p Priority) error {
+	rl.mu.Lock()
+	now := time.Now()
+	rl.refill(now)
+	if len(rl.queue) == 0 && rl.tokens >= rl.need(p) {
+		rl.tokens--
+		rl.mu.Unlock()
+		return nil
+	}
+
+	w := &rateWaiter{priority: p, enqueued: now, ready: make(chan struct{})}
+	rl.queue = append(rl.queue, w)
+	if !rl.running {
+		rl.running = true
+		go rl.run()
+	}
+	rl.mu.Unlock()
+
+	select {
+	case rl.wake <- struct{}{}:
+	default:
+	}
+
+	select {
+	case <-w.ready:
+		return nil
+	case <-ctx.Done():
+		rl.mu.Lock()
+		defer rl.mu.Unlock()
+		if i := slices.Index(rl.queue, w); i >= 0 {
+			rl.queue = slices.Delete(rl.queue, i, i+1)
+		} else {
+			// Served at the same time, give the token back
+			rl.tokens = min(rl.tokens+1, float64(rl.opts.Burst))
+		}
+		return ctx.Err()
+	}
+}
+
+// Queued returns the number of waiting requests.
+func (rl *RateLimiter) Queued() int {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	return len(rl.queue)
+}
+
+// Middleware returns a [Middleware] that waits for every request, including retries,
+// with the priority of the request context.
+func (rl *RateLimiter) Middleware() Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			if err := rl.Wait(r.Context(), PriorityFrom(r.Context())); err != nil {
+				return nil, err
+			}
+			return next.RoundTrip(r)
+		})
+	}
+}
+
+// run serves the queue until it is empty, sleeping until there are enough tokens
+// for the next waiter, a waiter reaches MaxWait or a new waiter arrives.
+func (rl *RateLimiter) run() {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	for len(rl.queue) > 0 {
+		now := time.Now()
+		rl.refill(now)
+
+		i := rl.next(now)
+		w := rl.queue[i]
+		need := rl.need(rl.effective(w, now))
+		if rl.tokens >= need {
+			rl.tokens--
+			rl.queue = slices.Delete(rl.queue, i, i+1)
+			close(w.ready)
+			continue
+		}
+
+		delay := time.Duration((need - rl.tokens) / rl.opts.Rate * float64(time.Second))
+		for _, w := range rl.queue {
+			if until := w.enqueued.Add(rl.opts.MaxWait).Sub(now); w.priority < PriorityInteractive && until > 0 {
+				delay = min(delay, until)
+			}
+		}
+
+		rl.mu.Unlock()
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-rl.wake:
+			timer.Stop()
+		}
+		rl.mu.Lock()
+	}
+	rl.running = false
+}
+
+// next returns the index of the waiter with the highest effective priority, the oldest first.
+func (rl *RateLimiter) next(now time.Time) int {
+	best := 0
+	for i, w := range rl.queue {
+		if rl.effective(w, now) > rl.effective(rl.queue[best], now) {
+			best = i
+		}
+	}
+	return best
+}
+
+// effective promotes a waiter to PriorityInteractive after MaxWait.
+func (rl *RateLimiter) effective(w *rateWaiter, now time.Time) Priority {
+	if now.Sub(w.enqueued) >= rl.opts.MaxWait {
+		return PriorityInteractive
+	}
+	return w.priority
+}
+
+// need returns the tokens that must be available for the priority, including the Reserve.
+func (rl *RateLimiter) need(p Priority) float64 {
+	if p >= PriorityInteractive {
+		return 1
+	}
+	return float64(rl.opts.Reserve + 1)
+}
+
+func (rl *RateLimiter) refill(now time.Time) {
+	rl.tokens = min(rl.tokens+now.Sub(rl.last).Seconds()*rl.opts.Rate, float64(rl.opts.Burst))
+	rl.last = now
+}
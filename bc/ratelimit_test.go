@@ -0,0 +1,128 @@
+package bc_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/erlorenz/bc-go/internal/bctest"
+)
+
+func TestPriorityFrom(t *testing.T) {
+	if got := bc.PriorityFrom(context.Background()); got != bc.PriorityNormal {
+		t.Errorf("wanted PriorityNormal, got %d", got)
+	}
+
+	ctx := bc.WithPriority(context.Background(), bc.PriorityBulk)
+	if got := bc.PriorityFrom(ctx); got != bc.PriorityBulk {
+		t.Errorf("wanted PriorityBulk, got %d", got)
+	}
+}
+
+func TestRateLimiterPriority(t *testing.T) {
+	rl := bc.NewRateLimiter(bc.RateLimitOptions{Rate: 50, Burst: 1, Reserve: -1, MaxWait: time.Minute})
+	if err := rl.Wait(context.Background(), bc.PriorityNormal); err != nil {
+		t.Fatal(err)
+	}
+
+	var mu sync.Mutex
+	var order []bc.Priority
+	var wg sync.WaitGroup
+	start := func(p bc.Priority) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := rl.Wait(context.Background(), p); err != nil {
+				t.Error(err)
+			}
+			mu.Lock()
+			order = append(order, p)
+			mu.Unlock()
+		}()
+		// Wait until queued so the order is deterministic
+		for rl.Queued() == 0 {
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	start(bc.PriorityBulk)
+	start(bc.PriorityNormal)
+	start(bc.PriorityInteractive)
+	wg.Wait()
+
+	want := []bc.Priority{bc.PriorityInteractive, bc.PriorityNormal, bc.PriorityBulk}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Fatalf("wanted order %v, got %v", want, order)
+		}
+	}
+}
+
+func TestRateLimiterReserve(t *testing.T) {
+	rl := bc.NewRateLimiter(bc.RateLimitOptions{Rate: 0.001, Burst: 2, Reserve: 1, MaxWait: time.Minute})
+
+	// Bulk can use one token, the other is reserved
+	if err := rl.Wait(context.Background(), bc.PriorityBulk); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := rl.Wait(ctx, bc.PriorityBulk); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("wanted DeadlineExceeded, got %v", err)
+	}
+	if rl.Queued() != 0 {
+		t.Errorf("wanted canceled request removed from the queue, got %d", rl.Queued())
+	}
+
+	if err := rl.Wait(context.Background(), bc.PriorityInteractive); err != nil {
+		t.Fatalf("wanted interactive request to use the reserve, got %v", err)
+	}
+}
+
+func TestRateLimiterStarvation(t *testing.T) {
+	// Bulk needs the whole burst of 10 tokens, or 1 token once promoted after MaxWait
+	rl := bc.NewRateLimiter(bc.RateLimitOptions{Rate: 10, Burst: 10, Reserve: 9, MaxWait: 30 * time.Millisecond})
+	for range 10 {
+		if err := rl.Wait(context.Background(), bc.PriorityInteractive); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	start := time.Now()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := rl.Wait(ctx, bc.PriorityBulk); err != nil {
+		t.Fatalf("wanted bulk request served after MaxWait, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("wanted bulk request promoted after MaxWait, waited %s", elapsed)
+	}
+}
+
+func TestWithRateLimiter(t *testing.T) {
+	rl := bc.NewRateLimiter(bc.RateLimitOptions{Rate: 0.001, Burst: 1, Reserve: -1})
+	st := &bctest.SequenceTransport{Responses: []*http.Response{
+		bctest.NewResponse(http.StatusOK, map[string]any{"value": []any{}}),
+	}}
+	client := newSequenceClient(t, st, bc.WithRateLimiter(rl))
+	page := bc.NewAPIPage[fakeEntity](client, "fakeEntities")
+
+	if _, err := page.List(context.Background(), bc.ListOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := page.List(ctx, bc.ListOptions{})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("wanted DeadlineExceeded, got %v", err)
+	}
+	if st.Count() != 1 {
+		t.Errorf("wanted 1 request sent, got %d", st.Count())
+	}
+}
@@ -0,0 +1,57 @@
+package bc
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Raw sends a request to a URL that the library does not model yet, e.g. an endpoint
+// added in the latest BC release. The relativeURL is resolved against the company URL,
+// so "salesOrders?$top=1" is in the company, "../$metadata" is in the API root and
+// "/v2.0/<tenant>/<environment>/api/microsoft/automation/v2.0/companies" is on the host.
+// Only the auth, default and given headers, retry, read-only and dry-run checks and
+// observability apply: the URL is sent as is and the body is not marshaled.
+// The caller must close the body of the response, which is returned for any status.
+func (c *Client) Raw(ctx context.Context, method, relativeURL string, body io.Reader, headers http.Header) (*http.Response, error) {
+	ref, err := url.Parse(relativeURL)
+	if err != nil {
+		return nil, fmt.Errorf("raw request: invalid url: %w", err)
+	}
+	base := *c.baseURL
+	base.Path = strings.TrimSuffix(base.Path, "/") + "/"
+	base.RawPath = ""
+	base.RawQuery = ""
+	u := base.ResolveReference(ref)
+	if !c.sameOrigin(u) {
+		return nil, fmt.Errorf("raw request: host %s does not match %s", u.Host, c.EnvironmentURL().Host)
+	}
+	if err := c.checkReadOnly(method, u.Path); err != nil {
+		return nil, err
+	}
+
+	// The body is read so that retries can send it again.
+	var b any
+	if body != nil {
+		data, err := io.ReadAll(body)
+		if err != nil {
+			return nil, fmt.Errorf("raw request: read body: %w", err)
+		}
+		b = mediaBody(data)
+	}
+
+	req, err := c.newRequest(ctx, u.String(), RequestOptions{Method: method, Body: b, Header: headers})
+	if err != nil {
+		return nil, fmt.Errorf("raw request: %w", err)
+	}
+	c.logger.Debug("Sending raw request...", "method", method, "url", req.URL.String())
+
+	res, err := c.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("raw request: %w", err)
+	}
+	return res, nil
+}
@@ -0,0 +1,85 @@
+package bc_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/erlorenz/bc-go/internal/bctest"
+)
+
+func TestRawURLs(t *testing.T) {
+	company := "/v2.0/" + validGUID + "/Sandbox/api/publisher/group/1.0/companies(" + validGUID + ")"
+	table := []struct {
+		relativeURL string
+		path        string
+		query       string
+	}{
+		{"newEntities?$top=1", company + "/newEntities", "$top=1"},
+		{"../$metadata", "/v2.0/" + validGUID + "/Sandbox/api/publisher/group/1.0/$metadata", ""},
+		{"/v2.0/" + validGUID + "/Sandbox/api/microsoft/automation/v2.0/companies", "/v2.0/" + validGUID + "/Sandbox/api/microsoft/automation/v2.0/companies", ""},
+		{"https://api.businesscentral.dynamics.com" + company + "/items", company + "/items", ""},
+	}
+
+	for _, tt := range table {
+		st := &bctest.SequenceTransport{Responses: []*http.Response{bctest.NewResponse(200, map[string]any{})}}
+		client := newSequenceClient(t, st)
+
+		res, err := client.Raw(context.Background(), http.MethodGet, tt.relativeURL, nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+
+		u := st.Requests[0].URL
+		if u.Path != tt.path || u.RawQuery != tt.query {
+			t.Errorf("%s: wanted %s?%s, got %s", tt.relativeURL, tt.path, tt.query, u)
+		}
+		if st.Requests[0].Header.Get("Authorization") != "Bearer FAKEACCESSTOKEN" {
+			t.Errorf("%s: wanted the Authorization header", tt.relativeURL)
+		}
+	}
+}
+
+func TestRawBodyAndRetry(t *testing.T) {
+	st := &bctest.SequenceTransport{Responses: []*http.Response{
+		errorResponse(http.StatusTooManyRequests, "TooManyRequests"),
+		bctest.NewResponse(http.StatusAccepted, nil),
+	}}
+	client := newSequenceClient(t, st, bc.WithRetryClassifier(bc.DefaultRetryClassifier{BaseDelay: time.Millisecond}))
+
+	res, err := client.Raw(context.Background(), http.MethodPost, "newEntities(1)/Microsoft.NAV.run", strings.NewReader("a,b\n1,2\n"), http.Header{"Content-Type": {"text/csv"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+
+	if res.StatusCode != http.StatusAccepted || st.Count() != 2 {
+		t.Fatalf("wanted the retried response, got %d after %d requests", res.StatusCode, st.Count())
+	}
+	r := st.Requests[1]
+	body, _ := io.ReadAll(r.Body)
+	if string(body) != "a,b\n1,2\n" || r.Header.Get("Content-Type") != "text/csv" {
+		t.Errorf("wanted the body sent as is, got %q with Content-Type %s", body, r.Header.Get("Content-Type"))
+	}
+}
+
+func TestRawRejected(t *testing.T) {
+	st := &bctest.SequenceTransport{}
+	client := newSequenceClient(t, st)
+
+	if _, err := client.Raw(context.Background(), http.MethodGet, "https://example.com/steal", nil, nil); err == nil {
+		t.Error("wanted an error for another host")
+	}
+	if _, err := client.ReadOnly().Raw(context.Background(), http.MethodDelete, "items(1)", nil, nil); !errors.Is(err, bc.ErrReadOnly) {
+		t.Errorf("wanted ErrReadOnly, got %v", err)
+	}
+	if st.Count() != 0 {
+		t.Errorf("wanted no requests, got %d", st.Count())
+	}
+}
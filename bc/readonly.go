@@ -0,0 +1,34 @@
+package bc
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// ErrReadOnly is returned when a client created with [Client.ReadOnly] builds a request
+// with a method other than GET or HEAD.
+var ErrReadOnly = errors.New("read-only client")
+
+// ReadOnly returns a copy of the client that refuses to build POST, PUT, PATCH and DELETE
+// requests, including bound actions, media uploads and the items of [Client.Batch],
+// and returns an error matching [ErrReadOnly] instead. Use it for reporting services, or
+// to hand least-privilege access to parts of a codebase that share one configured client.
+func (c *Client) ReadOnly() *Client {
+	ro := *c
+	ro.readOnly = true
+	return &ro
+}
+
+// IsReadOnly returns true if the client was created with [Client.ReadOnly].
+func (c *Client) IsReadOnly() bool {
+	return c.readOnly
+}
+
+// checkReadOnly returns an error for a mutating method on a read-only client.
+func (c *Client) checkReadOnly(method, target string) error {
+	if c.readOnly && method != http.MethodGet && method != http.MethodHead {
+		return fmt.Errorf("%w: cannot %s %s", ErrReadOnly, method, target)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package bc_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/erlorenz/bc-go/internal/bctest"
+	"github.com/google/uuid"
+)
+
+func TestReadOnly(t *testing.T) {
+	st := &bctest.SequenceTransport{Responses: []*http.Response{bctest.NewResponse(200, map[string]any{"value": []any{}})}}
+	client := newSequenceClient(t, st)
+
+	ro := client.ReadOnly()
+	if !ro.IsReadOnly() || client.IsReadOnly() {
+		t.Fatal("expected only the copy to be read-only")
+	}
+
+	page := bc.NewAPIPage[fakeEntity](ro, "fakeEntities")
+	if _, err := page.List(context.Background(), bc.ListOptions{}); err != nil {
+		t.Fatalf("expected GET to work, got %v", err)
+	}
+
+	id := uuid.New()
+	table := []struct {
+		name string
+		call func() error
+	}{
+		{"Create", func() error {
+			_, err := page.Create(context.Background(), map[string]any{"Number": "1000"}, bc.GetOptions{})
+			return err
+		}},
+		{"Update", func() error {
+			_, err := page.Update(context.Background(), id, nil, map[string]any{"Number": "1000"})
+			return err
+		}},
+		{"Delete", func() error { return page.Delete(context.Background(), id) }},
+		{"Action", func() error { return page.InvokeAction(context.Background(), id, "post", nil) }},
+		{"Media", func() error {
+			return ro.UploadMedia(context.Background(), "items("+id.String()+")/picture/pictureContent", strings.NewReader("x"), "image/png")
+		}},
+		{"URL", func() error {
+			_, err := ro.NewRequestURL(context.Background(), http.MethodPatch, "https://api.businesscentral.dynamics.com/v2.0/x", nil)
+			return err
+		}},
+		{"Batch", func() error {
+			_, err := ro.Batch(context.Background(), []bc.RequestOptions{
+				{Method: http.MethodGet, EntitySetName: "fakeEntities"},
+				{Method: http.MethodDelete, EntitySetName: "fakeEntities", RecordID: id},
+			}, bc.BatchOptions{})
+			return err
+		}},
+	}
+
+	for _, v := range table {
+		t.Run(v.name, func(t *testing.T) {
+			if err := v.call(); !errors.Is(err, bc.ErrReadOnly) {
+				t.Errorf("expected ErrReadOnly, got %v", err)
+			}
+		})
+	}
+
+	if st.Count() != 1 {
+		t.Errorf("expected only the GET sent, got %d requests", st.Count())
+	}
+}
@@ -0,0 +1,325 @@
+package bc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// EnvironmentResolver returns the current root URL of the environment, e.g.
+// "https://api.businesscentral.dynamics.com/v2.0/{tenantID}/{environment}", for
+// [WithRebase]. The requests of the client in it are sent to the configured URL.
+type EnvironmentResolver func(ctx context.Context, client *Client) (*url.URL, error)
+
+// RebaseOptions configure [WithRebase].
+type RebaseOptions struct {
+	// Resolver defaults to [ProbeEnvironment].
+	Resolver EnvironmentResolver
+	// MinInterval is the least time between two resolutions, so a misconfigured
+	// environment does not resolve on every request. Defaults to 1 minute.
+	MinInterval time.Duration
+	// OnRebase is called after the client moved from one root URL to another.
+	OnRebase func(from, to *url.URL)
+	// AllowedHosts are the hosts the client may move to, as the Authorization header is
+	// sent there. A "*." prefix matches any subdomain. The configured host is always
+	// allowed. Defaults to DefaultRebaseHosts.
+	AllowedHosts []string
+}
+
+// DefaultRebaseHosts are the AllowedHosts of [RebaseOptions] in the public cloud.
+var DefaultRebaseHosts = []string{"*.businesscentral.dynamics.com"}
+
+// WithRebase makes the client follow the environment when its URL changes, e.g. after
+// the tenant is moved or the environment is renamed, instead of failing until a restart.
+// A permanent or temporary redirect of the gateway moves the client to the redirected
+// URL if its host is allowed. A 404 with [ErrorCodeTenantNotFound] or [ErrorCodeEnvironmentNotFound], or a
+// [GatewayTenantMoving] failure, resolves the environment with the Resolver and sends the
+// request once more if the root URL changed. Later requests are sent to the new root.
+func WithRebase(opts RebaseOptions) ClientOption {
+	return func(client *Client) {
+		client.rebaser = &rebaser{client: client, opts: opts}
+	}
+}
+
+// EnvironmentURL returns the root URL of the environment that requests are sent to,
+// which differs from the configured one after a rebase by [WithRebase].
+func (c *Client) EnvironmentURL() *url.URL {
+	if c.rebaser != nil {
+		if current := c.rebaser.current.Load(); current != nil {
+			u := *current
+			return &u
+		}
+	}
+	return environmentRoot(c.baseURL)
+}
+
+// Rebase resolves the environment with the Resolver of [WithRebase] right away, e.g.
+// after an update notice, and returns true if the root URL changed.
+func (c *Client) Rebase(ctx context.Context) (bool, error) {
+	if c.rebaser == nil {
+		return false, errors.New("rebase: client has no WithRebase option")
+	}
+	return c.rebaser.resolve(ctx, true)
+}
+
+// ProbeEnvironment is the default [EnvironmentResolver]. It lists the companies of the
+// configured environment and returns the root of the URL that the gateway redirects
+// the request to, or the configured root if it is not redirected.
+func ProbeEnvironment(ctx context.Context, client *Client) (*url.URL, error) {
+	root := environmentRoot(client.baseURL)
+	u := *root
+	u.Path += "/api/v2.0/companies"
+	u.RawQuery = "$select=id&$top=1"
+
+	ctx = context.WithValue(ctx, rebaseSkipKey{}, true)
+	req, err := client.newRequest(ctx, u.String(), RequestOptions{Method: http.MethodGet})
+	if err != nil {
+		return nil, fmt.Errorf("probe environment: %w", err)
+	}
+	// The redirect is read instead of followed, which would drop the Authorization header.
+	hc := *client.baseClient
+	hc.CheckRedirect = func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }
+	res, err := hc.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("probe environment: %w", err)
+	}
+	defer res.Body.Close()
+
+	if isRedirect(res.StatusCode) {
+		loc, err := res.Location()
+		if err != nil {
+			return nil, fmt.Errorf("probe environment: %w", err)
+		}
+		path, ok := strings.CutSuffix(loc.Path, "/api/v2.0/companies")
+		if !ok {
+			return nil, fmt.Errorf("probe environment: unexpected redirect to %s", loc)
+		}
+		moved := &url.URL{Scheme: loc.Scheme, Host: loc.Host, Path: path}
+		if !client.rebaseAllowed(moved) {
+			return nil, fmt.Errorf("probe environment: redirect to a host that is not allowed: %s", loc.Host)
+		}
+		return moved, nil
+	}
+	if res.StatusCode >= 400 {
+		return nil, fmt.Errorf("probe environment: %w", decodeErrorResponse(res))
+	}
+	return root, nil
+}
+
+// sameOrigin returns true if the URL is on the scheme and host of the configured base
+// URL or of the [Client.EnvironmentURL] after a rebase, so the Authorization header is
+// never sent elsewhere.
+func (c *Client) sameOrigin(u *url.URL) bool {
+	if u.Scheme == c.baseURL.Scheme && u.Host == c.baseURL.Host {
+		return true
+	}
+	env := c.EnvironmentURL()
+	return u.Scheme == env.Scheme && u.Host == env.Host
+}
+
+// environmentRoot returns the URL up to the environment of a company URL.
+func environmentRoot(companyURL *url.URL) *url.URL {
+	u := *companyURL
+	if i := strings.Index(u.Path, "/api/"); i >= 0 {
+		u.Path = u.Path[:i]
+	}
+	u.RawPath, u.RawQuery = "", ""
+	return &u
+}
+
+// rebaseSkipKey marks the requests of a resolver, which are sent to the configured URL.
+type rebaseSkipKey struct{}
+
+// rebaser is the middleware of [WithRebase].
+type rebaser struct {
+	client  *Client
+	opts    RebaseOptions
+	current atomic.Pointer[url.URL]
+
+	mu       sync.Mutex
+	resolved time.Time
+}
+
+func (rb *rebaser) middleware(next http.RoundTripper) http.RoundTripper {
+	return RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		if r.Context().Value(rebaseSkipKey{}) != nil || strings.HasPrefix(r.URL.Path, "/admin/") {
+			return next.RoundTrip(r)
+		}
+		current := rb.root()
+		res, err := next.RoundTrip(rb.rewrite(r, current))
+		if err != nil {
+			return res, err
+		}
+
+		var changed bool
+		switch {
+		case isRedirect(res.StatusCode):
+			changed = rb.followRedirect(r, current, res)
+		case rb.moved(res):
+			var rerr error
+			if changed, rerr = rb.resolve(r.Context(), false); rerr != nil {
+				rb.client.logger.Debug("Could not resolve the environment.", "error", rerr)
+			}
+		}
+		// The request is sent again rather than followed by the http.Client, which
+		// drops the Authorization header on a redirect to another host.
+		if !changed || (r.Body != nil && r.Body != http.NoBody && r.GetBody == nil) {
+			return res, nil
+		}
+		io.Copy(io.Discard, res.Body)
+		res.Body.Close()
+
+		retry := r.Clone(r.Context())
+		if r.GetBody != nil {
+			if retry.Body, err = r.GetBody(); err != nil {
+				return nil, fmt.Errorf("rebase rewind body: %w", err)
+			}
+		}
+		return next.RoundTrip(rb.rewrite(retry, rb.root()))
+	})
+}
+
+// root returns the current root URL.
+func (rb *rebaser) root() *url.URL {
+	if current := rb.current.Load(); current != nil {
+		return current
+	}
+	return environmentRoot(rb.client.baseURL)
+}
+
+// rewrite returns the request with the configured root replaced by the current one.
+func (rb *rebaser) rewrite(r *http.Request, current *url.URL) *http.Request {
+	configured := environmentRoot(rb.client.baseURL)
+	if current.String() == configured.String() || r.URL.Host != configured.Host {
+		return r
+	}
+	rest, ok := strings.CutPrefix(r.URL.Path, configured.Path)
+	if !ok {
+		return r
+	}
+	rewritten := r.Clone(r.Context())
+	u := *r.URL
+	u.Scheme, u.Host, u.Path, u.RawPath = current.Scheme, current.Host, current.Path+rest, ""
+	rewritten.URL = &u
+	rewritten.Host = ""
+	return rewritten
+}
+
+// rebaseAllowed returns true if the URL has the configured scheme and the configured
+// host or one of the AllowedHosts of [WithRebase].
+func (c *Client) rebaseAllowed(u *url.URL) bool {
+	configured := c.baseURL
+	if u.Scheme != configured.Scheme || u.User != nil {
+		return false
+	}
+	host := strings.ToLower(u.Host)
+	if host == strings.ToLower(configured.Host) {
+		return true
+	}
+	hosts := DefaultRebaseHosts
+	if c.rebaser != nil && c.rebaser.opts.AllowedHosts != nil {
+		hosts = c.rebaser.opts.AllowedHosts
+	}
+	for _, pattern := range hosts {
+		pattern = strings.ToLower(pattern)
+		if suffix, ok := strings.CutPrefix(pattern, "*."); ok {
+			if strings.HasSuffix(host, "."+suffix) {
+				return true
+			}
+		} else if host == pattern {
+			return true
+		}
+	}
+	return false
+}
+
+func isRedirect(statusCode int) bool {
+	switch statusCode {
+	case http.StatusMovedPermanently, http.StatusFound, http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+		return true
+	}
+	return false
+}
+
+// followRedirect moves to the root of the Location if it keeps the path after the root,
+// so later requests go there directly, and returns true if the root changed.
+func (rb *rebaser) followRedirect(r *http.Request, current *url.URL, res *http.Response) bool {
+	loc, err := res.Location()
+	if err != nil || loc.Host == "" {
+		return false
+	}
+	configured := environmentRoot(rb.client.baseURL)
+	rest, ok := strings.CutPrefix(r.URL.Path, configured.Path)
+	if !ok {
+		return false
+	}
+	path, ok := strings.CutSuffix(loc.Path, rest)
+	if !ok {
+		return false
+	}
+	return rb.set(current, &url.URL{Scheme: loc.Scheme, Host: loc.Host, Path: path})
+}
+
+// moved returns true for the responses of an environment that is no longer at the URL.
+func (rb *rebaser) moved(res *http.Response) bool {
+	switch res.StatusCode {
+	case http.StatusNotFound:
+		code, _ := peekErrorResponse(res)
+		return code == ErrorCodeTenantNotFound || code == ErrorCodeEnvironmentNotFound
+	case http.StatusServiceUnavailable, http.StatusBadGateway:
+		return FingerprintGateway(res.StatusCode, peekBody(res)) == GatewayTenantMoving
+	}
+	return false
+}
+
+// resolve calls the Resolver, at most once per MinInterval unless forced, and
+// returns true if the root URL changed.
+func (rb *rebaser) resolve(ctx context.Context, force bool) (bool, error) {
+	rb.mu.Lock()
+	defer rb.mu.Unlock()
+	minInterval := rb.opts.MinInterval
+	if minInterval <= 0 {
+		minInterval = time.Minute
+	}
+	now := clockOrSystem(rb.client.clock).Now()
+	if !force && !rb.resolved.IsZero() && now.Sub(rb.resolved) < minInterval {
+		return false, nil
+	}
+	rb.resolved = now
+
+	resolver := rb.opts.Resolver
+	if resolver == nil {
+		resolver = ProbeEnvironment
+	}
+	root, err := resolver(context.WithValue(ctx, rebaseSkipKey{}, true), rb.client)
+	if err != nil {
+		return false, fmt.Errorf("rebase: %w", err)
+	}
+	return rb.set(rb.root(), root), nil
+}
+
+// set moves from the current root URL to another and returns true if it differs.
+// A root on a host that is not allowed is refused.
+func (rb *rebaser) set(from, to *url.URL) bool {
+	to.Path = strings.TrimSuffix(to.Path, "/")
+	if to.String() == from.String() {
+		return false
+	}
+	if !rb.client.rebaseAllowed(to) {
+		rb.client.logger.Warn("Refused to move the environment to a host that is not allowed.", "from", from.String(), "to", to.String())
+		return false
+	}
+	rb.current.Store(to)
+	rb.client.logger.Debug("Environment moved, rebasing the client.", "from", from.String(), "to", to.String())
+	if rb.opts.OnRebase != nil {
+		rb.opts.OnRebase(from, to)
+	}
+	return true
+}
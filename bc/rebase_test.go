@@ -0,0 +1,191 @@
+package bc_test
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/erlorenz/bc-go/internal/bctest"
+	"github.com/google/uuid"
+)
+
+var movedRoot = "https://moved.api.businesscentral.dynamics.com/v2.0/" + validGUID + "/Sandbox"
+
+// rebaseTransport serves the moved environment and answers requests to the old one
+// with the response of moved.
+type rebaseTransport struct {
+	moved func(r *http.Request) *http.Response
+
+	mu       sync.Mutex
+	requests []*http.Request
+}
+
+func (rt *rebaseTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	rt.mu.Lock()
+	rt.requests = append(rt.requests, r)
+	rt.mu.Unlock()
+	if r.URL.Host == "moved.api.businesscentral.dynamics.com" {
+		if r.Header.Get("Authorization") == "" {
+			return errorResponse(http.StatusUnauthorized, "Unauthorized"), nil
+		}
+		if strings.HasSuffix(r.URL.Path, "/companies") {
+			return bctest.NewResponse(http.StatusOK, map[string]any{"value": []any{}}), nil
+		}
+		return bctest.NewResponse(http.StatusOK, map[string]any{"ID": validGUID}), nil
+	}
+	return rt.moved(r), nil
+}
+
+func TestRebaseRedirect(t *testing.T) {
+	rt := &rebaseTransport{moved: func(r *http.Request) *http.Response {
+		res := bctest.NewResponse(http.StatusTemporaryRedirect, map[string]any{})
+		res.Header.Set("Location", "https://moved.api.businesscentral.dynamics.com"+r.URL.Path)
+		return res
+	}}
+	var rebased []string
+	client, err := bc.NewClient(fakeConfig, bc.WithAuthClient(fakeTokenGetter{}), bc.WithHTTPClient(&http.Client{Transport: rt}),
+		bc.WithRebase(bc.RebaseOptions{OnRebase: func(from, to *url.URL) { rebased = append(rebased, to.String()) }}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	page := bc.NewAPIPage[fakeEntity](client, "fakeEntities")
+
+	for range 2 {
+		if _, err := page.Get(context.Background(), uuid.New(), bc.GetOptions{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(rt.requests) != 3 || rt.requests[2].URL.Host != "moved.api.businesscentral.dynamics.com" {
+		t.Errorf("wanted the second request sent to the moved environment, got %d requests", len(rt.requests))
+	}
+	if len(rebased) != 1 || rebased[0] != movedRoot || client.EnvironmentURL().String() != movedRoot {
+		t.Errorf("wanted a rebase to %s, got %v and %s", movedRoot, rebased, client.EnvironmentURL())
+	}
+}
+
+func TestRebaseTenantNotFound(t *testing.T) {
+	rt := &rebaseTransport{moved: func(r *http.Request) *http.Response {
+		return errorResponse(http.StatusNotFound, string(bc.ErrorCodeTenantNotFound))
+	}}
+	var resolves int
+	resolver := func(ctx context.Context, client *bc.Client) (*url.URL, error) {
+		resolves++
+		return url.Parse(movedRoot)
+	}
+	client, err := bc.NewClient(fakeConfig, bc.WithAuthClient(fakeTokenGetter{}), bc.WithHTTPClient(&http.Client{Transport: rt}),
+		bc.WithRebase(bc.RebaseOptions{Resolver: resolver}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	created, err := bc.NewAPIPage[fakeEntity](client, "fakeEntities").Create(context.Background(), map[string]any{"name": "x"}, bc.GetOptions{})
+	if err != nil {
+		t.Fatalf("wanted the create to be sent again to the moved environment, got %v", err)
+	}
+	if string(created.ID) != validGUID || resolves != 1 || len(rt.requests) != 2 {
+		t.Errorf("unexpected %+v after %d resolves and %d requests", created, resolves, len(rt.requests))
+	}
+	if path := rt.requests[1].URL.Path; !strings.HasSuffix(path, "/Sandbox/api/publisher/group/1.0/companies("+validGUID+")/fakeEntities") {
+		t.Errorf("unexpected path %s", path)
+	}
+}
+
+func TestRebaseProbe(t *testing.T) {
+	rt := &rebaseTransport{moved: func(r *http.Request) *http.Response {
+		if strings.HasSuffix(r.URL.Path, "/companies") {
+			res := bctest.NewResponse(http.StatusMovedPermanently, map[string]any{})
+			res.Header.Set("Location", movedRoot+"/api/v2.0/companies?$select=id&$top=1")
+			return res
+		}
+		return errorResponse(http.StatusNotFound, string(bc.ErrorCodeEnvironmentNotFound))
+	}}
+	client, err := bc.NewClient(fakeConfig, bc.WithAuthClient(fakeTokenGetter{}), bc.WithHTTPClient(&http.Client{Transport: rt}),
+		bc.WithRebase(bc.RebaseOptions{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	root, err := bc.ProbeEnvironment(context.Background(), client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if root.String() != movedRoot {
+		t.Errorf("wanted %s, got %s", movedRoot, root)
+	}
+	if changed, err := client.Rebase(context.Background()); err != nil || !changed {
+		t.Fatalf("wanted a rebase, got %t %v", changed, err)
+	}
+	if changed, _ := client.Rebase(context.Background()); changed {
+		t.Error("wanted no change on the second rebase")
+	}
+}
+
+func TestRebaseRedirectForeignHost(t *testing.T) {
+	rt := &rebaseTransport{moved: func(r *http.Request) *http.Response {
+		res := bctest.NewResponse(http.StatusTemporaryRedirect, map[string]any{})
+		res.Header.Set("Location", "https://evil.example.com"+r.URL.Path)
+		return res
+	}}
+	client, err := bc.NewClient(fakeConfig, bc.WithAuthClient(fakeTokenGetter{}), bc.WithHTTPClient(&http.Client{Transport: rt}),
+		bc.WithRebase(bc.RebaseOptions{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := bc.NewAPIPage[fakeEntity](client, "fakeEntities").Get(context.Background(), uuid.New(), bc.GetOptions{}); err == nil {
+		t.Fatal("wanted an error for the refused redirect")
+	}
+	for _, r := range rt.requests {
+		if r.URL.Host == "evil.example.com" && r.Header.Get("Authorization") != "" {
+			t.Fatal("wanted no Authorization header sent to the foreign host")
+		}
+	}
+	if client.EnvironmentURL().Host != "api.businesscentral.dynamics.com" {
+		t.Errorf("wanted no rebase, got %s", client.EnvironmentURL())
+	}
+	if _, err := bc.ProbeEnvironment(context.Background(), client); err == nil {
+		t.Error("wanted the probe to refuse the foreign host")
+	}
+}
+
+func TestRebaseListAfterMove(t *testing.T) {
+	var moved bool
+	rt := bc.RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		if r.URL.Host != "moved.api.businesscentral.dynamics.com" {
+			moved = true
+			res := bctest.NewResponse(http.StatusTemporaryRedirect, map[string]any{})
+			res.Header.Set("Location", "https://moved.api.businesscentral.dynamics.com"+r.URL.Path)
+			return res, nil
+		}
+		if r.URL.Query().Get("$skiptoken") == "" {
+			return bctest.NewResponse(http.StatusOK, map[string]any{
+				"value":           []any{map[string]any{"ID": validGUID}},
+				"@odata.nextLink": "https://" + r.URL.Host + r.URL.Path + "?$skiptoken=2",
+			}), nil
+		}
+		return bctest.NewResponse(http.StatusOK, map[string]any{"value": []any{map[string]any{"ID": validGUID}}}), nil
+	})
+	client, err := bc.NewClient(fakeConfig, bc.WithAuthClient(fakeTokenGetter{}), bc.WithHTTPClient(&http.Client{Transport: rt}),
+		bc.WithRebase(bc.RebaseOptions{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	page := bc.NewAPIPage[fakeEntity](client, "fakeEntities")
+	var records int
+	for link, first := "", true; first || link != ""; first = false {
+		list, err := page.ListPage(context.Background(), link, bc.ListOptions{})
+		if err != nil {
+			t.Fatalf("wanted the nextLink of the moved environment to be followed, got %v", err)
+		}
+		records += len(list.Value)
+		link = list.NextLink
+	}
+	if !moved || records != 2 {
+		t.Errorf("wanted 2 records after the move, got %d", records)
+	}
+}
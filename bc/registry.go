@@ -0,0 +1,113 @@
+package bc
+
+import (
+	"cmp"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// EntitySetInfo is the entity set that a model type is read from.
+type EntitySetInfo struct {
+	// Name is the entity set name, e.g. "customers".
+	Name string
+	// Key is the JSON name of the key field, "id" for the standard APIs.
+	Key string
+}
+
+// entitySets maps the reflect.Type of a model to its EntitySetInfo.
+var entitySets sync.Map
+
+// RegisterEntitySet registers the entity set of the model type T, so [EntitySetOf] and
+// [NewAPIPageOf] can infer it. An empty Key defaults to "id". Registering T again
+// replaces the entity set. It panics if the Name is empty. The models package registers
+// its types, register your own in an init function:
+//
+//	func init() {
+//		bc.RegisterEntitySet[Shipment](bc.EntitySetInfo{Name: "shipments"})
+//	}
+//
+// A model can instead declare its entity set with a "bc" struct tag, see [EntitySetOf].
+func RegisterEntitySet[T Validator](info EntitySetInfo) {
+	if info.Name == "" {
+		panic("register entity set: name is empty")
+	}
+	info.Key = cmp.Or(info.Key, "id")
+	entitySets.Store(reflect.TypeFor[T](), info)
+}
+
+// EntitySetOf returns the entity set of the model type T, the registered one or else
+// the one of its "bc" struct tag. The tag is on any field, usually the key or a blank
+// field, with an entitySet and an optional key that defaults to "id":
+//
+//	type Shipment struct {
+//		_  struct{}  `bc:"entitySet=shipments,key=id"`
+//		ID uuid.UUID `json:"id"`
+//	}
+func EntitySetOf[T Validator]() (EntitySetInfo, bool) {
+	info, err := entitySetOf(reflect.TypeFor[T]())
+	return info, err == nil
+}
+
+// NewAPIPageOf creates an [APIPage] for the entity set of T, see [EntitySetOf], e.g.
+// bc.NewAPIPageOf[models.Customer](client). It panics if T has no entity set.
+func NewAPIPageOf[T Validator](client *Client) *APIPage[T] {
+	info, err := entitySetOf(reflect.TypeFor[T]())
+	if err != nil {
+		panic("create API page: " + err.Error())
+	}
+	return NewAPIPage[T](client, info.Name)
+}
+
+// entitySetOf returns the registered entity set of the type or parses its struct tags.
+// Parsed tags are cached like registered types.
+func entitySetOf(t reflect.Type) (EntitySetInfo, error) {
+	if info, ok := entitySets.Load(t); ok {
+		return info.(EntitySetInfo), nil
+	}
+	st := t
+	for st.Kind() == reflect.Pointer {
+		st = st.Elem()
+	}
+	if st.Kind() == reflect.Struct {
+		for i := range st.NumField() {
+			f := st.Field(i)
+			tag, ok := f.Tag.Lookup("bc")
+			if !ok {
+				continue
+			}
+			info, err := parseEntitySetTag(tag)
+			if err != nil {
+				return EntitySetInfo{}, fmt.Errorf("invalid bc tag of %s.%s: %w", t, f.Name, err)
+			}
+			stored, _ := entitySets.LoadOrStore(t, info)
+			return stored.(EntitySetInfo), nil
+		}
+	}
+	return EntitySetInfo{}, fmt.Errorf("no entity set registered or tagged for %s", t)
+}
+
+// parseEntitySetTag parses a tag like "entitySet=customers,key=id".
+func parseEntitySetTag(tag string) (EntitySetInfo, error) {
+	var info EntitySetInfo
+	for _, opt := range strings.Split(tag, ",") {
+		k, v, ok := strings.Cut(strings.TrimSpace(opt), "=")
+		if !ok || v == "" {
+			return info, fmt.Errorf("option %q is not key=value", opt)
+		}
+		switch k {
+		case "entitySet":
+			info.Name = v
+		case "key":
+			info.Key = v
+		default:
+			return info, fmt.Errorf("unknown option %q", k)
+		}
+	}
+	if info.Name == "" {
+		return info, fmt.Errorf("no entitySet in %q", tag)
+	}
+	info.Key = cmp.Or(info.Key, "id")
+	return info, nil
+}
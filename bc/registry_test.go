@@ -0,0 +1,92 @@
+package bc_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/erlorenz/bc-go/internal/bctest"
+	"github.com/google/uuid"
+)
+
+type shipment struct {
+	ID string `json:"id"`
+}
+
+func (shipment) Validate() error { return nil }
+
+type unregistered struct{}
+
+func (unregistered) Validate() error { return nil }
+
+func TestRegisterEntitySet(t *testing.T) {
+	bc.RegisterEntitySet[shipment](bc.EntitySetInfo{Name: "shipments"})
+
+	info, ok := bc.EntitySetOf[shipment]()
+	if !ok || info.Name != "shipments" || info.Key != "id" {
+		t.Errorf("unexpected entity set %+v %v", info, ok)
+	}
+	if _, ok := bc.EntitySetOf[unregistered](); ok {
+		t.Error("wanted no entity set for an unregistered type")
+	}
+
+	client := newSequenceClient(t, &bctest.SequenceTransport{})
+	if page := bc.NewAPIPageOf[shipment](client); page.EntitySetName() != "shipments" {
+		t.Errorf("wanted the page of shipments, got %s", page.EntitySetName())
+	}
+
+	defer func() {
+		if r := recover(); r == nil || !strings.Contains(r.(string), "unregistered") {
+			t.Errorf("wanted a panic naming the type, got %v", r)
+		}
+	}()
+	bc.NewAPIPageOf[unregistered](client)
+}
+
+type taggedShipment struct {
+	_      struct{} `bc:"entitySet=warehouseShipments,key=systemId"`
+	Number string   `json:"number"`
+}
+
+func (taggedShipment) Validate() error { return nil }
+
+type badTag struct {
+	Number string `json:"number" bc:"entitySet"`
+}
+
+func (badTag) Validate() error { return nil }
+
+func TestEntitySetTag(t *testing.T) {
+	info, ok := bc.EntitySetOf[taggedShipment]()
+	if !ok || info.Name != "warehouseShipments" || info.Key != "systemId" {
+		t.Errorf("unexpected entity set %+v %v", info, ok)
+	}
+
+	st := &bctest.SequenceTransport{Responses: []*http.Response{
+		bctest.NewResponse(200, map[string]any{"value": []map[string]any{{"number": "WS-1001"}}}),
+	}}
+	client := newSequenceClient(t, st)
+
+	shipments, err := bc.List[taggedShipment](context.Background(), client, bc.ListOptions{Top: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(shipments) != 1 || shipments[0].Number != "WS-1001" {
+		t.Errorf("unexpected shipments %+v", shipments)
+	}
+	if !strings.HasSuffix(st.Requests[0].URL.Path, "/warehouseShipments") {
+		t.Errorf("unexpected path %s", st.Requests[0].URL.Path)
+	}
+
+	if _, err := bc.List[badTag](context.Background(), client, bc.ListOptions{}); err == nil || !strings.Contains(err.Error(), "badTag.Number") {
+		t.Errorf("wanted an error naming the field, got %v", err)
+	}
+	if _, err := bc.GetRecord[unregistered](context.Background(), client, uuid.New(), bc.GetOptions{}); err == nil {
+		t.Error("wanted an error for an unregistered type")
+	}
+	if st.Count() != 1 {
+		t.Errorf("wanted no request without an entity set, got %d", st.Count())
+	}
+}
@@ -0,0 +1,77 @@
+package bc
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Repository is a storage-agnostic view of an entity set, so data-sync
+// frameworks can use BC as a source or sink without HTTP-level details.
+// Create one with [NewRepository].
+type Repository[T any] interface {
+	List(ctx context.Context, opts ListOptions) ([]T, error)
+	Get(ctx context.Context, id uuid.UUID) (T, error)
+	Create(ctx context.Context, record T) (T, error)
+	Update(ctx context.Context, id uuid.UUID, record T) (T, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+	// Changes returns the records modified after since, oldest first.
+	Changes(ctx context.Context, since time.Time) ([]T, error)
+}
+
+// RepositoryOptions configure the [Repository] returned by [NewRepository].
+type RepositoryOptions[T any] struct {
+	// Payload converts the record to the body for Create and Update, e.g. to leave
+	// out read-only fields. Defaults to the record itself.
+	Payload func(T) any
+	// LastModifiedField is the field used by Changes. Defaults to "lastModifiedDateTime".
+	LastModifiedField string
+}
+
+type apiPageRepository[T Validator] struct {
+	page *APIPage[T]
+	opts RepositoryOptions[T]
+}
+
+// NewRepository returns a [Repository] that uses the [APIPage].
+func NewRepository[T Validator](page *APIPage[T], opts RepositoryOptions[T]) Repository[T] {
+	if page == nil {
+		panic("create repository: page is nil")
+	}
+	if opts.Payload == nil {
+		opts.Payload = func(record T) any { return record }
+	}
+	if opts.LastModifiedField == "" {
+		opts.LastModifiedField = "lastModifiedDateTime"
+	}
+	return &apiPageRepository[T]{page: page, opts: opts}
+}
+
+func (r *apiPageRepository[T]) List(ctx context.Context, opts ListOptions) ([]T, error) {
+	return r.page.List(ctx, opts)
+}
+
+func (r *apiPageRepository[T]) Get(ctx context.Context, id uuid.UUID) (T, error) {
+	return r.page.Get(ctx, id, GetOptions{})
+}
+
+func (r *apiPageRepository[T]) Create(ctx context.Context, record T) (T, error) {
+	return r.page.Create(ctx, r.opts.Payload(record), GetOptions{})
+}
+
+func (r *apiPageRepository[T]) Update(ctx context.Context, id uuid.UUID, record T) (T, error) {
+	return r.page.Update(ctx, id, nil, r.opts.Payload(record))
+}
+
+func (r *apiPageRepository[T]) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.page.Delete(ctx, id)
+}
+
+func (r *apiPageRepository[T]) Changes(ctx context.Context, since time.Time) ([]T, error) {
+	return r.page.List(ctx, ListOptions{
+		Filter:  fmt.Sprintf("%s gt %s", r.opts.LastModifiedField, since.UTC().Format(time.RFC3339Nano)),
+		OrderBy: []string{r.opts.LastModifiedField},
+	})
+}
@@ -0,0 +1,55 @@
+package bc_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/erlorenz/bc-go/internal/bctest"
+)
+
+func TestRepositoryChanges(t *testing.T) {
+	st := &bctest.SequenceTransport{Responses: []*http.Response{
+		bctest.NewResponse(200, map[string]any{"value": []map[string]any{{"ID": validGUID}}}),
+	}}
+	client := newSequenceClient(t, st)
+
+	var repo bc.Repository[fakeEntity] = bc.NewRepository(bc.NewAPIPage[fakeEntity](client, "fakeEntities"), bc.RepositoryOptions[fakeEntity]{})
+
+	since := time.Date(2024, 2, 20, 10, 0, 0, 0, time.UTC)
+	records, err := repo.Changes(context.Background(), since)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("wanted 1 record, got %d", len(records))
+	}
+
+	q := st.Requests[0].URL.Query()
+	if got := q.Get("$filter"); got != "lastModifiedDateTime gt 2024-02-20T10:00:00Z" {
+		t.Errorf("unexpected filter %s", got)
+	}
+	if got := q.Get("$orderby"); got != "lastModifiedDateTime" {
+		t.Errorf("unexpected orderby %s", got)
+	}
+}
+
+func TestRepositoryCreatePayload(t *testing.T) {
+	st := &bctest.SequenceTransport{Responses: []*http.Response{bctest.NewResponse(201, map[string]any{"ID": validGUID})}}
+	client := newSequenceClient(t, st)
+
+	repo := bc.NewRepository(bc.NewAPIPage[fakeEntity](client, "fakeEntities"), bc.RepositoryOptions[fakeEntity]{
+		Payload: func(f fakeEntity) any { return map[string]any{"Number": f.Number} },
+	})
+
+	if _, err := repo.Create(context.Background(), fakeEntity{Number: "1000", Quantity: 5}); err != nil {
+		t.Fatal(err)
+	}
+
+	body := readBody(t, st.Requests[0])
+	if body != `{"Number":"1000"}` {
+		t.Errorf("unexpected body %s", body)
+	}
+}
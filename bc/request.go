@@ -2,11 +2,12 @@ package bc
 
 import (
 	"bytes"
+	"cmp"
 	"context"
-	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"strings"
 
 	"github.com/google/uuid"
@@ -21,12 +22,21 @@ const DataAccessReadOnly = "ReadOnly"
 var AcceptJSONNoMetadata = strings.Join([]string{ContentTypeJSON, NoODATAMetadata}, ";")
 
 // MakeRequestOptions are the unique options for the http.Request.
+// The builders [Get], [Post], [Patch], [Put] and [Delete] create valid options.
 type RequestOptions struct {
 	Method        string
 	EntitySetName string
 	RecordID      uuid.UUID
 	QueryParams   QueryParams
 	Body          any
+	// ETag is sent as the If-Match header for PATCH, PUT and DELETE.
+	// Defaults to "*" which matches any version of the record.
+	ETag string
+	// Header has additional headers set after the defaults, e.g. "Prefer".
+	Header http.Header
+	// TokenGetter overrides the client's auth for this request, e.g. to call BC as a different user.
+	// Takes precedence over [WithTokenGetter].
+	TokenGetter TokenGetter
 }
 
 // Validate checks all the fields for invalid combinations or values.
@@ -49,7 +59,7 @@ func (r RequestOptions) Validate() error {
 		}
 	}
 	// Cannot have filter query params with anything but GET
-	if r.QueryParams != nil && r.QueryParams["$filter"] != "" {
+	if r.QueryParams.Get("$filter") != "" {
 		if r.Method != http.MethodGet {
 			errs = append(errs, fmt.Sprintf("invalid combination: cannot have $filter query param with method %s", r.Method))
 		}
@@ -57,6 +67,9 @@ func (r RequestOptions) Validate() error {
 	if r.Method == http.MethodPatch && r.RecordID == uuid.Nil {
 		errs = append(errs, "invalid combination: cannot have method PATCH with no RecordID")
 	}
+	if r.ETag != "" && r.Method != http.MethodPatch && r.Method != http.MethodPut && r.Method != http.MethodDelete {
+		errs = append(errs, fmt.Sprintf("invalid combination: cannot have ETag with method %s", r.Method))
+	}
 
 	if len(errs) > 0 {
 		return fmt.Errorf("invalid requestoptions: [ %s ]", strings.Join(errs, ", "))
@@ -64,44 +77,97 @@ func (r RequestOptions) Validate() error {
 	return nil
 }
 
-// QueryParams are used to build the http.Request url.
-type QueryParams map[string]string
-
 // NewRequest is the base method that creates the http.Request.
-// It has the same return as http.RequestWithContext.
+// It has the same return as http.RequestWithContext. If the context is done it
+// returns its error without acquiring a token, so loops over pages stop at the next page.
 func (c *Client) NewRequest(ctx context.Context, opts RequestOptions) (*http.Request, error) {
 
 	// Validate options
-	if err := opts.Validate(); err != nil {
+	if err := c.validateRequest(opts); err != nil {
 		return nil, err
 	}
 
+	if c.tiebreaker != "" && opts.Method == http.MethodGet && opts.EntitySetName != "" && opts.RecordID == uuid.Nil {
+		opts.QueryParams = withTiebreaker(opts.QueryParams, c.tiebreaker)
+	}
+
 	// Build the full URL string
-	newURL := BuildRequestURL(*c.baseURL, opts.EntitySetName, opts.RecordID, opts.QueryParams)
+	rawURL := c.requestURL(opts.EntitySetName, opts.RecordID, opts.QueryParams)
+	if err := c.checkURLLength(rawURL); err != nil {
+		return nil, err
+	}
+
+	return c.newRequest(ctx, rawURL, opts)
+}
+
+// NewRequestURL creates an http.Request for an absolute URL returned by BC,
+// like an "@odata.nextLink" or "@odata.deltaLink", with the same headers as [Client.NewRequest].
+// The URL must have the same scheme and host as the client's base URL, or as its
+// [Client.EnvironmentURL] after a rebase, so the Authorization header is never sent elsewhere.
+func (c *Client) NewRequestURL(ctx context.Context, method string, rawURL string, body any) (*http.Request, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid url: %w", err)
+	}
+	if !c.sameOrigin(u) {
+		return nil, fmt.Errorf("invalid url: host %s does not match %s", u.Host, c.EnvironmentURL().Host)
+	}
+	if err := c.checkReadOnly(method, u.Path); err != nil {
+		return nil, err
+	}
+
+	return c.newRequest(ctx, u.String(), RequestOptions{Method: method, Body: body})
+}
 
-	// Marshall JSON
+// newRequest marshals the body and creates the http.Request with the auth and OData headers.
+func (c *Client) newRequest(ctx context.Context, rawURL string, opts RequestOptions) (*http.Request, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("creating new request: %w", err)
+	}
+
+	// Marshall JSON, media is sent as is
 	var body io.Reader
-	if opts.Body != nil {
-		b, err := json.Marshal(opts.Body)
+	var rawBody []byte
+	if b, ok := opts.Body.(mediaBody); ok {
+		body = bytes.NewReader(b)
+		rawBody = b
+	} else if opts.Body != nil {
+		b, err := c.codec.Marshal(opts.Body)
 		if err != nil {
 			return nil, fmt.Errorf("cannot marshal body %s: %w", opts.Body, err)
 		}
 		body = bytes.NewReader(b)
+		rawBody = b
 	}
 
 	// Create Request
-	req, err := http.NewRequestWithContext(ctx, opts.Method, newURL.String(), body)
+	req, err := http.NewRequestWithContext(ctx, opts.Method, rawURL, body)
 	if err != nil {
 		return nil, fmt.Errorf("creating new request: %w", err)
 	}
 
 	// Add the Authorization header for each request
-	bearerToken, err := getBearerToken(ctx, c.authClient)
+	tg := opts.TokenGetter
+	if tg == nil {
+		tg = TokenGetterFrom(ctx)
+	}
+	if tg == nil {
+		tg = c.authClient
+	}
+	bearerToken, err := getBearerToken(ctx, tg)
 	if err != nil {
 		return nil, fmt.Errorf("create auth header: %w", err)
 	}
 	req.Header.Set("Authorization", bearerToken)
 
+	req.Header.Set("User-Agent", c.userAgent)
+	for k, v := range c.telemetryHeader {
+		req.Header[k] = v
+	}
+	if id := c.requestID(ctx); id != "" {
+		req.Header.Set(HeaderClientRequestID, id)
+	}
+
 	// Add this header so it doesn't return the extra OData fields
 	req.Header.Set("Accept", AcceptJSONNoMetadata)
 
@@ -115,9 +181,21 @@ func (c *Client) NewRequest(ctx context.Context, opts RequestOptions) (*http.Req
 		req.Header.Set("Content-Type", ContentTypeJSON)
 	}
 
-	// Use If-Match for POST, PUT, PATCH, DELETE
+	// Use If-Match for PUT, PATCH, DELETE
 	if opts.Method == http.MethodDelete || opts.Method == http.MethodPut || opts.Method == http.MethodPatch {
-		req.Header.Set("If-Match", "*")
+		req.Header.Set("If-Match", cmp.Or(opts.ETag, "*"))
+	}
+
+	// Additional headers last so they can override the defaults
+	for k, v := range opts.Header {
+		req.Header[http.CanonicalHeaderKey(k)] = v
+	}
+
+	// Sign last so the signature covers the final request
+	if c.signer != nil {
+		if err := c.signer.Sign(req, rawBody); err != nil {
+			return nil, fmt.Errorf("signing request: %w", err)
+		}
 	}
 
 	return req, nil
@@ -128,6 +206,10 @@ func (c *Client) NewRequest(ctx context.Context, opts RequestOptions) (*http.Req
 func getBearerToken(ctx context.Context, tg TokenGetter) (string, error) {
 	accessToken, err := tg.GetToken(ctx)
 	if err != nil {
+		return "", fmt.Errorf("error adding auth header: %w", tokenError{err})
+	}
+	// A TokenGetter may ignore the context, so don't send a request that was canceled meanwhile.
+	if err := ctx.Err(); err != nil {
 		return "", fmt.Errorf("error adding auth header: %w", err)
 	}
 
@@ -135,7 +217,39 @@ func getBearerToken(ctx context.Context, tg TokenGetter) (string, error) {
 
 }
 
-// Do calls Do on the baseClient.
+// tokenError marks an error from the TokenGetter so it can be told apart from other request errors.
+type tokenError struct {
+	err error
+}
+
+func (te tokenError) Error() string { return te.err.Error() }
+
+func (te tokenError) Unwrap() error { return te.err }
+
+// Do calls Do on the baseClient. If a [RetryClassifier] is set with
+// [WithRetryClassifier] failed attempts are retried as it decides.
+// A client created with [Client.DryRun] returns a [DryRunError] for mutating requests.
+// Deprecation headers of the response are reported as a [Warning], and failures to
+// the [ErrorReporter] of [WithErrorReporter]. During a window of [WithMaintenanceSchedule]
+// it returns a [MaintenanceError] without sending the request.
 func (c *Client) Do(r *http.Request) (*http.Response, error) {
-	return c.baseClient.Do(r)
+	if c.dryRun && r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return nil, newDryRunError(r)
+	}
+	if err := c.checkMaintenance(r, clockOrSystem(c.clock).Now()); err != nil {
+		c.logger.Debug("Request not sent during maintenance window.", "method", r.Method, "url", r.URL.String(), "error", err)
+		return nil, err
+	}
+	var res *http.Response
+	var err error
+	if c.retryClassifier != nil {
+		res, err = c.doWithRetry(r)
+	} else {
+		res, err = c.baseClient.Do(r)
+	}
+	if err == nil && c.warnings != nil {
+		c.warnings.checkResponse(r, res)
+	}
+	c.reportError(r, res, err)
+	return res, err
 }
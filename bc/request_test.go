@@ -152,8 +152,8 @@ func TestMakeRequestGetParams(t *testing.T) {
 	}
 
 	qp := bc.QueryParams{
-		"param1": "value1",
-		"param2": "value2",
+		{Key: "param1", Value: "value1"},
+		{Key: "param2", Value: "value2"},
 	}
 
 	want := "param1=value1&param2=value2"
@@ -176,3 +176,86 @@ func TestMakeRequestGetParams(t *testing.T) {
 		t.Errorf("wanted %s, got %s", want, got)
 	}
 }
+
+func TestMakeRequestTokenGetterOverride(t *testing.T) {
+	client, err := bc.NewClient(fakeConfig, bc.WithAuthClient(fakeTokenGetter{}))
+	if err != nil {
+		t.Fatalf("failed to create new client: %s", err)
+	}
+
+	opts := bc.RequestOptions{Method: http.MethodGet, EntitySetName: "fakeEntities"}
+	userA := &countingTokenGetter{token: "USERA"}
+	userB := &countingTokenGetter{token: "USERB"}
+	ctxB := bc.WithTokenGetter(context.Background(), userB)
+
+	table := []struct {
+		name string
+		ctx  context.Context
+		tg   bc.TokenGetter
+		want string
+	}{
+		{"Client", context.Background(), nil, "Bearer FAKEACCESSTOKEN"},
+		{"Context", ctxB, nil, "Bearer USERB"},
+		{"RequestOptions", context.Background(), userA, "Bearer USERA"},
+		{"RequestOptionsOverContext", ctxB, userA, "Bearer USERA"},
+	}
+
+	for _, v := range table {
+		t.Run(v.name, func(t *testing.T) {
+			opts.TokenGetter = v.tg
+			req, err := client.NewRequest(v.ctx, opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := req.Header.Get("Authorization"); got != v.want {
+				t.Errorf("wanted %s, got %s", v.want, got)
+			}
+		})
+	}
+}
+
+func TestMakeRequestUserAgent(t *testing.T) {
+	opts := bc.RequestOptions{Method: http.MethodGet, EntitySetName: "fakeEntities"}
+
+	client, err := bc.NewClient(fakeConfig, bc.WithAuthClient(fakeTokenGetter{}))
+	if err != nil {
+		t.Fatalf("failed to create new client: %s", err)
+	}
+	req, err := client.NewRequest(context.Background(), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := req.Header.Get("User-Agent"), "bcgo/"+bc.Version(); got != want {
+		t.Errorf("wanted %s, got %s", want, got)
+	}
+
+	client, err = bc.NewClient(fakeConfig, bc.WithAuthClient(fakeTokenGetter{}),
+		bc.WithUserAgent("invoice-sync/1.2.0"),
+		bc.WithTelemetryHeader("X-Partner-ID", "PARTNER"),
+		bc.WithTelemetryHeader("X-Application-ID", "APP"),
+	)
+	if err != nil {
+		t.Fatalf("failed to create new client: %s", err)
+	}
+	opts.Header = http.Header{"X-Application-Id": {"OVERRIDE"}}
+	req, err = client.NewRequest(context.Background(), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	table := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"UserAgent", req.Header.Get("User-Agent"), "bcgo/" + bc.Version() + " invoice-sync/1.2.0"},
+		{"ClientUserAgent", client.UserAgent(), "bcgo/" + bc.Version() + " invoice-sync/1.2.0"},
+		{"Telemetry", req.Header.Get("X-Partner-ID"), "PARTNER"},
+		{"TelemetryOverride", strings.Join(req.Header.Values("X-Application-ID"), "--"), "OVERRIDE"},
+	}
+	for _, test := range table {
+		if test.got != test.want {
+			t.Errorf("%s: wanted %v, got %v", test.name, test.want, test.got)
+		}
+	}
+}
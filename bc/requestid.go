@@ -0,0 +1,54 @@
+package bc
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// HeaderClientRequestID identifies a request in the telemetry of the environment.
+// BC sets one if the request has none and returns it in the response.
+const HeaderClientRequestID = "client-request-id"
+
+// RequestIDFunc returns the [HeaderClientRequestID] of a new request. An empty
+// string sends none. Set it with [WithRequestIDs].
+type RequestIDFunc func(ctx context.Context) string
+
+// RandomRequestIDs is a [RequestIDFunc] that sends a random UUID.
+func RandomRequestIDs(context.Context) string {
+	return uuid.NewString()
+}
+
+// UUIDRequestIDs returns a [RequestIDFunc] that sends the UUIDs of the generator,
+// e.g. a deterministic one in tests.
+func UUIDRequestIDs(generate func() uuid.UUID) RequestIDFunc {
+	return func(context.Context) string {
+		return generate().String()
+	}
+}
+
+type requestIDKey struct{}
+
+// WithRequestID returns a context that makes every request created with it send the
+// id as the [HeaderClientRequestID], e.g. the trace ID of the incoming request, so
+// BC telemetry can be joined with your own. It takes precedence over [WithRequestIDs].
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFrom returns the id set with [WithRequestID] or an empty string.
+func RequestIDFrom(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// requestID returns the id of the context or of the client's [RequestIDFunc].
+func (c *Client) requestID(ctx context.Context) string {
+	if id := RequestIDFrom(ctx); id != "" {
+		return id
+	}
+	if c.requestIDs != nil {
+		return c.requestIDs(ctx)
+	}
+	return ""
+}
@@ -0,0 +1,65 @@
+package bc_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/google/uuid"
+)
+
+func TestRequestIDs(t *testing.T) {
+	opts := bc.RequestOptions{Method: http.MethodGet, EntitySetName: "fakeEntities"}
+	newRequestID := func(t *testing.T, client *bc.Client, ctx context.Context) string {
+		t.Helper()
+		req, err := client.NewRequest(ctx, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return req.Header.Get(bc.HeaderClientRequestID)
+	}
+
+	client, err := bc.NewClient(fakeConfig, bc.WithAuthClient(fakeTokenGetter{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id := newRequestID(t, client, context.Background()); id != "" {
+		t.Errorf("wanted no request id by default, got %s", id)
+	}
+
+	var n byte
+	sequential := func() uuid.UUID {
+		n++
+		return uuid.UUID{15: n}
+	}
+	client, err = bc.NewClient(fakeConfig, bc.WithAuthClient(fakeTokenGetter{}), bc.WithRequestIDs(bc.UUIDRequestIDs(sequential)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	table := []struct {
+		name string
+		ctx  context.Context
+		want string
+	}{
+		{"First", context.Background(), "00000000-0000-0000-0000-000000000001"},
+		{"Second", context.Background(), "00000000-0000-0000-0000-000000000002"},
+		{"Context", bc.WithRequestID(context.Background(), "trace-1234"), "trace-1234"},
+	}
+	for _, v := range table {
+		t.Run(v.name, func(t *testing.T) {
+			if got := newRequestID(t, client, v.ctx); got != v.want {
+				t.Errorf("wanted %s, got %s", v.want, got)
+			}
+		})
+	}
+
+	client, err = bc.NewClient(fakeConfig, bc.WithAuthClient(fakeTokenGetter{}), bc.WithRequestIDs(bc.RandomRequestIDs))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := uuid.Parse(newRequestID(t, client, context.Background())); err != nil {
+		t.Errorf("wanted a random UUID: %s", err)
+	}
+}
@@ -28,7 +28,7 @@ func TestRequestOptions(t *testing.T) {
 				Method:        http.MethodGet,
 				EntitySetName: "fakeEntities",
 				QueryParams: bc.QueryParams{
-					"$filter": "number eq 'XXXX'",
+					{Key: "$filter", Value: "number eq 'XXXX'"},
 				},
 			},
 		},
@@ -0,0 +1,61 @@
+package bc
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+)
+
+// RequestValidator checks the [RequestOptions] before a request is created, to enforce
+// conventions like requiring an ETag for deletes. Register it with [WithRequestValidator].
+type RequestValidator func(opts RequestOptions) error
+
+// validateRequest rejects mutating requests of a [Client.ReadOnly] client, then runs
+// [RequestOptions.Validate] and the global validators and the validators registered for the entity set.
+func (c *Client) validateRequest(opts RequestOptions) error {
+	if err := c.checkReadOnly(opts.Method, opts.EntitySetName); err != nil {
+		return err
+	}
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+
+	validators := slices.Concat(c.requestValidators[""], c.requestValidators[entitySetKey(opts.EntitySetName)])
+	var errs []error
+	for _, fn := range validators {
+		if err := fn(opts); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid requestoptions: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+// RequireETag rejects requests with the methods that have no ETag, so they cannot overwrite
+// changes with the default "*". Defaults to PATCH, PUT and DELETE.
+func RequireETag(methods ...string) RequestValidator {
+	if len(methods) == 0 {
+		methods = []string{http.MethodPatch, http.MethodPut, http.MethodDelete}
+	}
+	return func(opts RequestOptions) error {
+		if opts.ETag == "" && slices.Contains(methods, opts.Method) {
+			return fmt.Errorf("%s %s requires an ETag", opts.Method, opts.EntitySetName)
+		}
+		return nil
+	}
+}
+
+// MaxTop rejects requests with a $top above n.
+func MaxTop(n int) RequestValidator {
+	return func(opts RequestOptions) error {
+		top, err := strconv.Atoi(opts.QueryParams.Get("$top"))
+		if err == nil && top > n {
+			return fmt.Errorf("$top %d is above the maximum of %d", top, n)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,74 @@
+package bc_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/erlorenz/bc-go/internal/bctest"
+	"github.com/google/uuid"
+)
+
+func TestWithRequestValidator(t *testing.T) {
+	errNoCustomers := errors.New("customers are read only")
+	readOnly := func(opts bc.RequestOptions) error {
+		if opts.Method != http.MethodGet {
+			return errNoCustomers
+		}
+		return nil
+	}
+
+	client, err := bc.NewClient(fakeConfig, bc.WithAuthClient(fakeTokenGetter{}),
+		bc.WithRequestValidator("", bc.RequireETag(http.MethodDelete)),
+		bc.WithRequestValidator("", bc.MaxTop(1000)),
+		bc.WithRequestValidator("customers", readOnly),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	id := uuid.New()
+	table := []struct {
+		name    string
+		opts    bc.RequestOptions
+		wantErr error
+		valid   bool
+	}{
+		{"Valid", bc.RequestOptions{Method: http.MethodGet, EntitySetName: "items", QueryParams: bc.QueryParams{{Key: "$top", Value: "1000"}}}, nil, true},
+		{"TopTooHigh", bc.RequestOptions{Method: http.MethodGet, EntitySetName: "items", QueryParams: bc.QueryParams{{Key: "$top", Value: "1001"}}}, nil, false},
+		{"DeleteWithoutETag", bc.RequestOptions{Method: http.MethodDelete, EntitySetName: "items", RecordID: id}, nil, false},
+		{"DeleteWithETag", bc.RequestOptions{Method: http.MethodDelete, EntitySetName: "items", RecordID: id, ETag: `W/"1"`}, nil, true},
+		{"PatchWithoutETag", bc.RequestOptions{Method: http.MethodPatch, EntitySetName: "items", RecordID: id, Body: map[string]any{}}, nil, true},
+		{"EntitySetValidator", bc.RequestOptions{Method: http.MethodPost, EntitySetName: "customers", Body: map[string]any{}}, errNoCustomers, false},
+		{"NavigationPath", bc.RequestOptions{Method: http.MethodPost, EntitySetName: "companies(" + validGUID + ")/customers", Body: map[string]any{}}, errNoCustomers, false},
+	}
+
+	for _, v := range table {
+		t.Run(v.name, func(t *testing.T) {
+			_, err := client.NewRequest(context.Background(), v.opts)
+			if (err == nil) != v.valid {
+				t.Errorf("wanted valid %t, got %v", v.valid, err)
+			}
+			if v.wantErr != nil && !errors.Is(err, v.wantErr) {
+				t.Errorf("wanted %v, got %v", v.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestRequestValidatorBatch(t *testing.T) {
+	st := &bctest.SequenceTransport{Responses: []*http.Response{bctest.NewResponse(200, map[string]any{"responses": []any{}})}}
+	client := newSequenceClient(t, st, bc.WithRequestValidator("", bc.RequireETag()))
+
+	_, err := client.Batch(context.Background(), []bc.RequestOptions{
+		{Method: http.MethodDelete, EntitySetName: "items", RecordID: uuid.New()},
+	}, bc.BatchOptions{})
+	if err == nil {
+		t.Error("expected error")
+	}
+	if st.Count() != 0 {
+		t.Errorf("wanted no request sent, got %d", st.Count())
+	}
+}
@@ -0,0 +1,105 @@
+package bc
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrValidation is matched by errors.Is for any [ValidationError].
+var ErrValidation = errors.New("validation failed")
+
+// FieldError describes a single field that failed validation.
+type FieldError struct {
+	Field  string
+	Reason string
+}
+
+func (fe FieldError) String() string {
+	return fmt.Sprintf("%s: %s", fe.Field, fe.Reason)
+}
+
+// ValidationError is returned when a registered [ResponseValidator] rejects a decoded response.
+// It matches [ErrValidation] with errors.Is.
+type ValidationError struct {
+	EntitySetName string
+	Fields        []FieldError
+}
+
+func (err ValidationError) Error() string {
+	s := make([]string, len(err.Fields))
+	for i, f := range err.Fields {
+		s[i] = f.String()
+	}
+	return fmt.Sprintf("%s: %s: [ %s ]", ErrValidation, err.EntitySetName, strings.Join(s, ", "))
+}
+
+// Is returns true if target is ErrValidation.
+func (err ValidationError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// ResponseValidator checks a decoded record and returns the fields that are invalid.
+// The record is the type T of the [APIPage] or [APIQuery] that decoded it.
+type ResponseValidator func(record any) []FieldError
+
+// ValidatorFor adapts a typed validation function to a [ResponseValidator].
+// Records that are not of type T are ignored.
+func ValidatorFor[T any](fn func(T) []FieldError) ResponseValidator {
+	return func(record any) []FieldError {
+		v, ok := record.(T)
+		if !ok {
+			return nil
+		}
+		return fn(v)
+	}
+}
+
+// validateRecord runs the validators registered for the entity set against the record.
+func validateRecord[T any](c *Client, entitySetName string, record T) error {
+	fields := runValidators(c.responseValidators[entitySetKey(entitySetName)], record)
+	if len(fields) > 0 {
+		return ValidationError{EntitySetName: entitySetName, Fields: fields}
+	}
+	return nil
+}
+
+// validateList runs the validators registered for the entity set against each record,
+// prefixing each field with the index of the record.
+func validateList[T any](c *Client, entitySetName string, records []T) error {
+	validators := c.responseValidators[entitySetKey(entitySetName)]
+	if len(validators) == 0 {
+		return nil
+	}
+
+	var fields []FieldError
+	for i, record := range records {
+		for _, fe := range runValidators(validators, record) {
+			fe.Field = fmt.Sprintf("value[%d].%s", i, fe.Field)
+			fields = append(fields, fe)
+		}
+	}
+
+	if len(fields) > 0 {
+		return ValidationError{EntitySetName: entitySetName, Fields: fields}
+	}
+	return nil
+}
+
+func runValidators(validators []ResponseValidator, record any) []FieldError {
+	var fields []FieldError
+	for _, fn := range validators {
+		fields = append(fields, fn(record)...)
+	}
+	return fields
+}
+
+// entitySetKey returns the last entity set in a path so that navigations
+// like "salesOrders(id)/salesOrderLines" resolve to "salesOrderLines".
+func entitySetKey(entitySetName string) string {
+	if i := strings.LastIndex(entitySetName, "/"); i >= 0 {
+		entitySetName = entitySetName[i+1:]
+	}
+	name, _, _ := strings.Cut(entitySetName, "(")
+	return name
+}
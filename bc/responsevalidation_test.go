@@ -0,0 +1,54 @@
+package bc_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/google/uuid"
+)
+
+func positiveQuantity(f fakeEntity) []bc.FieldError {
+	if f.Quantity <= 0 {
+		return []bc.FieldError{{Field: "Quantity", Reason: "must be positive"}}
+	}
+	return nil
+}
+
+func TestResponseValidatorList(t *testing.T) {
+	body := map[string]any{
+		"value": []map[string]any{
+			{"ID": validGUID, "Quantity": 5},
+			{"ID": validGUID, "Quantity": 0},
+		},
+	}
+
+	client := newMockClient(t, 200, body, bc.WithResponseValidator("fakeEntities", bc.ValidatorFor(positiveQuantity)))
+	page := bc.NewAPIPage[fakeEntity](client, "fakeEntities")
+
+	_, err := page.List(context.Background(), bc.ListOptions{})
+	if !errors.Is(err, bc.ErrValidation) {
+		t.Fatalf("expected ErrValidation, got %v", err)
+	}
+
+	var valErr bc.ValidationError
+	if !errors.As(err, &valErr) {
+		t.Fatalf("expected ValidationError, got %v", err)
+	}
+
+	if len(valErr.Fields) != 1 || valErr.Fields[0].Field != "value[1].Quantity" {
+		t.Errorf("unexpected fields %+v", valErr.Fields)
+	}
+}
+
+func TestResponseValidatorGet(t *testing.T) {
+	body := map[string]any{"ID": validGUID, "Quantity": 3}
+
+	client := newMockClient(t, 200, body, bc.WithResponseValidator("fakeEntities", bc.ValidatorFor(positiveQuantity)))
+	page := bc.NewAPIPage[fakeEntity](client, "fakeEntities")
+
+	if _, err := page.Get(context.Background(), uuid.MustParse(validGUID), bc.GetOptions{}); err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+}
@@ -0,0 +1,289 @@
+package bc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// RetryAttempt describes the result of a single attempt of a request.
+// It is passed to a [RetryClassifier] to decide if the request is retried.
+type RetryAttempt struct {
+	// Attempt is the number of attempts made so far, starting at 1.
+	Attempt int
+	Request *http.Request
+	// Response is nil if Err is not nil. The body must not be read.
+	Response *http.Response
+	Err      error
+	// StatusCode is 0 if Err is not nil.
+	StatusCode int
+	// ErrorCode and ErrorMessage are the error.code and error.message of the response body
+	// if it is a BC error response.
+	ErrorCode    ErrorCode
+	ErrorMessage string
+	// Gateway is the kind of a 502, 503 or 504 response, see [FingerprintGateway].
+	Gateway GatewayKind
+}
+
+// RetryDecision is returned by a [RetryClassifier].
+type RetryDecision struct {
+	// Retry is false to abort and return the response or error as is.
+	Retry bool
+	// Backoff is the duration to wait before the next attempt.
+	Backoff time.Duration
+}
+
+// RetryClassifier decides if and when a failed request is retried.
+// Set it on the [Client] with [WithRetryClassifier].
+type RetryClassifier interface {
+	Classify(RetryAttempt) RetryDecision
+}
+
+// RetryClassifierFunc adapts a function to a [RetryClassifier].
+type RetryClassifierFunc func(RetryAttempt) RetryDecision
+
+// Classify calls f.
+func (f RetryClassifierFunc) Classify(ra RetryAttempt) RetryDecision {
+	return f(ra)
+}
+
+// DefaultRetryClassifier retries throttling, gateway, transient BC error codes and network errors
+// with exponential backoff, honoring the Retry-After header.
+// POST requests are only retried on a 429 or a gateway failure that rejected the request,
+// see [GatewayKind.Rejected], as other failures may have created the record.
+// An environment update is not retried, and a tenant move waits the MaxDelay.
+type DefaultRetryClassifier struct {
+	// MaxAttempts includes the first attempt. Defaults to 4.
+	MaxAttempts int
+	// BaseDelay is doubled after each attempt. Defaults to 500ms.
+	BaseDelay time.Duration
+	// MaxDelay caps the backoff. Defaults to 30s.
+	MaxDelay time.Duration
+	// RetryStatusCodes defaults to 429, 502, 503 and 504.
+	RetryStatusCodes []int
+	// RetryErrorCodes are BC error codes that are retried regardless of status
+	// in addition to those where [ErrorCode.IsTransient] is true.
+	RetryErrorCodes []ErrorCode
+	// Clock converts a Retry-After date to a backoff. Defaults to the [SystemClock].
+	Clock Clock
+}
+
+var defaultRetryStatusCodes = []int{
+	http.StatusTooManyRequests,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// Classify implements [RetryClassifier].
+func (d DefaultRetryClassifier) Classify(ra RetryAttempt) RetryDecision {
+	maxAttempts := d.MaxAttempts
+	if maxAttempts == 0 {
+		maxAttempts = 4
+	}
+	if ra.Attempt >= maxAttempts {
+		return RetryDecision{}
+	}
+
+	backoff := d.backoff(ra.Attempt)
+
+	if ra.Err != nil {
+		if errors.Is(ra.Err, context.Canceled) || errors.Is(ra.Err, context.DeadlineExceeded) {
+			return RetryDecision{}
+		}
+		if ra.Request != nil && ra.Request.Method == http.MethodPost {
+			return RetryDecision{}
+		}
+		return RetryDecision{Retry: true, Backoff: backoff}
+	}
+
+	if ra.Response != nil {
+		if after, ok := retryAfterAt(ra.Response.Header, clockOrSystem(d.Clock).Now()); ok {
+			backoff = after
+		}
+	}
+
+	if !ra.Gateway.Retryable() {
+		return RetryDecision{}
+	}
+	if ra.Gateway == GatewayTenantMoving {
+		backoff = max(backoff, d.maxDelay())
+	}
+
+	if ra.ErrorCode.IsTransient() || slices.Contains(d.RetryErrorCodes, ra.ErrorCode) {
+		return RetryDecision{Retry: true, Backoff: backoff}
+	}
+
+	if ra.StatusCode == http.StatusTooManyRequests || ra.Gateway.Rejected() {
+		return RetryDecision{Retry: true, Backoff: backoff}
+	}
+
+	if ra.Request != nil && ra.Request.Method == http.MethodPost {
+		return RetryDecision{}
+	}
+
+	statusCodes := d.RetryStatusCodes
+	if len(statusCodes) == 0 {
+		statusCodes = defaultRetryStatusCodes
+	}
+	if slices.Contains(statusCodes, ra.StatusCode) {
+		return RetryDecision{Retry: true, Backoff: backoff}
+	}
+
+	return RetryDecision{}
+}
+
+func (d DefaultRetryClassifier) backoff(attempt int) time.Duration {
+	base := d.BaseDelay
+	if base == 0 {
+		base = 500 * time.Millisecond
+	}
+	maxDelay := d.maxDelay()
+
+	delay := base << (attempt - 1)
+	if delay <= 0 || delay > maxDelay {
+		return maxDelay
+	}
+	return delay
+}
+
+func (d DefaultRetryClassifier) maxDelay() time.Duration {
+	if d.MaxDelay == 0 {
+		return 30 * time.Second
+	}
+	return d.MaxDelay
+}
+
+// retryAfter parses the Retry-After header as seconds or an HTTP date.
+func retryAfter(h http.Header) (time.Duration, bool) {
+	return retryAfterAt(h, time.Now())
+}
+
+// retryAfterAt is retryAfter with an HTTP date relative to now.
+func retryAfterAt(h http.Header, now time.Time) (time.Duration, bool) {
+	v := h.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return max(t.Sub(now), 0), true
+	}
+	return 0, false
+}
+
+// doWithRetry sends the request and asks the classifier whether to retry until it
+// succeeds, the classifier aborts or the context is done.
+func (c *Client) doWithRetry(r *http.Request) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		res, err := c.baseClient.Do(r)
+
+		if err == nil && res.StatusCode < 400 {
+			return res, nil
+		}
+
+		ra := RetryAttempt{
+			Attempt:  attempt,
+			Request:  r,
+			Response: res,
+			Err:      err,
+		}
+		if res != nil {
+			ra.StatusCode = res.StatusCode
+			ra.ErrorCode, ra.ErrorMessage = peekErrorResponse(res)
+			ra.Gateway = FingerprintGateway(res.StatusCode, peekBody(res))
+		}
+
+		decision := c.retryClassifier.Classify(ra)
+		if !decision.Retry {
+			return res, err
+		}
+
+		// Cannot resend a body that can't be rewound.
+		if r.Body != nil && r.Body != http.NoBody && r.GetBody == nil {
+			return res, err
+		}
+
+		// Retrying into a maintenance window only fails again.
+		if merr := c.checkMaintenance(r, clockOrSystem(c.clock).Now().Add(decision.Backoff)); merr != nil {
+			if res != nil {
+				io.Copy(io.Discard, res.Body)
+				res.Body.Close()
+			}
+			return nil, merr
+		}
+
+		c.logger.Debug("Retrying request.", "attempt", attempt, "status", ra.StatusCode, "code", ra.ErrorCode, "error", err, "backoff", decision.Backoff)
+
+		if res != nil {
+			io.Copy(io.Discard, res.Body)
+			res.Body.Close()
+		}
+
+		if err := sleepContext(r.Context(), c.clock, decision.Backoff); err != nil {
+			return nil, fmt.Errorf("retry aborted: %w", err)
+		}
+
+		if r.GetBody != nil {
+			body, err := r.GetBody()
+			if err != nil {
+				return nil, fmt.Errorf("retry rewind body: %w", err)
+			}
+			r = r.Clone(r.Context())
+			r.Body = body
+		}
+	}
+}
+
+// peekErrorResponse reads the error code and message from the body and
+// replaces the body so it can be read again.
+func peekErrorResponse(res *http.Response) (ErrorCode, string) {
+	b := peekBody(res)
+	if b == nil {
+		return "", ""
+	}
+
+	var data ErrorResponse
+	if err := json.Unmarshal(b, &data); err != nil {
+		return "", ""
+	}
+	return ErrorCode(data.Error.Code), data.Error.Message
+}
+
+// peekBody reads the body and replaces it so it can be read again.
+// It returns nil if there is no body or it cannot be read.
+func peekBody(res *http.Response) []byte {
+	if res.Body == nil {
+		return nil
+	}
+	b, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	res.Body = io.NopCloser(bytes.NewReader(b))
+	if err != nil {
+		return nil
+	}
+	return b
+}
+
+// sleepContext waits for d on the clock or until the context is done.
+func sleepContext(ctx context.Context, clock Clock, d time.Duration) error {
+	// Checked first as select picks randomly if both are ready.
+	if err := ctx.Err(); err != nil || d <= 0 {
+		return err
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-clockOrSystem(clock).After(d):
+		return nil
+	}
+}
@@ -0,0 +1,109 @@
+package bc_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/erlorenz/bc-go/internal/bctest"
+	"github.com/google/uuid"
+)
+
+func newSequenceClient(t *testing.T, st *bctest.SequenceTransport, opts ...bc.ClientOption) *bc.Client {
+	t.Helper()
+
+	opts = append([]bc.ClientOption{bc.WithAuthClient(fakeTokenGetter{}), bc.WithHTTPClient(&http.Client{Transport: st})}, opts...)
+
+	client, err := bc.NewClient(fakeConfig, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+func errorResponse(status int, code string) *http.Response {
+	return bctest.NewResponse(status, bc.ErrorResponse{Error: bc.ErrorResponseError{Code: code, Message: "message"}})
+}
+
+func TestRetryDefaultClassifier(t *testing.T) {
+	st := &bctest.SequenceTransport{Responses: []*http.Response{
+		errorResponse(http.StatusServiceUnavailable, "ServiceUnavailable"),
+		errorResponse(http.StatusTooManyRequests, "TooManyRequests"),
+		bctest.NewResponse(http.StatusOK, map[string]any{"ID": validGUID}),
+	}}
+
+	client := newSequenceClient(t, st, bc.WithRetryClassifier(bc.DefaultRetryClassifier{BaseDelay: time.Millisecond}))
+	page := bc.NewAPIPage[fakeEntity](client, "fakeEntities")
+
+	if _, err := page.Get(context.Background(), uuid.New(), bc.GetOptions{}); err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	if got := st.Count(); got != 3 {
+		t.Errorf("wanted 3 attempts, got %d", got)
+	}
+}
+
+func TestRetryErrorCode(t *testing.T) {
+	st := &bctest.SequenceTransport{Responses: []*http.Response{
+		errorResponse(http.StatusBadRequest, "Internal_RecordLocked"),
+		bctest.NewResponse(http.StatusOK, map[string]any{"ID": validGUID}),
+	}}
+
+	rc := bc.DefaultRetryClassifier{BaseDelay: time.Millisecond, RetryErrorCodes: []bc.ErrorCode{"Internal_RecordLocked"}}
+	client := newSequenceClient(t, st, bc.WithRetryClassifier(rc))
+	page := bc.NewAPIPage[fakeEntity](client, "fakeEntities")
+
+	if _, err := page.Update(context.Background(), uuid.New(), nil, map[string]any{"Quantity": 1}); err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	if got := st.Count(); got != 2 {
+		t.Errorf("wanted 2 attempts, got %d", got)
+	}
+}
+
+func TestRetryCustomClassifierAbort(t *testing.T) {
+	st := &bctest.SequenceTransport{Responses: []*http.Response{
+		errorResponse(http.StatusServiceUnavailable, "ServiceUnavailable"),
+	}}
+
+	var attempts []bc.RetryAttempt
+	rc := bc.RetryClassifierFunc(func(ra bc.RetryAttempt) bc.RetryDecision {
+		attempts = append(attempts, ra)
+		return bc.RetryDecision{}
+	})
+
+	client := newSequenceClient(t, st, bc.WithRetryClassifier(rc))
+	page := bc.NewAPIPage[fakeEntity](client, "fakeEntities")
+
+	if _, err := page.Get(context.Background(), uuid.New(), bc.GetOptions{}); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	if len(attempts) != 1 || attempts[0].ErrorCode != "ServiceUnavailable" || attempts[0].StatusCode != 503 {
+		t.Errorf("unexpected attempts %+v", attempts)
+	}
+}
+
+func TestRetryPostNotRetried(t *testing.T) {
+	rc := bc.DefaultRetryClassifier{}
+	req, _ := http.NewRequest(http.MethodPost, "https://example.com", nil)
+
+	d := rc.Classify(bc.RetryAttempt{Attempt: 1, Request: req, StatusCode: http.StatusServiceUnavailable})
+	if d.Retry {
+		t.Error("expected POST with 503 not to be retried")
+	}
+
+	d = rc.Classify(bc.RetryAttempt{Attempt: 1, Request: req, StatusCode: http.StatusTooManyRequests})
+	if !d.Retry {
+		t.Error("expected POST with 429 to be retried")
+	}
+
+	d = rc.Classify(bc.RetryAttempt{Attempt: 4, StatusCode: http.StatusServiceUnavailable})
+	if d.Retry {
+		t.Error("expected no retry after MaxAttempts")
+	}
+}
@@ -0,0 +1,18 @@
+package bc
+
+import "net/http"
+
+// Signer adds headers to a request after it is fully built, e.g. an HMAC signature
+// over the method, path and body required by a gateway. The body is the marshaled
+// request body, or nil. Set it with [WithSigner].
+type Signer interface {
+	Sign(r *http.Request, body []byte) error
+}
+
+// SignerFunc adapts a function to a [Signer].
+type SignerFunc func(r *http.Request, body []byte) error
+
+// Sign calls f.
+func (f SignerFunc) Sign(r *http.Request, body []byte) error {
+	return f(r, body)
+}
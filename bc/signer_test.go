@@ -0,0 +1,65 @@
+package bc_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/erlorenz/bc-go/bc"
+)
+
+func hmacSignature(key []byte, r *http.Request, body []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(r.Method + "\n" + r.URL.RequestURI() + "\n"))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestWithSigner(t *testing.T) {
+	key := []byte("GATEWAYKEY")
+	signer := bc.SignerFunc(func(r *http.Request, body []byte) error {
+		if r.Header.Get("Authorization") == "" {
+			return errors.New("signed before the Authorization header was set")
+		}
+		r.Header.Set("X-Signature", hmacSignature(key, r, body))
+		return nil
+	})
+
+	client, err := bc.NewClient(fakeConfig, bc.WithAuthClient(fakeTokenGetter{}), bc.WithSigner(signer))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req, err := client.NewRequest(context.Background(), bc.RequestOptions{
+		Method:        http.MethodPost,
+		EntitySetName: "fakeEntities",
+		Body:          map[string]string{"number": "1000"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := hmacSignature(key, req, []byte(readBody(t, req)))
+	if got := req.Header.Get("X-Signature"); got != want {
+		t.Errorf("wanted signature %s, got %s", want, got)
+	}
+}
+
+func TestWithSignerError(t *testing.T) {
+	signErr := errors.New("no signing key")
+	signer := bc.SignerFunc(func(*http.Request, []byte) error { return signErr })
+
+	client, err := bc.NewClient(fakeConfig, bc.WithAuthClient(fakeTokenGetter{}), bc.WithSigner(signer))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = client.NewRequest(context.Background(), bc.RequestOptions{Method: http.MethodGet, EntitySetName: "fakeEntities"})
+	if !errors.Is(err, signErr) {
+		t.Errorf("wanted signing error, got %v", err)
+	}
+}
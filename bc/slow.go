@@ -0,0 +1,114 @@
+package bc
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// DefaultSlowThreshold is the threshold of [WithSlowRequestHook] when it is 0.
+const DefaultSlowThreshold = 10 * time.Second
+
+// defaultSlowBodySize is the most bytes of a request body in a [SlowRequest].
+const defaultSlowBodySize = 64 << 10
+
+// SlowRequest is a request attempt that took longer than the threshold of
+// [WithSlowRequestHook], captured so intermittent slowness can be diagnosed later.
+type SlowRequest struct {
+	Method string
+	URL    string
+	// Header has the Authorization and Cookie headers redacted.
+	Header http.Header
+	// Body is the start of the request body, if it can be read again.
+	Body []byte
+	// Truncated is true if Body is only the start of the body.
+	Truncated bool
+	// StatusCode is 0 if the attempt failed with Err.
+	StatusCode int
+	Err        error
+	Timings    Timings
+	// CorrelationID and RequestID are the "ms-correlation-id" and "request-id"
+	// response headers to look the request up in the telemetry of the environment.
+	CorrelationID string
+	RequestID     string
+}
+
+// LogValue implements [slog.LogValuer] without the header and body.
+func (s SlowRequest) LogValue() slog.Value {
+	attrs := []slog.Attr{
+		slog.String("method", s.Method),
+		slog.String("url", s.URL),
+		slog.Int("status", s.StatusCode),
+		slog.Any("timings", s.Timings),
+	}
+	if s.CorrelationID != "" {
+		attrs = append(attrs, slog.String("correlationId", s.CorrelationID))
+	}
+	if s.RequestID != "" {
+		attrs = append(attrs, slog.String("requestId", s.RequestID))
+	}
+	if s.Err != nil {
+		attrs = append(attrs, slog.String("error", s.Err.Error()))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// SlowRequestHandler is called with each [SlowRequest], e.g. to send it to an error
+// tracker or append it to a file.
+type SlowRequestHandler func(ctx context.Context, s SlowRequest)
+
+// SlowRequestOptions configure [WithSlowRequestHook].
+type SlowRequestOptions struct {
+	// Threshold is the duration of an attempt, until the response headers are read,
+	// above which it is reported. Defaults to DefaultSlowThreshold.
+	Threshold time.Duration
+	// MaxBodySize is the most bytes of the request body captured. Defaults to 64 KiB,
+	// use -1 to leave the body out.
+	MaxBodySize int
+	// Handler defaults to logging the request at warn level.
+	Handler SlowRequestHandler
+}
+
+// redactHeader returns a copy of the header without the credentials.
+func redactHeader(h http.Header) http.Header {
+	header := h.Clone()
+	for _, key := range []string{"Authorization", "Cookie"} {
+		if header.Get(key) != "" {
+			header.Set(key, "REDACTED")
+		}
+	}
+	return header
+}
+
+// reportSlow calls the slow request handler if the attempt took longer than the threshold.
+func (c *Client) reportSlow(r *http.Request, res *http.Response, err error, t Timings) {
+	opts := c.slowRequests
+	if opts == nil || t.Total < opts.Threshold {
+		return
+	}
+
+	s := SlowRequest{Method: r.Method, URL: r.URL.String(), Header: redactHeader(r.Header), Err: err, Timings: t}
+	if res != nil {
+		s.StatusCode = res.StatusCode
+		s.CorrelationID = res.Header.Get("ms-correlation-id")
+		if s.CorrelationID == "" {
+			s.CorrelationID = res.Header.Get("ms-correlation-x")
+		}
+		s.RequestID = res.Header.Get("request-id")
+	}
+	if opts.MaxBodySize > 0 && r.GetBody != nil {
+		if body, err := r.GetBody(); err == nil {
+			b, _ := io.ReadAll(io.LimitReader(body, int64(opts.MaxBodySize)+1))
+			body.Close()
+			s.Body, s.Truncated = b[:min(len(b), opts.MaxBodySize)], len(b) > opts.MaxBodySize
+		}
+	}
+
+	if opts.Handler != nil {
+		opts.Handler(r.Context(), s)
+		return
+	}
+	c.logger.Warn("Slow request.", "request", s)
+}
@@ -0,0 +1,61 @@
+package bc_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/erlorenz/bc-go/internal/bctest"
+	"github.com/google/uuid"
+)
+
+func TestWithSlowRequestHook(t *testing.T) {
+	delays := []time.Duration{0, 20 * time.Millisecond}
+	transport := bc.RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		time.Sleep(delays[0])
+		delays = delays[1:]
+		res := bctest.NewResponse(http.StatusCreated, map[string]any{"ID": validGUID})
+		res.Header.Set("ms-correlation-id", "correlation")
+		return res, nil
+	})
+
+	var slow []bc.SlowRequest
+	client, err := bc.NewClient(fakeConfig, bc.WithAuthClient(fakeTokenGetter{}), bc.WithHTTPClient(&http.Client{Transport: transport}),
+		bc.WithSlowRequestHook(bc.SlowRequestOptions{
+			Threshold:   10 * time.Millisecond,
+			MaxBodySize: 10,
+			Handler:     func(_ context.Context, s bc.SlowRequest) { slow = append(slow, s) },
+		}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	page := bc.NewAPIPage[fakeEntity](client, "fakeEntities")
+
+	if _, err := page.Get(context.Background(), uuid.New(), bc.GetOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if len(slow) != 0 {
+		t.Fatalf("wanted a fast request not to be reported, got %+v", slow)
+	}
+
+	if _, err := page.Create(context.Background(), map[string]any{"Name": "a long name"}, bc.GetOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if len(slow) != 1 {
+		t.Fatalf("wanted 1 slow request, got %d", len(slow))
+	}
+	s := slow[0]
+	if s.Method != http.MethodPost || !strings.Contains(s.URL, "/fakeEntities") || s.StatusCode != http.StatusCreated ||
+		s.CorrelationID != "correlation" || s.Timings.Total < 20*time.Millisecond {
+		t.Errorf("unexpected slow request %+v", s)
+	}
+	if got := s.Header.Get("Authorization"); got != "REDACTED" {
+		t.Errorf("wanted the Authorization header redacted, got %q", got)
+	}
+	if string(s.Body) != `{"Name":"a` || !s.Truncated {
+		t.Errorf("unexpected body %q", s.Body)
+	}
+}
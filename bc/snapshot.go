@@ -0,0 +1,151 @@
+package bc
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+)
+
+// EntitySpec is an entity set to fetch with [Client.Snapshot].
+type EntitySpec struct {
+	// Name is the key of the records in the [Snapshot]. Defaults to EntitySetName.
+	Name          string
+	EntitySetName string
+	// ListOptions select and filter the records. Every page is fetched.
+	ListOptions ListOptions
+}
+
+// Snapshot has the records of each [EntitySpec] by name. Decode them with [SnapshotRecords].
+type Snapshot struct {
+	client     *Client
+	entitySets map[string]string
+	records    map[string][]json.RawMessage
+}
+
+// Names returns the sorted names of the entities in the snapshot.
+func (s Snapshot) Names() []string {
+	return slices.Sorted(maps.Keys(s.records))
+}
+
+// Raw returns the JSON of the records of the entity, or nil if it is not in the snapshot.
+func (s Snapshot) Raw(name string) []json.RawMessage {
+	return s.records[name]
+}
+
+// Len returns the number of records of the entity.
+func (s Snapshot) Len(name string) int {
+	return len(s.records[name])
+}
+
+// SnapshotRecords decodes the records of the entity in the snapshot with the codec of the
+// client and runs the response validators registered for its entity set.
+func SnapshotRecords[T Validator](s Snapshot, name string) ([]T, error) {
+	raw, ok := s.records[name]
+	if !ok {
+		return nil, fmt.Errorf("snapshot has no entity %s", name)
+	}
+
+	records := make([]T, len(raw))
+	for i, b := range raw {
+		if err := s.client.codec.Unmarshal(b, &records[i]); err != nil {
+			return nil, fmt.Errorf("decode %s record %d: %w", name, i, err)
+		}
+		if err := records[i].Validate(); err != nil {
+			return nil, fmt.Errorf("failed validation of %T: %w", records[i], err)
+		}
+	}
+
+	if err := validateList(s.client, s.entitySets[name], records); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// rawRecord keeps the JSON of a record for [Client.Snapshot].
+type rawRecord struct {
+	json.RawMessage
+}
+
+func (rawRecord) Validate() error {
+	return nil
+}
+
+// Snapshot fetches every page of the entity sets concurrently, one goroutine per spec,
+// for dashboards that need several entity sets at once. The requests share the
+// [RateLimiter] and [ConcurrencyLimiter] of the client, so configure them with
+// [WithRateLimiter] and [WithConcurrencyLimiter] to keep a large snapshot within the
+// limits of the tenant. It stops at the first error.
+func (c *Client) Snapshot(ctx context.Context, specs []EntitySpec) (Snapshot, error) {
+	snap := Snapshot{
+		client:     c,
+		entitySets: make(map[string]string, len(specs)),
+		records:    make(map[string][]json.RawMessage, len(specs)),
+	}
+
+	for _, spec := range specs {
+		if spec.EntitySetName == "" {
+			return snap, errors.New("snapshot: EntitySetName is required")
+		}
+		name := cmp.Or(spec.Name, spec.EntitySetName)
+		if _, ok := snap.entitySets[name]; ok {
+			return snap, fmt.Errorf("snapshot: duplicate entity %s", name)
+		}
+		snap.entitySets[name] = spec.EntitySetName
+	}
+
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, spec := range specs {
+		name := cmp.Or(spec.Name, spec.EntitySetName)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			records, err := c.snapshotEntity(ctx, spec)
+			if err != nil {
+				cancel(fmt.Errorf("snapshot %s: %w", name, err))
+				return
+			}
+			mu.Lock()
+			snap.records[name] = records
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	if err := context.Cause(ctx); err != nil {
+		return snap, err
+	}
+	return snap, nil
+}
+
+// snapshotEntity follows the nextLinks of the spec to the last page.
+func (c *Client) snapshotEntity(ctx context.Context, spec EntitySpec) ([]json.RawMessage, error) {
+	page := NewAPIPage[rawRecord](c, spec.EntitySetName)
+
+	var records []json.RawMessage
+	var nextLink string
+	for {
+		if err := ctx.Err(); err != nil {
+			return records, err
+		}
+		list, err := page.ListPage(ctx, nextLink, spec.ListOptions)
+		if err != nil {
+			return records, err
+		}
+		for _, r := range list.Value {
+			records = append(records, r.RawMessage)
+		}
+		if list.NextLink == "" {
+			return records, nil
+		}
+		nextLink = list.NextLink
+	}
+}
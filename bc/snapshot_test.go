@@ -0,0 +1,91 @@
+package bc_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/erlorenz/bc-go/bcfake"
+	"github.com/erlorenz/bc-go/internal/bctest"
+)
+
+func TestSnapshot(t *testing.T) {
+	server := bcfake.New()
+	for i := range 5 {
+		server.Seed("customers", map[string]any{"number": fmt.Sprintf("C%d", i)})
+	}
+	for i := range 3 {
+		server.Seed("items", map[string]any{"number": fmt.Sprintf("I%d", i)})
+	}
+
+	client, err := bc.NewClient(fakeConfig, bc.WithAuthClient(&fakeTokenGetter{}), bc.WithHTTPClient(server.HTTPClient()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	snap, err := client.Snapshot(context.Background(), []bc.EntitySpec{
+		{EntitySetName: "customers", ListOptions: bc.ListOptions{MaxPageSize: 2, OrderBy: []string{"number"}}},
+		{Name: "allItems", EntitySetName: "items"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if names := snap.Names(); !slices.Equal(names, []string{"allItems", "customers"}) {
+		t.Errorf("unexpected names %v", names)
+	}
+	if snap.Len("customers") != 5 {
+		t.Errorf("wanted every page of 5 customers, got %d", snap.Len("customers"))
+	}
+
+	items, err := bc.SnapshotRecords[fakeEntity](snap, "allItems")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 3 || items[0].Number != "I0" {
+		t.Errorf("unexpected items %+v", items)
+	}
+
+	if _, err := bc.SnapshotRecords[fakeEntity](snap, "vendors"); err == nil {
+		t.Error("wanted error for an entity not in the snapshot")
+	}
+}
+
+func TestSnapshotError(t *testing.T) {
+	client, err := bc.NewClient(fakeConfig, bc.WithAuthClient(&fakeTokenGetter{}), bc.WithHTTPClient(&http.Client{
+		Transport: bc.RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			if strings.Contains(r.URL.Path, "items") {
+				return errorResponse(http.StatusBadRequest, "BadRequest"), nil
+			}
+			<-r.Context().Done()
+			return nil, r.Context().Err()
+		}),
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = client.Snapshot(context.Background(), []bc.EntitySpec{
+		{EntitySetName: "customers"},
+		{EntitySetName: "items"},
+	})
+	if err == nil || !strings.HasPrefix(err.Error(), "snapshot items:") {
+		t.Errorf("wanted the items error to cancel customers, got %v", err)
+	}
+}
+
+func TestSnapshotDuplicate(t *testing.T) {
+	client := newSequenceClient(t, &bctest.SequenceTransport{})
+
+	_, err := client.Snapshot(context.Background(), []bc.EntitySpec{
+		{EntitySetName: "items"},
+		{Name: "items", EntitySetName: "itemsV2"},
+	})
+	if err == nil {
+		t.Error("wanted error for duplicate names")
+	}
+}
@@ -0,0 +1,182 @@
+package bc
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// UnknownFieldsError is returned by [DecodeStrict] when the response contains
+// fields that are not represented in the target type.
+// Fields are JSON paths, e.g. "value[0].newField".
+type UnknownFieldsError struct {
+	Type   string
+	Fields []string
+}
+
+func (err UnknownFieldsError) Error() string {
+	return fmt.Sprintf("unknown fields in %s: %s", err.Type, strings.Join(err.Fields, ", "))
+}
+
+// DecodeStrict is the same as [Decode] but also checks the response body for fields
+// that are not represented in T, returning an [UnknownFieldsError] if any exist.
+// OData control annotations ("@odata.*") are ignored.
+// Use it in tests to catch model drift after a BC upgrade or AL change.
+func DecodeStrict[T Validator](r *http.Response) (T, error) {
+	defer r.Body.Close()
+
+	var data T
+
+	// If error status call decodeErrorResponse() to return an error
+	if r.StatusCode < 200 || r.StatusCode >= 300 {
+		err := decodeErrorResponse(r)
+		return data, err
+	}
+
+	b, err := io.ReadAll(r.Body)
+	if err != nil {
+		return data, fmt.Errorf("failed to read Response.Body: %w", err)
+	}
+
+	if err := json.Unmarshal(b, &data); err != nil {
+		return data, fmt.Errorf("could not decode %T: %w", data, err)
+	}
+
+	var raw any
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return data, fmt.Errorf("could not decode %T: %w", data, err)
+	}
+
+	if fields := unknownFields(raw, reflect.TypeOf(data), ""); len(fields) > 0 {
+		slices.Sort(fields)
+		return data, UnknownFieldsError{Type: fmt.Sprintf("%T", data), Fields: fields}
+	}
+
+	if err := data.Validate(); err != nil {
+		return data, fmt.Errorf("failed validation of %T: %w", data, err)
+	}
+
+	return data, nil
+}
+
+// decode calls DecodeStrict if the client has strict decoding enabled,
+// otherwise Decode, or the client's codec if it is not the default.
+func decode[T Validator](c *Client, r *http.Response) (T, error) {
+	if c.strictDecoding {
+		return DecodeStrict[T](r)
+	}
+	if _, ok := c.codec.(JSONCodec); ok || c.codec == nil {
+		return Decode[T](r)
+	}
+	return decodeCodec[T](c.codec, r)
+}
+
+// unknownFields walks the raw JSON value alongside the type t and returns the
+// paths of object keys that have no matching struct field.
+func unknownFields(raw any, t reflect.Type, path string) []string {
+	if t == nil {
+		return nil
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	// Types with custom unmarshaling own their representation.
+	if reflect.PointerTo(t).Implements(reflect.TypeFor[json.Unmarshaler]()) {
+		return nil
+	}
+
+	var unknown []string
+
+	switch v := raw.(type) {
+	case map[string]any:
+		if t.Kind() != reflect.Struct {
+			return nil
+		}
+		fields := jsonFields(t)
+		for key, val := range v {
+			if strings.Contains(key, "@odata.") {
+				continue
+			}
+			ft, ok := lookupField(fields, key)
+			if !ok {
+				unknown = append(unknown, joinPath(path, key))
+				continue
+			}
+			unknown = append(unknown, unknownFields(val, ft, joinPath(path, key))...)
+		}
+	case []any:
+		if t.Kind() != reflect.Slice && t.Kind() != reflect.Array {
+			return nil
+		}
+		for i, val := range v {
+			unknown = append(unknown, unknownFields(val, t.Elem(), fmt.Sprintf("%s[%d]", path, i))...)
+		}
+	}
+
+	return unknown
+}
+
+// jsonFields returns the JSON names of the struct fields of t, including
+// promoted fields of embedded structs.
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	fields := map[string]reflect.Type{}
+
+	for i := range t.NumField() {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				for k, v := range jsonFields(ft) {
+					if _, exists := fields[k]; !exists {
+						fields[k] = v
+					}
+				}
+				continue
+			}
+		}
+
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields[name] = f.Type
+	}
+	return fields
+}
+
+// lookupField matches like encoding/json, preferring an exact match and
+// falling back to a case-insensitive one.
+func lookupField(fields map[string]reflect.Type, key string) (reflect.Type, bool) {
+	if t, ok := fields[key]; ok {
+		return t, true
+	}
+	for name, t := range fields {
+		if strings.EqualFold(name, key) {
+			return t, true
+		}
+	}
+	return nil, false
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
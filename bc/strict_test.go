@@ -0,0 +1,83 @@
+package bc_test
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/erlorenz/bc-go/internal/bctest"
+)
+
+type strictLine struct {
+	LineNo int `json:"lineNo"`
+}
+
+type strictEntity struct {
+	ID     string       `json:"id"`
+	Number string       `json:"number"`
+	Date   bc.Date      `json:"date"`
+	Lines  []strictLine `json:"lines"`
+}
+
+func (s strictEntity) Validate() error { return nil }
+
+func TestDecodeStrict(t *testing.T) {
+	body := map[string]any{
+		"@odata.etag": "W/\"etag\"",
+		"id":          validGUID,
+		"number":      "1000",
+		"date":        "2024-02-20",
+		"newField":    true,
+		"lines": []map[string]any{
+			{"lineNo": 1},
+			{"lineNo": 2, "newLineField": "x"},
+		},
+	}
+
+	res := &http.Response{StatusCode: 200, Body: bctest.NewRequestBody(body)}
+
+	_, err := bc.DecodeStrict[strictEntity](res)
+
+	var unknownErr bc.UnknownFieldsError
+	if !errors.As(err, &unknownErr) {
+		t.Fatalf("expected UnknownFieldsError, got %v", err)
+	}
+
+	want := []string{"lines[1].newLineField", "newField"}
+	if !slices.Equal(unknownErr.Fields, want) {
+		t.Errorf("wanted %v, got %v", want, unknownErr.Fields)
+	}
+}
+
+func TestDecodeStrictList(t *testing.T) {
+	body := map[string]any{
+		"value": []map[string]any{
+			{"id": validGUID, "number": "1000", "extra": 1},
+		},
+	}
+
+	res := &http.Response{StatusCode: 200, Body: bctest.NewRequestBody(body)}
+
+	_, err := bc.DecodeStrict[bc.APIListResponse[strictEntity]](res)
+
+	var unknownErr bc.UnknownFieldsError
+	if !errors.As(err, &unknownErr) {
+		t.Fatalf("expected UnknownFieldsError, got %v", err)
+	}
+
+	if !slices.Equal(unknownErr.Fields, []string{"value[0].extra"}) {
+		t.Errorf("unexpected fields %v", unknownErr.Fields)
+	}
+}
+
+func TestDecodeStrictNoUnknown(t *testing.T) {
+	body := map[string]any{"id": validGUID, "number": "1000"}
+
+	res := &http.Response{StatusCode: 200, Body: bctest.NewRequestBody(body)}
+
+	if _, err := bc.DecodeStrict[strictEntity](res); err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+}
@@ -2,8 +2,11 @@ package bc
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"strings"
+	"time"
 
 	"github.com/AzureAD/microsoft-authentication-library-for-go/apps/confidential"
 )
@@ -14,8 +17,15 @@ type Auth struct {
 	client confidential.Client
 	scopes []string
 	logger *slog.Logger
+	clock  Clock
+
+	cache    TokenCache
+	cacheKey string
 }
 
+// tokenExpiryMargin is how long before it expires a cached token is no longer used.
+const tokenExpiryMargin = time.Minute
+
 // AccessToken is used in the Authorization header of requests.
 type AccessToken string
 
@@ -25,8 +35,22 @@ type TokenGetter interface {
 	GetToken(context.Context) (AccessToken, error)
 }
 
+type tokenGetterKey struct{}
+
+// WithTokenGetter returns a context that makes every request created with it use tg
+// instead of the client's auth, including requests made by [APIPage] methods.
+func WithTokenGetter(ctx context.Context, tg TokenGetter) context.Context {
+	return context.WithValue(ctx, tokenGetterKey{}, tg)
+}
+
+// TokenGetterFrom returns the TokenGetter set with [WithTokenGetter] or nil.
+func TokenGetterFrom(ctx context.Context) TokenGetter {
+	tg, _ := ctx.Value(tokenGetterKey{}).(TokenGetter)
+	return tg
+}
+
 // NewAuth validates the AuthParams and creates a new AuthClient.
-func NewAuth(tenantID, clientID, clientSecret string) (*Auth, error) {
+func NewAuth(tenantID, clientID, clientSecret string, opts ...AuthOption) (*Auth, error) {
 
 	cred, err := confidential.NewCredFromSecret(clientSecret)
 	if err != nil {
@@ -34,28 +58,48 @@ func NewAuth(tenantID, clientID, clientSecret string) (*Auth, error) {
 		return nil, err
 	}
 
-	authority := "https://login.microsoft.com/" + string(tenantID)
+	return newAuth(tenantID, clientID, cred, opts...)
+}
 
-	confidentialClient, err := confidential.New(authority, string(clientID), cred)
+// newAuth creates the confidential client for the credential.
+func newAuth(tenantID, clientID string, cred confidential.Credential, opts ...AuthOption) (*Auth, error) {
+	settings := newAuthSettings(tenantID, opts)
+
+	var clientOpts []confidential.Option
+	if settings.httpClient != nil {
+		clientOpts = append(clientOpts, confidential.WithHTTPClient(settings.httpClient))
+	}
+
+	confidentialClient, err := confidential.New(settings.authority(), string(clientID), cred, clientOpts...)
 	if err != nil {
 		err = fmt.Errorf("authclient confidentialClient: %w", err)
 		return nil, err
 	}
 
-	// Don't think there is any reason to use a different one.
-	// Can have this as a config param if ever need to.
-	scopes := []string{"https://api.businesscentral.dynamics.com/.default"}
-
 	return &Auth{
-		client: confidentialClient,
-		scopes: scopes,
-		logger: slog.Default(),
+		client:   confidentialClient,
+		scopes:   []string{settings.scope},
+		logger:   slog.Default(),
+		clock:    settings.clock,
+		cache:    settings.cache,
+		cacheKey: strings.Join([]string{settings.authority(), clientID, settings.scope}, "|"),
 	}, nil
 
 }
 
 func (ac *Auth) GetToken(ctx context.Context) (AccessToken, error) {
 
+	if ac.cache != nil {
+		token, expiresOn, err := ac.cache.Get(ctx, ac.cacheKey)
+		switch {
+		case err == nil && expiresOn.Sub(ac.clock.Now()) > tokenExpiryMargin:
+			ac.logger.Debug("Using token from cache.")
+			return token, nil
+		case err != nil && !errors.Is(err, ErrTokenCacheMiss):
+			ac.logger.Warn("Failed to get token from cache.", "error", err)
+		}
+	}
+
 	ac.logger.Debug("Acquiring token...")
 	result, err := ac.client.AcquireTokenSilent(ctx, ac.scopes)
 	if err != nil {
@@ -68,5 +112,12 @@ func (ac *Auth) GetToken(ctx context.Context) (AccessToken, error) {
 		}
 	}
 	ac.logger.Debug("Successfully acquired token.")
+
+	if ac.cache != nil {
+		if err := ac.cache.Set(ctx, ac.cacheKey, AccessToken(result.AccessToken), result.ExpiresOn); err != nil {
+			ac.logger.Warn("Failed to save token to cache.", "error", err)
+		}
+	}
+
 	return AccessToken(result.AccessToken), nil
 }
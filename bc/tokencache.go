@@ -0,0 +1,59 @@
+package bc
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrTokenCacheMiss is returned by a [TokenCache] when there is no token for the key.
+var ErrTokenCacheMiss = errors.New("token not in cache")
+
+// TokenCache stores access tokens outside the process, so horizontally scaled services
+// share a token instead of each requesting one from Entra ID.
+// Set it with [WithAuthTokenCache] or [WithTokenCache].
+type TokenCache interface {
+	// Get returns ErrTokenCacheMiss if there is no token for the key or it has expired.
+	Get(ctx context.Context, key string) (AccessToken, time.Time, error)
+	// Set stores the token until it expires.
+	Set(ctx context.Context, key string, token AccessToken, expiresOn time.Time) error
+}
+
+// MemoryTokenCache is an in-process [TokenCache], mainly for tests.
+type MemoryTokenCache struct {
+	// Clock decides if a token has expired. Defaults to the [SystemClock].
+	Clock Clock
+
+	mu     sync.Mutex
+	tokens map[string]cachedToken
+}
+
+type cachedToken struct {
+	token     AccessToken
+	expiresOn time.Time
+}
+
+// Get implements [TokenCache].
+func (m *MemoryTokenCache) Get(_ context.Context, key string) (AccessToken, time.Time, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ct, ok := m.tokens[key]
+	if !ok || !clockOrSystem(m.Clock).Now().Before(ct.expiresOn) {
+		return "", time.Time{}, ErrTokenCacheMiss
+	}
+	return ct.token, ct.expiresOn, nil
+}
+
+// Set implements [TokenCache].
+func (m *MemoryTokenCache) Set(_ context.Context, key string, token AccessToken, expiresOn time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.tokens == nil {
+		m.tokens = map[string]cachedToken{}
+	}
+	m.tokens[key] = cachedToken{token: token, expiresOn: expiresOn}
+	return nil
+}
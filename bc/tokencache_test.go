@@ -0,0 +1,59 @@
+package bc_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/erlorenz/bc-go/bc"
+)
+
+func TestMemoryTokenCache(t *testing.T) {
+	ctx := context.Background()
+	cache := &bc.MemoryTokenCache{}
+
+	if _, _, err := cache.Get(ctx, "a"); !errors.Is(err, bc.ErrTokenCacheMiss) {
+		t.Errorf("wanted ErrTokenCacheMiss, got %v", err)
+	}
+
+	cache.Set(ctx, "a", "TOKEN", time.Now().Add(time.Hour))
+	cache.Set(ctx, "b", "EXPIRED", time.Now().Add(-time.Minute))
+
+	if token, _, err := cache.Get(ctx, "a"); err != nil || token != "TOKEN" {
+		t.Errorf("wanted TOKEN, got %s %v", token, err)
+	}
+	if _, _, err := cache.Get(ctx, "b"); !errors.Is(err, bc.ErrTokenCacheMiss) {
+		t.Errorf("wanted ErrTokenCacheMiss for expired token, got %v", err)
+	}
+}
+
+// recordingCache returns the same token for any key.
+type recordingCache struct {
+	bc.MemoryTokenCache
+	keys []string
+}
+
+func (rc *recordingCache) Get(ctx context.Context, key string) (bc.AccessToken, time.Time, error) {
+	rc.keys = append(rc.keys, key)
+	return "CACHED", time.Now().Add(time.Hour), nil
+}
+
+func TestAuthTokenCache(t *testing.T) {
+	cache := &recordingCache{}
+	auth, err := bc.NewAuth(validGUID, validGUID, "SECRET", bc.WithAuthTokenCache(cache))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token, err := auth.GetToken(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token != "CACHED" {
+		t.Errorf("wanted token from cache, got %s", token)
+	}
+	if len(cache.keys) != 1 {
+		t.Errorf("wanted 1 cache lookup, got %d", len(cache.keys))
+	}
+}
@@ -0,0 +1,139 @@
+package bc
+
+import (
+	"crypto/tls"
+	"log/slog"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// Timings are the connection-level timings of a request attempt from
+// net/http/httptrace, to tell BC slowness apart from network slowness.
+// DNS, Connect and TLS are zero when a kept-alive connection is reused.
+type Timings struct {
+	DNS     time.Duration
+	Connect time.Duration
+	TLS     time.Duration
+	// Wait is from the request being written until the first response byte, the time
+	// spent in BC plus one round trip.
+	Wait time.Duration
+	// TTFB is from the start of the attempt until the first response byte.
+	TTFB time.Duration
+	// Total is from the start of the attempt until the response headers were read,
+	// or the attempt failed.
+	Total time.Duration
+	// Reused is true if the request was sent on a kept-alive connection.
+	Reused bool
+}
+
+// LogValue implements [slog.LogValuer].
+func (t Timings) LogValue() slog.Value {
+	attrs := []slog.Attr{slog.Duration("ttfb", t.TTFB), slog.Duration("wait", t.Wait), slog.Duration("total", t.Total)}
+	if t.Reused {
+		attrs = append(attrs, slog.Bool("reused", true))
+	} else {
+		attrs = append(attrs, slog.Duration("dns", t.DNS), slog.Duration("connect", t.Connect), slog.Duration("tls", t.TLS))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// TimingHandler is called with the [Timings] of each attempt, e.g. to record a metric
+// per phase. The response is nil if the attempt failed. Set it with [WithHTTPTrace].
+type TimingHandler func(r *http.Request, res *http.Response, t Timings)
+
+// tracer collects the times of the httptrace callbacks, which can run on other goroutines.
+type tracer struct {
+	mu                        sync.Mutex
+	start                     time.Time
+	dnsStart, dnsDone         time.Time
+	connectStart, connectDone time.Time
+	tlsStart, tlsDone         time.Time
+	wroteRequest, firstByte   time.Time
+	reused                    bool
+}
+
+func (tr *tracer) set(t *time.Time) func() {
+	return func() {
+		tr.mu.Lock()
+		defer tr.mu.Unlock()
+		*t = time.Now()
+	}
+}
+
+func (tr *tracer) clientTrace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) { tr.set(&tr.dnsStart)() },
+		DNSDone:  func(httptrace.DNSDoneInfo) { tr.set(&tr.dnsDone)() },
+		ConnectStart: func(string, string) {
+			// Only the first dial of parallel dials is measured.
+			tr.mu.Lock()
+			defer tr.mu.Unlock()
+			if tr.connectStart.IsZero() {
+				tr.connectStart = time.Now()
+			}
+		},
+		ConnectDone: func(_, _ string, err error) {
+			if err == nil {
+				tr.set(&tr.connectDone)()
+			}
+		},
+		TLSHandshakeStart: tr.set(&tr.tlsStart),
+		TLSHandshakeDone:  func(tls.ConnectionState, error) { tr.set(&tr.tlsDone)() },
+		GotConn: func(info httptrace.GotConnInfo) {
+			tr.mu.Lock()
+			defer tr.mu.Unlock()
+			tr.reused = info.Reused
+		},
+		WroteRequest:         func(httptrace.WroteRequestInfo) { tr.set(&tr.wroteRequest)() },
+		GotFirstResponseByte: tr.set(&tr.firstByte),
+	}
+}
+
+// since returns the time between start and end, or zero if either was not reached.
+func since(start, end time.Time) time.Duration {
+	if start.IsZero() || end.IsZero() {
+		return 0
+	}
+	return end.Sub(start)
+}
+
+func (tr *tracer) timings(end time.Time) Timings {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	return Timings{
+		DNS:     since(tr.dnsStart, tr.dnsDone),
+		Connect: since(tr.connectStart, tr.connectDone),
+		TLS:     since(tr.tlsStart, tr.tlsDone),
+		Wait:    since(tr.wroteRequest, tr.firstByte),
+		TTFB:    since(tr.start, tr.firstByte),
+		Total:   end.Sub(tr.start),
+		Reused:  tr.reused,
+	}
+}
+
+// traceMiddleware returns the [Middleware] of [WithHTTPTrace] and [WithSlowRequestHook].
+func (c *Client) traceMiddleware() Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			tr := &tracer{start: time.Now()}
+			res, err := next.RoundTrip(r.WithContext(httptrace.WithClientTrace(r.Context(), tr.clientTrace())))
+			t := tr.timings(time.Now())
+
+			status := 0
+			if err == nil {
+				status = res.StatusCode
+			}
+			c.logger.Debug("Request timings.", "method", r.Method, "url", r.URL.String(), "status", status, "timings", t)
+			if rec, ok := r.Context().Value(diagnosticsKey{}).(*DiagnosticsRecorder); ok {
+				rec.recordTimings(t)
+			}
+			if c.timingHandler != nil {
+				c.timingHandler(r, res, t)
+			}
+			c.reportSlow(r, res, err, t)
+			return res, err
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package bc_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/google/uuid"
+)
+
+func TestWithHTTPTrace(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"ID": "` + validGUID + `"}`))
+	}))
+	defer server.Close()
+	target, _ := url.Parse(server.URL)
+
+	// Send the requests for BC to the test server
+	transport := server.Client().Transport
+	httpClient := &http.Client{Transport: bc.RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		r.URL.Host = target.Host
+		return transport.RoundTrip(r)
+	})}
+
+	var timings []bc.Timings
+	client, err := bc.NewClient(fakeConfig, bc.WithAuthClient(fakeTokenGetter{}), bc.WithHTTPClient(httpClient),
+		bc.WithHTTPTrace(func(_ *http.Request, res *http.Response, tm bc.Timings) {
+			if res.StatusCode != http.StatusOK {
+				t.Errorf("unexpected status %d", res.StatusCode)
+			}
+			timings = append(timings, tm)
+		}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	page := bc.NewAPIPage[fakeEntity](client, "fakeEntities")
+
+	ctx, rec := bc.WithDiagnostics(context.Background())
+	for range 2 {
+		if _, err := page.Get(ctx, uuid.New(), bc.GetOptions{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(timings) != 2 {
+		t.Fatalf("wanted 2 timings, got %d", len(timings))
+	}
+	first, second := timings[0], timings[1]
+	if first.Reused || first.Connect <= 0 || first.TLS <= 0 || first.TTFB <= 0 || first.Total < first.TTFB {
+		t.Errorf("unexpected timings of a new connection %+v", first)
+	}
+	if !second.Reused || second.TLS != 0 || second.Wait <= 0 {
+		t.Errorf("unexpected timings of a reused connection %+v", second)
+	}
+	if got := rec.Diagnostics().Timings; got != second {
+		t.Errorf("wanted the last timings in the diagnostics, got %+v", got)
+	}
+}
@@ -0,0 +1,69 @@
+package bc
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/google/uuid"
+)
+
+// The functions in this file infer the entity set from the model type T, see
+// [EntitySetOf], so bc.List[models.Customer](ctx, client, opts) needs no entity set name.
+// They return an error if T has no entity set. GetRecord and DeleteRecord are named so
+// as [Get] and [Delete] build requests.
+
+// List calls [APIPage.List] on the entity set of T.
+func List[T Validator](ctx context.Context, c *Client, opts ListOptions) ([]T, error) {
+	page, err := pageOf[T](c)
+	if err != nil {
+		return nil, err
+	}
+	return page.List(ctx, opts)
+}
+
+// GetRecord calls [APIPage.Get] on the entity set of T.
+func GetRecord[T Validator](ctx context.Context, c *Client, id uuid.UUID, opts GetOptions) (T, error) {
+	page, err := pageOf[T](c)
+	if err != nil {
+		var v T
+		return v, err
+	}
+	return page.Get(ctx, id, opts)
+}
+
+// Create calls [APIPage.Create] on the entity set of T.
+func Create[T Validator](ctx context.Context, c *Client, body any, opts GetOptions) (T, error) {
+	page, err := pageOf[T](c)
+	if err != nil {
+		var v T
+		return v, err
+	}
+	return page.Create(ctx, body, opts)
+}
+
+// Update calls [APIPage.Update] on the entity set of T.
+func Update[T Validator](ctx context.Context, c *Client, id uuid.UUID, expand []string, body any) (T, error) {
+	page, err := pageOf[T](c)
+	if err != nil {
+		var v T
+		return v, err
+	}
+	return page.Update(ctx, id, expand, body)
+}
+
+// DeleteRecord calls [APIPage.Delete] on the entity set of T.
+func DeleteRecord[T Validator](ctx context.Context, c *Client, id uuid.UUID) error {
+	page, err := pageOf[T](c)
+	if err != nil {
+		return err
+	}
+	return page.Delete(ctx, id)
+}
+
+func pageOf[T Validator](c *Client) (*APIPage[T], error) {
+	info, err := entitySetOf(reflect.TypeFor[T]())
+	if err != nil {
+		return nil, err
+	}
+	return NewAPIPage[T](c, info.Name), nil
+}
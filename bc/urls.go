@@ -1,20 +1,28 @@
 package bc
 
 import (
+	"cmp"
 	"fmt"
 	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
 
 	"github.com/google/uuid"
 )
 
+// defaultAPIHost is the host of the API in the public cloud.
+const defaultAPIHost = "api.businesscentral.dynamics.com"
+
 // dksdlfjdsja
 // BuildBaseURL builds the BaseURL from the ClientConfig.
 // It uses the structure
-// "https://api.businesscentral.dynamics.com/v2.0/{tenantID}/{environment}/api/{APIendpoint}/companies({companyID})"
+// "https://{APIHost}/v2.0/{tenantID}/{environment}/api/{APIendpoint}/companies({companyID})"
+// with the APIHost defaulting to "api.businesscentral.dynamics.com".
 func BuildBaseURL(cfg ClientConfig) (*url.URL, error) {
 
 	// All BC APIs use this same prefix.
-	staticPrefix := "https://api.businesscentral.dynamics.com/v2.0"
+	staticPrefix := "https://" + cmp.Or(cfg.APIHost, defaultAPIHost) + "/v2.0"
 
 	// Specific to this Client
 	dynamicURL := fmt.Sprintf("/%s/%s/api/%s/companies(%s)", cfg.TenantID, cfg.Environment, cfg.APIEndpoint, cfg.CompanyID)
@@ -35,26 +43,112 @@ func BuildBaseURL(cfg ClientConfig) (*url.URL, error) {
 func BuildRequestURL(baseURL url.URL, entitySet string, recordID uuid.UUID, queryParams QueryParams) url.URL {
 
 	newURL := baseURL
-	// Don't forget the slash in between
-	entitySetPath := "/" + entitySet
-	newURL.Path += entitySetPath
+	// Don't forget the slash in between, and add recordID if exists
+	if recordID != uuid.Nil {
+		newURL.Path += "/" + entitySet + "(" + recordID.String() + ")"
+	} else {
+		newURL.Path += "/" + entitySet
+	}
+
+	// Build query params, empty values are skipped
+	newURL.RawQuery = queryParams.Encode()
+
+	return newURL
+}
+
+// maxCachedPrefixes bounds the [urlCache] for clients that build many different paths.
+const maxCachedPrefixes = 1024
 
-	// Add recordID if exists
+// urlCache caches the encoded URL up to the entity set of each entity set name,
+// so requests only encode the record ID and query params. It is shared by the copies
+// of a Client from [Client.DryRun] and [Client.ReadOnly], which have the same baseURL.
+type urlCache struct {
+	prefixes sync.Map
+	size     atomic.Int64
+}
+
+// entitySetPrefix returns the encoded URL of the entity set, the same as
+// BuildRequestURL with no record ID and query params.
+func (c *Client) entitySetPrefix(entitySet string) string {
+	if c.urls != nil {
+		if prefix, ok := c.urls.prefixes.Load(entitySet); ok {
+			return prefix.(string)
+		}
+	}
+
+	u := BuildRequestURL(*c.baseURL, entitySet, uuid.Nil, nil)
+	prefix := u.String()
+
+	// Paths with a record ID like media and actions are not worth caching
+	if c.urls != nil && !strings.ContainsAny(entitySet, "(/") && c.urls.size.Load() < maxCachedPrefixes {
+		if _, loaded := c.urls.prefixes.LoadOrStore(entitySet, prefix); !loaded {
+			c.urls.size.Add(1)
+		}
+	}
+	return prefix
+}
+
+// requestURL returns the same URL as BuildRequestURL as a string, using the cached
+// entity set prefix and usually a single allocation for the rest.
+func (c *Client) requestURL(entitySet string, recordID uuid.UUID, queryParams QueryParams) string {
+	prefix := c.entitySetPrefix(entitySet)
+
+	var b strings.Builder
+	b.Grow(len(prefix) + encodedRecordIDLen + 1 + queryParams.encodedLen())
+	b.WriteString(prefix)
 	if recordID != uuid.Nil {
-		newURL.Path += fmt.Sprintf("(%s)", recordID)
+		writeRecordID(&b, recordID)
 	}
+	queryParams.writeEncoded(&b, "?")
+	return b.String()
+}
 
-	// Build query params, skip if empty
-	query := url.Values{}
-	for k, v := range queryParams {
-		if v == "" {
-			continue
+// encodedRecordIDLen is the length of a record ID segment with the escaped parentheses.
+const encodedRecordIDLen = len("%28") + 36 + len("%29")
+
+// writeRecordID writes the record ID segment escaped the way url.URL escapes the path.
+func writeRecordID(b *strings.Builder, recordID uuid.UUID) {
+	var id [36]byte
+	encodeUUID(id[:], recordID)
+	b.WriteString("%28")
+	b.Write(id[:])
+	b.WriteString("%29")
+}
+
+// encodeUUID writes the canonical form of the UUID to dst without allocating.
+func encodeUUID(dst []byte, id uuid.UUID) {
+	const hex = "0123456789abcdef"
+	j := 0
+	for i, c := range id {
+		if i == 4 || i == 6 || i == 8 || i == 10 {
+			dst[j] = '-'
+			j++
 		}
-		query.Set(k, v)
+		dst[j] = hex[c>>4]
+		dst[j+1] = hex[c&15]
+		j += 2
 	}
-	newURL.RawQuery = query.Encode()
+}
 
-	return newURL
+// SplitEntityPath returns the entity set name and record ID of a request path,
+// using the last entity set after the "companies(...)" segment. Bound actions
+// like "Microsoft.NAV.post" are skipped. RecordID is empty if there is none.
+func SplitEntityPath(path string) (entitySetName string, recordID string) {
+	_, rest, found := strings.Cut(path, "/companies(")
+	if !found {
+		return "", ""
+	}
+
+	segments := strings.Split(rest, "/")
+	for i := len(segments) - 1; i >= 1; i-- {
+		seg := segments[i]
+		if seg == "" || strings.HasPrefix(seg, "Microsoft.NAV.") {
+			continue
+		}
+		name, id, _ := strings.Cut(seg, "(")
+		return name, strings.TrimSuffix(id, ")")
+	}
+	return "", ""
 }
 
 // const pathIndexTenant = 2
@@ -1,10 +1,13 @@
 package bc_test
 
 import (
+	"context"
 	"fmt"
+	"net/http"
 	"testing"
 
 	"github.com/erlorenz/bc-go/bc"
+	"github.com/google/uuid"
 )
 
 func TestBuildBaseURLCommon(t *testing.T) {
@@ -63,3 +66,44 @@ func TestBuildBaseURLExt(t *testing.T) {
 		}
 	})
 }
+
+func TestNewRequestURLCached(t *testing.T) {
+	client, err := bc.NewClient(fakeConfig, bc.WithAuthClient(&fakeTokenGetter{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	base, err := bc.BuildBaseURL(fakeConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		opts bc.RequestOptions
+	}{
+		{"EntitySet", bc.RequestOptions{Method: http.MethodGet, EntitySetName: "fakeEntities"}},
+		{"RecordID", bc.RequestOptions{Method: http.MethodGet, EntitySetName: "fakeEntities", RecordID: uuid.MustParse(validGUID)}},
+		{"QueryParams", bc.RequestOptions{Method: http.MethodGet, EntitySetName: "fakeEntities", QueryParams: bc.QueryParams{
+			{Key: "$filter", Value: "displayName eq 'A & B; C'"},
+			{Key: "$top", Value: ""},
+			{Key: "$expand", Value: "lines($select=id;$top=2)"},
+		}}},
+		{"Path", bc.RequestOptions{Method: http.MethodPost, EntitySetName: "fakeEntities(" + validGUID + ")/Microsoft.NAV.post"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := bc.BuildRequestURL(*base, tt.opts.EntitySetName, tt.opts.RecordID, tt.opts.QueryParams)
+			// The second request uses the cached prefix
+			for range 2 {
+				req, err := client.NewRequest(context.Background(), tt.opts)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if req.URL.String() != want.String() {
+					t.Errorf("wanted %s, got %s", want.String(), req.URL.String())
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package bc
+
+import (
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// modulePath is the path of this module in the build info.
+const modulePath = "github.com/erlorenz/bc-go"
+
+// version is used when the build info has no version for the module,
+// e.g. in its own tests or when built from a checkout. Bump it on each release.
+const version = "0.14.0"
+
+// ErrVersionTooOld is returned by [RequireMinVersion] when the library is older than required.
+var ErrVersionTooOld = errors.New("bcgo version too old")
+
+// BuildInfo describes the build of the library.
+type BuildInfo struct {
+	// Version is the module version without the "v" prefix, e.g. "0.14.0".
+	Version string
+	// Sum is the checksum of the module from go.sum, empty when built from source.
+	Sum string
+	// GoVersion is the version of the Go toolchain that built the binary.
+	GoVersion string
+}
+
+var buildInfo = sync.OnceValue(func() BuildInfo {
+	bi := BuildInfo{Version: version}
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return bi
+	}
+	bi.GoVersion = info.GoVersion
+	mods := append([]*debug.Module{&info.Main}, info.Deps...)
+	for _, m := range mods {
+		if m.Replace != nil {
+			m = m.Replace
+		}
+		if m.Path != modulePath || m.Version == "" || m.Version == "(devel)" {
+			continue
+		}
+		bi.Version = strings.TrimPrefix(m.Version, "v")
+		bi.Sum = m.Sum
+		break
+	}
+	return bi
+})
+
+// Version returns the version of the library that the binary was built with.
+// It is sent in the User-Agent header and logged when a [Client] is created.
+func Version() string {
+	return buildInfo().Version
+}
+
+// ReadBuildInfo returns the [BuildInfo] of the library.
+func ReadBuildInfo() BuildInfo {
+	return buildInfo()
+}
+
+// RequireMinVersion returns an error if the [Version] of the library is lower than
+// minimum, e.g. "0.14.0", so a tool that relies on newer client capabilities fails on
+// start instead of misbehaving. Pre-release and build suffixes are ignored.
+func RequireMinVersion(minimum string) error {
+	want, err := parseVersion(minimum)
+	if err != nil {
+		return fmt.Errorf("require version: %w", err)
+	}
+	have, err := parseVersion(Version())
+	if err != nil {
+		return fmt.Errorf("require version: %w", err)
+	}
+	for i := range want {
+		if have[i] != want[i] {
+			if have[i] < want[i] {
+				return fmt.Errorf("require version: %w: %s is older than %s", ErrVersionTooOld, Version(), minimum)
+			}
+			return nil
+		}
+	}
+	return nil
+}
+
+// parseVersion parses the major, minor and patch of a semantic version,
+// with an optional "v" prefix. Missing minor and patch are 0.
+func parseVersion(s string) ([3]int, error) {
+	var v [3]int
+	core := strings.TrimPrefix(s, "v")
+	if i := strings.IndexAny(core, "-+"); i >= 0 {
+		core = core[:i]
+	}
+	parts := strings.Split(core, ".")
+	if len(parts) > 3 {
+		return v, fmt.Errorf("invalid version %q", s)
+	}
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			return v, fmt.Errorf("invalid version %q", s)
+		}
+		v[i] = n
+	}
+	return v, nil
+}
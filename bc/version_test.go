@@ -0,0 +1,43 @@
+package bc_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/erlorenz/bc-go/bc"
+)
+
+func TestVersion(t *testing.T) {
+	if v := bc.Version(); v == "" || v[0] == 'v' {
+		t.Errorf("wanted a version without the v prefix, got %q", v)
+	}
+	if info := bc.ReadBuildInfo(); info.Version != bc.Version() || info.GoVersion == "" {
+		t.Errorf("unexpected build info %+v", info)
+	}
+}
+
+func TestRequireMinVersion(t *testing.T) {
+	table := []struct {
+		minimum string
+		tooOld  bool
+	}{
+		{"0.1.0", false},
+		{"v0.14", false},
+		{bc.Version(), false},
+		{"0.14.0-rc.1", false},
+		{"0.99.0", true},
+		{"v1", true},
+	}
+	for _, v := range table {
+		t.Run(v.minimum, func(t *testing.T) {
+			err := bc.RequireMinVersion(v.minimum)
+			if got := errors.Is(err, bc.ErrVersionTooOld); got != v.tooOld {
+				t.Errorf("wanted too old %t, got %v", v.tooOld, err)
+			}
+		})
+	}
+
+	if err := bc.RequireMinVersion("latest"); err == nil || errors.Is(err, bc.ErrVersionTooOld) {
+		t.Errorf("wanted an invalid version error, got %v", err)
+	}
+}
@@ -0,0 +1,95 @@
+package bc
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// WarningKind is the source of a [Warning].
+type WarningKind string
+
+const (
+	// WarningDeprecation is from a "Deprecation" response header.
+	WarningDeprecation WarningKind = "deprecation"
+	// WarningSunset is from a "Sunset" response header with the date the endpoint is removed.
+	WarningSunset WarningKind = "sunset"
+	// WarningHeader is from a "Warning" response header.
+	WarningHeader WarningKind = "warning"
+	// WarningMetadata is from a deprecated revision annotation in the $metadata, see [Client.Capabilities].
+	WarningMetadata WarningKind = "metadata"
+	// WarningOrdering is from [APIPage.ListPage] when a page has a nextLink but the request
+	// has no $orderby, so rows can be skipped or duplicated between pages. See [WithStableOrdering].
+	WarningOrdering WarningKind = "ordering"
+)
+
+// Warning is a deprecation notice from BC, so breaking changes can be found before
+// the endpoint or field is removed, or a [WarningOrdering] of a listing that can lose rows.
+type Warning struct {
+	Kind WarningKind
+	// Message is the header value or the description of the annotation.
+	Message string
+	// Sunset is the removal date from the "Sunset" header if it could be parsed.
+	Sunset time.Time
+	// Method and URL of the request. Empty for WarningMetadata.
+	Method string
+	URL    string
+	// EntitySetName is the deprecated entity set, and Field the deprecated field if any.
+	EntitySetName string
+	Field         string
+}
+
+// WarningHandler is called for each [Warning], e.g. to record a metric.
+// Set it with [WithWarningHandler].
+type WarningHandler func(ctx context.Context, w Warning)
+
+// warnings reports each [Warning] to the handler, and logs the first one of each kind
+// for an entity set so a deprecated endpoint does not flood the logs.
+type warnings struct {
+	client *Client
+	logged sync.Map
+}
+
+func (ws *warnings) report(ctx context.Context, w Warning) {
+	key := string(w.Kind) + "|" + w.EntitySetName + "|" + w.Field
+	if _, loaded := ws.logged.LoadOrStore(key, true); !loaded {
+		ws.client.logger.Warn("Business Central warning.", "kind", w.Kind, "message", w.Message,
+			"entitySetName", w.EntitySetName, "field", w.Field, "url", w.URL)
+	}
+	if ws.client.warningHandler != nil {
+		ws.client.warningHandler(ctx, w)
+	}
+}
+
+// checkResponse reports the deprecation headers of the response.
+func (ws *warnings) checkResponse(r *http.Request, res *http.Response) {
+	for _, w := range headerWarnings(res.Header) {
+		w.Method, w.URL = r.Method, r.URL.String()
+		w.EntitySetName, _ = SplitEntityPath(r.URL.Path)
+		ws.report(r.Context(), w)
+	}
+}
+
+// headerWarnings returns a Warning for each Deprecation, Sunset and Warning header.
+func headerWarnings(h http.Header) []Warning {
+	var ws []Warning
+	for _, v := range h.Values("Deprecation") {
+		ws = append(ws, Warning{Kind: WarningDeprecation, Message: v})
+	}
+	for _, v := range h.Values("Sunset") {
+		w := Warning{Kind: WarningSunset, Message: v}
+		if t, err := http.ParseTime(v); err == nil {
+			w.Sunset = t
+		}
+		ws = append(ws, w)
+	}
+	for _, v := range h.Values("Warning") {
+		// Only 299 "Miscellaneous persistent warning" is used for deprecations
+		if strings.HasPrefix(v, "299 ") {
+			ws = append(ws, Warning{Kind: WarningHeader, Message: v})
+		}
+	}
+	return ws
+}
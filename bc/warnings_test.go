@@ -0,0 +1,112 @@
+package bc_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/erlorenz/bc-go/internal/bctest"
+	"github.com/google/uuid"
+)
+
+func TestWarningHandlerHeaders(t *testing.T) {
+	res := bctest.NewResponse(200, map[string]any{"ID": validGUID})
+	res.Header.Set("Deprecation", "@1735689600")
+	res.Header.Set("Sunset", "Wed, 01 Oct 2025 00:00:00 GMT")
+	res.Header.Add("Warning", `299 - "The API version is deprecated"`)
+	res.Header.Add("Warning", `110 - "Response is stale"`)
+
+	var got []bc.Warning
+	st := &bctest.SequenceTransport{Responses: []*http.Response{res}}
+	client := newSequenceClient(t, st, bc.WithWarningHandler(func(ctx context.Context, w bc.Warning) {
+		got = append(got, w)
+	}))
+
+	page := bc.NewAPIPage[fakeEntity](client, "fakeEntities")
+	if _, err := page.Get(context.Background(), uuid.New(), bc.GetOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(got) != 3 {
+		t.Fatalf("wanted 3 warnings, got %d: %v", len(got), got)
+	}
+	if got[0].Kind != bc.WarningDeprecation || got[0].EntitySetName != "fakeEntities" || got[0].Method != http.MethodGet {
+		t.Errorf("unexpected deprecation warning %+v", got[0])
+	}
+	if got[1].Kind != bc.WarningSunset || !got[1].Sunset.Equal(time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected sunset warning %+v", got[1])
+	}
+	if got[2].Kind != bc.WarningHeader || !strings.Contains(got[2].Message, "deprecated") {
+		t.Errorf("unexpected warning header %+v", got[2])
+	}
+}
+
+const deprecatedMetadataXML = `<?xml version="1.0" encoding="utf-8"?>
+<edmx:Edmx Version="4.0" xmlns:edmx="http://docs.oasis-open.org/odata/ns/edmx">
+  <edmx:DataServices>
+    <Schema Namespace="Microsoft.NAV" xmlns="http://docs.oasis-open.org/odata/ns/edm">
+      <EntityType Name="customer">
+        <Property Name="id" Type="Edm.Guid" Nullable="false" />
+        <Property Name="taxLiable" Type="Edm.Boolean">
+          <Annotation Term="Org.OData.Core.V1.Revisions">
+            <Collection>
+              <Record>
+                <PropertyValue Property="Kind" EnumMember="Org.OData.Core.V1.RevisionKind/Deprecated" />
+                <PropertyValue Property="Description" String="Use taxAreaId" />
+              </Record>
+            </Collection>
+          </Annotation>
+        </Property>
+      </EntityType>
+      <EntityType Name="oldEntity">
+        <Property Name="id" Type="Edm.Guid" Nullable="false" />
+      </EntityType>
+      <EntityContainer Name="default">
+        <EntitySet Name="customers" EntityType="Microsoft.NAV.customer" />
+        <EntitySet Name="oldEntities" EntityType="Microsoft.NAV.oldEntity" />
+      </EntityContainer>
+      <Annotations Target="Microsoft.NAV.oldEntity">
+        <Annotation Term="Org.OData.Core.V1.Revisions">
+          <Collection>
+            <Record>
+              <PropertyValue Property="Kind" EnumMember="Org.OData.Core.V1.RevisionKind/Deprecated" />
+              <PropertyValue Property="Description" String="Removed in the next version" />
+            </Record>
+          </Collection>
+        </Annotation>
+      </Annotations>
+    </Schema>
+  </edmx:DataServices>
+</edmx:Edmx>`
+
+func TestWarningHandlerMetadata(t *testing.T) {
+	res := &http.Response{StatusCode: 200, Body: io.NopCloser(strings.NewReader(deprecatedMetadataXML)), Header: http.Header{}}
+	st := &bctest.SequenceTransport{Responses: []*http.Response{res}}
+
+	var got []bc.Warning
+	client := newSequenceClient(t, st, bc.WithWarningHandler(func(ctx context.Context, w bc.Warning) {
+		got = append(got, w)
+	}))
+
+	caps, err := client.Capabilities(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []bc.Warning{
+		{Kind: bc.WarningMetadata, Message: "Use taxAreaId", EntitySetName: "customers", Field: "taxLiable"},
+		{Kind: bc.WarningMetadata, Message: "Removed in the next version", EntitySetName: "oldEntities"},
+	}
+	if len(caps.Deprecated) != len(want) || len(got) != len(want) {
+		t.Fatalf("wanted %d warnings, got %v and handler %v", len(want), caps.Deprecated, got)
+	}
+	for i := range want {
+		if caps.Deprecated[i] != want[i] || got[i] != want[i] {
+			t.Errorf("wanted %+v, got %+v", want[i], caps.Deprecated[i])
+		}
+	}
+}
@@ -0,0 +1,228 @@
+package bcfake
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// Request is a request recorded by a [Recorder].
+type Request struct {
+	Method string
+	URL    *url.URL
+	Header http.Header
+	// EntitySetName and ID are parsed from the path. ID is uuid.Nil for a collection.
+	EntitySetName string
+	ID            uuid.UUID
+	// Body is the JSON body decoded into any with numbers as json.Number, or nil.
+	Body any
+	// Batch is true for a request of a $batch, which is recorded instead of the $batch.
+	Batch bool
+}
+
+// Recorder is an http.RoundTripper that records every request before sending it to
+// next, with assertions for behavioral tests of sync logic:
+//
+//	server := bcfake.New()
+//	rec := bcfake.NewRecorder(server)
+//	client, err := bc.NewClient(config, bc.WithHTTPClient(rec.HTTPClient()))
+//	...
+//	rec.AssertPatched(t, "customers", id, map[string]any{"displayName": "Adatum"})
+//
+// It is safe for concurrent use.
+type Recorder struct {
+	next     http.RoundTripper
+	mu       sync.Mutex
+	requests []Request
+}
+
+// NewRecorder returns a Recorder that sends the requests to next, e.g. a [Server].
+func NewRecorder(next http.RoundTripper) *Recorder {
+	return &Recorder{next: next}
+}
+
+// HTTPClient returns an http.Client that sends every request through the recorder.
+func (rec *Recorder) HTTPClient() *http.Client {
+	return &http.Client{Transport: rec}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (rec *Recorder) RoundTrip(r *http.Request) (*http.Response, error) {
+	var body []byte
+	if r.Body != nil && r.Body != http.NoBody {
+		var err error
+		body, err = io.ReadAll(r.Body)
+		r.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	rec.mu.Lock()
+	if strings.HasSuffix(r.URL.Path, "/$batch") {
+		rec.requests = append(rec.requests, batchRequests(r, body)...)
+	} else {
+		rec.requests = append(rec.requests, newRequest(r.Method, r.URL, r.Header.Clone(), body))
+	}
+	rec.mu.Unlock()
+	return rec.next.RoundTrip(r)
+}
+
+func newRequest(method string, u *url.URL, header http.Header, body []byte) Request {
+	req := Request{Method: method, URL: u, Header: header}
+	req.EntitySetName, req.ID, _ = parsePath(u.Path)
+	if len(body) > 0 {
+		d := json.NewDecoder(bytes.NewReader(body))
+		d.UseNumber()
+		if d.Decode(&req.Body) != nil {
+			req.Body = string(body)
+		}
+	}
+	return req
+}
+
+// batchRequests returns the requests of a JSON $batch, with the paths resolved like
+// [Server.ServeHTTP] does.
+func batchRequests(r *http.Request, body []byte) []Request {
+	var batch batchRequest
+	if err := json.Unmarshal(body, &batch); err != nil {
+		return []Request{newRequest(r.Method, r.URL, r.Header.Clone(), body)}
+	}
+	root := strings.TrimSuffix(r.URL.Path, "$batch")
+	requests := make([]Request, 0, len(batch.Requests))
+	for _, br := range batch.Requests {
+		u, err := url.Parse(br.URL)
+		if err != nil {
+			continue
+		}
+		resolved := *r.URL
+		resolved.Path, resolved.RawPath, resolved.RawQuery = root+u.Path, "", u.RawQuery
+		header := http.Header{}
+		for k, v := range br.Headers {
+			header.Set(k, v)
+		}
+		req := newRequest(br.Method, &resolved, header, br.Body)
+		req.Batch = true
+		requests = append(requests, req)
+	}
+	return requests
+}
+
+// Requests returns the recorded requests in order.
+func (rec *Recorder) Requests() []Request {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	return append([]Request(nil), rec.requests...)
+}
+
+// Reset forgets the recorded requests, e.g. after the setup of a test.
+func (rec *Recorder) Reset() {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	rec.requests = nil
+}
+
+// Find returns the recorded requests with the method to the entity set, and the id
+// unless it is uuid.Nil.
+func (rec *Recorder) Find(method, entitySetName string, id uuid.UUID) []Request {
+	var found []Request
+	for _, req := range rec.Requests() {
+		if req.Method == method && req.EntitySetName == entitySetName && (id == uuid.Nil || req.ID == id) {
+			found = append(found, req)
+		}
+	}
+	return found
+}
+
+// AssertCreated asserts that a record was created in the entity set with a body equal
+// to want encoded as JSON.
+func (rec *Recorder) AssertCreated(t testing.TB, entitySetName string, want any) {
+	t.Helper()
+	rec.assertBody(t, http.MethodPost, entitySetName, uuid.Nil, want)
+}
+
+// AssertPatched asserts that the record was updated with a body equal to want encoded
+// as JSON.
+func (rec *Recorder) AssertPatched(t testing.TB, entitySetName string, id uuid.UUID, want any) {
+	t.Helper()
+	rec.assertBody(t, http.MethodPatch, entitySetName, id, want)
+}
+
+// AssertDeleted asserts that the record was deleted.
+func (rec *Recorder) AssertDeleted(t testing.TB, entitySetName string, id uuid.UUID) {
+	t.Helper()
+	if len(rec.Find(http.MethodDelete, entitySetName, id)) == 0 {
+		t.Errorf("wanted %s(%s) deleted, got %s", entitySetName, id, rec.summary())
+	}
+}
+
+// AssertCount asserts the number of requests with the method to the entity set.
+func (rec *Recorder) AssertCount(t testing.TB, method, entitySetName string, want int) {
+	t.Helper()
+	if got := len(rec.Find(method, entitySetName, uuid.Nil)); got != want {
+		t.Errorf("wanted %d %s requests to %s, got %d: %s", want, method, entitySetName, got, rec.summary())
+	}
+}
+
+// AssertNoWrites asserts that no record was created, updated or deleted.
+func (rec *Recorder) AssertNoWrites(t testing.TB) {
+	t.Helper()
+	for _, req := range rec.Requests() {
+		if req.Method != http.MethodGet {
+			t.Errorf("wanted no writes, got %s", rec.summary())
+			return
+		}
+	}
+}
+
+func (rec *Recorder) assertBody(t testing.TB, method, entitySetName string, id uuid.UUID, want any) {
+	t.Helper()
+	b, err := json.Marshal(want)
+	if err != nil {
+		t.Fatalf("encode want: %s", err)
+	}
+	wantBody := newRequest(method, &url.URL{}, nil, b).Body
+
+	found := rec.Find(method, entitySetName, id)
+	for _, req := range found {
+		if reflect.DeepEqual(req.Body, wantBody) {
+			return
+		}
+	}
+	target := entitySetName
+	if id != uuid.Nil {
+		target += "(" + id.String() + ")"
+	}
+	if len(found) == 0 {
+		t.Errorf("wanted a %s to %s, got %s", method, target, rec.summary())
+		return
+	}
+	var bodies []string
+	for _, req := range found {
+		b, _ := json.Marshal(req.Body)
+		bodies = append(bodies, string(b))
+	}
+	t.Errorf("wanted a %s to %s with %s, got\n%s", method, target, b, strings.Join(bodies, "\n"))
+}
+
+// summary lists the recorded requests for a failure message.
+func (rec *Recorder) summary() string {
+	requests := rec.Requests()
+	if len(requests) == 0 {
+		return "no requests"
+	}
+	lines := make([]string, len(requests))
+	for i, req := range requests {
+		lines[i] = req.Method + " " + req.URL.Path
+	}
+	return "\n" + strings.Join(lines, "\n")
+}
@@ -0,0 +1,85 @@
+package bcfake_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/erlorenz/bc-go/bcfake"
+	"github.com/erlorenz/bc-go/internal/bctest"
+)
+
+// failures counts the failures of the assertions.
+type failures struct {
+	testing.TB
+	errors int
+}
+
+func (f *failures) Helper()                           {}
+func (f *failures) Errorf(format string, args ...any) { f.errors++ }
+
+func TestRecorder(t *testing.T) {
+	ctx := context.Background()
+	server := bcfake.New()
+	ids := server.Seed("customers", map[string]any{"displayName": "Adatum"}, map[string]any{"displayName": "Fabrikam"})
+	rec := bcfake.NewRecorder(server)
+	page := bc.NewAPIPage[customer](bctest.NewClient(t, rec), "customers")
+
+	if _, err := page.List(ctx, bc.ListOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	rec.AssertNoWrites(t)
+
+	if _, err := page.Update(ctx, ids[0], nil, map[string]any{"displayName": "Adatum Corporation", "balance": 1.5}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := page.Create(ctx, map[string]any{"displayName": "Litware"}, bc.GetOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := page.Delete(ctx, ids[1]); err != nil {
+		t.Fatal(err)
+	}
+
+	rec.AssertPatched(t, "customers", ids[0], map[string]any{"displayName": "Adatum Corporation", "balance": 1.5})
+	rec.AssertCreated(t, "customers", map[string]any{"displayName": "Litware"})
+	rec.AssertDeleted(t, "customers", ids[1])
+	rec.AssertCount(t, http.MethodGet, "customers", 1)
+
+	f := &failures{TB: t}
+	rec.AssertPatched(f, "customers", ids[0], map[string]any{"displayName": "Adatum"})
+	rec.AssertPatched(f, "customers", ids[1], map[string]any{"displayName": "Fabrikam"})
+	rec.AssertDeleted(f, "customers", ids[0])
+	rec.AssertCount(f, http.MethodPost, "customers", 2)
+	rec.AssertNoWrites(f)
+	if f.errors != 5 {
+		t.Errorf("wanted 5 failed assertions, got %d", f.errors)
+	}
+
+	rec.Reset()
+	if n := len(rec.Requests()); n != 0 {
+		t.Errorf("wanted no requests after Reset, got %d", n)
+	}
+}
+
+func TestRecorderBatch(t *testing.T) {
+	server := bcfake.New()
+	ids := server.Seed("customers", map[string]any{"displayName": "Adatum"})
+	rec := bcfake.NewRecorder(server)
+	client := bctest.NewClient(t, rec)
+
+	_, err := client.Batch(context.Background(), []bc.RequestOptions{
+		{Method: http.MethodPatch, EntitySetName: "customers", RecordID: ids[0], Body: map[string]any{"displayName": "Adatum Corporation"}},
+		{Method: http.MethodPost, EntitySetName: "customers", Body: map[string]any{"displayName": "Litware"}},
+	}, bc.BatchOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	requests := rec.Requests()
+	if len(requests) != 2 || !requests[0].Batch || requests[0].ID != ids[0] {
+		t.Fatalf("wanted the 2 requests of the batch, got %+v", requests)
+	}
+	rec.AssertPatched(t, "customers", ids[0], map[string]any{"displayName": "Adatum Corporation"})
+	rec.AssertCreated(t, "customers", map[string]any{"displayName": "Litware"})
+}
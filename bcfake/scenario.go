@@ -0,0 +1,254 @@
+package bcfake
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/erlorenz/bc-go/bc"
+)
+
+// Script is a scripted sequence of responses for the requests that match it, created
+// with [Server.On]. Each matching request takes the next step; when the steps run out
+// the server serves the requests normally again:
+//
+//	server.On(http.MethodGet, "customers").
+//		Fail(http.StatusInternalServerError, "Internal_ServerError", "Something went wrong.").
+//		Pass().Delay(2 * time.Second).
+//		Fail(http.StatusServiceUnavailable, "Internal_ServiceUnavailable", "Upgrading.").Times(3)
+//
+// The first GET of customers fails, the second is served after 2 seconds and the next
+// three fail with a 503.
+type Script struct {
+	method        string
+	entitySetName string
+	mu            sync.Mutex
+	steps         []step
+}
+
+type step struct {
+	pass   bool
+	status int
+	body   any
+	header http.Header
+	delay  time.Duration
+}
+
+// On adds a script for the requests with the method to the entity set, empty for any.
+// A request of a $batch is matched on its own. When several scripts match, the first
+// one with steps left is used.
+func (s *Server) On(method, entitySetName string) *Script {
+	sc := &Script{method: method, entitySetName: entitySetName}
+	s.scenario.mu.Lock()
+	defer s.scenario.mu.Unlock()
+	s.scenario.scripts = append(s.scenario.scripts, sc)
+	return sc
+}
+
+// Respond adds a step that answers with the status and the JSON of the body, or no
+// body if it is nil.
+func (sc *Script) Respond(status int, body any) *Script {
+	return sc.add(step{status: status, body: body})
+}
+
+// Fail adds a step that answers with the status and a BC error, e.g.
+// Fail(http.StatusTooManyRequests, "Application_TooManyRequests", "Too many requests.").
+func (sc *Script) Fail(status int, code, message string) *Script {
+	body := map[string]any{"error": map[string]string{"code": code, "message": message}}
+	return sc.add(step{status: status, body: body})
+}
+
+// Pass adds a step that serves the request normally, to fail a later request.
+func (sc *Script) Pass() *Script {
+	return sc.add(step{pass: true})
+}
+
+// Header sets a response header of the last step, e.g. "Retry-After".
+func (sc *Script) Header(key, value string) *Script {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	last := sc.last()
+	if last.header == nil {
+		last.header = http.Header{}
+	}
+	last.header.Set(key, value)
+	return sc
+}
+
+// Delay delays the last step.
+func (sc *Script) Delay(d time.Duration) *Script {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	sc.last().delay = d
+	return sc
+}
+
+// Times repeats the last step until it is used n times in a row.
+func (sc *Script) Times(n int) *Script {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	last := *sc.last()
+	for range n - 1 {
+		sc.steps = append(sc.steps, last)
+	}
+	return sc
+}
+
+// Remaining returns the number of steps that are not used yet.
+func (sc *Script) Remaining() int {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	return len(sc.steps)
+}
+
+func (sc *Script) add(st step) *Script {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	sc.steps = append(sc.steps, st)
+	return sc
+}
+
+// last returns the last step. It panics without steps, as it is a mistake in the test.
+func (sc *Script) last() *step {
+	if len(sc.steps) == 0 {
+		panic("bcfake: script has no steps")
+	}
+	return &sc.steps[len(sc.steps)-1]
+}
+
+// next takes the next step if the request matches.
+func (sc *Script) next(method, entitySetName string) (step, bool) {
+	if (sc.method != "" && sc.method != method) || (sc.entitySetName != "" && sc.entitySetName != entitySetName) {
+		return step{}, false
+	}
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	if len(sc.steps) == 0 {
+		return step{}, false
+	}
+	st := sc.steps[0]
+	sc.steps = sc.steps[1:]
+	return st, true
+}
+
+// scenario is the simulated behavior of a [Server]. It has its own lock, so a delay
+// does not block the other requests.
+type scenario struct {
+	mu      sync.Mutex
+	scripts []*Script
+	latency map[string]time.Duration
+
+	quota       int
+	window      time.Duration
+	windowStart time.Time
+	used        int
+	clock       bc.Clock
+}
+
+// SetLatency delays every request to the entity set, empty for any, by d. It adds to
+// the delay of a script step.
+func (s *Server) SetLatency(entitySetName string, d time.Duration) {
+	s.scenario.mu.Lock()
+	defer s.scenario.mu.Unlock()
+	if s.scenario.latency == nil {
+		s.scenario.latency = map[string]time.Duration{}
+	}
+	s.scenario.latency[entitySetName] = d
+}
+
+// SetQuota limits the server to limit requests per window, like the per-user limits of
+// BC. A request over the quota is answered with a 429 and a Retry-After header until the
+// window ends. A $batch counts as one request. A limit of 0 removes the quota.
+func (s *Server) SetQuota(limit int, window time.Duration) {
+	s.scenario.mu.Lock()
+	defer s.scenario.mu.Unlock()
+	s.scenario.quota, s.scenario.window = limit, window
+	s.scenario.windowStart, s.scenario.used = time.Time{}, 0
+}
+
+// SetClock replaces the [bc.SystemClock] for the quota windows and the latency, to
+// test them without waiting, e.g. with the bcmock.Clock of the client.
+func (s *Server) SetClock(clock bc.Clock) {
+	s.scenario.mu.Lock()
+	defer s.scenario.mu.Unlock()
+	s.scenario.clock = clock
+}
+
+// clockOrSystem returns the clock, or the [bc.SystemClock] if it is not set. The lock
+// must be held.
+func (sn *scenario) clockOrSystem() bc.Clock {
+	if sn.clock == nil {
+		return bc.SystemClock
+	}
+	return sn.clock
+}
+
+// overQuota counts the request and returns the time until the window ends if it is
+// over the quota, with the limit.
+func (sn *scenario) overQuota() (time.Duration, int, bool) {
+	sn.mu.Lock()
+	defer sn.mu.Unlock()
+	if sn.quota <= 0 {
+		return 0, 0, false
+	}
+	now := sn.clockOrSystem().Now()
+	if sn.windowStart.IsZero() || now.Sub(sn.windowStart) >= sn.window {
+		sn.windowStart, sn.used = now, 0
+	}
+	sn.used++
+	if sn.used <= sn.quota {
+		return 0, 0, false
+	}
+	return sn.window - now.Sub(sn.windowStart), sn.quota, true
+}
+
+// plan returns the delay and the scripted step of the request.
+func (sn *scenario) plan(method, entitySetName string) (time.Duration, step, bool) {
+	sn.mu.Lock()
+	defer sn.mu.Unlock()
+	delay := sn.latency[""] + sn.latency[entitySetName]
+	for _, sc := range sn.scripts {
+		if st, ok := sc.next(method, entitySetName); ok {
+			return delay + st.delay, st, !st.pass
+		}
+	}
+	return delay, step{}, false
+}
+
+// writeOverQuota answers a request over the quota.
+func writeOverQuota(w http.ResponseWriter, limit int, retryAfter time.Duration) {
+	w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+	writeError(w, http.StatusTooManyRequests, "Application_TooManyRequests", fmt.Sprintf("Too many requests reached. Actual (%d), maximum (%d).", limit+1, limit))
+}
+
+// simulate applies the latency and the scripts to the request. It returns true if the
+// request was answered.
+func (s *Server) simulate(w http.ResponseWriter, r *http.Request, entitySetName string) bool {
+	delay, st, scripted := s.scenario.plan(r.Method, entitySetName)
+	if delay > 0 {
+		s.scenario.mu.Lock()
+		clock := s.scenario.clockOrSystem()
+		s.scenario.mu.Unlock()
+		select {
+		case <-r.Context().Done():
+			writeError(w, http.StatusRequestTimeout, "RequestTimeout", fmt.Sprintf("The request was canceled: %s.", r.Context().Err()))
+			return true
+		case <-clock.After(delay):
+		}
+	}
+	if !scripted {
+		return false
+	}
+	for k, v := range st.header {
+		w.Header()[k] = v
+	}
+	if st.body == nil {
+		w.WriteHeader(st.status)
+		return true
+	}
+	writeJSON(w, st.status, st.body)
+	return true
+}
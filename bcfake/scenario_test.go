@@ -0,0 +1,188 @@
+package bcfake_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/erlorenz/bc-go/bcfake"
+	"github.com/erlorenz/bc-go/bcmock"
+)
+
+func statusCode(t *testing.T, err error) int {
+	t.Helper()
+	if err == nil {
+		return http.StatusOK
+	}
+	var apiErr bc.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("wanted an APIError, got %v", err)
+	}
+	return apiErr.StatusCode
+}
+
+func TestScript(t *testing.T) {
+	ctx := context.Background()
+	server := bcfake.New()
+	ids := server.Seed("customers", map[string]any{"displayName": "Adatum"})
+	page := newPage(t, server)
+
+	script := server.On(http.MethodGet, "customers").
+		Fail(http.StatusInternalServerError, "Internal_ServerError", "Something went wrong.").
+		Pass().
+		Fail(http.StatusServiceUnavailable, "Internal_ServiceUnavailable", "Upgrading.").Times(2).
+		Respond(http.StatusOK, map[string]any{"id": ids[0], "displayName": "Scripted"})
+	// Other methods and entity sets are not scripted.
+	if _, err := page.Update(ctx, ids[0], nil, map[string]any{"displayName": "Adatum Corporation"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []int
+	for range 6 {
+		_, err := page.Get(ctx, ids[0], bc.GetOptions{})
+		got = append(got, statusCode(t, err))
+	}
+	want := []int{500, 200, 503, 503, 200, 200}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("wanted statuses %v, got %v", want, got)
+		}
+	}
+	if script.Remaining() != 0 {
+		t.Errorf("wanted no steps left, got %d", script.Remaining())
+	}
+
+	server.On("", "").Respond(http.StatusOK, map[string]any{"id": ids[0], "displayName": "Scripted"})
+	customer, err := page.Get(ctx, ids[0], bc.GetOptions{})
+	if err != nil || customer.DisplayName != "Scripted" {
+		t.Errorf("wanted the scripted record, got %+v, %v", customer, err)
+	}
+}
+
+func TestScriptHeaderAndBatch(t *testing.T) {
+	server := bcfake.New()
+	ids := server.Seed("customers", map[string]any{"displayName": "Adatum"})
+	client := newPage(t, server).Client()
+
+	server.On(http.MethodDelete, "customers").
+		Fail(http.StatusTooManyRequests, "Application_TooManyRequests", "Too many requests.").Header("Retry-After", "7")
+
+	responses, err := client.Batch(context.Background(), []bc.RequestOptions{
+		{Method: http.MethodGet, EntitySetName: "customers", RecordID: ids[0]},
+		{Method: http.MethodDelete, EntitySetName: "customers", RecordID: ids[0]},
+	}, bc.BatchOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if responses[0].StatusCode != http.StatusOK || responses[1].StatusCode != http.StatusTooManyRequests {
+		t.Errorf("wanted only the scripted request of the batch to fail, got %+v", responses)
+	}
+	if got := responses[1].Header["retry-after"]; got != "7" {
+		t.Errorf("wanted Retry-After 7, got %v", responses[1].Header)
+	}
+}
+
+func TestScriptWithoutSteps(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("wanted a panic for Times without a step")
+		}
+	}()
+	bcfake.New().On("", "").Times(2)
+}
+
+func TestLatency(t *testing.T) {
+	server := bcfake.New()
+	ids := server.Seed("customers", map[string]any{"displayName": "Adatum"})
+	page := newPage(t, server)
+
+	server.SetLatency("customers", 20*time.Millisecond)
+	server.On(http.MethodGet, "customers").Pass().Delay(20 * time.Millisecond)
+
+	start := time.Now()
+	if _, err := page.Get(context.Background(), ids[0], bc.GetOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if d := time.Since(start); d < 40*time.Millisecond {
+		t.Errorf("wanted the latency and the step delay, got %s", d)
+	}
+
+	server.SetLatency("customers", time.Hour)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := page.Get(ctx, ids[0], bc.GetOptions{}); err == nil {
+		t.Error("wanted an error when the context is done")
+	}
+}
+
+func TestQuota(t *testing.T) {
+	server := bcfake.New()
+	ids := server.Seed("customers", map[string]any{"displayName": "Adatum"})
+	page := newPage(t, server)
+
+	clock := bcmock.NewClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	server.SetClock(clock)
+	server.SetQuota(2, time.Minute)
+
+	var got []int
+	for range 3 {
+		_, err := page.Get(context.Background(), ids[0], bc.GetOptions{})
+		got = append(got, statusCode(t, err))
+	}
+	if got[0] != 200 || got[1] != 200 || got[2] != 429 {
+		t.Errorf("wanted the third request throttled, got %v", got)
+	}
+
+	res, err := server.RoundTrip(newRequest(t, page))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode != http.StatusTooManyRequests || res.Header.Get("Retry-After") != "60" {
+		t.Errorf("wanted a 429 with Retry-After 60, got %d %q", res.StatusCode, res.Header.Get("Retry-After"))
+	}
+
+	clock.Advance(time.Minute)
+	if _, err := page.Get(context.Background(), ids[0], bc.GetOptions{}); err != nil {
+		t.Errorf("wanted the quota reset after the window, got %v", err)
+	}
+
+	server.SetQuota(0, 0)
+	for range 3 {
+		if _, err := page.List(context.Background(), bc.ListOptions{}); err != nil {
+			t.Fatalf("wanted no quota, got %v", err)
+		}
+	}
+}
+
+func TestLatencyClock(t *testing.T) {
+	server := bcfake.New()
+	ids := server.Seed("customers", map[string]any{"displayName": "Adatum"})
+	page := newPage(t, server)
+
+	clock := bcmock.NewClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	server.SetClock(clock)
+	server.SetLatency("customers", time.Hour)
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := page.Get(context.Background(), ids[0], bc.GetOptions{})
+		done <- err
+	}()
+	clock.BlockUntil(1)
+	clock.Advance(time.Hour)
+	if err := <-done; err != nil {
+		t.Errorf("wanted the request served after the latency, got %v", err)
+	}
+}
+
+func newRequest(t *testing.T, page *bc.APIPage[customer]) *http.Request {
+	t.Helper()
+	r, err := page.Client().NewRequest(context.Background(), bc.RequestOptions{Method: http.MethodGet, EntitySetName: "customers"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
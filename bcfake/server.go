@@ -0,0 +1,344 @@
+// Package bcfake is an in-memory fake of the Business Central API for tests and benchmarks.
+// A [Server] is an http.RoundTripper, so a bc.Client uses it with
+//
+//	bc.WithHTTPClient(server.HTTPClient())
+//
+// It supports GET, POST, PATCH and DELETE on any entity set, including navigation paths like
+// "salesOrders(id)/salesOrderLines", If-Match checks against "@odata.etag", paging with the
+// "Prefer: odata.maxpagesize" header, $top, $skip and JSON $batch. Other query options such as
+// $filter and $orderby are ignored.
+//
+// Failures are simulated with scripts of responses per method and entity set ([Server.On]),
+// latency per entity set ([Server.SetLatency]) and a request quota ([Server.SetQuota]).
+// A [Recorder] in front of the server records the requests and asserts what was written.
+package bcfake
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// Server is an in-memory fake of the Business Central API. It is safe for concurrent use.
+type Server struct {
+	mu       sync.Mutex
+	sets     map[string]*entitySet
+	version  int
+	scenario scenario
+}
+
+type entitySet struct {
+	ids     []uuid.UUID
+	records map[uuid.UUID]map[string]any
+}
+
+// New creates an empty Server.
+func New() *Server {
+	return &Server{sets: map[string]*entitySet{}}
+}
+
+// HTTPClient returns an http.Client that sends every request to the server.
+func (s *Server) HTTPClient() *http.Client {
+	return &http.Client{Transport: s}
+}
+
+// Seed adds records to the entity set. An "id" is generated if missing
+// and an "@odata.etag" is set. It returns the ids in order.
+func (s *Server) Seed(entitySetName string, records ...map[string]any) []uuid.UUID {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ids := make([]uuid.UUID, len(records))
+	for i, r := range records {
+		ids[i] = s.insert(entitySetName, maps.Clone(r))
+	}
+	return ids
+}
+
+// Records returns copies of the records in the entity set, in insertion order.
+func (s *Server) Records(entitySetName string) []map[string]any {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	set := s.sets[entitySetName]
+	if set == nil {
+		return nil
+	}
+	records := make([]map[string]any, len(set.ids))
+	for i, id := range set.ids {
+		records[i] = maps.Clone(set.records[id])
+	}
+	return records
+}
+
+// RoundTrip implements http.RoundTripper by serving the request in memory.
+func (s *Server) RoundTrip(r *http.Request) (*http.Response, error) {
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, r)
+	res := rec.Result()
+	res.Request = r
+	return res, nil
+}
+
+// ServeHTTP implements http.Handler, so the server can also run with httptest.NewServer.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Body != nil {
+		defer r.Body.Close()
+	}
+
+	if retryAfter, limit, ok := s.scenario.overQuota(); ok {
+		writeOverQuota(w, limit, retryAfter)
+		return
+	}
+	if strings.HasSuffix(r.URL.Path, "/$batch") {
+		s.serveBatch(w, r)
+		return
+	}
+	s.serve(w, r)
+}
+
+// serve serves a request to an entity set, on its own or in a $batch.
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	entitySetName, id, ok := parsePath(r.URL.Path)
+	if !ok {
+		writeError(w, http.StatusNotFound, "BadRequest_NotFound", "No HTTP resource was found that matches the request URI.")
+		return
+	}
+	if s.simulate(w, r, entitySetName) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch {
+	case r.Method == http.MethodGet && id == uuid.Nil:
+		s.list(w, r, entitySetName)
+	case r.Method == http.MethodGet:
+		if record, ok := s.lookup(w, entitySetName, id); ok {
+			writeJSON(w, http.StatusOK, record)
+		}
+	case r.Method == http.MethodPost && id == uuid.Nil:
+		var body map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			writeError(w, http.StatusBadRequest, "BadRequest_InvalidRequestBody", err.Error())
+			return
+		}
+		id := s.insert(entitySetName, body)
+		writeJSON(w, http.StatusCreated, s.sets[entitySetName].records[id])
+	case r.Method == http.MethodPatch && id != uuid.Nil:
+		record, ok := s.lookup(w, entitySetName, id)
+		if !ok || !checkIfMatch(w, r, record) {
+			return
+		}
+		var body map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			writeError(w, http.StatusBadRequest, "BadRequest_InvalidRequestBody", err.Error())
+			return
+		}
+		delete(body, "id")
+		maps.Copy(record, body)
+		record["@odata.etag"] = s.etag()
+		writeJSON(w, http.StatusOK, record)
+	case r.Method == http.MethodDelete && id != uuid.Nil:
+		record, ok := s.lookup(w, entitySetName, id)
+		if !ok || !checkIfMatch(w, r, record) {
+			return
+		}
+		set := s.sets[entitySetName]
+		delete(set.records, id)
+		set.ids = slices.DeleteFunc(set.ids, func(v uuid.UUID) bool { return v == id })
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "BadRequest_MethodNotAllowed", fmt.Sprintf("'%s' requests for '%s' are not allowed.", r.Method, entitySetName))
+	}
+}
+
+// list writes a page of records. The nextLink has a $skip for the next page.
+func (s *Server) list(w http.ResponseWriter, r *http.Request, entitySetName string) {
+	query := r.URL.Query()
+	skip, _ := strconv.Atoi(query.Get("$skip"))
+	top, _ := strconv.Atoi(query.Get("$top"))
+	pageSize := 0
+	if v, ok := strings.CutPrefix(r.Header.Get("Prefer"), "odata.maxpagesize="); ok {
+		pageSize, _ = strconv.Atoi(v)
+	}
+
+	var records []map[string]any
+	if set := s.sets[entitySetName]; set != nil {
+		for _, id := range set.ids {
+			records = append(records, set.records[id])
+		}
+	}
+
+	records = records[min(skip, len(records)):]
+	if top > 0 && top < len(records) {
+		records = records[:top]
+	}
+
+	body := map[string]any{}
+	if pageSize > 0 && pageSize < len(records) {
+		records = records[:pageSize]
+
+		next := *r.URL
+		query.Set("$skip", strconv.Itoa(skip+pageSize))
+		if top > 0 {
+			query.Set("$top", strconv.Itoa(top-pageSize))
+		}
+		next.RawQuery = query.Encode()
+		body["@odata.nextLink"] = next.String()
+	}
+	body["value"] = nonNil(records)
+
+	writeJSON(w, http.StatusOK, body)
+}
+
+func nonNil(records []map[string]any) []map[string]any {
+	if records == nil {
+		return []map[string]any{}
+	}
+	return records
+}
+
+func (s *Server) lookup(w http.ResponseWriter, entitySetName string, id uuid.UUID) (map[string]any, bool) {
+	if set := s.sets[entitySetName]; set != nil {
+		if record, ok := set.records[id]; ok {
+			return record, true
+		}
+	}
+	writeError(w, http.StatusNotFound, "Internal_RecordNotFound", fmt.Sprintf("The %s does not exist. Identification fields and values: Id='%s'", entitySetName, id))
+	return nil, false
+}
+
+// insert adds the record and returns its id. The lock must be held.
+func (s *Server) insert(entitySetName string, record map[string]any) uuid.UUID {
+	set := s.sets[entitySetName]
+	if set == nil {
+		set = &entitySet{records: map[uuid.UUID]map[string]any{}}
+		s.sets[entitySetName] = set
+	}
+
+	id, _ := uuid.Parse(fmt.Sprint(record["id"]))
+	if id == uuid.Nil {
+		id = uuid.New()
+	}
+	record["id"] = id.String()
+	record["@odata.etag"] = s.etag()
+
+	if _, exists := set.records[id]; !exists {
+		set.ids = append(set.ids, id)
+	}
+	set.records[id] = record
+	return id
+}
+
+// etag returns a new weak ETag. The lock must be held.
+func (s *Server) etag() string {
+	s.version++
+	return fmt.Sprintf(`W/"%d"`, s.version)
+}
+
+func checkIfMatch(w http.ResponseWriter, r *http.Request, record map[string]any) bool {
+	match := r.Header.Get("If-Match")
+	if match == "" || match == "*" || match == record["@odata.etag"] {
+		return true
+	}
+	writeError(w, http.StatusPreconditionFailed, "Request_EntityChanged", "Another user has already changed the record.")
+	return false
+}
+
+// pathPattern matches "/companies({id})/{entitySet}" with an optional "({id})" at the end.
+var pathPattern = regexp.MustCompile(`/companies\([^)]*\)/(.+?)(?:\(([0-9a-fA-F-]{36})\))?$`)
+
+// parsePath returns the entity set name, which may be a navigation path, and the record id.
+func parsePath(path string) (string, uuid.UUID, bool) {
+	m := pathPattern.FindStringSubmatch(path)
+	if m == nil {
+		return "", uuid.Nil, false
+	}
+	var id uuid.UUID
+	if m[2] != "" {
+		id = uuid.MustParse(m[2])
+	}
+	return m[1], id, true
+}
+
+type batchRequest struct {
+	Requests []struct {
+		ID      string            `json:"id"`
+		Method  string            `json:"method"`
+		URL     string            `json:"url"`
+		Headers map[string]string `json:"headers"`
+		Body    json.RawMessage   `json:"body"`
+	} `json:"requests"`
+}
+
+type batchResponse struct {
+	ID      string            `json:"id"`
+	Status  int               `json:"status"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    json.RawMessage   `json:"body,omitempty"`
+}
+
+// serveBatch runs each request of a JSON $batch in order. Atomicity groups are not rolled back.
+func (s *Server) serveBatch(w http.ResponseWriter, r *http.Request) {
+	var batch batchRequest
+	if err := json.NewDecoder(r.Body).Decode(&batch); err != nil {
+		writeError(w, http.StatusBadRequest, "BadRequest_InvalidRequestBody", err.Error())
+		return
+	}
+
+	root := strings.TrimSuffix(r.URL.Path, "$batch")
+	responses := make([]batchResponse, len(batch.Requests))
+	for i, br := range batch.Requests {
+		u, err := url.Parse(br.URL)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "BadRequest_InvalidRequestUrl", err.Error())
+			return
+		}
+		u.Path = root + u.Path
+
+		inner := httptest.NewRequestWithContext(r.Context(), br.Method, u.String(), bytes.NewReader(br.Body))
+		for k, v := range br.Headers {
+			inner.Header.Set(k, v)
+		}
+
+		rec := httptest.NewRecorder()
+		s.serve(rec, inner)
+
+		responses[i] = batchResponse{ID: br.ID, Status: rec.Code}
+		for k := range rec.Header() {
+			if responses[i].Headers == nil {
+				responses[i].Headers = map[string]string{}
+			}
+			responses[i].Headers[strings.ToLower(k)] = rec.Header().Get(k)
+		}
+		if b, _ := io.ReadAll(rec.Body); len(b) > 0 {
+			responses[i].Body = b
+		}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{"responses": responses})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, code string, message string) {
+	writeJSON(w, status, map[string]any{"error": map[string]string{"code": code, "message": message}})
+}
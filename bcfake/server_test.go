@@ -0,0 +1,112 @@
+package bcfake_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/erlorenz/bc-go/bcfake"
+	"github.com/erlorenz/bc-go/internal/bctest"
+	"github.com/google/uuid"
+)
+
+type customer struct {
+	ETag        string    `json:"@odata.etag"`
+	ID          uuid.UUID `json:"id"`
+	DisplayName string    `json:"displayName"`
+}
+
+func (c customer) Validate() error { return nil }
+
+func newPage(t *testing.T, server *bcfake.Server) *bc.APIPage[customer] {
+	t.Helper()
+	return bc.NewAPIPage[customer](bctest.NewClient(t, server), "customers")
+}
+
+func TestServerCRUD(t *testing.T) {
+	ctx := context.Background()
+	server := bcfake.New()
+	page := newPage(t, server)
+
+	created, err := page.Create(ctx, map[string]any{"displayName": "Adatum"}, bc.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := page.Get(ctx, created.ID, bc.GetOptions{})
+	if err != nil || got.DisplayName != "Adatum" {
+		t.Fatalf("get: %v %+v", err, got)
+	}
+
+	updated, err := page.UpdateIfMatch(ctx, created.ID, nil, map[string]any{"displayName": "Fabrikam"}, created.ETag)
+	if err != nil || updated.DisplayName != "Fabrikam" {
+		t.Fatalf("update: %v %+v", err, updated)
+	}
+
+	if err := page.DeleteIfMatch(ctx, created.ID, created.ETag); !errors.Is(err, bc.ErrPreconditionFailed) {
+		t.Errorf("expected ErrPreconditionFailed with stale etag, got %v", err)
+	}
+	if err := page.DeleteIfMatch(ctx, created.ID, updated.ETag); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := page.Get(ctx, created.ID, bc.GetOptions{}); !errors.Is(err, bc.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestServerPaging(t *testing.T) {
+	server := bcfake.New()
+	for range 5 {
+		server.Seed("customers", map[string]any{"displayName": "x"})
+	}
+	page := newPage(t, server)
+
+	list, err := page.ListPage(context.Background(), "", bc.ListOptions{MaxPageSize: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pages := 1
+	for list.NextLink != "" {
+		if list, err = page.ListPage(context.Background(), list.NextLink, bc.ListOptions{MaxPageSize: 2}); err != nil {
+			t.Fatal(err)
+		}
+		pages++
+	}
+	if pages != 3 {
+		t.Errorf("wanted 3 pages, got %d", pages)
+	}
+
+	all, err := page.List(context.Background(), bc.ListOptions{Top: 3})
+	if err != nil || len(all) != 3 {
+		t.Errorf("wanted 3 records, got %d: %v", len(all), err)
+	}
+}
+
+func TestServerBatch(t *testing.T) {
+	server := bcfake.New()
+	ids := server.Seed("customers", map[string]any{"displayName": "a"})
+	client := newPage(t, server).Client()
+
+	responses, err := client.Batch(context.Background(), []bc.RequestOptions{
+		{Method: http.MethodPost, EntitySetName: "customers", Body: map[string]any{"displayName": "b"}},
+		{Method: http.MethodDelete, EntitySetName: "customers", RecordID: ids[0]},
+		{Method: http.MethodGet, EntitySetName: "customers", RecordID: uuid.New()},
+	}, bc.BatchOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if responses[0].StatusCode != http.StatusCreated || responses[1].StatusCode != http.StatusNoContent {
+		t.Errorf("unexpected responses %+v", responses)
+	}
+	if err := responses[2].Err(); !errors.Is(err, bc.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+
+	records := server.Records("customers")
+	if len(records) != 1 || records[0]["displayName"] != "b" {
+		t.Errorf("unexpected records %v", records)
+	}
+}
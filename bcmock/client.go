@@ -0,0 +1,197 @@
+// Package bcmock has configurable stubs of [bc.API] and [bc.Repository], to unit test
+// code that uses BC without HTTP. Each method calls the function field of the same
+// name, or returns an error matching [ErrNotConfigured] if it is nil, and records
+// the call:
+//
+//	client := &bcmock.Client{
+//		InvokeActionFunc: func(ctx context.Context, entitySetName string, id uuid.UUID, action string, body any) error {
+//			return nil
+//		},
+//	}
+//	err := postInvoice(ctx, client, id)
+//	if calls := client.CallsTo("InvokeAction"); len(calls) != 1 || calls[0].Args[2] != "post" {
+//		t.Errorf("wanted the invoice posted, got %v", calls)
+//	}
+package bcmock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/google/uuid"
+)
+
+// ErrNotConfigured is returned by a stub method without a function.
+var ErrNotConfigured = errors.New("bcmock: method not configured")
+
+func notConfigured(method string) error {
+	return fmt.Errorf("%w: %s", ErrNotConfigured, method)
+}
+
+// Call is a recorded call of a stub method. Args are the arguments after the context.
+type Call struct {
+	Method string
+	Args   []any
+}
+
+// calls records the calls of a stub. It is safe for concurrent use.
+type calls struct {
+	mu    sync.Mutex
+	calls []Call
+}
+
+func (c *calls) record(method string, args ...any) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.calls = append(c.calls, Call{Method: method, Args: args})
+}
+
+// Calls returns the recorded calls in order.
+func (c *calls) Calls() []Call {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]Call(nil), c.calls...)
+}
+
+// CallsTo returns the recorded calls of the method in order.
+func (c *calls) CallsTo(method string) []Call {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var calls []Call
+	for _, call := range c.calls {
+		if call.Method == method {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// Client is a stub of [bc.API]. The zero value returns ErrNotConfigured for every method.
+type Client struct {
+	calls
+
+	DoFunc             func(r *http.Request) (*http.Response, error)
+	NewRequestFunc     func(ctx context.Context, opts bc.RequestOptions) (*http.Request, error)
+	NewRequestURLFunc  func(ctx context.Context, method string, rawURL string, body any) (*http.Request, error)
+	InvokeActionFunc   func(ctx context.Context, entitySetName string, id uuid.UUID, action string, body any) error
+	DeleteIfExistsFunc func(ctx context.Context, entitySetName string, id uuid.UUID) (bool, error)
+	DeleteIfMatchFunc  func(ctx context.Context, entitySetName string, id uuid.UUID, etag string) error
+	BatchFunc          func(ctx context.Context, requests []bc.RequestOptions, opts bc.BatchOptions) ([]bc.BatchResponse, error)
+	BulkFunc           func(ctx context.Context, requests []bc.RequestOptions, opts bc.BulkOptions) ([]bc.BatchResponse, error)
+	CapabilitiesFunc   func(ctx context.Context) (bc.Capabilities, error)
+	DownloadMediaFunc  func(ctx context.Context, path string, w io.Writer, opts bc.MediaOptions) (bc.MediaResult, error)
+	UploadMediaFunc    func(ctx context.Context, path string, r io.Reader, contentType string) error
+	PingFunc           func(ctx context.Context) (bc.HealthReport, error)
+	SnapshotFunc       func(ctx context.Context, specs []bc.EntitySpec) (bc.Snapshot, error)
+}
+
+var _ bc.API = (*Client)(nil)
+
+func (c *Client) Do(r *http.Request) (*http.Response, error) {
+	c.record("Do", r)
+	if c.DoFunc == nil {
+		return nil, notConfigured("Do")
+	}
+	return c.DoFunc(r)
+}
+
+func (c *Client) NewRequest(ctx context.Context, opts bc.RequestOptions) (*http.Request, error) {
+	c.record("NewRequest", opts)
+	if c.NewRequestFunc == nil {
+		return nil, notConfigured("NewRequest")
+	}
+	return c.NewRequestFunc(ctx, opts)
+}
+
+func (c *Client) NewRequestURL(ctx context.Context, method string, rawURL string, body any) (*http.Request, error) {
+	c.record("NewRequestURL", method, rawURL, body)
+	if c.NewRequestURLFunc == nil {
+		return nil, notConfigured("NewRequestURL")
+	}
+	return c.NewRequestURLFunc(ctx, method, rawURL, body)
+}
+
+func (c *Client) InvokeAction(ctx context.Context, entitySetName string, id uuid.UUID, action string, body any) error {
+	c.record("InvokeAction", entitySetName, id, action, body)
+	if c.InvokeActionFunc == nil {
+		return notConfigured("InvokeAction")
+	}
+	return c.InvokeActionFunc(ctx, entitySetName, id, action, body)
+}
+
+func (c *Client) DeleteIfExists(ctx context.Context, entitySetName string, id uuid.UUID) (bool, error) {
+	c.record("DeleteIfExists", entitySetName, id)
+	if c.DeleteIfExistsFunc == nil {
+		return false, notConfigured("DeleteIfExists")
+	}
+	return c.DeleteIfExistsFunc(ctx, entitySetName, id)
+}
+
+func (c *Client) DeleteIfMatch(ctx context.Context, entitySetName string, id uuid.UUID, etag string) error {
+	c.record("DeleteIfMatch", entitySetName, id, etag)
+	if c.DeleteIfMatchFunc == nil {
+		return notConfigured("DeleteIfMatch")
+	}
+	return c.DeleteIfMatchFunc(ctx, entitySetName, id, etag)
+}
+
+func (c *Client) Batch(ctx context.Context, requests []bc.RequestOptions, opts bc.BatchOptions) ([]bc.BatchResponse, error) {
+	c.record("Batch", requests, opts)
+	if c.BatchFunc == nil {
+		return nil, notConfigured("Batch")
+	}
+	return c.BatchFunc(ctx, requests, opts)
+}
+
+func (c *Client) Bulk(ctx context.Context, requests []bc.RequestOptions, opts bc.BulkOptions) ([]bc.BatchResponse, error) {
+	c.record("Bulk", requests, opts)
+	if c.BulkFunc == nil {
+		return nil, notConfigured("Bulk")
+	}
+	return c.BulkFunc(ctx, requests, opts)
+}
+
+func (c *Client) Capabilities(ctx context.Context) (bc.Capabilities, error) {
+	c.record("Capabilities")
+	if c.CapabilitiesFunc == nil {
+		return bc.Capabilities{}, notConfigured("Capabilities")
+	}
+	return c.CapabilitiesFunc(ctx)
+}
+
+func (c *Client) DownloadMedia(ctx context.Context, path string, w io.Writer, opts bc.MediaOptions) (bc.MediaResult, error) {
+	c.record("DownloadMedia", path, w, opts)
+	if c.DownloadMediaFunc == nil {
+		return bc.MediaResult{}, notConfigured("DownloadMedia")
+	}
+	return c.DownloadMediaFunc(ctx, path, w, opts)
+}
+
+func (c *Client) UploadMedia(ctx context.Context, path string, r io.Reader, contentType string) error {
+	c.record("UploadMedia", path, r, contentType)
+	if c.UploadMediaFunc == nil {
+		return notConfigured("UploadMedia")
+	}
+	return c.UploadMediaFunc(ctx, path, r, contentType)
+}
+
+func (c *Client) Ping(ctx context.Context) (bc.HealthReport, error) {
+	c.record("Ping")
+	if c.PingFunc == nil {
+		return bc.HealthReport{}, notConfigured("Ping")
+	}
+	return c.PingFunc(ctx)
+}
+
+func (c *Client) Snapshot(ctx context.Context, specs []bc.EntitySpec) (bc.Snapshot, error) {
+	c.record("Snapshot", specs)
+	if c.SnapshotFunc == nil {
+		return bc.Snapshot{}, notConfigured("Snapshot")
+	}
+	return c.SnapshotFunc(ctx, specs)
+}
@@ -0,0 +1,68 @@
+package bcmock_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/erlorenz/bc-go/bcmock"
+	"github.com/google/uuid"
+)
+
+// postInvoice is code under test that only depends on bc.API.
+func postInvoice(ctx context.Context, api bc.API, id uuid.UUID) error {
+	if err := api.InvokeAction(ctx, "salesInvoices", id, "post", nil); err != nil {
+		return err
+	}
+	_, err := api.DeleteIfExists(ctx, "salesInvoiceDrafts", id)
+	return err
+}
+
+func TestClient(t *testing.T) {
+	client := &bcmock.Client{
+		InvokeActionFunc: func(ctx context.Context, entitySetName string, id uuid.UUID, action string, body any) error {
+			return nil
+		},
+	}
+	id := uuid.New()
+
+	err := postInvoice(context.Background(), client, id)
+	if !errors.Is(err, bcmock.ErrNotConfigured) {
+		t.Fatalf("wanted ErrNotConfigured for DeleteIfExists, got %v", err)
+	}
+
+	calls := client.Calls()
+	if len(calls) != 2 || calls[0].Method != "InvokeAction" || calls[1].Method != "DeleteIfExists" {
+		t.Fatalf("unexpected calls %+v", calls)
+	}
+	invoke := client.CallsTo("InvokeAction")
+	if len(invoke) != 1 || invoke[0].Args[0] != "salesInvoices" || invoke[0].Args[1] != id || invoke[0].Args[2] != "post" {
+		t.Errorf("unexpected InvokeAction calls %+v", invoke)
+	}
+
+	client.DeleteIfExistsFunc = func(ctx context.Context, entitySetName string, id uuid.UUID) (bool, error) {
+		return true, nil
+	}
+	if err := postInvoice(context.Background(), client, id); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(client.CallsTo("DeleteIfExists")); n != 2 {
+		t.Errorf("wanted 2 DeleteIfExists calls, got %d", n)
+	}
+}
+
+func TestClientNotConfigured(t *testing.T) {
+	var client bcmock.Client
+	ctx := context.Background()
+	_, err := client.Ping(ctx)
+	if !errors.Is(err, bcmock.ErrNotConfigured) || err.Error() != "bcmock: method not configured: Ping" {
+		t.Errorf("unexpected error %v", err)
+	}
+	if _, err := client.Snapshot(ctx, nil); !errors.Is(err, bcmock.ErrNotConfigured) {
+		t.Errorf("wanted ErrNotConfigured, got %v", err)
+	}
+	if _, err := client.Do(nil); !errors.Is(err, bcmock.ErrNotConfigured) {
+		t.Errorf("wanted ErrNotConfigured, got %v", err)
+	}
+}
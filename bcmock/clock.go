@@ -0,0 +1,104 @@
+package bcmock
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/erlorenz/bc-go/bc"
+)
+
+// Clock is a fake [bc.Clock] whose time only moves with Advance or Set, so retry
+// backoff, token expiry and subscription renewal can be tested without sleeping:
+//
+//	clock := bcmock.NewClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+//	client, _ := bc.NewClient(config, bc.WithClock(clock), bc.WithRetryClassifier(bc.DefaultRetryClassifier{}))
+//	go func() {
+//		clock.BlockUntil(1) // the first backoff
+//		clock.Advance(time.Second)
+//	}()
+//
+// It is safe for concurrent use.
+type Clock struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	now     time.Time
+	waiters []clockWaiter
+}
+
+type clockWaiter struct {
+	at time.Time
+	c  chan time.Time
+}
+
+var _ bc.Clock = (*Clock)(nil)
+
+// NewClock creates a Clock at the time.
+func NewClock(now time.Time) *Clock {
+	c := &Clock{now: now}
+	c.cond = sync.NewCond(&c.mu)
+	return c
+}
+
+// Now implements [bc.Clock].
+func (c *Clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// After implements [bc.Clock]. The channel receives once the clock is advanced past d.
+func (c *Clock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ch := make(chan time.Time, 1)
+	if d <= 0 {
+		ch <- c.now
+		return ch
+	}
+	c.waiters = append(c.waiters, clockWaiter{at: c.now.Add(d), c: ch})
+	c.cond.Broadcast()
+	return ch
+}
+
+// Advance moves the clock forward and fires the waiters that are due.
+func (c *Clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.set(c.now.Add(d))
+}
+
+// Set moves the clock to the time and fires the waiters that are due.
+func (c *Clock) Set(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.set(now)
+}
+
+func (c *Clock) set(now time.Time) {
+	c.now = now
+	sort.SliceStable(c.waiters, func(i, j int) bool { return c.waiters[i].at.Before(c.waiters[j].at) })
+	i := 0
+	for ; i < len(c.waiters) && !c.waiters[i].at.After(now); i++ {
+		c.waiters[i].c <- now
+	}
+	c.waiters = c.waiters[i:]
+	c.cond.Broadcast()
+}
+
+// Waiters returns the number of pending After calls.
+func (c *Clock) Waiters() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.waiters)
+}
+
+// BlockUntil waits until there are at least n pending After calls, so a test can
+// advance the clock once the code under test is waiting.
+func (c *Clock) BlockUntil(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for len(c.waiters) < n {
+		c.cond.Wait()
+	}
+}
@@ -0,0 +1,59 @@
+package bcmock_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/erlorenz/bc-go/bcmock"
+)
+
+func TestClock(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := bcmock.NewClock(start)
+
+	short := clock.After(time.Second)
+	long := clock.After(time.Minute)
+	if n := clock.Waiters(); n != 2 {
+		t.Fatalf("wanted 2 waiters, got %d", n)
+	}
+
+	clock.Advance(30 * time.Second)
+	select {
+	case got := <-short:
+		if !got.Equal(start.Add(30 * time.Second)) {
+			t.Errorf("unexpected time %s", got)
+		}
+	default:
+		t.Fatal("wanted the short waiter fired")
+	}
+	select {
+	case <-long:
+		t.Fatal("wanted the long waiter pending")
+	default:
+	}
+
+	clock.Set(start.Add(time.Hour))
+	<-long
+	if n := clock.Waiters(); n != 0 {
+		t.Errorf("wanted no waiters, got %d", n)
+	}
+
+	select {
+	case <-clock.After(0):
+	default:
+		t.Error("wanted After(0) to fire immediately")
+	}
+}
+
+func TestClockBlockUntil(t *testing.T) {
+	clock := bcmock.NewClock(time.Now())
+	done := make(chan struct{})
+	go func() {
+		<-clock.After(time.Hour)
+		close(done)
+	}()
+
+	clock.BlockUntil(1)
+	clock.Advance(time.Hour)
+	<-done
+}
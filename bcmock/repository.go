@@ -0,0 +1,75 @@
+package bcmock
+
+import (
+	"context"
+	"time"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/google/uuid"
+)
+
+// Repository is a stub of [bc.Repository]. The zero value returns ErrNotConfigured
+// for every method.
+type Repository[T any] struct {
+	calls
+
+	ListFunc    func(ctx context.Context, opts bc.ListOptions) ([]T, error)
+	GetFunc     func(ctx context.Context, id uuid.UUID) (T, error)
+	CreateFunc  func(ctx context.Context, record T) (T, error)
+	UpdateFunc  func(ctx context.Context, id uuid.UUID, record T) (T, error)
+	DeleteFunc  func(ctx context.Context, id uuid.UUID) error
+	ChangesFunc func(ctx context.Context, since time.Time) ([]T, error)
+}
+
+var _ bc.Repository[struct{}] = (*Repository[struct{}])(nil)
+
+func (r *Repository[T]) List(ctx context.Context, opts bc.ListOptions) ([]T, error) {
+	r.record("List", opts)
+	if r.ListFunc == nil {
+		return nil, notConfigured("List")
+	}
+	return r.ListFunc(ctx, opts)
+}
+
+func (r *Repository[T]) Get(ctx context.Context, id uuid.UUID) (T, error) {
+	r.record("Get", id)
+	if r.GetFunc == nil {
+		var zero T
+		return zero, notConfigured("Get")
+	}
+	return r.GetFunc(ctx, id)
+}
+
+func (r *Repository[T]) Create(ctx context.Context, record T) (T, error) {
+	r.record("Create", record)
+	if r.CreateFunc == nil {
+		var zero T
+		return zero, notConfigured("Create")
+	}
+	return r.CreateFunc(ctx, record)
+}
+
+func (r *Repository[T]) Update(ctx context.Context, id uuid.UUID, record T) (T, error) {
+	r.record("Update", id, record)
+	if r.UpdateFunc == nil {
+		var zero T
+		return zero, notConfigured("Update")
+	}
+	return r.UpdateFunc(ctx, id, record)
+}
+
+func (r *Repository[T]) Delete(ctx context.Context, id uuid.UUID) error {
+	r.record("Delete", id)
+	if r.DeleteFunc == nil {
+		return notConfigured("Delete")
+	}
+	return r.DeleteFunc(ctx, id)
+}
+
+func (r *Repository[T]) Changes(ctx context.Context, since time.Time) ([]T, error) {
+	r.record("Changes", since)
+	if r.ChangesFunc == nil {
+		return nil, notConfigured("Changes")
+	}
+	return r.ChangesFunc(ctx, since)
+}
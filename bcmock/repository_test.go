@@ -0,0 +1,38 @@
+package bcmock_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/erlorenz/bc-go/bcmock"
+	"github.com/google/uuid"
+)
+
+type customer struct {
+	ID     uuid.UUID `json:"id"`
+	Number string    `json:"number"`
+}
+
+func TestRepository(t *testing.T) {
+	repo := &bcmock.Repository[customer]{
+		ListFunc: func(ctx context.Context, opts bc.ListOptions) ([]customer, error) {
+			return []customer{{ID: uuid.New(), Number: "C001"}}, nil
+		},
+	}
+	var r bc.Repository[customer] = repo
+
+	got, err := r.List(context.Background(), bc.ListOptions{Filter: "number eq 'C001'"})
+	if err != nil || len(got) != 1 || got[0].Number != "C001" {
+		t.Fatalf("unexpected List %+v, %v", got, err)
+	}
+	if _, err := r.Update(context.Background(), got[0].ID, got[0]); !errors.Is(err, bcmock.ErrNotConfigured) {
+		t.Errorf("wanted ErrNotConfigured, got %v", err)
+	}
+
+	calls := repo.Calls()
+	if len(calls) != 2 || calls[0].Args[0].(bc.ListOptions).Filter != "number eq 'C001'" || calls[1].Args[1].(customer).Number != "C001" {
+		t.Errorf("unexpected calls %+v", calls)
+	}
+}
@@ -0,0 +1,519 @@
+// Package bcql parses a small SQL-like query language into the OData query options of an
+// entity set, for internal tools and exploring an environment from a REPL:
+//
+//	q, err := bcql.Parse("SELECT number, displayName FROM customers WHERE blocked = false ORDER BY displayName LIMIT 100")
+//	req, err := client.NewRequest(ctx, q.RequestOptions())
+//
+// A statement is
+//
+//	SELECT * | field, ... FROM entitySet [EXPAND field, ...] [WHERE condition]
+//	[ORDER BY field [ASC | DESC], ...] [LIMIT n] [OFFSET n]
+//
+// Keywords are not case sensitive, field and entity set names are. A condition compares
+// a field with =, != or <>, <, <=, > and >=, or is one of
+//
+//	field IS [NOT] NULL
+//	field [NOT] IN (value, ...)
+//	field [NOT] LIKE 'abc%'
+//	field BETWEEN value AND value
+//
+// combined with AND, OR, NOT and parentheses. LIKE only supports a % at the start, the end
+// or both, which become startswith, endswith and contains. Values are 'strings' with quotes
+// doubled inside them, numbers, TRUE, FALSE and NULL, and DATE '2024-01-31',
+// TIMESTAMP '2024-01-31T12:00:00Z' and GUID '...' for the OData literals that are not quoted.
+package bcql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/erlorenz/bc-go/filter"
+	"github.com/google/uuid"
+)
+
+// Query is a parsed statement.
+type Query struct {
+	EntitySetName string
+	ListOptions   bc.ListOptions
+}
+
+// RequestOptions returns the GET request of the query.
+func (q Query) RequestOptions() bc.RequestOptions {
+	return bc.RequestOptions{
+		Method:        http.MethodGet,
+		EntitySetName: q.EntitySetName,
+		QueryParams:   q.queryParams(),
+	}
+}
+
+// queryParams adds an OFFSET without a LIMIT, which [bc.ListOptions.BuildQueryParams]
+// only sends with a $top.
+func (q Query) queryParams() bc.QueryParams {
+	qp := q.ListOptions.BuildQueryParams("", nil)
+	if q.ListOptions.Skip != 0 {
+		qp.Set("$skip", strconv.Itoa(q.ListOptions.Skip))
+	}
+	return qp
+}
+
+// SyntaxError is returned by [Parse] for an invalid statement.
+type SyntaxError struct {
+	// Pos is the byte offset in the statement.
+	Pos int
+	Msg string
+}
+
+func (err *SyntaxError) Error() string {
+	return fmt.Sprintf("bcql: syntax error at position %d: %s", err.Pos, err.Msg)
+}
+
+// record decodes any entity as a map of its fields.
+type record map[string]any
+
+func (record) Validate() error {
+	return nil
+}
+
+// Run parses the statement and lists the records. Without a LIMIT it returns the first
+// page, up to 20000 records.
+func Run(ctx context.Context, client *bc.Client, stmt string) ([]map[string]any, error) {
+	q, err := Parse(stmt)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := client.NewRequest(ctx, q.RequestOptions())
+	if err != nil {
+		return nil, fmt.Errorf("bcql: %w", err)
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("bcql: %w", err)
+	}
+	list, err := bc.Decode[bc.APIListResponse[record]](res)
+	if err != nil {
+		return nil, fmt.Errorf("bcql: %w", err)
+	}
+	rows := make([]map[string]any, len(list.Value))
+	for i, r := range list.Value {
+		rows[i] = r
+	}
+	return rows, nil
+}
+
+// Parse parses the statement into a [Query]. The errors are a [*SyntaxError].
+func Parse(stmt string) (Query, error) {
+	tokens, err := lex(stmt)
+	if err != nil {
+		return Query{}, err
+	}
+	p := &parser{tokens: tokens}
+	return p.statement()
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+// accept consumes the next token if it is the keyword or symbol.
+func (p *parser) accept(s string) bool {
+	if p.peek().is(s) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(s string) error {
+	if !p.accept(s) {
+		return p.errorf("expected %s", s)
+	}
+	return nil
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	t := p.peek()
+	msg := fmt.Sprintf(format, args...)
+	if t.kind == tokenEOF {
+		msg += ", got end of statement"
+	} else {
+		msg += fmt.Sprintf(", got %q", t.text)
+	}
+	return &SyntaxError{Pos: t.pos, Msg: msg}
+}
+
+// keywords cannot be used as field names without a clause in between.
+var keywords = []string{"SELECT", "FROM", "EXPAND", "WHERE", "ORDER", "BY", "LIMIT", "OFFSET",
+	"AND", "OR", "NOT", "IS", "IN", "LIKE", "BETWEEN", "ASC", "DESC"}
+
+func (p *parser) ident(what string) (string, error) {
+	t := p.peek()
+	if t.kind != tokenIdent {
+		return "", p.errorf("expected %s", what)
+	}
+	for _, kw := range keywords {
+		if t.is(kw) {
+			return "", p.errorf("expected %s", what)
+		}
+	}
+	p.pos++
+	return t.text, nil
+}
+
+func (p *parser) identList(what string) ([]string, error) {
+	var names []string
+	for {
+		name, err := p.ident(what)
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+		if !p.accept(",") {
+			return names, nil
+		}
+	}
+}
+
+func (p *parser) statement() (Query, error) {
+	var q Query
+
+	if err := p.expect("SELECT"); err != nil {
+		return q, err
+	}
+	if !p.accept("*") {
+		fields, err := p.identList("field")
+		if err != nil {
+			return q, err
+		}
+		q.ListOptions.Select = fields
+	}
+
+	if err := p.expect("FROM"); err != nil {
+		return q, err
+	}
+	name, err := p.ident("entity set")
+	if err != nil {
+		return q, err
+	}
+	q.EntitySetName = name
+
+	if p.accept("EXPAND") {
+		if q.ListOptions.Expand, err = p.identList("field"); err != nil {
+			return q, err
+		}
+	}
+
+	if p.accept("WHERE") {
+		cond, err := p.or()
+		if err != nil {
+			return q, err
+		}
+		q.ListOptions.Filter = cond
+	}
+
+	if p.accept("ORDER") {
+		if err := p.expect("BY"); err != nil {
+			return q, err
+		}
+		for {
+			field, err := p.ident("field")
+			if err != nil {
+				return q, err
+			}
+			if p.accept("DESC") {
+				field += " desc"
+			} else {
+				p.accept("ASC")
+			}
+			q.ListOptions.OrderBy = append(q.ListOptions.OrderBy, field)
+			if !p.accept(",") {
+				break
+			}
+		}
+	}
+
+	if p.accept("LIMIT") {
+		if q.ListOptions.Top, err = p.count(); err != nil {
+			return q, err
+		}
+	}
+	if p.accept("OFFSET") {
+		if q.ListOptions.Skip, err = p.count(); err != nil {
+			return q, err
+		}
+	}
+
+	p.accept(";")
+	if p.peek().kind != tokenEOF {
+		return q, p.errorf("expected end of statement")
+	}
+	return q, nil
+}
+
+func (p *parser) count() (int, error) {
+	t := p.peek()
+	n, err := strconv.Atoi(t.text)
+	if t.kind != tokenNumber || err != nil || n < 0 {
+		return 0, p.errorf("expected a whole number")
+	}
+	p.pos++
+	return n, nil
+}
+
+// or parses the condition. OData has the same precedence of not, and and or as SQL,
+// so the condition is rendered with the parentheses of the statement.
+func (p *parser) or() (string, error) {
+	left, err := p.and()
+	if err != nil {
+		return "", err
+	}
+	for p.accept("OR") {
+		right, err := p.and()
+		if err != nil {
+			return "", err
+		}
+		left += " or " + right
+	}
+	return left, nil
+}
+
+func (p *parser) and() (string, error) {
+	left, err := p.not()
+	if err != nil {
+		return "", err
+	}
+	for p.accept("AND") {
+		right, err := p.not()
+		if err != nil {
+			return "", err
+		}
+		left += " and " + right
+	}
+	return left, nil
+}
+
+func (p *parser) not() (string, error) {
+	if p.accept("NOT") {
+		cond, err := p.not()
+		if err != nil {
+			return "", err
+		}
+		return "not " + cond, nil
+	}
+	return p.primary()
+}
+
+func (p *parser) primary() (string, error) {
+	if p.accept("(") {
+		cond, err := p.or()
+		if err != nil {
+			return "", err
+		}
+		if err := p.expect(")"); err != nil {
+			return "", err
+		}
+		return "(" + cond + ")", nil
+	}
+
+	field, err := p.ident("field")
+	if err != nil {
+		return "", err
+	}
+
+	if p.accept("IS") {
+		op := "eq"
+		if p.accept("NOT") {
+			op = "ne"
+		}
+		if err := p.expect("NULL"); err != nil {
+			return "", err
+		}
+		return field + " " + op + " null", nil
+	}
+
+	if p.accept("BETWEEN") {
+		from, err := p.literal()
+		if err != nil {
+			return "", err
+		}
+		if err := p.expect("AND"); err != nil {
+			return "", err
+		}
+		to, err := p.literal()
+		if err != nil {
+			return "", err
+		}
+		return "(" + field + " ge " + from + " and " + field + " le " + to + ")", nil
+	}
+
+	negate := p.accept("NOT")
+	var cond string
+	switch {
+	case p.accept("IN"):
+		if cond, err = p.in(field); err != nil {
+			return "", err
+		}
+	case p.accept("LIKE"):
+		if cond, err = p.like(field); err != nil {
+			return "", err
+		}
+	case negate:
+		return "", p.errorf("expected IN or LIKE")
+	default:
+		op, ok := operators[p.peek().text]
+		if p.peek().kind != tokenSymbol || !ok {
+			return "", p.errorf("expected an operator")
+		}
+		p.pos++
+		value, err := p.literal()
+		if err != nil {
+			return "", err
+		}
+		cond = field + " " + op + " " + value
+	}
+
+	if negate {
+		cond = "not (" + cond + ")"
+	}
+	return cond, nil
+}
+
+var operators = map[string]string{
+	"=":  "eq",
+	"!=": "ne",
+	"<>": "ne",
+	"<":  "lt",
+	"<=": "le",
+	">":  "gt",
+	">=": "ge",
+}
+
+func (p *parser) in(field string) (string, error) {
+	if err := p.expect("("); err != nil {
+		return "", err
+	}
+	in := filter.InFilter{Field: field}
+	for {
+		value, err := p.literal()
+		if err != nil {
+			return "", err
+		}
+		in.Values = append(in.Values, value)
+		if !p.accept(",") {
+			break
+		}
+	}
+	if err := p.expect(")"); err != nil {
+		return "", err
+	}
+	return in.String(), nil
+}
+
+func (p *parser) like(field string) (string, error) {
+	t := p.peek()
+	if t.kind != tokenString {
+		return "", p.errorf("expected a string pattern")
+	}
+	p.pos++
+
+	pattern := t.text
+	prefix := strings.HasPrefix(pattern, "%")
+	suffix := strings.HasSuffix(pattern, "%") && len(pattern) > 1
+	value := strings.TrimSuffix(strings.TrimPrefix(pattern, "%"), "%")
+	if strings.ContainsAny(value, "%_") {
+		return "", &SyntaxError{Pos: t.pos, Msg: "LIKE only supports % at the start or end of the pattern"}
+	}
+
+	lit := filter.Literal(value)
+	switch {
+	case prefix && suffix:
+		return "contains(" + field + "," + lit + ")", nil
+	case prefix:
+		return "endswith(" + field + "," + lit + ")", nil
+	case suffix:
+		return "startswith(" + field + "," + lit + ")", nil
+	default:
+		return field + " eq " + lit, nil
+	}
+}
+
+// literal parses a value and returns it as an OData literal.
+func (p *parser) literal() (string, error) {
+	t := p.peek()
+	switch {
+	case t.kind == tokenString:
+		p.pos++
+		return filter.Literal(t.text), nil
+	case t.kind == tokenNumber:
+		p.pos++
+		return number(t)
+	case t.is("-"):
+		p.pos++
+		n := p.peek()
+		if n.kind != tokenNumber {
+			return "", p.errorf("expected a number")
+		}
+		p.pos++
+		v, err := number(n)
+		return "-" + v, err
+	case t.is("TRUE"), t.is("FALSE"), t.is("NULL"):
+		p.pos++
+		return strings.ToLower(t.text), nil
+	case t.is("DATE"), t.is("TIMESTAMP"), t.is("GUID"):
+		p.pos++
+		s := p.peek()
+		if s.kind != tokenString {
+			return "", p.errorf("expected a string after %s", strings.ToUpper(t.text))
+		}
+		p.pos++
+		return typedLiteral(strings.ToUpper(t.text), s)
+	}
+	return "", p.errorf("expected a value")
+}
+
+func number(t token) (string, error) {
+	if _, err := strconv.ParseFloat(t.text, 64); err != nil {
+		return "", &SyntaxError{Pos: t.pos, Msg: "invalid number " + t.text}
+	}
+	return t.text, nil
+}
+
+// typedLiteral checks the string of a DATE, TIMESTAMP or GUID and returns the literal.
+func typedLiteral(kind string, t token) (string, error) {
+	var err error
+	var lit string
+	switch kind {
+	case "DATE":
+		var d bc.Date
+		if d, err = bc.ParseDate(t.text); err == nil {
+			lit = filter.Literal(d)
+		}
+	case "TIMESTAMP":
+		var ts time.Time
+		if ts, err = time.Parse(time.RFC3339, t.text); err == nil {
+			lit = filter.Literal(ts)
+		}
+	case "GUID":
+		var id uuid.UUID
+		if id, err = uuid.Parse(t.text); err == nil {
+			lit = filter.Literal(id)
+		}
+	default:
+		err = errors.New("unknown type")
+	}
+	if err != nil {
+		return "", &SyntaxError{Pos: t.pos, Msg: fmt.Sprintf("invalid %s %q", kind, t.text)}
+	}
+	return lit, nil
+}
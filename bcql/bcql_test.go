@@ -0,0 +1,160 @@
+package bcql_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/erlorenz/bc-go/bcfake"
+	"github.com/erlorenz/bc-go/bcql"
+	"github.com/erlorenz/bc-go/internal/bctest"
+	"github.com/google/uuid"
+)
+
+func TestParse(t *testing.T) {
+	q, err := bcql.Parse("SELECT number, displayName FROM customers WHERE blocked = false ORDER BY displayName LIMIT 100")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q.EntitySetName != "customers" {
+		t.Errorf("unexpected entity set %s", q.EntitySetName)
+	}
+	want := bc.ListOptions{
+		Select:  []string{"number", "displayName"},
+		Filter:  "blocked eq false",
+		OrderBy: []string{"displayName"},
+		Top:     100,
+	}
+	if !slices.Equal(q.ListOptions.Select, want.Select) || q.ListOptions.Filter != want.Filter ||
+		!slices.Equal(q.ListOptions.OrderBy, want.OrderBy) || q.ListOptions.Top != want.Top {
+		t.Errorf("wanted %+v, got %+v", want, q.ListOptions)
+	}
+
+	opts := q.RequestOptions()
+	if opts.Method != http.MethodGet || opts.EntitySetName != "customers" {
+		t.Errorf("unexpected request options %+v", opts)
+	}
+	if got := opts.QueryParams.Encode(); got != "$filter=blocked%20eq%20false&$select=number,displayName&$orderby=displayName&$top=100&$skip=0" {
+		t.Errorf("unexpected query %s", got)
+	}
+}
+
+func TestParseClauses(t *testing.T) {
+	q, err := bcql.Parse("select * from salesOrders expand salesOrderLines, customer order by orderDate desc, number asc limit 10 offset 20;")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q.ListOptions.Select != nil || !slices.Equal(q.ListOptions.Expand, []string{"salesOrderLines", "customer"}) ||
+		!slices.Equal(q.ListOptions.OrderBy, []string{"orderDate desc", "number"}) ||
+		q.ListOptions.Top != 10 || q.ListOptions.Skip != 20 {
+		t.Errorf("unexpected options %+v", q.ListOptions)
+	}
+
+	q, err = bcql.Parse("SELECT * FROM customers OFFSET 20")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := q.RequestOptions().QueryParams.Encode(); got != "$skip=20" {
+		t.Errorf("unexpected query %s", got)
+	}
+}
+
+func TestParseWhere(t *testing.T) {
+	id := uuid.MustParse("7d0a3b4e-6d0c-4f4e-9c38-3c7a1f6f4b21")
+	tests := []struct {
+		where string
+		want  string
+	}{
+		{"displayName = 'O''Brien'", "displayName eq 'O''Brien'"},
+		{"balance >= 100.5 AND balance < -2", "balance ge 100.5 and balance lt -2"},
+		{"a <> 1 OR b != 2", "a ne 1 or b ne 2"},
+		{"NOT (a = 1 OR b = 2) AND c <= 3e2", "not (a eq 1 or b eq 2) and c le 3e2"},
+		{"NOT a = 1", "not a eq 1"},
+		{"email IS NULL OR phone IS NOT NULL", "email eq null or phone ne null"},
+		{"number IN ('1000', '2000')", "number in ('1000','2000')"},
+		{"number NOT IN (1)", "not (number in (1))"},
+		{"displayName LIKE 'Ad%'", "startswith(displayName,'Ad')"},
+		{"displayName LIKE '%um'", "endswith(displayName,'um')"},
+		{"displayName NOT LIKE '%at%'", "not (contains(displayName,'at'))"},
+		{"displayName LIKE 'Adatum'", "displayName eq 'Adatum'"},
+		{"postingDate BETWEEN DATE '2024-01-01' AND DATE '2024-01-31'", "(postingDate ge 2024-01-01 and postingDate le 2024-01-31)"},
+		{"lastModifiedDateTime > TIMESTAMP '2024-01-31T12:00:00+01:00'", "lastModifiedDateTime gt 2024-01-31T11:00:00Z"},
+		{"customerId = GUID '" + id.String() + "'", "customerId eq " + id.String()},
+		{"blocked = TRUE", "blocked eq true"},
+		{"dimensionSetLines/code = 'AREA'", "dimensionSetLines/code eq 'AREA'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.where, func(t *testing.T) {
+			q, err := bcql.Parse("SELECT * FROM customers WHERE " + tt.where)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if q.ListOptions.Filter != tt.want {
+				t.Errorf("wanted %s, got %s", tt.want, q.ListOptions.Filter)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		stmt string
+		pos  int
+	}{
+		{"", 0},
+		{"SELECT FROM customers", 7},
+		{"SELECT * customers", 9},
+		{"SELECT * FROM customers WHERE", 29},
+		{"SELECT * FROM customers WHERE a = 'x", 34},
+		{"SELECT * FROM customers WHERE a LIKE 'a%b'", 37},
+		{"SELECT * FROM customers WHERE a = DATE '2024-13-01'", 39},
+		{"SELECT * FROM customers WHERE a NOT = 1", 36},
+		{"SELECT * FROM customers WHERE (a = 1", 36},
+		{"SELECT * FROM customers LIMIT -1", 30},
+		{"SELECT * FROM customers LIMIT 10 extra", 33},
+		{"SELECT * FROM customers WHERE a = 1 # b", 36},
+	}
+	for _, tt := range tests {
+		t.Run(tt.stmt, func(t *testing.T) {
+			_, err := bcql.Parse(tt.stmt)
+			var syntaxErr *bcql.SyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Fatalf("wanted SyntaxError, got %v", err)
+			}
+			if syntaxErr.Pos != tt.pos {
+				t.Errorf("wanted position %d, got %d: %v", tt.pos, syntaxErr.Pos, err)
+			}
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	server := bcfake.New()
+	for range 3 {
+		server.Seed("customers", map[string]any{"displayName": "Adatum"})
+	}
+	client := bctest.NewClient(t, server)
+
+	rows, err := bcql.Run(context.Background(), client, "SELECT * FROM customers LIMIT 2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 2 || rows[0]["displayName"] != "Adatum" {
+		t.Errorf("unexpected rows %v", rows)
+	}
+
+	rows, err = bcql.Run(context.Background(), client, "SELECT * FROM customers OFFSET 2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 1 {
+		t.Errorf("wanted the rows after the offset, got %v", rows)
+	}
+
+	if _, err := bcql.Run(context.Background(), client, "SELECT"); err == nil {
+		t.Error("wanted syntax error")
+	}
+}
@@ -0,0 +1,99 @@
+package bcql
+
+import (
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenString
+	tokenNumber
+	tokenSymbol
+)
+
+type token struct {
+	kind tokenKind
+	// text is the identifier, the unquoted string, the number or the symbol.
+	text string
+	pos  int
+}
+
+// is reports whether the token is the keyword or symbol, ignoring case.
+func (t token) is(s string) bool {
+	return (t.kind == tokenIdent || t.kind == tokenSymbol) && strings.EqualFold(t.text, s)
+}
+
+// lex splits the statement into tokens. Strings are quoted with single quotes,
+// with a quote doubled inside them like in SQL and OData.
+func lex(s string) ([]token, error) {
+	var tokens []token
+	i := 0
+	for i < len(s) {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+
+		case c == '\'':
+			start := i
+			var b strings.Builder
+			i++
+			for {
+				if i >= len(s) {
+					return nil, &SyntaxError{Pos: start, Msg: "unterminated string"}
+				}
+				if s[i] == '\'' {
+					if i+1 < len(s) && s[i+1] == '\'' {
+						b.WriteByte('\'')
+						i += 2
+						continue
+					}
+					i++
+					break
+				}
+				b.WriteByte(s[i])
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenString, text: b.String(), pos: start})
+
+		case isDigit(c) || (c == '.' && i+1 < len(s) && isDigit(s[i+1])):
+			start := i
+			for i < len(s) && (isDigit(s[i]) || s[i] == '.' || s[i] == 'e' || s[i] == 'E' ||
+				((s[i] == '+' || s[i] == '-') && (s[i-1] == 'e' || s[i-1] == 'E'))) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: s[start:i], pos: start})
+
+		case c == '_' || unicode.IsLetter(rune(c)):
+			start := i
+			for i < len(s) && (s[i] == '_' || s[i] == '/' || isDigit(s[i]) || unicode.IsLetter(rune(s[i]))) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, text: s[start:i], pos: start})
+
+		default:
+			start := i
+			if i+1 < len(s) {
+				if two := s[i : i+2]; two == "<=" || two == ">=" || two == "!=" || two == "<>" {
+					tokens = append(tokens, token{kind: tokenSymbol, text: two, pos: start})
+					i += 2
+					continue
+				}
+			}
+			if !strings.ContainsRune("=<>(),*;-", rune(c)) {
+				return nil, &SyntaxError{Pos: start, Msg: "unexpected character " + string(c)}
+			}
+			tokens = append(tokens, token{kind: tokenSymbol, text: string(c), pos: start})
+			i++
+		}
+	}
+	return append(tokens, token{kind: tokenEOF, pos: len(s)}), nil
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
@@ -0,0 +1,200 @@
+// Package bcsentry sends the failures of a [bc.Client] to Sentry with the envelope
+// API, without depending on the Sentry SDK:
+//
+//	reporter, err := bcsentry.New(os.Getenv("SENTRY_DSN"), bcsentry.Options{Environment: "production"})
+//	defer reporter.Flush(5 * time.Second)
+//	client, err := bc.NewClient(config, bc.WithErrorReporter(reporter))
+//
+// Every event has the entity set, method, status and BC error code as tags, so they
+// can be grouped and alerted on, and the correlation id to look the request up in the
+// telemetry of the environment.
+package bcsentry
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/erlorenz/bc-go/bc"
+)
+
+// Options configure [New].
+type Options struct {
+	Environment string
+	Release     string
+	// Tags are added to every event.
+	Tags map[string]string
+	// HTTPClient defaults to a client with a 10 second timeout.
+	HTTPClient *http.Client
+	// OnError is called if an event cannot be sent.
+	OnError func(err error)
+}
+
+// Reporter is a [bc.ErrorReporter] that sends every report as a Sentry event in the
+// background. It is safe for concurrent use.
+type Reporter struct {
+	endpoint string
+	auth     string
+	opts     Options
+	wg       sync.WaitGroup
+}
+
+// New returns a Reporter for the DSN of a Sentry project, e.g.
+// "https://public@o1.ingest.sentry.io/123".
+func New(dsn string, opts Options) (*Reporter, error) {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("invalid DSN: %w", err)
+	}
+	key := u.User.Username()
+	project := u.Path[strings.LastIndex(u.Path, "/")+1:]
+	if key == "" || project == "" || u.Host == "" {
+		return nil, errors.New("invalid DSN: wanted scheme://key@host/project")
+	}
+	if opts.HTTPClient == nil {
+		opts.HTTPClient = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	path := strings.TrimSuffix(u.Path[:len(u.Path)-len(project)], "/")
+	endpoint := url.URL{Scheme: u.Scheme, Host: u.Host, Path: path + "/api/" + project + "/envelope/"}
+	return &Reporter{
+		endpoint: endpoint.String(),
+		auth:     "Sentry sentry_version=7, sentry_client=bc-go/1, sentry_key=" + key,
+		opts:     opts,
+	}, nil
+}
+
+// ReportError implements [bc.ErrorReporter].
+func (r *Reporter) ReportError(ctx context.Context, report bc.ErrorReport) {
+	event := r.event(report)
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		if err := r.send(context.WithoutCancel(ctx), event); err != nil && r.opts.OnError != nil {
+			r.opts.OnError(err)
+		}
+	}()
+}
+
+// Flush waits until the events reported so far are sent, or the timeout. It returns
+// false on a timeout.
+func (r *Reporter) Flush(timeout time.Duration) bool {
+	done := make(chan struct{})
+	go func() {
+		r.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
+
+// event is the subset of the Sentry event payload that is sent.
+type event struct {
+	EventID     string            `json:"event_id"`
+	Timestamp   string            `json:"timestamp"`
+	Platform    string            `json:"platform"`
+	Level       string            `json:"level"`
+	Logger      string            `json:"logger"`
+	Environment string            `json:"environment,omitempty"`
+	Release     string            `json:"release,omitempty"`
+	Message     string            `json:"message"`
+	Fingerprint []string          `json:"fingerprint"`
+	Tags        map[string]string `json:"tags"`
+	Extra       map[string]any    `json:"extra"`
+	Exception   struct {
+		Values []exception `json:"values"`
+	} `json:"exception"`
+}
+
+type exception struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+func (r *Reporter) event(report bc.ErrorReport) event {
+	id := make([]byte, 16)
+	rand.Read(id)
+
+	e := event{
+		EventID:     hex.EncodeToString(id),
+		Timestamp:   time.Now().UTC().Format(time.RFC3339Nano),
+		Platform:    "go",
+		Level:       "error",
+		Logger:      "bc",
+		Environment: r.opts.Environment,
+		Release:     r.opts.Release,
+		Tags:        map[string]string{"bc.method": report.Method},
+		Extra:       map[string]any{"url": report.URL},
+	}
+	for k, v := range r.opts.Tags {
+		e.Tags[k] = v
+	}
+	if report.EntitySetName != "" {
+		e.Tags["bc.entity"] = report.EntitySetName
+	}
+	if report.StatusCode != 0 {
+		e.Tags["bc.status"] = strconv.Itoa(report.StatusCode)
+	}
+	if report.Code != "" {
+		e.Tags["bc.code"] = string(report.Code)
+	}
+	if report.RecordID != "" {
+		e.Extra["recordId"] = report.RecordID
+	}
+	if report.CorrelationID != "" {
+		e.Tags["bc.correlation_id"] = report.CorrelationID
+	}
+
+	// Group by what failed rather than by the message, which has record values.
+	typ := "bc.RequestError"
+	if report.StatusCode != 0 {
+		typ = "bc.APIError"
+	}
+	e.Fingerprint = []string{typ, report.Method, report.EntitySetName, string(report.Code)}
+	if report.Err != nil {
+		e.Message = report.Err.Error()
+	}
+	e.Exception.Values = []exception{{Type: typ, Value: e.Message}}
+	return e
+}
+
+// send posts the event in an envelope.
+func (r *Reporter) send(ctx context.Context, e event) error {
+	var body bytes.Buffer
+	enc := json.NewEncoder(&body)
+	enc.Encode(map[string]string{"event_id": e.EventID, "sent_at": e.Timestamp})
+	enc.Encode(map[string]string{"type": "event"})
+	if err := enc.Encode(e); err != nil {
+		return fmt.Errorf("send event: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.endpoint, &body)
+	if err != nil {
+		return fmt.Errorf("send event: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-sentry-envelope")
+	req.Header.Set("X-Sentry-Auth", r.auth)
+	res, err := r.opts.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("send event: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode >= 300 {
+		return fmt.Errorf("send event: status %d", res.StatusCode)
+	}
+	return nil
+}
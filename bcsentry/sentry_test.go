@@ -0,0 +1,82 @@
+package bcsentry_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/erlorenz/bc-go/bcsentry"
+)
+
+func TestReporter(t *testing.T) {
+	var mu sync.Mutex
+	var events []map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/42/envelope/" || !strings.Contains(r.Header.Get("X-Sentry-Auth"), "sentry_key=public") {
+			t.Errorf("unexpected request %s %v", r.URL.Path, r.Header)
+		}
+		// The envelope header, the item header and the event
+		scanner := bufio.NewScanner(r.Body)
+		var lines []string
+		for scanner.Scan() {
+			lines = append(lines, scanner.Text())
+		}
+		if len(lines) != 3 {
+			t.Errorf("wanted 3 lines, got %q", lines)
+			return
+		}
+		var e map[string]any
+		json.Unmarshal([]byte(lines[2]), &e)
+		mu.Lock()
+		events = append(events, e)
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	dsn := strings.Replace(server.URL, "://", "://public@", 1) + "/42"
+	reporter, err := bcsentry.New(dsn, bcsentry.Options{Environment: "test", Tags: map[string]string{"service": "orders"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reporter.ReportError(context.Background(), bc.ErrorReport{
+		Method:        http.MethodPost,
+		EntitySetName: "salesOrders",
+		StatusCode:    http.StatusBadRequest,
+		Code:          "BadRequest",
+		CorrelationID: "9f1c3e4a-7b2d-4c8e-a5f6-0d1e2f3a4b5c",
+		Err:           errors.New("[400 BadRequest] Customer does not exist."),
+	})
+	if !reporter.Flush(5 * time.Second) {
+		t.Fatal("flush timed out")
+	}
+
+	if len(events) != 1 {
+		t.Fatalf("wanted 1 event, got %d", len(events))
+	}
+	e := events[0]
+	tags, _ := e["tags"].(map[string]any)
+	if tags["bc.entity"] != "salesOrders" || tags["bc.status"] != "400" || tags["bc.code"] != "BadRequest" ||
+		tags["bc.correlation_id"] != "9f1c3e4a-7b2d-4c8e-a5f6-0d1e2f3a4b5c" || tags["service"] != "orders" {
+		t.Errorf("unexpected tags %v", tags)
+	}
+	if e["environment"] != "test" || e["message"] != "[400 BadRequest] Customer does not exist." {
+		t.Errorf("unexpected event %v", e)
+	}
+}
+
+func TestNewInvalidDSN(t *testing.T) {
+	for _, dsn := range []string{"", "https://o1.ingest.sentry.io/42", "https://public@o1.ingest.sentry.io/"} {
+		if _, err := bcsentry.New(dsn, bcsentry.Options{}); err == nil {
+			t.Errorf("wanted an error for %q", dsn)
+		}
+	}
+}
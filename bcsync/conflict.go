@@ -0,0 +1,63 @@
+package bcsync
+
+import "context"
+
+// Resolution is the outcome of a conflict.
+type Resolution int
+
+const (
+	// KeepRemote discards the local change and saves the BC version locally.
+	KeepRemote Resolution = iota
+	// KeepLocal overwrites the BC version with the local change.
+	KeepLocal
+	// Merged pushes the merged record returned by the policy.
+	Merged
+)
+
+// ConflictPolicy decides what happens when a local change conflicts with a newer
+// version in BC, or the record was deleted in BC (remote is nil).
+type ConflictPolicy[T any] interface {
+	Resolve(ctx context.Context, local Change[T], remote *T) (Resolution, T, error)
+}
+
+// ConflictFunc adapts a function to a [ConflictPolicy].
+type ConflictFunc[T any] func(ctx context.Context, local Change[T], remote *T) (Resolution, T, error)
+
+// Resolve calls f.
+func (f ConflictFunc[T]) Resolve(ctx context.Context, local Change[T], remote *T) (Resolution, T, error) {
+	return f(ctx, local, remote)
+}
+
+// BCWins always keeps the BC version.
+func BCWins[T any]() ConflictPolicy[T] {
+	return ConflictFunc[T](func(_ context.Context, _ Change[T], remote *T) (Resolution, T, error) {
+		var v T
+		if remote != nil {
+			v = *remote
+		}
+		return KeepRemote, v, nil
+	})
+}
+
+// LocalWins always overwrites BC with the local change.
+func LocalWins[T any]() ConflictPolicy[T] {
+	return ConflictFunc[T](func(_ context.Context, local Change[T], _ *T) (Resolution, T, error) {
+		return KeepLocal, local.Record, nil
+	})
+}
+
+// Merge pushes the result of the merge function. If the record was deleted in BC
+// the BC version wins.
+func Merge[T any](merge func(local T, remote T) (T, error)) ConflictPolicy[T] {
+	return ConflictFunc[T](func(_ context.Context, local Change[T], remote *T) (Resolution, T, error) {
+		if remote == nil {
+			var v T
+			return KeepRemote, v, nil
+		}
+		v, err := merge(local.Record, *remote)
+		if err != nil {
+			return KeepRemote, v, err
+		}
+		return Merged, v, nil
+	})
+}
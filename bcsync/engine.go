@@ -0,0 +1,289 @@
+package bcsync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/google/uuid"
+)
+
+// Engine syncs an entity set with a [Store].
+type Engine[T bc.Validator] struct {
+	// Page is the entity set in BC.
+	Page *bc.APIPage[T]
+	// Store is the local side.
+	Store Store[T]
+	// Checkpoints persists the delta link between pulls.
+	Checkpoints CheckpointStore
+	// Policy resolves conflicts when pushing. Defaults to BCWins.
+	Policy ConflictPolicy[T]
+	// Key is the checkpoint key. Defaults to the entity set name.
+	Key string
+	// ID returns the BC id of a record.
+	ID func(T) uuid.UUID
+	// ETag returns the "@odata.etag" of a record.
+	ETag func(T) string
+	// Payload converts a record to the body of a create or update, e.g. to leave out
+	// read-only fields. Defaults to the record itself.
+	Payload func(T) any
+	// ListOptions are used for the initial pull, e.g. a filter or select.
+	ListOptions bc.ListOptions
+}
+
+// PullResult counts the changes applied by [Engine.Pull].
+type PullResult struct {
+	Upserted int
+	Removed  int
+	Pages    int
+}
+
+// PushResult counts the changes applied by [Engine.Push].
+type PushResult struct {
+	Created   int
+	Updated   int
+	Deleted   int
+	Conflicts int
+}
+
+func (e *Engine[T]) validate() error {
+	var errs []error
+	if e.Page == nil {
+		errs = append(errs, errors.New("Page is nil"))
+	}
+	if e.Store == nil {
+		errs = append(errs, errors.New("Store is nil"))
+	}
+	if e.Checkpoints == nil {
+		errs = append(errs, errors.New("Checkpoints is nil"))
+	}
+	if e.ID == nil {
+		errs = append(errs, errors.New("ID is nil"))
+	}
+	if e.ETag == nil {
+		errs = append(errs, errors.New("ETag is nil"))
+	}
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("invalid engine: %w", err)
+	}
+	return nil
+}
+
+func (e *Engine[T]) key() string {
+	if e.Key != "" {
+		return e.Key
+	}
+	return e.Page.EntitySetName()
+}
+
+func (e *Engine[T]) payload(record T) any {
+	if e.Payload != nil {
+		return e.Payload(record)
+	}
+	return record
+}
+
+func (e *Engine[T]) policy() ConflictPolicy[T] {
+	if e.Policy != nil {
+		return e.Policy
+	}
+	return BCWins[T]()
+}
+
+// Pull applies the changes in BC since the last checkpoint to the store.
+// The first pull loads every record. The checkpoint is saved after each page so an
+// interrupted pull resumes from the last page applied.
+func (e *Engine[T]) Pull(ctx context.Context) (PullResult, error) {
+	var result PullResult
+	if err := e.validate(); err != nil {
+		return result, err
+	}
+
+	link, err := e.Checkpoints.LoadCheckpoint(ctx, e.key())
+	if err != nil && !errors.Is(err, ErrNoCheckpoint) {
+		return result, fmt.Errorf("load checkpoint: %w", err)
+	}
+
+	for {
+		page, err := e.Page.Delta(ctx, link, e.ListOptions)
+		if err != nil {
+			return result, fmt.Errorf("pull: %w", err)
+		}
+		result.Pages++
+
+		for _, record := range page.Records {
+			if err := e.Store.Upsert(ctx, record); err != nil {
+				return result, fmt.Errorf("upsert %s: %w", e.ID(record), err)
+			}
+			result.Upserted++
+		}
+		for _, id := range page.Deleted {
+			if err := e.Store.Remove(ctx, id); err != nil {
+				return result, fmt.Errorf("remove %s: %w", id, err)
+			}
+			result.Removed++
+		}
+
+		link = page.Link()
+		if link == "" {
+			return result, errors.New("pull: response has no nextLink or deltaLink")
+		}
+		if err := e.Checkpoints.SaveCheckpoint(ctx, e.key(), link); err != nil {
+			return result, fmt.Errorf("save checkpoint: %w", err)
+		}
+
+		if page.NextLink == "" {
+			return result, nil
+		}
+	}
+}
+
+// Push sends the pending changes in the store to BC. Updates and deletes use the ETag
+// of the change, and conflicts are resolved with the Policy.
+func (e *Engine[T]) Push(ctx context.Context) (PushResult, error) {
+	var result PushResult
+	if err := e.validate(); err != nil {
+		return result, err
+	}
+
+	changes, err := e.Store.Pending(ctx)
+	if err != nil {
+		return result, fmt.Errorf("pending: %w", err)
+	}
+
+	for _, change := range changes {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		if err := e.push(ctx, change, &result); err != nil {
+			return result, fmt.Errorf("push %s %s: %w", change.Op, change.ID, err)
+		}
+	}
+	return result, nil
+}
+
+func (e *Engine[T]) push(ctx context.Context, change Change[T], result *PushResult) error {
+	var zero T
+
+	switch change.Op {
+	case OpCreate:
+		v, err := e.Page.Create(ctx, e.payload(change.Record), bc.GetOptions{})
+		if err != nil {
+			return err
+		}
+		result.Created++
+		return e.Store.Ack(ctx, change, v)
+
+	case OpUpdate:
+		v, err := e.update(ctx, change.ID, change.Record, change.ETag)
+		if err == nil {
+			result.Updated++
+			return e.Store.Ack(ctx, change, v)
+		}
+		if !errors.Is(err, bc.ErrPreconditionFailed) && !errors.Is(err, bc.ErrNotFound) {
+			return err
+		}
+		result.Conflicts++
+		return e.resolve(ctx, change, result)
+
+	case OpDelete:
+		var err error
+		if change.ETag != "" {
+			err = e.Page.DeleteIfMatch(ctx, change.ID, change.ETag)
+		} else {
+			_, err = e.Page.DeleteIfExists(ctx, change.ID)
+		}
+		if errors.Is(err, bc.ErrNotFound) {
+			err = nil
+		}
+		if err == nil {
+			result.Deleted++
+			return e.Store.Ack(ctx, change, zero)
+		}
+		if !errors.Is(err, bc.ErrPreconditionFailed) {
+			return err
+		}
+		result.Conflicts++
+		return e.resolve(ctx, change, result)
+	}
+
+	return fmt.Errorf("unknown op %q", change.Op)
+}
+
+// resolve fetches the current BC version and applies the policy.
+func (e *Engine[T]) resolve(ctx context.Context, change Change[T], result *PushResult) error {
+	var zero T
+
+	remote, found, err := e.Page.GetOptional(ctx, change.ID, bc.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("get remote: %w", err)
+	}
+	var remotePtr *T
+	if found {
+		remotePtr = &remote
+	}
+
+	resolution, record, err := e.policy().Resolve(ctx, change, remotePtr)
+	if err != nil {
+		return fmt.Errorf("resolve conflict: %w", err)
+	}
+
+	switch resolution {
+	case KeepRemote:
+		if found {
+			if err := e.Store.Upsert(ctx, remote); err != nil {
+				return err
+			}
+		} else if err := e.Store.Remove(ctx, change.ID); err != nil {
+			return err
+		}
+		return e.Store.Ack(ctx, change, zero)
+
+	case KeepLocal, Merged:
+		if change.Op == OpDelete {
+			if found {
+				if err := e.Page.DeleteIfMatch(ctx, change.ID, e.ETag(remote)); err != nil {
+					return err
+				}
+			}
+			result.Deleted++
+			return e.Store.Ack(ctx, change, zero)
+		}
+
+		if !found {
+			v, err := e.Page.Create(ctx, e.payload(record), bc.GetOptions{})
+			if err != nil {
+				return err
+			}
+			result.Created++
+			return e.Store.Ack(ctx, change, v)
+		}
+
+		v, err := e.update(ctx, change.ID, record, e.ETag(remote))
+		if err != nil {
+			return err
+		}
+		result.Updated++
+		return e.Store.Ack(ctx, change, v)
+	}
+
+	return fmt.Errorf("unknown resolution %d", resolution)
+}
+
+func (e *Engine[T]) update(ctx context.Context, id uuid.UUID, record T, etag string) (T, error) {
+	if etag == "" {
+		return e.Page.Update(ctx, id, nil, e.payload(record))
+	}
+	return e.Page.UpdateIfMatch(ctx, id, nil, e.payload(record), etag)
+}
+
+// Sync calls Pull and then Push.
+func (e *Engine[T]) Sync(ctx context.Context) (PullResult, PushResult, error) {
+	pull, err := e.Pull(ctx)
+	if err != nil {
+		return pull, PushResult{}, err
+	}
+	push, err := e.Push(ctx)
+	return pull, push, err
+}
@@ -0,0 +1,155 @@
+package bcsync_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/erlorenz/bc-go/bcsync"
+	"github.com/erlorenz/bc-go/internal/bctest"
+	"github.com/google/uuid"
+)
+
+type record struct {
+	ETag string    `json:"@odata.etag,omitempty"`
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+	// Modified is only used by Incremental.
+	Modified time.Time `json:"lastModifiedDateTime"`
+}
+
+func (r record) Validate() error { return nil }
+
+type memoryStore struct {
+	records map[uuid.UUID]record
+	pending []bcsync.Change[record]
+	acked   []record
+}
+
+func (m *memoryStore) Upsert(_ context.Context, r record) error {
+	m.records[r.ID] = r
+	return nil
+}
+
+func (m *memoryStore) Remove(_ context.Context, id uuid.UUID) error {
+	delete(m.records, id)
+	return nil
+}
+
+func (m *memoryStore) Pending(context.Context) ([]bcsync.Change[record], error) {
+	return m.pending, nil
+}
+
+func (m *memoryStore) Ack(_ context.Context, _ bcsync.Change[record], result record) error {
+	m.acked = append(m.acked, result)
+	return nil
+}
+
+const baseURL = "https://api.businesscentral.dynamics.com/v2.0"
+
+func newEngine(t *testing.T, st *bctest.SequenceTransport, policy bcsync.ConflictPolicy[record]) (*bcsync.Engine[record], *memoryStore) {
+	t.Helper()
+	client := bctest.NewClient(t, st)
+
+	store := &memoryStore{records: map[uuid.UUID]record{}}
+	return &bcsync.Engine[record]{
+		Page:        bc.NewAPIPage[record](client, "customers"),
+		Store:       store,
+		Checkpoints: &bcsync.MemoryCheckpoints{},
+		Policy:      policy,
+		ID:          func(r record) uuid.UUID { return r.ID },
+		ETag:        func(r record) string { return r.ETag },
+	}, store
+}
+
+func TestEnginePull(t *testing.T) {
+	id1, id2 := uuid.New(), uuid.New()
+	st := &bctest.SequenceTransport{Responses: []*http.Response{
+		bctest.NewResponse(200, map[string]any{
+			"value":           []record{{ID: id1, Name: "one"}},
+			"@odata.nextLink": baseURL + "/next",
+		}),
+		bctest.NewResponse(200, map[string]any{
+			"value":            []record{{ID: id2, Name: "two"}},
+			"@odata.deltaLink": baseURL + "/delta1",
+		}),
+		bctest.NewResponse(200, map[string]any{
+			"value":            []map[string]any{{"id": id1, "reason": "deleted"}},
+			"@odata.deltaLink": baseURL + "/delta2",
+		}),
+	}}
+	engine, store := newEngine(t, st, nil)
+
+	result, err := engine.Pull(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Pages != 2 || result.Upserted != 2 || len(store.records) != 2 {
+		t.Fatalf("unexpected result %+v", result)
+	}
+
+	cp, _ := engine.Checkpoints.LoadCheckpoint(context.Background(), "customers")
+	if cp != baseURL+"/delta1" {
+		t.Errorf("unexpected checkpoint %s", cp)
+	}
+
+	result, err = engine.Pull(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Removed != 1 || len(store.records) != 1 {
+		t.Errorf("unexpected result %+v", result)
+	}
+	if st.Requests[2].URL.Path != "/v2.0/delta1" {
+		t.Errorf("expected pull from delta link, got %s", st.Requests[2].URL.Path)
+	}
+}
+
+func conflictResponses(id uuid.UUID, extra ...*http.Response) []*http.Response {
+	return append([]*http.Response{
+		bctest.NewResponse(http.StatusPreconditionFailed, bc.ErrorResponse{Error: bc.ErrorResponseError{Code: "Request_EntityChanged", Message: "changed"}}),
+		bctest.NewResponse(200, record{ID: id, ETag: "remote", Name: "remote"}),
+	}, extra...)
+}
+
+func TestEnginePushBCWins(t *testing.T) {
+	id := uuid.New()
+	st := &bctest.SequenceTransport{Responses: conflictResponses(id)}
+	engine, store := newEngine(t, st, bcsync.BCWins[record]())
+	store.pending = []bcsync.Change[record]{{ID: id, Op: bcsync.OpUpdate, ETag: "local", Record: record{ID: id, Name: "local"}}}
+
+	result, err := engine.Push(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Conflicts != 1 || store.records[id].Name != "remote" {
+		t.Errorf("unexpected result %+v %+v", result, store.records)
+	}
+	if got := st.Requests[0].Header.Get("If-Match"); got != "local" {
+		t.Errorf("wanted If-Match local, got %s", got)
+	}
+}
+
+func TestEnginePushMerge(t *testing.T) {
+	id := uuid.New()
+	st := &bctest.SequenceTransport{Responses: conflictResponses(id, bctest.NewResponse(200, record{ID: id, ETag: "new", Name: "local+remote"}))}
+
+	merge := bcsync.Merge(func(local, remote record) (record, error) {
+		return record{ID: local.ID, Name: local.Name + "+" + remote.Name}, nil
+	})
+	engine, store := newEngine(t, st, merge)
+	store.pending = []bcsync.Change[record]{{ID: id, Op: bcsync.OpUpdate, ETag: "local", Record: record{ID: id, Name: "local"}}}
+
+	result, err := engine.Push(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Updated != 1 || len(store.acked) != 1 || store.acked[0].Name != "local+remote" {
+		t.Errorf("unexpected result %+v %+v", result, store.acked)
+	}
+	if got := st.Requests[2].Header.Get("If-Match"); got != "remote" {
+		t.Errorf("wanted If-Match remote, got %s", got)
+	}
+}
@@ -0,0 +1,173 @@
+package bcsync
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/google/uuid"
+)
+
+// DefaultOverlap is the clock-skew window used by [Incremental] when Overlap is 0.
+const DefaultOverlap = 5 * time.Second
+
+// Incremental pulls records modified since the last pull by filtering on the
+// last modified field, for entity sets that do not support delta links.
+// Deletes are not detected.
+//
+// Records committed with a timestamp slightly before the high-water mark can
+// appear after it was read, so each pull re-reads an Overlap window before the mark
+// and skips the records in it that were already applied.
+type Incremental[T bc.Validator] struct {
+	// Page is the entity set in BC.
+	Page *bc.APIPage[T]
+	// Store is the local side. Only Upsert is used.
+	Store Store[T]
+	// Checkpoints persists the high-water mark between pulls.
+	Checkpoints CheckpointStore
+	// Key is the checkpoint key. Defaults to "incremental:" + the entity set name.
+	Key string
+	// ID returns the BC id of a record.
+	ID func(T) uuid.UUID
+	// LastModified returns the value of Field for a record.
+	LastModified func(T) time.Time
+	// Field is the filtered field. Defaults to "lastModifiedDateTime".
+	Field string
+	// Overlap defaults to DefaultOverlap.
+	Overlap time.Duration
+	// ListOptions are combined with the filter, e.g. to select fields.
+	// OrderBy is replaced by Field.
+	ListOptions bc.ListOptions
+}
+
+// incrementalCheckpoint is saved as JSON.
+type incrementalCheckpoint struct {
+	HighWaterMark time.Time `json:"highWaterMark"`
+	// Seen are the records applied within the overlap window of the mark.
+	Seen map[uuid.UUID]time.Time `json:"seen,omitempty"`
+}
+
+func (inc *Incremental[T]) validate() error {
+	var errs []error
+	if inc.Page == nil {
+		errs = append(errs, errors.New("Page is nil"))
+	}
+	if inc.Store == nil {
+		errs = append(errs, errors.New("Store is nil"))
+	}
+	if inc.Checkpoints == nil {
+		errs = append(errs, errors.New("Checkpoints is nil"))
+	}
+	if inc.ID == nil {
+		errs = append(errs, errors.New("ID is nil"))
+	}
+	if inc.LastModified == nil {
+		errs = append(errs, errors.New("LastModified is nil"))
+	}
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("invalid incremental: %w", err)
+	}
+	return nil
+}
+
+func (inc *Incremental[T]) key() string {
+	if inc.Key != "" {
+		return inc.Key
+	}
+	return "incremental:" + inc.Page.EntitySetName()
+}
+
+func (inc *Incremental[T]) field() string {
+	if inc.Field != "" {
+		return inc.Field
+	}
+	return "lastModifiedDateTime"
+}
+
+func (inc *Incremental[T]) overlap() time.Duration {
+	if inc.Overlap > 0 {
+		return inc.Overlap
+	}
+	return DefaultOverlap
+}
+
+// Pull upserts the records modified since the last checkpoint. The first pull loads
+// every record. The checkpoint is saved after each page.
+// Upserted in the result does not include records skipped as duplicates.
+func (inc *Incremental[T]) Pull(ctx context.Context) (PullResult, error) {
+	var result PullResult
+	if err := inc.validate(); err != nil {
+		return result, err
+	}
+
+	var cp incrementalCheckpoint
+	v, err := inc.Checkpoints.LoadCheckpoint(ctx, inc.key())
+	switch {
+	case errors.Is(err, ErrNoCheckpoint):
+	case err != nil:
+		return result, fmt.Errorf("load checkpoint: %w", err)
+	default:
+		if err := json.Unmarshal([]byte(v), &cp); err != nil {
+			return result, fmt.Errorf("invalid checkpoint: %w", err)
+		}
+	}
+	if cp.Seen == nil {
+		cp.Seen = map[uuid.UUID]time.Time{}
+	}
+
+	opts := inc.ListOptions
+	opts.OrderBy = []string{inc.field()}
+	if !cp.HighWaterMark.IsZero() {
+		filter := fmt.Sprintf("%s gt %s", inc.field(), cp.HighWaterMark.Add(-inc.overlap()).UTC().Format(time.RFC3339Nano))
+		if opts.Filter != "" {
+			filter = fmt.Sprintf("(%s) and %s", opts.Filter, filter)
+		}
+		opts.Filter = filter
+	}
+
+	link := ""
+	for {
+		page, err := inc.Page.ListPage(ctx, link, opts)
+		if err != nil {
+			return result, fmt.Errorf("pull: %w", err)
+		}
+		result.Pages++
+
+		for _, record := range page.Value {
+			id, modified := inc.ID(record), inc.LastModified(record)
+			if seen, ok := cp.Seen[id]; ok && !modified.After(seen) {
+				continue
+			}
+			if err := inc.Store.Upsert(ctx, record); err != nil {
+				return result, fmt.Errorf("upsert %s: %w", id, err)
+			}
+			result.Upserted++
+
+			cp.Seen[id] = modified
+			if modified.After(cp.HighWaterMark) {
+				cp.HighWaterMark = modified
+			}
+		}
+
+		// Only keep the records that can be read again in the next overlap window.
+		cutoff := cp.HighWaterMark.Add(-inc.overlap())
+		for id, modified := range cp.Seen {
+			if !modified.After(cutoff) {
+				delete(cp.Seen, id)
+			}
+		}
+
+		b, _ := json.Marshal(cp)
+		if err := inc.Checkpoints.SaveCheckpoint(ctx, inc.key(), string(b)); err != nil {
+			return result, fmt.Errorf("save checkpoint: %w", err)
+		}
+
+		link = page.NextLink
+		if link == "" {
+			return result, nil
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package bcsync_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/erlorenz/bc-go/bcsync"
+	"github.com/erlorenz/bc-go/internal/bctest"
+	"github.com/google/uuid"
+)
+
+func TestIncrementalPull(t *testing.T) {
+	id1, id2, id3 := uuid.New(), uuid.New(), uuid.New()
+	t1 := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	t2 := t1.Add(time.Minute)
+
+	st := &bctest.SequenceTransport{Responses: []*http.Response{
+		bctest.NewResponse(200, map[string]any{
+			"value":           []record{{ID: id1, Modified: t1}},
+			"@odata.nextLink": baseURL + "/next",
+		}),
+		bctest.NewResponse(200, map[string]any{
+			"value": []record{{ID: id2, Modified: t2}},
+		}),
+		// id2 is read again in the overlap window, id3 was committed late with an earlier timestamp.
+		bctest.NewResponse(200, map[string]any{
+			"value": []record{{ID: id3, Modified: t2.Add(-time.Second)}, {ID: id2, Modified: t2}},
+		}),
+	}}
+
+	store := &memoryStore{records: map[uuid.UUID]record{}}
+	inc := &bcsync.Incremental[record]{
+		Page:         bc.NewAPIPage[record](bctest.NewClient(t, st), "customers"),
+		Store:        store,
+		Checkpoints:  &bcsync.MemoryCheckpoints{},
+		ID:           func(r record) uuid.UUID { return r.ID },
+		LastModified: func(r record) time.Time { return r.Modified },
+	}
+
+	result, err := inc.Pull(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Pages != 2 || result.Upserted != 2 {
+		t.Fatalf("unexpected result %+v", result)
+	}
+	if filter := st.Requests[0].URL.Query().Get("$filter"); filter != "" {
+		t.Errorf("expected no filter on first pull, got %s", filter)
+	}
+
+	result, err = inc.Pull(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Upserted != 1 || len(store.records) != 3 {
+		t.Errorf("expected only id3 upserted, got %+v", result)
+	}
+
+	filter := st.Requests[2].URL.Query().Get("$filter")
+	want := "lastModifiedDateTime gt " + t2.Add(-bcsync.DefaultOverlap).Format(time.RFC3339Nano)
+	if !strings.Contains(filter, want) {
+		t.Errorf("expected filter %q, got %q", want, filter)
+	}
+}
@@ -0,0 +1,129 @@
+package bcsync
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/google/uuid"
+)
+
+// Snapshot is the hash of every record in an entity set by id.
+// Compare two snapshots with [Snapshot.Diff] to detect changes in entity sets
+// without delta links or a last modified field.
+type Snapshot map[uuid.UUID]string
+
+// Diff is the difference between two snapshots. The ids are sorted.
+type Diff struct {
+	Created []uuid.UUID
+	Updated []uuid.UUID
+	Deleted []uuid.UUID
+}
+
+// Empty returns true if nothing changed.
+func (d Diff) Empty() bool {
+	return len(d.Created) == 0 && len(d.Updated) == 0 && len(d.Deleted) == 0
+}
+
+// TakeSnapshot lists every record in the page and hashes it.
+// Use opts.Select to only detect changes in some fields.
+func TakeSnapshot[T bc.Validator](ctx context.Context, page *bc.APIPage[T], id func(T) uuid.UUID, opts bc.ListOptions) (Snapshot, error) {
+	snapshot := Snapshot{}
+
+	link := ""
+	for {
+		list, err := page.ListPage(ctx, link, opts)
+		if err != nil {
+			return nil, fmt.Errorf("snapshot: %w", err)
+		}
+
+		for _, record := range list.Value {
+			hash, err := HashRecord(record)
+			if err != nil {
+				return nil, fmt.Errorf("snapshot %s: %w", id(record), err)
+			}
+			snapshot[id(record)] = hash
+		}
+
+		link = list.NextLink
+		if link == "" {
+			return snapshot, nil
+		}
+	}
+}
+
+// HashRecord returns the hex sha256 of the JSON of the record, leaving out "@odata.etag"
+// so only changes to the fields are detected.
+func HashRecord(record any) (string, error) {
+	b, err := json.Marshal(record)
+	if err != nil {
+		return "", err
+	}
+
+	// Re-encode objects so the etag is dropped and the keys are sorted.
+	if bytes.HasPrefix(b, []byte("{")) {
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(b, &fields); err != nil {
+			return "", err
+		}
+		delete(fields, "@odata.etag")
+		if b, err = json.Marshal(fields); err != nil {
+			return "", err
+		}
+	}
+
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Diff returns the records created, updated and deleted in later since s was taken.
+func (s Snapshot) Diff(later Snapshot) Diff {
+	var d Diff
+	for id, hash := range later {
+		prev, ok := s[id]
+		switch {
+		case !ok:
+			d.Created = append(d.Created, id)
+		case prev != hash:
+			d.Updated = append(d.Updated, id)
+		}
+	}
+	for id := range s {
+		if _, ok := later[id]; !ok {
+			d.Deleted = append(d.Deleted, id)
+		}
+	}
+
+	for _, ids := range [][]uuid.UUID{d.Created, d.Updated, d.Deleted} {
+		slices.SortFunc(ids, func(a, b uuid.UUID) int { return bytes.Compare(a[:], b[:]) })
+	}
+	return d
+}
+
+// LoadSnapshot loads a snapshot saved with [SaveSnapshot].
+// It returns ErrNoCheckpoint if there is none.
+func LoadSnapshot(ctx context.Context, store CheckpointStore, key string) (Snapshot, error) {
+	v, err := store.LoadCheckpoint(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	var s Snapshot
+	if err := json.Unmarshal([]byte(v), &s); err != nil {
+		return nil, fmt.Errorf("invalid snapshot: %w", err)
+	}
+	return s, nil
+}
+
+// SaveSnapshot saves the snapshot as JSON in the store.
+func SaveSnapshot(ctx context.Context, store CheckpointStore, key string, s Snapshot) error {
+	b, err := json.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("marshal snapshot: %w", err)
+	}
+	return store.SaveCheckpoint(ctx, key, string(b))
+}
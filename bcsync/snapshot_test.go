@@ -0,0 +1,65 @@
+package bcsync_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/erlorenz/bc-go/bcsync"
+	"github.com/erlorenz/bc-go/internal/bctest"
+	"github.com/google/uuid"
+)
+
+func TestSnapshotDiff(t *testing.T) {
+	kept, updated, deleted, created := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+
+	st := &bctest.SequenceTransport{Responses: []*http.Response{
+		bctest.NewResponse(200, map[string]any{
+			"value":           []record{{ID: kept, Name: "kept", ETag: "1"}, {ID: updated, Name: "before"}},
+			"@odata.nextLink": baseURL + "/next",
+		}),
+		bctest.NewResponse(200, map[string]any{"value": []record{{ID: deleted, Name: "deleted"}}}),
+		bctest.NewResponse(200, map[string]any{
+			"value": []record{{ID: kept, Name: "kept", ETag: "2"}, {ID: updated, Name: "after"}, {ID: created, Name: "new"}},
+		}),
+	}}
+	page := bc.NewAPIPage[record](bctest.NewClient(t, st), "customers")
+	id := func(r record) uuid.UUID { return r.ID }
+
+	before, err := bcsync.TakeSnapshot(context.Background(), page, id, bc.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(before) != 3 {
+		t.Fatalf("wanted 3 records, got %d", len(before))
+	}
+
+	cps := &bcsync.MemoryCheckpoints{}
+	if _, err := bcsync.LoadSnapshot(context.Background(), cps, "customers"); !errors.Is(err, bcsync.ErrNoCheckpoint) {
+		t.Errorf("wanted ErrNoCheckpoint, got %v", err)
+	}
+	if err := bcsync.SaveSnapshot(context.Background(), cps, "customers", before); err != nil {
+		t.Fatal(err)
+	}
+	before, err = bcsync.LoadSnapshot(context.Background(), cps, "customers")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	after, err := bcsync.TakeSnapshot(context.Background(), page, id, bc.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d := before.Diff(after)
+	if !slices.Equal(d.Created, []uuid.UUID{created}) || !slices.Equal(d.Updated, []uuid.UUID{updated}) || !slices.Equal(d.Deleted, []uuid.UUID{deleted}) {
+		t.Errorf("unexpected diff %+v", d)
+	}
+
+	if !after.Diff(after).Empty() {
+		t.Error("expected empty diff")
+	}
+}
@@ -0,0 +1,88 @@
+// Package bcsync keeps a local store and a BC entity set in sync.
+// An [Engine] pulls changes from BC with delta links and pushes local changes
+// with ETag checks, resolving conflicts with a [ConflictPolicy].
+// For entity sets without delta links, [Incremental] pulls by last modified time
+// and a [Snapshot] detects changes by comparing record hashes.
+package bcsync
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// ErrNoCheckpoint is returned by a [CheckpointStore] when there is no checkpoint for the key.
+var ErrNoCheckpoint = errors.New("no checkpoint")
+
+// CheckpointStore persists the position of a pull so it can resume after an interruption.
+type CheckpointStore interface {
+	// LoadCheckpoint returns ErrNoCheckpoint if nothing has been saved for the key.
+	LoadCheckpoint(ctx context.Context, key string) (string, error)
+	SaveCheckpoint(ctx context.Context, key string, value string) error
+}
+
+// Op is the type of a local [Change].
+type Op string
+
+const (
+	OpCreate Op = "create"
+	OpUpdate Op = "update"
+	OpDelete Op = "delete"
+)
+
+// Change is a local change waiting to be pushed to BC.
+type Change[T any] struct {
+	// ID is the BC record ID. Empty for OpCreate.
+	ID uuid.UUID
+	Op Op
+	// Record is the local version. Not used for OpDelete.
+	Record T
+	// ETag is the "@odata.etag" of the BC version the change was based on.
+	// Empty to overwrite without a check.
+	ETag string
+}
+
+// Store is the local side of the sync.
+type Store[T any] interface {
+	// Upsert saves a record pulled from BC.
+	Upsert(ctx context.Context, record T) error
+	// Remove deletes a record that was deleted in BC.
+	Remove(ctx context.Context, id uuid.UUID) error
+	// Pending returns the local changes that have not been pushed yet.
+	Pending(ctx context.Context) ([]Change[T], error)
+	// Ack marks the change as pushed. Result is the record returned by BC,
+	// or the zero value for OpDelete or if the change was discarded.
+	Ack(ctx context.Context, change Change[T], result T) error
+}
+
+// MemoryCheckpoints is an in-memory [CheckpointStore], mainly for tests.
+type MemoryCheckpoints struct {
+	mu          sync.Mutex
+	checkpoints map[string]string
+}
+
+// LoadCheckpoint implements [CheckpointStore].
+func (m *MemoryCheckpoints) LoadCheckpoint(_ context.Context, key string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	v, ok := m.checkpoints[key]
+	if !ok {
+		return "", ErrNoCheckpoint
+	}
+	return v, nil
+}
+
+// SaveCheckpoint implements [CheckpointStore].
+func (m *MemoryCheckpoints) SaveCheckpoint(_ context.Context, key string, value string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.checkpoints == nil {
+		m.checkpoints = map[string]string{}
+	}
+	m.checkpoints[key] = value
+	return nil
+}
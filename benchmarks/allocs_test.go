@@ -0,0 +1,70 @@
+package benchmarks_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/erlorenz/bc-go/bc"
+)
+
+// TestAllocs fails if the allocations in the request path grow past the budget.
+// The list and batch budgets include the fake server, which is the same between runs.
+// The budgets leave about 20% headroom over the measured allocations. It is skipped
+// under the race detector and coverage, which add allocations.
+func TestAllocs(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping allocation budgets in short mode")
+	}
+	if raceEnabled || testing.CoverMode() != "" {
+		t.Skip("skipping allocation budgets with race or coverage instrumentation")
+	}
+
+	ctx := context.Background()
+	client := newClient(t, 100)
+	base, err := bc.BuildBaseURL(client.Config())
+	if err != nil {
+		t.Fatal(err)
+	}
+	page := bc.NewAPIPage[item](client, "items")
+	requests := batchRequests()
+	prepared, err := client.Prepare(bc.RequestOptions{
+		Method:        requestOptions.Method,
+		EntitySetName: requestOptions.EntitySetName,
+		QueryParams:   requestOptions.QueryParams,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		budget float64
+		fn     func()
+	}{
+		{"BuildRequestURL", 6, func() {
+			bc.BuildRequestURL(*base, requestOptions.EntitySetName, requestOptions.RecordID, requestOptions.QueryParams)
+		}},
+		{"NewRequest", 18, func() {
+			client.NewRequest(ctx, requestOptions)
+		}},
+		{"PreparedRequest", 18, func() {
+			prepared.NewRequest(ctx, bc.PreparedParams{RecordID: requestOptions.RecordID})
+		}},
+		{"List", 2700, func() {
+			page.List(ctx, bc.ListOptions{})
+		}},
+		{"Batch", 12000, func() {
+			client.Batch(ctx, requests, bc.BatchOptions{})
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := testing.AllocsPerRun(20, tt.fn)
+			t.Logf("%s: %.0f allocs/op", tt.name, got)
+			if got > tt.budget {
+				t.Errorf("%s allocates %.0f per op, budget is %.0f", tt.name, got, tt.budget)
+			}
+		})
+	}
+}
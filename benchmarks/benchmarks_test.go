@@ -0,0 +1,152 @@
+package benchmarks_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/erlorenz/bc-go/bcfake"
+	"github.com/erlorenz/bc-go/internal/bctest"
+	"github.com/google/uuid"
+)
+
+type item struct {
+	ID          uuid.UUID `json:"id"`
+	Number      string    `json:"number"`
+	DisplayName string    `json:"displayName"`
+	UnitPrice   float64   `json:"unitPrice"`
+	Inventory   float64   `json:"inventory"`
+	Blocked     bool      `json:"blocked"`
+}
+
+func (i item) Validate() error { return nil }
+
+// newClient returns a client for a fake server seeded with n items.
+func newClient(tb testing.TB, n int) *bc.Client {
+	tb.Helper()
+
+	server := bcfake.New()
+	for i := range n {
+		server.Seed("items", map[string]any{
+			"number":      fmt.Sprintf("1%04d", i),
+			"displayName": fmt.Sprintf("Item %d", i),
+			"unitPrice":   float64(i) * 1.25,
+			"inventory":   float64(i % 50),
+			"blocked":     i%10 == 0,
+		})
+	}
+
+	return bctest.NewClient(tb, server)
+}
+
+var requestOptions = bc.RequestOptions{
+	Method:        http.MethodGet,
+	EntitySetName: "items",
+	RecordID:      uuid.MustParse("7d0a3b4e-6d0c-4f4e-9c38-3c7a1f6f4b21"),
+	QueryParams:   bc.QueryParams{{Key: "$select", Value: "id,number,displayName"}, {Key: "$expand", Value: "itemCategory"}},
+}
+
+func batchRequests() []bc.RequestOptions {
+	requests := make([]bc.RequestOptions, bc.MaxBatchSize)
+	for i := range requests {
+		requests[i] = bc.RequestOptions{
+			Method:        http.MethodPost,
+			EntitySetName: "items",
+			Body:          map[string]any{"number": fmt.Sprintf("2%04d", i), "displayName": "New item"},
+		}
+	}
+	return requests
+}
+
+func BenchmarkBuildRequestURL(b *testing.B) {
+	base, err := bc.BuildBaseURL(newClient(b, 0).Config())
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for range b.N {
+		bc.BuildRequestURL(*base, requestOptions.EntitySetName, requestOptions.RecordID, requestOptions.QueryParams)
+	}
+}
+
+func BenchmarkNewRequest(b *testing.B) {
+	client := newClient(b, 0)
+	ctx := context.Background()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for range b.N {
+		if _, err := client.NewRequest(ctx, requestOptions); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkNewRequestParallel creates requests from many goroutines, like a bulk job
+// sending tens of thousands of requests per minute through one client.
+func BenchmarkNewRequestParallel(b *testing.B) {
+	client := newClient(b, 0)
+	ctx := context.Background()
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := client.NewRequest(ctx, requestOptions); err != nil {
+				b.Error(err)
+				return
+			}
+		}
+	})
+}
+
+func BenchmarkPreparedRequest(b *testing.B) {
+	client := newClient(b, 0)
+	prepared, err := client.Prepare(bc.RequestOptions{
+		Method:        requestOptions.Method,
+		EntitySetName: requestOptions.EntitySetName,
+		QueryParams:   requestOptions.QueryParams,
+	})
+	if err != nil {
+		b.Fatal(err)
+	}
+	ctx := context.Background()
+	params := bc.PreparedParams{RecordID: requestOptions.RecordID}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for range b.N {
+		if _, err := prepared.NewRequest(ctx, params); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkList(b *testing.B) {
+	for _, n := range []int{10, 1000} {
+		b.Run(fmt.Sprint(n), func(b *testing.B) {
+			page := bc.NewAPIPage[item](newClient(b, n), "items")
+			ctx := context.Background()
+			b.ReportAllocs()
+			b.ResetTimer()
+			for range b.N {
+				if _, err := page.List(ctx, bc.ListOptions{}); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkBatch(b *testing.B) {
+	client := newClient(b, 0)
+	requests := batchRequests()
+	ctx := context.Background()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for range b.N {
+		if _, err := client.Batch(ctx, requests, bc.BatchOptions{}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
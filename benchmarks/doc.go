@@ -0,0 +1,12 @@
+// Package benchmarks measures the request path of the bc package against the in-memory
+// [bcfake.Server]. It has no exported API.
+//
+// Run the benchmarks with
+//
+//	go test -bench . -benchmem ./benchmarks
+//
+// and profile with -cpuprofile or -memprofile. The tests in the package fail if the
+// allocations per operation grow past a budget, so regressions are caught by go test.
+package benchmarks
+
+import _ "github.com/erlorenz/bc-go/bcfake"
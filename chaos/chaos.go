@@ -0,0 +1,181 @@
+// Package chaos injects faults into the requests of a [bc.Client], to verify that the
+// retry, rate limit and circuit breaker configuration of an application handles them
+// before they happen in production:
+//
+//	client, err := bc.NewClient(config,
+//		bc.WithRetryClassifier(bc.DefaultRetryClassifier{}),
+//		bc.WithMiddleware(chaos.Middleware(chaos.Options{Throttle: 0.1, Unavailable: 0.05})),
+//	)
+//
+// Use it in tests and staging only.
+package chaos
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/erlorenz/bc-go/bc"
+)
+
+// Fault is an injected failure.
+type Fault string
+
+const (
+	// FaultLatency delays the request before it is sent.
+	FaultLatency Fault = "latency"
+	// FaultThrottle answers with a 429 and a Retry-After header without sending the request.
+	FaultThrottle Fault = "throttle"
+	// FaultUnavailable answers with a 503 without sending the request.
+	FaultUnavailable Fault = "unavailable"
+	// FaultReset fails the request with a connection reset without sending it.
+	FaultReset Fault = "reset"
+	// FaultMalformed sends the request and truncates the JSON of the response, as if
+	// the connection was cut while reading it.
+	FaultMalformed Fault = "malformed"
+)
+
+// Options are the probabilities of the faults, from 0 to 1. FaultLatency is independent
+// of the others; at most one of the others is injected per request, so their sum should
+// be 1 or less.
+type Options struct {
+	Latency     float64
+	Throttle    float64
+	Unavailable float64
+	Reset       float64
+	Malformed   float64
+
+	// MaxLatency is the longest delay of FaultLatency, which is random up to it.
+	// Defaults to 2 seconds.
+	MaxLatency time.Duration
+	// RetryAfter is the Retry-After of FaultThrottle. Defaults to 1 second.
+	RetryAfter time.Duration
+	// Methods limits the faults to requests with these methods. Defaults to all.
+	Methods []string
+	// Seed makes the faults reproducible. Defaults to a random seed.
+	Seed uint64
+	// OnFault is called for every injected fault, e.g. to count them in a test.
+	OnFault func(r *http.Request, f Fault)
+}
+
+// Middleware returns a [bc.Middleware] that injects the faults. Add it after the
+// middleware under test, so it is closer to the network.
+func Middleware(opts Options) bc.Middleware {
+	if opts.MaxLatency <= 0 {
+		opts.MaxLatency = 2 * time.Second
+	}
+	if opts.RetryAfter <= 0 {
+		opts.RetryAfter = time.Second
+	}
+	if opts.Seed == 0 {
+		opts.Seed = rand.Uint64()
+	}
+	rnd := rand.New(rand.NewPCG(opts.Seed, opts.Seed))
+	var mu sync.Mutex
+	// float returns a random number, as a rand.Rand is not safe for concurrent use.
+	float := func() float64 {
+		mu.Lock()
+		defer mu.Unlock()
+		return rnd.Float64()
+	}
+
+	return func(next http.RoundTripper) http.RoundTripper {
+		return bc.RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			if len(opts.Methods) > 0 && !slices.Contains(opts.Methods, r.Method) {
+				return next.RoundTrip(r)
+			}
+			report := func(f Fault) {
+				if opts.OnFault != nil {
+					opts.OnFault(r, f)
+				}
+			}
+
+			if float() < opts.Latency {
+				report(FaultLatency)
+				delay := time.Duration(float() * float64(opts.MaxLatency))
+				timer := time.NewTimer(delay)
+				select {
+				case <-r.Context().Done():
+					timer.Stop()
+					return nil, r.Context().Err()
+				case <-timer.C:
+				}
+			}
+
+			p := float()
+			for _, fault := range []struct {
+				fault       Fault
+				probability float64
+			}{
+				{FaultThrottle, opts.Throttle},
+				{FaultUnavailable, opts.Unavailable},
+				{FaultReset, opts.Reset},
+				{FaultMalformed, opts.Malformed},
+			} {
+				if p >= fault.probability {
+					p -= fault.probability
+					continue
+				}
+				report(fault.fault)
+				return inject(next, r, fault.fault, opts)
+			}
+			return next.RoundTrip(r)
+		})
+	}
+}
+
+func inject(next http.RoundTripper, r *http.Request, f Fault, opts Options) (*http.Response, error) {
+	if r.Body != nil && f != FaultMalformed {
+		r.Body.Close()
+	}
+	switch f {
+	case FaultThrottle:
+		res := errorResponse(r, http.StatusTooManyRequests, bc.ErrorCodeTooManyRequests, "Injected throttling.")
+		res.Header.Set("Retry-After", strconv.Itoa(int(opts.RetryAfter.Round(time.Second)/time.Second)))
+		return res, nil
+	case FaultUnavailable:
+		return errorResponse(r, http.StatusServiceUnavailable, bc.ErrorCodeServiceUnavailable, "Injected unavailability."), nil
+	case FaultReset:
+		return nil, &net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET}
+	}
+
+	res, err := next.RoundTrip(r)
+	if err != nil {
+		return res, err
+	}
+	body, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("chaos: read body: %w", err)
+	}
+	// Half of the body is never valid JSON, and an empty body is not either.
+	res.Body = io.NopCloser(bytes.NewReader(body[:len(body)/2]))
+	res.ContentLength = -1
+	res.Header.Del("Content-Length")
+	return res, nil
+}
+
+// errorResponse returns a response with the error body of BC.
+func errorResponse(r *http.Request, status int, code bc.ErrorCode, message string) *http.Response {
+	body, _ := json.Marshal(bc.ErrorResponse{Error: bc.ErrorResponseError{Code: string(code), Message: message}})
+	return &http.Response{
+		Status:        strconv.Itoa(status) + " " + http.StatusText(status),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": {"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       r,
+	}
+}
@@ -0,0 +1,101 @@
+package chaos_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/erlorenz/bc-go/chaos"
+	"github.com/erlorenz/bc-go/internal/bctest"
+	"github.com/google/uuid"
+)
+
+type record map[string]any
+
+func (record) Validate() error { return nil }
+
+func newPage(t *testing.T, st http.RoundTripper, opts chaos.Options, clientOpts ...bc.ClientOption) *bc.APIPage[record] {
+	t.Helper()
+	client := bctest.NewClient(t, st, append([]bc.ClientOption{bc.WithMiddleware(chaos.Middleware(opts))}, clientOpts...)...)
+	return bc.NewAPIPage[record](client, "customers")
+}
+
+func TestFaults(t *testing.T) {
+	type testCase struct {
+		name  string
+		opts  chaos.Options
+		check func(err error) bool
+		sent  bool
+	}
+	tests := []testCase{
+		{"throttle", chaos.Options{Throttle: 1}, func(err error) bool {
+			var apiErr bc.APIError
+			return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusTooManyRequests && apiErr.Code.IsTransient()
+		}, false},
+		{"unavailable", chaos.Options{Unavailable: 1}, func(err error) bool {
+			var apiErr bc.APIError
+			return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusServiceUnavailable
+		}, false},
+		{"reset", chaos.Options{Reset: 1}, func(err error) bool { return errors.Is(err, syscall.ECONNRESET) }, false},
+		{"malformed", chaos.Options{Malformed: 1}, func(err error) bool {
+			var apiErr bc.APIError
+			return err != nil && !errors.As(err, &apiErr)
+		}, true},
+		{"other method", chaos.Options{Throttle: 1, Methods: []string{http.MethodPost}}, func(err error) bool { return err == nil }, true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			st := &bctest.SequenceTransport{Responses: []*http.Response{bctest.NewResponse(http.StatusOK, record{"id": uuid.NewString()})}}
+			page := newPage(t, st, tc.opts)
+			_, err := page.Get(context.Background(), uuid.New(), bc.GetOptions{})
+			if !tc.check(err) {
+				t.Errorf("unexpected error %v", err)
+			}
+			if sent := st.Count() == 1; sent != tc.sent {
+				t.Errorf("wanted sent %t, got %t", tc.sent, sent)
+			}
+		})
+	}
+}
+
+func TestRetriesRecover(t *testing.T) {
+	var faults []chaos.Fault
+	st := bc.RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		return bctest.NewResponse(http.StatusOK, record{"id": uuid.NewString()}), nil
+	})
+	opts := chaos.Options{
+		Latency: 1, MaxLatency: time.Millisecond, Throttle: 0.5, RetryAfter: time.Millisecond, Seed: 7,
+		OnFault: func(_ *http.Request, f chaos.Fault) { faults = append(faults, f) },
+	}
+	page := newPage(t, st, opts, bc.WithRetryClassifier(bc.DefaultRetryClassifier{MaxAttempts: 20, BaseDelay: time.Millisecond}))
+
+	if _, err := page.Get(context.Background(), uuid.New(), bc.GetOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	throttled := 0
+	for _, f := range faults {
+		if f == chaos.FaultThrottle {
+			throttled++
+		}
+	}
+	if throttled+1 != len(faults)-throttled {
+		t.Errorf("wanted a latency for each attempt, got %v", faults)
+	}
+
+	// The same seed injects the same faults
+	var again []chaos.Fault
+	opts.OnFault = func(_ *http.Request, f chaos.Fault) { again = append(again, f) }
+	page = newPage(t, st, opts, bc.WithRetryClassifier(bc.DefaultRetryClassifier{MaxAttempts: 20, BaseDelay: time.Millisecond}))
+	if _, err := page.Get(context.Background(), uuid.New(), bc.GetOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(faults, again) {
+		t.Errorf("wanted %v, got %v", faults, again)
+	}
+}
@@ -0,0 +1,109 @@
+// Command bccontract generates a test that asserts the typed models of a package still
+// match the entity definitions of a saved $metadata document, so that a BC upgrade that
+// removes or retypes a field fails the test suite instead of production. See package
+// contract.
+//
+// Usage:
+//
+//	bcctl metadata -out models/testdata/metadata.xml
+//	bccontract -metadata models/testdata/metadata.xml -models models -import github.com/erlorenz/bc-go/models
+//
+// or with a go:generate directive in the models package:
+//
+//	//go:generate go run github.com/erlorenz/bc-go/cmd/bccontract -metadata testdata/metadata.xml -import github.com/erlorenz/bc-go/models
+//
+// After an upgrade, download the $metadata again and run the tests; the generated test
+// only has to be regenerated when models are added.
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/erlorenz/bc-go/contract"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stderr); err != nil {
+		if !errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintln(os.Stderr, "bccontract:", err)
+		}
+		os.Exit(2)
+	}
+}
+
+func run(args []string, stderr io.Writer) error {
+	fs := flag.NewFlagSet("bccontract", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	metadata := fs.String("metadata", "", "saved $metadata `file`")
+	dir := fs.String("models", ".", "`directory` of the models package")
+	importPath := fs.String("import", "", "import `path` of the models package")
+	pkg := fs.String("package", "", "`package` of the test, defaults to the external test package of the models")
+	out := fs.String("out", "", "test `file`, defaults to contract_test.go in the models directory")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *metadata == "" || *importPath == "" {
+		fs.Usage()
+		return flag.ErrHelp
+	}
+	if *pkg == "" {
+		*pkg = filepath.Base(*importPath) + "_test"
+	}
+	if *out == "" {
+		*out = filepath.Join(*dir, "contract_test.go")
+	}
+
+	f, err := os.Open(*metadata)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	caps, err := bc.ParseCapabilities(f)
+	if err != nil {
+		return err
+	}
+
+	models, err := contract.FindModels(*dir)
+	if err != nil {
+		return err
+	}
+	if len(models) == 0 {
+		return fmt.Errorf("no models found in %s", *dir)
+	}
+	for _, m := range models {
+		if !caps.HasEntitySet(m.EntitySetName) {
+			fmt.Fprintf(stderr, "bccontract: warning: %s of %s is not in the $metadata\n", m.EntitySetName, m.TypeName)
+		}
+	}
+
+	// The test loads the $metadata relative to its own directory.
+	absOut, err := filepath.Abs(*out)
+	if err != nil {
+		return err
+	}
+	absMetadata, err := filepath.Abs(*metadata)
+	if err != nil {
+		return err
+	}
+	rel, err := filepath.Rel(filepath.Dir(absOut), absMetadata)
+	if err != nil {
+		return err
+	}
+	var b bytes.Buffer
+	err = contract.Generate(&b, models, contract.GenerateOptions{
+		Package:      *pkg,
+		ImportPath:   *importPath,
+		MetadataPath: filepath.ToSlash(rel),
+	})
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(*out, b.Bytes(), 0o644)
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	models := filepath.Join(dir, "models")
+	if err := os.MkdirAll(filepath.Join(models, "testdata"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	metadata, err := os.ReadFile("../../contract/testdata/metadata.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(models, "testdata", "metadata.xml"), metadata, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	src := "package models\n\nfunc Customers() any { return newPage[Customer](nil, \"customers\") }\n\nfunc Vendors() any { return newPage[Vendor](nil, \"vendors\") }\n"
+	if err := os.WriteFile(filepath.Join(models, "models.go"), []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var stderr bytes.Buffer
+	err = run([]string{"-metadata", filepath.Join(models, "testdata", "metadata.xml"), "-models", models, "-import", "example.com/app/models"}, &stderr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(stderr.String(), "vendors of Vendor is not in the $metadata") {
+		t.Errorf("wanted a warning for vendors, got %q", stderr.String())
+	}
+
+	b, err := os.ReadFile(filepath.Join(models, "contract_test.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"package models_test\n",
+		`contract.Load(t, "testdata/metadata.xml")`,
+		`contract.Assert(t, caps, "customers", models.Customer{})`,
+		`contract.Assert(t, caps, "vendors", models.Vendor{})`,
+	} {
+		if !strings.Contains(string(b), want) {
+			t.Errorf("wanted %q in\n%s", want, b)
+		}
+	}
+}
+
+func TestRunUsage(t *testing.T) {
+	var stderr bytes.Buffer
+	if err := run([]string{"-models", "."}, &stderr); !errors.Is(err, flag.ErrHelp) {
+		t.Errorf("wanted flag.ErrHelp without -metadata and -import, got %v", err)
+	}
+}
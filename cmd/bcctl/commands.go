@@ -0,0 +1,466 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/erlorenz/bc-go/apply"
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/erlorenz/bc-go/bcql"
+	"github.com/erlorenz/bc-go/webhook"
+	"github.com/google/uuid"
+)
+
+// record is any record. The CLI does not know the schema, so nothing is validated.
+type record map[string]any
+
+func (record) Validate() error { return nil }
+
+type command func(ctx context.Context, a *app, args []string) error
+
+// commands is set in init as completion looks up the command names.
+var commands map[string]command
+
+func init() {
+	commands = map[string]command{
+		"get":           getCommand,
+		"list":          listCommand,
+		"query":         queryCommand,
+		"create":        createCommand,
+		"patch":         patchCommand,
+		"delete":        deleteCommand,
+		"action":        actionCommand,
+		"subscriptions": subscriptionsCommand,
+		"metadata":      metadataCommand,
+		"entities":      entitiesCommand,
+		"describe":      describeCommand,
+		"apply":         applyCommand,
+		"completion":    completionCommand,
+		"__complete":    completeCommand,
+	}
+}
+
+// newFlagSet creates the flag set of a command with its usage line.
+func (a *app) newFlagSet(name, usage string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(a.stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(a.stderr, "usage: bcctl %s %s\n", name, usage)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parseArgs parses the flags, which may come before or after the positional
+// arguments, and checks the number of positional arguments.
+func parseArgs(fs *flag.FlagSet, args []string, n int) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		if fs.NArg() == 0 {
+			break
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+	if len(positional) != n {
+		fs.Usage()
+		return nil, fmt.Errorf("%s: wanted %d arguments, got %d", fs.Name(), n, len(positional))
+	}
+	return positional, nil
+}
+
+func parseID(s string) (uuid.UUID, error) {
+	id, err := uuid.Parse(s)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("invalid id %q: %w", s, err)
+	}
+	return id, nil
+}
+
+// splitList splits a comma separated flag value.
+func splitList(s string) []string {
+	if s == "" {
+		return nil
+	}
+	fields := strings.Split(s, ",")
+	for i := range fields {
+		fields[i] = strings.TrimSpace(fields[i])
+	}
+	return fields
+}
+
+// readData returns the JSON body of -data: the value itself, a file with "@path"
+// or stdin with "-".
+func (a *app) readData(data string) (json.RawMessage, error) {
+	var b []byte
+	var err error
+	switch {
+	case data == "-":
+		b, err = io.ReadAll(a.stdin)
+	case strings.HasPrefix(data, "@"):
+		b, err = os.ReadFile(data[1:])
+	default:
+		b = []byte(data)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read data: %w", err)
+	}
+	if !json.Valid(b) {
+		return nil, errors.New("read data: not valid JSON")
+	}
+	return b, nil
+}
+
+func getCommand(ctx context.Context, a *app, args []string) error {
+	fs := a.newFlagSet("get", "[-select fields] [-expand fields] <entitySet> <id>")
+	sel := fs.String("select", "", "comma separated `fields` to return")
+	expand := fs.String("expand", "", "comma separated navigation `fields` to expand")
+	args, err := parseArgs(fs, args, 2)
+	if err != nil {
+		return err
+	}
+	id, err := parseID(args[1])
+	if err != nil {
+		return err
+	}
+
+	rec, err := bc.NewAPIPage[record](a.client, args[0]).Get(ctx, id, bc.GetOptions{Select: splitList(*sel), Expand: splitList(*expand)})
+	if err != nil {
+		return err
+	}
+	return a.writeRecord(rec, splitList(*sel))
+}
+
+func listCommand(ctx context.Context, a *app, args []string) error {
+	fs := a.newFlagSet("list", "[flags] <entitySet>")
+	var opts bc.ListOptions
+	fs.StringVar(&opts.Filter, "filter", "", "OData `expression`")
+	sel := fs.String("select", "", "comma separated `fields` to return")
+	expand := fs.String("expand", "", "comma separated navigation `fields` to expand")
+	orderBy := fs.String("orderby", "", "comma separated `fields`, e.g. \"number desc\"")
+	fs.IntVar(&opts.Top, "top", 0, "maximum number of records")
+	fs.IntVar(&opts.Skip, "skip", 0, "number of records to skip")
+	all := fs.Bool("all", false, "follow the nextLink of every page")
+	fs.IntVar(&opts.MaxPageSize, "page-size", 0, "records per page with -all")
+	args, err := parseArgs(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	opts.Select, opts.Expand, opts.OrderBy = splitList(*sel), splitList(*expand), splitList(*orderBy)
+
+	records, err := a.list(ctx, args[0], opts, *all)
+	if err != nil {
+		return err
+	}
+	return a.writeRecords(records, opts.Select)
+}
+
+func queryCommand(ctx context.Context, a *app, args []string) error {
+	fs := a.newFlagSet("query", "[-all] <statement>")
+	all := fs.Bool("all", false, "follow the nextLink of every page")
+	args, err := parseArgs(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	q, err := bcql.Parse(args[0])
+	if err != nil {
+		return err
+	}
+
+	records, err := a.list(ctx, q.EntitySetName, q.ListOptions, *all)
+	if err != nil {
+		return err
+	}
+	return a.writeRecords(records, q.ListOptions.Select)
+}
+
+// list returns the first page, or every page if all is set.
+func (a *app) list(ctx context.Context, entitySetName string, opts bc.ListOptions, all bool) ([]record, error) {
+	page := bc.NewAPIPage[record](a.client, entitySetName)
+	if !all {
+		return page.List(ctx, opts)
+	}
+
+	var records []record
+	var nextLink string
+	for {
+		res, err := page.ListPage(ctx, nextLink, opts)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, res.Value...)
+		if res.NextLink == "" {
+			return records, nil
+		}
+		nextLink = res.NextLink
+	}
+}
+
+func createCommand(ctx context.Context, a *app, args []string) error {
+	fs := a.newFlagSet("create", "-data json <entitySet>")
+	data := fs.String("data", "-", "JSON `body`, @file or - for stdin")
+	args, err := parseArgs(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	body, err := a.readData(*data)
+	if err != nil {
+		return err
+	}
+
+	rec, err := bc.NewAPIPage[record](a.client, args[0]).Create(ctx, body, bc.GetOptions{})
+	if err != nil {
+		return err
+	}
+	return a.writeRecord(rec, nil)
+}
+
+func patchCommand(ctx context.Context, a *app, args []string) error {
+	fs := a.newFlagSet("patch", "-data json [-etag etag] <entitySet> <id>")
+	data := fs.String("data", "-", "JSON `body`, @file or - for stdin")
+	etag := fs.String("etag", "", "only update if the record has this `etag`")
+	args, err := parseArgs(fs, args, 2)
+	if err != nil {
+		return err
+	}
+	id, err := parseID(args[1])
+	if err != nil {
+		return err
+	}
+	body, err := a.readData(*data)
+	if err != nil {
+		return err
+	}
+
+	page := bc.NewAPIPage[record](a.client, args[0])
+	var rec record
+	if *etag != "" {
+		rec, err = page.UpdateIfMatch(ctx, id, nil, body, *etag)
+	} else {
+		rec, err = page.Update(ctx, id, nil, body)
+	}
+	if err != nil {
+		return err
+	}
+	return a.writeRecord(rec, nil)
+}
+
+func deleteCommand(ctx context.Context, a *app, args []string) error {
+	fs := a.newFlagSet("delete", "[-etag etag] <entitySet> <id>")
+	etag := fs.String("etag", "", "only delete if the record has this `etag`")
+	args, err := parseArgs(fs, args, 2)
+	if err != nil {
+		return err
+	}
+	id, err := parseID(args[1])
+	if err != nil {
+		return err
+	}
+
+	page := bc.NewAPIPage[record](a.client, args[0])
+	if *etag != "" {
+		return page.DeleteIfMatch(ctx, id, *etag)
+	}
+	return page.Delete(ctx, id)
+}
+
+func actionCommand(ctx context.Context, a *app, args []string) error {
+	fs := a.newFlagSet("action", "[-data json] <entitySet> <id> <action>")
+	data := fs.String("data", "", "optional JSON `body`, @file or - for stdin")
+	args, err := parseArgs(fs, args, 3)
+	if err != nil {
+		return err
+	}
+	id, err := parseID(args[1])
+	if err != nil {
+		return err
+	}
+
+	var body any
+	if *data != "" {
+		if body, err = a.readData(*data); err != nil {
+			return err
+		}
+	}
+	return a.client.InvokeAction(ctx, args[0], id, args[2], body)
+}
+
+func applyCommand(ctx context.Context, a *app, args []string) error {
+	fs := a.newFlagSet("apply", "[-plan] <file>")
+	planOnly := fs.Bool("plan", false, "only print the changes")
+	args, err := parseArgs(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	cfg, err := apply.LoadFile(args[0])
+	if err != nil {
+		return err
+	}
+
+	plan, err := apply.NewPlan(ctx, a.client, cfg, apply.Options{})
+	if err != nil {
+		return err
+	}
+	fmt.Fprint(a.stdout, plan)
+	if *planOnly || plan.Empty() {
+		return nil
+	}
+
+	result, err := apply.Apply(ctx, a.client, plan, apply.Options{})
+	fmt.Fprintf(a.stdout, "Applied: %d created, %d updated, %d deleted.\n", result.Created, result.Updated, result.Deleted)
+	for _, ce := range result.Errors {
+		fmt.Fprintln(a.stderr, ce)
+	}
+	if err != nil {
+		return err
+	}
+	if len(result.Errors) > 0 {
+		return fmt.Errorf("apply: %d changes failed", len(result.Errors))
+	}
+	return nil
+}
+
+// apiRoot returns the URL of the API without the company, where $metadata is.
+func (a *app) apiRoot() (*url.URL, error) {
+	u, err := bc.BuildBaseURL(a.client.Config())
+	if err != nil {
+		return nil, err
+	}
+	if i := strings.LastIndex(u.Path, "/companies("); i >= 0 {
+		u.Path = u.Path[:i]
+	}
+	return u, nil
+}
+
+// subscriptionRecord is the subscription as a record for the output.
+func subscriptionRecord(s webhook.Subscription) record {
+	expiration := ""
+	if !s.ExpirationDateTime.IsZero() {
+		expiration = s.ExpirationDateTime.Format(time.RFC3339)
+	}
+	return record{
+		"subscriptionId":     s.SubscriptionID,
+		"notificationUrl":    s.NotificationURL,
+		"resource":           s.Resource,
+		"clientState":        s.ClientState,
+		"expirationDateTime": expiration,
+	}
+}
+
+var subscriptionColumns = []string{"subscriptionId", "resource", "notificationUrl", "expirationDateTime"}
+
+func subscriptionsCommand(ctx context.Context, a *app, args []string) error {
+	if len(args) == 0 {
+		return errors.New("subscriptions: wanted list, create or delete")
+	}
+
+	switch args[0] {
+	case "list":
+		list, err := webhook.ListSubscriptions(ctx, a.client)
+		if err != nil {
+			return err
+		}
+		records := make([]record, len(list))
+		for i, s := range list {
+			records[i] = subscriptionRecord(s)
+		}
+		return a.writeRecords(records, subscriptionColumns)
+
+	case "create":
+		fs := a.newFlagSet("subscriptions create", "-url url [-client-state state] <entitySet>")
+		notificationURL := fs.String("url", "", "notification `url`, which must answer the validation request")
+		clientState := fs.String("client-state", "", "`state` sent back with every notification")
+		args, err := parseArgs(fs, args[1:], 1)
+		if err != nil {
+			return err
+		}
+		if *notificationURL == "" {
+			return errors.New("subscriptions create: -url is required")
+		}
+
+		resource, err := webhook.Resource(a.client, args[0])
+		if err != nil {
+			return err
+		}
+		s, err := webhook.CreateSubscription(ctx, a.client, webhook.Subscription{
+			NotificationURL: *notificationURL,
+			Resource:        resource,
+			ClientState:     *clientState,
+		})
+		if err != nil {
+			return err
+		}
+		return a.writeRecord(subscriptionRecord(s), subscriptionColumns)
+
+	case "delete":
+		fs := a.newFlagSet("subscriptions delete", "[-etag etag] <subscriptionId>")
+		etag := fs.String("etag", "*", "only delete if the subscription has this `etag`")
+		args, err := parseArgs(fs, args[1:], 1)
+		if err != nil {
+			return err
+		}
+		return webhook.DeleteSubscription(ctx, a.client, args[0], *etag)
+	}
+	return fmt.Errorf("subscriptions: unknown command %q", args[0])
+}
+
+func metadataCommand(ctx context.Context, a *app, args []string) error {
+	fs := a.newFlagSet("metadata", "[-out file]")
+	out := fs.String("out", "", "write to the `file` instead of stdout")
+	if _, err := parseArgs(fs, args, 0); err != nil {
+		return err
+	}
+
+	if *out == "" {
+		return a.downloadMetadata(ctx, a.stdout)
+	}
+	f, err := os.Create(*out)
+	if err != nil {
+		return err
+	}
+	if err := a.downloadMetadata(ctx, f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// downloadMetadata copies the $metadata document to w.
+func (a *app) downloadMetadata(ctx context.Context, w io.Writer) error {
+	root, err := a.apiRoot()
+	if err != nil {
+		return err
+	}
+	root.Path += "/$metadata"
+
+	req, err := a.client.NewRequestURL(ctx, http.MethodGet, root.String(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create Request: %w", err)
+	}
+	req.Header.Set("Accept", "application/xml")
+	res, err := a.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed during request: %w", err)
+	}
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return bc.DecodeNoContent(res)
+	}
+	defer res.Body.Close()
+
+	if _, err := io.Copy(w, res.Body); err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	return nil
+}
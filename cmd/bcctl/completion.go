@@ -0,0 +1,194 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/erlorenz/bc-go/bc"
+)
+
+// The scripts call "bcctl __complete" with the words before the cursor and the
+// word being completed, which prints a candidate per line.
+const (
+	bashCompletion = `_bcctl() {
+    local IFS=$'\n'
+    COMPREPLY=($(bcctl __complete "${COMP_WORDS[@]:1:COMP_CWORD}" 2>/dev/null))
+}
+complete -o default -F _bcctl bcctl
+`
+	zshCompletion = `#compdef bcctl
+_bcctl() {
+    local -a candidates
+    candidates=("${(@f)$(bcctl __complete "${(@)words[2,CURRENT]}" 2>/dev/null)}")
+    compadd -a candidates
+}
+compdef _bcctl bcctl
+`
+	fishCompletion = `complete -c bcctl -f -a '(bcctl __complete (commandline -opc)[2..-1] (commandline -ct) 2>/dev/null)'
+`
+)
+
+var completionScripts = map[string]string{
+	"bash": bashCompletion,
+	"zsh":  zshCompletion,
+	"fish": fishCompletion,
+}
+
+func completionCommand(_ context.Context, a *app, args []string) error {
+	fs := a.newFlagSet("completion", "bash|zsh|fish")
+	args, err := parseArgs(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	script, ok := completionScripts[args[0]]
+	if !ok {
+		return fmt.Errorf("completion: unknown shell %q", args[0])
+	}
+	_, err = fmt.Fprint(a.stdout, script)
+	return err
+}
+
+// boolFlags are the flags without a value, so the next word is not skipped.
+var boolFlags = map[string]bool{"-all": true, "-debug": true, "-interactive": true, "-refresh": true}
+
+// takesValue reports whether the word is a flag followed by its value.
+func takesValue(word string) bool {
+	return strings.HasPrefix(word, "-") && !strings.Contains(word, "=") && !boolFlags["-"+strings.TrimLeft(word, "-")]
+}
+
+// positionals returns the words that are not flags or flag values.
+func positionals(words []string) []string {
+	var args []string
+	for i := 0; i < len(words); i++ {
+		switch {
+		case takesValue(words[i]):
+			i++
+		case !strings.HasPrefix(words[i], "-"):
+			args = append(args, words[i])
+		}
+	}
+	return args
+}
+
+// completeCommand prints the candidates for the last word. Errors are not reported,
+// there are just no candidates.
+func completeCommand(ctx context.Context, a *app, words []string) error {
+	if len(words) == 0 {
+		words = []string{""}
+	}
+	current, before := words[len(words)-1], words[:len(words)-1]
+	for _, c := range a.candidates(ctx, before, current) {
+		if strings.HasPrefix(c, current) {
+			fmt.Fprintln(a.stdout, c)
+		}
+	}
+	return nil
+}
+
+// candidates returns the completions of the word after before, which are the
+// words after "bcctl".
+func (a *app) candidates(ctx context.Context, before []string, current string) []string {
+	var flagName string
+	if n := len(before); n > 0 && takesValue(before[n-1]) {
+		flagName = strings.TrimLeft(before[n-1], "-")
+	}
+
+	// Skip the global flags to find the command
+	i := 0
+	for i < len(before) && strings.HasPrefix(before[i], "-") {
+		if takesValue(before[i]) {
+			i++
+		}
+		i++
+	}
+	if i >= len(before) {
+		if flagName == "o" {
+			return slices.Sorted(maps.Keys(formatters))
+		}
+		var names []string
+		for name := range commands {
+			if !strings.HasPrefix(name, "_") {
+				names = append(names, name)
+			}
+		}
+		slices.Sort(names)
+		return names
+	}
+
+	command, args := before[i], positionals(before[i+1:])
+	if flagName != "" {
+		return a.fieldCandidates(ctx, command, args, flagName, current)
+	}
+
+	switch {
+	case command == "completion" && len(args) == 0:
+		return slices.Sorted(maps.Keys(completionScripts))
+	case command == "subscriptions" && len(args) == 0:
+		return []string{"create", "delete", "list"}
+	case command == "subscriptions" && len(args) == 1 && args[0] == "create":
+		return a.entitySets(ctx)
+	case command == "action" && len(args) == 2:
+		caps, ok := a.cachedCapabilities(ctx)
+		if !ok {
+			return nil
+		}
+		return caps.Actions[args[0]]
+	}
+	switch command {
+	case "get", "list", "create", "patch", "delete", "action", "describe":
+		if len(args) == 0 {
+			return a.entitySets(ctx)
+		}
+	}
+	return nil
+}
+
+// fieldCandidates completes the value of -select, -orderby and -expand, which are
+// comma separated lists, so the candidates start with the fields already typed.
+func (a *app) fieldCandidates(ctx context.Context, command string, args []string, flagName, current string) []string {
+	if len(args) == 0 || command == "query" {
+		return nil
+	}
+	caps, ok := a.cachedCapabilities(ctx)
+	if !ok {
+		return nil
+	}
+
+	var fields []string
+	switch flagName {
+	case "select", "orderby":
+		for _, p := range caps.Properties[args[0]] {
+			fields = append(fields, p.Name)
+		}
+	case "expand":
+		fields = navigationFields(caps, args[0])
+	default:
+		return nil
+	}
+
+	prefix := current[:strings.LastIndex(current, ",")+1]
+	for i, f := range fields {
+		fields[i] = prefix + f
+	}
+	return fields
+}
+
+func (a *app) entitySets(ctx context.Context) []string {
+	caps, ok := a.cachedCapabilities(ctx)
+	if !ok {
+		return nil
+	}
+	return slices.Sorted(maps.Keys(caps.EntitySets))
+}
+
+// cachedCapabilities returns the capabilities if there is a valid client.
+func (a *app) cachedCapabilities(ctx context.Context) (caps bc.Capabilities, ok bool) {
+	if a.client == nil {
+		return caps, false
+	}
+	caps, err := a.capabilities(ctx, false)
+	return caps, err == nil
+}
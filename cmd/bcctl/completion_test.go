@@ -0,0 +1,70 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestComplete(t *testing.T) {
+	var count atomic.Int32
+	handler := metadataHandler(&count)
+
+	tests := []struct {
+		words []string
+		want  string
+	}{
+		{[]string{""}, "action\napply\ncompletion\ncreate\ndelete\ndescribe\nentities\nget\nlist\nmetadata\npatch\nquery\nsubscriptions\n"},
+		{[]string{"-o", "t"}, "table\n"},
+		{[]string{"-o", "csv", "de"}, "delete\ndescribe\n"},
+		{[]string{"get", ""}, "customers\nsalesInvoices\n"},
+		{[]string{"list", "-top", "10", "c"}, "customers\n"},
+		{[]string{"list", "customers", "-select", "number,d"}, "number,displayName\n"},
+		{[]string{"list", "customers", "-expand", ""}, "paymentTerm\n"},
+		{[]string{"list", "customers", "-all", "-orderby", "i"}, "id\n"},
+		{[]string{"action", "salesInvoices", "7d0a3b4e-6d0c-4f4e-9c38-3c7a1f6f4b21", ""}, "post\n"},
+		{[]string{"subscriptions", ""}, "create\ndelete\nlist\n"},
+		{[]string{"subscriptions", "create", "-url", "https://example.com", "s"}, "salesInvoices\n"},
+		{[]string{"completion", "z"}, "zsh\n"},
+		{[]string{"get", "customers", ""}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.words, " "), func(t *testing.T) {
+			out, err := bcctl(t, handler, "", append([]string{"__complete"}, tt.words...)...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if out != tt.want {
+				t.Errorf("wanted %q, got %q", tt.want, out)
+			}
+		})
+	}
+}
+
+func TestCompleteWithoutConfig(t *testing.T) {
+	var stdout bytes.Buffer
+	args := []string{"-tenant", "", "-cache-dir", "", "__complete", "en"}
+	if err := run(context.Background(), args, nil, &stdout, &bytes.Buffer{}); err != nil {
+		t.Fatal(err)
+	}
+	if stdout.String() != "entities\n" {
+		t.Errorf("unexpected candidates %q", stdout.String())
+	}
+}
+
+func TestCompletionScripts(t *testing.T) {
+	for _, shell := range []string{"bash", "zsh", "fish"} {
+		out, err := bcctl(t, nil, "", "completion", shell)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(out, "bcctl __complete") {
+			t.Errorf("%s: unexpected script %s", shell, out)
+		}
+	}
+	if _, err := bcctl(t, nil, "", "completion", "powershell"); err == nil {
+		t.Error("wanted error for an unknown shell")
+	}
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/erlorenz/bc-go/bc"
+)
+
+// metadataTTL is how long a cached $metadata is used. It only changes when
+// an extension is installed or BC is updated.
+const metadataTTL = 24 * time.Hour
+
+func defaultCacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "bcctl")
+}
+
+// metadataCachePath returns the file of the cached $metadata. The $metadata is the
+// same for every company, so the company is not part of the name.
+func (a *app) metadataCachePath() string {
+	if a.cacheDir == "" {
+		return ""
+	}
+	config := a.client.Config()
+	sum := sha256.Sum256([]byte(config.TenantID + "/" + config.Environment + "/" + config.APIEndpoint))
+	return filepath.Join(a.cacheDir, hex.EncodeToString(sum[:8])+".xml")
+}
+
+// capabilities reads the cached $metadata, or downloads it if it is missing,
+// older than metadataTTL or refresh is set.
+func (a *app) capabilities(ctx context.Context, refresh bool) (bc.Capabilities, error) {
+	path := a.metadataCachePath()
+	if path != "" && !refresh {
+		if info, err := os.Stat(path); err == nil && time.Since(info.ModTime()) < metadataTTL {
+			if b, err := os.ReadFile(path); err == nil {
+				if caps, err := bc.ParseCapabilities(bytes.NewReader(b)); err == nil {
+					return caps, nil
+				}
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := a.downloadMetadata(ctx, &buf); err != nil {
+		return bc.Capabilities{}, err
+	}
+	caps, err := bc.ParseCapabilities(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		return bc.Capabilities{}, err
+	}
+
+	// The cache is best effort, a read-only home directory should not fail the command
+	if path != "" {
+		if err := os.MkdirAll(a.cacheDir, 0o700); err == nil {
+			os.WriteFile(path, buf.Bytes(), 0o600)
+		}
+	}
+	return caps, nil
+}
+
+// navigationFields returns the fields of the entity set that are not structural properties.
+func navigationFields(caps bc.Capabilities, entitySetName string) []string {
+	var fields []string
+	for _, f := range caps.EntitySets[entitySetName] {
+		if _, ok := caps.Property(entitySetName, f); !ok {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+func entitiesCommand(ctx context.Context, a *app, args []string) error {
+	fs := a.newFlagSet("entities", "[-refresh]")
+	refresh := fs.Bool("refresh", false, "download the $metadata even if it is cached")
+	if _, err := parseArgs(fs, args, 0); err != nil {
+		return err
+	}
+	caps, err := a.capabilities(ctx, *refresh)
+	if err != nil {
+		return err
+	}
+
+	var records []record
+	for _, name := range slices.Sorted(maps.Keys(caps.EntitySets)) {
+		records = append(records, record{
+			"name":       name,
+			"fields":     len(caps.Properties[name]),
+			"navigation": strings.Join(navigationFields(caps, name), ","),
+			"actions":    strings.Join(caps.Actions[name], ","),
+		})
+	}
+	return a.writeRecords(records, []string{"name", "fields", "navigation", "actions"})
+}
+
+func describeCommand(ctx context.Context, a *app, args []string) error {
+	fs := a.newFlagSet("describe", "[-refresh] <entitySet>")
+	refresh := fs.Bool("refresh", false, "download the $metadata even if it is cached")
+	args, err := parseArgs(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	caps, err := a.capabilities(ctx, *refresh)
+	if err != nil {
+		return err
+	}
+	if !caps.HasEntitySet(args[0]) {
+		return fmt.Errorf("unknown entity set %s%s", args[0], suggest(caps, args[0]))
+	}
+
+	var records []record
+	for _, p := range caps.Properties[args[0]] {
+		rec := record{"name": p.Name, "kind": "field", "type": p.Type, "nullable": p.Nullable}
+		if p.MaxLength > 0 {
+			rec["maxLength"] = p.MaxLength
+		}
+		records = append(records, rec)
+	}
+	for _, f := range navigationFields(caps, args[0]) {
+		records = append(records, record{"name": f, "kind": "navigation"})
+	}
+	for _, action := range caps.Actions[args[0]] {
+		records = append(records, record{"name": action, "kind": "action"})
+	}
+	return a.writeRecords(records, []string{"name", "kind", "type", "nullable", "maxLength"})
+}
+
+// suggest returns the entity sets with a similar name, as custom APIs often
+// differ from the standard ones only in case or plural.
+func suggest(caps bc.Capabilities, name string) string {
+	stem := strings.ToLower(strings.TrimSuffix(name, "s"))
+	var similar []string
+	for set := range caps.EntitySets {
+		if strings.Contains(strings.ToLower(set), stem) {
+			similar = append(similar, set)
+		}
+	}
+	if len(similar) == 0 {
+		return ""
+	}
+	slices.Sort(similar)
+	return ", did you mean " + strings.Join(similar, ", ")
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+const metadataXML = `<?xml version="1.0" encoding="utf-8"?>
+<edmx:Edmx Version="4.0" xmlns:edmx="http://docs.oasis-open.org/odata/ns/edmx">
+  <edmx:DataServices>
+    <Schema Namespace="Microsoft.NAV" xmlns="http://docs.oasis-open.org/odata/ns/edm">
+      <EntityType Name="customer">
+        <Property Name="id" Type="Edm.Guid" Nullable="false" />
+        <Property Name="number" Type="Edm.String" MaxLength="20" />
+        <Property Name="displayName" Type="Edm.String" MaxLength="100" />
+        <NavigationProperty Name="paymentTerm" Type="Microsoft.NAV.paymentTerm" />
+      </EntityType>
+      <EntityType Name="salesInvoice">
+        <Property Name="id" Type="Edm.Guid" Nullable="false" />
+      </EntityType>
+      <Action Name="post" IsBound="true">
+        <Parameter Name="bindingParameter" Type="Microsoft.NAV.salesInvoice" />
+      </Action>
+      <EntityContainer Name="default">
+        <EntitySet Name="customers" EntityType="Microsoft.NAV.customer" />
+        <EntitySet Name="salesInvoices" EntityType="Microsoft.NAV.salesInvoice" />
+      </EntityContainer>
+    </Schema>
+  </edmx:DataServices>
+</edmx:Edmx>`
+
+// metadataHandler serves the $metadata and counts the requests.
+func metadataHandler(count *atomic.Int32) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		count.Add(1)
+		w.Header().Set("Content-Type", "application/xml")
+		io.WriteString(w, metadataXML)
+	})
+}
+
+func TestEntities(t *testing.T) {
+	var count atomic.Int32
+	out, err := bcctl(t, metadataHandler(&count), "", "-o", "csv", "entities")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "name,fields,navigation,actions\ncustomers,3,paymentTerm,\nsalesInvoices,1,,post\n"; out != want {
+		t.Errorf("wanted %q, got %q", want, out)
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	var count atomic.Int32
+	out, err := bcctl(t, metadataHandler(&count), "", "-o", "csv", "describe", "customers")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "name,kind,type,nullable,maxLength\nid,field,Edm.Guid,false,\nnumber,field,Edm.String,true,20\n" +
+		"displayName,field,Edm.String,true,100\npaymentTerm,navigation,,,\n"
+	if out != want {
+		t.Errorf("wanted %q, got %q", want, out)
+	}
+
+	_, err = bcctl(t, metadataHandler(&count), "", "describe", "customer")
+	if err == nil || !strings.Contains(err.Error(), "did you mean customers") {
+		t.Errorf("wanted a suggestion, got %v", err)
+	}
+}
+
+func TestMetadataCache(t *testing.T) {
+	var count atomic.Int32
+	dir := t.TempDir()
+	for range 2 {
+		if _, err := bcctl(t, metadataHandler(&count), "", "-cache-dir", dir, "entities"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := count.Load(); n != 1 {
+		t.Errorf("wanted 1 request with the cache, got %d", n)
+	}
+
+	if _, err := bcctl(t, metadataHandler(&count), "", "-cache-dir", dir, "entities", "-refresh"); err != nil {
+		t.Fatal(err)
+	}
+	if n := count.Load(); n != 2 {
+		t.Errorf("wanted a request with -refresh, got %d", n)
+	}
+}
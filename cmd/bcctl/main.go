@@ -0,0 +1,135 @@
+// Command bcctl reads and writes Business Central API records from the command line.
+// It is also a small example of the bc package: every command is a few calls to
+// [bc.APIPage], [bc.Client.InvokeAction] or [bc.Client.NewRequestURL].
+//
+// Usage:
+//
+//	bcctl [flags] <command> [command flags] [args]
+//
+// The commands are:
+//
+//	get <entitySet> <id>              get a record
+//	list <entitySet>                  list records, following every page with -all
+//	query <statement>                 list records with a bcql statement
+//	create <entitySet>                create a record from the JSON of -data
+//	patch <entitySet> <id>            update a record from the JSON of -data
+//	delete <entitySet> <id>           delete a record
+//	action <entitySet> <id> <action>  invoke a bound action, e.g. "post"
+//	subscriptions list|create|delete  manage webhook subscriptions
+//	metadata                          download the $metadata document
+//	apply <file>                      converge configuration records to a file, see package apply
+//	entities                          list the entity sets with their actions
+//	describe <entitySet>              list the fields and actions of an entity set
+//	completion bash|zsh|fish          print a shell completion script
+//
+// The connection is configured with flags or the BC_TENANT_ID, BC_COMPANY_ID,
+// BC_ENVIRONMENT, BC_API_ENDPOINT, BC_CLIENT_ID and BC_CLIENT_SECRET environment variables.
+// The token is requested with [bc.NewDefaultTokenGetter]: managed identity,
+// the AZURE_* environment variables, the client secret and, with -interactive, a device code.
+//
+// The entity sets, fields and actions for discovery and completion come from the $metadata,
+// which is cached for a day in the user cache directory. Custom APIs are explored with -endpoint:
+//
+//	source <(bcctl completion bash)
+//	bcctl -endpoint contoso/warehouse/v1.0 entities
+//	bcctl -endpoint contoso/warehouse/v1.0 describe bins
+package main
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+
+	"github.com/erlorenz/bc-go/bc"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if err := run(ctx, os.Args[1:], os.Stdin, os.Stdout, os.Stderr); err != nil {
+		if !errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintln(os.Stderr, "bcctl:", err)
+		}
+		os.Exit(2)
+	}
+}
+
+// app has what the commands share.
+type app struct {
+	// client is nil for completion if the config is not valid.
+	client *bc.Client
+	format string
+	// cacheDir is where the $metadata is cached. It is empty to not cache it.
+	cacheDir string
+	stdin    io.Reader
+	stdout   io.Writer
+	stderr   io.Writer
+}
+
+// run parses the global flags, creates the client and runs the command.
+// The client options are applied after the defaults, so tests can replace the
+// HTTP client and the token getter.
+func run(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer, opts ...bc.ClientOption) error {
+	fs := flag.NewFlagSet("bcctl", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: bcctl [flags] <command> [args]")
+		fmt.Fprintln(stderr, "commands: get, list, query, create, patch, delete, action, subscriptions, metadata, apply, entities, describe, completion")
+		fs.PrintDefaults()
+	}
+
+	var config bc.ClientConfig
+	fs.StringVar(&config.TenantID, "tenant", os.Getenv("BC_TENANT_ID"), "Entra tenant `id`")
+	fs.StringVar(&config.CompanyID, "company", os.Getenv("BC_COMPANY_ID"), "company `id`")
+	fs.StringVar(&config.Environment, "environment", cmp.Or(os.Getenv("BC_ENVIRONMENT"), "Production"), "environment `name`")
+	fs.StringVar(&config.APIEndpoint, "endpoint", cmp.Or(os.Getenv("BC_API_ENDPOINT"), "v2.0"), "API endpoint, \"v2.0\" or \"<publisher>/<group>/<version>\"")
+	fs.StringVar(&config.ClientID, "client-id", os.Getenv("BC_CLIENT_ID"), "application `id`")
+	fs.StringVar(&config.ClientSecret, "client-secret", os.Getenv("BC_CLIENT_SECRET"), "client `secret`")
+	format := fs.String("o", "json", "output `format`: json, table or csv")
+	interactive := fs.Bool("interactive", false, "sign in with a device code if no other credential is available")
+	debug := fs.Bool("debug", false, "log the requests to stderr")
+	cacheDir := fs.String("cache-dir", defaultCacheDir(), "`directory` to cache the $metadata in, empty to not cache it")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return flag.ErrHelp
+	}
+	if _, ok := formatters[*format]; !ok {
+		return fmt.Errorf("unknown output format %q", *format)
+	}
+
+	level := slog.LevelWarn
+	if *debug {
+		level = slog.LevelDebug
+	}
+	logger := slog.New(slog.NewTextHandler(stderr, &slog.HandlerOptions{Level: level}))
+
+	tokenGetter := bc.NewDefaultTokenGetter(config, bc.DefaultTokenGetterOptions{
+		Interactive:      *interactive,
+		DeviceCodePrompt: func(message string) { fmt.Fprintln(stderr, message) },
+	})
+	command, args := fs.Arg(0), fs.Args()[1:]
+	cmd, ok := commands[command]
+	if !ok {
+		return fmt.Errorf("unknown command %q", command)
+	}
+
+	opts = append([]bc.ClientOption{bc.WithAuthClient(tokenGetter), bc.WithLogger(logger)}, opts...)
+	client, err := bc.NewClient(config, opts...)
+	// Completion still works without a valid config, just not for the entity sets
+	if err != nil && command != "completion" && command != "__complete" {
+		return err
+	}
+
+	a := &app{client: client, format: *format, cacheDir: *cacheDir, stdin: stdin, stdout: stdout, stderr: stderr}
+	return cmd(ctx, a, args)
+}
@@ -0,0 +1,240 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/erlorenz/bc-go/bcfake"
+	"github.com/erlorenz/bc-go/internal/bctest"
+	"github.com/google/uuid"
+)
+
+// tenantID is the same for every command so the $metadata cache is shared.
+var tenantID = uuid.NewString()
+
+// bcctl runs the command against the handler and returns stdout.
+func bcctl(t *testing.T, handler http.Handler, stdin string, args ...string) (string, error) {
+	t.Helper()
+	global := []string{
+		"-tenant", tenantID,
+		"-company", uuid.NewString(),
+		"-client-id", uuid.NewString(),
+		"-environment", "Sandbox",
+		"-cache-dir", "",
+	}
+	transport := bc.RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, r)
+		res := rec.Result()
+		res.Request = r
+		return res, nil
+	})
+
+	var stdout, stderr bytes.Buffer
+	err := run(context.Background(), append(global, args...), strings.NewReader(stdin), &stdout, &stderr,
+		bc.WithAuthClient(bctest.TokenGetter{}), bc.WithHTTPClient(&http.Client{Transport: transport}))
+	return stdout.String(), err
+}
+
+func TestCRUD(t *testing.T) {
+	server := bcfake.New()
+	ids := server.Seed("customers",
+		map[string]any{"number": "C001", "displayName": "Adatum"},
+		map[string]any{"number": "C002", "displayName": "Fabrikam"},
+	)
+
+	out, err := bcctl(t, server, "", "get", "customers", ids[0].String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]any
+	if err := json.Unmarshal([]byte(out), &got); err != nil || got["number"] != "C001" {
+		t.Errorf("unexpected record %s %v", out, err)
+	}
+
+	out, err = bcctl(t, server, "", "-o", "csv", "list", "-select", "number,displayName", "customers")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "number,displayName\nC001,Adatum\nC002,Fabrikam\n"; out != want {
+		t.Errorf("wanted %q, got %q", want, out)
+	}
+
+	out, err = bcctl(t, server, "", "-o", "table", "query", "-all", "SELECT number FROM customers")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "number\nC001\nC002\n"; out != want {
+		t.Errorf("wanted %q, got %q", want, out)
+	}
+
+	if _, err := bcctl(t, server, `{"number": "C003"}`, "create", "customers"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := bcctl(t, server, "", "patch", "-data", `{"displayName": "Adatum Corp"}`, "customers", ids[0].String()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := bcctl(t, server, "", "delete", "-etag", `W/"stale"`, "customers", ids[1].String()); err == nil {
+		t.Error("wanted error for a stale etag")
+	}
+	if _, err := bcctl(t, server, "", "delete", "customers", ids[1].String()); err != nil {
+		t.Fatal(err)
+	}
+
+	records := server.Records("customers")
+	if len(records) != 2 || records[0]["displayName"] != "Adatum Corp" || records[1]["number"] != "C003" {
+		t.Errorf("unexpected records %v", records)
+	}
+}
+
+func TestAction(t *testing.T) {
+	id := uuid.New()
+	var path string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	if _, err := bcctl(t, handler, "", "action", "salesInvoices", id.String(), "post"); err != nil {
+		t.Fatal(err)
+	}
+	if want := "/salesInvoices(" + id.String() + ")/Microsoft.NAV.post"; !strings.HasSuffix(path, want) {
+		t.Errorf("wanted path ending in %s, got %s", want, path)
+	}
+}
+
+func TestSubscriptions(t *testing.T) {
+	var created map[string]any
+	var ifMatch string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/api/v2.0/subscriptions") && r.Method != http.MethodDelete {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodGet:
+			io.WriteString(w, `{"value": [{"subscriptionId": "abc", "resource": "api/v2.0/companies(1)/customers", "notificationUrl": "https://example.com/hook"}]}`)
+		case http.MethodPost:
+			json.NewDecoder(r.Body).Decode(&created)
+			created["subscriptionId"] = "def"
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(created)
+		case http.MethodDelete:
+			if !strings.HasSuffix(r.URL.Path, "/api/v2.0/subscriptions('abc')") {
+				t.Errorf("unexpected path %s", r.URL.Path)
+			}
+			ifMatch = r.Header.Get("If-Match")
+			w.WriteHeader(http.StatusNoContent)
+		}
+	})
+
+	out, err := bcctl(t, handler, "", "-o", "csv", "subscriptions", "list")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "subscriptionId,resource,notificationUrl,expirationDateTime\nabc,api/v2.0/companies(1)/customers,https://example.com/hook,\n"; out != want {
+		t.Errorf("wanted %q, got %q", want, out)
+	}
+
+	if _, err := bcctl(t, handler, "", "subscriptions", "create", "-url", "https://example.com/hook", "customers"); err != nil {
+		t.Fatal(err)
+	}
+	if resource, _ := created["resource"].(string); !strings.HasPrefix(resource, "api/v2.0/companies(") || !strings.HasSuffix(resource, ")/customers") {
+		t.Errorf("unexpected resource %s", resource)
+	}
+
+	if _, err := bcctl(t, handler, "", "subscriptions", "delete", "abc"); err != nil {
+		t.Fatal(err)
+	}
+	if ifMatch != "*" {
+		t.Errorf("wanted If-Match *, got %s", ifMatch)
+	}
+}
+
+func TestMetadata(t *testing.T) {
+	const doc = `<?xml version="1.0"?><edmx:Edmx Version="4.0"></edmx:Edmx>`
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/api/v2.0/$metadata") {
+			w.WriteHeader(http.StatusNotFound)
+			io.WriteString(w, `{"error": {"code": "BadRequest_NotFound", "message": "Not found"}}`)
+			return
+		}
+		w.Header().Set("Content-Type", "application/xml")
+		io.WriteString(w, doc)
+	})
+
+	out, err := bcctl(t, handler, "", "metadata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != doc {
+		t.Errorf("unexpected document %s", out)
+	}
+
+	path := filepath.Join(t.TempDir(), "metadata.xml")
+	if _, err := bcctl(t, handler, "", "metadata", "-out", path); err != nil {
+		t.Fatal(err)
+	}
+	if b, _ := os.ReadFile(path); string(b) != doc {
+		t.Errorf("unexpected file %s", b)
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	server := bcfake.New()
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"NoCommand", nil},
+		{"UnknownCommand", []string{"sync"}},
+		{"UnknownFormat", []string{"-o", "xml", "list", "customers"}},
+		{"MissingID", []string{"get", "customers"}},
+		{"InvalidID", []string{"get", "customers", "abc"}},
+		{"InvalidData", []string{"create", "-data", "{", "customers"}},
+		{"SyntaxError", []string{"query", "SELECT FROM customers"}},
+		{"NotFound", []string{"get", "customers", uuid.NewString()}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := bcctl(t, server, "", tt.args...); err == nil {
+				t.Error("wanted error")
+			}
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	server := bcfake.New()
+	server.Seed("paymentTerms", map[string]any{"code": "NET30", "displayName": "Net 30"})
+	path := filepath.Join(t.TempDir(), "setup.json")
+	config := `{"entitySets": [{"name": "paymentTerms", "records": [{"code": "NET30", "displayName": "Net 30 days"}]}]}`
+	if err := os.WriteFile(path, []byte(config), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := bcctl(t, server, "", "apply", "-plan", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "1 to update") || server.Records("paymentTerms")[0]["displayName"] != "Net 30" {
+		t.Errorf("wanted only a plan, got %s", out)
+	}
+
+	out, err = bcctl(t, server, "", "apply", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "Applied: 0 created, 1 updated, 0 deleted.") || server.Records("paymentTerms")[0]["displayName"] != "Net 30 days" {
+		t.Errorf("wanted the update to be applied, got %s", out)
+	}
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"text/tabwriter"
+)
+
+// formatter writes the records with the columns in order.
+type formatter func(w io.Writer, records []record, columns []string) error
+
+var formatters = map[string]formatter{
+	"json":  writeJSON,
+	"table": writeTable,
+	"csv":   writeCSV,
+}
+
+// writeRecords writes the records in the output format. Without columns,
+// every field of the records is a column.
+func (a *app) writeRecords(records []record, columns []string) error {
+	if len(columns) == 0 {
+		columns = recordColumns(records)
+	}
+	return formatters[a.format](a.stdout, records, columns)
+}
+
+// writeRecord writes a single record. JSON is an object instead of an array.
+func (a *app) writeRecord(rec record, columns []string) error {
+	if a.format == "json" {
+		return encodeJSON(a.stdout, rec)
+	}
+	return a.writeRecords([]record{rec}, columns)
+}
+
+// recordColumns returns the fields of the records with "id" first and the rest sorted.
+// OData annotations like "@odata.etag" are left out.
+func recordColumns(records []record) []string {
+	fields := map[string]bool{}
+	for _, r := range records {
+		for k := range r {
+			if !strings.HasPrefix(k, "@") {
+				fields[k] = true
+			}
+		}
+	}
+	columns := slices.Sorted(maps.Keys(fields))
+	if i := slices.Index(columns, "id"); i > 0 {
+		columns = slices.Insert(slices.Delete(columns, i, i+1), 0, "id")
+	}
+	return columns
+}
+
+// cell formats a value for the table and CSV. Objects and arrays are JSON.
+func cell(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case map[string]any, []any:
+		b, _ := json.Marshal(v)
+		return string(b)
+	}
+	return fmt.Sprint(v)
+}
+
+func encodeJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func writeJSON(w io.Writer, records []record, _ []string) error {
+	if records == nil {
+		records = []record{}
+	}
+	return encodeJSON(w, records)
+}
+
+func writeTable(w io.Writer, records []record, columns []string) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(columns, "\t"))
+	row := make([]string, len(columns))
+	for _, r := range records {
+		for i, c := range columns {
+			// Tabs and newlines would break the alignment
+			row[i] = strings.NewReplacer("\t", " ", "\n", " ").Replace(cell(r[c]))
+		}
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+func writeCSV(w io.Writer, records []record, columns []string) error {
+	cw := csv.NewWriter(w)
+	cw.Write(columns)
+	row := make([]string, len(columns))
+	for _, r := range records {
+		for i, c := range columns {
+			row[i] = cell(r[c])
+		}
+		cw.Write(row)
+	}
+	cw.Flush()
+	return cw.Error()
+}
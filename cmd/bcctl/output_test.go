@@ -0,0 +1,46 @@
+package main
+
+import (
+	"bytes"
+	"slices"
+	"testing"
+)
+
+func TestRecordColumns(t *testing.T) {
+	records := []record{
+		{"@odata.etag": `W/"1"`, "number": "C001", "id": "1"},
+		{"displayName": "Adatum", "id": "2"},
+	}
+	if got, want := recordColumns(records), []string{"id", "displayName", "number"}; !slices.Equal(got, want) {
+		t.Errorf("wanted %v, got %v", want, got)
+	}
+}
+
+func TestFormatters(t *testing.T) {
+	records := []record{
+		{"number": "C001", "balance": 12.5, "address": map[string]any{"city": "Oslo"}},
+		{"number": "C\t002", "balance": nil, "tags": []any{"a", "b"}},
+	}
+	columns := []string{"number", "balance", "address"}
+
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{"json", "[\n  {\n    \"address\": {\n      \"city\": \"Oslo\"\n    },\n    \"balance\": 12.5,\n    \"number\": \"C001\"\n  },\n" +
+			"  {\n    \"balance\": null,\n    \"number\": \"C\\t002\",\n    \"tags\": [\n      \"a\",\n      \"b\"\n    ]\n  }\n]\n"},
+		{"table", "number  balance  address\nC001    12.5     {\"city\":\"Oslo\"}\nC 002            \n"},
+		{"csv", "number,balance,address\nC001,12.5,\"{\"\"city\"\":\"\"Oslo\"\"}\"\nC\t002,,\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := formatters[tt.format](&buf, records, columns); err != nil {
+				t.Fatal(err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("wanted %q, got %q", tt.want, buf.String())
+			}
+		})
+	}
+}
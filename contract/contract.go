@@ -0,0 +1,226 @@
+// Package contract checks that typed models still match the entity definitions in the
+// $metadata of an environment, so a BC upgrade that removes a field or changes its type
+// fails a test instead of silently decoding zero values. Save the $metadata with
+// "bcctl metadata -out testdata/metadata.xml" and generate the tests with
+// cmd/bccontract, or write them by hand:
+//
+//	func TestContract(t *testing.T) {
+//		caps := contract.Load(t, "testdata/metadata.xml")
+//		contract.Assert(t, caps, "customers", models.Customer{})
+//	}
+package contract
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/google/uuid"
+)
+
+// Kind is the kind of a [Mismatch].
+type Kind string
+
+const (
+	// KindMissingEntitySet is an entity set of a model that is not in the $metadata.
+	KindMissingEntitySet Kind = "missing entity set"
+	// KindMissingField is a field of the model that is not in the $metadata, which
+	// always decodes to the zero value.
+	KindMissingField Kind = "missing field"
+	// KindType is a field whose Go type cannot decode the EDM type.
+	KindType Kind = "type"
+	// KindNullable is a pointer field for a property that is never null.
+	KindNullable Kind = "nullable"
+	// KindNewField is a property in the $metadata that the model does not have. It is
+	// not an error, but shows what an upgrade added.
+	KindNewField Kind = "new field"
+)
+
+// Mismatch is a difference between a model and its entity definition.
+type Mismatch struct {
+	Kind          Kind
+	EntitySetName string
+	// Field is the JSON name, and GoType the type of the model field if it has one.
+	Field   string
+	GoType  string
+	EDMType string
+}
+
+func (m Mismatch) String() string {
+	switch m.Kind {
+	case KindMissingEntitySet:
+		return fmt.Sprintf("%s: entity set is not in the $metadata", m.EntitySetName)
+	case KindMissingField:
+		return fmt.Sprintf("%s.%s: field is not in the $metadata", m.EntitySetName, m.Field)
+	case KindType:
+		return fmt.Sprintf("%s.%s: %s cannot decode %s", m.EntitySetName, m.Field, m.GoType, m.EDMType)
+	case KindNullable:
+		return fmt.Sprintf("%s.%s: %s is a pointer but %s is not nullable", m.EntitySetName, m.Field, m.GoType, m.EDMType)
+	}
+	return fmt.Sprintf("%s.%s: new %s field", m.EntitySetName, m.Field, m.EDMType)
+}
+
+// Load parses a saved $metadata document, failing the test if it cannot be read.
+func Load(t testing.TB, path string) bc.Capabilities {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("load $metadata: %s", err)
+	}
+	defer f.Close()
+	caps, err := bc.ParseCapabilities(f)
+	if err != nil {
+		t.Fatalf("load $metadata %s: %s", path, err)
+	}
+	return caps
+}
+
+// Assert fails the test for every mismatch of the model, a struct or a pointer to
+// one, except KindNewField, which is logged.
+func Assert(t testing.TB, caps bc.Capabilities, entitySetName string, model any) {
+	t.Helper()
+	for _, m := range Check(caps, entitySetName, model) {
+		if m.Kind == KindNewField {
+			t.Log(m)
+			continue
+		}
+		t.Error(m)
+	}
+}
+
+// field is a JSON field of a model.
+type field struct {
+	name string
+	typ  reflect.Type
+}
+
+// fields returns the JSON fields of the struct type, including those of embedded
+// structs, without the "@odata" annotations and the ignored fields.
+func fields(typ reflect.Type) []field {
+	var fs []field
+	for i := range typ.NumField() {
+		sf := typ.Field(i)
+		tag := sf.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if sf.Anonymous && name == "" && sf.Type.Kind() == reflect.Struct {
+			fs = append(fs, fields(sf.Type)...)
+			continue
+		}
+		if !sf.IsExported() || strings.HasPrefix(name, "@") {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		fs = append(fs, field{name: name, typ: sf.Type})
+	}
+	return fs
+}
+
+// Check returns the mismatches of the model, a struct or a pointer to one, with the
+// entity set in the $metadata. Fields with a slice, map or struct type that is not a
+// known scalar, like expanded navigation properties, are only checked for presence.
+func Check(caps bc.Capabilities, entitySetName string, model any) []Mismatch {
+	if !caps.HasEntitySet(entitySetName) {
+		return []Mismatch{{Kind: KindMissingEntitySet, EntitySetName: entitySetName}}
+	}
+	typ := reflect.TypeOf(model)
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	var mismatches []Mismatch
+	seen := map[string]bool{}
+	for _, f := range fields(typ) {
+		seen[f.name] = true
+		m := Mismatch{EntitySetName: entitySetName, Field: f.name, GoType: f.typ.String()}
+		p, ok := caps.Property(entitySetName, f.name)
+		if !ok {
+			// A navigation property has no type to check
+			if !caps.HasField(entitySetName, f.name) {
+				m.Kind = KindMissingField
+				mismatches = append(mismatches, m)
+			}
+			continue
+		}
+		m.EDMType = p.Type
+		if decodes(f.typ, p.Type) == no {
+			m.Kind = KindType
+			mismatches = append(mismatches, m)
+		} else if f.typ.Kind() == reflect.Pointer && !p.Nullable {
+			m.Kind = KindNullable
+			mismatches = append(mismatches, m)
+		}
+	}
+	for _, p := range caps.Properties[entitySetName] {
+		if !seen[p.Name] {
+			mismatches = append(mismatches, Mismatch{Kind: KindNewField, EntitySetName: entitySetName, Field: p.Name, EDMType: p.Type})
+		}
+	}
+	return mismatches
+}
+
+type result int
+
+const (
+	no result = iota
+	yes
+	// unknown is a type that is not checked.
+	unknown
+)
+
+var (
+	uuidType = reflect.TypeOf(uuid.UUID{})
+	timeType = reflect.TypeOf(time.Time{})
+	dateType = reflect.TypeOf(bc.Date{})
+	guidType = reflect.TypeOf(bc.GUID(""))
+)
+
+// decodes returns whether a JSON value of the EDM type decodes into the Go type.
+func decodes(typ reflect.Type, edm string) result {
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	is := func(types ...string) result {
+		for _, t := range types {
+			if edm == t {
+				return yes
+			}
+		}
+		return no
+	}
+
+	switch typ {
+	case uuidType, guidType:
+		return is("Edm.Guid")
+	case timeType:
+		return is("Edm.DateTimeOffset")
+	case dateType:
+		return is("Edm.Date")
+	}
+	switch typ.Kind() {
+	case reflect.String:
+		// Enums are serialized as their member names
+		if !strings.HasPrefix(edm, "Edm.") {
+			return yes
+		}
+		return is("Edm.String", "Edm.Guid", "Edm.Date", "Edm.DateTimeOffset", "Edm.TimeOfDay", "Edm.Duration", "Edm.Binary", "Edm.Stream")
+	case reflect.Bool:
+		return is("Edm.Boolean")
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return is("Edm.Int16", "Edm.Int32", "Edm.Int64", "Edm.Byte", "Edm.SByte")
+	case reflect.Float32, reflect.Float64:
+		return is("Edm.Decimal", "Edm.Double", "Edm.Single", "Edm.Int16", "Edm.Int32", "Edm.Int64", "Edm.Byte", "Edm.SByte")
+	case reflect.Interface:
+		return yes
+	}
+	return unknown
+}
@@ -0,0 +1,109 @@
+package contract_test
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/erlorenz/bc-go/contract"
+	"github.com/google/uuid"
+)
+
+type base struct {
+	OdataEtag string    `json:"@odata.etag"`
+	ID        uuid.UUID `json:"id"`
+}
+
+type customer struct {
+	base
+	Number               string    `json:"number"`
+	Type                 string    `json:"type"`
+	Balance              float64   `json:"balance"`
+	LastModifiedDateTime time.Time `json:"lastModifiedDateTime"`
+	PaymentTerm          *struct{} `json:"paymentTerm,omitempty"`
+	// internal is not decoded, so it is not checked.
+	internal string
+}
+
+func TestCheck(t *testing.T) {
+	caps := contract.Load(t, "testdata/metadata.xml")
+
+	if got := contract.Check(caps, "customers", customer{}); len(got) != 1 || got[0].Kind != contract.KindNewField || got[0].Field != "blocked" {
+		t.Errorf("wanted only the new blocked field, got %v", got)
+	}
+
+	type changed struct {
+		ID                   *uuid.UUID `json:"id"`
+		Number               int        `json:"number"`
+		Blocked              bool       `json:"blocked"`
+		Balance              bc.Date    `json:"balance"`
+		LastModifiedDateTime *time.Time `json:"lastModifiedDateTime"`
+		Email                string     `json:"email"`
+		Ignored              string     `json:"-"`
+	}
+	var got []string
+	for _, m := range contract.Check(caps, "customers", &changed{}) {
+		got = append(got, string(m.Kind)+" "+m.Field)
+	}
+	want := []string{
+		"nullable id",
+		"type number",
+		"type blocked",
+		"type balance",
+		"nullable lastModifiedDateTime",
+		"missing field email",
+		"new field type",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("wanted %q, got %q", want, got)
+	}
+
+	if got := contract.Check(caps, "vendors", customer{}); len(got) != 1 || got[0].Kind != contract.KindMissingEntitySet {
+		t.Errorf("wanted a missing entity set, got %v", got)
+	}
+}
+
+func TestMismatchString(t *testing.T) {
+	table := []struct {
+		m    contract.Mismatch
+		want string
+	}{
+		{contract.Mismatch{Kind: contract.KindMissingEntitySet, EntitySetName: "vendors"}, "vendors: entity set is not in the $metadata"},
+		{contract.Mismatch{Kind: contract.KindMissingField, EntitySetName: "customers", Field: "email"}, "customers.email: field is not in the $metadata"},
+		{contract.Mismatch{Kind: contract.KindType, EntitySetName: "customers", Field: "number", GoType: "int", EDMType: "Edm.String"}, "customers.number: int cannot decode Edm.String"},
+		{contract.Mismatch{Kind: contract.KindNullable, EntitySetName: "customers", Field: "id", GoType: "*uuid.UUID", EDMType: "Edm.Guid"}, "customers.id: *uuid.UUID is a pointer but Edm.Guid is not nullable"},
+		{contract.Mismatch{Kind: contract.KindNewField, EntitySetName: "customers", Field: "blocked", EDMType: "Edm.String"}, "customers.blocked: new Edm.String field"},
+	}
+	for _, tt := range table {
+		if got := tt.m.String(); got != tt.want {
+			t.Errorf("wanted %q, got %q", tt.want, got)
+		}
+	}
+}
+
+// recorder records the failures of Assert.
+type recorder struct {
+	testing.TB
+	errors, logs int
+}
+
+func (r *recorder) Helper()           {}
+func (r *recorder) Error(args ...any) { r.errors++ }
+func (r *recorder) Log(args ...any)   { r.logs++ }
+
+func TestAssert(t *testing.T) {
+	caps := contract.Load(t, "testdata/metadata.xml")
+
+	r := &recorder{TB: t}
+	contract.Assert(r, caps, "customers", customer{})
+	if r.errors != 0 || r.logs != 1 {
+		t.Errorf("wanted 1 log and no errors, got %d logs and %d errors", r.logs, r.errors)
+	}
+
+	r = &recorder{TB: t}
+	contract.Assert(r, caps, "vendors", customer{})
+	if r.errors != 1 {
+		t.Errorf("wanted 1 error, got %d", r.errors)
+	}
+}
@@ -0,0 +1,110 @@
+package contract
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Model is a typed model and the entity set it is read from.
+type Model struct {
+	TypeName      string
+	EntitySetName string
+}
+
+// FindModels returns the models of the Go package in dir, found from the calls of a
+// generic function with the model as type argument and the entity set as last
+// string literal, like bc.NewAPIPage[Customer](client, "customers"). A literal with a
+// path, like a navigation "salesOrders(id)/salesOrderLines", uses the last segment.
+func FindModels(dir string) ([]Model, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("could not read package: %w", err)
+	}
+
+	fset := token.NewFileSet()
+	var models []Model
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".go") || strings.HasSuffix(e.Name(), "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, filepath.Join(dir, e.Name()), nil, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse package: %w", err)
+		}
+		ast.Inspect(file, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok || len(call.Args) == 0 {
+				return true
+			}
+			index, ok := call.Fun.(*ast.IndexExpr)
+			if !ok {
+				return true
+			}
+			typ, ok := index.Index.(*ast.Ident)
+			if !ok || !ast.IsExported(typ.Name) {
+				return true
+			}
+			lit, ok := call.Args[len(call.Args)-1].(*ast.BasicLit)
+			if !ok || lit.Kind != token.STRING {
+				return true
+			}
+			name, err := strconv.Unquote(lit.Value)
+			if err != nil || name == "" {
+				return true
+			}
+			name = name[strings.LastIndex(name, "/")+1:]
+			m := Model{TypeName: typ.Name, EntitySetName: name}
+			if !slices.Contains(models, m) {
+				models = append(models, m)
+			}
+			return true
+		})
+	}
+	slices.SortFunc(models, func(a, b Model) int { return strings.Compare(a.EntitySetName, b.EntitySetName) })
+	return models, nil
+}
+
+// GenerateOptions configure [Generate].
+type GenerateOptions struct {
+	// Package is the package of the test file, e.g. "models_test".
+	Package string
+	// ImportPath is the import path of the models, e.g. "github.com/erlorenz/bc-go/models".
+	ImportPath string
+	// MetadataPath is the $metadata file the test loads, relative to the test file.
+	MetadataPath string
+}
+
+// Generate writes a test file that asserts every model matches its entity set in the
+// $metadata at opts.MetadataPath.
+func Generate(w io.Writer, models []Model, opts GenerateOptions) error {
+	alias := opts.ImportPath[strings.LastIndex(opts.ImportPath, "/")+1:]
+	alias = strings.NewReplacer("-", "", ".", "").Replace(alias)
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by bccontract from %s. DO NOT EDIT.\n\n", opts.MetadataPath)
+	fmt.Fprintf(&b, "package %s\n\n", opts.Package)
+	fmt.Fprintf(&b, "import (\n\t\"testing\"\n\n\t\"github.com/erlorenz/bc-go/contract\"\n\t%s %q\n)\n\n", alias, opts.ImportPath)
+	b.WriteString("func TestContract(t *testing.T) {\n")
+	fmt.Fprintf(&b, "\tcaps := contract.Load(t, %q)\n", opts.MetadataPath)
+	for _, m := range models {
+		fmt.Fprintf(&b, "\tt.Run(%q, func(t *testing.T) { contract.Assert(t, caps, %q, %s.%s{}) })\n", m.EntitySetName, m.EntitySetName, alias, m.TypeName)
+	}
+	b.WriteString("}\n")
+
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		return fmt.Errorf("could not format test: %w", err)
+	}
+	_, err = w.Write(src)
+	return err
+}
@@ -0,0 +1,62 @@
+package contract_test
+
+import (
+	"bytes"
+	"go/parser"
+	"go/token"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/erlorenz/bc-go/contract"
+)
+
+func TestFindModels(t *testing.T) {
+	models, err := contract.FindModels("../models")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []contract.Model{
+		{TypeName: "Customer", EntitySetName: "customers"},
+		{TypeName: "SalesOrder", EntitySetName: "salesOrders"},
+		{TypeName: "SalesOrderLine", EntitySetName: "salesOrderLines"},
+	} {
+		if !slices.Contains(models, want) {
+			t.Errorf("wanted %+v in %+v", want, models)
+		}
+	}
+	if !slices.IsSortedFunc(models, func(a, b contract.Model) int { return strings.Compare(a.EntitySetName, b.EntitySetName) }) {
+		t.Errorf("wanted the models sorted by entity set, got %+v", models)
+	}
+
+	if _, err := contract.FindModels("testdata/missing"); err == nil {
+		t.Error("wanted an error for a missing directory")
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	var b bytes.Buffer
+	err := contract.Generate(&b, []contract.Model{{TypeName: "Customer", EntitySetName: "customers"}}, contract.GenerateOptions{
+		Package:      "models_test",
+		ImportPath:   "github.com/erlorenz/bc-go/models",
+		MetadataPath: "testdata/metadata.xml",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := b.String()
+	if _, err := parser.ParseFile(token.NewFileSet(), "contract_test.go", src, 0); err != nil {
+		t.Fatalf("generated invalid Go: %s\n%s", err, src)
+	}
+	for _, want := range []string{
+		"// Code generated by bccontract from testdata/metadata.xml. DO NOT EDIT.\n",
+		"package models_test\n",
+		`"github.com/erlorenz/bc-go/models"`,
+		`caps := contract.Load(t, "testdata/metadata.xml")`,
+		`contract.Assert(t, caps, "customers", models.Customer{})`,
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("wanted %q in\n%s", want, src)
+		}
+	}
+}
@@ -0,0 +1,188 @@
+// Package copier copies records from one company or environment to another, e.g. to
+// refresh a sandbox or to create customers from a template company. Both sides are
+// bc.Clients, so the source and target can be in different tenants.
+//
+// A record is read with its expanded navigation properties, like the lines of a sales
+// order, and created with a deep insert. Its GUID references are mapped through their
+// natural keys: the customerId of the source is read as the customer's number, and the
+// customer with that number in the target gives the new customerId.
+//
+//	c := copier.New(template, company)
+//	result, err := c.Copy(ctx, copier.Spec{
+//		EntitySetName: "salesOrders",
+//		Key:           "externalDocumentNumber",
+//		Expand:        []string{"salesOrderLines"},
+//		References: map[string]copier.Reference{
+//			"customerId": {EntitySetName: "customers", Key: "number"},
+//			"itemId":     {EntitySetName: "items", Key: "number"},
+//		},
+//	}, orderID)
+package copier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/google/uuid"
+)
+
+// ErrReferenceMissing is returned when a referenced record has no record with the same
+// natural key in the target.
+var ErrReferenceMissing = errors.New("referenced record missing in target")
+
+// Reference maps a GUID field to the natural key of the entity set it refers to.
+type Reference struct {
+	// EntitySetName is the referenced entity set, e.g. "customers".
+	EntitySetName string
+	// Key is the field that identifies the record in both companies, e.g. "number".
+	Key string
+}
+
+// Spec describes what to copy.
+type Spec struct {
+	// EntitySetName is the entity set of the record, e.g. "salesOrders".
+	EntitySetName string
+	// Key is the natural key of the record. If set, a record with the same key in the
+	// target is not copied again.
+	Key string
+	// Expand are the navigation properties copied with the record, e.g. "salesOrderLines".
+	Expand []string
+	// References map the GUID fields of the record and its expanded records to the
+	// natural key of their entity set, e.g. "customerId" to the "number" of "customers".
+	References map[string]Reference
+	// Omit are more fields left out of the copy, e.g. read-only totals. The id, the
+	// OData annotations, lastModifiedDateTime and GUID fields ending in "Id" without a
+	// Reference are always left out, as they are of the source.
+	Omit []string
+}
+
+// Result is the outcome of [Copier.Copy].
+type Result struct {
+	// ID is the id of the record in the target.
+	ID uuid.UUID
+	// Created is false if the target had a record with the same Key.
+	Created bool
+}
+
+// Copier copies records between two clients. It remembers the mapped references, so
+// copying many records reads each referenced record once. It is safe for concurrent use.
+type Copier struct {
+	from     *bc.Client
+	to       *bc.Client
+	remapper *Remapper
+}
+
+// New creates a Copier from the source to the target client. Use [Remapper.Spec] for
+// the References of a Spec from the $metadata.
+func New(from, to *bc.Client) *Copier {
+	return &Copier{from: from, to: to, remapper: NewRemapper(from, to, bc.Capabilities{}, RemapOptions{})}
+}
+
+// Copy copies the record with the id from the source to the target.
+func (c *Copier) Copy(ctx context.Context, spec Spec, id uuid.UUID) (Result, error) {
+	if spec.EntitySetName == "" {
+		return Result{}, errors.New("copy: EntitySetName is required")
+	}
+	source, err := bc.NewAPIPage[record](c.from, spec.EntitySetName).Get(ctx, id, bc.GetOptions{Expand: spec.Expand})
+	if err != nil {
+		return Result{}, fmt.Errorf("copy %s(%s): read source: %w", spec.EntitySetName, id, err)
+	}
+
+	if spec.Key != "" {
+		if existing, ok, err := c.remapper.find(ctx, spec.EntitySetName, spec.Key, source[spec.Key]); err != nil {
+			return Result{}, fmt.Errorf("copy %s(%s): %w", spec.EntitySetName, id, err)
+		} else if ok {
+			c.to.Logger().Debug("Record exists in target, not copying.", "entitySetName", spec.EntitySetName, "key", source[spec.Key])
+			return Result{ID: existing}, nil
+		}
+	}
+
+	body, err := c.body(ctx, spec, source)
+	if err != nil {
+		return Result{}, fmt.Errorf("copy %s(%s): %w", spec.EntitySetName, id, err)
+	}
+	created, err := bc.NewAPIPage[record](c.to, spec.EntitySetName).Create(ctx, body, bc.GetOptions{})
+	if err != nil {
+		return Result{}, fmt.Errorf("copy %s(%s): create in target: %w", spec.EntitySetName, id, err)
+	}
+	newID, _ := uuid.Parse(fmt.Sprint(created["id"]))
+	return Result{ID: newID, Created: true}, nil
+}
+
+// body returns the record without the fields of the source and with its references mapped.
+func (c *Copier) body(ctx context.Context, spec Spec, r record) (record, error) {
+	body := record{}
+	for field, v := range r {
+		if slices.Contains(spec.Expand, field) {
+			lines, err := c.expanded(ctx, spec, field, v)
+			if err != nil {
+				return nil, err
+			}
+			body[field] = lines
+			continue
+		}
+		if ref, ok := spec.References[field]; ok {
+			mapped, err := c.remapper.Map(ctx, ref, v)
+			if err != nil {
+				return nil, fmt.Errorf("map %s: %w", field, err)
+			}
+			body[field] = mapped
+			continue
+		}
+		if omitted(spec, field, v) {
+			continue
+		}
+		body[field] = v
+	}
+	return body, nil
+}
+
+// expanded returns the bodies of an expanded navigation property, a list or a single record.
+func (c *Copier) expanded(ctx context.Context, spec Spec, field string, v any) (any, error) {
+	switch v := v.(type) {
+	case []any:
+		lines := make([]any, 0, len(v))
+		for i, item := range v {
+			m, ok := item.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("%s[%d] is not a record", field, i)
+			}
+			line, err := c.body(ctx, spec, m)
+			if err != nil {
+				return nil, fmt.Errorf("%s[%d]: %w", field, i, err)
+			}
+			lines = append(lines, line)
+		}
+		return lines, nil
+	case map[string]any:
+		line, err := c.body(ctx, spec, v)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", field, err)
+		}
+		return line, nil
+	}
+	return v, nil
+}
+
+// omitted returns true for the fields that belong to the source.
+func omitted(spec Spec, field string, v any) bool {
+	if field == "id" || field == "lastModifiedDateTime" || strings.Contains(field, "@") || slices.Contains(spec.Omit, field) {
+		return true
+	}
+	if strings.HasSuffix(field, "Id") {
+		_, err := uuid.Parse(fmt.Sprint(v))
+		return err == nil
+	}
+	return false
+}
+
+// record decodes any entity as a map of its fields.
+type record map[string]any
+
+func (record) Validate() error {
+	return nil
+}
@@ -0,0 +1,104 @@
+package copier_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/erlorenz/bc-go/bcfake"
+	"github.com/erlorenz/bc-go/copier"
+	"github.com/erlorenz/bc-go/internal/bctest"
+	"github.com/google/uuid"
+)
+
+var orderSpec = copier.Spec{
+	EntitySetName: "salesOrders",
+	Key:           "externalDocumentNumber",
+	Expand:        []string{"salesOrderLines"},
+	References: map[string]copier.Reference{
+		"customerId": {EntitySetName: "customers", Key: "number"},
+		"itemId":     {EntitySetName: "items", Key: "number"},
+	},
+	Omit: []string{"totalAmountIncludingTax"},
+}
+
+func TestCopy(t *testing.T) {
+	ctx := context.Background()
+	from, to := bcfake.New(), bcfake.New()
+	customers := from.Seed("customers", map[string]any{"number": "C001"})
+	items := from.Seed("items", map[string]any{"number": "1000"}, map[string]any{"number": "1001"})
+	orders := from.Seed("salesOrders", map[string]any{
+		"externalDocumentNumber":  "WEB-1",
+		"customerId":              customers[0].String(),
+		"totalAmountIncludingTax": 125.0,
+		"shipToAddressId":         uuid.NewString(),
+		"salesOrderLines": []any{
+			map[string]any{"id": uuid.NewString(), "itemId": items[0].String(), "quantity": 2.0},
+			map[string]any{"id": uuid.NewString(), "itemId": items[1].String(), "quantity": 1.0},
+		},
+	})
+	targetCustomers := to.Seed("customers", map[string]any{"number": "c001"})
+	targetItems := to.Seed("items", map[string]any{"number": "1001"}, map[string]any{"number": "1000"})
+
+	c := copier.New(bctest.NewClient(t, from), bctest.NewClient(t, to))
+	result, err := c.Copy(ctx, orderSpec, orders[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Created || result.ID == uuid.Nil || result.ID == orders[0] {
+		t.Fatalf("wanted a new record, got %+v", result)
+	}
+
+	created := to.Records("salesOrders")
+	if len(created) != 1 {
+		t.Fatalf("wanted 1 order in the target, got %d", len(created))
+	}
+	order := created[0]
+	if order["customerId"] != targetCustomers[0].String() {
+		t.Errorf("wanted customerId %s, got %v", targetCustomers[0], order["customerId"])
+	}
+	for _, field := range []string{"totalAmountIncludingTax", "shipToAddressId"} {
+		if _, ok := order[field]; ok {
+			t.Errorf("wanted %s to be left out, got %v", field, order[field])
+		}
+	}
+	lines, _ := order["salesOrderLines"].([]any)
+	if len(lines) != 2 {
+		t.Fatalf("wanted 2 lines, got %v", order["salesOrderLines"])
+	}
+	for i, want := range []uuid.UUID{targetItems[1], targetItems[0]} {
+		line := lines[i].(map[string]any)
+		if line["itemId"] != want.String() {
+			t.Errorf("line %d: wanted itemId %s, got %v", i, want, line["itemId"])
+		}
+		if _, ok := line["id"]; ok {
+			t.Errorf("line %d: wanted the id to be left out", i)
+		}
+	}
+
+	again, err := c.Copy(ctx, orderSpec, orders[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.Created || again.ID != result.ID {
+		t.Errorf("wanted the existing order %s, got %+v", result.ID, again)
+	}
+	if n := len(to.Records("salesOrders")); n != 1 {
+		t.Errorf("wanted no second order, got %d", n)
+	}
+}
+
+func TestCopyReferenceMissing(t *testing.T) {
+	from, to := bcfake.New(), bcfake.New()
+	customers := from.Seed("customers", map[string]any{"number": "C001"})
+	orders := from.Seed("salesOrders", map[string]any{"externalDocumentNumber": "WEB-1", "customerId": customers[0].String()})
+	to.Seed("customers", map[string]any{"number": "C002"})
+
+	_, err := copier.New(bctest.NewClient(t, from), bctest.NewClient(t, to)).Copy(context.Background(), orderSpec, orders[0])
+	if !errors.Is(err, copier.ErrReferenceMissing) {
+		t.Fatalf("wanted ErrReferenceMissing, got %v", err)
+	}
+	if n := len(to.Records("salesOrders")); n != 0 {
+		t.Errorf("wanted no order created, got %d", n)
+	}
+}
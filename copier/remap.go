@@ -0,0 +1,216 @@
+package copier
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"strings"
+	"sync"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/erlorenz/bc-go/filter"
+	"github.com/google/uuid"
+)
+
+// RemapOptions configure [NewRemapper].
+type RemapOptions struct {
+	// Keys map entity set names to their natural key, e.g. "customers" to "number".
+	// Entity sets without one use "number" or "code", the first they have in the $metadata.
+	Keys map[string]string
+}
+
+// Remapper maps the GUID references of records of the source to the ids of the records
+// with the same natural key in the target. The references are found in the $metadata:
+// a navigation property with a referential constraint, like the customer of a sales
+// order with "customerId", makes customerId a reference to customers. It remembers the
+// mapped ids and is safe for concurrent use.
+type Remapper struct {
+	from *bc.Client
+	to   *bc.Client
+	caps bc.Capabilities
+	keys map[string]string
+
+	mu     sync.Mutex
+	mapped map[string]uuid.UUID
+}
+
+// NewRemapper creates a Remapper from the source to the target client with the
+// capabilities of the target, see [bc.Client.Capabilities].
+func NewRemapper(from, to *bc.Client, caps bc.Capabilities, opts RemapOptions) *Remapper {
+	return &Remapper{from: from, to: to, caps: caps, keys: opts.Keys, mapped: map[string]uuid.UUID{}}
+}
+
+// References returns the references of the entity set and of the navigation properties
+// in expand, e.g. the itemId of the "salesOrderLines" of "salesOrders", keyed by field.
+// References to entity sets without a natural key are left out.
+func (r *Remapper) References(entitySetName string, expand ...string) map[string]Reference {
+	refs := map[string]Reference{}
+	r.addReferences(refs, entitySetName)
+	for _, name := range expand {
+		if nav, ok := r.caps.NavigationProperty(entitySetName, name); ok && nav.EntitySetName != "" {
+			r.addReferences(refs, nav.EntitySetName)
+		}
+	}
+	return refs
+}
+
+func (r *Remapper) addReferences(refs map[string]Reference, entitySetName string) {
+	for _, nav := range r.caps.NavigationProperties[entitySetName] {
+		if nav.Collection || nav.EntitySetName == "" {
+			continue
+		}
+		key := r.key(nav.EntitySetName)
+		if key == "" {
+			continue
+		}
+		for field, referenced := range nav.Constraints {
+			if referenced == "id" {
+				refs[field] = Reference{EntitySetName: nav.EntitySetName, Key: key}
+			}
+		}
+	}
+}
+
+// key returns the natural key of the entity set, or an empty string if it has none.
+func (r *Remapper) key(entitySetName string) string {
+	if key, ok := r.keys[entitySetName]; ok {
+		return key
+	}
+	for _, key := range []string{"number", "code"} {
+		if r.caps.HasField(entitySetName, key) {
+			return key
+		}
+	}
+	return ""
+}
+
+// Spec returns a [Spec] for [Copier.Copy] with the References from the $metadata.
+func (r *Remapper) Spec(entitySetName string, expand ...string) Spec {
+	return Spec{
+		EntitySetName: entitySetName,
+		Expand:        expand,
+		References:    r.References(entitySetName, expand...),
+	}
+}
+
+// Remap returns a copy of the record of the entity set with its references mapped to
+// the ids of the target, e.g. a payload read from a sandbox for a load into production.
+// Expanded navigation properties are remapped with the references of their entity set.
+// Other fields, including the id, are kept as they are.
+func (r *Remapper) Remap(ctx context.Context, entitySetName string, rec map[string]any) (map[string]any, error) {
+	refs := r.References(entitySetName)
+	out := maps.Clone(rec)
+	for field, v := range rec {
+		if ref, ok := refs[field]; ok {
+			mapped, err := r.Map(ctx, ref, v)
+			if err != nil {
+				return nil, fmt.Errorf("remap %s.%s: %w", entitySetName, field, err)
+			}
+			out[field] = mapped
+			continue
+		}
+		nav, ok := r.caps.NavigationProperty(entitySetName, field)
+		if !ok || nav.EntitySetName == "" {
+			continue
+		}
+		switch v := v.(type) {
+		case []any:
+			lines := make([]any, len(v))
+			for i, item := range v {
+				m, ok := item.(map[string]any)
+				if !ok {
+					return nil, fmt.Errorf("remap %s.%s[%d]: not a record", entitySetName, field, i)
+				}
+				line, err := r.Remap(ctx, nav.EntitySetName, m)
+				if err != nil {
+					return nil, fmt.Errorf("remap %s.%s[%d]: %w", entitySetName, field, i, err)
+				}
+				lines[i] = line
+			}
+			out[field] = lines
+		case map[string]any:
+			m, err := r.Remap(ctx, nav.EntitySetName, v)
+			if err != nil {
+				return nil, fmt.Errorf("remap %s.%s: %w", entitySetName, field, err)
+			}
+			out[field] = m
+		}
+	}
+	return out, nil
+}
+
+// Map returns the id of the target record with the natural key of the source record
+// with the id in v. Values that are not a GUID, or the empty GUID, are returned as is.
+// It returns an error matching [ErrReferenceMissing] if the target has no such record.
+func (r *Remapper) Map(ctx context.Context, ref Reference, v any) (any, error) {
+	sourceID, err := uuid.Parse(fmt.Sprint(v))
+	if err != nil || sourceID == uuid.Nil {
+		return v, nil
+	}
+	cacheKey := ref.EntitySetName + "(" + sourceID.String() + ")"
+	r.mu.Lock()
+	targetID, ok := r.mapped[cacheKey]
+	r.mu.Unlock()
+	if ok {
+		return targetID, nil
+	}
+
+	source, err := bc.NewAPIPage[record](r.from, ref.EntitySetName).Get(ctx, sourceID, bc.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", cacheKey, err)
+	}
+	targetID, found, err := r.find(ctx, ref.EntitySetName, ref.Key, source[ref.Key])
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("%w: %s with %s %v", ErrReferenceMissing, ref.EntitySetName, ref.Key, source[ref.Key])
+	}
+
+	r.mu.Lock()
+	r.mapped[cacheKey] = targetID
+	r.mu.Unlock()
+	return targetID, nil
+}
+
+// find returns the id of the target record with the key value. Codes are compared
+// without case, like BC compares them.
+func (r *Remapper) find(ctx context.Context, entitySetName, key string, value any) (uuid.UUID, bool, error) {
+	if value == nil || value == "" {
+		return uuid.Nil, false, fmt.Errorf("find %s: %s is empty", entitySetName, key)
+	}
+	records, err := bc.NewAPIPage[record](r.to, entitySetName).List(ctx, bc.ListOptions{Filter: key + " eq " + filter.Literal(value)})
+	if err != nil {
+		return uuid.Nil, false, fmt.Errorf("find %s by %s: %w", entitySetName, key, err)
+	}
+	for _, rec := range records {
+		if strings.EqualFold(fmt.Sprint(rec[key]), fmt.Sprint(value)) {
+			id, err := uuid.Parse(fmt.Sprint(rec["id"]))
+			return id, err == nil, err
+		}
+	}
+	return uuid.Nil, false, nil
+}
+
+// Order sorts the specs in creation order by the $metadata, see
+// [bc.Capabilities.CreationOrder], so the copies of the customers are created before
+// the sales orders that reference them.
+func (r *Remapper) Order(specs []Spec) ([]Spec, error) {
+	names := make([]string, len(specs))
+	for i, spec := range specs {
+		names[i] = spec.EntitySetName
+	}
+	ordered, err := r.caps.CreationOrder(names...)
+	if err != nil {
+		return nil, fmt.Errorf("order specs: %w", err)
+	}
+	sorted := make([]Spec, 0, len(specs))
+	for _, name := range ordered {
+		for _, spec := range specs {
+			if spec.EntitySetName == name {
+				sorted = append(sorted, spec)
+			}
+		}
+	}
+	return sorted, nil
+}
@@ -0,0 +1,134 @@
+package copier_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/erlorenz/bc-go/bcfake"
+	"github.com/erlorenz/bc-go/copier"
+	"github.com/erlorenz/bc-go/internal/bctest"
+	"github.com/google/uuid"
+)
+
+const metadataXML = `<?xml version="1.0" encoding="utf-8"?>
+<edmx:Edmx Version="4.0" xmlns:edmx="http://docs.oasis-open.org/odata/ns/edmx">
+  <edmx:DataServices>
+    <Schema Namespace="Microsoft.NAV" xmlns="http://docs.oasis-open.org/odata/ns/edm">
+      <EntityType Name="customer">
+        <Property Name="id" Type="Edm.Guid" Nullable="false" />
+        <Property Name="number" Type="Edm.String" />
+      </EntityType>
+      <EntityType Name="item">
+        <Property Name="id" Type="Edm.Guid" Nullable="false" />
+        <Property Name="number" Type="Edm.String" />
+      </EntityType>
+      <EntityType Name="currency">
+        <Property Name="id" Type="Edm.Guid" Nullable="false" />
+        <Property Name="code" Type="Edm.String" />
+      </EntityType>
+      <EntityType Name="salesOrder">
+        <Property Name="id" Type="Edm.Guid" Nullable="false" />
+        <Property Name="customerId" Type="Edm.Guid" />
+        <Property Name="currencyId" Type="Edm.Guid" />
+        <NavigationProperty Name="customer" Type="Microsoft.NAV.customer">
+          <ReferentialConstraint Property="customerId" ReferencedProperty="id" />
+        </NavigationProperty>
+        <NavigationProperty Name="currency" Type="Microsoft.NAV.currency">
+          <ReferentialConstraint Property="currencyId" ReferencedProperty="id" />
+        </NavigationProperty>
+        <NavigationProperty Name="salesOrderLines" Type="Collection(Microsoft.NAV.salesOrderLine)" />
+      </EntityType>
+      <EntityType Name="salesOrderLine">
+        <Property Name="id" Type="Edm.Guid" Nullable="false" />
+        <Property Name="itemId" Type="Edm.Guid" />
+        <NavigationProperty Name="item" Type="Microsoft.NAV.item">
+          <ReferentialConstraint Property="itemId" ReferencedProperty="id" />
+        </NavigationProperty>
+      </EntityType>
+      <EntityContainer Name="default">
+        <EntitySet Name="customers" EntityType="Microsoft.NAV.customer" />
+        <EntitySet Name="items" EntityType="Microsoft.NAV.item" />
+        <EntitySet Name="currencies" EntityType="Microsoft.NAV.currency" />
+        <EntitySet Name="salesOrders" EntityType="Microsoft.NAV.salesOrder" />
+        <EntitySet Name="salesOrderLines" EntityType="Microsoft.NAV.salesOrderLine" />
+      </EntityContainer>
+    </Schema>
+  </edmx:DataServices>
+</edmx:Edmx>`
+
+func parseCapabilities(t *testing.T) bc.Capabilities {
+	t.Helper()
+	caps, err := bc.ParseCapabilities(strings.NewReader(metadataXML))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return caps
+}
+
+func TestRemapperReferences(t *testing.T) {
+	r := copier.NewRemapper(nil, nil, parseCapabilities(t), copier.RemapOptions{Keys: map[string]string{"customers": "email"}})
+	refs := r.References("salesOrders", "salesOrderLines")
+	want := map[string]copier.Reference{
+		"customerId": {EntitySetName: "customers", Key: "email"},
+		"currencyId": {EntitySetName: "currencies", Key: "code"},
+		"itemId":     {EntitySetName: "items", Key: "number"},
+	}
+	if len(refs) != len(want) {
+		t.Fatalf("wanted %v, got %v", want, refs)
+	}
+	for field, ref := range want {
+		if refs[field] != ref {
+			t.Errorf("%s: wanted %+v, got %+v", field, ref, refs[field])
+		}
+	}
+}
+
+func TestRemap(t *testing.T) {
+	ctx := context.Background()
+	from, to := bcfake.New(), bcfake.New()
+	customers := from.Seed("customers", map[string]any{"number": "C001"})
+	items := from.Seed("items", map[string]any{"number": "1000"})
+	targetCustomers := to.Seed("customers", map[string]any{"number": "C001"})
+	targetItems := to.Seed("items", map[string]any{"number": "1000"})
+
+	r := copier.NewRemapper(bctest.NewClient(t, from), bctest.NewClient(t, to), parseCapabilities(t), copier.RemapOptions{})
+	id := uuid.NewString()
+	got, err := r.Remap(ctx, "salesOrders", map[string]any{
+		"id":              id,
+		"customerId":      customers[0].String(),
+		"currencyId":      uuid.Nil.String(),
+		"salesOrderLines": []any{map[string]any{"itemId": items[0].String(), "quantity": 1.0}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got["id"] != id || got["customerId"] != targetCustomers[0] || got["currencyId"] != uuid.Nil.String() {
+		t.Errorf("unexpected remapped order %v", got)
+	}
+	if line := got["salesOrderLines"].([]any)[0].(map[string]any); line["itemId"] != targetItems[0] || line["quantity"] != 1.0 {
+		t.Errorf("unexpected remapped line %v", line)
+	}
+
+	_, err = r.Remap(ctx, "salesOrderLines", map[string]any{"itemId": from.Seed("items", map[string]any{"number": "2000"})[0].String()})
+	if !errors.Is(err, copier.ErrReferenceMissing) {
+		t.Errorf("wanted ErrReferenceMissing, got %v", err)
+	}
+}
+
+func TestRemapperOrder(t *testing.T) {
+	r := copier.NewRemapper(nil, nil, parseCapabilities(t), copier.RemapOptions{})
+	specs, err := r.Order([]copier.Spec{r.Spec("salesOrders", "salesOrderLines"), r.Spec("customers"), r.Spec("currencies")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, spec := range specs {
+		got = append(got, spec.EntitySetName)
+	}
+	if want := "customers currencies salesOrders"; strings.Join(got, " ") != want {
+		t.Errorf("wanted %s, got %v", want, got)
+	}
+}
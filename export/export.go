@@ -0,0 +1,204 @@
+// Package export streams an entity set to an io.Writer as NDJSON or CSV,
+// saving a checkpoint after each page so a long export can resume after an interruption.
+package export
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/erlorenz/bc-go/bcsync"
+)
+
+// Format is the output format.
+type Format int
+
+const (
+	// NDJSON writes each record as a line of JSON.
+	NDJSON Format = iota
+	// CSV writes a header row followed by a row per record.
+	CSV
+)
+
+// Options configure [Run].
+type Options struct {
+	Format Format
+	// ListOptions select and filter the records. MaxPageSize controls how often a
+	// checkpoint is saved and defaults to 1000.
+	ListOptions bc.ListOptions
+	// Columns are the JSON field names written by CSV, in order. Defaults to
+	// ListOptions.Select, or the sorted fields of the first record.
+	Columns []string
+	// Checkpoints is optional. Without it the export always starts from the beginning.
+	Checkpoints bcsync.CheckpointStore
+	// Key is the checkpoint key. Defaults to "export:" + the entity set name.
+	Key string
+}
+
+// Result describes the export.
+type Result struct {
+	// Records written by this call, not including those before a resume.
+	Records int
+	Pages   int
+	// Resumed is true if the export continued from a checkpoint.
+	Resumed bool
+}
+
+// checkpoint is saved as JSON.
+type checkpoint struct {
+	NextLink string   `json:"nextLink,omitempty"`
+	Columns  []string `json:"columns,omitempty"`
+	Records  int      `json:"records"`
+	Done     bool     `json:"done,omitempty"`
+}
+
+// ErrComplete is returned by [Run] if the checkpoint shows the export already finished.
+// Delete the checkpoint to export again.
+var ErrComplete = errors.New("export already complete")
+
+// Run writes every record of the page to w. If a checkpoint exists it continues from
+// the page after the last one written, and w should be opened for append.
+// The CSV header is only written when starting from the beginning.
+func Run[T bc.Validator](ctx context.Context, page *bc.APIPage[T], w io.Writer, opts Options) (Result, error) {
+	var result Result
+
+	key := opts.Key
+	if key == "" {
+		key = "export:" + page.EntitySetName()
+	}
+	if opts.ListOptions.MaxPageSize == 0 {
+		opts.ListOptions.MaxPageSize = 1000
+	}
+
+	var cp checkpoint
+	if opts.Checkpoints != nil {
+		v, err := opts.Checkpoints.LoadCheckpoint(ctx, key)
+		switch {
+		case errors.Is(err, bcsync.ErrNoCheckpoint):
+		case err != nil:
+			return result, fmt.Errorf("load checkpoint: %w", err)
+		default:
+			if err := json.Unmarshal([]byte(v), &cp); err != nil {
+				return result, fmt.Errorf("invalid checkpoint: %w", err)
+			}
+			if cp.Done {
+				return result, ErrComplete
+			}
+			result.Resumed = cp.NextLink != ""
+		}
+	}
+
+	columns := cp.Columns
+	if len(columns) == 0 {
+		columns = slices.Clone(opts.Columns)
+	}
+	if len(columns) == 0 {
+		columns = slices.Clone(opts.ListOptions.Select)
+	}
+
+	var cw *csv.Writer
+	if opts.Format == CSV {
+		cw = csv.NewWriter(w)
+	}
+	headerWritten := result.Resumed
+
+	link := cp.NextLink
+	for {
+		list, err := page.ListPage(ctx, link, opts.ListOptions)
+		if err != nil {
+			return result, fmt.Errorf("export page %d: %w", result.Pages+1, err)
+		}
+		result.Pages++
+
+		for _, record := range list.Value {
+			b, err := json.Marshal(record)
+			if err != nil {
+				return result, fmt.Errorf("marshal record: %w", err)
+			}
+
+			if cw == nil {
+				if _, err := w.Write(append(b, '\n')); err != nil {
+					return result, fmt.Errorf("write record: %w", err)
+				}
+				result.Records++
+				continue
+			}
+
+			var fields map[string]any
+			if err := json.Unmarshal(b, &fields); err != nil {
+				return result, fmt.Errorf("record is not an object: %w", err)
+			}
+			if len(columns) == 0 {
+				columns = sortedKeys(fields)
+			}
+			if !headerWritten {
+				if err := cw.Write(columns); err != nil {
+					return result, fmt.Errorf("write header: %w", err)
+				}
+				headerWritten = true
+			}
+			if err := cw.Write(row(fields, columns)); err != nil {
+				return result, fmt.Errorf("write record: %w", err)
+			}
+			result.Records++
+		}
+
+		if cw != nil {
+			cw.Flush()
+			if err := cw.Error(); err != nil {
+				return result, fmt.Errorf("write csv: %w", err)
+			}
+		}
+
+		link = list.NextLink
+		cp = checkpoint{NextLink: link, Columns: columns, Records: cp.Records + len(list.Value), Done: link == ""}
+		if opts.Checkpoints != nil {
+			b, _ := json.Marshal(cp)
+			if err := opts.Checkpoints.SaveCheckpoint(ctx, key, string(b)); err != nil {
+				return result, fmt.Errorf("save checkpoint: %w", err)
+			}
+		}
+
+		if link == "" {
+			return result, nil
+		}
+	}
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		if k == "@odata.etag" {
+			continue
+		}
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// row formats the fields as CSV values. Nested values are written as JSON.
+func row(fields map[string]any, columns []string) []string {
+	values := make([]string, len(columns))
+	for i, col := range columns {
+		switch v := fields[col].(type) {
+		case nil:
+		case string:
+			values[i] = v
+		case bool:
+			values[i] = strconv.FormatBool(v)
+		case float64:
+			values[i] = strconv.FormatFloat(v, 'f', -1, 64)
+		default:
+			b, _ := json.Marshal(v)
+			values[i] = string(b)
+		}
+	}
+	return values
+}
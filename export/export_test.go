@@ -0,0 +1,78 @@
+package export_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/erlorenz/bc-go/bcsync"
+	"github.com/erlorenz/bc-go/export"
+	"github.com/erlorenz/bc-go/internal/bctest"
+)
+
+type item struct {
+	Number string  `json:"number"`
+	Price  float64 `json:"unitPrice"`
+}
+
+func (i item) Validate() error { return nil }
+
+func newPage(t *testing.T, st *bctest.SequenceTransport) *bc.APIPage[item] {
+	t.Helper()
+	return bc.NewAPIPage[item](bctest.NewClient(t, st), "items")
+}
+
+const next = "https://api.businesscentral.dynamics.com/v2.0/next"
+
+func TestRunCSV(t *testing.T) {
+	st := &bctest.SequenceTransport{Responses: []*http.Response{
+		bctest.NewResponse(200, map[string]any{"value": []item{{"1000", 1.5}}, "@odata.nextLink": next}),
+		bctest.NewResponse(200, map[string]any{"value": []item{{"2000", 2}}}),
+	}}
+
+	buf := &bytes.Buffer{}
+	cps := &bcsync.MemoryCheckpoints{}
+	result, err := export.Run(context.Background(), newPage(t, st), buf, export.Options{Format: export.CSV, Checkpoints: cps})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "number,unitPrice\n1000,1.5\n2000,2\n"
+	if buf.String() != want {
+		t.Errorf("wanted %q, got %q", want, buf.String())
+	}
+	if result.Records != 2 || result.Pages != 2 {
+		t.Errorf("unexpected result %+v", result)
+	}
+
+	_, err = export.Run(context.Background(), newPage(t, st), buf, export.Options{Format: export.CSV, Checkpoints: cps})
+	if !errors.Is(err, export.ErrComplete) {
+		t.Errorf("expected ErrComplete, got %v", err)
+	}
+}
+
+func TestRunResume(t *testing.T) {
+	cps := &bcsync.MemoryCheckpoints{}
+	cps.SaveCheckpoint(context.Background(), "export:items", `{"nextLink":"`+next+`","records":1}`)
+
+	st := &bctest.SequenceTransport{Responses: []*http.Response{
+		bctest.NewResponse(200, map[string]any{"value": []item{{"2000", 2}}}),
+	}}
+
+	buf := &bytes.Buffer{}
+	result, err := export.Run(context.Background(), newPage(t, st), buf, export.Options{Checkpoints: cps})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !result.Resumed || st.Requests[0].URL.Path != "/v2.0/next" {
+		t.Errorf("expected resume from next link, got %+v %s", result, st.Requests[0].URL.Path)
+	}
+	if strings.TrimSpace(buf.String()) != `{"number":"2000","unitPrice":2}` {
+		t.Errorf("unexpected output %s", buf.String())
+	}
+}
@@ -0,0 +1,186 @@
+// Package extref manages external reference numbers, like the externalDocumentNumber of
+// a sales order, for integrations that create documents idempotently keyed on an external
+// ID. A [Reserver] generates numbers or claims the IDs of the source system, and checks
+// with a $filter that BC does not use them yet:
+//
+//	refs := extref.New(client, extref.Options{
+//		EntitySetNames: []string{"salesOrders", "salesInvoices"},
+//		Prefix:         "WEB-",
+//	})
+//	number, err := refs.Reserve(ctx)
+//	order, err := orders.CreateOnce(ctx, body, bc.CreateOnceOptions{KeyFilter: refs.KeyFilter(number)})
+//	refs.Release(number)
+package extref
+
+import (
+	"cmp"
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/erlorenz/bc-go/filter"
+)
+
+// ErrInUse is returned by [Reserver.Claim] for a number that BC or another caller of
+// the Reserver already uses.
+var ErrInUse = errors.New("external reference in use")
+
+// DefaultMaxLength is the length of the externalDocumentNumber field of BC, a Code[35].
+const DefaultMaxLength = 35
+
+// Options configure [New].
+type Options struct {
+	// EntitySetNames are checked for a collision, e.g. "salesOrders" and "salesInvoices"
+	// so a number is not reused after the order is posted. Required.
+	EntitySetNames []string
+	// Field has the number in the entity sets. Defaults to "externalDocumentNumber".
+	Field string
+	// Prefix is added to the generated numbers, e.g. "WEB-".
+	Prefix string
+	// MaxLength is the longest number accepted. Defaults to DefaultMaxLength.
+	MaxLength int
+	// Generate returns the part of a number after the Prefix. Defaults to 10 random
+	// uppercase letters and digits.
+	Generate func() string
+	// MaxAttempts is the number of generated numbers tried by Reserve. Defaults to 5.
+	MaxAttempts int
+}
+
+// Reserver generates and claims external reference numbers. The numbers it hands out
+// are reserved in the process until they are released, so concurrent callers never
+// get the same one before it exists in BC. Numbers are compared without case, like BC
+// compares codes. It is safe for concurrent use.
+type Reserver struct {
+	client *bc.Client
+	opts   Options
+
+	mu       sync.Mutex
+	reserved map[string]bool
+}
+
+// New creates a Reserver for the client.
+func New(client *bc.Client, opts Options) *Reserver {
+	opts.Field = cmp.Or(opts.Field, "externalDocumentNumber")
+	if opts.MaxLength <= 0 {
+		opts.MaxLength = DefaultMaxLength
+	}
+	if opts.Generate == nil {
+		opts.Generate = randomCode
+	}
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = 5
+	}
+	return &Reserver{client: client, opts: opts, reserved: map[string]bool{}}
+}
+
+// KeyFilter returns the $filter that matches the number, e.g. for the KeyFilter of
+// [bc.CreateOnceOptions].
+func (r *Reserver) KeyFilter(number string) string {
+	return r.opts.Field + " eq " + filter.Literal(number)
+}
+
+// Reserve generates numbers until one is not used in BC nor reserved, and reserves it.
+func (r *Reserver) Reserve(ctx context.Context) (string, error) {
+	for range r.opts.MaxAttempts {
+		number := r.opts.Prefix + r.opts.Generate()
+		err := r.Claim(ctx, number)
+		if errors.Is(err, ErrInUse) {
+			r.client.Logger().Debug("Generated external reference in use.", "number", number)
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		return number, nil
+	}
+	return "", fmt.Errorf("reserve external reference: no free number after %d attempts", r.opts.MaxAttempts)
+}
+
+// Claim reserves the number, e.g. the order ID of a web shop. It returns an error
+// matching [ErrInUse] if the number is reserved or any of the entity sets has it.
+func (r *Reserver) Claim(ctx context.Context, number string) error {
+	if err := r.validate(number); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	if r.reserved[strings.ToUpper(number)] {
+		r.mu.Unlock()
+		return fmt.Errorf("%w: %s is reserved", ErrInUse, number)
+	}
+	r.reserved[strings.ToUpper(number)] = true
+	r.mu.Unlock()
+
+	entitySetName, err := r.Lookup(ctx, number)
+	if err == nil && entitySetName != "" {
+		err = fmt.Errorf("%w: %s exists in %s", ErrInUse, number, entitySetName)
+	}
+	if err != nil {
+		r.Release(number)
+		return err
+	}
+	return nil
+}
+
+// Release forgets a reserved number, once the document is created or its creation was
+// abandoned. A created document keeps the number in use through BC.
+func (r *Reserver) Release(number string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.reserved, strings.ToUpper(number))
+}
+
+// Lookup returns the first entity set that has a record with the number, or an empty
+// string if none has.
+func (r *Reserver) Lookup(ctx context.Context, number string) (string, error) {
+	if len(r.opts.EntitySetNames) == 0 {
+		return "", errors.New("lookup external reference: EntitySetNames is required")
+	}
+	for _, name := range r.opts.EntitySetNames {
+		page := bc.NewAPIPage[record](r.client, name)
+		records, err := page.List(ctx, bc.ListOptions{Filter: r.KeyFilter(number), Select: []string{"id"}, Top: 1})
+		if err != nil {
+			return "", fmt.Errorf("lookup external reference in %s: %w", name, err)
+		}
+		if len(records) > 0 {
+			return name, nil
+		}
+	}
+	return "", nil
+}
+
+// validate checks the number fits the field of BC.
+func (r *Reserver) validate(number string) error {
+	if strings.TrimSpace(number) == "" {
+		return errors.New("external reference is empty")
+	}
+	if len(number) > r.opts.MaxLength {
+		return fmt.Errorf("external reference %s is longer than %d characters", number, r.opts.MaxLength)
+	}
+	return nil
+}
+
+type record map[string]any
+
+func (record) Validate() error {
+	return nil
+}
+
+// codeAlphabet leaves out the letters and digits that are easily confused.
+const codeAlphabet = "23456789ABCDEFGHJKLMNPQRSTUVWXYZ"
+
+// randomCode returns 10 random characters of the codeAlphabet.
+func randomCode() string {
+	b := make([]byte, 10)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	for i := range b {
+		b[i] = codeAlphabet[int(b[i])%len(codeAlphabet)]
+	}
+	return string(b)
+}
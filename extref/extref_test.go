@@ -0,0 +1,107 @@
+package extref_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/erlorenz/bc-go/extref"
+	"github.com/erlorenz/bc-go/internal/bctest"
+	"github.com/google/uuid"
+)
+
+// existing answers a list with a record if the filter has one of the numbers.
+type existing struct {
+	mu      sync.Mutex
+	numbers map[string][]string
+	filters []string
+}
+
+func (e *existing) RoundTrip(r *http.Request) (*http.Response, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	filter := r.URL.Query().Get("$filter")
+	e.filters = append(e.filters, filter)
+
+	segments := strings.Split(r.URL.Path, "/")
+	value := []map[string]any{}
+	for _, number := range e.numbers[segments[len(segments)-1]] {
+		if strings.Contains(filter, "'"+number+"'") {
+			value = append(value, map[string]any{"id": uuid.NewString()})
+		}
+	}
+	res := bctest.NewResponse(http.StatusOK, map[string]any{"value": value})
+	res.Request = r
+	return res, nil
+}
+
+func TestClaim(t *testing.T) {
+	server := &existing{numbers: map[string][]string{"salesInvoices": {"SHOP-1"}}}
+	refs := extref.New(bctest.NewClient(t, server), extref.Options{EntitySetNames: []string{"salesOrders", "salesInvoices"}})
+	ctx := context.Background()
+
+	err := refs.Claim(ctx, "SHOP-1")
+	if !errors.Is(err, extref.ErrInUse) || !strings.Contains(err.Error(), "salesInvoices") {
+		t.Errorf("wanted the posted invoice to be found, got %v", err)
+	}
+	if got := server.filters[0]; got != "externalDocumentNumber eq 'SHOP-1'" {
+		t.Errorf("unexpected filter %s", got)
+	}
+
+	if err := refs.Claim(ctx, "SHOP-2"); err != nil {
+		t.Fatal(err)
+	}
+	if err := refs.Claim(ctx, "shop-2"); !errors.Is(err, extref.ErrInUse) {
+		t.Errorf("wanted the reserved number in use, got %v", err)
+	}
+	refs.Release("SHOP-2")
+	if err := refs.Claim(ctx, "SHOP-2"); err != nil {
+		t.Errorf("wanted the released number free, got %v", err)
+	}
+
+	if err := refs.Claim(ctx, strings.Repeat("X", 36)); err == nil || errors.Is(err, extref.ErrInUse) {
+		t.Errorf("wanted an error for a number longer than the field, got %v", err)
+	}
+	if got := refs.KeyFilter("O'Brien"); got != "externalDocumentNumber eq 'O''Brien'" {
+		t.Errorf("unexpected key filter %s", got)
+	}
+}
+
+func TestReserve(t *testing.T) {
+	server := &existing{numbers: map[string][]string{"salesOrders": {"WEB-1"}}}
+	n := 0
+	refs := extref.New(bctest.NewClient(t, server), extref.Options{
+		EntitySetNames: []string{"salesOrders"},
+		Prefix:         "WEB-",
+		Generate: func() string {
+			n++
+			return string(rune('0' + n))
+		},
+	})
+	ctx := context.Background()
+
+	number, err := refs.Reserve(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if number != "WEB-2" {
+		t.Errorf("wanted WEB-1 skipped as it exists, got %s", number)
+	}
+
+	refs = extref.New(bctest.NewClient(t, server), extref.Options{EntitySetNames: []string{"salesOrders"}, Generate: func() string { return "WEB-1" }, MaxAttempts: 2})
+	if _, err := refs.Reserve(ctx); err == nil {
+		t.Error("wanted an error when every generated number exists")
+	}
+
+	refs = extref.New(bctest.NewClient(t, server), extref.Options{EntitySetNames: []string{"salesOrders"}})
+	number, err = refs.Reserve(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(number) != 10 || strings.ToUpper(number) != number {
+		t.Errorf("unexpected random number %s", number)
+	}
+}
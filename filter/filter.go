@@ -0,0 +1,108 @@
+// Package filter renders OData $filter expressions for Business Central, with values
+// formatted and escaped as literals. The "in" operator is rendered by [In]:
+//
+//	in := filter.In("id", ids...)
+//	records, err := filter.ListIn(ctx, page, in, bc.ListOptions{})
+//
+// ListIn splits a long list of values into several requests and merges the results.
+package filter
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/google/uuid"
+)
+
+// Literal formats the value as an OData literal. Strings are quoted with single quotes
+// doubled, a uuid.UUID and numbers are bare, a time.Time is in RFC 3339 UTC and
+// a [bc.Date] is "YYYY-MM-DD". Other types are formatted with fmt and quoted.
+func Literal(v any) string {
+	switch v := v.(type) {
+	case string:
+		return quote(v)
+	case uuid.UUID:
+		return v.String()
+	case bool:
+		return strconv.FormatBool(v)
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprint(v)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case time.Time:
+		return v.UTC().Format(time.RFC3339)
+	case bc.Date:
+		return v.String()
+	case fmt.Stringer:
+		return quote(v.String())
+	}
+	return quote(fmt.Sprint(v))
+}
+
+func quote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// InFilter matches records where the Field is one of the Values, which are OData literals.
+// Create one with [In].
+type InFilter struct {
+	Field  string
+	Values []string
+}
+
+// In returns an [InFilter] for the field with each value formatted by [Literal].
+func In[V any](field string, values ...V) InFilter {
+	in := InFilter{Field: field, Values: make([]string, len(values))}
+	for i, v := range values {
+		in.Values[i] = Literal(v)
+	}
+	return in
+}
+
+// String renders the "in" operator, e.g. "number in ('1000','2000')".
+// Older BC versions do not support it, use [InFilter.Or] for those.
+// It renders "false" if there are no values.
+func (in InFilter) String() string {
+	if len(in.Values) == 0 {
+		return "false"
+	}
+	return fmt.Sprintf("%s in (%s)", in.Field, strings.Join(in.Values, ","))
+}
+
+// Or renders the filter with "eq" and "or" for environments without the "in" operator,
+// e.g. "(number eq '1000' or number eq '2000')". It is much longer than [InFilter.String].
+func (in InFilter) Or() string {
+	if len(in.Values) == 0 {
+		return "false"
+	}
+	parts := make([]string, len(in.Values))
+	for i, v := range in.Values {
+		parts[i] = fmt.Sprintf("%s eq %s", in.Field, v)
+	}
+	return "(" + strings.Join(parts, " or ") + ")"
+}
+
+// Chunks splits the values into filters of at most size values, e.g. to request them concurrently.
+func (in InFilter) Chunks(size int) []InFilter {
+	if size <= 0 {
+		size = len(in.Values)
+	}
+	var chunks []InFilter
+	for start := 0; start < len(in.Values); start += size {
+		end := min(start+size, len(in.Values))
+		chunks = append(chunks, InFilter{Field: in.Field, Values: in.Values[start:end]})
+	}
+	return chunks
+}
+
+// ListIn lists the records of the page that match the filter with [bc.APIPage.ListIn],
+// which splits the values into several requests if the URL would be too long.
+func ListIn[T bc.Validator](ctx context.Context, page *bc.APIPage[T], in InFilter, opts bc.ListOptions) ([]T, error) {
+	return page.ListIn(ctx, in.Field, in.Values, opts)
+}
@@ -0,0 +1,91 @@
+package filter_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/erlorenz/bc-go/filter"
+	"github.com/erlorenz/bc-go/internal/bctest"
+	"github.com/google/uuid"
+)
+
+type customer struct {
+	ID     uuid.UUID `json:"id"`
+	Number string    `json:"number"`
+}
+
+func (c customer) Validate() error { return nil }
+
+func TestLiteral(t *testing.T) {
+	id := uuid.MustParse("6f6c6a9e-1c2b-4b8a-9f4e-0e2d4c3b2a19")
+
+	table := []struct {
+		name  string
+		value any
+		want  string
+	}{
+		{"String", "10000", "'10000'"},
+		{"Quote", "O'Brien", "'O''Brien'"},
+		{"UUID", id, "6f6c6a9e-1c2b-4b8a-9f4e-0e2d4c3b2a19"},
+		{"Int", 42, "42"},
+		{"Float", 12.5, "12.5"},
+		{"Bool", true, "true"},
+		{"Time", time.Date(2024, 3, 1, 12, 0, 0, 0, time.FixedZone("CET", 3600)), "2024-03-01T11:00:00Z"},
+		{"Date", bc.Date{Year: 2024, Month: 3, Day: 1}, "2024-03-01"},
+	}
+
+	for _, v := range table {
+		t.Run(v.name, func(t *testing.T) {
+			if got := filter.Literal(v.value); got != v.want {
+				t.Errorf("wanted %s, got %s", v.want, got)
+			}
+		})
+	}
+}
+
+func TestIn(t *testing.T) {
+	in := filter.In("number", "1000", "2000", "O'Brien")
+
+	if got, want := in.String(), "number in ('1000','2000','O''Brien')"; got != want {
+		t.Errorf("wanted %s, got %s", want, got)
+	}
+	if got, want := in.Or(), "(number eq '1000' or number eq '2000' or number eq 'O''Brien')"; got != want {
+		t.Errorf("wanted %s, got %s", want, got)
+	}
+	if got := filter.In[string]("number").String(); got != "false" {
+		t.Errorf("wanted false for no values, got %s", got)
+	}
+
+	chunks := in.Chunks(2)
+	if len(chunks) != 2 || len(chunks[0].Values) != 2 || chunks[1].String() != "number in ('O''Brien')" {
+		t.Errorf("unexpected chunks %v", chunks)
+	}
+}
+
+func TestListIn(t *testing.T) {
+	ids := make([]uuid.UUID, 200)
+	for i := range ids {
+		ids[i] = uuid.New()
+	}
+
+	var responses []*http.Response
+	for range 8 {
+		responses = append(responses, bctest.NewResponse(200, map[string]any{"value": []customer{{ID: uuid.New(), Number: "1"}}}))
+	}
+	st := &bctest.SequenceTransport{Responses: responses}
+	client := bctest.NewClient(t, st)
+	page := bc.NewAPIPage[customer](client, "customers")
+
+	records, err := filter.ListIn(context.Background(), page, filter.In("id", ids...), bc.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 200 GUIDs do not fit in one URL
+	if st.Count() < 2 || len(records) != st.Count() {
+		t.Errorf("wanted split requests with merged records, got %d requests and %d records", st.Count(), len(records))
+	}
+}
@@ -0,0 +1,96 @@
+package filter
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/erlorenz/bc-go/bc"
+)
+
+// Between renders an inclusive range, e.g. "postingDate ge 2024-01-01 and postingDate le 2024-01-31"
+// for [bc.Date] values, which are Edm.Date fields. Use time.Time values for Edm.DateTimeOffset
+// fields like lastModifiedDateTime.
+func Between[V any](field string, from, to V) string {
+	return fmt.Sprintf("%s ge %s and %s le %s", field, Literal(from), field, Literal(to))
+}
+
+// Period is an inclusive range of dates.
+type Period struct {
+	From bc.Date
+	To   bc.Date
+}
+
+// Filter renders the period with [Between].
+func (p Period) Filter(field string) string {
+	return Between(field, p.From, p.To)
+}
+
+// Contains returns true if the date is within the period.
+func (p Period) Contains(d bc.Date) bool {
+	t := d.TimeUTC()
+	return !t.Before(p.From.TimeUTC()) && !t.After(p.To.TimeUTC())
+}
+
+// Month returns the calendar month of the date.
+func Month(d bc.Date) Period {
+	return FiscalCalendar{YearStart: bc.Date{Year: d.Year, Month: time.January, Day: 1}}.Month(d)
+}
+
+// Quarter returns the calendar quarter of the date.
+func Quarter(d bc.Date) Period {
+	return FiscalCalendar{YearStart: bc.Date{Year: d.Year, Month: time.January, Day: 1}}.Quarter(d)
+}
+
+// Year returns the calendar year of the date.
+func Year(d bc.Date) Period {
+	return FiscalCalendar{YearStart: bc.Date{Year: d.Year, Month: time.January, Day: 1}}.Year(d)
+}
+
+// FiscalCalendar has fiscal years of 12 months that start on the same day as YearStart,
+// the start of any fiscal year, e.g. July 1 for a fiscal year from July to June.
+// Fiscal quarters and months start on that day of the month.
+type FiscalCalendar struct {
+	YearStart bc.Date
+}
+
+// Year returns the fiscal year of the date.
+func (fc FiscalCalendar) Year(d bc.Date) Period {
+	start := fc.yearStart(d.TimeUTC())
+	return period(start, start.AddDate(1, 0, 0))
+}
+
+// Quarter returns the fiscal quarter of the date.
+func (fc FiscalCalendar) Quarter(d bc.Date) Period {
+	return fc.step(d, 3)
+}
+
+// Month returns the fiscal month of the date.
+func (fc FiscalCalendar) Month(d bc.Date) Period {
+	return fc.step(d, 1)
+}
+
+// step returns the period of months months within the fiscal year that contains the date.
+func (fc FiscalCalendar) step(d bc.Date, months int) Period {
+	t := d.TimeUTC()
+	year := fc.yearStart(t)
+	for i := months; ; i += months {
+		if end := year.AddDate(0, i, 0); end.After(t) {
+			return period(year.AddDate(0, i-months, 0), end)
+		}
+	}
+}
+
+// yearStart returns the start of the fiscal year that contains t.
+func (fc FiscalCalendar) yearStart(t time.Time) time.Time {
+	anchor := fc.YearStart.TimeUTC()
+	start := anchor.AddDate(t.Year()-anchor.Year(), 0, 0)
+	if start.After(t) {
+		start = anchor.AddDate(t.Year()-anchor.Year()-1, 0, 0)
+	}
+	return start
+}
+
+// period returns the period from start to the day before end.
+func period(start, end time.Time) Period {
+	return Period{From: bc.DateOf(start), To: bc.DateOf(end.AddDate(0, 0, -1))}
+}
@@ -0,0 +1,67 @@
+package filter_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/erlorenz/bc-go/filter"
+)
+
+func date(s string) bc.Date {
+	d, err := bc.ParseDate(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func TestBetween(t *testing.T) {
+	got := filter.Between("postingDate", date("2024-01-01"), date("2024-01-31"))
+	if want := "postingDate ge 2024-01-01 and postingDate le 2024-01-31"; got != want {
+		t.Errorf("wanted %s, got %s", want, got)
+	}
+
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	got = filter.Between("lastModifiedDateTime", from, from.Add(time.Hour))
+	if want := "lastModifiedDateTime ge 2024-01-01T00:00:00Z and lastModifiedDateTime le 2024-01-01T01:00:00Z"; got != want {
+		t.Errorf("wanted %s, got %s", want, got)
+	}
+}
+
+func TestPeriods(t *testing.T) {
+	july := filter.FiscalCalendar{YearStart: date("2020-07-01")}
+	mid := filter.FiscalCalendar{YearStart: date("2023-04-15")}
+
+	table := []struct {
+		name     string
+		got      filter.Period
+		from, to string
+	}{
+		{"Month", filter.Month(date("2024-02-10")), "2024-02-01", "2024-02-29"},
+		{"Quarter", filter.Quarter(date("2024-05-31")), "2024-04-01", "2024-06-30"},
+		{"Year", filter.Year(date("2024-12-31")), "2024-01-01", "2024-12-31"},
+		{"FiscalYear", july.Year(date("2024-03-15")), "2023-07-01", "2024-06-30"},
+		{"FiscalYearStartDay", july.Year(date("2024-07-01")), "2024-07-01", "2025-06-30"},
+		{"FiscalQuarter", july.Quarter(date("2024-11-30")), "2024-10-01", "2024-12-31"},
+		{"FiscalMonth", july.Month(date("2025-06-30")), "2025-06-01", "2025-06-30"},
+		{"MidMonthYear", mid.Year(date("2024-04-14")), "2023-04-15", "2024-04-14"},
+		{"MidMonthMonth", mid.Month(date("2024-01-01")), "2023-12-15", "2024-01-14"},
+	}
+
+	for _, v := range table {
+		t.Run(v.name, func(t *testing.T) {
+			if v.got.From.String() != v.from || v.got.To.String() != v.to {
+				t.Errorf("wanted %s to %s, got %s to %s", v.from, v.to, v.got.From, v.got.To)
+			}
+		})
+	}
+
+	p := filter.Quarter(date("2024-05-31"))
+	if want := "postingDate ge 2024-04-01 and postingDate le 2024-06-30"; p.Filter("postingDate") != want {
+		t.Errorf("wanted %s, got %s", want, p.Filter("postingDate"))
+	}
+	if !p.Contains(date("2024-06-30")) || p.Contains(date("2024-07-01")) {
+		t.Error("unexpected Contains")
+	}
+}
@@ -0,0 +1,388 @@
+// Package fixtures loads named records into a sandbox for end-to-end tests and tears them
+// down afterwards. A directory has a file per entity set, named after it, with an
+// object of named records. Files are JSON, other formats like YAML are decoded with
+// [Options.Unmarshalers]:
+//
+//	customers.json:
+//	{"adatum": {"displayName": "Adatum Corporation", "currencyCode": "EUR"}}
+//
+//	salesOrders.json:
+//	{"first": {"customerId": "@customers.adatum", "salesOrderLines": [
+//		{"lineType": "Item", "itemId": "@items.bicycle", "quantity": 2}
+//	]}}
+//
+// A string of the form "@{entitySet}.{name}" is replaced with the id of that record
+// after it is created, and "@{entitySet}.{name}.{field}" with one of its fields, e.g.
+// "@customers.adatum.number". The records are created in dependency order, so the
+// customers are created before the orders. Start a string with "@@" for a literal "@".
+//
+// In a test:
+//
+//	created := fixtures.Setup(t, client, os.DirFS("testdata/fixtures"), fixtures.Options{})
+//	order := created.ID("salesOrders", "first")
+package fixtures
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"path"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/google/uuid"
+)
+
+// Fixture is a named record of an entity set.
+type Fixture struct {
+	EntitySetName string
+	Name          string
+	// Fields is the body of the create request, with the references still unresolved.
+	Fields map[string]any
+}
+
+// ref is the "{entitySet}.{name}" of a fixture.
+func (f Fixture) ref() string {
+	return f.EntitySetName + "." + f.Name
+}
+
+// Fixtures are the records to create.
+type Fixtures struct {
+	// Fixtures are in dependency order, so every reference is to an earlier fixture.
+	Fixtures []Fixture
+}
+
+// Unmarshaler decodes a fixture file, like json.Unmarshal.
+type Unmarshaler func(data []byte, v any) error
+
+// Options configure [Load].
+type Options struct {
+	// Unmarshalers decode the files with other extensions than ".json", e.g.
+	// {".yaml": yaml.Unmarshal, ".yml": yaml.Unmarshal} with gopkg.in/yaml.v3.
+	Unmarshalers map[string]Unmarshaler
+	// Capabilities add the dependencies between the entity sets from the $metadata, see
+	// [bc.Capabilities.Dependencies], so the customers are created before the orders even
+	// if the orders reference them by a number instead of "@customers.{name}".
+	Capabilities bc.Capabilities
+}
+
+// Load reads every fixture file in the root of fsys and orders the fixtures so that
+// references come first. Fixtures without dependencies keep the order of the file
+// names and then of their names. Files with other extensions are ignored.
+func Load(fsys fs.FS, opts Options) (*Fixtures, error) {
+	unmarshalers := map[string]Unmarshaler{".json": json.Unmarshal}
+	maps.Copy(unmarshalers, opts.Unmarshalers)
+
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("load fixtures: %w", err)
+	}
+
+	var all []Fixture
+	seen := map[string]string{}
+	for _, entry := range entries {
+		ext := path.Ext(entry.Name())
+		unmarshal, ok := unmarshalers[ext]
+		if entry.IsDir() || !ok {
+			continue
+		}
+		entitySetName := strings.TrimSuffix(entry.Name(), ext)
+		if other, ok := seen[entitySetName]; ok {
+			return nil, fmt.Errorf("load fixtures: %s and %s are the same entity set", other, entry.Name())
+		}
+		seen[entitySetName] = entry.Name()
+
+		b, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("load fixtures: %w", err)
+		}
+		var records map[string]map[string]any
+		if err := unmarshal(b, &records); err != nil {
+			return nil, fmt.Errorf("load fixtures: %s: %w", entry.Name(), err)
+		}
+		for _, name := range slices.Sorted(maps.Keys(records)) {
+			if name == "" || strings.Contains(name, ".") {
+				return nil, fmt.Errorf("load fixtures: %s: invalid name %q", entry.Name(), name)
+			}
+			all = append(all, Fixture{EntitySetName: entitySetName, Name: name, Fields: records[name]})
+		}
+	}
+
+	ordered, err := order(all, opts.Capabilities)
+	if err != nil {
+		return nil, fmt.Errorf("load fixtures: %w", err)
+	}
+	return &Fixtures{Fixtures: ordered}, nil
+}
+
+// order sorts the fixtures topologically by their references and the dependencies of
+// their entity sets.
+func order(all []Fixture, caps bc.Capabilities) ([]Fixture, error) {
+	byRef := make(map[string]int, len(all))
+	bySet := map[string][]int{}
+	for i, f := range all {
+		byRef[f.ref()] = i
+		bySet[f.EntitySetName] = append(bySet[f.EntitySetName], i)
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := make([]int, len(all))
+	ordered := make([]Fixture, 0, len(all))
+	var path []string
+
+	var visit func(i int) error
+	visit = func(i int) error {
+		f := all[i]
+		switch state[i] {
+		case done:
+			return nil
+		case visiting:
+			return fmt.Errorf("reference cycle %s -> %s", strings.Join(path, " -> "), f.ref())
+		}
+		state[i] = visiting
+		path = append(path, f.ref())
+
+		for _, r := range references(f.Fields) {
+			j, ok := byRef[r.target()]
+			if !ok {
+				return fmt.Errorf("%s: unknown reference @%s", f.ref(), r)
+			}
+			if err := visit(j); err != nil {
+				return err
+			}
+		}
+		for _, dep := range caps.Dependencies(f.EntitySetName) {
+			for _, j := range bySet[dep] {
+				if err := visit(j); err != nil {
+					return err
+				}
+			}
+		}
+
+		path = path[:len(path)-1]
+		state[i] = done
+		ordered = append(ordered, f)
+		return nil
+	}
+	for i := range all {
+		if err := visit(i); err != nil {
+			return nil, err
+		}
+	}
+	return ordered, nil
+}
+
+// reference is a parsed "@{entitySet}.{name}[.{field}]".
+type reference struct {
+	entitySetName, name, field string
+}
+
+func (r reference) target() string {
+	return r.entitySetName + "." + r.name
+}
+
+func (r reference) String() string {
+	if r.field == "" {
+		return r.target()
+	}
+	return r.target() + "." + r.field
+}
+
+// parseReference returns the reference of a string value, if it is one.
+func parseReference(s string) (reference, bool) {
+	if !strings.HasPrefix(s, "@") || strings.HasPrefix(s, "@@") {
+		return reference{}, false
+	}
+	parts := strings.SplitN(s[1:], ".", 3)
+	if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+		return reference{}, false
+	}
+	r := reference{entitySetName: parts[0], name: parts[1]}
+	if len(parts) == 3 {
+		r.field = parts[2]
+	}
+	return r, true
+}
+
+// references returns the references in the value, including nested objects and arrays.
+func references(v any) []reference {
+	var refs []reference
+	walk(v, func(s string) any {
+		if r, ok := parseReference(s); ok {
+			refs = append(refs, r)
+		}
+		return s
+	})
+	return refs
+}
+
+// walk returns a copy of the value with every string replaced by replace.
+func walk(v any, replace func(string) any) any {
+	switch v := v.(type) {
+	case string:
+		return replace(v)
+	case map[string]any:
+		m := make(map[string]any, len(v))
+		for k, e := range v {
+			m[k] = walk(e, replace)
+		}
+		return m
+	case []any:
+		s := make([]any, len(v))
+		for i, e := range v {
+			s[i] = walk(e, replace)
+		}
+		return s
+	}
+	return v
+}
+
+// Entry is a created record, enough to delete it.
+type Entry struct {
+	EntitySetName string    `json:"entitySetName"`
+	Name          string    `json:"name"`
+	ID            uuid.UUID `json:"id"`
+}
+
+// Created has the records created by [Fixtures.Create].
+type Created struct {
+	client  *bc.Client
+	entries []Entry
+	records map[string]map[string]any
+}
+
+// ID returns the id of the created fixture, or uuid.Nil if it was not created.
+func (c *Created) ID(entitySetName, name string) uuid.UUID {
+	for _, e := range c.entries {
+		if e.EntitySetName == entitySetName && e.Name == name {
+			return e.ID
+		}
+	}
+	return uuid.Nil
+}
+
+// Record returns the response of the create request of the fixture.
+func (c *Created) Record(entitySetName, name string) map[string]any {
+	return c.records[entitySetName+"."+name]
+}
+
+// Entries returns the created records in the order they were created.
+// Save them with [Created.WriteJSON] to tear down from another process.
+func (c *Created) Entries() []Entry {
+	return slices.Clone(c.entries)
+}
+
+// WriteJSON writes the entries as a JSON array for [ReadEntries].
+func (c *Created) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(c.entries)
+}
+
+// ReadEntries reads the entries written by [Created.WriteJSON].
+func ReadEntries(r io.Reader) ([]Entry, error) {
+	var entries []Entry
+	if err := json.NewDecoder(r).Decode(&entries); err != nil {
+		return nil, fmt.Errorf("read entries: %w", err)
+	}
+	return entries, nil
+}
+
+// Teardown deletes the created records in reverse order.
+func (c *Created) Teardown(ctx context.Context) error {
+	return Teardown(ctx, c.client, c.entries)
+}
+
+// record is a record of any entity set.
+type record map[string]any
+
+func (record) Validate() error { return nil }
+
+// Create creates the fixtures in order, resolving the references to the records created
+// before. If a create fails, the records created so far are returned with the error,
+// so they can be torn down.
+func (f *Fixtures) Create(ctx context.Context, client *bc.Client) (*Created, error) {
+	created := &Created{client: client, records: map[string]map[string]any{}}
+
+	for _, fixture := range f.Fixtures {
+		var errs []error
+		fields := walk(fixture.Fields, func(s string) any {
+			if strings.HasPrefix(s, "@@") {
+				return s[1:]
+			}
+			r, ok := parseReference(s)
+			if !ok {
+				return s
+			}
+			target := created.records[r.target()]
+			if r.field == "" {
+				return target["id"]
+			}
+			v, ok := target[r.field]
+			if !ok {
+				errs = append(errs, fmt.Errorf("@%s: %s has no field %s", r, r.target(), r.field))
+			}
+			return v
+		})
+		if err := errors.Join(errs...); err != nil {
+			return created, fmt.Errorf("create %s: %w", fixture.ref(), err)
+		}
+
+		rec, err := bc.NewAPIPage[record](client, fixture.EntitySetName).Create(ctx, fields, bc.GetOptions{})
+		if err != nil {
+			return created, fmt.Errorf("create %s: %w", fixture.ref(), err)
+		}
+		s, _ := rec["id"].(string)
+		id, err := uuid.Parse(s)
+		if err != nil {
+			return created, fmt.Errorf("create %s: response has no id: %w", fixture.ref(), err)
+		}
+		created.entries = append(created.entries, Entry{EntitySetName: fixture.EntitySetName, Name: fixture.Name, ID: id})
+		created.records[fixture.ref()] = rec
+	}
+	return created, nil
+}
+
+// Teardown deletes the records in reverse order, so the orders are deleted before
+// their customers. Records that no longer exist are skipped and every record is
+// attempted even if some fail.
+func Teardown(ctx context.Context, client *bc.Client, entries []Entry) error {
+	var errs []error
+	for _, e := range slices.Backward(entries) {
+		if _, err := client.DeleteIfExists(ctx, e.EntitySetName, e.ID); err != nil {
+			errs = append(errs, fmt.Errorf("delete %s.%s: %w", e.EntitySetName, e.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Setup loads the fixtures from fsys, creates them and tears them down when the test
+// ends. It fails the test if a fixture cannot be loaded or created.
+func Setup(t testing.TB, client *bc.Client, fsys fs.FS, opts Options) *Created {
+	t.Helper()
+	f, err := Load(fsys, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	created, err := f.Create(context.Background(), client)
+	t.Cleanup(func() {
+		if err := created.Teardown(context.Background()); err != nil {
+			t.Errorf("teardown fixtures: %s", err)
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return created
+}
@@ -0,0 +1,196 @@
+package fixtures_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/erlorenz/bc-go/bcfake"
+	"github.com/erlorenz/bc-go/fixtures"
+	"github.com/erlorenz/bc-go/internal/bctest"
+	"github.com/google/uuid"
+)
+
+var fsys = fstest.MapFS{
+	"salesOrders.json": {Data: []byte(`{"first": {"customerId": "@customers.adatum", "customerNumber": "@customers.adatum.number",
+		"externalDocumentNumber": "@@web", "salesOrderLines": [{"itemId": "@items.bicycle", "quantity": 2}]}}`)},
+	"customers.json": {Data: []byte(`{"adatum": {"displayName": "Adatum", "number": "C001"}, "fabrikam": {"displayName": "Fabrikam"}}`)},
+	"items.json":     {Data: []byte(`{"bicycle": {"displayName": "Bicycle"}}`)},
+	"README.md":      {Data: []byte("not a fixture")},
+}
+
+func TestLoad(t *testing.T) {
+	f, err := fixtures.Load(fsys, fixtures.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, fixture := range f.Fixtures {
+		got = append(got, fixture.EntitySetName+"."+fixture.Name)
+	}
+	if want := "customers.adatum customers.fabrikam items.bicycle salesOrders.first"; strings.Join(got, " ") != want {
+		t.Errorf("wanted order %s, got %v", want, got)
+	}
+
+	// Without the file order the references still come first
+	f, err = fixtures.Load(fstest.MapFS{
+		"a.json": {Data: []byte(`{"x": {"bId": "@b.y"}}`)},
+		"b.json": {Data: []byte(`{"y": {}}`)},
+	}, fixtures.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Fixtures[0].EntitySetName != "b" {
+		t.Errorf("wanted b before a, got %+v", f.Fixtures)
+	}
+
+	// The $metadata orders entity sets referenced by a number
+	caps := bc.Capabilities{NavigationProperties: map[string][]bc.NavigationProperty{
+		"a": {{Name: "b", EntitySetName: "b", Constraints: map[string]string{"bId": "id"}}},
+	}}
+	f, err = fixtures.Load(fstest.MapFS{
+		"a.json": {Data: []byte(`{"x": {"bNumber": "B1"}}`)},
+		"b.json": {Data: []byte(`{"y": {"number": "B1"}}`)},
+	}, fixtures.Options{Capabilities: caps})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Fixtures[0].EntitySetName != "b" {
+		t.Errorf("wanted b before a from the $metadata, got %+v", f.Fixtures)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		fsys fstest.MapFS
+	}{
+		{"Cycle", fstest.MapFS{
+			"a.json": {Data: []byte(`{"x": {"bId": "@b.y"}}`)},
+			"b.json": {Data: []byte(`{"y": {"aId": "@a.x"}}`)},
+		}},
+		{"UnknownReference", fstest.MapFS{"a.json": {Data: []byte(`{"x": {"bId": "@b.missing"}}`)}}},
+		{"InvalidJSON", fstest.MapFS{"a.json": {Data: []byte(`[1, 2]`)}}},
+		{"InvalidName", fstest.MapFS{"a.json": {Data: []byte(`{"x.y": {}}`)}}},
+		{"SameEntitySet", fstest.MapFS{"a.json": {Data: []byte(`{}`)}, "a.txt": {Data: []byte(`{}`)}}},
+	}
+	opts := fixtures.Options{Unmarshalers: map[string]fixtures.Unmarshaler{".txt": json.Unmarshal}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := fixtures.Load(tt.fsys, opts); err == nil {
+				t.Error("wanted error")
+			}
+		})
+	}
+}
+
+func TestCreateAndTeardown(t *testing.T) {
+	server := bcfake.New()
+	client := bctest.NewClient(t, server)
+	f, err := fixtures.Load(fsys, fixtures.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	created, err := f.Create(context.Background(), client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	orders := server.Records("salesOrders")
+	if len(orders) != 1 {
+		t.Fatalf("wanted 1 order, got %v", orders)
+	}
+	order := orders[0]
+	lines, _ := order["salesOrderLines"].([]any)
+	if order["customerId"] != created.ID("customers", "adatum").String() || order["customerNumber"] != "C001" ||
+		order["externalDocumentNumber"] != "@web" || len(lines) != 1 ||
+		lines[0].(map[string]any)["itemId"] != created.ID("items", "bicycle").String() {
+		t.Errorf("unexpected order %v", order)
+	}
+	if created.Record("customers", "fabrikam")["displayName"] != "Fabrikam" {
+		t.Errorf("unexpected record %v", created.Record("customers", "fabrikam"))
+	}
+
+	// The entries can be saved and torn down later
+	var buf bytes.Buffer
+	if err := created.WriteJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := fixtures.ReadEntries(&buf)
+	if err != nil || len(entries) != 4 {
+		t.Fatalf("wanted 4 entries, got %v %v", entries, err)
+	}
+
+	// A record deleted by the test itself does not fail the teardown
+	if err := client.DeleteIfMatch(context.Background(), "items", created.ID("items", "bicycle"), "*"); err != nil {
+		t.Fatal(err)
+	}
+	if err := fixtures.Teardown(context.Background(), client, entries); err != nil {
+		t.Fatal(err)
+	}
+	for _, set := range []string{"customers", "items", "salesOrders"} {
+		if records := server.Records(set); len(records) != 0 {
+			t.Errorf("wanted %s to be empty, got %v", set, records)
+		}
+	}
+}
+
+func TestCreateUnknownField(t *testing.T) {
+	server := bcfake.New()
+	f, err := fixtures.Load(fstest.MapFS{
+		"a.json": {Data: []byte(`{"x": {}}`)},
+		"b.json": {Data: []byte(`{"y": {"number": "@a.x.number"}}`)},
+	}, fixtures.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	created, err := f.Create(context.Background(), bctest.NewClient(t, server))
+	if err == nil {
+		t.Fatal("wanted error for a field that is not in the created record")
+	}
+	if len(created.Entries()) != 1 {
+		t.Errorf("wanted the created record to be returned for teardown, got %v", created.Entries())
+	}
+}
+
+func TestSetup(t *testing.T) {
+	server := bcfake.New()
+	client := bctest.NewClient(t, server)
+
+	t.Run("Test", func(t *testing.T) {
+		created := fixtures.Setup(t, client, fsys, fixtures.Options{})
+		if created.ID("salesOrders", "first") == uuid.Nil {
+			t.Error("wanted the order to be created")
+		}
+	})
+	if records := server.Records("customers"); len(records) != 0 {
+		t.Errorf("wanted the fixtures to be torn down after the test, got %v", records)
+	}
+}
+
+func TestLoadUnmarshalers(t *testing.T) {
+	// A line per record of "name: displayName" stands in for a YAML decoder
+	lines := func(data []byte, v any) error {
+		records := map[string]map[string]any{}
+		for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+			name, displayName, _ := strings.Cut(line, ": ")
+			records[name] = map[string]any{"displayName": displayName}
+		}
+		*(v.(*map[string]map[string]any)) = records
+		return nil
+	}
+	f, err := fixtures.Load(fstest.MapFS{
+		"customers.txt": {Data: []byte("adatum: Adatum\nfabrikam: Fabrikam\n")},
+		"items.json":    {Data: []byte(`{"bicycle": {}}`)},
+	}, fixtures.Options{Unmarshalers: map[string]fixtures.Unmarshaler{".txt": lines}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(f.Fixtures) != 3 || f.Fixtures[0].Fields["displayName"] != "Adatum" {
+		t.Errorf("unexpected fixtures %+v", f.Fixtures)
+	}
+}
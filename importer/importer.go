@@ -0,0 +1,344 @@
+// Package importer loads CSV or NDJSON into an entity set. A declarative [Mapping]
+// converts each row to the JSON fields of the entity, rows that fail validation are
+// reported without being sent, and the rest are created in batches with [bc.Client.Bulk].
+//
+// For example, loading opening balances into a general journal:
+//
+//	mapping := importer.Mapping{Columns: []importer.Column{
+//		{Source: "Account", Field: "accountNumber", Required: true},
+//		{Source: "Date", Field: "postingDate", Type: importer.Date, Required: true},
+//		{Source: "Amount", Field: "amount", Type: importer.Number, Required: true},
+//		{Source: "Description", Field: "description", Default: "Opening balance"},
+//	}}
+//	report, err := importer.Run(ctx, client, "journals("+id+")/journalLines", f, importer.Options{Mapping: mapping})
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/erlorenz/bc-go/export"
+	"github.com/google/uuid"
+)
+
+// FieldType is the JSON type a source value is converted to.
+type FieldType int
+
+const (
+	// String is sent as is.
+	String FieldType = iota
+	// Number is a decimal, sent as a JSON number without losing precision.
+	Number
+	// Integer is a whole number.
+	Integer
+	// Bool accepts the values of strconv.ParseBool.
+	Bool
+	// Date is parsed with the column Layout and sent as YYYY-MM-DD.
+	Date
+	// GUID is a uuid.
+	GUID
+)
+
+// DefaultDateLayout is used for Date columns without a Layout.
+const DefaultDateLayout = time.DateOnly
+
+// Column maps a source column to an entity field.
+type Column struct {
+	// Source is the CSV header or the NDJSON key.
+	Source string
+	// Field is the JSON name of the entity field. Defaults to Source.
+	Field string
+	Type  FieldType
+	// Required rows fail validation if the value is empty and there is no Default.
+	Required bool
+	// Default is used when the value is empty.
+	Default string
+	// Layout is the time layout of a Date column. Defaults to DefaultDateLayout.
+	Layout string
+}
+
+// Mapping converts source rows to entity fields.
+// Source columns that are not mapped are ignored.
+type Mapping struct {
+	Columns []Column
+	// Validate is optional and runs on the converted fields of each row.
+	Validate func(fields map[string]any) []bc.FieldError
+}
+
+// Options configure [Run].
+type Options struct {
+	Format  export.Format
+	Mapping Mapping
+	// ChunkSize is the number of rows per batch. Defaults to bc.MaxBatchSize.
+	ChunkSize int
+	// Atomic makes each batch all or nothing.
+	Atomic bool
+	// ValidateOnly converts and validates every row without sending anything.
+	ValidateOnly bool
+}
+
+// RowError is a row that failed validation or was rejected by BC.
+type RowError struct {
+	// Row is the 1-based number of the record, not counting the CSV header.
+	Row int
+	// Field is empty if the error is not for a single field.
+	Field string
+	Err   error
+}
+
+func (re RowError) Error() string {
+	if re.Field == "" {
+		return fmt.Sprintf("row %d: %s", re.Row, re.Err)
+	}
+	return fmt.Sprintf("row %d: %s: %s", re.Row, re.Field, re.Err)
+}
+
+func (re RowError) Unwrap() error {
+	return re.Err
+}
+
+// Report describes the import.
+type Report struct {
+	Rows    int
+	Created int
+	// Failed is the number of rows with at least one error.
+	Failed int
+	Errors []RowError
+}
+
+// WriteCSV writes the errors as rows of "row,field,error" with a header.
+func (r Report) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"row", "field", "error"})
+	for _, re := range r.Errors {
+		cw.Write([]string{strconv.Itoa(re.Row), re.Field, re.Err.Error()})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// Run reads every row from r, converts and validates it, and creates the valid rows
+// in the entity set. Errors for single rows are in the [Report], the error is only
+// non-nil if the source cannot be read or a whole batch fails to send.
+func Run(ctx context.Context, client *bc.Client, entitySetName string, r io.Reader, opts Options) (Report, error) {
+	var report Report
+
+	rows, err := read(r, opts.Format)
+	if err != nil {
+		return report, err
+	}
+	report.Rows = len(rows)
+
+	var requests []bc.RequestOptions
+	var rowNumbers []int
+	for i, values := range rows {
+		fields, errs := opts.Mapping.convert(values)
+		if len(errs) == 0 && opts.Mapping.Validate != nil {
+			for _, fe := range opts.Mapping.Validate(fields) {
+				errs = append(errs, RowError{Field: fe.Field, Err: errors.New(fe.Reason)})
+			}
+		}
+		if len(errs) > 0 {
+			report.fail(i+1, errs...)
+			continue
+		}
+		requests = append(requests, bc.RequestOptions{Method: http.MethodPost, EntitySetName: entitySetName, Body: fields})
+		rowNumbers = append(rowNumbers, i+1)
+	}
+
+	if opts.ValidateOnly || len(requests) == 0 {
+		return report, nil
+	}
+
+	responses, err := client.Bulk(ctx, requests, bc.BulkOptions{ChunkSize: opts.ChunkSize, Atomic: opts.Atomic})
+	for i, res := range responses {
+		if err := res.Err(); err != nil {
+			report.fail(rowNumbers[i], RowError{Err: err})
+			continue
+		}
+		report.Created++
+	}
+	// Rows rejected by BC come after all the rows that failed validation.
+	slices.SortStableFunc(report.Errors, func(a, b RowError) int { return a.Row - b.Row })
+
+	if err != nil {
+		return report, fmt.Errorf("import %s: %w", entitySetName, err)
+	}
+
+	return report, nil
+}
+
+// fail adds the errors for the row.
+func (r *Report) fail(row int, errs ...RowError) {
+	r.Failed++
+	for _, re := range errs {
+		re.Row = row
+		r.Errors = append(r.Errors, re)
+	}
+}
+
+// convert maps the source values to entity fields.
+func (m Mapping) convert(values map[string]string) (map[string]any, []RowError) {
+	fields := make(map[string]any, len(m.Columns))
+	var errs []RowError
+
+	for _, col := range m.Columns {
+		field := col.Field
+		if field == "" {
+			field = col.Source
+		}
+
+		v := strings.TrimSpace(values[col.Source])
+		if v == "" {
+			v = col.Default
+		}
+		if v == "" {
+			if col.Required {
+				errs = append(errs, RowError{Field: field, Err: errors.New("required")})
+			}
+			continue
+		}
+
+		converted, err := col.convert(v)
+		if err != nil {
+			errs = append(errs, RowError{Field: field, Err: err})
+			continue
+		}
+		fields[field] = converted
+	}
+	return fields, errs
+}
+
+func (col Column) convert(v string) (any, error) {
+	switch col.Type {
+	case Number:
+		if _, err := strconv.ParseFloat(v, 64); err != nil || !json.Valid([]byte(v)) {
+			return nil, fmt.Errorf("invalid number %q", v)
+		}
+		return json.Number(v), nil
+	case Integer:
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid integer %q", v)
+		}
+		return n, nil
+	case Bool:
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid bool %q", v)
+		}
+		return b, nil
+	case Date:
+		layout := col.Layout
+		if layout == "" {
+			layout = DefaultDateLayout
+		}
+		t, err := time.Parse(layout, v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid date %q", v)
+		}
+		return t.Format(time.DateOnly), nil
+	case GUID:
+		id, err := uuid.Parse(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid guid %q", v)
+		}
+		return id, nil
+	}
+	return v, nil
+}
+
+// read returns every row as its source values.
+func read(r io.Reader, format export.Format) ([]map[string]string, error) {
+	if format == export.CSV {
+		return readCSV(r)
+	}
+	return readNDJSON(r)
+}
+
+func readCSV(r io.Reader) ([]map[string]string, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read csv header: %w", err)
+	}
+	// Strip a UTF-8 BOM, which spreadsheet exports often have.
+	header[0] = strings.TrimPrefix(header[0], "\ufeff")
+
+	var rows []map[string]string
+	for {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return rows, nil
+		}
+		if err != nil {
+			return rows, fmt.Errorf("read csv row %d: %w", len(rows)+1, err)
+		}
+
+		values := make(map[string]string, len(header))
+		for i, name := range header {
+			if i < len(record) {
+				values[name] = record[i]
+			}
+		}
+		rows = append(rows, values)
+	}
+}
+
+func readNDJSON(r io.Reader) ([]map[string]string, error) {
+	var rows []map[string]string
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		dec := json.NewDecoder(bytes.NewReader(line))
+		dec.UseNumber()
+		var object map[string]any
+		if err := dec.Decode(&object); err != nil {
+			return rows, fmt.Errorf("read ndjson row %d: %w", len(rows)+1, err)
+		}
+
+		values := make(map[string]string, len(object))
+		for k, v := range object {
+			switch v := v.(type) {
+			case nil:
+			case string:
+				values[k] = v
+			case json.Number:
+				values[k] = v.String()
+			case bool:
+				values[k] = strconv.FormatBool(v)
+			default:
+				b, _ := json.Marshal(v)
+				values[k] = string(b)
+			}
+		}
+		rows = append(rows, values)
+	}
+	if err := scanner.Err(); err != nil {
+		return rows, fmt.Errorf("read ndjson: %w", err)
+	}
+	return rows, nil
+}
@@ -0,0 +1,117 @@
+package importer_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/erlorenz/bc-go/export"
+	"github.com/erlorenz/bc-go/importer"
+	"github.com/erlorenz/bc-go/internal/bctest"
+)
+
+var openingBalances = importer.Mapping{Columns: []importer.Column{
+	{Source: "Account", Field: "accountNumber", Required: true},
+	{Source: "Date", Field: "postingDate", Type: importer.Date, Layout: "01/02/2006", Required: true},
+	{Source: "Amount", Field: "amount", Type: importer.Number, Required: true},
+	{Source: "Description", Field: "description", Default: "Opening balance"},
+}}
+
+const balancesCSV = `Account,Date,Amount,Description
+10100,12/31/2025,1500.25,
+10200,12/31/2025,abc,Cash
+,12/31/2025,10,
+40100,12/31/2025,-1500.25,Revenue
+`
+
+func TestRunCSV(t *testing.T) {
+	st := &bctest.SequenceTransport{Responses: []*http.Response{
+		bctest.NewResponse(http.StatusOK, map[string]any{"responses": []bc.BatchResponse{
+			{ID: "0", StatusCode: http.StatusCreated, Body: json.RawMessage(`{}`)},
+			{ID: "1", StatusCode: http.StatusBadRequest, Body: json.RawMessage(`{"error":{"code":"Application_DialogException","message":"Account is blocked."}}`)},
+		}}),
+	}}
+
+	report, err := importer.Run(context.Background(), bctest.NewClient(t, st), "journals(x)/journalLines", strings.NewReader(balancesCSV), importer.Options{
+		Format:  export.CSV,
+		Mapping: openingBalances,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if report.Rows != 4 || report.Created != 1 || report.Failed != 3 {
+		t.Errorf("wrong report %+v", report)
+	}
+
+	wantRows := []int{2, 3, 4}
+	for i, re := range report.Errors {
+		if re.Row != wantRows[i] {
+			t.Errorf("error %d: wanted row %d, got %d", i, wantRows[i], re.Row)
+		}
+	}
+
+	t.Run("Body", func(t *testing.T) {
+		b, _ := io.ReadAll(st.Requests[0].Body)
+		var sent struct {
+			Requests []struct {
+				Body map[string]any `json:"body"`
+			} `json:"requests"`
+		}
+		if err := json.Unmarshal(b, &sent); err != nil {
+			t.Fatal(err)
+		}
+		if len(sent.Requests) != 2 {
+			t.Fatalf("wanted 2 valid rows sent, got %d", len(sent.Requests))
+		}
+		first := sent.Requests[0].Body
+		if first["postingDate"] != "2025-12-31" || first["amount"] != 1500.25 || first["description"] != "Opening balance" {
+			t.Errorf("wrong body %v", first)
+		}
+	})
+
+	t.Run("WriteCSV", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		if err := report.WriteCSV(buf); err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(buf.String(), `2,amount,"invalid number ""abc"""`) {
+			t.Errorf("wrong report csv:\n%s", buf)
+		}
+	})
+}
+
+func TestRunNDJSONValidateOnly(t *testing.T) {
+	st := &bctest.SequenceTransport{}
+
+	src := `{"Account":"10100","Date":"12/31/2025","Amount":100}
+{"Account":"10200","Date":"2025-12-31","Amount":100}
+`
+	mapping := openingBalances
+	mapping.Validate = func(fields map[string]any) []bc.FieldError {
+		if fields["accountNumber"] == "10100" {
+			return []bc.FieldError{{Field: "accountNumber", Reason: "not allowed"}}
+		}
+		return nil
+	}
+
+	report, err := importer.Run(context.Background(), bctest.NewClient(t, st), "journalLines", strings.NewReader(src), importer.Options{
+		Mapping:      mapping,
+		ValidateOnly: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if st.Count() != 0 {
+		t.Errorf("wanted no requests, got %d", st.Count())
+	}
+	if report.Failed != 2 || len(report.Errors) != 2 {
+		t.Errorf("wrong report %+v", report)
+	}
+}
@@ -0,0 +1,188 @@
+// Package integrity checks references between entity sets, like every itemId of the
+// sales order lines existing in items, and reports the orphans. It is meant as an audit
+// before a migration. Both sides are read page by page with only the fields it needs,
+// and only the keys of the target entity sets are kept in memory.
+//
+//	result, err := integrity.Check(ctx, client, []integrity.Reference{
+//		{EntitySetName: "salesOrderLines", Field: "itemId", TargetEntitySetName: "items", Filter: "lineType eq 'Item'"},
+//		{EntitySetName: "salesOrders", Field: "customerId", TargetEntitySetName: "customers"},
+//	}, integrity.Options{})
+package integrity
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/google/uuid"
+)
+
+// Reference is a field of one entity set that refers to a record of another.
+type Reference struct {
+	// EntitySetName and Field are the referencing records and their field, e.g. "salesOrderLines" and "itemId".
+	EntitySetName string
+	Field         string
+	// TargetEntitySetName and TargetField are the referenced records and their key, e.g. "items" and "id".
+	// TargetField defaults to "id".
+	TargetEntitySetName string
+	TargetField         string
+	// Filter limits the referencing records, e.g. to lines of type Item.
+	Filter string
+}
+
+// String returns the reference in the form "salesOrderLines.itemId -> items.id".
+func (ref Reference) String() string {
+	return ref.EntitySetName + "." + ref.Field + " -> " + ref.TargetEntitySetName + "." + cmp.Or(ref.TargetField, "id")
+}
+
+// Orphan is a record with a value that has no target record.
+type Orphan struct {
+	Reference Reference
+	// RecordID is the id of the referencing record.
+	RecordID string
+	Value    string
+}
+
+// Options configure [Check].
+type Options struct {
+	// MaxPageSize of both sides. Defaults to 5000.
+	MaxPageSize int
+	// OnOrphan is called for each orphan as it is found. If it returns an error the check stops.
+	// The orphans are also in the [Result].
+	OnOrphan func(Orphan) error
+}
+
+// Result has the number of records checked per reference and the orphans in the order found.
+type Result struct {
+	Checked map[string]int
+	Orphans []Orphan
+}
+
+// record decodes any entity as a map of its fields.
+type record map[string]any
+
+func (record) Validate() error {
+	return nil
+}
+
+// Check reads the keys of each target entity set once, then the referencing records of each
+// reference, and reports the values that are not a key. Empty values and the empty GUID,
+// which BC uses for no reference, are skipped. Values are compared without case, like BC compares codes.
+func Check(ctx context.Context, client *bc.Client, refs []Reference, opts Options) (Result, error) {
+	result := Result{Checked: map[string]int{}}
+	opts.MaxPageSize = cmp.Or(opts.MaxPageSize, 5000)
+
+	for _, ref := range refs {
+		if ref.EntitySetName == "" || ref.Field == "" || ref.TargetEntitySetName == "" {
+			return result, errors.New("check integrity: EntitySetName, Field and TargetEntitySetName are required")
+		}
+	}
+
+	// Targets are read once for all references to them
+	targets := map[string]map[string]struct{}{}
+	for _, ref := range refs {
+		field := cmp.Or(ref.TargetField, "id")
+		key := ref.TargetEntitySetName + "." + field
+		keys, ok := targets[key]
+		if !ok {
+			var err error
+			keys, err = readKeys(ctx, client, ref.TargetEntitySetName, field, opts.MaxPageSize)
+			if err != nil {
+				return result, fmt.Errorf("check integrity: read %s: %w", key, err)
+			}
+			targets[key] = keys
+		}
+
+		if err := checkReference(ctx, client, ref, keys, opts, &result); err != nil {
+			return result, fmt.Errorf("check integrity: %s: %w", ref, err)
+		}
+	}
+	return result, nil
+}
+
+// readKeys returns the normalized values of the field of every record.
+func readKeys(ctx context.Context, client *bc.Client, entitySetName, field string, pageSize int) (map[string]struct{}, error) {
+	keys := map[string]struct{}{}
+	err := eachRecord(ctx, client, entitySetName, bc.ListOptions{
+		Select:      []string{field},
+		MaxPageSize: pageSize,
+	}, func(r record) error {
+		if v, ok := normalize(r[field]); ok {
+			keys[v] = struct{}{}
+		}
+		return nil
+	})
+	return keys, err
+}
+
+func checkReference(ctx context.Context, client *bc.Client, ref Reference, keys map[string]struct{}, opts Options, result *Result) error {
+	return eachRecord(ctx, client, ref.EntitySetName, bc.ListOptions{
+		Filter:      ref.Filter,
+		Select:      []string{"id", ref.Field},
+		MaxPageSize: opts.MaxPageSize,
+	}, func(r record) error {
+		result.Checked[ref.String()]++
+
+		v, ok := normalize(r[ref.Field])
+		if !ok {
+			return nil
+		}
+		if _, found := keys[v]; found {
+			return nil
+		}
+
+		id, _ := r["id"].(string)
+		orphan := Orphan{Reference: ref, RecordID: id, Value: fmt.Sprint(r[ref.Field])}
+		result.Orphans = append(result.Orphans, orphan)
+		if opts.OnOrphan != nil {
+			return opts.OnOrphan(orphan)
+		}
+		return nil
+	})
+}
+
+// eachRecord calls fn for every record of the entity set, a page at a time,
+// ordered by id so no page is skipped.
+func eachRecord(ctx context.Context, client *bc.Client, entitySetName string, opts bc.ListOptions, fn func(record) error) error {
+	page := bc.NewAPIPage[record](client, entitySetName)
+	opts.OrderBy = []string{"id"}
+
+	var nextLink string
+	for {
+		list, err := page.ListPage(ctx, nextLink, opts)
+		if err != nil {
+			return err
+		}
+		for _, r := range list.Value {
+			if err := fn(r); err != nil {
+				return err
+			}
+		}
+		if list.NextLink == "" {
+			return nil
+		}
+		nextLink = list.NextLink
+	}
+}
+
+// normalize returns the value as a lowercase string to compare.
+// Empty values and the empty GUID are not references.
+func normalize(v any) (string, bool) {
+	switch v := v.(type) {
+	case nil:
+		return "", false
+	case string:
+		if v == "" {
+			return "", false
+		}
+		if id, err := uuid.Parse(v); err == nil {
+			return id.String(), id != uuid.Nil
+		}
+		return strings.ToLower(v), true
+	default:
+		return fmt.Sprint(v), true
+	}
+}
@@ -0,0 +1,82 @@
+package integrity_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/erlorenz/bc-go/bcfake"
+	"github.com/erlorenz/bc-go/integrity"
+	"github.com/erlorenz/bc-go/internal/bctest"
+	"github.com/google/uuid"
+)
+
+func TestCheck(t *testing.T) {
+	server := bcfake.New()
+	items := server.Seed("items",
+		map[string]any{"number": "1000", "baseUnitOfMeasureCode": "PCS"},
+		map[string]any{"number": "1001", "baseUnitOfMeasureCode": "box"},
+	)
+	server.Seed("unitsOfMeasure", map[string]any{"code": "PCS"}, map[string]any{"code": "BOX"})
+	orphanID := uuid.New()
+	lines := server.Seed("salesOrderLines",
+		map[string]any{"itemId": strings.ToUpper(items[0].String())},
+		map[string]any{"itemId": items[1].String()},
+		map[string]any{"itemId": orphanID.String()},
+		map[string]any{"itemId": uuid.Nil.String()},
+		map[string]any{"itemId": ""},
+	)
+
+	refs := []integrity.Reference{
+		{EntitySetName: "salesOrderLines", Field: "itemId", TargetEntitySetName: "items"},
+		{EntitySetName: "items", Field: "baseUnitOfMeasureCode", TargetEntitySetName: "unitsOfMeasure", TargetField: "code"},
+	}
+	result, err := integrity.Check(context.Background(), bctest.NewClient(t, server), refs, integrity.Options{MaxPageSize: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := result.Checked["salesOrderLines.itemId -> items.id"]; got != 5 {
+		t.Errorf("wanted 5 lines checked, got %d", got)
+	}
+	if got := result.Checked["items.baseUnitOfMeasureCode -> unitsOfMeasure.code"]; got != 2 {
+		t.Errorf("wanted 2 items checked, got %d", got)
+	}
+	if len(result.Orphans) != 1 {
+		t.Fatalf("wanted 1 orphan, got %+v", result.Orphans)
+	}
+	orphan := result.Orphans[0]
+	if orphan.RecordID != lines[2].String() || orphan.Value != orphanID.String() {
+		t.Errorf("unexpected orphan %+v", orphan)
+	}
+}
+
+func TestCheckOnOrphan(t *testing.T) {
+	server := bcfake.New()
+	server.Seed("salesOrders", map[string]any{"customerId": uuid.NewString()}, map[string]any{"customerId": uuid.NewString()})
+
+	stop := errors.New("stop")
+	var calls int
+	_, err := integrity.Check(context.Background(), bctest.NewClient(t, server), []integrity.Reference{
+		{EntitySetName: "salesOrders", Field: "customerId", TargetEntitySetName: "customers"},
+	}, integrity.Options{OnOrphan: func(integrity.Orphan) error {
+		calls++
+		return stop
+	}})
+	if !errors.Is(err, stop) {
+		t.Errorf("wanted the OnOrphan error, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("wanted the check to stop after 1 orphan, got %d", calls)
+	}
+}
+
+func TestCheckInvalid(t *testing.T) {
+	_, err := integrity.Check(context.Background(), bctest.NewClient(t, bcfake.New()), []integrity.Reference{
+		{EntitySetName: "salesOrders", Field: "customerId"},
+	}, integrity.Options{})
+	if err == nil {
+		t.Error("wanted error without TargetEntitySetName")
+	}
+}
@@ -0,0 +1,49 @@
+package bctest
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/google/uuid"
+)
+
+// TokenGetter returns a fake access token.
+type TokenGetter struct{}
+
+func (TokenGetter) GetToken(context.Context) (bc.AccessToken, error) {
+	return "FAKEACCESSTOKEN", nil
+}
+
+// ClientConfig returns a config with random ids for the "Sandbox" environment and the
+// "v2.0" API, to change before [NewClientConfig].
+func ClientConfig() bc.ClientConfig {
+	return bc.ClientConfig{
+		TenantID:     uuid.NewString(),
+		CompanyID:    uuid.NewString(),
+		ClientID:     uuid.NewString(),
+		ClientSecret: "SECRET",
+		Environment:  "Sandbox",
+		APIEndpoint:  "v2.0",
+	}
+}
+
+// NewClient returns a client of [ClientConfig] with a fake access token that sends its
+// requests to the transport, e.g. a [SequenceTransport] or a bcfake.Server.
+// The options are applied after the auth and HTTP client.
+func NewClient(t testing.TB, transport http.RoundTripper, opts ...bc.ClientOption) *bc.Client {
+	t.Helper()
+	return NewClientConfig(t, ClientConfig(), transport, opts...)
+}
+
+// NewClientConfig is [NewClient] with another config.
+func NewClientConfig(t testing.TB, config bc.ClientConfig, transport http.RoundTripper, opts ...bc.ClientOption) *bc.Client {
+	t.Helper()
+	opts = append([]bc.ClientOption{bc.WithAuthClient(TokenGetter{}), bc.WithHTTPClient(&http.Client{Transport: transport})}, opts...)
+	client, err := bc.NewClient(config, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
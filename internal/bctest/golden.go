@@ -0,0 +1,189 @@
+package bctest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// UpdateGoldenEnv is the environment variable that makes the golden assertions write
+// the golden files instead of comparing, e.g. UPDATE_GOLDEN=1 go test ./bc.
+const UpdateGoldenEnv = "UPDATE_GOLDEN"
+
+// Redacted replaces the redacted values in golden files.
+const Redacted = "REDACTED"
+
+var uuidPattern = regexp.MustCompile(`[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`)
+
+type golden struct {
+	fields []string
+	uuids  bool
+}
+
+// GoldenOption changes what a golden assertion compares.
+type GoldenOption func(*golden)
+
+// Redact replaces the values of the fields with Redacted at any depth, e.g. an
+// "@odata.etag" or a timestamp that changes on every run.
+func Redact(fields ...string) GoldenOption {
+	return func(g *golden) {
+		g.fields = append(g.fields, fields...)
+	}
+}
+
+// RedactUUIDs replaces every uuid in the strings, including the URL, with the
+// nil uuid, for tests with random ids.
+func RedactUUIDs() GoldenOption {
+	return func(g *golden) {
+		g.uuids = true
+	}
+}
+
+// AssertGolden asserts that v encoded as JSON matches the golden file at path, with the
+// object keys sorted so the field order of a struct does not matter.
+func AssertGolden(t testing.TB, path string, v any, opts ...GoldenOption) {
+	t.Helper()
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("golden %s: %s", path, err)
+	}
+	assertGolden(t, path, b, opts)
+}
+
+// AssertGoldenRequest asserts that the method, URL, headers and JSON body of the request
+// match the golden file at path. The Authorization header is always redacted, as is the
+// User-Agent so a release does not change every golden file, and the body can still be
+// read afterwards.
+func AssertGoldenRequest(t testing.TB, path string, r *http.Request, opts ...GoldenOption) {
+	t.Helper()
+
+	var body any
+	if r.Body != nil && r.Body != http.NoBody {
+		b, err := io.ReadAll(r.Body)
+		r.Body.Close()
+		if err != nil {
+			t.Fatalf("golden %s: read body: %s", path, err)
+		}
+		r.Body = io.NopCloser(bytes.NewReader(b))
+		d := json.NewDecoder(bytes.NewReader(b))
+		d.UseNumber()
+		if err := d.Decode(&body); err != nil {
+			body = string(b)
+		}
+	}
+
+	header := map[string]string{}
+	for key, values := range r.Header {
+		header[key] = strings.Join(values, ", ")
+	}
+	for _, key := range []string{"Authorization", "User-Agent"} {
+		if _, ok := header[key]; ok {
+			header[key] = Redacted
+		}
+	}
+
+	query := map[string]string{}
+	for key, values := range r.URL.Query() {
+		query[key] = strings.Join(values, ", ")
+	}
+	b, err := json.Marshal(map[string]any{
+		"method": r.Method,
+		"url":    r.URL.Scheme + "://" + r.URL.Host + r.URL.Path,
+		"query":  query,
+		"header": header,
+		"body":   body,
+	})
+	if err != nil {
+		t.Fatalf("golden %s: %s", path, err)
+	}
+	assertGolden(t, path, b, opts)
+}
+
+func assertGolden(t testing.TB, path string, b []byte, opts []GoldenOption) {
+	t.Helper()
+	var g golden
+	for _, opt := range opts {
+		opt(&g)
+	}
+
+	// Decoding into any and encoding again sorts the keys.
+	var v any
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+	if err := d.Decode(&v); err != nil {
+		t.Fatalf("golden %s: %s", path, err)
+	}
+	got, _ := json.MarshalIndent(g.redact(v), "", "  ")
+	got = append(got, '\n')
+
+	if os.Getenv(UpdateGoldenEnv) != "" {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("golden %s: %s", path, err)
+		}
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatalf("golden %s: %s", path, err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("golden %s does not exist, run the test with %s=1 to create it", path, UpdateGoldenEnv)
+	}
+	if err != nil {
+		t.Fatalf("golden %s: %s", path, err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("golden %s does not match, run the test with %s=1 to update it:\n%s", path, UpdateGoldenEnv, diff(string(want), string(got)))
+	}
+}
+
+func (g golden) redact(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for key, value := range v {
+			if slices.Contains(g.fields, key) {
+				v[key] = Redacted
+				continue
+			}
+			v[key] = g.redact(value)
+		}
+	case []any:
+		for i, value := range v {
+			v[i] = g.redact(value)
+		}
+	case string:
+		if g.uuids {
+			return uuidPattern.ReplaceAllString(v, "00000000-0000-0000-0000-000000000000")
+		}
+	}
+	return v
+}
+
+// diff returns the lines that differ, prefixed with "-" for want and "+" for got.
+func diff(want, got string) string {
+	wantLines, gotLines := strings.Split(want, "\n"), strings.Split(got, "\n")
+	var b strings.Builder
+	for i := range max(len(wantLines), len(gotLines)) {
+		var w, g string
+		if i < len(wantLines) {
+			w = wantLines[i]
+		}
+		if i < len(gotLines) {
+			g = gotLines[i]
+		}
+		if w != g {
+			b.WriteString("- " + w + "\n+ " + g + "\n")
+		}
+	}
+	return b.String()
+}
@@ -1,6 +1,10 @@
 package bctest
 
-import "net/http"
+import (
+	"errors"
+	"net/http"
+	"sync"
+)
 
 type MockTransport struct {
 	Error    error
@@ -14,3 +18,41 @@ func (mt MockTransport) RoundTrip(r *http.Request) (*http.Response, error) {
 
 	return mt.Response, nil
 }
+
+// SequenceTransport returns each Response in order and repeats the last one
+// once exhausted. It records the requests it receives.
+type SequenceTransport struct {
+	mu        sync.Mutex
+	Responses []*http.Response
+	Requests  []*http.Request
+}
+
+func (st *SequenceTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	st.Requests = append(st.Requests, r)
+
+	if len(st.Responses) == 0 {
+		return nil, errors.New("sequence transport: no responses")
+	}
+
+	res := st.Responses[0]
+	if len(st.Responses) > 1 {
+		st.Responses = st.Responses[1:]
+	}
+	res.Request = r
+	return res, nil
+}
+
+// Count returns the number of requests received.
+func (st *SequenceTransport) Count() int {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	return len(st.Requests)
+}
+
+// NewResponse creates an http.Response with the status and the JSON body.
+func NewResponse(status int, body any) *http.Response {
+	return &http.Response{StatusCode: status, Body: NewRequestBody(body), Header: http.Header{}}
+}
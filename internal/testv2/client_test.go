@@ -56,8 +56,8 @@ func TestV2Client_GetItems(t *testing.T) {
 		Method:        "GET",
 		EntitySetName: "items",
 		QueryParams: bc.QueryParams{
-			"$select": "id,number",
-			"$top":    "5",
+			{Key: "$select", Value: "id,number"},
+			{Key: "$top", Value: "5"},
 		},
 	})
 
@@ -88,8 +88,8 @@ func TestV2Client_GetItemsAPIError(t *testing.T) {
 		Method:        "GET",
 		EntitySetName: "itemsa",
 		QueryParams: bc.QueryParams{
-			"$select": "id,number",
-			"$top":    "5",
+			{Key: "$select", Value: "id,number"},
+			{Key: "$top", Value: "5"},
 		},
 	})
 
@@ -136,7 +136,7 @@ func TestV2Client_GetItemsNetworkError(t *testing.T) {
 	req, err := client.NewRequest(context.Background(), bc.RequestOptions{
 		Method:        "GET",
 		EntitySetName: "items",
-		QueryParams:   bc.QueryParams{"$top": "5"},
+		QueryParams:   bc.QueryParams{{Key: "$top", Value: "5"}},
 	})
 
 	if err != nil {
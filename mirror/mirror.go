@@ -0,0 +1,293 @@
+// Package mirror keeps selected entity sets of a company in a local [Store], so
+// user-facing reads are served locally instead of waiting for BC on every page load.
+// Entity sets with delta links are pulled with them, the others by their
+// lastModifiedDateTime:
+//
+//	m, err := mirror.New(client, mirror.SQLStore{DB: db}, []mirror.Entity{
+//		{EntitySetName: "customers"},
+//		{EntitySetName: "items", Incremental: true, Interval: time.Hour},
+//	}, mirror.Options{})
+//	go m.Run(ctx)
+//	customers, err := m.List(ctx, "customers", mirror.Query{OrderBy: "displayName"})
+//
+// Pulls are sent with [bc.PriorityBulk], so with a [bc.RateLimiter] on the client they
+// yield to interactive requests and stay within the quota.
+package mirror
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/erlorenz/bc-go/bcsync"
+	"github.com/google/uuid"
+)
+
+// DefaultInterval is the time between the pulls of an entity set by [Mirror.Run].
+const DefaultInterval = 5 * time.Minute
+
+// Entity is a mirrored entity set.
+type Entity struct {
+	EntitySetName string
+	// Incremental pulls the records by lastModifiedDateTime, for entity sets without
+	// delta links. Records deleted in BC are not removed from the store.
+	Incremental bool
+	// ListOptions limit the mirrored records and fields, e.g. with a filter or select.
+	ListOptions bc.ListOptions
+	// Interval defaults to the Options Interval.
+	Interval time.Duration
+}
+
+// Options configure a [Mirror].
+type Options struct {
+	// Interval defaults to DefaultInterval.
+	Interval time.Duration
+	// MaxPageSize is the page size of the pulls, unless set in the Entity ListOptions.
+	// Defaults to 1000.
+	MaxPageSize int
+}
+
+// Status is the state of a mirrored entity set.
+type Status struct {
+	// LastPull is when the last successful pull started. The store has every change
+	// in BC until then. Zero before the first pull.
+	LastPull time.Time
+	// LastResult counts the changes of the last successful pull.
+	LastResult bcsync.PullResult
+	// Err is the error of the last pull, nil if it succeeded.
+	Err error
+}
+
+// Mirror pulls the entity sets into the store and serves reads from it.
+// It is safe for concurrent use.
+type Mirror struct {
+	store    Store
+	opts     Options
+	entities []Entity
+	pullers  map[string]func(context.Context) (bcsync.PullResult, error)
+
+	mu     sync.Mutex
+	status map[string]Status
+	// pulling serializes the pulls of an entity set.
+	pulling map[string]*sync.Mutex
+}
+
+// record is a record of any entity set.
+type record map[string]any
+
+func (record) Validate() error { return nil }
+
+// recordID returns the id of a pulled record.
+func recordID(r record) uuid.UUID {
+	s, _ := r["id"].(string)
+	id, _ := uuid.Parse(s)
+	return id
+}
+
+// storeAdapter is the [bcsync.Store] of an entity set. Nothing is pushed from a mirror.
+type storeAdapter struct {
+	store         Store
+	entitySetName string
+}
+
+func (s storeAdapter) Upsert(ctx context.Context, r record) error {
+	id := recordID(r)
+	if id == uuid.Nil {
+		return fmt.Errorf("record has no id")
+	}
+	return s.store.Upsert(ctx, s.entitySetName, id, r)
+}
+
+func (s storeAdapter) Remove(ctx context.Context, id uuid.UUID) error {
+	return s.store.Remove(ctx, s.entitySetName, id)
+}
+
+func (storeAdapter) Pending(context.Context) ([]bcsync.Change[record], error) {
+	return nil, nil
+}
+
+func (storeAdapter) Ack(context.Context, bcsync.Change[record], record) error {
+	return nil
+}
+
+// New returns a Mirror of the entity sets. Nothing is pulled until [Mirror.Pull] or [Mirror.Run].
+func New(client *bc.Client, store Store, entities []Entity, opts Options) (*Mirror, error) {
+	if client == nil || store == nil {
+		return nil, errors.New("new mirror: client and store are required")
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultInterval
+	}
+	if opts.MaxPageSize <= 0 {
+		opts.MaxPageSize = 1000
+	}
+
+	m := &Mirror{
+		store:   store,
+		opts:    opts,
+		pullers: map[string]func(context.Context) (bcsync.PullResult, error){},
+		status:  map[string]Status{},
+		pulling: map[string]*sync.Mutex{},
+	}
+	for _, e := range entities {
+		if e.EntitySetName == "" {
+			return nil, errors.New("new mirror: entity has no EntitySetName")
+		}
+		if _, ok := m.pullers[e.EntitySetName]; ok {
+			return nil, fmt.Errorf("new mirror: %s is repeated", e.EntitySetName)
+		}
+		if e.Interval <= 0 {
+			e.Interval = opts.Interval
+		}
+		if e.ListOptions.MaxPageSize <= 0 {
+			e.ListOptions.MaxPageSize = opts.MaxPageSize
+		}
+		m.entities = append(m.entities, e)
+		m.pullers[e.EntitySetName] = newPuller(client, store, e)
+		m.pulling[e.EntitySetName] = &sync.Mutex{}
+	}
+	return m, nil
+}
+
+func newPuller(client *bc.Client, store Store, e Entity) func(context.Context) (bcsync.PullResult, error) {
+	page := bc.NewAPIPage[record](client, e.EntitySetName)
+	adapter := storeAdapter{store: store, entitySetName: e.EntitySetName}
+	if e.Incremental {
+		inc := &bcsync.Incremental[record]{
+			Page:        page,
+			Store:       adapter,
+			Checkpoints: store,
+			Key:         "mirror:incremental:" + e.EntitySetName,
+			ID:          recordID,
+			LastModified: func(r record) time.Time {
+				s, _ := r["lastModifiedDateTime"].(string)
+				t, _ := time.Parse(time.RFC3339Nano, s)
+				return t
+			},
+			ListOptions: e.ListOptions,
+		}
+		return inc.Pull
+	}
+	engine := &bcsync.Engine[record]{
+		Page:        page,
+		Store:       adapter,
+		Checkpoints: store,
+		Key:         "mirror:" + e.EntitySetName,
+		ID:          recordID,
+		ETag: func(r record) string {
+			etag, _ := r["@odata.etag"].(string)
+			return etag
+		},
+		ListOptions: e.ListOptions,
+	}
+	return engine.Pull
+}
+
+// Pull pulls the changes of an entity set into the store now.
+func (m *Mirror) Pull(ctx context.Context, entitySetName string) (bcsync.PullResult, error) {
+	pull, ok := m.pullers[entitySetName]
+	if !ok {
+		return bcsync.PullResult{}, fmt.Errorf("pull %s: not mirrored", entitySetName)
+	}
+	lock := m.pulling[entitySetName]
+	lock.Lock()
+	defer lock.Unlock()
+
+	start := time.Now()
+	result, err := pull(bc.WithPriority(ctx, bc.PriorityBulk))
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	status := m.status[entitySetName]
+	status.Err = err
+	if err == nil {
+		status.LastPull = start
+		status.LastResult = result
+	}
+	m.status[entitySetName] = status
+
+	if err != nil {
+		return result, fmt.Errorf("pull %s: %w", entitySetName, err)
+	}
+	return result, nil
+}
+
+// PullAll pulls every entity set in order and returns the errors joined.
+func (m *Mirror) PullAll(ctx context.Context) error {
+	var errs []error
+	for _, e := range m.entities {
+		if _, err := m.Pull(ctx, e.EntitySetName); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Run pulls every entity set and then each again after its Interval, one at a time,
+// until the context is done. A failed pull is in the [Status] and retried after the
+// Interval. It returns the context error.
+func (m *Mirror) Run(ctx context.Context) error {
+	if len(m.entities) == 0 {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	next := make([]time.Time, len(m.entities))
+	for {
+		i := 0
+		for j := range next {
+			if next[j].Before(next[i]) {
+				i = j
+			}
+		}
+		if wait := time.Until(next[i]); wait > 0 {
+			timer := time.NewTimer(wait)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return ctx.Err()
+			case <-timer.C:
+			}
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		e := m.entities[i]
+		m.Pull(ctx, e.EntitySetName)
+		next[i] = time.Now().Add(e.Interval)
+	}
+}
+
+// Status returns the state of a mirrored entity set.
+func (m *Mirror) Status(entitySetName string) Status {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.status[entitySetName]
+}
+
+// Get returns a mirrored record, or an error matching ErrNotFound.
+func (m *Mirror) Get(ctx context.Context, entitySetName string, id uuid.UUID) (map[string]any, error) {
+	if _, ok := m.pullers[entitySetName]; !ok {
+		return nil, fmt.Errorf("get %s: not mirrored", entitySetName)
+	}
+	r, err := m.store.Get(ctx, entitySetName, id)
+	if err != nil {
+		return nil, fmt.Errorf("get %s(%s): %w", entitySetName, id, err)
+	}
+	return r, nil
+}
+
+// List returns the mirrored records of the entity set that match the query.
+func (m *Mirror) List(ctx context.Context, entitySetName string, q Query) ([]map[string]any, error) {
+	if _, ok := m.pullers[entitySetName]; !ok {
+		return nil, fmt.Errorf("list %s: not mirrored", entitySetName)
+	}
+	records, err := m.store.List(ctx, entitySetName, q)
+	if err != nil {
+		return nil, fmt.Errorf("list %s: %w", entitySetName, err)
+	}
+	return records, nil
+}
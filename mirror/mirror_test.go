@@ -0,0 +1,151 @@
+package mirror_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/erlorenz/bc-go/internal/bctest"
+	"github.com/erlorenz/bc-go/mirror"
+	"github.com/google/uuid"
+)
+
+const baseURL = "https://api.businesscentral.dynamics.com/v2.0"
+
+func TestPullDelta(t *testing.T) {
+	id1, id2 := uuid.New(), uuid.New()
+	st := &bctest.SequenceTransport{Responses: []*http.Response{
+		bctest.NewResponse(200, map[string]any{
+			"value":            []map[string]any{{"id": id1, "displayName": "Bravo"}, {"id": id2, "displayName": "Alpha"}},
+			"@odata.deltaLink": baseURL + "/delta1",
+		}),
+		bctest.NewResponse(200, map[string]any{
+			"value":            []map[string]any{{"id": id1, "reason": "deleted"}},
+			"@odata.deltaLink": baseURL + "/delta2",
+		}),
+	}}
+	store := &mirror.MemoryStore{}
+	m, err := mirror.New(bctest.NewClient(t, st), store, []mirror.Entity{{EntitySetName: "customers"}}, mirror.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.PullAll(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	customers, err := m.List(context.Background(), "customers", mirror.Query{OrderBy: "displayName"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(customers) != 2 || customers[0]["displayName"] != "Alpha" {
+		t.Errorf("unexpected customers %v", customers)
+	}
+	if status := m.Status("customers"); status.LastPull.IsZero() || status.LastResult.Upserted != 2 || status.Err != nil {
+		t.Errorf("unexpected status %+v", status)
+	}
+
+	if _, err := m.Pull(context.Background(), "customers"); err != nil {
+		t.Fatal(err)
+	}
+	if st.Requests[1].URL.String() != baseURL+"/delta1" {
+		t.Errorf("wanted the second pull to use the delta link, got %s", st.Requests[1].URL)
+	}
+	if _, err := m.Get(context.Background(), "customers", id1); !errors.Is(err, mirror.ErrNotFound) {
+		t.Errorf("wanted the deleted record to be removed, got %v", err)
+	}
+	if r, err := m.Get(context.Background(), "customers", id2); err != nil || r["displayName"] != "Alpha" {
+		t.Errorf("unexpected record %v %v", r, err)
+	}
+}
+
+func TestPullIncremental(t *testing.T) {
+	modified := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	value := []map[string]any{{"id": uuid.New(), "lastModifiedDateTime": modified}}
+	st := &bctest.SequenceTransport{Responses: []*http.Response{
+		bctest.NewResponse(200, map[string]any{"value": value}),
+		bctest.NewResponse(200, map[string]any{"value": value}),
+	}}
+	m, err := mirror.New(bctest.NewClient(t, st), &mirror.MemoryStore{}, []mirror.Entity{{EntitySetName: "items", Incremental: true}}, mirror.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for range 2 {
+		if _, err := m.Pull(context.Background(), "items"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if filter := st.Requests[1].URL.Query().Get("$filter"); filter == "" {
+		t.Error("wanted the second pull to filter by lastModifiedDateTime")
+	}
+	if got := st.Requests[0].Header.Get("Prefer"); got != "odata.maxpagesize=1000" {
+		t.Errorf("wanted the default page size, got %q", got)
+	}
+}
+
+func TestPullError(t *testing.T) {
+	st := &bctest.SequenceTransport{Responses: []*http.Response{
+		bctest.NewResponse(500, map[string]any{"error": map[string]any{"code": "Internal", "message": "down"}}),
+	}}
+	m, err := mirror.New(bctest.NewClient(t, st), &mirror.MemoryStore{}, []mirror.Entity{{EntitySetName: "customers"}}, mirror.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.PullAll(context.Background()); err == nil {
+		t.Fatal("wanted error")
+	}
+	if status := m.Status("customers"); status.Err == nil || !status.LastPull.IsZero() {
+		t.Errorf("unexpected status %+v", status)
+	}
+	if _, err := m.List(context.Background(), "vendors", mirror.Query{}); err == nil {
+		t.Error("wanted error for an entity set that is not mirrored")
+	}
+}
+
+func TestRun(t *testing.T) {
+	var mu sync.Mutex
+	var customers, vendors int
+	transport := bc.RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if strings.Contains(r.URL.Path, "vendors") {
+			vendors++
+		} else {
+			customers++
+		}
+		return bctest.NewResponse(200, map[string]any{"value": []any{}, "@odata.deltaLink": r.URL.String()}), nil
+	})
+	client := bctest.NewClient(t, transport)
+	m, err := mirror.New(client, &mirror.MemoryStore{}, []mirror.Entity{
+		{EntitySetName: "customers"},
+		{EntitySetName: "vendors", Interval: time.Hour},
+	}, mirror.Options{Interval: 10 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if err := m.Run(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("wanted the context error, got %v", err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if vendors != 1 || customers < 3 {
+		t.Errorf("wanted vendors pulled once and customers every interval, got %d and %d", vendors, customers)
+	}
+}
+
+func TestNew(t *testing.T) {
+	client := bctest.NewClient(t, &bctest.SequenceTransport{})
+	if _, err := mirror.New(client, &mirror.MemoryStore{}, []mirror.Entity{{EntitySetName: "a"}, {EntitySetName: "a"}}, mirror.Options{}); err == nil {
+		t.Error("wanted error for a repeated entity set")
+	}
+	if _, err := mirror.New(client, nil, nil, mirror.Options{}); err == nil {
+		t.Error("wanted error without a store")
+	}
+}
@@ -0,0 +1,289 @@
+package mirror
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/erlorenz/bc-go/bcsync"
+	"github.com/google/uuid"
+)
+
+// ErrNotFound is returned by a [Store] for a record that is not mirrored.
+var ErrNotFound = errors.New("mirror: record not found")
+
+// Store is the local read model. It also persists the checkpoints of the pulls.
+type Store interface {
+	bcsync.CheckpointStore
+	// Upsert saves a record pulled from BC.
+	Upsert(ctx context.Context, entitySetName string, id uuid.UUID, record map[string]any) error
+	// Remove deletes a record that was deleted in BC.
+	Remove(ctx context.Context, entitySetName string, id uuid.UUID) error
+	// Get returns ErrNotFound if the record is not in the store.
+	Get(ctx context.Context, entitySetName string, id uuid.UUID) (map[string]any, error)
+	// List returns the records of the entity set that match the query. Stores that
+	// cannot run the query themselves can read the records and call [Query.Apply].
+	List(ctx context.Context, entitySetName string, q Query) ([]map[string]any, error)
+}
+
+// Query selects and orders mirrored records.
+type Query struct {
+	// Where returns true for the records to return. Nil returns every record.
+	Where func(record map[string]any) bool
+	// OrderBy is the field to sort by. Records are sorted by id without it.
+	OrderBy string
+	Desc    bool
+	Skip    int
+	// Top is the most records to return, 0 for all.
+	Top int
+}
+
+// Apply returns the records that match the query, in order.
+func (q Query) Apply(records []map[string]any) []map[string]any {
+	var matched []map[string]any
+	for _, r := range records {
+		if q.Where == nil || q.Where(r) {
+			matched = append(matched, r)
+		}
+	}
+
+	field := cmp.Or(q.OrderBy, "id")
+	slices.SortStableFunc(matched, func(a, b map[string]any) int {
+		c := compare(a[field], b[field])
+		if q.Desc {
+			return -c
+		}
+		return c
+	})
+
+	matched = matched[min(q.Skip, len(matched)):]
+	if q.Top > 0 && q.Top < len(matched) {
+		matched = matched[:q.Top]
+	}
+	return matched
+}
+
+// compare orders JSON values: nil first, then numbers, strings and booleans by value.
+func compare(a, b any) int {
+	switch a := a.(type) {
+	case nil:
+		if b == nil {
+			return 0
+		}
+		return -1
+	case float64:
+		if b, ok := b.(float64); ok {
+			return cmp.Compare(a, b)
+		}
+	case string:
+		if b, ok := b.(string); ok {
+			return strings.Compare(a, b)
+		}
+	case bool:
+		if b, ok := b.(bool); ok {
+			switch {
+			case a == b:
+				return 0
+			case b:
+				return -1
+			}
+			return 1
+		}
+	}
+	if b == nil {
+		return 1
+	}
+	return strings.Compare(fmt.Sprint(a), fmt.Sprint(b))
+}
+
+// MemoryStore is an in-memory [Store], for tests and small read models that are
+// pulled again on start. It is safe for concurrent use.
+type MemoryStore struct {
+	bcsync.MemoryCheckpoints
+
+	mu      sync.RWMutex
+	records map[string]map[uuid.UUID]map[string]any
+}
+
+// Upsert implements [Store].
+func (m *MemoryStore) Upsert(_ context.Context, entitySetName string, id uuid.UUID, record map[string]any) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.records == nil {
+		m.records = map[string]map[uuid.UUID]map[string]any{}
+	}
+	if m.records[entitySetName] == nil {
+		m.records[entitySetName] = map[uuid.UUID]map[string]any{}
+	}
+	m.records[entitySetName][id] = maps.Clone(record)
+	return nil
+}
+
+// Remove implements [Store].
+func (m *MemoryStore) Remove(_ context.Context, entitySetName string, id uuid.UUID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.records[entitySetName], id)
+	return nil
+}
+
+// Get implements [Store].
+func (m *MemoryStore) Get(_ context.Context, entitySetName string, id uuid.UUID) (map[string]any, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	r, ok := m.records[entitySetName][id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return maps.Clone(r), nil
+}
+
+// List implements [Store].
+func (m *MemoryStore) List(_ context.Context, entitySetName string, q Query) ([]map[string]any, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	records := make([]map[string]any, 0, len(m.records[entitySetName]))
+	for _, r := range m.records[entitySetName] {
+		records = append(records, maps.Clone(r))
+	}
+	return q.Apply(records), nil
+}
+
+// Schema creates the tables of a [SQLStore] in SQLite or Postgres.
+const Schema = `CREATE TABLE IF NOT EXISTS mirror_records (
+	entity_set TEXT NOT NULL,
+	id TEXT NOT NULL,
+	data TEXT NOT NULL,
+	PRIMARY KEY (entity_set, id)
+);
+CREATE TABLE IF NOT EXISTS mirror_checkpoints (
+	name TEXT PRIMARY KEY,
+	value TEXT NOT NULL
+);`
+
+// SQLStore is a [Store] in the tables of [Schema], with the records as JSON.
+// It works with the SQLite and Postgres drivers. List reads every record of the
+// entity set and applies the query in memory.
+type SQLStore struct {
+	DB *sql.DB
+	// Postgres uses "$1" placeholders instead of "?".
+	Postgres bool
+}
+
+// bind replaces the "?" placeholders of the query for Postgres.
+func (s SQLStore) bind(query string) string {
+	if !s.Postgres {
+		return query
+	}
+	var b strings.Builder
+	n := 0
+	for _, c := range query {
+		if c == '?' {
+			n++
+			fmt.Fprintf(&b, "$%d", n)
+			continue
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
+}
+
+// Upsert implements [Store].
+func (s SQLStore) Upsert(ctx context.Context, entitySetName string, id uuid.UUID, record map[string]any) error {
+	b, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("upsert record: %w", err)
+	}
+	_, err = s.DB.ExecContext(ctx, s.bind(`INSERT INTO mirror_records (entity_set, id, data) VALUES (?, ?, ?)
+		ON CONFLICT (entity_set, id) DO UPDATE SET data = excluded.data`), entitySetName, id.String(), string(b))
+	if err != nil {
+		return fmt.Errorf("upsert record: %w", err)
+	}
+	return nil
+}
+
+// Remove implements [Store].
+func (s SQLStore) Remove(ctx context.Context, entitySetName string, id uuid.UUID) error {
+	_, err := s.DB.ExecContext(ctx, s.bind(`DELETE FROM mirror_records WHERE entity_set = ? AND id = ?`), entitySetName, id.String())
+	if err != nil {
+		return fmt.Errorf("remove record: %w", err)
+	}
+	return nil
+}
+
+// Get implements [Store].
+func (s SQLStore) Get(ctx context.Context, entitySetName string, id uuid.UUID) (map[string]any, error) {
+	var data string
+	err := s.DB.QueryRowContext(ctx, s.bind(`SELECT data FROM mirror_records WHERE entity_set = ? AND id = ?`), entitySetName, id.String()).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get record: %w", err)
+	}
+	var record map[string]any
+	if err := json.Unmarshal([]byte(data), &record); err != nil {
+		return nil, fmt.Errorf("get record: %w", err)
+	}
+	return record, nil
+}
+
+// List implements [Store].
+func (s SQLStore) List(ctx context.Context, entitySetName string, q Query) ([]map[string]any, error) {
+	rows, err := s.DB.QueryContext(ctx, s.bind(`SELECT data FROM mirror_records WHERE entity_set = ?`), entitySetName)
+	if err != nil {
+		return nil, fmt.Errorf("list records: %w", err)
+	}
+	defer rows.Close()
+
+	var records []map[string]any
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("list records: %w", err)
+		}
+		var record map[string]any
+		if err := json.Unmarshal([]byte(data), &record); err != nil {
+			return nil, fmt.Errorf("list records: %w", err)
+		}
+		records = append(records, record)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list records: %w", err)
+	}
+	return q.Apply(records), nil
+}
+
+// LoadCheckpoint implements [bcsync.CheckpointStore].
+func (s SQLStore) LoadCheckpoint(ctx context.Context, key string) (string, error) {
+	var value string
+	err := s.DB.QueryRowContext(ctx, s.bind(`SELECT value FROM mirror_checkpoints WHERE name = ?`), key).Scan(&value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", bcsync.ErrNoCheckpoint
+	}
+	if err != nil {
+		return "", fmt.Errorf("load checkpoint: %w", err)
+	}
+	return value, nil
+}
+
+// SaveCheckpoint implements [bcsync.CheckpointStore].
+func (s SQLStore) SaveCheckpoint(ctx context.Context, key string, value string) error {
+	_, err := s.DB.ExecContext(ctx, s.bind(`INSERT INTO mirror_checkpoints (name, value) VALUES (?, ?)
+		ON CONFLICT (name) DO UPDATE SET value = excluded.value`), key, value)
+	if err != nil {
+		return fmt.Errorf("save checkpoint: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package mirror_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/erlorenz/bc-go/bcsync"
+	"github.com/erlorenz/bc-go/mirror"
+	"github.com/google/uuid"
+)
+
+func TestQueryApply(t *testing.T) {
+	records := []map[string]any{
+		{"id": "3", "displayName": "Charlie", "balance": 30.0, "blocked": true},
+		{"id": "1", "displayName": "Alpha", "balance": 10.0, "blocked": false},
+		{"id": "2", "displayName": "Bravo", "balance": nil, "blocked": false},
+	}
+	names := func(records []map[string]any) []any {
+		var names []any
+		for _, r := range records {
+			names = append(names, r["displayName"])
+		}
+		return names
+	}
+
+	tests := []struct {
+		name  string
+		query mirror.Query
+		want  []any
+	}{
+		{"ByID", mirror.Query{}, []any{"Alpha", "Bravo", "Charlie"}},
+		{"OrderByNumberDesc", mirror.Query{OrderBy: "balance", Desc: true}, []any{"Charlie", "Alpha", "Bravo"}},
+		{"Where", mirror.Query{Where: func(r map[string]any) bool { return r["blocked"] == false }}, []any{"Alpha", "Bravo"}},
+		{"SkipTop", mirror.Query{Skip: 1, Top: 1}, []any{"Bravo"}},
+		{"SkipAll", mirror.Query{Skip: 5}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := names(tt.query.Apply(records))
+			if len(got) != len(tt.want) {
+				t.Fatalf("wanted %v, got %v", tt.want, got)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("wanted %v, got %v", tt.want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+	ctx := context.Background()
+	store := &mirror.MemoryStore{}
+	id := uuid.New()
+
+	r := map[string]any{"id": id.String(), "displayName": "Adatum"}
+	if err := store.Upsert(ctx, "customers", id, r); err != nil {
+		t.Fatal(err)
+	}
+	r["displayName"] = "changed"
+	got, err := store.Get(ctx, "customers", id)
+	if err != nil || got["displayName"] != "Adatum" {
+		t.Errorf("wanted a copy of the record, got %v %v", got, err)
+	}
+	if _, err := store.Get(ctx, "vendors", id); !errors.Is(err, mirror.ErrNotFound) {
+		t.Errorf("wanted ErrNotFound for another entity set, got %v", err)
+	}
+	if err := store.Remove(ctx, "customers", id); err != nil {
+		t.Fatal(err)
+	}
+	if records, _ := store.List(ctx, "customers", mirror.Query{}); len(records) != 0 {
+		t.Errorf("wanted no records, got %v", records)
+	}
+	if _, err := store.LoadCheckpoint(ctx, "customers"); !errors.Is(err, bcsync.ErrNoCheckpoint) {
+		t.Errorf("wanted ErrNoCheckpoint, got %v", err)
+	}
+}
@@ -0,0 +1,126 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/erlorenz/bc-go/filter"
+	"github.com/google/uuid"
+)
+
+// Account is the accounts entity, a G/L account.
+type Account struct {
+	ETag                           string    `json:"@odata.etag,omitempty"`
+	ID                             uuid.UUID `json:"id"`
+	Number                         string    `json:"number"`
+	DisplayName                    string    `json:"displayName"`
+	Category                       string    `json:"category"`
+	SubCategory                    string    `json:"subCategory"`
+	Blocked                        bool      `json:"blocked"`
+	AccountType                    string    `json:"accountType"`
+	DirectPosting                  bool      `json:"directPosting"`
+	NetChange                      float64   `json:"netChange"`
+	ConsolidationTranslationMethod string    `json:"consolidationTranslationMethod"`
+	ConsolidationDebitAccount      string    `json:"consolidationDebitAccount"`
+	ConsolidationCreditAccount     string    `json:"consolidationCreditAccount"`
+	ExcludeFromConsolidation       bool      `json:"excludeFromConsolidation"`
+	LastModifiedDateTime           time.Time `json:"lastModifiedDateTime"`
+}
+
+// Validate implements the [bc.Validator] interface.
+func (a Account) Validate() error {
+	return bc.ValidateStruct(a)
+}
+
+// GeneralLedgerEntry is the generalLedgerEntries entity.
+type GeneralLedgerEntry struct {
+	ETag                           string    `json:"@odata.etag,omitempty"`
+	ID                             uuid.UUID `json:"id"`
+	EntryNumber                    int       `json:"entryNumber"`
+	PostingDate                    bc.Date   `json:"postingDate"`
+	DocumentNumber                 string    `json:"documentNumber"`
+	DocumentType                   string    `json:"documentType"`
+	AccountID                      uuid.UUID `json:"accountId"`
+	AccountNumber                  string    `json:"accountNumber"`
+	Description                    string    `json:"description"`
+	DebitAmount                    float64   `json:"debitAmount"`
+	CreditAmount                   float64   `json:"creditAmount"`
+	AdditionalCurrencyDebitAmount  float64   `json:"additionalCurrencyDebitAmount"`
+	AdditionalCurrencyCreditAmount float64   `json:"additionalCurrencyCreditAmount"`
+	LastModifiedDateTime           time.Time `json:"lastModifiedDateTime"`
+}
+
+// Validate implements the [bc.Validator] interface.
+func (e GeneralLedgerEntry) Validate() error {
+	return bc.ValidateStruct(e)
+}
+
+// Account returns the reference to the G/L account of the entry.
+func (e GeneralLedgerEntry) Account() Ref[Account] {
+	return Ref[Account]{ID: e.AccountID, Code: e.AccountNumber}
+}
+
+// Amount returns the debit amount minus the credit amount.
+func (e GeneralLedgerEntry) Amount() float64 {
+	return e.DebitAmount - e.CreditAmount
+}
+
+// FixedAsset is the fixedAssets entity.
+type FixedAsset struct {
+	ETag                   string    `json:"@odata.etag,omitempty"`
+	ID                     uuid.UUID `json:"id"`
+	Number                 string    `json:"number"`
+	DisplayName            string    `json:"displayName"`
+	FixedAssetLocationCode string    `json:"fixedAssetLocationCode"`
+	FixedAssetLocationID   uuid.UUID `json:"fixedAssetLocationId"`
+	ClassCode              string    `json:"classCode"`
+	SubclassCode           string    `json:"subclassCode"`
+	Blocked                bool      `json:"blocked"`
+	SerialNumber           string    `json:"serialNumber"`
+	EmployeeNumber         string    `json:"employeeNumber"`
+	EmployeeID             uuid.UUID `json:"employeeId"`
+	UnderMaintenance       bool      `json:"underMaintenance"`
+	LastModifiedDateTime   time.Time `json:"lastModifiedDateTime"`
+}
+
+// Validate implements the [bc.Validator] interface.
+func (f FixedAsset) Validate() error {
+	return bc.ValidateStruct(f)
+}
+
+// AccountByNumber returns the G/L account with the number.
+func (c *Client) AccountByNumber(ctx context.Context, number string) (Account, error) {
+	accounts, err := c.Accounts().List(ctx, bc.ListOptions{Filter: "number eq " + filter.Literal(number), Top: 1})
+	if err != nil {
+		return Account{}, fmt.Errorf("get account %s: %w", number, err)
+	}
+	if len(accounts) == 0 {
+		return Account{}, fmt.Errorf("get account %s: %w", number, bc.ErrNotFound)
+	}
+	return accounts[0], nil
+}
+
+// LedgerEntries returns the G/L entries of the account posted in the period, in entry
+// order. A zero From or To leaves that end open, and an empty account number returns the
+// entries of all accounts, e.g. for a trial balance of a [filter.Month].
+func (c *Client) LedgerEntries(ctx context.Context, accountNumber string, period filter.Period) ([]GeneralLedgerEntry, error) {
+	var conds []string
+	if accountNumber != "" {
+		conds = append(conds, "accountNumber eq "+filter.Literal(accountNumber))
+	}
+	if !period.From.IsZero() {
+		conds = append(conds, "postingDate ge "+filter.Literal(period.From))
+	}
+	if !period.To.IsZero() {
+		conds = append(conds, "postingDate le "+filter.Literal(period.To))
+	}
+
+	entries, err := c.GeneralLedgerEntries().List(ctx, bc.ListOptions{Filter: strings.Join(conds, " and "), OrderBy: []string{"entryNumber"}})
+	if err != nil {
+		return nil, fmt.Errorf("ledger entries of account %s: %w", accountNumber, err)
+	}
+	return entries, nil
+}
@@ -0,0 +1,74 @@
+package models_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/erlorenz/bc-go/filter"
+	"github.com/erlorenz/bc-go/internal/bctest"
+	"github.com/erlorenz/bc-go/models"
+	"github.com/google/uuid"
+)
+
+func TestLedgerEntries(t *testing.T) {
+	accountID := uuid.New()
+	st := &bctest.SequenceTransport{Responses: []*http.Response{
+		bctest.NewResponse(200, map[string]any{"value": []map[string]any{
+			{"id": uuid.NewString(), "entryNumber": 1, "postingDate": "2026-03-02", "accountId": accountID, "accountNumber": "10100", "debitAmount": 100},
+			{"id": uuid.NewString(), "entryNumber": 2, "postingDate": "2026-03-05", "accountId": accountID, "accountNumber": "10100", "creditAmount": 40},
+		}}),
+		bctest.NewResponse(200, map[string]any{"value": []map[string]any{}}),
+	}}
+	api := models.NewClient(bctest.NewClient(t, st))
+	ctx := context.Background()
+
+	entries, err := api.LedgerEntries(ctx, "10100", filter.Month(bc.Date{Year: 2026, Month: 3, Day: 15}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].Amount()+entries[1].Amount() != 60 {
+		t.Errorf("unexpected entries %+v", entries)
+	}
+	if ref := entries[0].Account(); ref.ID != accountID || ref.Code != "10100" {
+		t.Errorf("unexpected account reference %+v", ref)
+	}
+
+	q := st.Requests[0].URL.Query()
+	if want := "accountNumber eq '10100' and postingDate ge 2026-03-01 and postingDate le 2026-03-31"; q.Get("$filter") != want {
+		t.Errorf("wanted filter %q, got %q", want, q.Get("$filter"))
+	}
+	if !strings.HasSuffix(st.Requests[0].URL.Path, "/generalLedgerEntries") || q.Get("$orderby") != "entryNumber" {
+		t.Errorf("unexpected request %s", st.Requests[0].URL)
+	}
+
+	if _, err := api.AccountByNumber(ctx, "99999"); !errors.Is(err, bc.ErrNotFound) {
+		t.Errorf("wanted bc.ErrNotFound, got %v", err)
+	}
+}
+
+func TestItemPostingGroupRefs(t *testing.T) {
+	groupID := uuid.New()
+	st := &bctest.SequenceTransport{Responses: []*http.Response{
+		bctest.NewResponse(200, map[string]any{"id": groupID, "code": "RESALE", "description": "Resale items"}),
+	}}
+	api := models.NewClient(bctest.NewClient(t, st))
+
+	item := models.Item{InventoryPostingGroupID: groupID, InventoryPostingGroupCode: "RESALE"}
+	if !item.GeneralProductPostingGroup().IsZero() {
+		t.Error("wanted an empty general product posting group")
+	}
+	group, err := item.InventoryPostingGroup().Get(context.Background(), api.InventoryPostingGroups())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if group.Code != "RESALE" {
+		t.Errorf("unexpected group %+v", group)
+	}
+	if want := "/inventoryPostingGroups(" + groupID.String() + ")"; !strings.HasSuffix(st.Requests[0].URL.Path, want) {
+		t.Errorf("wanted path ending %s, got %s", want, st.Requests[0].URL.Path)
+	}
+}
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/google/uuid"
+)
+
+// BankAccount is the bankAccounts entity.
+type BankAccount struct {
+	ETag                 string    `json:"@odata.etag,omitempty"`
+	ID                   uuid.UUID `json:"id"`
+	Number               string    `json:"number"`
+	DisplayName          string    `json:"displayName"`
+	BankAccountNumber    string    `json:"bankAccountNumber"`
+	Blocked              bool      `json:"blocked"`
+	CurrencyID           uuid.UUID `json:"currencyId"`
+	CurrencyCode         string    `json:"currencyCode"`
+	IBAN                 string    `json:"iban"`
+	IntercompanyEnabled  bool      `json:"intercompanyEnabled"`
+	LastModifiedDateTime time.Time `json:"lastModifiedDateTime"`
+}
+
+// Validate implements the [bc.Validator] interface.
+func (b BankAccount) Validate() error {
+	return bc.ValidateStruct(b)
+}
@@ -0,0 +1,77 @@
+package models
+
+import (
+	"time"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/google/uuid"
+)
+
+// Customer is the customers entity.
+type Customer struct {
+	ETag                  string    `json:"@odata.etag,omitempty"`
+	ID                    uuid.UUID `json:"id"`
+	Number                string    `json:"number"`
+	DisplayName           string    `json:"displayName"`
+	Type                  string    `json:"type"`
+	AddressLine1          string    `json:"addressLine1"`
+	AddressLine2          string    `json:"addressLine2"`
+	City                  string    `json:"city"`
+	State                 string    `json:"state"`
+	Country               string    `json:"country"`
+	PostalCode            string    `json:"postalCode"`
+	PhoneNumber           string    `json:"phoneNumber"`
+	Email                 string    `json:"email"`
+	Website               string    `json:"website"`
+	SalespersonCode       string    `json:"salespersonCode"`
+	BalanceDue            float64   `json:"balanceDue"`
+	CreditLimit           float64   `json:"creditLimit"`
+	TaxLiable             bool      `json:"taxLiable"`
+	TaxAreaID             uuid.UUID `json:"taxAreaId"`
+	TaxAreaDisplayName    string    `json:"taxAreaDisplayName"`
+	TaxRegistrationNumber string    `json:"taxRegistrationNumber"`
+	CurrencyID            uuid.UUID `json:"currencyId"`
+	CurrencyCode          string    `json:"currencyCode"`
+	PaymentTermsID        uuid.UUID `json:"paymentTermsId"`
+	ShipmentMethodID      uuid.UUID `json:"shipmentMethodId"`
+	PaymentMethodID       uuid.UUID `json:"paymentMethodId"`
+	Blocked               string    `json:"blocked"`
+	LastModifiedDateTime  time.Time `json:"lastModifiedDateTime"`
+}
+
+// Validate implements the [bc.Validator] interface.
+func (c Customer) Validate() error {
+	return bc.ValidateStruct(c)
+}
+
+// Vendor is the vendors entity.
+type Vendor struct {
+	ETag                  string    `json:"@odata.etag,omitempty"`
+	ID                    uuid.UUID `json:"id"`
+	Number                string    `json:"number"`
+	DisplayName           string    `json:"displayName"`
+	AddressLine1          string    `json:"addressLine1"`
+	AddressLine2          string    `json:"addressLine2"`
+	City                  string    `json:"city"`
+	State                 string    `json:"state"`
+	Country               string    `json:"country"`
+	PostalCode            string    `json:"postalCode"`
+	PhoneNumber           string    `json:"phoneNumber"`
+	Email                 string    `json:"email"`
+	Website               string    `json:"website"`
+	TaxRegistrationNumber string    `json:"taxRegistrationNumber"`
+	CurrencyID            uuid.UUID `json:"currencyId"`
+	CurrencyCode          string    `json:"currencyCode"`
+	IRS1099Code           string    `json:"irs1099Code"`
+	PaymentTermsID        uuid.UUID `json:"paymentTermsId"`
+	PaymentMethodID       uuid.UUID `json:"paymentMethodId"`
+	TaxLiable             bool      `json:"taxLiable"`
+	Blocked               string    `json:"blocked"`
+	Balance               float64   `json:"balance"`
+	LastModifiedDateTime  time.Time `json:"lastModifiedDateTime"`
+}
+
+// Validate implements the [bc.Validator] interface.
+func (v Vendor) Validate() error {
+	return bc.ValidateStruct(v)
+}
@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/google/uuid"
+)
+
+// DocumentAttachment is the documentAttachments entity. The file is the
+// "attachmentContent" stream, see [bc.Client.DownloadMedia].
+type DocumentAttachment struct {
+	ETag                 string    `json:"@odata.etag,omitempty"`
+	ID                   uuid.UUID `json:"id"`
+	FileName             string    `json:"fileName"`
+	ByteSize             int64     `json:"byteSize"`
+	ParentType           string    `json:"parentType"`
+	ParentID             uuid.UUID `json:"parentId"`
+	LineNumber           int       `json:"lineNumber"`
+	LastModifiedDateTime time.Time `json:"lastModifiedDateTime"`
+}
+
+// Validate implements the [bc.Validator] interface.
+func (d DocumentAttachment) Validate() error {
+	return bc.ValidateStruct(d)
+}
@@ -0,0 +1,115 @@
+package models
+
+import "github.com/erlorenz/bc-go/bc"
+
+// Entity set names of the standard API v2.0.
+const (
+	EntitySetAccounts                     = "accounts"
+	EntitySetAgedAccountsPayables         = "agedAccountsPayables"
+	EntitySetAgedAccountsReceivables      = "agedAccountsReceivables"
+	EntitySetAttachments                  = "attachments"
+	EntitySetBalanceSheets                = "balanceSheets"
+	EntitySetBankAccounts                 = "bankAccounts"
+	EntitySetCashFlowStatements           = "cashFlowStatements"
+	EntitySetCompanies                    = "companies"
+	EntitySetCompanyInformation           = "companyInformation"
+	EntitySetContacts                     = "contacts"
+	EntitySetContactsInformation          = "contactsInformation"
+	EntitySetCountriesRegions             = "countriesRegions"
+	EntitySetCurrencies                   = "currencies"
+	EntitySetCurrencyExchangeRates        = "currencyExchangeRates"
+	EntitySetCustomerFinancialDetails     = "customerFinancialDetails"
+	EntitySetCustomerPaymentJournals      = "customerPaymentJournals"
+	EntitySetCustomerPayments             = "customerPayments"
+	EntitySetCustomerReturnReasons        = "customerReturnReasons"
+	EntitySetCustomerSales                = "customerSales"
+	EntitySetCustomers                    = "customers"
+	EntitySetDefaultDimensions            = "defaultDimensions"
+	EntitySetDimensionSetLines            = "dimensionSetLines"
+	EntitySetDimensionValues              = "dimensionValues"
+	EntitySetDimensions                   = "dimensions"
+	EntitySetDisputeStatus                = "disputeStatus"
+	EntitySetDocumentAttachments          = "documentAttachments"
+	EntitySetEmployees                    = "employees"
+	EntitySetEntityDefinitions            = "entityDefinitions"
+	EntitySetFixedAssetLocations          = "fixedAssetLocations"
+	EntitySetFixedAssets                  = "fixedAssets"
+	EntitySetGeneralBusinessPostingGroups = "generalBusinessPostingGroups"
+	EntitySetGeneralLedgerEntries         = "generalLedgerEntries"
+	EntitySetGeneralProductPostingGroups  = "generalProductPostingGroups"
+	EntitySetIncomeStatements             = "incomeStatements"
+	EntitySetInventoryPostingGroups       = "inventoryPostingGroups"
+	EntitySetIRS1099                      = "irs1099"
+	EntitySetItemCategories               = "itemCategories"
+	EntitySetItemLedgerEntries            = "itemLedgerEntries"
+	EntitySetItemVariants                 = "itemVariants"
+	EntitySetItems                        = "items"
+	EntitySetJournalLines                 = "journalLines"
+	EntitySetJournals                     = "journals"
+	EntitySetLocations                    = "locations"
+	EntitySetOpportunities                = "opportunities"
+	EntitySetPaymentMethods               = "paymentMethods"
+	EntitySetPaymentTerms                 = "paymentTerms"
+	EntitySetProjects                     = "projects"
+	EntitySetPurchaseCreditMemoLines      = "purchaseCreditMemoLines"
+	EntitySetPurchaseCreditMemos          = "purchaseCreditMemos"
+	EntitySetPurchaseInvoiceLines         = "purchaseInvoiceLines"
+	EntitySetPurchaseInvoices             = "purchaseInvoices"
+	EntitySetPurchaseOrderLines           = "purchaseOrderLines"
+	EntitySetPurchaseOrders               = "purchaseOrders"
+	EntitySetPurchaseReceiptLines         = "purchaseReceiptLines"
+	EntitySetPurchaseReceipts             = "purchaseReceipts"
+	EntitySetRetainedEarningsStatements   = "retainedEarningsStatements"
+	EntitySetSalesCreditMemoLines         = "salesCreditMemoLines"
+	EntitySetSalesCreditMemos             = "salesCreditMemos"
+	EntitySetSalesInvoiceLines            = "salesInvoiceLines"
+	EntitySetSalesInvoices                = "salesInvoices"
+	EntitySetSalesOrderLines              = "salesOrderLines"
+	EntitySetSalesOrders                  = "salesOrders"
+	EntitySetSalesQuoteLines              = "salesQuoteLines"
+	EntitySetSalesQuotes                  = "salesQuotes"
+	EntitySetSalesShipmentLines           = "salesShipmentLines"
+	EntitySetSalesShipments               = "salesShipments"
+	EntitySetShipmentMethods              = "shipmentMethods"
+	EntitySetSubscriptions                = "subscriptions"
+	EntitySetTaxAreas                     = "taxAreas"
+	EntitySetTaxGroups                    = "taxGroups"
+	EntitySetTimeRegistrationEntries      = "timeRegistrationEntries"
+	EntitySetTrialBalances                = "trialBalances"
+	EntitySetUnitsOfMeasure               = "unitsOfMeasure"
+	EntitySetVendorPaymentJournals        = "vendorPaymentJournals"
+	EntitySetVendorPayments               = "vendorPayments"
+	EntitySetVendorPurchases              = "vendorPurchases"
+	EntitySetVendors                      = "vendors"
+)
+
+// The models are registered so bc.NewAPIPageOf and bc.EntitySetOf infer their entity set.
+// Lines are registered with the entity set that lists them across documents.
+func init() {
+	register[Account](EntitySetAccounts)
+	register[BankAccount](EntitySetBankAccounts)
+	register[Customer](EntitySetCustomers)
+	register[CustomerPayment](EntitySetCustomerPayments)
+	register[CustomerPaymentJournal](EntitySetCustomerPaymentJournals)
+	register[DocumentAttachment](EntitySetDocumentAttachments)
+	register[FixedAsset](EntitySetFixedAssets)
+	register[GeneralBusinessPostingGroup](EntitySetGeneralBusinessPostingGroups)
+	register[GeneralLedgerEntry](EntitySetGeneralLedgerEntries)
+	register[GeneralProductPostingGroup](EntitySetGeneralProductPostingGroups)
+	register[InventoryPostingGroup](EntitySetInventoryPostingGroups)
+	register[Item](EntitySetItems)
+	register[SalesInvoice](EntitySetSalesInvoices)
+	register[SalesInvoiceLine](EntitySetSalesInvoiceLines)
+	register[SalesOrder](EntitySetSalesOrders)
+	register[SalesOrderLine](EntitySetSalesOrderLines)
+	register[SalesQuote](EntitySetSalesQuotes)
+	register[SalesQuoteLine](EntitySetSalesQuoteLines)
+	register[SalesShipment](EntitySetSalesShipments)
+	register[Vendor](EntitySetVendors)
+	register[VendorPayment](EntitySetVendorPayments)
+	register[VendorPaymentJournal](EntitySetVendorPaymentJournals)
+}
+
+func register[T bc.Validator](name string) {
+	bc.RegisterEntitySet[T](bc.EntitySetInfo{Name: name, Key: "id"})
+}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/google/uuid"
+)
+
+// Item is the items entity.
+type Item struct {
+	ETag                           string    `json:"@odata.etag,omitempty"`
+	ID                             uuid.UUID `json:"id"`
+	Number                         string    `json:"number"`
+	DisplayName                    string    `json:"displayName"`
+	DisplayName2                   string    `json:"displayName2"`
+	Type                           string    `json:"type"`
+	ItemCategoryID                 uuid.UUID `json:"itemCategoryId"`
+	ItemCategoryCode               string    `json:"itemCategoryCode"`
+	Blocked                        bool      `json:"blocked"`
+	GTIN                           string    `json:"gtin"`
+	Inventory                      float64   `json:"inventory"`
+	UnitPrice                      float64   `json:"unitPrice"`
+	PriceIncludesTax               bool      `json:"priceIncludesTax"`
+	UnitCost                       float64   `json:"unitCost"`
+	TaxGroupID                     uuid.UUID `json:"taxGroupId"`
+	TaxGroupCode                   string    `json:"taxGroupCode"`
+	BaseUnitOfMeasureID            uuid.UUID `json:"baseUnitOfMeasureId"`
+	BaseUnitOfMeasureCode          string    `json:"baseUnitOfMeasureCode"`
+	GeneralProductPostingGroupID   uuid.UUID `json:"generalProductPostingGroupId"`
+	GeneralProductPostingGroupCode string    `json:"generalProductPostingGroupCode"`
+	InventoryPostingGroupID        uuid.UUID `json:"inventoryPostingGroupId"`
+	InventoryPostingGroupCode      string    `json:"inventoryPostingGroupCode"`
+	LastModifiedDateTime           time.Time `json:"lastModifiedDateTime"`
+}
+
+// Validate implements the [bc.Validator] interface.
+func (i Item) Validate() error {
+	return bc.ValidateStruct(i)
+}
@@ -0,0 +1,122 @@
+// Package models has the entities of the Business Central API v2.0 and a fluent
+// [Client] to access them, e.g.
+//
+//	api := models.NewClient(client)
+//	customers, err := api.Customers().List(ctx, bc.ListOptions{Top: 10})
+//	line, err := api.SalesOrders().Lines(orderID).Create(ctx, body, bc.GetOptions{})
+//
+// The bc.Client must use the "v2.0" APIEndpoint. The models are registered with
+// [bc.RegisterEntitySet], so bc.NewAPIPageOf[models.Customer](client) works as well.
+package models
+
+import (
+	"github.com/erlorenz/bc-go/bc"
+)
+
+// Client is a fluent wrapper around a [bc.Client] with a method per entity set.
+type Client struct {
+	client *bc.Client
+}
+
+// NewClient creates a [Client]. It panics if client is nil.
+func NewClient(client *bc.Client) *Client {
+	if client == nil {
+		panic("create models client: client is nil")
+	}
+	return &Client{client: client}
+}
+
+// BC returns the underlying [bc.Client].
+func (c *Client) BC() *bc.Client {
+	return c.client
+}
+
+// Customers returns the customers entity set.
+func (c *Client) Customers() *bc.APIPage[Customer] {
+	return bc.NewAPIPage[Customer](c.client, "customers")
+}
+
+// Vendors returns the vendors entity set.
+func (c *Client) Vendors() *bc.APIPage[Vendor] {
+	return bc.NewAPIPage[Vendor](c.client, "vendors")
+}
+
+// Items returns the items entity set.
+func (c *Client) Items() *bc.APIPage[Item] {
+	return bc.NewAPIPage[Item](c.client, "items")
+}
+
+// SalesOrders returns the salesOrders entity set with its lines and actions.
+func (c *Client) SalesOrders() *SalesOrders {
+	return &SalesOrders{APIPage: bc.NewAPIPage[SalesOrder](c.client, "salesOrders")}
+}
+
+// SalesInvoices returns the salesInvoices entity set with its lines and actions.
+func (c *Client) SalesInvoices() *SalesInvoices {
+	return &SalesInvoices{APIPage: bc.NewAPIPage[SalesInvoice](c.client, "salesInvoices")}
+}
+
+// SalesQuotes returns the salesQuotes entity set with its lines and actions.
+func (c *Client) SalesQuotes() *SalesQuotes {
+	return &SalesQuotes{APIPage: bc.NewAPIPage[SalesQuote](c.client, "salesQuotes")}
+}
+
+// SalesShipments returns the salesShipments entity set.
+func (c *Client) SalesShipments() *bc.APIPage[SalesShipment] {
+	return bc.NewAPIPage[SalesShipment](c.client, "salesShipments")
+}
+
+// Accounts returns the accounts entity set, the G/L accounts.
+func (c *Client) Accounts() *bc.APIPage[Account] {
+	return bc.NewAPIPage[Account](c.client, "accounts")
+}
+
+// GeneralLedgerEntries returns the generalLedgerEntries entity set.
+func (c *Client) GeneralLedgerEntries() *bc.APIPage[GeneralLedgerEntry] {
+	return bc.NewAPIPage[GeneralLedgerEntry](c.client, "generalLedgerEntries")
+}
+
+// FixedAssets returns the fixedAssets entity set.
+func (c *Client) FixedAssets() *bc.APIPage[FixedAsset] {
+	return bc.NewAPIPage[FixedAsset](c.client, "fixedAssets")
+}
+
+// GeneralProductPostingGroups returns the generalProductPostingGroups entity set.
+func (c *Client) GeneralProductPostingGroups() *bc.APIPage[GeneralProductPostingGroup] {
+	return bc.NewAPIPage[GeneralProductPostingGroup](c.client, "generalProductPostingGroups")
+}
+
+// GeneralBusinessPostingGroups returns the generalBusinessPostingGroups entity set.
+func (c *Client) GeneralBusinessPostingGroups() *bc.APIPage[GeneralBusinessPostingGroup] {
+	return bc.NewAPIPage[GeneralBusinessPostingGroup](c.client, "generalBusinessPostingGroups")
+}
+
+// InventoryPostingGroups returns the inventoryPostingGroups entity set.
+func (c *Client) InventoryPostingGroups() *bc.APIPage[InventoryPostingGroup] {
+	return bc.NewAPIPage[InventoryPostingGroup](c.client, "inventoryPostingGroups")
+}
+
+// BankAccounts returns the bankAccounts entity set.
+func (c *Client) BankAccounts() *bc.APIPage[BankAccount] {
+	return bc.NewAPIPage[BankAccount](c.client, "bankAccounts")
+}
+
+// CustomerPaymentJournals returns the customerPaymentJournals entity set with their payments.
+func (c *Client) CustomerPaymentJournals() *CustomerPaymentJournals {
+	return &CustomerPaymentJournals{APIPage: bc.NewAPIPage[CustomerPaymentJournal](c.client, "customerPaymentJournals")}
+}
+
+// VendorPaymentJournals returns the vendorPaymentJournals entity set with their payments.
+func (c *Client) VendorPaymentJournals() *VendorPaymentJournals {
+	return &VendorPaymentJournals{APIPage: bc.NewAPIPage[VendorPaymentJournal](c.client, "vendorPaymentJournals")}
+}
+
+// DocumentAttachments returns the documentAttachments entity set.
+func (c *Client) DocumentAttachments() *bc.APIPage[DocumentAttachment] {
+	return bc.NewAPIPage[DocumentAttachment](c.client, "documentAttachments")
+}
+
+// navigation returns an APIPage for the navigation property of a record of the page.
+func navigation[T bc.Validator, P bc.Validator](parent *bc.APIPage[P], id string, name string) *bc.APIPage[T] {
+	return bc.NewAPIPage[T](parent.Client(), parent.EntitySetName()+"("+id+")/"+name)
+}
@@ -0,0 +1,138 @@
+package models_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/erlorenz/bc-go/internal/bctest"
+	"github.com/erlorenz/bc-go/models"
+	"github.com/google/uuid"
+)
+
+func TestCustomersList(t *testing.T) {
+	body := map[string]any{"value": []map[string]any{
+		{"id": uuid.NewString(), "number": "10000", "displayName": "Adatum", "lastModifiedDateTime": "2024-02-20T00:12:20.273Z"},
+	}}
+	st := &bctest.SequenceTransport{Responses: []*http.Response{bctest.NewResponse(200, body)}}
+	api := models.NewClient(bctest.NewClient(t, st))
+
+	customers, err := api.Customers().List(context.Background(), bc.ListOptions{Top: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(customers) != 1 || customers[0].DisplayName != "Adatum" {
+		t.Errorf("unexpected customers %+v", customers)
+	}
+	if !strings.HasSuffix(st.Requests[0].URL.Path, "/customers") {
+		t.Errorf("unexpected path %s", st.Requests[0].URL.Path)
+	}
+}
+
+func TestSalesOrderLinesCreate(t *testing.T) {
+	orderID := uuid.New()
+	body := map[string]any{"id": uuid.NewString(), "documentId": orderID, "quantity": 2, "shipmentDate": "2024-02-20"}
+	st := &bctest.SequenceTransport{Responses: []*http.Response{bctest.NewResponse(201, body)}}
+	api := models.NewClient(bctest.NewClient(t, st))
+
+	line, err := api.SalesOrders().Lines(orderID).Create(context.Background(), map[string]any{"quantity": 2}, bc.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if line.DocumentID != orderID || line.Quantity != 2 || line.ShipmentDate.Day != 20 {
+		t.Errorf("unexpected line %+v", line)
+	}
+
+	want := "/salesOrders(" + orderID.String() + ")/salesOrderLines"
+	if !strings.HasSuffix(st.Requests[0].URL.Path, want) {
+		t.Errorf("wanted path ending %s, got %s", want, st.Requests[0].URL.Path)
+	}
+}
+
+func TestSalesOrdersShipAndInvoice(t *testing.T) {
+	st := &bctest.SequenceTransport{Responses: []*http.Response{bctest.NewResponse(204, nil)}}
+	api := models.NewClient(bctest.NewClient(t, st))
+
+	id := uuid.New()
+	if err := api.SalesOrders().ShipAndInvoice(context.Background(), id); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "/salesOrders(" + id.String() + ")/Microsoft.NAV.shipAndInvoice"
+	if !strings.HasSuffix(st.Requests[0].URL.Path, want) {
+		t.Errorf("wanted path ending %s, got %s", want, st.Requests[0].URL.Path)
+	}
+}
+
+func TestSendActions(t *testing.T) {
+	id := uuid.New()
+	table := []struct {
+		name string
+		send func(api *models.Client) error
+		want string
+	}{
+		{"InvoiceSend", func(api *models.Client) error { return api.SalesInvoices().Send(context.Background(), id) }, "/salesInvoices(" + id.String() + ")/Microsoft.NAV.send"},
+		{"InvoicePostAndSend", func(api *models.Client) error { return api.SalesInvoices().PostAndSend(context.Background(), id) }, "/salesInvoices(" + id.String() + ")/Microsoft.NAV.postAndSend"},
+		{"InvoiceCancelAndSend", func(api *models.Client) error { return api.SalesInvoices().CancelAndSend(context.Background(), id) }, "/salesInvoices(" + id.String() + ")/Microsoft.NAV.cancelAndSend"},
+		{"QuoteSend", func(api *models.Client) error { return api.SalesQuotes().Send(context.Background(), id) }, "/salesQuotes(" + id.String() + ")/Microsoft.NAV.send"},
+	}
+
+	for _, v := range table {
+		t.Run(v.name, func(t *testing.T) {
+			st := &bctest.SequenceTransport{Responses: []*http.Response{bctest.NewResponse(204, nil)}}
+			if err := v.send(models.NewClient(bctest.NewClient(t, st))); err != nil {
+				t.Fatal(err)
+			}
+			if !strings.HasSuffix(st.Requests[0].URL.Path, v.want) || st.Requests[0].Method != http.MethodPost {
+				t.Errorf("wanted POST %s, got %s %s", v.want, st.Requests[0].Method, st.Requests[0].URL.Path)
+			}
+		})
+	}
+}
+
+func TestSalesOrderLinesCreateGolden(t *testing.T) {
+	body := map[string]any{
+		"@odata.etag": `W/"JzQ0OzE="`, "id": uuid.NewString(), "documentId": uuid.NewString(), "sequence": 10000,
+		"lineType": "Item", "lineObjectNumber": "1896-S", "description": "ATHENS Desk", "quantity": 2,
+		"unitPrice": 1000.8, "shipmentDate": "2024-02-20",
+	}
+	st := &bctest.SequenceTransport{Responses: []*http.Response{bctest.NewResponse(201, body)}}
+	api := models.NewClient(bctest.NewClient(t, st))
+
+	line, err := api.SalesOrders().Lines(uuid.New()).Create(context.Background(),
+		map[string]any{"lineType": "Item", "lineObjectNumber": "1896-S", "quantity": 2}, bc.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bctest.AssertGoldenRequest(t, "testdata/salesorderline_create.request.json", st.Requests[0], bctest.RedactUUIDs())
+	bctest.AssertGolden(t, "testdata/salesorderline.json", line, bctest.RedactUUIDs(), bctest.Redact("@odata.etag"))
+}
+
+func TestEntitySetRegistry(t *testing.T) {
+	for _, name := range []string{
+		entitySetName[models.Customer](t),
+		entitySetName[models.SalesOrderLine](t),
+		entitySetName[models.GeneralLedgerEntry](t),
+	} {
+		if name == "" {
+			t.Error("wanted the models to be registered")
+		}
+	}
+	if got := entitySetName[models.SalesOrder](t); got != models.EntitySetSalesOrders {
+		t.Errorf("wanted %s, got %s", models.EntitySetSalesOrders, got)
+	}
+}
+
+func entitySetName[T bc.Validator](t *testing.T) string {
+	t.Helper()
+	info, ok := bc.EntitySetOf[T]()
+	if !ok || info.Key != "id" {
+		t.Errorf("unexpected entity set of %T: %+v", *new(T), info)
+	}
+	return info.Name
+}
@@ -0,0 +1,213 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/google/uuid"
+)
+
+// CustomerPaymentJournal is the customerPaymentJournals entity.
+type CustomerPaymentJournal struct {
+	ETag                   string    `json:"@odata.etag,omitempty"`
+	ID                     uuid.UUID `json:"id"`
+	Code                   string    `json:"code"`
+	DisplayName            string    `json:"displayName"`
+	BalancingAccountID     uuid.UUID `json:"balancingAccountId"`
+	BalancingAccountNumber string    `json:"balancingAccountNumber"`
+	LastModifiedDateTime   time.Time `json:"lastModifiedDateTime"`
+}
+
+// Validate implements the [bc.Validator] interface.
+func (j CustomerPaymentJournal) Validate() error {
+	return bc.ValidateStruct(j)
+}
+
+// CustomerPayment is the customerPayments entity, a line of a customer payment journal.
+type CustomerPayment struct {
+	ETag                   string    `json:"@odata.etag,omitempty"`
+	ID                     uuid.UUID `json:"id"`
+	JournalID              uuid.UUID `json:"journalId"`
+	JournalDisplayName     string    `json:"journalDisplayName"`
+	LineNumber             int       `json:"lineNumber"`
+	CustomerID             uuid.UUID `json:"customerId"`
+	CustomerNumber         string    `json:"customerNumber"`
+	ContactID              string    `json:"contactId"`
+	PostingDate            bc.Date   `json:"postingDate"`
+	DocumentNumber         string    `json:"documentNumber"`
+	ExternalDocumentNumber string    `json:"externalDocumentNumber"`
+	Amount                 float64   `json:"amount"`
+	AppliesToInvoiceID     uuid.UUID `json:"appliesToInvoiceId"`
+	AppliesToInvoiceNumber string    `json:"appliesToInvoiceNumber"`
+	Description            string    `json:"description"`
+	Comment                string    `json:"comment"`
+	LastModifiedDateTime   time.Time `json:"lastModifiedDateTime"`
+}
+
+// Validate implements the [bc.Validator] interface.
+func (p CustomerPayment) Validate() error {
+	return bc.ValidateStruct(p)
+}
+
+// VendorPaymentJournal is the vendorPaymentJournals entity.
+type VendorPaymentJournal struct {
+	ETag                   string    `json:"@odata.etag,omitempty"`
+	ID                     uuid.UUID `json:"id"`
+	Code                   string    `json:"code"`
+	DisplayName            string    `json:"displayName"`
+	BalancingAccountID     uuid.UUID `json:"balancingAccountId"`
+	BalancingAccountNumber string    `json:"balancingAccountNumber"`
+	LastModifiedDateTime   time.Time `json:"lastModifiedDateTime"`
+}
+
+// Validate implements the [bc.Validator] interface.
+func (j VendorPaymentJournal) Validate() error {
+	return bc.ValidateStruct(j)
+}
+
+// VendorPayment is the vendorPayments entity, a line of a vendor payment journal.
+type VendorPayment struct {
+	ETag                   string    `json:"@odata.etag,omitempty"`
+	ID                     uuid.UUID `json:"id"`
+	JournalID              uuid.UUID `json:"journalId"`
+	JournalDisplayName     string    `json:"journalDisplayName"`
+	LineNumber             int       `json:"lineNumber"`
+	VendorID               uuid.UUID `json:"vendorId"`
+	VendorNumber           string    `json:"vendorNumber"`
+	PostingDate            bc.Date   `json:"postingDate"`
+	DocumentNumber         string    `json:"documentNumber"`
+	ExternalDocumentNumber string    `json:"externalDocumentNumber"`
+	Amount                 float64   `json:"amount"`
+	AppliesToInvoiceID     uuid.UUID `json:"appliesToInvoiceId"`
+	AppliesToInvoiceNumber string    `json:"appliesToInvoiceNumber"`
+	Description            string    `json:"description"`
+	Comment                string    `json:"comment"`
+	LastModifiedDateTime   time.Time `json:"lastModifiedDateTime"`
+}
+
+// Validate implements the [bc.Validator] interface.
+func (p VendorPayment) Validate() error {
+	return bc.ValidateStruct(p)
+}
+
+// CustomerPaymentJournals is the customerPaymentJournals entity set.
+type CustomerPaymentJournals struct {
+	*bc.APIPage[CustomerPaymentJournal]
+}
+
+// Payments returns the customerPayments of the journal.
+func (j *CustomerPaymentJournals) Payments(journalID uuid.UUID) *bc.APIPage[CustomerPayment] {
+	return navigation[CustomerPayment](j.APIPage, journalID.String(), "customerPayments")
+}
+
+// VendorPaymentJournals is the vendorPaymentJournals entity set.
+type VendorPaymentJournals struct {
+	*bc.APIPage[VendorPaymentJournal]
+}
+
+// Payments returns the vendorPayments of the journal.
+func (j *VendorPaymentJournals) Payments(journalID uuid.UUID) *bc.APIPage[VendorPayment] {
+	return navigation[VendorPayment](j.APIPage, journalID.String(), "vendorPayments")
+}
+
+// PendingPayment is a payment to add to a payment journal, e.g. one that a payments hub
+// sent to a vendor or received from a customer.
+type PendingPayment struct {
+	// PartyNumber is the customer or vendor number. Required.
+	PartyNumber string
+	// PostingDate defaults to the work date of BC.
+	PostingDate bc.Date
+	// DocumentNumber defaults to the number series of the journal.
+	DocumentNumber string
+	// ExternalDocumentNumber is the reference of the payment in the hub.
+	ExternalDocumentNumber string
+	// Amount is the paid amount, a positive number. The lines get the sign of the journal.
+	Amount float64
+	// AppliesToInvoiceNumber is the posted invoice that the payment settles.
+	AppliesToInvoiceNumber string
+	Description            string
+}
+
+// CustomerPaymentLines returns the bodies that create the pending payments in a customer
+// payment journal. The amounts are negative, as a payment credits the customer.
+func CustomerPaymentLines(pending []PendingPayment) ([]map[string]any, error) {
+	return paymentLines(pending, "customerNumber", -1)
+}
+
+// VendorPaymentLines returns the bodies that create the pending payments in a vendor
+// payment journal. The amounts are positive, as a payment debits the vendor.
+func VendorPaymentLines(pending []PendingPayment) ([]map[string]any, error) {
+	return paymentLines(pending, "vendorNumber", 1)
+}
+
+func paymentLines(pending []PendingPayment, partyField string, sign float64) ([]map[string]any, error) {
+	lines := make([]map[string]any, 0, len(pending))
+	for i, p := range pending {
+		if p.PartyNumber == "" {
+			return nil, fmt.Errorf("pending payment %d: %s is required", i, partyField)
+		}
+		if p.Amount <= 0 {
+			return nil, fmt.Errorf("pending payment %d: amount %v is not positive", i, p.Amount)
+		}
+		line := map[string]any{partyField: p.PartyNumber, "amount": sign * p.Amount}
+		if !p.PostingDate.IsZero() {
+			line["postingDate"] = p.PostingDate
+		}
+		for field, v := range map[string]string{
+			"documentNumber":         p.DocumentNumber,
+			"externalDocumentNumber": p.ExternalDocumentNumber,
+			"appliesToInvoiceNumber": p.AppliesToInvoiceNumber,
+			"description":            p.Description,
+		} {
+			if v != "" {
+				line[field] = v
+			}
+		}
+		lines = append(lines, line)
+	}
+	return lines, nil
+}
+
+// AddPayments creates the pending payments in the journal in batches, each of them atomic.
+// It returns the created lines, which are fewer than the payments if it fails.
+func (j *CustomerPaymentJournals) AddPayments(ctx context.Context, journalID uuid.UUID, pending []PendingPayment) ([]CustomerPayment, error) {
+	lines, err := CustomerPaymentLines(pending)
+	if err != nil {
+		return nil, err
+	}
+	return addLines(ctx, j.Payments(journalID), lines)
+}
+
+// AddPayments creates the pending payments in the journal in batches, each of them atomic.
+// It returns the created lines, which are fewer than the payments if it fails.
+func (j *VendorPaymentJournals) AddPayments(ctx context.Context, journalID uuid.UUID, pending []PendingPayment) ([]VendorPayment, error) {
+	lines, err := VendorPaymentLines(pending)
+	if err != nil {
+		return nil, err
+	}
+	return addLines(ctx, j.Payments(journalID), lines)
+}
+
+func addLines[T bc.Validator](ctx context.Context, page *bc.APIPage[T], lines []map[string]any) ([]T, error) {
+	requests := make([]bc.RequestOptions, 0, len(lines))
+	for _, l := range lines {
+		requests = append(requests, bc.RequestOptions{Method: http.MethodPost, EntitySetName: page.EntitySetName(), Body: l})
+	}
+	responses, err := page.Client().Bulk(ctx, requests, bc.BulkOptions{Atomic: true})
+
+	created := make([]T, 0, len(responses))
+	for i, res := range responses {
+		v, derr := bc.DecodeBatchResponse[T](res)
+		if derr != nil {
+			return created, fmt.Errorf("add payment %d to %s: %w", i, page.EntitySetName(), derr)
+		}
+		created = append(created, v)
+	}
+	if err != nil {
+		return created, fmt.Errorf("add payments to %s: %w", page.EntitySetName(), err)
+	}
+	return created, nil
+}
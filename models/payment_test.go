@@ -0,0 +1,76 @@
+package models_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/erlorenz/bc-go/internal/bctest"
+	"github.com/erlorenz/bc-go/models"
+	"github.com/google/uuid"
+)
+
+func TestPaymentLines(t *testing.T) {
+	pending := []models.PendingPayment{
+		{PartyNumber: "10000", Amount: 250, PostingDate: bc.Date{Year: 2026, Month: 3, Day: 2}, ExternalDocumentNumber: "HUB-881", AppliesToInvoiceNumber: "PS-INV103001"},
+		{PartyNumber: "20000", Amount: 12.5},
+	}
+
+	customer, err := models.CustomerPaymentLines(pending)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := json.Marshal(customer)
+	want := `[{"amount":-250,"appliesToInvoiceNumber":"PS-INV103001","customerNumber":"10000","externalDocumentNumber":"HUB-881","postingDate":"2026-03-02"},{"amount":-12.5,"customerNumber":"20000"}]`
+	if string(b) != want {
+		t.Errorf("wanted %s, got %s", want, b)
+	}
+
+	vendor, err := models.VendorPaymentLines(pending[1:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vendor[0]["vendorNumber"] != "20000" || vendor[0]["amount"] != 12.5 {
+		t.Errorf("unexpected vendor line %v", vendor[0])
+	}
+
+	if _, err := models.VendorPaymentLines([]models.PendingPayment{{PartyNumber: "20000", Amount: -1}}); err == nil {
+		t.Error("wanted an error for a negative amount")
+	}
+	if _, err := models.VendorPaymentLines([]models.PendingPayment{{Amount: 1}}); err == nil {
+		t.Error("wanted an error without a vendor number")
+	}
+}
+
+func TestVendorPaymentJournalsAddPayments(t *testing.T) {
+	journalID := uuid.New()
+	created, _ := json.Marshal(map[string]any{"id": uuid.New(), "journalId": journalID, "vendorNumber": "20000", "amount": 12.5})
+	st := &bctest.SequenceTransport{Responses: []*http.Response{
+		bctest.NewResponse(200, map[string]any{"responses": []map[string]any{
+			{"id": "0", "status": 201, "body": json.RawMessage(created)},
+			{"id": "1", "status": 400, "body": map[string]any{"error": map[string]any{"code": "Internal_RecordNotFound", "message": "The Vendor does not exist."}}},
+		}}),
+	}}
+	api := models.NewClient(bctest.NewClient(t, st))
+
+	payments, err := api.VendorPaymentJournals().AddPayments(context.Background(), journalID, []models.PendingPayment{
+		{PartyNumber: "20000", Amount: 12.5},
+		{PartyNumber: "99999", Amount: 1},
+	})
+	if err == nil || !strings.Contains(err.Error(), "The Vendor does not exist.") {
+		t.Errorf("wanted the error of the second line, got %v", err)
+	}
+	if len(payments) != 1 || payments[0].VendorNumber != "20000" {
+		t.Errorf("unexpected payments %+v", payments)
+	}
+
+	body, _ := io.ReadAll(st.Requests[0].Body)
+	want := "vendorPaymentJournals(" + journalID.String() + ")/vendorPayments"
+	if !strings.Contains(string(body), want) || !strings.Contains(string(body), `"atomicityGroup"`) {
+		t.Errorf("wanted an atomic batch of %s, got %s", want, body)
+	}
+}
@@ -0,0 +1,60 @@
+package models
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/erlorenz/bc-go/filter"
+	"github.com/google/uuid"
+)
+
+// PostedSalesOrder are the documents posted from a sales order.
+type PostedSalesOrder struct {
+	OrderNumber string
+	Invoices    []SalesInvoice
+	Shipments   []SalesShipment
+}
+
+// PostedFromOrder returns the posted invoices and shipments of the sales order with the
+// number. BC deletes a fully posted order and its documents get other ids in other
+// entity sets, so they are found by their orderNumber. Draft invoices are left out.
+func (c *Client) PostedFromOrder(ctx context.Context, orderNumber string) (PostedSalesOrder, error) {
+	posted := PostedSalesOrder{OrderNumber: orderNumber}
+	byOrder := "orderNumber eq " + filter.Literal(orderNumber)
+
+	invoices, err := c.SalesInvoices().List(ctx, bc.ListOptions{Filter: byOrder + " and status ne 'Draft'", OrderBy: []string{"number"}})
+	if err != nil {
+		return posted, fmt.Errorf("posted invoices of order %s: %w", orderNumber, err)
+	}
+	posted.Invoices = invoices
+
+	shipments, err := c.SalesShipments().List(ctx, bc.ListOptions{Filter: byOrder, OrderBy: []string{"number"}})
+	if err != nil {
+		return posted, fmt.Errorf("posted shipments of order %s: %w", orderNumber, err)
+	}
+	posted.Shipments = shipments
+	return posted, nil
+}
+
+// ShipAndInvoicePosted posts the order like ShipAndInvoice and returns the documents it
+// created, see [Client.PostedFromOrder].
+func (so *SalesOrders) ShipAndInvoicePosted(ctx context.Context, id uuid.UUID) (PostedSalesOrder, error) {
+	order, err := so.Get(ctx, id, bc.GetOptions{Select: []string{"id", "number"}})
+	if err != nil {
+		return PostedSalesOrder{}, err
+	}
+	if err := so.ShipAndInvoice(ctx, id); err != nil {
+		return PostedSalesOrder{OrderNumber: order.Number}, err
+	}
+	return NewClient(so.Client()).PostedFromOrder(ctx, order.Number)
+}
+
+// PostAndGet posts the draft invoice and returns the posted invoice, which keeps the
+// id of the draft.
+func (si *SalesInvoices) PostAndGet(ctx context.Context, id uuid.UUID) (SalesInvoice, error) {
+	if err := si.Post(ctx, id); err != nil {
+		return SalesInvoice{}, err
+	}
+	return si.Get(ctx, id, bc.GetOptions{})
+}
@@ -0,0 +1,70 @@
+package models_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/erlorenz/bc-go/internal/bctest"
+	"github.com/erlorenz/bc-go/models"
+	"github.com/google/uuid"
+)
+
+func TestSalesOrdersShipAndInvoicePosted(t *testing.T) {
+	id := uuid.New()
+	st := &bctest.SequenceTransport{Responses: []*http.Response{
+		bctest.NewResponse(200, map[string]any{"id": id, "number": "S-ORD101001"}),
+		bctest.NewResponse(204, nil),
+		bctest.NewResponse(200, map[string]any{"value": []map[string]any{{"id": uuid.NewString(), "number": "PS-INV103001", "orderNumber": "S-ORD101001", "status": "Open"}}}),
+		bctest.NewResponse(200, map[string]any{"value": []map[string]any{{"id": uuid.NewString(), "number": "S-SHPT102001", "orderNumber": "S-ORD101001"}}}),
+	}}
+	api := models.NewClient(bctest.NewClient(t, st))
+
+	posted, err := api.SalesOrders().ShipAndInvoicePosted(context.Background(), id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(posted.Invoices) != 1 || posted.Invoices[0].Number != "PS-INV103001" {
+		t.Errorf("unexpected invoices %+v", posted.Invoices)
+	}
+	if len(posted.Shipments) != 1 || posted.Shipments[0].Number != "S-SHPT102001" {
+		t.Errorf("unexpected shipments %+v", posted.Shipments)
+	}
+
+	table := []struct {
+		path   string
+		filter string
+	}{
+		{"/salesOrders(" + id.String() + ")", ""},
+		{"/salesOrders(" + id.String() + ")/Microsoft.NAV.shipAndInvoice", ""},
+		{"/salesInvoices", "orderNumber eq 'S-ORD101001' and status ne 'Draft'"},
+		{"/salesShipments", "orderNumber eq 'S-ORD101001'"},
+	}
+	for i, v := range table {
+		r := st.Requests[i]
+		if !strings.HasSuffix(r.URL.Path, v.path) || r.URL.Query().Get("$filter") != v.filter {
+			t.Errorf("request %d: wanted %s with filter %q, got %s", i, v.path, v.filter, r.URL)
+		}
+	}
+}
+
+func TestSalesInvoicesPostAndGet(t *testing.T) {
+	id := uuid.New()
+	st := &bctest.SequenceTransport{Responses: []*http.Response{
+		bctest.NewResponse(204, nil),
+		bctest.NewResponse(200, map[string]any{"id": id, "number": "PS-INV103002", "status": "Open"}),
+	}}
+	api := models.NewClient(bctest.NewClient(t, st))
+
+	invoice, err := api.SalesInvoices().PostAndGet(context.Background(), id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if invoice.Number != "PS-INV103002" || invoice.Status != "Open" {
+		t.Errorf("unexpected invoice %+v", invoice)
+	}
+	if want := "/salesInvoices(" + id.String() + ")"; !strings.HasSuffix(st.Requests[1].URL.Path, want) {
+		t.Errorf("wanted the posted invoice by the draft id, got %s", st.Requests[1].URL.Path)
+	}
+}
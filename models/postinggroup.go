@@ -0,0 +1,83 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/google/uuid"
+)
+
+// GeneralProductPostingGroup is the generalProductPostingGroups entity.
+type GeneralProductPostingGroup struct {
+	ETag                          string    `json:"@odata.etag,omitempty"`
+	ID                            uuid.UUID `json:"id"`
+	Code                          string    `json:"code"`
+	Description                   string    `json:"description"`
+	DefaultVATProductPostingGroup string    `json:"defaultVATProductPostingGroup"`
+	AutoInsertDefault             bool      `json:"autoInsertDefault"`
+	LastModifiedDateTime          time.Time `json:"lastModifiedDateTime"`
+}
+
+// Validate implements the [bc.Validator] interface.
+func (g GeneralProductPostingGroup) Validate() error {
+	return bc.ValidateStruct(g)
+}
+
+// GeneralBusinessPostingGroup is the generalBusinessPostingGroups entity.
+type GeneralBusinessPostingGroup struct {
+	ETag                           string    `json:"@odata.etag,omitempty"`
+	ID                             uuid.UUID `json:"id"`
+	Code                           string    `json:"code"`
+	Description                    string    `json:"description"`
+	DefaultVATBusinessPostingGroup string    `json:"defaultVATBusinessPostingGroup"`
+	AutoInsertDefault              bool      `json:"autoInsertDefault"`
+	LastModifiedDateTime           time.Time `json:"lastModifiedDateTime"`
+}
+
+// Validate implements the [bc.Validator] interface.
+func (g GeneralBusinessPostingGroup) Validate() error {
+	return bc.ValidateStruct(g)
+}
+
+// InventoryPostingGroup is the inventoryPostingGroups entity.
+type InventoryPostingGroup struct {
+	ETag                 string    `json:"@odata.etag,omitempty"`
+	ID                   uuid.UUID `json:"id"`
+	Code                 string    `json:"code"`
+	Description          string    `json:"description"`
+	LastModifiedDateTime time.Time `json:"lastModifiedDateTime"`
+}
+
+// Validate implements the [bc.Validator] interface.
+func (g InventoryPostingGroup) Validate() error {
+	return bc.ValidateStruct(g)
+}
+
+// Ref references a record of type T by the id and code fields of another entity, like
+// the inventoryPostingGroupId and inventoryPostingGroupCode of an item. The type keeps a
+// general product posting group from being passed where an inventory one is expected.
+type Ref[T bc.Validator] struct {
+	ID   uuid.UUID
+	Code string
+}
+
+// IsZero returns true if the reference is empty, e.g. an item without a posting group.
+func (r Ref[T]) IsZero() bool {
+	return r.ID == uuid.Nil && r.Code == ""
+}
+
+// Get returns the referenced record from the page.
+func (r Ref[T]) Get(ctx context.Context, page *bc.APIPage[T]) (T, error) {
+	return page.Get(ctx, r.ID, bc.GetOptions{})
+}
+
+// GeneralProductPostingGroup returns the reference to the general product posting group of the item.
+func (i Item) GeneralProductPostingGroup() Ref[GeneralProductPostingGroup] {
+	return Ref[GeneralProductPostingGroup]{ID: i.GeneralProductPostingGroupID, Code: i.GeneralProductPostingGroupCode}
+}
+
+// InventoryPostingGroup returns the reference to the inventory posting group of the item.
+func (i Item) InventoryPostingGroup() Ref[InventoryPostingGroup] {
+	return Ref[InventoryPostingGroup]{ID: i.InventoryPostingGroupID, Code: i.InventoryPostingGroupCode}
+}
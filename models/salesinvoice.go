@@ -0,0 +1,100 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/google/uuid"
+)
+
+// SalesInvoice is the salesInvoices entity.
+type SalesInvoice struct {
+	ETag                           string             `json:"@odata.etag,omitempty"`
+	ID                             uuid.UUID          `json:"id"`
+	Number                         string             `json:"number"`
+	ExternalDocumentNumber         string             `json:"externalDocumentNumber"`
+	InvoiceDate                    bc.Date            `json:"invoiceDate"`
+	PostingDate                    bc.Date            `json:"postingDate"`
+	DueDate                        bc.Date            `json:"dueDate"`
+	CustomerPurchaseOrderReference string             `json:"customerPurchaseOrderReference"`
+	CustomerID                     uuid.UUID          `json:"customerId"`
+	CustomerNumber                 string             `json:"customerNumber"`
+	CustomerName                   string             `json:"customerName"`
+	BillToName                     string             `json:"billToName"`
+	BillToCustomerID               uuid.UUID          `json:"billToCustomerId"`
+	BillToCustomerNumber           string             `json:"billToCustomerNumber"`
+	CurrencyID                     uuid.UUID          `json:"currencyId"`
+	CurrencyCode                   string             `json:"currencyCode"`
+	OrderID                        uuid.UUID          `json:"orderId"`
+	OrderNumber                    string             `json:"orderNumber"`
+	PricesIncludeTax               bool               `json:"pricesIncludeTax"`
+	Salesperson                    string             `json:"salesperson"`
+	DiscountAmount                 float64            `json:"discountAmount"`
+	TotalAmountExcludingTax        float64            `json:"totalAmountExcludingTax"`
+	TotalTaxAmount                 float64            `json:"totalTaxAmount"`
+	TotalAmountIncludingTax        float64            `json:"totalAmountIncludingTax"`
+	RemainingAmount                float64            `json:"remainingAmount"`
+	Status                         string             `json:"status"`
+	PhoneNumber                    string             `json:"phoneNumber"`
+	Email                          string             `json:"email"`
+	LastModifiedDateTime           time.Time          `json:"lastModifiedDateTime"`
+	SalesInvoiceLines              []SalesInvoiceLine `json:"salesInvoiceLines,omitempty"`
+}
+
+// Validate implements the [bc.Validator] interface.
+func (si SalesInvoice) Validate() error {
+	return bc.ValidateStruct(si)
+}
+
+// SalesInvoiceLine is the salesInvoiceLines entity.
+type SalesInvoiceLine struct {
+	SalesLineFields
+	ShipmentDate bc.Date `json:"shipmentDate"`
+}
+
+// Validate implements the [bc.Validator] interface.
+func (sil SalesInvoiceLine) Validate() error {
+	return bc.ValidateStruct(sil)
+}
+
+// SalesInvoices is the salesInvoices entity set.
+type SalesInvoices struct {
+	*bc.APIPage[SalesInvoice]
+}
+
+// Lines returns the salesInvoiceLines of the invoice.
+func (si *SalesInvoices) Lines(invoiceID uuid.UUID) *bc.APIPage[SalesInvoiceLine] {
+	return navigation[SalesInvoiceLine](si.APIPage, invoiceID.String(), "salesInvoiceLines")
+}
+
+// Post posts the draft invoice.
+func (si *SalesInvoices) Post(ctx context.Context, id uuid.UUID) error {
+	return si.InvokeAction(ctx, id, "post", nil)
+}
+
+// PostAndSend posts the draft invoice and emails it to the customer, see [SalesInvoices.Send].
+func (si *SalesInvoices) PostAndSend(ctx context.Context, id uuid.UUID) error {
+	return si.InvokeAction(ctx, id, "postAndSend", nil)
+}
+
+// Send emails the posted invoice to the email of the customer with the document sending
+// profile and email layout set up in BC. The action has no parameters.
+func (si *SalesInvoices) Send(ctx context.Context, id uuid.UUID) error {
+	return si.InvokeAction(ctx, id, "send", nil)
+}
+
+// Cancel cancels the posted invoice.
+func (si *SalesInvoices) Cancel(ctx context.Context, id uuid.UUID) error {
+	return si.InvokeAction(ctx, id, "cancel", nil)
+}
+
+// CancelAndSend cancels the posted invoice and emails the cancellation to the customer.
+func (si *SalesInvoices) CancelAndSend(ctx context.Context, id uuid.UUID) error {
+	return si.InvokeAction(ctx, id, "cancelAndSend", nil)
+}
+
+// MakeCorrectiveCreditMemo creates a corrective credit memo for the posted invoice.
+func (si *SalesInvoices) MakeCorrectiveCreditMemo(ctx context.Context, id uuid.UUID) error {
+	return si.InvokeAction(ctx, id, "makeCorrectiveCreditMemo", nil)
+}
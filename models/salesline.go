@@ -0,0 +1,37 @@
+package models
+
+import (
+	"github.com/google/uuid"
+)
+
+// SalesLineFields are the fields shared by salesOrderLines, salesInvoiceLines and salesQuoteLines.
+type SalesLineFields struct {
+	ETag                      string    `json:"@odata.etag,omitempty"`
+	ID                        uuid.UUID `json:"id"`
+	DocumentID                uuid.UUID `json:"documentId"`
+	Sequence                  int       `json:"sequence"`
+	ItemID                    uuid.UUID `json:"itemId"`
+	AccountID                 uuid.UUID `json:"accountId"`
+	LineType                  string    `json:"lineType"`
+	LineObjectNumber          string    `json:"lineObjectNumber"`
+	Description               string    `json:"description"`
+	Description2              string    `json:"description2"`
+	UnitOfMeasureID           uuid.UUID `json:"unitOfMeasureId"`
+	UnitOfMeasureCode         string    `json:"unitOfMeasureCode"`
+	Quantity                  float64   `json:"quantity"`
+	UnitPrice                 float64   `json:"unitPrice"`
+	DiscountAmount            float64   `json:"discountAmount"`
+	DiscountPercent           float64   `json:"discountPercent"`
+	DiscountAppliedBeforeTax  bool      `json:"discountAppliedBeforeTax"`
+	AmountExcludingTax        float64   `json:"amountExcludingTax"`
+	TaxCode                   string    `json:"taxCode"`
+	TaxPercent                float64   `json:"taxPercent"`
+	TotalTaxAmount            float64   `json:"totalTaxAmount"`
+	AmountIncludingTax        float64   `json:"amountIncludingTax"`
+	InvoiceDiscountAllocation float64   `json:"invoiceDiscountAllocation"`
+	NetAmount                 float64   `json:"netAmount"`
+	NetTaxAmount              float64   `json:"netTaxAmount"`
+	NetAmountIncludingTax     float64   `json:"netAmountIncludingTax"`
+	ItemVariantID             uuid.UUID `json:"itemVariantId"`
+	LocationID                uuid.UUID `json:"locationId"`
+}
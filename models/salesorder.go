@@ -0,0 +1,81 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/google/uuid"
+)
+
+// SalesOrder is the salesOrders entity.
+type SalesOrder struct {
+	ETag                     string           `json:"@odata.etag,omitempty"`
+	ID                       uuid.UUID        `json:"id"`
+	Number                   string           `json:"number"`
+	ExternalDocumentNumber   string           `json:"externalDocumentNumber"`
+	OrderDate                bc.Date          `json:"orderDate"`
+	PostingDate              bc.Date          `json:"postingDate"`
+	CustomerID               uuid.UUID        `json:"customerId"`
+	CustomerNumber           string           `json:"customerNumber"`
+	CustomerName             string           `json:"customerName"`
+	BillToName               string           `json:"billToName"`
+	BillToCustomerID         uuid.UUID        `json:"billToCustomerId"`
+	BillToCustomerNumber     string           `json:"billToCustomerNumber"`
+	ShipToName               string           `json:"shipToName"`
+	ShipToContact            string           `json:"shipToContact"`
+	CurrencyID               uuid.UUID        `json:"currencyId"`
+	CurrencyCode             string           `json:"currencyCode"`
+	PricesIncludeTax         bool             `json:"pricesIncludeTax"`
+	PaymentTermsID           uuid.UUID        `json:"paymentTermsId"`
+	ShipmentMethodID         uuid.UUID        `json:"shipmentMethodId"`
+	Salesperson              string           `json:"salesperson"`
+	PartialShipping          bool             `json:"partialShipping"`
+	RequestedDeliveryDate    bc.Date          `json:"requestedDeliveryDate"`
+	DiscountAmount           float64          `json:"discountAmount"`
+	DiscountAppliedBeforeTax bool             `json:"discountAppliedBeforeTax"`
+	TotalAmountExcludingTax  float64          `json:"totalAmountExcludingTax"`
+	TotalTaxAmount           float64          `json:"totalTaxAmount"`
+	TotalAmountIncludingTax  float64          `json:"totalAmountIncludingTax"`
+	FullyShipped             bool             `json:"fullyShipped"`
+	Status                   string           `json:"status"`
+	PhoneNumber              string           `json:"phoneNumber"`
+	Email                    string           `json:"email"`
+	LastModifiedDateTime     time.Time        `json:"lastModifiedDateTime"`
+	SalesOrderLines          []SalesOrderLine `json:"salesOrderLines,omitempty"`
+}
+
+// Validate implements the [bc.Validator] interface.
+func (so SalesOrder) Validate() error {
+	return bc.ValidateStruct(so)
+}
+
+// SalesOrderLine is the salesOrderLines entity.
+type SalesOrderLine struct {
+	SalesLineFields
+	ShipmentDate     bc.Date `json:"shipmentDate"`
+	ShippedQuantity  float64 `json:"shippedQuantity"`
+	InvoicedQuantity float64 `json:"invoicedQuantity"`
+	InvoiceQuantity  float64 `json:"invoiceQuantity"`
+	ShipQuantity     float64 `json:"shipQuantity"`
+}
+
+// Validate implements the [bc.Validator] interface.
+func (sol SalesOrderLine) Validate() error {
+	return bc.ValidateStruct(sol)
+}
+
+// SalesOrders is the salesOrders entity set.
+type SalesOrders struct {
+	*bc.APIPage[SalesOrder]
+}
+
+// Lines returns the salesOrderLines of the order.
+func (so *SalesOrders) Lines(orderID uuid.UUID) *bc.APIPage[SalesOrderLine] {
+	return navigation[SalesOrderLine](so.APIPage, orderID.String(), "salesOrderLines")
+}
+
+// ShipAndInvoice posts the order as shipped and invoiced.
+func (so *SalesOrders) ShipAndInvoice(ctx context.Context, id uuid.UUID) error {
+	return so.InvokeAction(ctx, id, "shipAndInvoice", nil)
+}
@@ -0,0 +1,79 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/google/uuid"
+)
+
+// SalesQuote is the salesQuotes entity.
+type SalesQuote struct {
+	ETag                    string           `json:"@odata.etag,omitempty"`
+	ID                      uuid.UUID        `json:"id"`
+	Number                  string           `json:"number"`
+	ExternalDocumentNumber  string           `json:"externalDocumentNumber"`
+	DocumentDate            bc.Date          `json:"documentDate"`
+	PostingDate             bc.Date          `json:"postingDate"`
+	DueDate                 bc.Date          `json:"dueDate"`
+	CustomerID              uuid.UUID        `json:"customerId"`
+	CustomerNumber          string           `json:"customerNumber"`
+	CustomerName            string           `json:"customerName"`
+	CurrencyID              uuid.UUID        `json:"currencyId"`
+	CurrencyCode            string           `json:"currencyCode"`
+	Salesperson             string           `json:"salesperson"`
+	DiscountAmount          float64          `json:"discountAmount"`
+	TotalAmountExcludingTax float64          `json:"totalAmountExcludingTax"`
+	TotalTaxAmount          float64          `json:"totalTaxAmount"`
+	TotalAmountIncludingTax float64          `json:"totalAmountIncludingTax"`
+	Status                  string           `json:"status"`
+	SentDate                time.Time        `json:"sentDate"`
+	ValidUntilDate          bc.Date          `json:"validUntilDate"`
+	AcceptedDate            bc.Date          `json:"acceptedDate"`
+	PhoneNumber             string           `json:"phoneNumber"`
+	Email                   string           `json:"email"`
+	LastModifiedDateTime    time.Time        `json:"lastModifiedDateTime"`
+	SalesQuoteLines         []SalesQuoteLine `json:"salesQuoteLines,omitempty"`
+}
+
+// Validate implements the [bc.Validator] interface.
+func (sq SalesQuote) Validate() error {
+	return bc.ValidateStruct(sq)
+}
+
+// SalesQuoteLine is the salesQuoteLines entity.
+type SalesQuoteLine struct {
+	SalesLineFields
+}
+
+// Validate implements the [bc.Validator] interface.
+func (sql SalesQuoteLine) Validate() error {
+	return bc.ValidateStruct(sql)
+}
+
+// SalesQuotes is the salesQuotes entity set.
+type SalesQuotes struct {
+	*bc.APIPage[SalesQuote]
+}
+
+// Lines returns the salesQuoteLines of the quote.
+func (sq *SalesQuotes) Lines(quoteID uuid.UUID) *bc.APIPage[SalesQuoteLine] {
+	return navigation[SalesQuoteLine](sq.APIPage, quoteID.String(), "salesQuoteLines")
+}
+
+// MakeOrder converts the quote to a sales order.
+func (sq *SalesQuotes) MakeOrder(ctx context.Context, id uuid.UUID) error {
+	return sq.InvokeAction(ctx, id, "makeOrder", nil)
+}
+
+// MakeInvoice converts the quote to a sales invoice.
+func (sq *SalesQuotes) MakeInvoice(ctx context.Context, id uuid.UUID) error {
+	return sq.InvokeAction(ctx, id, "makeInvoice", nil)
+}
+
+// Send emails the quote to the email of the customer with the document sending
+// profile and email layout set up in BC. The action has no parameters.
+func (sq *SalesQuotes) Send(ctx context.Context, id uuid.UUID) error {
+	return sq.InvokeAction(ctx, id, "send", nil)
+}
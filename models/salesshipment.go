@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/google/uuid"
+)
+
+// SalesShipment is the salesShipments entity, a posted shipment of a sales order.
+type SalesShipment struct {
+	ETag                           string    `json:"@odata.etag,omitempty"`
+	ID                             uuid.UUID `json:"id"`
+	Number                         string    `json:"number"`
+	ExternalDocumentNumber         string    `json:"externalDocumentNumber"`
+	InvoiceDate                    bc.Date   `json:"invoiceDate"`
+	PostingDate                    bc.Date   `json:"postingDate"`
+	DueDate                        bc.Date   `json:"dueDate"`
+	CustomerPurchaseOrderReference string    `json:"customerPurchaseOrderReference"`
+	CustomerNumber                 string    `json:"customerNumber"`
+	CustomerName                   string    `json:"customerName"`
+	BillToName                     string    `json:"billToName"`
+	BillToCustomerNumber           string    `json:"billToCustomerNumber"`
+	ShipToName                     string    `json:"shipToName"`
+	ShipToContact                  string    `json:"shipToContact"`
+	CurrencyCode                   string    `json:"currencyCode"`
+	OrderNumber                    string    `json:"orderNumber"`
+	PaymentTermsCode               string    `json:"paymentTermsCode"`
+	ShipmentMethodCode             string    `json:"shipmentMethodCode"`
+	Salesperson                    string    `json:"salesperson"`
+	PricesIncludeTax               bool      `json:"pricesIncludeTax"`
+	PhoneNumber                    string    `json:"phoneNumber"`
+	Email                          string    `json:"email"`
+	LastModifiedDateTime           time.Time `json:"lastModifiedDateTime"`
+}
+
+// Validate implements the [bc.Validator] interface.
+func (ss SalesShipment) Validate() error {
+	return bc.ValidateStruct(ss)
+}
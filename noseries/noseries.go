@@ -0,0 +1,246 @@
+// Package noseries reads the number series of BC and peeks or reserves the next number,
+// for integrations that pre-print documents and need their number before posting.
+//
+// The standard API v2.0 has no number series, so the entity sets come from an extension
+// API with pages on the "No. Series" and "No. Series Line" tables. Reserving needs a
+// bound action on the series page that calls the No. Series codeunit and returns the
+// number, e.g.
+//
+//	[ServiceEnabled]
+//	procedure reserveNextNo(): Code[20]
+//	var
+//		NoSeries: Codeunit "No. Series";
+//	begin
+//		exit(NoSeries.GetNextNo(Rec.Code));
+//	end;
+//
+// Peeking only reads the series, so the number can be taken by someone else before it is used.
+package noseries
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/erlorenz/bc-go/filter"
+	"github.com/google/uuid"
+)
+
+// ErrNoOpenLine is returned when the series has no open line that starts on or before the date.
+var ErrNoOpenLine = errors.New("number series has no open line")
+
+// ErrExhausted is returned when the next number is after the ending number of the line.
+var ErrExhausted = errors.New("number series is exhausted")
+
+// Series is a record of the "No. Series" table.
+type Series struct {
+	ID          uuid.UUID `json:"id"`
+	Code        string    `json:"code"`
+	Description string    `json:"description"`
+	DefaultNos  bool      `json:"defaultNos"`
+	ManualNos   bool      `json:"manualNos"`
+	DateOrder   bool      `json:"dateOrder"`
+}
+
+// Validate implements the [bc.Validator] interface.
+func (s Series) Validate() error {
+	return bc.ValidateStruct(s)
+}
+
+// Line is a record of the "No. Series Line" table.
+type Line struct {
+	ID            uuid.UUID `json:"id"`
+	SeriesCode    string    `json:"seriesCode"`
+	LineNo        int       `json:"lineNo"`
+	StartingDate  bc.Date   `json:"startingDate"`
+	StartingNo    string    `json:"startingNo"`
+	EndingNo      string    `json:"endingNo"`
+	WarningNo     string    `json:"warningNo"`
+	LastNoUsed    string    `json:"lastNoUsed"`
+	LastDateUsed  bc.Date   `json:"lastDateUsed"`
+	IncrementByNo int       `json:"incrementByNo"`
+	Open          bool      `json:"open"`
+}
+
+// Validate implements the [bc.Validator] interface.
+func (l Line) Validate() error {
+	return bc.ValidateStruct(l)
+}
+
+// Next returns the number after the LastNoUsed, or the StartingNo if none was used.
+// It returns an error matching [ErrExhausted] after the EndingNo.
+func (l Line) Next() (string, error) {
+	next := l.StartingNo
+	if l.LastNoUsed != "" {
+		var err error
+		next, err = Increment(l.LastNoUsed, max(l.IncrementByNo, 1))
+		if err != nil {
+			return "", err
+		}
+	}
+	if l.EndingNo != "" && compareNo(next, l.EndingNo) > 0 {
+		return "", fmt.Errorf("%w: %s is after the ending number %s", ErrExhausted, next, l.EndingNo)
+	}
+	return next, nil
+}
+
+// Options configure [New].
+type Options struct {
+	// SeriesEntitySetName defaults to "noSeries".
+	SeriesEntitySetName string
+	// LinesEntitySetName defaults to "noSeriesLines".
+	LinesEntitySetName string
+	// ReserveAction is the bound action of the series page that returns the next number.
+	// Reserve returns an error without it.
+	ReserveAction string
+}
+
+// Client reads and reserves numbers of the series of an extension API.
+type Client struct {
+	client *bc.Client
+	opts   Options
+}
+
+// New creates a Client. The bc.Client must use the extension API with the series pages.
+func New(client *bc.Client, opts Options) *Client {
+	opts.SeriesEntitySetName = cmp.Or(opts.SeriesEntitySetName, "noSeries")
+	opts.LinesEntitySetName = cmp.Or(opts.LinesEntitySetName, "noSeriesLines")
+	return &Client{client: client, opts: opts}
+}
+
+// Series returns the series page.
+func (c *Client) Series() *bc.APIPage[Series] {
+	return bc.NewAPIPage[Series](c.client, c.opts.SeriesEntitySetName)
+}
+
+// Lines returns the series lines page.
+func (c *Client) Lines() *bc.APIPage[Line] {
+	return bc.NewAPIPage[Line](c.client, c.opts.LinesEntitySetName)
+}
+
+// Get returns the series with the code.
+func (c *Client) Get(ctx context.Context, code string) (Series, error) {
+	records, err := c.Series().List(ctx, bc.ListOptions{Filter: "code eq " + filter.Literal(code), Top: 1})
+	if err != nil {
+		return Series{}, fmt.Errorf("get number series %s: %w", code, err)
+	}
+	if len(records) == 0 {
+		return Series{}, fmt.Errorf("get number series %s: %w", code, bc.ErrNotFound)
+	}
+	return records[0], nil
+}
+
+// Line returns the open line of the series that applies on the date, the one with the
+// latest StartingDate on or before it, like BC picks the line when it assigns a number.
+func (c *Client) Line(ctx context.Context, code string, date time.Time) (Line, error) {
+	lines, err := c.Lines().List(ctx, bc.ListOptions{
+		Filter:  "seriesCode eq " + filter.Literal(code) + " and open eq true",
+		OrderBy: []string{"lineNo"},
+	})
+	if err != nil {
+		return Line{}, fmt.Errorf("get number series %s lines: %w", code, err)
+	}
+	day := bc.DateOf(date)
+	// An empty StartingDate is "0001-01-01", so it applies on any date.
+	slices.SortStableFunc(lines, func(a, b Line) int { return b.StartingDate.TimeUTC().Compare(a.StartingDate.TimeUTC()) })
+	for _, l := range lines {
+		if !l.StartingDate.TimeUTC().After(day.TimeUTC()) {
+			return l, nil
+		}
+	}
+	return Line{}, fmt.Errorf("%w: %s on %s", ErrNoOpenLine, code, day)
+}
+
+// Peek returns the next number of the series on the date without using it. Another user
+// can take it before a document does, so use Reserve for numbers that are printed.
+func (c *Client) Peek(ctx context.Context, code string, date time.Time) (string, error) {
+	l, err := c.Line(ctx, code, date)
+	if err != nil {
+		return "", err
+	}
+	next, err := l.Next()
+	if err != nil {
+		return "", fmt.Errorf("peek number series %s: %w", code, err)
+	}
+	return next, nil
+}
+
+// Reserve takes the next number of the series with the ReserveAction, so BC marks it
+// as used and no other document gets it.
+func (c *Client) Reserve(ctx context.Context, code string) (string, error) {
+	if c.opts.ReserveAction == "" {
+		return "", errors.New("reserve number: ReserveAction is not set")
+	}
+	s, err := c.Get(ctx, code)
+	if err != nil {
+		return "", err
+	}
+
+	req, err := c.client.NewRequest(ctx, bc.RequestOptions{
+		Method:        http.MethodPost,
+		EntitySetName: fmt.Sprintf("%s(%s)/Microsoft.NAV.%s", c.opts.SeriesEntitySetName, s.ID, c.opts.ReserveAction),
+	})
+	if err != nil {
+		return "", fmt.Errorf("reserve number %s: %w", code, err)
+	}
+	res, err := c.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("reserve number %s: %w", code, err)
+	}
+	result, err := bc.Decode[actionResult](res)
+	if err != nil {
+		return "", fmt.Errorf("reserve number %s: %w", code, err)
+	}
+	c.client.Logger().Debug("Reserved number.", "series", code, "number", result.Value)
+	return result.Value, nil
+}
+
+// actionResult is the response of a bound action that returns a value.
+type actionResult struct {
+	Value string `json:"value"`
+}
+
+func (r actionResult) Validate() error {
+	if r.Value == "" {
+		return errors.New("action returned no number")
+	}
+	return nil
+}
+
+// Increment adds n to the last run of digits in the number keeping its leading zeros,
+// like the IncStr function of AL, e.g. "SO-00099" to "SO-00100".
+func Increment(no string, n int) (string, error) {
+	end := len(no)
+	for end > 0 && (no[end-1] < '0' || no[end-1] > '9') {
+		end--
+	}
+	start := end
+	for start > 0 && no[start-1] >= '0' && no[start-1] <= '9' {
+		start--
+	}
+	if start == end {
+		return "", fmt.Errorf("increment %q: no digits", no)
+	}
+
+	digits := no[start:end]
+	v, err := strconv.ParseUint(digits, 10, 64)
+	if err != nil {
+		return "", fmt.Errorf("increment %q: %w", no, err)
+	}
+	next := strconv.FormatUint(v+uint64(n), 10)
+	if len(next) < len(digits) {
+		next = fmt.Sprintf("%0*s", len(digits), next)
+	}
+	return no[:start] + next + no[end:], nil
+}
+
+// compareNo compares numbers of a series, where a longer number is after a shorter one.
+func compareNo(a, b string) int {
+	return cmp.Or(cmp.Compare(len(a), len(b)), cmp.Compare(a, b))
+}
@@ -0,0 +1,120 @@
+package noseries_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/erlorenz/bc-go/internal/bctest"
+	"github.com/erlorenz/bc-go/noseries"
+	"github.com/google/uuid"
+)
+
+var seriesID = uuid.New()
+
+// seriesServer answers the series, lines and reserve action of an extension API.
+func seriesServer(t *testing.T, lines []map[string]any) *bc.Client {
+	t.Helper()
+	rt := bc.RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		var res *http.Response
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/noSeriesLines"):
+			res = bctest.NewResponse(http.StatusOK, map[string]any{"value": lines})
+		case strings.HasSuffix(r.URL.Path, "/noSeries"):
+			res = bctest.NewResponse(http.StatusOK, map[string]any{"value": []map[string]any{{"id": seriesID, "code": "S-ORD"}}})
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/noSeries("+seriesID.String()+")/Microsoft.NAV.reserveNextNo"):
+			res = bctest.NewResponse(http.StatusOK, map[string]any{"value": "SO-00042"})
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+			res = bctest.NewResponse(http.StatusNotFound, map[string]any{})
+		}
+		res.Request = r
+		return res, nil
+	})
+	config := bctest.ClientConfig()
+	config.APIEndpoint = "contoso/numbers/v1.0"
+	return bctest.NewClientConfig(t, config, rt)
+}
+
+func TestPeek(t *testing.T) {
+	client := seriesServer(t, []map[string]any{
+		{"seriesCode": "S-ORD", "lineNo": 10000, "startingDate": "0001-01-01", "startingNo": "SO-00001", "lastNoUsed": "SO-00099", "open": true},
+		{"seriesCode": "S-ORD", "lineNo": 20000, "startingDate": "2025-01-01", "startingNo": "SO25-0001", "endingNo": "SO25-9999", "open": true},
+	})
+	series := noseries.New(client, noseries.Options{ReserveAction: "reserveNextNo"})
+	ctx := context.Background()
+
+	table := []struct {
+		date time.Time
+		want string
+	}{
+		{time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC), "SO-00100"},
+		{time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), "SO25-0001"},
+	}
+	for _, v := range table {
+		got, err := series.Peek(ctx, "S-ORD", v.date)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != v.want {
+			t.Errorf("on %s wanted %s, got %s", v.date.Format(time.DateOnly), v.want, got)
+		}
+	}
+
+	got, err := series.Reserve(ctx, "S-ORD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "SO-00042" {
+		t.Errorf("wanted the number of the action, got %s", got)
+	}
+}
+
+func TestPeekNoLine(t *testing.T) {
+	client := seriesServer(t, []map[string]any{
+		{"seriesCode": "S-ORD", "lineNo": 10000, "startingDate": "2030-01-01", "startingNo": "SO-00001", "open": true},
+	})
+	series := noseries.New(client, noseries.Options{})
+
+	if _, err := series.Peek(context.Background(), "S-ORD", time.Now()); !errors.Is(err, noseries.ErrNoOpenLine) {
+		t.Errorf("wanted ErrNoOpenLine, got %v", err)
+	}
+	if _, err := series.Reserve(context.Background(), "S-ORD"); err == nil {
+		t.Error("wanted an error without a ReserveAction")
+	}
+}
+
+func TestLineNext(t *testing.T) {
+	table := []struct {
+		name    string
+		line    noseries.Line
+		want    string
+		wantErr error
+	}{
+		{"Unused", noseries.Line{StartingNo: "INV0001"}, "INV0001", nil},
+		{"Increment", noseries.Line{StartingNo: "INV0001", LastNoUsed: "INV0009", IncrementByNo: 10}, "INV0019", nil},
+		{"Suffix", noseries.Line{LastNoUsed: "A-099-X"}, "A-100-X", nil},
+		{"Grow", noseries.Line{LastNoUsed: "99"}, "100", nil},
+		{"Ending", noseries.Line{LastNoUsed: "INV0009", EndingNo: "INV0010"}, "INV0010", nil},
+		{"Exhausted", noseries.Line{LastNoUsed: "INV0010", EndingNo: "INV0010"}, "", noseries.ErrExhausted},
+	}
+	for _, v := range table {
+		t.Run(v.name, func(t *testing.T) {
+			got, err := v.line.Next()
+			if !errors.Is(err, v.wantErr) {
+				t.Fatalf("wanted error %v, got %v", v.wantErr, err)
+			}
+			if got != v.want {
+				t.Errorf("wanted %s, got %s", v.want, got)
+			}
+		})
+	}
+
+	if _, err := noseries.Increment("ABC", 1); err == nil {
+		t.Error("wanted an error for a number without digits")
+	}
+}
@@ -0,0 +1,102 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrNotDead is returned by [Queue.Edit] and [Queue.Replay] for a message that is
+// not a dead letter.
+var ErrNotDead = errors.New("outbox: message is not a dead letter")
+
+// DeadLetters returns the dead-lettered messages by EnqueuedAt. Their Failures are
+// the history of every failed attempt, including those before a replay.
+func (q *Queue) DeadLetters(ctx context.Context) ([]Message, error) {
+	messages, err := q.store.List(ctx, StatusDead)
+	if err != nil {
+		return nil, fmt.Errorf("list dead letters: %w", err)
+	}
+	return messages, nil
+}
+
+// Get returns a message of any status, or an error matching ErrNotFound.
+func (q *Queue) Get(ctx context.Context, id string) (Message, error) {
+	m, err := q.store.Get(ctx, id)
+	if err != nil {
+		return m, fmt.Errorf("get %s: %w", id, err)
+	}
+	return m, nil
+}
+
+// Edit changes a dead letter with the function, e.g. to fix its payload with
+// [Message.SetBody] before a [Queue.Replay]. Only the Method, EntitySetName, RecordID,
+// Body and ETag can be changed. The message stays dead until it is replayed.
+func (q *Queue) Edit(ctx context.Context, id string, edit func(m *Message) error) (Message, error) {
+	m, err := q.deadLetter(ctx, id)
+	if err != nil {
+		return m, fmt.Errorf("edit %s: %w", id, err)
+	}
+
+	edited := m
+	if err := edit(&edited); err != nil {
+		return m, fmt.Errorf("edit %s: %w", id, err)
+	}
+	if edited.Method == "" || edited.EntitySetName == "" {
+		return m, fmt.Errorf("edit %s: Method and EntitySetName are required", id)
+	}
+	m.Method = edited.Method
+	m.EntitySetName = edited.EntitySetName
+	m.RecordID = edited.RecordID
+	m.Body = edited.Body
+	m.ETag = edited.ETag
+
+	if err := q.store.Update(ctx, m); err != nil {
+		return m, fmt.Errorf("edit %s: %w", id, err)
+	}
+	return m, nil
+}
+
+// Replay moves a dead letter back to the queue to be sent right away, with
+// MaxAttempts new attempts. The failures so far are kept.
+func (q *Queue) Replay(ctx context.Context, id string) (Message, error) {
+	m, err := q.deadLetter(ctx, id)
+	if err != nil {
+		return m, fmt.Errorf("replay %s: %w", id, err)
+	}
+	m.Status = StatusPending
+	m.NextAttempt = q.opts.Clock.Now()
+	m.Attempts = 0
+	m.Replays++
+
+	if err := q.store.Update(ctx, m); err != nil {
+		return m, fmt.Errorf("replay %s: %w", id, err)
+	}
+	q.notify()
+	return m, nil
+}
+
+// ReplayAll replays every dead letter and returns how many were replayed.
+func (q *Queue) ReplayAll(ctx context.Context) (int, error) {
+	dead, err := q.DeadLetters(ctx)
+	if err != nil {
+		return 0, err
+	}
+	for i, m := range dead {
+		if _, err := q.Replay(ctx, m.ID); err != nil {
+			return i, err
+		}
+	}
+	return len(dead), nil
+}
+
+func (q *Queue) deadLetter(ctx context.Context, id string) (Message, error) {
+	m, err := q.store.Get(ctx, id)
+	if err != nil {
+		return m, err
+	}
+	if m.Status != StatusDead {
+		return m, ErrNotDead
+	}
+	return m, nil
+}
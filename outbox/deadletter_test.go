@@ -0,0 +1,105 @@
+package outbox_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/erlorenz/bc-go/bcfake"
+	"github.com/erlorenz/bc-go/bcmock"
+	"github.com/erlorenz/bc-go/internal/bctest"
+	"github.com/erlorenz/bc-go/outbox"
+	"github.com/google/uuid"
+)
+
+func TestEditAndReplay(t *testing.T) {
+	server := bcfake.New()
+	q := outbox.New(newClient(t, server, 0), &outbox.MemoryStore{}, outbox.Options{})
+	ctx := context.Background()
+
+	// The customer was never created, so the update fails with a 404
+	m := newMessage(t, "rename", http.MethodPatch, "customers", uuid.New(), map[string]any{"displayName": "Adatum"})
+	if _, err := q.Enqueue(ctx, m); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := q.Dispatch(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	dead, err := q.DeadLetters(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dead) != 1 || len(dead[0].Failures) != 1 || dead[0].Failures[0].StatusCode != http.StatusNotFound {
+		t.Fatalf("wanted the dead letter with its failure, got %+v", dead)
+	}
+
+	edited, err := q.Edit(ctx, "rename", func(m *outbox.Message) error {
+		m.Method = http.MethodPost
+		m.RecordID = uuid.Nil
+		m.Status = outbox.StatusDone // ignored
+		return m.SetBody(map[string]any{"displayName": "Adatum", "number": "C001"})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if edited.Status != outbox.StatusDead || edited.Method != http.MethodPost {
+		t.Errorf("unexpected edited message %+v", edited)
+	}
+
+	if n, err := q.ReplayAll(ctx); err != nil || n != 1 {
+		t.Fatalf("wanted 1 replayed, got %d %v", n, err)
+	}
+	if result, err := q.Dispatch(ctx); err != nil || result.Completed != 1 {
+		t.Fatalf("wanted the replay to complete, got %+v %v", result, err)
+	}
+	if records := server.Records("customers"); len(records) != 1 || records[0]["number"] != "C001" {
+		t.Errorf("wanted the edited payload to be created, got %v", records)
+	}
+
+	got, err := q.Get(ctx, "rename")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Status != outbox.StatusDone || got.Replays != 1 || len(got.Failures) != 1 {
+		t.Errorf("wanted the history to be kept, got %+v", got)
+	}
+
+	if _, err := q.Replay(ctx, "rename"); !errors.Is(err, outbox.ErrNotDead) {
+		t.Errorf("wanted ErrNotDead for a done message, got %v", err)
+	}
+	if _, err := q.Get(ctx, "unknown"); !errors.Is(err, outbox.ErrNotFound) {
+		t.Errorf("wanted ErrNotFound, got %v", err)
+	}
+}
+
+func TestReplayClock(t *testing.T) {
+	server := bcfake.New()
+	clock := bcmock.NewClock(time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC))
+	q := outbox.New(bctest.NewClient(t, server, bc.WithClock(clock)), &outbox.MemoryStore{}, outbox.Options{})
+	ctx := context.Background()
+
+	m := newMessage(t, "rename", http.MethodPatch, "customers", uuid.New(), map[string]any{"displayName": "Adatum"})
+	if _, err := q.Enqueue(ctx, m); err != nil {
+		t.Fatal(err)
+	}
+	if result, err := q.Dispatch(ctx); err != nil || result.DeadLettered != 1 {
+		t.Fatalf("wanted a dead letter, got %+v %v", result, err)
+	}
+
+	clock.Advance(time.Minute)
+	replayed, err := q.Replay(ctx, "rename")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !replayed.NextAttempt.Equal(clock.Now()) {
+		t.Errorf("wanted the replay due at %s, got %s", clock.Now(), replayed.NextAttempt)
+	}
+	// The replay is due on the clock of the queue, which is behind the time of the test.
+	if result, err := q.Dispatch(ctx); err != nil || result.DeadLettered != 1 {
+		t.Errorf("wanted the replay to be sent, got %+v %v", result, err)
+	}
+}
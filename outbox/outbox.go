@@ -0,0 +1,383 @@
+// Package outbox queues writes to BC in a durable [Store] and applies them in the
+// background, so an upstream system keeps accepting orders while BC is unavailable,
+// e.g. during a maintenance window:
+//
+//	q := outbox.New(client, outbox.SQLStore{DB: db}, outbox.Options{})
+//	go q.Run(ctx)
+//
+//	m, err := outbox.NewMessage("web-order-1001", http.MethodPost, "salesOrders", uuid.Nil, order)
+//	_, err = q.Enqueue(ctx, m)
+//
+// A failed write is retried with backoff while [Retryable] returns true, and moved to
+// the dead letters otherwise or after MaxAttempts. List them with [Queue.DeadLetters],
+// fix them with [Queue.Edit] and send them again with [Queue.Replay]. Messages are
+// deduplicated by ID, so enqueuing the same upstream order twice writes it once.
+//
+// Delivery is at least once: if the process stops after BC applied a write but before
+// the store is updated, the write is sent again. Use an If-Match ETag for updates and
+// deletes, and a unique external document number for creates, to make that safe.
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/google/uuid"
+)
+
+// ErrDuplicate is returned by [Queue.Enqueue] for a message ID that was already enqueued.
+var ErrDuplicate = errors.New("outbox: duplicate message")
+
+// ErrNotFound is returned by a [Store] for an unknown message ID.
+var ErrNotFound = errors.New("outbox: message not found")
+
+// errInvalid is a message that cannot be sent, e.g. with an invalid entity set name.
+var errInvalid = errors.New("invalid message")
+
+// Status is the state of a [Message].
+type Status string
+
+const (
+	// StatusPending messages are waiting for their next attempt.
+	StatusPending Status = "pending"
+	// StatusDone messages were applied. They are kept to deduplicate.
+	StatusDone Status = "done"
+	// StatusDead messages failed permanently or too often.
+	StatusDead Status = "dead"
+)
+
+// Failure is a failed attempt of a message.
+type Failure struct {
+	Time       time.Time `json:"time"`
+	Error      string    `json:"error"`
+	StatusCode int       `json:"statusCode,omitempty"`
+}
+
+// Message is a queued write.
+type Message struct {
+	// ID deduplicates the message, e.g. the id of the upstream order. Defaults to a random uuid.
+	ID            string          `json:"id"`
+	Method        string          `json:"method"`
+	EntitySetName string          `json:"entitySetName"`
+	RecordID      uuid.UUID       `json:"recordId,omitempty"`
+	Body          json.RawMessage `json:"body,omitempty"`
+	// ETag is sent as the If-Match header. Defaults to "*".
+	ETag string `json:"etag,omitempty"`
+
+	Status      Status    `json:"status"`
+	EnqueuedAt  time.Time `json:"enqueuedAt"`
+	NextAttempt time.Time `json:"nextAttempt"`
+	Attempts    int       `json:"attempts"`
+	// Failures are the failed attempts, oldest first.
+	Failures []Failure `json:"failures,omitempty"`
+	// Result is the response body of the successful attempt.
+	Result json.RawMessage `json:"result,omitempty"`
+	// Replays is the number of times the message was replayed from the dead letters.
+	Replays int `json:"replays,omitempty"`
+}
+
+// NewMessage returns a message with the body encoded as JSON. A nil body is left out.
+func NewMessage(id, method, entitySetName string, recordID uuid.UUID, body any) (Message, error) {
+	m := Message{ID: id, Method: method, EntitySetName: entitySetName, RecordID: recordID}
+	if err := m.SetBody(body); err != nil {
+		return m, fmt.Errorf("new message: %w", err)
+	}
+	return m, nil
+}
+
+// SetBody encodes the body as JSON, e.g. to fix the payload of a dead letter with
+// [Queue.Edit]. A nil body removes it.
+func (m *Message) SetBody(body any) error {
+	if body == nil {
+		m.Body = nil
+		return nil
+	}
+	b, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	m.Body = b
+	return nil
+}
+
+// Options configure a [Queue].
+type Options struct {
+	// MaxAttempts before a message is dead-lettered. Defaults to 30, which with the
+	// default Backoff keeps retrying for about 4 hours.
+	MaxAttempts int
+	// Backoff returns the wait after the failed attempt, starting at 1. Defaults to
+	// 5 seconds doubled after each attempt up to 10 minutes.
+	Backoff func(attempt int) time.Duration
+	// Retryable decides if a failed write is retried. Defaults to [Retryable].
+	Retryable func(err error) bool
+	// PollInterval is how often Run checks for due messages. Defaults to 1 second.
+	PollInterval time.Duration
+	// BatchSize is the most messages sent per Dispatch. Defaults to 100.
+	BatchSize int
+	// OnComplete is called after a message is applied.
+	OnComplete func(m Message)
+	// OnDeadLetter is called after a message is dead-lettered.
+	OnDeadLetter func(m Message)
+	// Clock decides when messages are due and when a pause ends. Defaults to the
+	// Clock of the client, so it matches the maintenance windows the client checks.
+	Clock bc.Clock
+}
+
+// Retryable returns true for network errors, throttling, transient BC errors and
+// server errors, which include the 503 of a maintenance window.
+func Retryable(err error) bool {
+	var apiErr bc.APIError
+	if !errors.As(err, &apiErr) {
+		return true
+	}
+	if apiErr.Code.IsTransient() {
+		return true
+	}
+	switch apiErr.StatusCode {
+	case http.StatusRequestTimeout, http.StatusTooManyRequests:
+		return true
+	}
+	return apiErr.StatusCode >= 500
+}
+
+func defaultBackoff(attempt int) time.Duration {
+	const maxBackoff = 10 * time.Minute
+	if attempt > 8 {
+		return maxBackoff
+	}
+	return min(5*time.Second<<(attempt-1), maxBackoff)
+}
+
+// Queue enqueues messages in the store and dispatches them to BC.
+// Run a single dispatcher per store.
+type Queue struct {
+	client *bc.Client
+	store  Store
+	opts   Options
+	wake   chan struct{}
+
+	mu          sync.Mutex
+	pausedUntil time.Time
+}
+
+// New returns a Queue with the defaults of the options set.
+func New(client *bc.Client, store Store, opts Options) *Queue {
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = 30
+	}
+	if opts.Backoff == nil {
+		opts.Backoff = defaultBackoff
+	}
+	if opts.Retryable == nil {
+		opts.Retryable = Retryable
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = time.Second
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 100
+	}
+	if opts.Clock == nil {
+		opts.Clock = client.Clock()
+	}
+	return &Queue{client: client, store: store, opts: opts, wake: make(chan struct{}, 1)}
+}
+
+// Enqueue saves the message to be sent as soon as possible and returns it as stored.
+// It returns an error matching ErrDuplicate if the ID was enqueued before.
+func (q *Queue) Enqueue(ctx context.Context, m Message) (Message, error) {
+	if m.Method == "" || m.EntitySetName == "" {
+		return m, errors.New("enqueue: Method and EntitySetName are required")
+	}
+	if m.ID == "" {
+		m.ID = uuid.NewString()
+	}
+	now := q.opts.Clock.Now()
+	m.Status = StatusPending
+	m.EnqueuedAt = now
+	m.NextAttempt = now
+	m.Attempts = 0
+	m.Failures = nil
+	m.Result = nil
+	m.Replays = 0
+
+	if err := q.store.Enqueue(ctx, m); err != nil {
+		return m, fmt.Errorf("enqueue %s: %w", m.ID, err)
+	}
+	q.notify()
+	return m, nil
+}
+
+// notify wakes Run up to dispatch right away.
+func (q *Queue) notify() {
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+// DispatchResult counts the messages handled by [Queue.Dispatch].
+type DispatchResult struct {
+	Completed    int
+	Retried      int
+	DeadLettered int
+	// Paused is true if the queue is paused for a maintenance window, see [Queue.PausedUntil].
+	Paused bool
+}
+
+// Dispatch sends the due messages once, oldest first. The error is non-nil if the
+// store fails or the context is done; failed writes are only in the result.
+//
+// A [bc.MaintenanceError] of a client with [bc.WithMaintenanceSchedule] pauses the
+// queue until the end of the window. It is not a failed attempt of the message.
+func (q *Queue) Dispatch(ctx context.Context) (DispatchResult, error) {
+	var result DispatchResult
+	if q.opts.Clock.Now().Before(q.PausedUntil()) {
+		result.Paused = true
+		return result, nil
+	}
+	due, err := q.store.Due(ctx, q.opts.Clock.Now(), q.opts.BatchSize)
+	if err != nil {
+		return result, fmt.Errorf("dispatch: %w", err)
+	}
+
+	for _, m := range due {
+		body, err := q.send(ctx, m)
+		if ctx.Err() != nil {
+			// Not a failure of the message, it is sent again on the next dispatch.
+			return result, ctx.Err()
+		}
+		var merr bc.MaintenanceError
+		if errors.As(err, &merr) {
+			q.pause(merr.Window.End)
+			m.NextAttempt = merr.Window.End
+			if err := q.store.Update(ctx, m); err != nil {
+				return result, fmt.Errorf("dispatch %s: %w", m.ID, err)
+			}
+			result.Paused = true
+			return result, nil
+		}
+
+		m.Attempts++
+		now := q.opts.Clock.Now()
+		switch {
+		case err == nil:
+			m.Status = StatusDone
+			m.Result = body
+			result.Completed++
+		case !errors.Is(err, errInvalid) && q.opts.Retryable(err) && m.Attempts < q.opts.MaxAttempts:
+			m.Failures = append(m.Failures, failure(now, err))
+			m.NextAttempt = now.Add(q.opts.Backoff(m.Attempts))
+			result.Retried++
+		default:
+			m.Failures = append(m.Failures, failure(now, err))
+			m.Status = StatusDead
+			result.DeadLettered++
+		}
+
+		if err := q.store.Update(ctx, m); err != nil {
+			return result, fmt.Errorf("dispatch %s: %w", m.ID, err)
+		}
+		switch {
+		case m.Status == StatusDone && q.opts.OnComplete != nil:
+			q.opts.OnComplete(m)
+		case m.Status == StatusDead && q.opts.OnDeadLetter != nil:
+			q.opts.OnDeadLetter(m)
+		}
+	}
+	return result, nil
+}
+
+// PausedUntil returns the end of the maintenance window that paused the queue, or the
+// zero time if it was never paused.
+func (q *Queue) PausedUntil() time.Time {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.pausedUntil
+}
+
+func (q *Queue) pause(until time.Time) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.pausedUntil = until
+	q.client.Logger().Debug("Outbox paused for maintenance window.", "until", until)
+}
+
+func failure(t time.Time, err error) Failure {
+	f := Failure{Time: t, Error: err.Error()}
+	var apiErr bc.APIError
+	if errors.As(err, &apiErr) {
+		f.StatusCode = apiErr.StatusCode
+	}
+	return f
+}
+
+// Run dispatches the due messages every PollInterval, and right away after an
+// Enqueue, until the context is done. It returns the context error, or the error
+// of the store.
+func (q *Queue) Run(ctx context.Context) error {
+	ticker := time.NewTicker(q.opts.PollInterval)
+	defer ticker.Stop()
+	for {
+		for {
+			result, err := q.Dispatch(ctx)
+			if err != nil {
+				return err
+			}
+			// A full batch means more may be due.
+			if result.Paused || result.Completed+result.Retried+result.DeadLettered < q.opts.BatchSize {
+				break
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		case <-q.wake:
+		}
+	}
+}
+
+// record is the response of any entity set.
+type record map[string]any
+
+func (record) Validate() error { return nil }
+
+// send applies the message and returns the response body.
+func (q *Queue) send(ctx context.Context, m Message) (json.RawMessage, error) {
+	opts := bc.RequestOptions{Method: m.Method, EntitySetName: m.EntitySetName, RecordID: m.RecordID, ETag: m.ETag}
+	if len(m.Body) > 0 {
+		opts.Body = m.Body
+	}
+	req, err := q.client.NewRequest(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to create Request: %w", errInvalid, err)
+	}
+	res, err := q.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed during request: %w", err)
+	}
+
+	if m.Method == http.MethodDelete {
+		// A delete sent again after it was applied finds no record.
+		if err := bc.DecodeNoContent(res); err != nil && !errors.Is(err, bc.ErrNotFound) {
+			return nil, err
+		}
+		return nil, nil
+	}
+	data, err := bc.Decode[record](res)
+	if err != nil {
+		return nil, err
+	}
+	b, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	return b, nil
+}
@@ -0,0 +1,223 @@
+package outbox_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/erlorenz/bc-go/bcfake"
+	"github.com/erlorenz/bc-go/bcmock"
+	"github.com/erlorenz/bc-go/internal/bctest"
+	"github.com/erlorenz/bc-go/outbox"
+	"github.com/google/uuid"
+)
+
+// newClient returns a client of the server that fails the first requests with a 503,
+// as during a maintenance window, and does not retry them itself.
+func newClient(t *testing.T, server *bcfake.Server, unavailable int) *bc.Client {
+	t.Helper()
+	var count atomic.Int32
+	transport := bc.RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		if int(count.Add(1)) <= unavailable {
+			return bctest.NewResponse(http.StatusServiceUnavailable, map[string]any{
+				"error": map[string]any{"code": "ServiceUnavailable", "message": "maintenance"},
+			}), nil
+		}
+		return server.RoundTrip(r)
+	})
+	return bctest.NewClient(t, transport,
+		bc.WithRetryClassifier(bc.RetryClassifierFunc(func(bc.RetryAttempt) bc.RetryDecision { return bc.RetryDecision{} })))
+}
+
+func newMessage(t *testing.T, id, method, entitySetName string, recordID uuid.UUID, body any) outbox.Message {
+	t.Helper()
+	m, err := outbox.NewMessage(id, method, entitySetName, recordID, body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestDispatch(t *testing.T) {
+	server := bcfake.New()
+	store := &outbox.MemoryStore{}
+	q := outbox.New(newClient(t, server, 0), store, outbox.Options{})
+	ctx := context.Background()
+
+	m := newMessage(t, "order-1", http.MethodPost, "customers", uuid.Nil, map[string]any{"displayName": "Adatum"})
+	if _, err := q.Enqueue(ctx, m); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := q.Enqueue(ctx, m); !errors.Is(err, outbox.ErrDuplicate) {
+		t.Fatalf("wanted ErrDuplicate, got %v", err)
+	}
+
+	result, err := q.Dispatch(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Completed != 1 || len(server.Records("customers")) != 1 {
+		t.Fatalf("unexpected result %+v", result)
+	}
+	done, _ := store.List(ctx, outbox.StatusDone)
+	var created map[string]any
+	if len(done) != 1 || json.Unmarshal(done[0].Result, &created) != nil || created["displayName"] != "Adatum" {
+		t.Errorf("wanted the created record as the result, got %+v", done)
+	}
+
+	// A done message is still deduplicated
+	if _, err := q.Enqueue(ctx, m); !errors.Is(err, outbox.ErrDuplicate) {
+		t.Errorf("wanted ErrDuplicate after dispatch, got %v", err)
+	}
+	if result, _ := q.Dispatch(ctx); result.Completed != 0 {
+		t.Errorf("wanted nothing to dispatch, got %+v", result)
+	}
+}
+
+func TestDispatchRetry(t *testing.T) {
+	server := bcfake.New()
+	store := &outbox.MemoryStore{}
+	var backoff time.Duration = time.Hour
+	q := outbox.New(newClient(t, server, 1), store, outbox.Options{Backoff: func(int) time.Duration { return backoff }})
+	ctx := context.Background()
+
+	if _, err := q.Enqueue(ctx, newMessage(t, "", http.MethodPost, "customers", uuid.Nil, map[string]any{})); err != nil {
+		t.Fatal(err)
+	}
+	result, err := q.Dispatch(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pending, _ := store.List(ctx, outbox.StatusPending)
+	if result.Retried != 1 || len(pending) != 1 || len(pending[0].Failures) != 1 || pending[0].Failures[0].StatusCode != 503 {
+		t.Fatalf("wanted the failure to be retried, got %+v %+v", result, pending)
+	}
+
+	// Not due yet
+	if result, _ := q.Dispatch(ctx); result.Completed+result.Retried != 0 {
+		t.Errorf("wanted the retry to wait for the backoff, got %+v", result)
+	}
+
+	backoff = 0
+	q = outbox.New(newClient(t, server, 0), store, outbox.Options{})
+	m := pending[0]
+	m.NextAttempt = time.Now()
+	if err := store.Update(ctx, m); err != nil {
+		t.Fatal(err)
+	}
+	if result, err := q.Dispatch(ctx); err != nil || result.Completed != 1 {
+		t.Errorf("wanted the retry to complete, got %+v %v", result, err)
+	}
+	if done, _ := store.List(ctx, outbox.StatusDone); len(done) != 1 || done[0].Attempts != 2 {
+		t.Errorf("unexpected done messages %+v", done)
+	}
+}
+
+func TestDispatchMaintenanceWindow(t *testing.T) {
+	server := bcfake.New()
+	store := &outbox.MemoryStore{}
+	clock := bcmock.NewClock(time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC))
+	end := clock.Now().Add(time.Hour)
+	schedule := bc.NewMaintenanceSchedule(bc.MaintenanceWindow{Start: clock.Now().Add(-time.Hour), End: end})
+	client := bctest.NewClient(t, server, bc.WithMaintenanceSchedule(schedule), bc.WithClock(clock))
+	q := outbox.New(client, store, outbox.Options{})
+	ctx := context.Background()
+
+	for _, id := range []string{"order-1", "order-2"} {
+		if _, err := q.Enqueue(ctx, newMessage(t, id, http.MethodPost, "customers", uuid.Nil, map[string]any{})); err != nil {
+			t.Fatal(err)
+		}
+	}
+	result, err := q.Dispatch(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Paused || result.Retried != 0 || !q.PausedUntil().Equal(end) {
+		t.Fatalf("wanted the queue paused until %s, got %+v until %s", end, result, q.PausedUntil())
+	}
+	if pending, _ := store.List(ctx, outbox.StatusPending); len(pending) != 2 {
+		t.Errorf("wanted both messages pending, got %+v", pending)
+	}
+	// order-1 is dispatched first by ID and deferred to the end of the window.
+	if m, err := q.Get(ctx, "order-1"); err != nil || m.Attempts != 0 || len(m.Failures) != 0 || !m.NextAttempt.Equal(end) {
+		t.Errorf("wanted no attempt counted during the window, got %+v %v", m, err)
+	}
+	if result, _ := q.Dispatch(ctx); !result.Paused || len(server.Records("customers")) != 0 {
+		t.Errorf("wanted nothing sent while paused, got %+v", result)
+	}
+
+	// The window ends on the clock of the client, not the time of the test.
+	clock.Advance(time.Hour)
+	if result, err := q.Dispatch(ctx); err != nil || result.Paused || result.Completed != 2 {
+		t.Errorf("wanted the messages sent after the window, got %+v %v", result, err)
+	}
+}
+
+func TestDispatchDeadLetter(t *testing.T) {
+	server := bcfake.New()
+	store := &outbox.MemoryStore{}
+	var dead []string
+	q := outbox.New(newClient(t, server, 1), store, outbox.Options{
+		MaxAttempts:  1,
+		OnDeadLetter: func(m outbox.Message) { dead = append(dead, m.ID) },
+	})
+	ctx := context.Background()
+
+	// Too many attempts, not found and a PATCH without a record are dead letters,
+	// a delete of a record that is already gone is done.
+	for _, m := range []outbox.Message{
+		newMessage(t, "unavailable", http.MethodPost, "customers", uuid.Nil, map[string]any{}),
+		newMessage(t, "missing", http.MethodPatch, "customers", uuid.New(), map[string]any{"displayName": "x"}),
+		newMessage(t, "invalid", http.MethodPatch, "customers", uuid.Nil, map[string]any{}),
+		newMessage(t, "deleted", http.MethodDelete, "customers", uuid.New(), nil),
+	} {
+		if _, err := q.Enqueue(ctx, m); err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	result, err := q.Dispatch(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.DeadLettered != 3 || result.Completed != 1 || strings.Join(dead, " ") != "unavailable missing invalid" {
+		t.Errorf("unexpected result %+v, dead letters %v", result, dead)
+	}
+}
+
+func TestRun(t *testing.T) {
+	server := bcfake.New()
+	completed := make(chan outbox.Message, 1)
+	q := outbox.New(newClient(t, server, 0), &outbox.MemoryStore{}, outbox.Options{
+		PollInterval: time.Hour,
+		OnComplete:   func(m outbox.Message) { completed <- m },
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errc := make(chan error, 1)
+	go func() { errc <- q.Run(ctx) }()
+
+	if _, err := q.Enqueue(ctx, newMessage(t, "order-1", http.MethodPost, "customers", uuid.Nil, map[string]any{})); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case m := <-completed:
+		if m.ID != "order-1" {
+			t.Errorf("unexpected message %+v", m)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("wanted the enqueued message to be dispatched right away")
+	}
+
+	cancel()
+	if err := <-errc; !errors.Is(err, context.Canceled) {
+		t.Errorf("wanted the context error, got %v", err)
+	}
+}
@@ -0,0 +1,258 @@
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Store persists the messages of a [Queue].
+type Store interface {
+	// Enqueue saves a new pending message. It returns ErrDuplicate if a message with
+	// the ID exists, whatever its status.
+	Enqueue(ctx context.Context, m Message) error
+	// Due returns up to limit pending messages with a NextAttempt before now, by
+	// NextAttempt, EnqueuedAt and then ID.
+	Due(ctx context.Context, now time.Time, limit int) ([]Message, error)
+	// Update saves the message after an attempt, an edit or a replay.
+	Update(ctx context.Context, m Message) error
+	// Get returns ErrNotFound if there is no message with the ID.
+	Get(ctx context.Context, id string) (Message, error)
+	// List returns the messages with the status by EnqueuedAt and then ID.
+	List(ctx context.Context, status Status) ([]Message, error)
+}
+
+// MemoryStore is an in-memory [Store] for tests. It is safe for concurrent use.
+type MemoryStore struct {
+	mu       sync.Mutex
+	messages map[string]Message
+}
+
+// Enqueue implements [Store].
+func (s *MemoryStore) Enqueue(_ context.Context, m Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.messages == nil {
+		s.messages = map[string]Message{}
+	}
+	if _, ok := s.messages[m.ID]; ok {
+		return ErrDuplicate
+	}
+	s.messages[m.ID] = m
+	return nil
+}
+
+// Due implements [Store].
+func (s *MemoryStore) Due(_ context.Context, now time.Time, limit int) ([]Message, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var due []Message
+	for _, m := range s.messages {
+		if m.Status == StatusPending && !m.NextAttempt.After(now) {
+			due = append(due, m)
+		}
+	}
+	slices.SortFunc(due, func(a, b Message) int {
+		if c := a.NextAttempt.Compare(b.NextAttempt); c != 0 {
+			return c
+		}
+		if c := a.EnqueuedAt.Compare(b.EnqueuedAt); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+	return due[:min(limit, len(due))], nil
+}
+
+// Update implements [Store].
+func (s *MemoryStore) Update(_ context.Context, m Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.messages[m.ID]; !ok {
+		return ErrNotFound
+	}
+	s.messages[m.ID] = m
+	return nil
+}
+
+// Get implements [Store].
+func (s *MemoryStore) Get(_ context.Context, id string) (Message, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	m, ok := s.messages[id]
+	if !ok {
+		return Message{}, ErrNotFound
+	}
+	return m, nil
+}
+
+// List implements [Store].
+func (s *MemoryStore) List(_ context.Context, status Status) ([]Message, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var messages []Message
+	for _, m := range s.messages {
+		if m.Status == status {
+			messages = append(messages, m)
+		}
+	}
+	slices.SortFunc(messages, func(a, b Message) int {
+		if c := a.EnqueuedAt.Compare(b.EnqueuedAt); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+	return messages, nil
+}
+
+// Schema creates the table of a [SQLStore] in SQLite or Postgres.
+const Schema = `CREATE TABLE IF NOT EXISTS outbox_messages (
+	id TEXT PRIMARY KEY,
+	status TEXT NOT NULL,
+	next_attempt BIGINT NOT NULL,
+	enqueued_at BIGINT NOT NULL,
+	data TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS outbox_messages_due ON outbox_messages (status, next_attempt);`
+
+// SQLStore is a [Store] in the table of [Schema], with the messages as JSON and the
+// times in Unix milliseconds. It works with the SQLite and Postgres drivers.
+type SQLStore struct {
+	DB *sql.DB
+	// Postgres uses "$1" placeholders instead of "?".
+	Postgres bool
+}
+
+// bind replaces the "?" placeholders of the query for Postgres.
+func (s SQLStore) bind(query string) string {
+	if !s.Postgres {
+		return query
+	}
+	var b strings.Builder
+	n := 0
+	for _, c := range query {
+		if c == '?' {
+			n++
+			fmt.Fprintf(&b, "$%d", n)
+			continue
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
+}
+
+// Enqueue implements [Store].
+func (s SQLStore) Enqueue(ctx context.Context, m Message) error {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return fmt.Errorf("insert message: %w", err)
+	}
+	res, err := s.DB.ExecContext(ctx, s.bind(`INSERT INTO outbox_messages (id, status, next_attempt, enqueued_at, data)
+		VALUES (?, ?, ?, ?, ?) ON CONFLICT (id) DO NOTHING`),
+		m.ID, string(m.Status), m.NextAttempt.UnixMilli(), m.EnqueuedAt.UnixMilli(), string(data))
+	if err != nil {
+		return fmt.Errorf("insert message: %w", err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return ErrDuplicate
+	}
+	return nil
+}
+
+// Due implements [Store].
+func (s SQLStore) Due(ctx context.Context, now time.Time, limit int) ([]Message, error) {
+	rows, err := s.DB.QueryContext(ctx, s.bind(`SELECT data FROM outbox_messages
+		WHERE status = ? AND next_attempt <= ? ORDER BY next_attempt, enqueued_at, id LIMIT ?`),
+		string(StatusPending), now.UnixMilli(), limit)
+	if err != nil {
+		return nil, fmt.Errorf("select due messages: %w", err)
+	}
+	return scanMessages(rows)
+}
+
+// scanMessages decodes the data column of the rows and closes them.
+func scanMessages(rows *sql.Rows) ([]Message, error) {
+	defer rows.Close()
+
+	var messages []Message
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("scan message: %w", err)
+		}
+		var m Message
+		if err := json.Unmarshal([]byte(data), &m); err != nil {
+			return nil, fmt.Errorf("scan message: %w", err)
+		}
+		messages = append(messages, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("scan message: %w", err)
+	}
+	return messages, nil
+}
+
+// Update implements [Store].
+func (s SQLStore) Update(ctx context.Context, m Message) error {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return fmt.Errorf("update message: %w", err)
+	}
+	res, err := s.DB.ExecContext(ctx, s.bind(`UPDATE outbox_messages SET status = ?, next_attempt = ?, data = ? WHERE id = ?`),
+		string(m.Status), m.NextAttempt.UnixMilli(), string(data), m.ID)
+	if err != nil {
+		return fmt.Errorf("update message: %w", err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Get implements [Store].
+func (s SQLStore) Get(ctx context.Context, id string) (Message, error) {
+	var data string
+	err := s.DB.QueryRowContext(ctx, s.bind(`SELECT data FROM outbox_messages WHERE id = ?`), id).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Message{}, ErrNotFound
+	}
+	if err != nil {
+		return Message{}, fmt.Errorf("get message: %w", err)
+	}
+	var m Message
+	if err := json.Unmarshal([]byte(data), &m); err != nil {
+		return Message{}, fmt.Errorf("get message: %w", err)
+	}
+	return m, nil
+}
+
+// List implements [Store].
+func (s SQLStore) List(ctx context.Context, status Status) ([]Message, error) {
+	rows, err := s.DB.QueryContext(ctx, s.bind(`SELECT data FROM outbox_messages WHERE status = ? ORDER BY enqueued_at, id`), string(status))
+	if err != nil {
+		return nil, fmt.Errorf("list messages: %w", err)
+	}
+	return scanMessages(rows)
+}
+
+// Purge deletes the done messages enqueued before the time. Their IDs can be
+// enqueued again afterwards.
+func (s SQLStore) Purge(ctx context.Context, before time.Time) (int64, error) {
+	res, err := s.DB.ExecContext(ctx, s.bind(`DELETE FROM outbox_messages WHERE status = ? AND enqueued_at < ?`),
+		string(StatusDone), before.UnixMilli())
+	if err != nil {
+		return 0, fmt.Errorf("purge messages: %w", err)
+	}
+	return res.RowsAffected()
+}
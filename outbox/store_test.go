@@ -0,0 +1,46 @@
+package outbox_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/erlorenz/bc-go/outbox"
+)
+
+func TestMemoryStore(t *testing.T) {
+	ctx := context.Background()
+	store := &outbox.MemoryStore{}
+	now := time.Now()
+
+	messages := []outbox.Message{
+		{ID: "later", Status: outbox.StatusPending, EnqueuedAt: now, NextAttempt: now.Add(time.Hour)},
+		{ID: "second", Status: outbox.StatusPending, EnqueuedAt: now.Add(time.Second), NextAttempt: now},
+		{ID: "first", Status: outbox.StatusPending, EnqueuedAt: now, NextAttempt: now},
+		{ID: "dead", Status: outbox.StatusDead, EnqueuedAt: now, NextAttempt: now},
+	}
+	for _, m := range messages {
+		if err := store.Enqueue(ctx, m); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := store.Enqueue(ctx, messages[0]); !errors.Is(err, outbox.ErrDuplicate) {
+		t.Errorf("wanted ErrDuplicate, got %v", err)
+	}
+
+	due, err := store.Due(ctx, now, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(due) != 2 || due[0].ID != "first" || due[1].ID != "second" {
+		t.Errorf("unexpected due messages %+v", due)
+	}
+	if due, _ := store.Due(ctx, now, 1); len(due) != 1 {
+		t.Errorf("wanted the limit, got %+v", due)
+	}
+
+	if err := store.Update(ctx, outbox.Message{ID: "unknown"}); err == nil {
+		t.Error("wanted error for an unknown message")
+	}
+}
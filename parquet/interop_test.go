@@ -0,0 +1,122 @@
+package parquet_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/erlorenz/bc-go/parquet"
+	"github.com/google/uuid"
+	pq "github.com/parquet-go/parquet-go"
+)
+
+// TestWriterInterop reads a written file with the parquet-go reader, so the encoding is
+// not only checked by the decoder of writer_test.go.
+func TestWriterInterop(t *testing.T) {
+	columns := []parquet.Column{
+		{Name: "id", Kind: parquet.UUID},
+		{Name: "number", Kind: parquet.String, Optional: true},
+		{Name: "blocked", Kind: parquet.Boolean},
+		{Name: "balance", Kind: parquet.Decimal, Optional: true, Precision: 18, Scale: 2},
+		{Name: "postingDate", Kind: parquet.Date},
+		{Name: "lastModifiedDateTime", Kind: parquet.Timestamp},
+		{Name: "quantity", Kind: parquet.Int32},
+		{Name: "entryNo", Kind: parquet.Int64},
+		{Name: "unitCost", Kind: parquet.Double},
+	}
+	ids := []uuid.UUID{uuid.New(), uuid.New(), uuid.New()}
+	rows := []map[string]any{
+		{"id": ids[0].String(), "number": "C001", "blocked": true, "balance": json.Number("1234.56"), "postingDate": "2024-01-31",
+			"lastModifiedDateTime": "2024-01-31T12:00:00.5Z", "quantity": json.Number("3"), "entryNo": json.Number("9007199254740993"), "unitCost": json.Number("1.25")},
+		{"id": ids[1].String(), "number": nil, "blocked": false, "balance": json.Number("-0.01"), "postingDate": "1970-01-02",
+			"lastModifiedDateTime": "1970-01-01T00:00:01Z", "quantity": json.Number("-1"), "entryNo": json.Number("1"), "unitCost": json.Number("0")},
+		{"id": ids[2].String(), "number": "C003", "blocked": true, "postingDate": "0001-01-01",
+			"lastModifiedDateTime": "0001-01-01T00:00:00Z", "quantity": json.Number("0"), "entryNo": json.Number("-2"), "unitCost": json.Number("-2.5")},
+	}
+
+	var buf bytes.Buffer
+	w, err := parquet.NewWriter(&buf, columns, parquet.WriterOptions{RowGroupSize: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, row := range rows {
+		if err := w.Write(row); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := pq.OpenFile(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("parquet-go cannot open the file: %s", err)
+	}
+	if f.NumRows() != 3 || len(f.RowGroups()) != 2 {
+		t.Fatalf("wanted 3 rows in 2 row groups, got %d in %d", f.NumRows(), len(f.RowGroups()))
+	}
+
+	fields := f.Schema().Fields()
+	wantTypes := []string{"UUID", "STRING", "BOOLEAN", "DECIMAL(18,2)", "DATE", "TIMESTAMP(isAdjustedToUTC=true,unit=MICROS)", "INT(32,true)", "INT(64,true)", "DOUBLE"}
+	for i, field := range fields {
+		if field.Name() != columns[i].Name || field.Optional() != columns[i].Optional {
+			t.Errorf("unexpected field %d: %s optional %t", i, field.Name(), field.Optional())
+		}
+		if got := typeString(field.Type()); got != wantTypes[i] {
+			t.Errorf("%s: wanted type %s, got %s", field.Name(), wantTypes[i], got)
+		}
+	}
+
+	var got []pq.Row
+	for _, rg := range f.RowGroups() {
+		rr := rg.Rows()
+		batch := make([]pq.Row, rg.NumRows())
+		n, err := rr.ReadRows(batch)
+		if err != nil && !errors.Is(err, io.EOF) {
+			t.Fatal(err)
+		}
+		got = append(got, batch[:n]...)
+		rr.Close()
+	}
+	if len(got) != 3 {
+		t.Fatalf("wanted 3 rows, got %d", len(got))
+	}
+
+	first := got[0]
+	if id, _ := uuid.FromBytes(first[0].ByteArray()); id != ids[0] {
+		t.Errorf("wanted id %s, got %s", ids[0], id)
+	}
+	if string(first[1].ByteArray()) != "C001" || !first[2].Boolean() {
+		t.Errorf("unexpected number and blocked %v %v", first[1], first[2])
+	}
+	if unscaled := new(big.Int).SetBytes(first[3].ByteArray()); unscaled.Int64() != 123456 {
+		t.Errorf("wanted the unscaled decimal 123456, got %s", unscaled)
+	}
+	if first[4].Int32() != int32(time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC).Unix()/86400) {
+		t.Errorf("unexpected date %v", first[4])
+	}
+	if first[5].Int64() != time.Date(2024, 1, 31, 12, 0, 0, 500e6, time.UTC).UnixMicro() {
+		t.Errorf("unexpected timestamp %v", first[5])
+	}
+	if first[6].Int32() != 3 || first[7].Int64() != 9007199254740993 || first[8].Double() != 1.25 {
+		t.Errorf("unexpected numbers %v %v %v", first[6], first[7], first[8])
+	}
+	if !got[1][1].IsNull() || !got[2][3].IsNull() || got[1][3].IsNull() {
+		t.Errorf("wanted the nulls of the optional columns, got %v and %v", got[1], got[2])
+	}
+	if string(got[2][1].ByteArray()) != "C003" || got[2][8].Double() != -2.5 {
+		t.Errorf("unexpected last row %v", got[2])
+	}
+}
+
+// typeString returns the logical type of a parquet-go type, or the physical type.
+func typeString(typ pq.Type) string {
+	if lt := typ.LogicalType(); lt != nil {
+		return lt.String()
+	}
+	return typ.Kind().String()
+}
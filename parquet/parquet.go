@@ -0,0 +1,222 @@
+// Package parquet writes entity sets to Parquet files with a schema from the $metadata of
+// the API, so the data can be loaded into a data lake as is. Decimals are written as
+// DECIMAL, dates as DATE, date times as TIMESTAMP and GUIDs as UUID.
+//
+// For example, exporting a few entity sets to a folder:
+//
+//	err := parquet.ExportDir(ctx, client, "lake/bc", []string{"customers", "items", "salesInvoices"}, parquet.Options{})
+package parquet
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/erlorenz/bc-go/bc"
+)
+
+// Options configure [Export].
+type Options struct {
+	// ListOptions select and filter the records. If Select is set, only those fields are columns.
+	// OrderBy defaults to "id" so pages are stable and MaxPageSize defaults to 1000.
+	ListOptions bc.ListOptions
+	// Columns are used instead of the columns from the $metadata. Not used by [ExportDir].
+	Columns []Column
+	// Capabilities are used instead of requesting the $metadata, e.g. when exporting several entity sets.
+	Capabilities *bc.Capabilities
+	// DecimalPrecision and DecimalScale are used for decimals with a "Variable" scale, which
+	// is most decimals in BC. Default to 38 and 10.
+	DecimalPrecision int
+	DecimalScale     int
+	// Writer configures the Parquet file.
+	Writer WriterOptions
+}
+
+// Result describes the export.
+type Result struct {
+	Records int
+	Pages   int
+	Columns []Column
+}
+
+// Columns returns the columns of the entity set from its properties in the $metadata, in order.
+// Only the fields in selected are included if it is not empty. Stream properties are skipped.
+func Columns(caps bc.Capabilities, entitySetName string, selected []string, opts Options) ([]Column, error) {
+	props, ok := caps.Properties[entitySetName]
+	if !ok {
+		return nil, fmt.Errorf("parquet: entity set %s is not in the $metadata", entitySetName)
+	}
+	precision, scale := opts.DecimalPrecision, opts.DecimalScale
+	if precision <= 0 {
+		precision = 38
+	}
+	if scale <= 0 {
+		scale = min(10, precision)
+	}
+
+	var columns []Column
+	for _, p := range props {
+		if len(selected) > 0 && !slices.Contains(selected, p.Name) {
+			continue
+		}
+		col := Column{Name: p.Name, Optional: p.Nullable}
+		switch p.Type {
+		case "Edm.Stream":
+			continue
+		case "Edm.Boolean":
+			col.Kind = Boolean
+		case "Edm.Byte", "Edm.SByte", "Edm.Int16", "Edm.Int32":
+			col.Kind = Int32
+		case "Edm.Int64":
+			col.Kind = Int64
+		case "Edm.Single", "Edm.Double":
+			col.Kind = Double
+		case "Edm.Decimal":
+			col.Kind = Decimal
+			col.Precision, col.Scale = precision, scale
+			if p.Precision > 0 {
+				col.Precision = p.Precision
+			}
+			// Without a Precision a missing Scale is treated as Variable
+			if p.Scale > 0 || (p.Scale == 0 && p.Precision > 0) {
+				col.Scale = p.Scale
+			}
+			col.Scale = min(col.Scale, col.Precision)
+		case "Edm.Date":
+			col.Kind = Date
+		case "Edm.DateTimeOffset":
+			col.Kind = Timestamp
+		case "Edm.Guid":
+			col.Kind = UUID
+		default:
+			// Strings, enums and anything else
+			col.Kind = String
+		}
+		columns = append(columns, col)
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("parquet: entity set %s has no columns", entitySetName)
+	}
+	return columns, nil
+}
+
+// record decodes a record with json.Number for numbers, so decimals keep their precision.
+type record map[string]any
+
+func (r *record) UnmarshalJSON(b []byte) error {
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+	var m map[string]any
+	if err := d.Decode(&m); err != nil {
+		return err
+	}
+	*r = m
+	return nil
+}
+
+func (record) Validate() error {
+	return nil
+}
+
+// Export writes every record of the entity set to w as a Parquet file, a page at a time.
+// Only a row group of records is kept in memory.
+func Export(ctx context.Context, client *bc.Client, entitySetName string, w io.Writer, opts Options) (Result, error) {
+	var result Result
+
+	columns := opts.Columns
+	if len(columns) == 0 {
+		caps := opts.Capabilities
+		if caps == nil {
+			c, err := client.Capabilities(ctx)
+			if err != nil {
+				return result, fmt.Errorf("parquet: get $metadata: %w", err)
+			}
+			caps = &c
+		}
+		var err error
+		if columns, err = Columns(*caps, entitySetName, opts.ListOptions.Select, opts); err != nil {
+			return result, err
+		}
+	}
+	result.Columns = columns
+
+	pw, err := NewWriter(w, columns, opts.Writer)
+	if err != nil {
+		return result, err
+	}
+
+	listOpts := opts.ListOptions
+	if listOpts.MaxPageSize == 0 {
+		listOpts.MaxPageSize = 1000
+	}
+	if len(listOpts.OrderBy) == 0 {
+		listOpts.OrderBy = []string{"id"}
+	}
+	page := bc.NewAPIPage[record](client, entitySetName)
+
+	var nextLink string
+	for {
+		list, err := page.ListPage(ctx, nextLink, listOpts)
+		if err != nil {
+			return result, fmt.Errorf("parquet: list %s: %w", entitySetName, err)
+		}
+		result.Pages++
+		for _, r := range list.Value {
+			if err := pw.Write(r); err != nil {
+				return result, err
+			}
+			result.Records++
+		}
+		if list.NextLink == "" {
+			break
+		}
+		nextLink = list.NextLink
+	}
+
+	return result, pw.Close()
+}
+
+// ExportDir exports each entity set to "{entitySetName}.parquet" in dir, requesting the
+// $metadata once. A file is written to a temporary name first, so a failed export does
+// not replace a previous file.
+func ExportDir(ctx context.Context, client *bc.Client, dir string, entitySetNames []string, opts Options) error {
+	opts.Columns = nil
+	if opts.Capabilities == nil {
+		caps, err := client.Capabilities(ctx)
+		if err != nil {
+			return fmt.Errorf("parquet: get $metadata: %w", err)
+		}
+		opts.Capabilities = &caps
+	}
+
+	var errs []error
+	for _, name := range entitySetNames {
+		if err := exportFile(ctx, client, filepath.Join(dir, name+".parquet"), name, opts); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func exportFile(ctx context.Context, client *bc.Client, path, entitySetName string, opts Options) error {
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := Export(ctx, client, entitySetName, f, opts); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
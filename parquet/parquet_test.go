@@ -0,0 +1,125 @@
+package parquet_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/erlorenz/bc-go/bcfake"
+	"github.com/erlorenz/bc-go/internal/bctest"
+	"github.com/erlorenz/bc-go/parquet"
+)
+
+var caps = bc.Capabilities{Properties: map[string][]bc.Property{
+	"items": {
+		{Name: "id", Type: "Edm.Guid"},
+		{Name: "number", Type: "Edm.String", Nullable: true, MaxLength: 20},
+		{Name: "type", Type: "Microsoft.NAV.itemType", Nullable: true},
+		{Name: "unitPrice", Type: "Edm.Decimal", Nullable: true, Scale: -1},
+		{Name: "inventory", Type: "Edm.Decimal", Nullable: true, Precision: 12, Scale: 3},
+		{Name: "blocked", Type: "Edm.Boolean", Nullable: true},
+		{Name: "lastModifiedDateTime", Type: "Edm.DateTimeOffset", Nullable: true},
+		{Name: "picture", Type: "Edm.Stream", Nullable: true},
+	},
+	"customers": {
+		{Name: "id", Type: "Edm.Guid"},
+		{Name: "balanceDue", Type: "Edm.Decimal", Nullable: true, Scale: -1},
+	},
+}}
+
+func TestColumns(t *testing.T) {
+	columns, err := parquet.Columns(caps, "items", nil, parquet.Options{DecimalScale: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []parquet.Column{
+		{Name: "id", Kind: parquet.UUID},
+		{Name: "number", Kind: parquet.String, Optional: true},
+		{Name: "type", Kind: parquet.String, Optional: true},
+		{Name: "unitPrice", Kind: parquet.Decimal, Optional: true, Precision: 38, Scale: 5},
+		{Name: "inventory", Kind: parquet.Decimal, Optional: true, Precision: 12, Scale: 3},
+		{Name: "blocked", Kind: parquet.Boolean, Optional: true},
+		{Name: "lastModifiedDateTime", Kind: parquet.Timestamp, Optional: true},
+	}
+	if len(columns) != len(want) {
+		t.Fatalf("wanted %d columns without the stream, got %+v", len(want), columns)
+	}
+	for i := range want {
+		if columns[i] != want[i] {
+			t.Errorf("wanted %+v, got %+v", want[i], columns[i])
+		}
+	}
+
+	selected, err := parquet.Columns(caps, "items", []string{"id", "number"}, parquet.Options{})
+	if err != nil || len(selected) != 2 {
+		t.Errorf("wanted 2 selected columns, got %+v %v", selected, err)
+	}
+
+	if _, err := parquet.Columns(caps, "vendors", nil, parquet.Options{}); err == nil {
+		t.Error("wanted error for an entity set that is not in the $metadata")
+	}
+}
+
+func TestExport(t *testing.T) {
+	server := bcfake.New()
+	for i := range 5 {
+		server.Seed("items", map[string]any{
+			"number":               "1000" + string(rune('0'+i)),
+			"type":                 "Inventory",
+			"unitPrice":            12.5,
+			"inventory":            nil,
+			"blocked":              false,
+			"lastModifiedDateTime": "2024-03-01T10:00:00Z",
+		})
+	}
+
+	var buf bytes.Buffer
+	result, err := parquet.Export(context.Background(), bctest.NewClient(t, server), "items", &buf, parquet.Options{
+		Capabilities: &caps,
+		ListOptions:  bc.ListOptions{MaxPageSize: 2},
+		Writer:       parquet.WriterOptions{RowGroupSize: 3},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Records != 5 || result.Pages != 3 {
+		t.Errorf("wanted 5 records in 3 pages, got %+v", result)
+	}
+
+	footer := readFooter(t, buf.Bytes())
+	if footer[3].(int64) != 5 || len(footer[4].([]any)) != 2 {
+		t.Errorf("wanted 5 rows in 2 row groups, got %v rows and %d groups", footer[3], len(footer[4].([]any)))
+	}
+}
+
+func TestExportDir(t *testing.T) {
+	server := bcfake.New()
+	server.Seed("items", map[string]any{"number": "1000"})
+	server.Seed("customers", map[string]any{"balanceDue": "not a number"})
+	dir := t.TempDir()
+
+	err := parquet.ExportDir(context.Background(), bctest.NewClient(t, server), dir, []string{"items", "customers"}, parquet.Options{Capabilities: &caps})
+	if err == nil {
+		t.Error("wanted error for the customers")
+	}
+
+	b, err := os.ReadFile(filepath.Join(dir, "items.parquet"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if footer := readFooter(t, b); footer[3].(int64) != 1 {
+		t.Errorf("wanted 1 row, got %v", footer[3])
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("wanted only items.parquet after the failed export, got %v", entries)
+	}
+}
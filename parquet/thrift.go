@@ -0,0 +1,101 @@
+package parquet
+
+import "encoding/binary"
+
+// Thrift compact protocol types used by the Parquet metadata.
+const (
+	thriftBoolTrue  = 1
+	thriftBoolFalse = 2
+	thriftI32       = 5
+	thriftI64       = 6
+	thriftBinary    = 8
+	thriftList      = 9
+	thriftStruct    = 12
+)
+
+// compactWriter encodes Thrift structs with the compact protocol, which is how Parquet
+// writes page headers and the footer. Only the types Parquet needs are supported.
+type compactWriter struct {
+	b []byte
+	// lastID is the last field id of each open struct, as field ids are written as deltas.
+	lastID []int16
+}
+
+// structBegin starts a struct. The top-level struct and each struct in a list need it,
+// a struct field starts one with structField.
+func (c *compactWriter) structBegin() {
+	c.lastID = append(c.lastID, 0)
+}
+
+// structEnd writes the stop field of the current struct.
+func (c *compactWriter) structEnd() {
+	c.b = append(c.b, 0)
+	c.lastID = c.lastID[:len(c.lastID)-1]
+}
+
+func (c *compactWriter) field(id int16, typ byte) {
+	last := &c.lastID[len(c.lastID)-1]
+	if delta := id - *last; delta > 0 && delta <= 15 {
+		c.b = append(c.b, byte(delta)<<4|typ)
+	} else {
+		c.b = append(c.b, typ)
+		c.b = binary.AppendUvarint(c.b, zigzag(int64(id)))
+	}
+	*last = id
+}
+
+func (c *compactWriter) i32Field(id int16, v int32) {
+	c.field(id, thriftI32)
+	c.b = binary.AppendUvarint(c.b, zigzag(int64(v)))
+}
+
+func (c *compactWriter) i64Field(id int16, v int64) {
+	c.field(id, thriftI64)
+	c.b = binary.AppendUvarint(c.b, zigzag(v))
+}
+
+func (c *compactWriter) stringField(id int16, s string) {
+	c.field(id, thriftBinary)
+	c.elemString(s)
+}
+
+func (c *compactWriter) boolField(id int16, v bool) {
+	if v {
+		c.field(id, thriftBoolTrue)
+	} else {
+		c.field(id, thriftBoolFalse)
+	}
+}
+
+// structField writes a struct field with the fields written by fn.
+func (c *compactWriter) structField(id int16, fn func()) {
+	c.field(id, thriftStruct)
+	c.structBegin()
+	fn()
+	c.structEnd()
+}
+
+// listField writes the header of a list of n elements, which must be written next with
+// the elem methods, or structBegin and structEnd for structs.
+func (c *compactWriter) listField(id int16, elemType byte, n int) {
+	c.field(id, thriftList)
+	if n < 15 {
+		c.b = append(c.b, byte(n)<<4|elemType)
+		return
+	}
+	c.b = append(c.b, 0xF0|elemType)
+	c.b = binary.AppendUvarint(c.b, uint64(n))
+}
+
+func (c *compactWriter) elemI32(v int32) {
+	c.b = binary.AppendUvarint(c.b, zigzag(int64(v)))
+}
+
+func (c *compactWriter) elemString(s string) {
+	c.b = binary.AppendUvarint(c.b, uint64(len(s)))
+	c.b = append(c.b, s...)
+}
+
+func zigzag(n int64) uint64 {
+	return uint64(n<<1) ^ uint64(n>>63)
+}
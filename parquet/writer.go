@@ -0,0 +1,600 @@
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"strconv"
+	"time"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/google/uuid"
+)
+
+// Kind is the logical type a [Column] is written as.
+type Kind int
+
+const (
+	// String is a UTF-8 BYTE_ARRAY. Values that are not strings are written as JSON.
+	String Kind = iota
+	Boolean
+	Int32
+	Int64
+	Double
+	// Decimal is a DECIMAL with the Precision and Scale of the column, stored as a FIXED_LEN_BYTE_ARRAY.
+	Decimal
+	// Date is a DATE, the days since 1970-01-01, from a "2006-01-02" string.
+	Date
+	// Timestamp is a TIMESTAMP in microseconds adjusted to UTC, from an RFC 3339 string.
+	Timestamp
+	// UUID is a UUID stored as a 16 byte FIXED_LEN_BYTE_ARRAY.
+	UUID
+)
+
+// Column is a field of the records and how it is written.
+type Column struct {
+	Name string
+	Kind Kind
+	// Optional columns can have null values. Required columns return an error for a null.
+	Optional bool
+	// Precision and Scale of a Decimal. Values with more decimals are rounded.
+	Precision int
+	Scale     int
+}
+
+// Parquet physical types.
+const (
+	typeBoolean           = 0
+	typeInt32             = 1
+	typeInt64             = 2
+	typeDouble            = 5
+	typeByteArray         = 6
+	typeFixedLenByteArray = 7
+)
+
+// Parquet encodings.
+const (
+	encodingPlain = 0
+	encodingRLE   = 3
+)
+
+// physicalType returns the Parquet type of the column and its length for FIXED_LEN_BYTE_ARRAY.
+func (col Column) physicalType() (int32, int) {
+	switch col.Kind {
+	case Boolean:
+		return typeBoolean, 0
+	case Int32, Date:
+		return typeInt32, 0
+	case Int64, Timestamp:
+		return typeInt64, 0
+	case Double:
+		return typeDouble, 0
+	case Decimal:
+		return typeFixedLenByteArray, decimalBytes(col.Precision)
+	case UUID:
+		return typeFixedLenByteArray, 16
+	default:
+		return typeByteArray, 0
+	}
+}
+
+// decimalBytes returns the fewest bytes that hold a two's complement decimal of the precision.
+func decimalBytes(precision int) int {
+	limit := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(precision)), nil)
+	n := 1
+	for new(big.Int).Lsh(big.NewInt(1), uint(8*n-1)).Cmp(limit) < 0 {
+		n++
+	}
+	return n
+}
+
+// WriterOptions configure [NewWriter].
+type WriterOptions struct {
+	// RowGroupSize is the number of rows buffered in memory before they are written
+	// as a row group. Defaults to 10000.
+	RowGroupSize int
+	// CreatedBy is written to the footer. Defaults to "bc-go" and the [bc.Version].
+	CreatedBy string
+}
+
+// Writer writes rows to a Parquet file, buffering a row group at a time.
+// Pages are uncompressed and PLAIN encoded, which every reader supports.
+// Close must be called to write the footer.
+type Writer struct {
+	w       io.Writer
+	columns []Column
+	opts    WriterOptions
+
+	offset    int64
+	buffers   []columnBuffer
+	rows      int
+	numRows   int64
+	rowGroups []rowGroup
+	closed    bool
+}
+
+type columnBuffer struct {
+	present []bool
+	bools   []bool
+	values  bytes.Buffer
+}
+
+type rowGroup struct {
+	numRows int64
+	size    int64
+	chunks  []columnChunk
+}
+
+type columnChunk struct {
+	offset int64
+	size   int64
+}
+
+// NewWriter writes the Parquet magic number to w and returns a [Writer] for the columns.
+func NewWriter(w io.Writer, columns []Column, opts WriterOptions) (*Writer, error) {
+	if len(columns) == 0 {
+		return nil, errors.New("parquet: no columns")
+	}
+	for _, col := range columns {
+		if col.Kind == Decimal && (col.Precision <= 0 || col.Scale < 0 || col.Scale > col.Precision) {
+			return nil, fmt.Errorf("parquet: column %s: invalid decimal precision %d and scale %d", col.Name, col.Precision, col.Scale)
+		}
+	}
+	if opts.RowGroupSize <= 0 {
+		opts.RowGroupSize = 10000
+	}
+	if opts.CreatedBy == "" {
+		opts.CreatedBy = "bc-go " + bc.Version()
+	}
+
+	pw := &Writer{w: w, columns: columns, opts: opts, buffers: make([]columnBuffer, len(columns))}
+	if err := pw.write([]byte("PAR1")); err != nil {
+		return nil, err
+	}
+	return pw, nil
+}
+
+func (w *Writer) write(b []byte) error {
+	n, err := w.w.Write(b)
+	w.offset += int64(n)
+	if err != nil {
+		return fmt.Errorf("parquet: write: %w", err)
+	}
+	return nil
+}
+
+// Write adds a row. Values are looked up by column name and converted to the kind of the
+// column from JSON values (string, json.Number, float64, bool and nil) or the matching Go
+// types. A missing value is null. The row group is written when it is full.
+func (w *Writer) Write(row map[string]any) error {
+	if w.closed {
+		return errors.New("parquet: write after close")
+	}
+
+	// Convert every value first so a bad row is not partly buffered
+	encoded := make([][]byte, len(w.columns))
+	bools := make([]bool, len(w.columns))
+	for i, col := range w.columns {
+		v := row[col.Name]
+		if v == nil {
+			if !col.Optional {
+				return fmt.Errorf("parquet: column %s: null value in required column", col.Name)
+			}
+			continue
+		}
+		b, bv, err := encodeValue(col, v)
+		if err != nil {
+			return fmt.Errorf("parquet: column %s: %w", col.Name, err)
+		}
+		encoded[i], bools[i] = b, bv
+	}
+
+	for i, col := range w.columns {
+		buf := &w.buffers[i]
+		present := row[col.Name] != nil
+		buf.present = append(buf.present, present)
+		if !present {
+			continue
+		}
+		if col.Kind == Boolean {
+			buf.bools = append(buf.bools, bools[i])
+		} else {
+			buf.values.Write(encoded[i])
+		}
+	}
+
+	w.rows++
+	if w.rows >= w.opts.RowGroupSize {
+		return w.flush()
+	}
+	return nil
+}
+
+// flush writes the buffered rows as a row group with one data page per column.
+func (w *Writer) flush() error {
+	if w.rows == 0 {
+		return nil
+	}
+
+	group := rowGroup{numRows: int64(w.rows)}
+	for i, col := range w.columns {
+		buf := &w.buffers[i]
+
+		var data []byte
+		if col.Optional {
+			levels := encodeLevels(buf.present)
+			data = binary.LittleEndian.AppendUint32(data, uint32(len(levels)))
+			data = append(data, levels...)
+		}
+		if col.Kind == Boolean {
+			data = append(data, packBools(buf.bools)...)
+		} else {
+			data = append(data, buf.values.Bytes()...)
+		}
+
+		header := pageHeader(w.rows, len(data))
+		chunk := columnChunk{offset: w.offset, size: int64(len(header) + len(data))}
+		if err := w.write(header); err != nil {
+			return err
+		}
+		if err := w.write(data); err != nil {
+			return err
+		}
+		group.chunks = append(group.chunks, chunk)
+		group.size += chunk.size
+
+		*buf = columnBuffer{}
+	}
+
+	w.rowGroups = append(w.rowGroups, group)
+	w.numRows += int64(w.rows)
+	w.rows = 0
+	return nil
+}
+
+// Close writes the buffered rows and the footer. It does not close the underlying writer.
+func (w *Writer) Close() error {
+	if w.closed {
+		return nil
+	}
+	if err := w.flush(); err != nil {
+		return err
+	}
+	w.closed = true
+
+	footer := w.footer()
+	footer = binary.LittleEndian.AppendUint32(footer, uint32(len(footer)))
+	footer = append(footer, "PAR1"...)
+	return w.write(footer)
+}
+
+// pageHeader encodes the header of a DATA_PAGE of n values.
+func pageHeader(n int, size int) []byte {
+	var c compactWriter
+	c.structBegin()
+	c.i32Field(1, 0) // DATA_PAGE
+	c.i32Field(2, int32(size))
+	c.i32Field(3, int32(size))
+	c.structField(5, func() {
+		c.i32Field(1, int32(n))
+		c.i32Field(2, encodingPlain)
+		c.i32Field(3, encodingRLE)
+		c.i32Field(4, encodingRLE)
+	})
+	c.structEnd()
+	return c.b
+}
+
+// footer encodes the FileMetaData.
+func (w *Writer) footer() []byte {
+	var c compactWriter
+	c.structBegin()
+	c.i32Field(1, 1)
+
+	c.listField(2, thriftStruct, len(w.columns)+1)
+	c.structBegin()
+	c.stringField(4, "schema")
+	c.i32Field(5, int32(len(w.columns)))
+	c.structEnd()
+	for _, col := range w.columns {
+		c.structBegin()
+		schemaElement(&c, col)
+		c.structEnd()
+	}
+
+	c.i64Field(3, w.numRows)
+
+	c.listField(4, thriftStruct, len(w.rowGroups))
+	for _, group := range w.rowGroups {
+		c.structBegin()
+		c.listField(1, thriftStruct, len(group.chunks))
+		for i, chunk := range group.chunks {
+			typ, _ := w.columns[i].physicalType()
+			c.structBegin()
+			c.i64Field(2, chunk.offset)
+			c.structField(3, func() {
+				c.i32Field(1, typ)
+				c.listField(2, thriftI32, 2)
+				c.elemI32(encodingPlain)
+				c.elemI32(encodingRLE)
+				c.listField(3, thriftBinary, 1)
+				c.elemString(w.columns[i].Name)
+				c.i32Field(4, 0) // UNCOMPRESSED
+				c.i64Field(5, group.numRows)
+				c.i64Field(6, chunk.size)
+				c.i64Field(7, chunk.size)
+				c.i64Field(9, chunk.offset)
+			})
+			c.structEnd()
+		}
+		c.i64Field(2, group.size)
+		c.i64Field(3, group.numRows)
+		c.structEnd()
+	}
+
+	c.stringField(6, w.opts.CreatedBy)
+	c.structEnd()
+	return c.b
+}
+
+// Converted types, for readers that predate logical types.
+const (
+	convertedUTF8            = 0
+	convertedDecimal         = 5
+	convertedDate            = 6
+	convertedTimestampMicros = 10
+)
+
+// schemaElement writes the fields of the SchemaElement of the column.
+func schemaElement(c *compactWriter, col Column) {
+	typ, length := col.physicalType()
+	c.i32Field(1, typ)
+	if length > 0 {
+		c.i32Field(2, int32(length))
+	}
+	if col.Optional {
+		c.i32Field(3, 1)
+	} else {
+		c.i32Field(3, 0)
+	}
+	c.stringField(4, col.Name)
+
+	switch col.Kind {
+	case String:
+		c.i32Field(6, convertedUTF8)
+		c.structField(10, func() { c.structField(1, func() {}) })
+	case Decimal:
+		c.i32Field(6, convertedDecimal)
+		c.i32Field(7, int32(col.Scale))
+		c.i32Field(8, int32(col.Precision))
+		c.structField(10, func() {
+			c.structField(5, func() {
+				c.i32Field(1, int32(col.Scale))
+				c.i32Field(2, int32(col.Precision))
+			})
+		})
+	case Date:
+		c.i32Field(6, convertedDate)
+		c.structField(10, func() { c.structField(6, func() {}) })
+	case Timestamp:
+		c.i32Field(6, convertedTimestampMicros)
+		c.structField(10, func() {
+			c.structField(8, func() {
+				c.boolField(1, true)
+				c.structField(2, func() { c.structField(2, func() {}) })
+			})
+		})
+	case UUID:
+		c.structField(10, func() { c.structField(14, func() {}) })
+	}
+}
+
+// encodeLevels encodes the definition levels, 1 if the value is present, with the
+// bit-packed runs of the RLE/bit-packing hybrid encoding.
+func encodeLevels(present []bool) []byte {
+	groups := (len(present) + 7) / 8
+	b := binary.AppendUvarint(nil, uint64(groups)<<1|1)
+	return append(b, packBools(present)...)
+}
+
+// packBools packs the values LSB first, as PLAIN booleans and bit-packed levels are.
+func packBools(values []bool) []byte {
+	b := make([]byte, (len(values)+7)/8)
+	for i, v := range values {
+		if v {
+			b[i/8] |= 1 << (i % 8)
+		}
+	}
+	return b
+}
+
+// encodeValue returns the PLAIN encoding of the value, or the value of a Boolean.
+func encodeValue(col Column, v any) ([]byte, bool, error) {
+	switch col.Kind {
+	case Boolean:
+		b, ok := v.(bool)
+		if !ok {
+			return nil, false, fmt.Errorf("cannot write %T as boolean", v)
+		}
+		return nil, b, nil
+	case Int32:
+		n, err := toInt(v)
+		if err != nil || n < math.MinInt32 || n > math.MaxInt32 {
+			return nil, false, fmt.Errorf("cannot write %v as int32", v)
+		}
+		return binary.LittleEndian.AppendUint32(nil, uint32(int32(n))), false, nil
+	case Int64:
+		n, err := toInt(v)
+		if err != nil {
+			return nil, false, fmt.Errorf("cannot write %v as int64", v)
+		}
+		return binary.LittleEndian.AppendUint64(nil, uint64(n)), false, nil
+	case Double:
+		f, err := toFloat(v)
+		if err != nil {
+			return nil, false, fmt.Errorf("cannot write %v as double", v)
+		}
+		return binary.LittleEndian.AppendUint64(nil, math.Float64bits(f)), false, nil
+	case Decimal:
+		b, err := encodeDecimal(col, v)
+		return b, false, err
+	case Date:
+		days, err := toDays(v)
+		if err != nil {
+			return nil, false, err
+		}
+		return binary.LittleEndian.AppendUint32(nil, uint32(int32(days))), false, nil
+	case Timestamp:
+		t, err := toTime(v)
+		if err != nil {
+			return nil, false, err
+		}
+		return binary.LittleEndian.AppendUint64(nil, uint64(t.UnixMicro())), false, nil
+	case UUID:
+		var id uuid.UUID
+		switch v := v.(type) {
+		case uuid.UUID:
+			id = v
+		case string, bc.GUID:
+			var err error
+			if id, err = uuid.Parse(fmt.Sprint(v)); err != nil {
+				return nil, false, fmt.Errorf("cannot write %q as uuid: %w", v, err)
+			}
+		default:
+			return nil, false, fmt.Errorf("cannot write %T as uuid", v)
+		}
+		return id[:], false, nil
+	default:
+		var s string
+		switch v := v.(type) {
+		case string:
+			s = v
+		case json.Number:
+			s = v.String()
+		case fmt.Stringer:
+			s = v.String()
+		default:
+			b, err := json.Marshal(v)
+			if err != nil {
+				return nil, false, err
+			}
+			s = string(b)
+		}
+		b := binary.LittleEndian.AppendUint32(nil, uint32(len(s)))
+		return append(b, s...), false, nil
+	}
+}
+
+func toInt(v any) (int64, error) {
+	switch v := v.(type) {
+	case json.Number:
+		return v.Int64()
+	case float64:
+		if v != math.Trunc(v) {
+			return 0, errors.New("not an integer")
+		}
+		return int64(v), nil
+	case int:
+		return int64(v), nil
+	case int32:
+		return int64(v), nil
+	case int64:
+		return v, nil
+	}
+	return 0, fmt.Errorf("not a number: %T", v)
+}
+
+func toFloat(v any) (float64, error) {
+	switch v := v.(type) {
+	case json.Number:
+		return v.Float64()
+	case float64:
+		return v, nil
+	case int:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	}
+	return 0, fmt.Errorf("not a number: %T", v)
+}
+
+func toDays(v any) (int64, error) {
+	var t time.Time
+	switch v := v.(type) {
+	case bc.Date:
+		t = v.TimeUTC()
+	case time.Time:
+		t = v
+	case string:
+		var err error
+		if t, err = time.Parse(time.DateOnly, v); err != nil {
+			return 0, fmt.Errorf("cannot write %q as date: %w", v, err)
+		}
+	default:
+		return 0, fmt.Errorf("cannot write %T as date", v)
+	}
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC).Unix() / 86400, nil
+}
+
+func toTime(v any) (time.Time, error) {
+	switch v := v.(type) {
+	case time.Time:
+		return v, nil
+	case string:
+		t, err := time.Parse(time.RFC3339Nano, v)
+		if err != nil {
+			return t, fmt.Errorf("cannot write %q as timestamp: %w", v, err)
+		}
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("cannot write %T as timestamp", v)
+}
+
+// encodeDecimal returns the unscaled value as big-endian two's complement, rounded half
+// away from zero to the scale.
+func encodeDecimal(col Column, v any) ([]byte, error) {
+	var s string
+	switch v := v.(type) {
+	case json.Number:
+		s = v.String()
+	case string:
+		s = v
+	case float64:
+		s = strconv.FormatFloat(v, 'f', -1, 64)
+	case int:
+		s = strconv.Itoa(v)
+	default:
+		return nil, fmt.Errorf("cannot write %T as decimal", v)
+	}
+
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return nil, fmt.Errorf("cannot write %q as decimal", s)
+	}
+	pow := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(col.Scale)), nil)
+	r.Mul(r, new(big.Rat).SetInt(pow))
+
+	num, den := r.Num(), r.Denom()
+	unscaled, rem := new(big.Int).QuoRem(num, den, new(big.Int))
+	if new(big.Int).Mul(new(big.Int).Abs(rem), big.NewInt(2)).Cmp(den) >= 0 {
+		unscaled.Add(unscaled, big.NewInt(int64(num.Sign())))
+	}
+
+	limit := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(col.Precision)), nil)
+	if new(big.Int).Abs(unscaled).Cmp(limit) >= 0 {
+		return nil, fmt.Errorf("%s does not fit decimal(%d,%d)", s, col.Precision, col.Scale)
+	}
+
+	n := decimalBytes(col.Precision)
+	if unscaled.Sign() < 0 {
+		// Two's complement of the n bytes
+		unscaled.Add(unscaled, new(big.Int).Lsh(big.NewInt(1), uint(8*n)))
+	}
+	return unscaled.FillBytes(make([]byte, n)), nil
+}
@@ -0,0 +1,311 @@
+package parquet_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"math"
+	"math/big"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/erlorenz/bc-go/parquet"
+	"github.com/google/uuid"
+)
+
+// tstruct is a decoded Thrift struct by field id.
+type tstruct map[int16]any
+
+// compactReader decodes the Thrift compact protocol to check the metadata.
+type compactReader struct {
+	t   *testing.T
+	b   []byte
+	pos int
+}
+
+func (r *compactReader) uvarint() uint64 {
+	v, n := binary.Uvarint(r.b[r.pos:])
+	if n <= 0 {
+		r.t.Fatalf("bad varint at %d", r.pos)
+	}
+	r.pos += n
+	return v
+}
+
+func (r *compactReader) varint() int64 {
+	u := r.uvarint()
+	return int64(u>>1) ^ -int64(u&1)
+}
+
+func (r *compactReader) readStruct() tstruct {
+	s := tstruct{}
+	var last int16
+	for {
+		h := r.b[r.pos]
+		r.pos++
+		if h == 0 {
+			return s
+		}
+		id := last + int16(h>>4)
+		if h>>4 == 0 {
+			id = int16(r.varint())
+		}
+		last = id
+		s[id] = r.readValue(h & 0x0f)
+	}
+}
+
+func (r *compactReader) readValue(typ byte) any {
+	switch typ {
+	case 1:
+		return true
+	case 2:
+		return false
+	case 4, 5, 6:
+		return r.varint()
+	case 8:
+		n := int(r.uvarint())
+		s := string(r.b[r.pos : r.pos+n])
+		r.pos += n
+		return s
+	case 9:
+		h := r.b[r.pos]
+		r.pos++
+		n := int(h >> 4)
+		if n == 15 {
+			n = int(r.uvarint())
+		}
+		list := make([]any, n)
+		for i := range list {
+			list[i] = r.readValue(h & 0x0f)
+		}
+		return list
+	case 12:
+		return r.readStruct()
+	}
+	r.t.Fatalf("unexpected type %d at %d", typ, r.pos)
+	return nil
+}
+
+// readFooter checks the magic numbers and returns the FileMetaData.
+func readFooter(t *testing.T, b []byte) tstruct {
+	t.Helper()
+	if !bytes.HasPrefix(b, []byte("PAR1")) || !bytes.HasSuffix(b, []byte("PAR1")) {
+		t.Fatal("missing magic number")
+	}
+	n := int(binary.LittleEndian.Uint32(b[len(b)-8:]))
+	r := &compactReader{t: t, b: b, pos: len(b) - 8 - n}
+	return r.readStruct()
+}
+
+// readValues returns the present flags and the raw values of the data page of a column chunk.
+func readValues(t *testing.T, b []byte, chunk tstruct, optional bool, rows int) ([]bool, []byte) {
+	t.Helper()
+	meta := chunk[3].(tstruct)
+	r := &compactReader{t: t, b: b, pos: int(meta[9].(int64))}
+	header := r.readStruct()
+	if header[1].(int64) != 0 || header[5].(tstruct)[1].(int64) != int64(rows) {
+		t.Fatalf("unexpected page header %v", header)
+	}
+	data := b[r.pos : r.pos+int(header[3].(int64))]
+
+	present := make([]bool, rows)
+	if !optional {
+		for i := range present {
+			present[i] = true
+		}
+		return present, data
+	}
+
+	n := int(binary.LittleEndian.Uint32(data))
+	levels := &compactReader{t: t, b: data[4 : 4+n]}
+	if h := levels.uvarint(); h&1 != 1 || int(h>>1) != (rows+7)/8 {
+		t.Fatalf("unexpected levels header %d", h)
+	}
+	for i := range present {
+		present[i] = levels.b[levels.pos+i/8]&(1<<(i%8)) != 0
+	}
+	return present, data[4+n:]
+}
+
+func TestWriter(t *testing.T) {
+	columns := []parquet.Column{
+		{Name: "id", Kind: parquet.UUID},
+		{Name: "number", Kind: parquet.String, Optional: true},
+		{Name: "blocked", Kind: parquet.Boolean},
+		{Name: "balance", Kind: parquet.Decimal, Optional: true, Precision: 18, Scale: 2},
+		{Name: "postingDate", Kind: parquet.Date},
+		{Name: "lastModifiedDateTime", Kind: parquet.Timestamp},
+		{Name: "quantity", Kind: parquet.Int32},
+		{Name: "unitCost", Kind: parquet.Double},
+	}
+	ids := []uuid.UUID{uuid.New(), uuid.New(), uuid.New()}
+	rows := []map[string]any{
+		{"id": ids[0].String(), "number": "C001", "blocked": true, "balance": json.Number("1234.565"), "postingDate": "2024-01-31",
+			"lastModifiedDateTime": "2024-01-31T12:00:00.5Z", "quantity": json.Number("3"), "unitCost": json.Number("1.25")},
+		{"id": ids[1].String(), "number": nil, "blocked": false, "balance": json.Number("-0.01"), "postingDate": "1970-01-02",
+			"lastModifiedDateTime": "1970-01-01T00:00:01Z", "quantity": json.Number("-1"), "unitCost": json.Number("0")},
+		{"id": ids[2].String(), "number": "C003", "blocked": true, "postingDate": "0001-01-01",
+			"lastModifiedDateTime": "0001-01-01T00:00:00Z", "quantity": json.Number("0"), "unitCost": json.Number("-2.5")},
+	}
+
+	var buf bytes.Buffer
+	w, err := parquet.NewWriter(&buf, columns, parquet.WriterOptions{RowGroupSize: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, row := range rows {
+		if err := w.Write(row); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	b := buf.Bytes()
+
+	footer := readFooter(t, b)
+	if footer[3].(int64) != 3 {
+		t.Errorf("wanted 3 rows, got %v", footer[3])
+	}
+
+	schema := footer[2].([]any)
+	if len(schema) != len(columns)+1 || schema[0].(tstruct)[5].(int64) != int64(len(columns)) {
+		t.Fatalf("unexpected schema %v", schema)
+	}
+	balance := schema[4].(tstruct)
+	if balance[4] != "balance" || balance[1].(int64) != 7 || balance[2].(int64) != 8 || balance[3].(int64) != 1 {
+		t.Errorf("unexpected decimal schema %v", balance)
+	}
+	decimal := balance[10].(tstruct)[5].(tstruct)
+	if decimal[1].(int64) != 2 || decimal[2].(int64) != 18 {
+		t.Errorf("unexpected decimal logical type %v", decimal)
+	}
+	if ts := schema[6].(tstruct)[10].(tstruct)[8].(tstruct); ts[1] != true {
+		t.Errorf("wanted timestamp adjusted to UTC, got %v", ts)
+	}
+	if _, ok := schema[1].(tstruct)[10].(tstruct)[14]; !ok {
+		t.Errorf("wanted UUID logical type, got %v", schema[1])
+	}
+
+	groups := footer[4].([]any)
+	if len(groups) != 2 {
+		t.Fatalf("wanted 2 row groups, got %d", len(groups))
+	}
+
+	// Decode each column of both row groups
+	var (
+		gotIDs      []uuid.UUID
+		gotNumbers  []any
+		gotBlocked  []bool
+		gotBalances []any
+		gotDates    []int32
+		gotTimes    []int64
+		gotQty      []int32
+		gotCost     []float64
+	)
+	for _, g := range groups {
+		group := g.(tstruct)
+		n := int(group[3].(int64))
+		chunks := group[1].([]any)
+		for i, col := range columns {
+			present, data := readValues(t, b, chunks[i].(tstruct), col.Optional, n)
+			pos := 0
+			for row := range n {
+				if !present[row] {
+					switch col.Name {
+					case "number":
+						gotNumbers = append(gotNumbers, nil)
+					case "balance":
+						gotBalances = append(gotBalances, nil)
+					}
+					continue
+				}
+				switch col.Kind {
+				case parquet.UUID:
+					gotIDs = append(gotIDs, uuid.UUID(data[pos:pos+16]))
+					pos += 16
+				case parquet.String:
+					l := int(binary.LittleEndian.Uint32(data[pos:]))
+					gotNumbers = append(gotNumbers, string(data[pos+4:pos+4+l]))
+					pos += 4 + l
+				case parquet.Boolean:
+					gotBlocked = append(gotBlocked, data[pos/8]&(1<<(pos%8)) != 0)
+					pos++
+				case parquet.Decimal:
+					v := new(big.Int).SetBytes(data[pos : pos+8])
+					if data[pos]&0x80 != 0 {
+						v.Sub(v, new(big.Int).Lsh(big.NewInt(1), 64))
+					}
+					gotBalances = append(gotBalances, v.Int64())
+					pos += 8
+				case parquet.Date:
+					gotDates = append(gotDates, int32(binary.LittleEndian.Uint32(data[pos:])))
+					pos += 4
+				case parquet.Timestamp:
+					gotTimes = append(gotTimes, int64(binary.LittleEndian.Uint64(data[pos:])))
+					pos += 8
+				case parquet.Int32:
+					gotQty = append(gotQty, int32(binary.LittleEndian.Uint32(data[pos:])))
+					pos += 4
+				case parquet.Double:
+					gotCost = append(gotCost, math.Float64frombits(binary.LittleEndian.Uint64(data[pos:])))
+					pos += 8
+				}
+			}
+		}
+	}
+
+	year1 := time.Date(1, 1, 1, 0, 0, 0, 0, time.UTC)
+	checks := []struct {
+		name string
+		ok   bool
+		got  any
+	}{
+		{"UUID", slices.Equal(gotIDs, ids), gotIDs},
+		{"String", slices.Equal(gotNumbers, []any{"C001", nil, "C003"}), gotNumbers},
+		{"Boolean", slices.Equal(gotBlocked, []bool{true, false, true}), gotBlocked},
+		{"Decimal", slices.Equal(gotBalances, []any{int64(123457), int64(-1), nil}), gotBalances},
+		{"Date", slices.Equal(gotDates, []int32{19753, 1, int32(year1.Unix() / 86400)}), gotDates},
+		{"Timestamp", slices.Equal(gotTimes, []int64{1706702400500000, 1000000, year1.UnixMicro()}), gotTimes},
+		{"Int32", slices.Equal(gotQty, []int32{3, -1, 0}), gotQty},
+		{"Double", slices.Equal(gotCost, []float64{1.25, 0, -2.5}), gotCost},
+	}
+	for _, c := range checks {
+		if !c.ok {
+			t.Errorf("%s: unexpected values %v", c.name, c.got)
+		}
+	}
+}
+
+func TestWriterErrors(t *testing.T) {
+	columns := []parquet.Column{
+		{Name: "id", Kind: parquet.UUID},
+		{Name: "amount", Kind: parquet.Decimal, Optional: true, Precision: 4, Scale: 2},
+	}
+	w, err := parquet.NewWriter(&bytes.Buffer{}, columns, parquet.WriterOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		row  map[string]any
+	}{
+		{"RequiredNull", map[string]any{"amount": json.Number("1")}},
+		{"BadUUID", map[string]any{"id": "abc"}},
+		{"DecimalOverflow", map[string]any{"id": uuid.NewString(), "amount": json.Number("100.00")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := w.Write(tt.row); err == nil {
+				t.Error("wanted error")
+			}
+		})
+	}
+
+	if _, err := parquet.NewWriter(&bytes.Buffer{}, []parquet.Column{{Name: "x", Kind: parquet.Decimal}}, parquet.WriterOptions{}); err == nil {
+		t.Error("wanted error for a decimal without precision")
+	}
+}
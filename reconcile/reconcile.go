@@ -0,0 +1,312 @@
+// Package reconcile matches incoming customer payments and outgoing vendor payments,
+// e.g. the lines of a bank statement, to open invoices and turns the matches into
+// payment journal lines that apply to the invoices:
+//
+//	invoices, err := reconcile.OpenSalesInvoices(ctx, api, "")
+//	result := reconcile.Reconcile(payments, invoices, reconcile.Options{})
+//	lines, err := reconcile.AddCustomerPayments(ctx, api, journalID, result.CustomerPayments())
+//
+// The standard API v2.0 has no customer or vendor ledger entries, so the open invoices are
+// the posted salesInvoices with a remainingAmount. Posting the journal is left to BC.
+package reconcile
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/erlorenz/bc-go/filter"
+	"github.com/erlorenz/bc-go/models"
+	"github.com/google/uuid"
+)
+
+// Payment is a received or sent payment.
+type Payment struct {
+	// Reference is the remittance information, e.g. "Invoice PS-INV103001, PS-INV103002".
+	Reference string
+	// PartyNumber is the customer or vendor number if it is known. When set, only its
+	// invoices match.
+	PartyNumber string
+	// Amount is the paid amount, a positive number.
+	Amount float64
+	// Date is the posting date of the journal line.
+	Date time.Time
+}
+
+// Invoice is an open invoice of a customer or vendor.
+type Invoice struct {
+	ID                     uuid.UUID
+	Number                 string
+	ExternalDocumentNumber string
+	PartyNumber            string
+	RemainingAmount        float64
+	DueDate                bc.Date
+}
+
+// InvoicesFromSales converts sales invoices to Invoices of their bill-to customer.
+func InvoicesFromSales(invoices []models.SalesInvoice) []Invoice {
+	result := make([]Invoice, 0, len(invoices))
+	for _, si := range invoices {
+		result = append(result, Invoice{
+			ID:                     si.ID,
+			Number:                 si.Number,
+			ExternalDocumentNumber: si.ExternalDocumentNumber,
+			PartyNumber:            cmp.Or(si.BillToCustomerNumber, si.CustomerNumber),
+			RemainingAmount:        si.RemainingAmount,
+			DueDate:                si.DueDate,
+		})
+	}
+	return result
+}
+
+// OpenSalesInvoices returns the posted sales invoices with a remaining amount, of the
+// customer if customerNumber is not empty, ordered by due date.
+func OpenSalesInvoices(ctx context.Context, api *models.Client, customerNumber string) ([]Invoice, error) {
+	f := "status eq 'Open' and remainingAmount gt 0"
+	if customerNumber != "" {
+		f += " and billToCustomerNumber eq " + filter.Literal(customerNumber)
+	}
+	invoices, err := api.SalesInvoices().List(ctx, bc.ListOptions{Filter: f, OrderBy: []string{"dueDate", "number"}})
+	if err != nil {
+		return nil, fmt.Errorf("open sales invoices: %w", err)
+	}
+	return InvoicesFromSales(invoices), nil
+}
+
+// Rule is how a payment was matched to an invoice.
+type Rule string
+
+const (
+	// RuleReferenceAmount matches the invoices in the reference whose remaining amounts
+	// add up to the payment.
+	RuleReferenceAmount Rule = "reference and amount"
+	// RuleReference matches the single invoice in the reference with another remaining
+	// amount, a partial payment or an overpayment.
+	RuleReference Rule = "reference"
+	// RuleAmount matches the single open invoice with the remaining amount of a payment
+	// without a usable reference.
+	RuleAmount Rule = "amount"
+)
+
+// Match applies a payment, or part of it, to an invoice.
+type Match struct {
+	Payment Payment
+	Invoice Invoice
+	Rule    Rule
+	// Amount is the part of the payment applied to the invoice.
+	Amount float64
+}
+
+// Result of [Reconcile].
+type Result struct {
+	Matches   []Match
+	Unmatched []Payment
+}
+
+// Options configure [Reconcile].
+type Options struct {
+	// Tolerance is the largest difference between amounts that are equal. Defaults to 0.005.
+	Tolerance float64
+}
+
+// Reconcile matches the payments to the invoices. A payment matches by the invoice
+// numbers or external document numbers in its reference first, and only by amount if
+// exactly one open invoice has it, so an ambiguous payment stays unmatched for a person
+// to look at. Each invoice is matched once.
+func Reconcile(payments []Payment, invoices []Invoice, opts Options) Result {
+	if opts.Tolerance <= 0 {
+		opts.Tolerance = 0.005
+	}
+	equal := func(a, b float64) bool { return math.Abs(a-b) <= opts.Tolerance }
+
+	used := make([]bool, len(invoices))
+	open := func(p Payment) []int {
+		var idx []int
+		for i, inv := range invoices {
+			if !used[i] && (p.PartyNumber == "" || strings.EqualFold(p.PartyNumber, inv.PartyNumber)) {
+				idx = append(idx, i)
+			}
+		}
+		return idx
+	}
+
+	var result Result
+	var byAmount []Payment
+	for _, p := range payments {
+		tokens := referenceTokens(p.Reference)
+		var referenced []int
+		var sum float64
+		for _, i := range open(p) {
+			if tokens[normalize(invoices[i].Number)] || tokens[normalize(invoices[i].ExternalDocumentNumber)] {
+				referenced = append(referenced, i)
+				sum += invoices[i].RemainingAmount
+			}
+		}
+		switch {
+		case len(referenced) > 0 && equal(sum, p.Amount):
+			for _, i := range referenced {
+				used[i] = true
+				result.Matches = append(result.Matches, Match{Payment: p, Invoice: invoices[i], Rule: RuleReferenceAmount, Amount: invoices[i].RemainingAmount})
+			}
+		case len(referenced) == 1:
+			i := referenced[0]
+			used[i] = true
+			result.Matches = append(result.Matches, Match{Payment: p, Invoice: invoices[i], Rule: RuleReference, Amount: p.Amount})
+		default:
+			byAmount = append(byAmount, p)
+		}
+	}
+
+	// Amounts are matched after all references, so a payment without a reference does
+	// not take the invoice that a later payment names.
+	for _, p := range byAmount {
+		var same []int
+		for _, i := range open(p) {
+			if equal(invoices[i].RemainingAmount, p.Amount) {
+				same = append(same, i)
+			}
+		}
+		if len(same) != 1 {
+			result.Unmatched = append(result.Unmatched, p)
+			continue
+		}
+		used[same[0]] = true
+		result.Matches = append(result.Matches, Match{Payment: p, Invoice: invoices[same[0]], Rule: RuleAmount, Amount: p.Amount})
+	}
+	return result
+}
+
+// CustomerPaymentLine is the body that creates a customerPayments line applied to an invoice.
+type CustomerPaymentLine struct {
+	CustomerNumber         string    `json:"customerNumber"`
+	PostingDate            *bc.Date  `json:"postingDate,omitempty"`
+	ExternalDocumentNumber string    `json:"externalDocumentNumber,omitempty"`
+	Amount                 float64   `json:"amount"`
+	AppliesToInvoiceID     uuid.UUID `json:"appliesToInvoiceId"`
+	Description            string    `json:"description,omitempty"`
+}
+
+// VendorPaymentLine is the body that creates a vendorPayments line applied to an invoice.
+type VendorPaymentLine struct {
+	VendorNumber           string    `json:"vendorNumber"`
+	PostingDate            *bc.Date  `json:"postingDate,omitempty"`
+	ExternalDocumentNumber string    `json:"externalDocumentNumber,omitempty"`
+	Amount                 float64   `json:"amount"`
+	AppliesToInvoiceID     uuid.UUID `json:"appliesToInvoiceId"`
+	Description            string    `json:"description,omitempty"`
+}
+
+// CustomerPayments returns a journal line per match. The amounts are negative, as a
+// payment credits the customer.
+func (r Result) CustomerPayments() []CustomerPaymentLine {
+	lines := make([]CustomerPaymentLine, 0, len(r.Matches))
+	for _, m := range r.Matches {
+		lines = append(lines, CustomerPaymentLine{
+			CustomerNumber:         m.Invoice.PartyNumber,
+			PostingDate:            postingDate(m.Payment.Date),
+			ExternalDocumentNumber: m.Invoice.ExternalDocumentNumber,
+			Amount:                 -m.Amount,
+			AppliesToInvoiceID:     m.Invoice.ID,
+			Description:            description(m),
+		})
+	}
+	return lines
+}
+
+// VendorPayments returns a journal line per match. The amounts are positive, as a
+// payment debits the vendor.
+func (r Result) VendorPayments() []VendorPaymentLine {
+	lines := make([]VendorPaymentLine, 0, len(r.Matches))
+	for _, m := range r.Matches {
+		lines = append(lines, VendorPaymentLine{
+			VendorNumber:           m.Invoice.PartyNumber,
+			PostingDate:            postingDate(m.Payment.Date),
+			ExternalDocumentNumber: m.Invoice.ExternalDocumentNumber,
+			Amount:                 m.Amount,
+			AppliesToInvoiceID:     m.Invoice.ID,
+			Description:            description(m),
+		})
+	}
+	return lines
+}
+
+// AddCustomerPayments creates the lines in the customer payment journal. It stops at the
+// first error and returns the lines created before it.
+func AddCustomerPayments(ctx context.Context, api *models.Client, journalID uuid.UUID, lines []CustomerPaymentLine) ([]models.CustomerPayment, error) {
+	page := api.CustomerPaymentJournals().Payments(journalID)
+	created := make([]models.CustomerPayment, 0, len(lines))
+	for _, l := range lines {
+		p, err := page.Create(ctx, l, bc.GetOptions{})
+		if err != nil {
+			return created, fmt.Errorf("add customer payment for invoice %s: %w", l.AppliesToInvoiceID, err)
+		}
+		created = append(created, p)
+	}
+	return created, nil
+}
+
+// AddVendorPayments creates the lines in the vendor payment journal. It stops at the
+// first error and returns the lines created before it.
+func AddVendorPayments(ctx context.Context, api *models.Client, journalID uuid.UUID, lines []VendorPaymentLine) ([]models.VendorPayment, error) {
+	page := api.VendorPaymentJournals().Payments(journalID)
+	created := make([]models.VendorPayment, 0, len(lines))
+	for _, l := range lines {
+		p, err := page.Create(ctx, l, bc.GetOptions{})
+		if err != nil {
+			return created, fmt.Errorf("add vendor payment for invoice %s: %w", l.AppliesToInvoiceID, err)
+		}
+		created = append(created, p)
+	}
+	return created, nil
+}
+
+func postingDate(t time.Time) *bc.Date {
+	if t.IsZero() {
+		return nil
+	}
+	d := bc.DateOf(t)
+	return &d
+}
+
+// description fits the Text[100] description of a journal line.
+func description(m Match) string {
+	s := "Payment " + m.Invoice.Number
+	if m.Payment.Reference != "" {
+		s += ": " + m.Payment.Reference
+	}
+	if r := []rune(s); len(r) > 100 {
+		s = string(r[:100])
+	}
+	return s
+}
+
+// referenceTokens splits the reference at spaces and punctuation other than the dashes
+// and slashes of document numbers, and normalizes the parts.
+func referenceTokens(reference string) map[string]bool {
+	fields := strings.FieldsFunc(reference, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-' && r != '/' && r != '_' && r != '.'
+	})
+	tokens := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		if n := normalize(strings.TrimRight(f, ".")); n != "" {
+			tokens[n] = true
+		}
+	}
+	return tokens
+}
+
+// normalize compares document numbers without case and separators,
+// so "PS-INV103001" matches "ps-inv103001" and "PSINV103001".
+func normalize(no string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToUpper(r)
+		}
+		return -1
+	}, no)
+}
@@ -0,0 +1,151 @@
+package reconcile_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/erlorenz/bc-go/internal/bctest"
+	"github.com/erlorenz/bc-go/models"
+	"github.com/erlorenz/bc-go/reconcile"
+	"github.com/google/uuid"
+)
+
+var invoices = []reconcile.Invoice{
+	{ID: uuid.New(), Number: "PS-INV103001", PartyNumber: "10000", RemainingAmount: 1200},
+	{ID: uuid.New(), Number: "PS-INV103002", PartyNumber: "10000", RemainingAmount: 300.5},
+	{ID: uuid.New(), Number: "PS-INV103003", ExternalDocumentNumber: "WEB-7731", PartyNumber: "20000", RemainingAmount: 99.99},
+	{ID: uuid.New(), Number: "PS-INV103004", PartyNumber: "30000", RemainingAmount: 450},
+	{ID: uuid.New(), Number: "PS-INV103005", PartyNumber: "40000", RemainingAmount: 75},
+	{ID: uuid.New(), Number: "PS-INV103006", PartyNumber: "50000", RemainingAmount: 75},
+}
+
+func TestReconcile(t *testing.T) {
+	payments := []reconcile.Payment{
+		{Reference: "Inv. PS-INV103001, ps-inv103002.", Amount: 1500.5},
+		{Reference: "order web-7731", Amount: 99.99},
+		{Reference: "PS-INV103004 first installment", Amount: 200},
+		{Reference: "thanks", Amount: 75},
+		{Reference: "PS-INV10300", Amount: 42},
+	}
+
+	result := reconcile.Reconcile(payments, invoices, reconcile.Options{})
+
+	table := []struct {
+		number string
+		rule   reconcile.Rule
+		amount float64
+	}{
+		{"PS-INV103001", reconcile.RuleReferenceAmount, 1200},
+		{"PS-INV103002", reconcile.RuleReferenceAmount, 300.5},
+		{"PS-INV103003", reconcile.RuleReferenceAmount, 99.99},
+		{"PS-INV103004", reconcile.RuleReference, 200},
+	}
+	if len(result.Matches) != len(table) {
+		t.Fatalf("wanted %d matches, got %+v", len(table), result.Matches)
+	}
+	for i, want := range table {
+		m := result.Matches[i]
+		if m.Invoice.Number != want.number || m.Rule != want.rule || m.Amount != want.amount {
+			t.Errorf("match %d: wanted %s by %s for %v, got %s by %s for %v", i, want.number, want.rule, want.amount, m.Invoice.Number, m.Rule, m.Amount)
+		}
+	}
+	// 75 is ambiguous and PS-INV10300 is only a prefix of a number.
+	if len(result.Unmatched) != 2 || result.Unmatched[0].Amount != 75 || result.Unmatched[1].Amount != 42 {
+		t.Errorf("unexpected unmatched %+v", result.Unmatched)
+	}
+}
+
+func TestReconcileByAmount(t *testing.T) {
+	payments := []reconcile.Payment{
+		{PartyNumber: "50000", Amount: 75.001},
+		// Named by the later payment, so not taken by amount.
+		{Amount: 300.5},
+		{Reference: "PS-INV103002", Amount: 300.5},
+	}
+
+	result := reconcile.Reconcile(payments, invoices, reconcile.Options{})
+
+	if len(result.Matches) != 2 {
+		t.Fatalf("wanted 2 matches, got %+v", result.Matches)
+	}
+	if m := result.Matches[0]; m.Invoice.Number != "PS-INV103002" || m.Rule != reconcile.RuleReferenceAmount {
+		t.Errorf("wanted PS-INV103002 by reference first, got %s by %s", m.Invoice.Number, m.Rule)
+	}
+	if m := result.Matches[1]; m.Invoice.Number != "PS-INV103006" || m.Rule != reconcile.RuleAmount {
+		t.Errorf("wanted PS-INV103006 of the customer by amount, got %s by %s", m.Invoice.Number, m.Rule)
+	}
+	if len(result.Unmatched) != 1 || result.Unmatched[0].Reference != "" {
+		t.Errorf("unexpected unmatched %+v", result.Unmatched)
+	}
+}
+
+func TestResultPaymentLines(t *testing.T) {
+	date := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	result := reconcile.Reconcile([]reconcile.Payment{{Reference: "WEB-7731", Amount: 99.99, Date: date}}, invoices, reconcile.Options{})
+
+	customer := result.CustomerPayments()
+	if len(customer) != 1 {
+		t.Fatalf("wanted 1 line, got %d", len(customer))
+	}
+	b, err := json.Marshal(customer[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"customerNumber":"20000","postingDate":"2026-03-02","externalDocumentNumber":"WEB-7731","amount":-99.99,"appliesToInvoiceId":"` + invoices[2].ID.String() + `","description":"Payment PS-INV103003: WEB-7731"}`
+	if string(b) != want {
+		t.Errorf("wanted %s, got %s", want, b)
+	}
+
+	vendor := result.VendorPayments()
+	if len(vendor) != 1 || vendor[0].Amount != 99.99 || vendor[0].VendorNumber != "20000" {
+		t.Errorf("unexpected vendor lines %+v", vendor)
+	}
+}
+
+func TestOpenSalesInvoicesAndAddCustomerPayments(t *testing.T) {
+	journalID := uuid.New()
+	invoiceID := uuid.New()
+	st := &bctest.SequenceTransport{Responses: []*http.Response{
+		bctest.NewResponse(200, map[string]any{"value": []map[string]any{
+			{"id": invoiceID, "number": "PS-INV103001", "customerNumber": "10000", "billToCustomerNumber": "10000", "remainingAmount": 1200, "status": "Open"},
+		}}),
+		bctest.NewResponse(201, map[string]any{"id": uuid.New(), "journalId": journalID, "customerNumber": "10000", "amount": -1200, "appliesToInvoiceId": invoiceID}),
+	}}
+	api := models.NewClient(bctest.NewClient(t, st))
+	ctx := context.Background()
+
+	open, err := reconcile.OpenSalesInvoices(ctx, api, "10000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := st.Requests[0].URL.Query().Get("$filter"); got != "status eq 'Open' and remainingAmount gt 0 and billToCustomerNumber eq '10000'" {
+		t.Errorf("unexpected filter %q", got)
+	}
+
+	result := reconcile.Reconcile([]reconcile.Payment{{Reference: "PS-INV103001", Amount: 1200}}, open, reconcile.Options{})
+	created, err := reconcile.AddCustomerPayments(ctx, api, journalID, result.CustomerPayments())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(created) != 1 || created[0].AppliesToInvoiceID != invoiceID {
+		t.Errorf("unexpected created lines %+v", created)
+	}
+
+	r := st.Requests[1]
+	if want := "/customerPaymentJournals(" + journalID.String() + ")/customerPayments"; r.Method != http.MethodPost || !strings.HasSuffix(r.URL.Path, want) {
+		t.Errorf("wanted POST %s, got %s %s", want, r.Method, r.URL.Path)
+	}
+	body, _ := io.ReadAll(r.Body)
+	var line map[string]any
+	if err := json.Unmarshal(body, &line); err != nil {
+		t.Fatal(err)
+	}
+	if line["amount"] != -1200.0 || line["appliesToInvoiceId"] != invoiceID.String() {
+		t.Errorf("unexpected body %s", body)
+	}
+}
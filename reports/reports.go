@@ -0,0 +1,234 @@
+// Package reports lists report layouts and runs reports through the API, and downloads
+// the PDF of the standard documents.
+//
+// The standard API v2.0 only renders documents, e.g. the pdfDocument of a sales invoice,
+// see [DocumentPDF]. Other reports need an extension API with a page on the report
+// layouts and one on report runs: a POST to the runs page inserts a run that a job queue
+// entry renders with Report.SaveAs into a Media field, and sets the status to Completed
+// or Failed. Reports that finish within the request can set it to Completed on insert.
+//
+//	r := reports.New(client, reports.Options{})
+//	run, err := r.Execute(ctx, reports.Request{ReportID: 6, Format: reports.PDF}, file)
+package reports
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/google/uuid"
+)
+
+// ErrReportFailed is returned when BC failed to render the report.
+var ErrReportFailed = errors.New("report failed")
+
+// Format is the output format of a run.
+type Format string
+
+// Formats of Report.SaveAs.
+const (
+	PDF   Format = "PDF"
+	Excel Format = "Excel"
+	Word  Format = "Word"
+	XML   Format = "XML"
+)
+
+// Status is the state of a run.
+type Status string
+
+// Statuses of a run.
+const (
+	StatusQueued    Status = "Queued"
+	StatusRunning   Status = "Running"
+	StatusCompleted Status = "Completed"
+	StatusFailed    Status = "Failed"
+)
+
+// Layout is a record of the "Report Layout List" table.
+type Layout struct {
+	ID          uuid.UUID `json:"id"`
+	ReportID    int       `json:"reportId"`
+	ReportName  string    `json:"reportName"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Format      string    `json:"layoutFormat"`
+	Default     bool      `json:"default"`
+}
+
+// Validate implements the [bc.Validator] interface.
+func (l Layout) Validate() error {
+	return bc.ValidateStruct(l)
+}
+
+// Run is a request to render a report and its result.
+type Run struct {
+	ID                   uuid.UUID `json:"id"`
+	ReportID             int       `json:"reportId"`
+	LayoutName           string    `json:"layoutName"`
+	Format               Format    `json:"format"`
+	Status               Status    `json:"status"`
+	ErrorMessage         string    `json:"errorMessage"`
+	FileName             string    `json:"fileName"`
+	LastModifiedDateTime time.Time `json:"lastModifiedDateTime"`
+}
+
+// Validate implements the [bc.Validator] interface.
+func (r Run) Validate() error {
+	return bc.ValidateStruct(r)
+}
+
+// Done returns true if the run completed or failed.
+func (r Run) Done() bool {
+	return r.Status == StatusCompleted || r.Status == StatusFailed
+}
+
+// Request is the body that starts a run.
+type Request struct {
+	ReportID   int    `json:"reportId"`
+	LayoutName string `json:"layoutName,omitempty"`
+	Format     Format `json:"format,omitempty"`
+	// Parameters are the request page parameters as XML, like Report.RunRequestPage
+	// returns them. Empty runs the report with its defaults.
+	Parameters string `json:"parameters,omitempty"`
+}
+
+// Options configure [New].
+type Options struct {
+	// LayoutsEntitySetName defaults to "reportLayouts".
+	LayoutsEntitySetName string
+	// RunsEntitySetName defaults to "reportRuns".
+	RunsEntitySetName string
+	// ContentField is the Media field of a run with the output. Defaults to "content".
+	ContentField string
+	// PollInterval is the wait between reads of a run that is not done. Defaults to 5s.
+	PollInterval time.Duration
+	// Clock waits the PollInterval. Defaults to the [bc.SystemClock].
+	Clock bc.Clock
+}
+
+// Client lists layouts and runs reports of an extension API.
+type Client struct {
+	client *bc.Client
+	opts   Options
+}
+
+// New creates a Client. The bc.Client must use the extension API with the report pages.
+func New(client *bc.Client, opts Options) *Client {
+	opts.LayoutsEntitySetName = cmp.Or(opts.LayoutsEntitySetName, "reportLayouts")
+	opts.RunsEntitySetName = cmp.Or(opts.RunsEntitySetName, "reportRuns")
+	opts.ContentField = cmp.Or(opts.ContentField, "content")
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = 5 * time.Second
+	}
+	if opts.Clock == nil {
+		opts.Clock = bc.SystemClock
+	}
+	return &Client{client: client, opts: opts}
+}
+
+// Layouts returns the layouts page.
+func (c *Client) Layouts() *bc.APIPage[Layout] {
+	return bc.NewAPIPage[Layout](c.client, c.opts.LayoutsEntitySetName)
+}
+
+// Runs returns the runs page.
+func (c *Client) Runs() *bc.APIPage[Run] {
+	return bc.NewAPIPage[Run](c.client, c.opts.RunsEntitySetName)
+}
+
+// LayoutsOf returns the layouts of the report, the default first.
+func (c *Client) LayoutsOf(ctx context.Context, reportID int) ([]Layout, error) {
+	layouts, err := c.Layouts().List(ctx, bc.ListOptions{
+		Filter:  "reportId eq " + strconv.Itoa(reportID),
+		OrderBy: []string{"default desc", "name"},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("layouts of report %d: %w", reportID, err)
+	}
+	return layouts, nil
+}
+
+// Start inserts a run. It returns without waiting for the report.
+func (c *Client) Start(ctx context.Context, req Request) (Run, error) {
+	run, err := c.Runs().Create(ctx, req, bc.GetOptions{})
+	if err != nil {
+		return Run{}, fmt.Errorf("start report %d: %w", req.ReportID, err)
+	}
+	c.client.Logger().Debug("Started report run.", "reportId", req.ReportID, "id", run.ID, "status", run.Status)
+	return run, nil
+}
+
+// Wait reads the run every PollInterval until it is done. A failed run returns an error
+// matching [ErrReportFailed] with the message of BC.
+func (c *Client) Wait(ctx context.Context, id uuid.UUID) (Run, error) {
+	for {
+		run, err := c.Runs().Get(ctx, id, bc.GetOptions{})
+		if err != nil {
+			return run, fmt.Errorf("wait for report run %s: %w", id, err)
+		}
+		if err := runErr(run); err != nil || run.Done() {
+			return run, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return run, fmt.Errorf("wait for report run %s: %w", id, ctx.Err())
+		case <-c.opts.Clock.After(c.opts.PollInterval):
+		}
+	}
+}
+
+// Download writes the output of a completed run to w.
+func (c *Client) Download(ctx context.Context, id uuid.UUID, w io.Writer) (bc.MediaResult, error) {
+	path := fmt.Sprintf("%s(%s)/%s", c.opts.RunsEntitySetName, id, c.opts.ContentField)
+	result, err := c.client.DownloadMedia(ctx, path, w, bc.MediaOptions{})
+	if err != nil {
+		return result, fmt.Errorf("download report run %s: %w", id, err)
+	}
+	return result, nil
+}
+
+// Execute starts a run, waits for it unless it completed on insert, and writes the output to w.
+func (c *Client) Execute(ctx context.Context, req Request, w io.Writer) (Run, error) {
+	run, err := c.Start(ctx, req)
+	if err != nil {
+		return run, err
+	}
+	if err := runErr(run); err != nil {
+		return run, err
+	}
+	if !run.Done() {
+		if run, err = c.Wait(ctx, run.ID); err != nil {
+			return run, err
+		}
+	}
+	if _, err := c.Download(ctx, run.ID, w); err != nil {
+		return run, err
+	}
+	return run, nil
+}
+
+// runErr returns an error matching [ErrReportFailed] for a failed run.
+func runErr(run Run) error {
+	if run.Status != StatusFailed {
+		return nil
+	}
+	return fmt.Errorf("%w: report %d run %s: %s", ErrReportFailed, run.ReportID, run.ID, run.ErrorMessage)
+}
+
+// DocumentPDF writes the PDF of a document of the standard API to w, e.g. a posted sales
+// invoice with entitySetName "salesInvoices". BC renders it with the report selection
+// of the document type.
+func DocumentPDF(ctx context.Context, client *bc.Client, entitySetName string, id uuid.UUID, w io.Writer) (bc.MediaResult, error) {
+	path := fmt.Sprintf("%s(%s)/pdfDocument/pdfDocumentContent", entitySetName, id)
+	result, err := client.DownloadMedia(ctx, path, w, bc.MediaOptions{})
+	if err != nil {
+		return result, fmt.Errorf("download pdf of %s %s: %w", entitySetName, id, err)
+	}
+	return result, nil
+}
@@ -0,0 +1,131 @@
+package reports_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/erlorenz/bc-go/bcmock"
+	"github.com/erlorenz/bc-go/internal/bctest"
+	"github.com/erlorenz/bc-go/reports"
+	"github.com/google/uuid"
+)
+
+func newClient(t *testing.T, rt http.RoundTripper) *bc.Client {
+	t.Helper()
+	config := bctest.ClientConfig()
+	config.APIEndpoint = "contoso/reporting/v1.0"
+	return bctest.NewClientConfig(t, config, rt)
+}
+
+func pdfResponse(content string) *http.Response {
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": {"application/pdf"}},
+		Body:       io.NopCloser(strings.NewReader(content)),
+	}
+}
+
+func TestExecuteWaitsForRun(t *testing.T) {
+	id := uuid.New()
+	run := func(status reports.Status) *http.Response {
+		return bctest.NewResponse(200, map[string]any{"id": id, "reportId": 6, "status": status})
+	}
+	st := &bctest.SequenceTransport{Responses: []*http.Response{
+		run(reports.StatusQueued),
+		run(reports.StatusRunning),
+		run(reports.StatusCompleted),
+		pdfResponse("%PDF-1.7"),
+	}}
+	clock := bcmock.NewClock(time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC))
+	r := reports.New(newClient(t, st), reports.Options{Clock: clock})
+
+	go func() {
+		clock.BlockUntil(1)
+		clock.Advance(5 * time.Second)
+	}()
+
+	var buf bytes.Buffer
+	got, err := r.Execute(context.Background(), reports.Request{ReportID: 6, Format: reports.PDF}, &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Status != reports.StatusCompleted || buf.String() != "%PDF-1.7" {
+		t.Errorf("unexpected run %+v with output %q", got, buf.String())
+	}
+
+	table := []struct {
+		method string
+		path   string
+	}{
+		{http.MethodPost, "/reportRuns"},
+		{http.MethodGet, "/reportRuns(" + id.String() + ")"},
+		{http.MethodGet, "/reportRuns(" + id.String() + ")"},
+		{http.MethodGet, "/reportRuns(" + id.String() + ")/content"},
+	}
+	if len(st.Requests) != len(table) {
+		t.Fatalf("wanted %d requests, got %d", len(table), len(st.Requests))
+	}
+	for i, want := range table {
+		r := st.Requests[i]
+		if r.Method != want.method || !strings.HasSuffix(r.URL.Path, want.path) {
+			t.Errorf("request %d: wanted %s %s, got %s %s", i, want.method, want.path, r.Method, r.URL.Path)
+		}
+	}
+}
+
+func TestExecuteFailedRun(t *testing.T) {
+	st := &bctest.SequenceTransport{Responses: []*http.Response{
+		bctest.NewResponse(201, map[string]any{"id": uuid.New(), "reportId": 6, "status": "Failed", "errorMessage": "The layout does not exist."}),
+	}}
+	r := reports.New(newClient(t, st), reports.Options{})
+
+	_, err := r.Execute(context.Background(), reports.Request{ReportID: 6, LayoutName: "Missing"}, io.Discard)
+	if !errors.Is(err, reports.ErrReportFailed) || !strings.Contains(err.Error(), "The layout does not exist.") {
+		t.Errorf("wanted ErrReportFailed with the message, got %v", err)
+	}
+	if st.Count() != 1 {
+		t.Errorf("wanted no download, got %d requests", st.Count())
+	}
+}
+
+func TestWaitCanceled(t *testing.T) {
+	st := &bctest.SequenceTransport{Responses: []*http.Response{
+		bctest.NewResponse(200, map[string]any{"id": uuid.New(), "status": "Running"}),
+	}}
+	clock := bcmock.NewClock(time.Now())
+	r := reports.New(newClient(t, st), reports.Options{Clock: clock})
+	ctx, cancel := context.WithCancel(context.Background())
+
+	go func() {
+		clock.BlockUntil(1)
+		cancel()
+	}()
+
+	if _, err := r.Wait(ctx, uuid.New()); !errors.Is(err, context.Canceled) {
+		t.Errorf("wanted context.Canceled, got %v", err)
+	}
+}
+
+func TestDocumentPDF(t *testing.T) {
+	id := uuid.New()
+	st := &bctest.SequenceTransport{Responses: []*http.Response{pdfResponse("%PDF-1.4")}}
+
+	var buf bytes.Buffer
+	result, err := reports.DocumentPDF(context.Background(), newClient(t, st), "salesInvoices", id, &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "%PDF-1.4" || result.ContentType != "application/pdf" {
+		t.Errorf("unexpected result %+v with output %q", result, buf.String())
+	}
+	if want := "/salesInvoices(" + id.String() + ")/pdfDocument/pdfDocumentContent"; !strings.HasSuffix(st.Requests[0].URL.Path, want) {
+		t.Errorf("wanted path ending %s, got %s", want, st.Requests[0].URL.Path)
+	}
+}
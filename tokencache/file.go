@@ -0,0 +1,110 @@
+package tokencache
+
+import (
+	"context"
+	"crypto/cipher"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/erlorenz/bc-go/bc"
+)
+
+// File is a [bc.TokenCache] that stores every token in a single AES-GCM encrypted file,
+// for processes on the same host. The file is replaced atomically on each Set.
+type File struct {
+	// Clock decides if a token has expired. Defaults to the [bc.SystemClock].
+	Clock bc.Clock
+
+	path string
+	aead cipher.AEAD
+	mu   sync.Mutex
+}
+
+// NewFile creates a File cache at path encrypted with key, which must be 16, 24 or 32 bytes.
+// The file is created on the first Set.
+func NewFile(path string, key []byte) (*File, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	return &File{path: path, aead: aead}, nil
+}
+
+// Get implements [bc.TokenCache].
+func (f *File) Get(_ context.Context, key string) (bc.AccessToken, time.Time, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	entries, err := f.load()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	e, ok := entries[key]
+	if !ok || e.expired(now(f.Clock)) {
+		return "", time.Time{}, bc.ErrTokenCacheMiss
+	}
+	return e.Token, e.ExpiresOn, nil
+}
+
+// Set implements [bc.TokenCache]. Expired tokens are removed.
+func (f *File) Set(_ context.Context, key string, token bc.AccessToken, expiresOn time.Time) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	entries, err := f.load()
+	if err != nil {
+		// A corrupt or undecryptable file is replaced.
+		entries = map[string]entry{}
+	}
+	t := now(f.Clock)
+	for k, e := range entries {
+		if e.expired(t) {
+			delete(entries, k)
+		}
+	}
+	entries[key] = entry{Token: token, ExpiresOn: expiresOn}
+
+	data, err := seal(f.aead, entries)
+	if err != nil {
+		return fmt.Errorf("encrypt token cache: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(f.path), filepath.Base(f.path)+".*")
+	if err != nil {
+		return fmt.Errorf("write token cache: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write token cache: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("write token cache: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), f.path); err != nil {
+		return fmt.Errorf("write token cache: %w", err)
+	}
+	return nil
+}
+
+func (f *File) load() (map[string]entry, error) {
+	data, err := os.ReadFile(f.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return map[string]entry{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read token cache: %w", err)
+	}
+
+	entries := map[string]entry{}
+	if err := open(f.aead, data, &entries); err != nil {
+		return nil, fmt.Errorf("read token cache: %w", err)
+	}
+	return entries, nil
+}
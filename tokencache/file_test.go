@@ -0,0 +1,72 @@
+package tokencache_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/erlorenz/bc-go/tokencache"
+)
+
+var key = bytes.Repeat([]byte("k"), 32)
+
+func TestFile(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "tokens")
+
+	cache, err := tokencache.NewFile(path, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := cache.Get(ctx, "a"); !errors.Is(err, bc.ErrTokenCacheMiss) {
+		t.Errorf("wanted ErrTokenCacheMiss, got %v", err)
+	}
+
+	expiresOn := time.Now().Add(time.Hour).Truncate(time.Second)
+	if err := cache.Set(ctx, "a", "TOKEN", expiresOn); err != nil {
+		t.Fatal(err)
+	}
+	if err := cache.Set(ctx, "b", "EXPIRED", time.Now().Add(-time.Second)); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Encrypted", func(t *testing.T) {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if bytes.Contains(b, []byte("TOKEN")) {
+			t.Error("token is stored in plain text")
+		}
+	})
+
+	t.Run("SharedFile", func(t *testing.T) {
+		other, _ := tokencache.NewFile(path, key)
+		token, got, err := other.Get(ctx, "a")
+		if err != nil || token != "TOKEN" || !got.Equal(expiresOn) {
+			t.Errorf("wanted TOKEN %s, got %s %s %v", expiresOn, token, got, err)
+		}
+		if _, _, err := other.Get(ctx, "b"); !errors.Is(err, bc.ErrTokenCacheMiss) {
+			t.Errorf("wanted expired token to miss, got %v", err)
+		}
+	})
+
+	t.Run("WrongKey", func(t *testing.T) {
+		other, _ := tokencache.NewFile(path, bytes.Repeat([]byte("x"), 32))
+		if _, _, err := other.Get(ctx, "a"); err == nil || errors.Is(err, bc.ErrTokenCacheMiss) {
+			t.Errorf("wanted decrypt error, got %v", err)
+		}
+	})
+}
+
+func TestNewFileInvalidKey(t *testing.T) {
+	if _, err := tokencache.NewFile("tokens", []byte("short")); err == nil {
+		t.Error("expected error")
+	}
+}
@@ -0,0 +1,302 @@
+package tokencache
+
+import (
+	"bufio"
+	"context"
+	"crypto/cipher"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/erlorenz/bc-go/bc"
+)
+
+// RedisOptions configure [NewRedis].
+type RedisOptions struct {
+	// Username is sent with the Password for a Redis 6 ACL user.
+	Username string
+	Password string
+	DB       int
+	// Prefix is added to every key. Defaults to "bc:token:".
+	Prefix string
+	// Key encrypts tokens with AES-GCM if set. It must be 16, 24 or 32 bytes.
+	Key []byte
+	// TLSConfig connects with TLS if set, e.g. to Azure Cache for Redis on port 6380.
+	// The ServerName defaults to the host of the address.
+	TLSConfig *tls.Config
+	// Dial defaults to a net.Dialer with a 5 second timeout.
+	Dial func(ctx context.Context, network, addr string) (net.Conn, error)
+	// MaxIdle is the most idle connections kept for the next calls. Defaults to 2.
+	MaxIdle int
+	// Clock decides if a token has expired and its TTL. Defaults to the [bc.SystemClock].
+	Clock bc.Clock
+}
+
+// Redis is a [bc.TokenCache] in Redis, for services running on several hosts.
+// Tokens expire in Redis when the token does. It speaks the Redis protocol directly
+// and keeps up to MaxIdle connections open between calls; Close closes them.
+type Redis struct {
+	addr string
+	opts RedisOptions
+	aead cipher.AEAD
+
+	mu   sync.Mutex
+	idle []*redisConn
+}
+
+// redisConn is a connection that has been authenticated and has selected the DB.
+type redisConn struct {
+	net.Conn
+	br *bufio.Reader
+}
+
+// redisError is an error reply of the server. The connection can still be used after it.
+type redisError string
+
+func (err redisError) Error() string { return string(err) }
+
+// NewRedis creates a Redis cache for the server at addr, e.g. "localhost:6379".
+func NewRedis(addr string, opts RedisOptions) (*Redis, error) {
+	r := &Redis{addr: addr, opts: opts}
+	if r.opts.Prefix == "" {
+		r.opts.Prefix = "bc:token:"
+	}
+	if r.opts.Dial == nil {
+		d := &net.Dialer{Timeout: 5 * time.Second}
+		r.opts.Dial = d.DialContext
+	}
+	if r.opts.MaxIdle <= 0 {
+		r.opts.MaxIdle = 2
+	}
+	if r.opts.TLSConfig != nil && r.opts.TLSConfig.ServerName == "" {
+		host, _, err := net.SplitHostPort(addr)
+		if err != nil {
+			return nil, fmt.Errorf("redis: %w", err)
+		}
+		r.opts.TLSConfig = r.opts.TLSConfig.Clone()
+		r.opts.TLSConfig.ServerName = host
+	}
+	if len(opts.Key) > 0 {
+		aead, err := newAEAD(opts.Key)
+		if err != nil {
+			return nil, err
+		}
+		r.aead = aead
+	}
+	return r, nil
+}
+
+// Get implements [bc.TokenCache].
+func (r *Redis) Get(ctx context.Context, key string) (bc.AccessToken, time.Time, error) {
+	reply, err := r.do(ctx, "GET", r.opts.Prefix+key)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	if reply == nil {
+		return "", time.Time{}, bc.ErrTokenCacheMiss
+	}
+
+	var e entry
+	if r.aead != nil {
+		data, err := base64.StdEncoding.DecodeString(*reply)
+		if err != nil {
+			return "", time.Time{}, fmt.Errorf("redis token: %w", err)
+		}
+		if err := open(r.aead, data, &e); err != nil {
+			return "", time.Time{}, fmt.Errorf("redis token: %w", err)
+		}
+	} else if err := json.Unmarshal([]byte(*reply), &e); err != nil {
+		return "", time.Time{}, fmt.Errorf("redis token: %w", err)
+	}
+
+	if e.expired(now(r.opts.Clock)) {
+		return "", time.Time{}, bc.ErrTokenCacheMiss
+	}
+	return e.Token, e.ExpiresOn, nil
+}
+
+// Set implements [bc.TokenCache].
+func (r *Redis) Set(ctx context.Context, key string, token bc.AccessToken, expiresOn time.Time) error {
+	ttl := expiresOn.Sub(now(r.opts.Clock)).Milliseconds()
+	if ttl <= 0 {
+		return nil
+	}
+
+	e := entry{Token: token, ExpiresOn: expiresOn}
+	var value string
+	if r.aead != nil {
+		data, err := seal(r.aead, e)
+		if err != nil {
+			return fmt.Errorf("redis token: %w", err)
+		}
+		value = base64.StdEncoding.EncodeToString(data)
+	} else {
+		b, err := json.Marshal(e)
+		if err != nil {
+			return fmt.Errorf("redis token: %w", err)
+		}
+		value = string(b)
+	}
+
+	_, err := r.do(ctx, "SET", r.opts.Prefix+key, value, "PX", strconv.FormatInt(ttl, 10))
+	return err
+}
+
+// Close closes the idle connections. Calls after Close open new ones.
+func (r *Redis) Close() error {
+	r.mu.Lock()
+	idle := r.idle
+	r.idle = nil
+	r.mu.Unlock()
+
+	var errs []error
+	for _, conn := range idle {
+		errs = append(errs, conn.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// do sends the command on an idle or new connection and returns its reply. A nil reply
+// is a Redis nil. An idle connection that the server has closed is replaced once.
+func (r *Redis) do(ctx context.Context, args ...string) (*string, error) {
+	for {
+		conn, reused, err := r.conn(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("redis: %w", err)
+		}
+		reply, err := conn.do(ctx, args)
+		var replyErr redisError
+		switch {
+		case err == nil || errors.As(err, &replyErr):
+			r.put(conn)
+		case reused && ctx.Err() == nil:
+			conn.Close()
+			continue
+		default:
+			conn.Close()
+		}
+		if err != nil {
+			return nil, fmt.Errorf("redis %s: %w", args[0], err)
+		}
+		return reply, nil
+	}
+}
+
+// conn returns an idle connection, or dials a new one that sends AUTH and SELECT if
+// needed. It returns true if the connection is idle from an earlier call.
+func (r *Redis) conn(ctx context.Context) (*redisConn, bool, error) {
+	r.mu.Lock()
+	if n := len(r.idle); n > 0 {
+		conn := r.idle[n-1]
+		r.idle = r.idle[:n-1]
+		r.mu.Unlock()
+		return conn, true, nil
+	}
+	r.mu.Unlock()
+
+	nc, err := r.opts.Dial(ctx, "tcp", r.addr)
+	if err != nil {
+		return nil, false, err
+	}
+	if r.opts.TLSConfig != nil {
+		tc := tls.Client(nc, r.opts.TLSConfig)
+		if err := tc.HandshakeContext(ctx); err != nil {
+			nc.Close()
+			return nil, false, err
+		}
+		nc = tc
+	}
+	conn := &redisConn{Conn: nc, br: bufio.NewReader(nc)}
+
+	if r.opts.Password != "" {
+		auth := []string{"AUTH", r.opts.Password}
+		if r.opts.Username != "" {
+			auth = []string{"AUTH", r.opts.Username, r.opts.Password}
+		}
+		if _, err := conn.do(ctx, auth); err != nil {
+			conn.Close()
+			return nil, false, fmt.Errorf("AUTH: %w", err)
+		}
+	}
+	if r.opts.DB != 0 {
+		if _, err := conn.do(ctx, []string{"SELECT", strconv.Itoa(r.opts.DB)}); err != nil {
+			conn.Close()
+			return nil, false, fmt.Errorf("SELECT: %w", err)
+		}
+	}
+	return conn, false, nil
+}
+
+// put keeps the connection for the next call, or closes it if MaxIdle are kept.
+func (r *Redis) put(conn *redisConn) {
+	r.mu.Lock()
+	if len(r.idle) < r.opts.MaxIdle {
+		r.idle = append(r.idle, conn)
+		conn = nil
+	}
+	r.mu.Unlock()
+	if conn != nil {
+		conn.Close()
+	}
+}
+
+// do writes the command and reads its reply, within the deadline of the context.
+func (conn *redisConn) do(ctx context.Context, args []string) (*string, error) {
+	deadline, _ := ctx.Deadline()
+	if err := conn.SetDeadline(deadline); err != nil {
+		return nil, err
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(&sb, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	if _, err := io.WriteString(conn, sb.String()); err != nil {
+		return nil, err
+	}
+	return readReply(conn.br)
+}
+
+// readReply reads a simple string, error, integer or bulk string reply.
+func readReply(br *bufio.Reader) (*string, error) {
+	line, err := br.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+	if line == "" {
+		return nil, errors.New("empty reply")
+	}
+
+	switch line[0] {
+	case '+', ':':
+		v := line[1:]
+		return &v, nil
+	case '-':
+		return nil, redisError(line[1:])
+	case '$':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid bulk length %q", line)
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		buf := make([]byte, n+2)
+		if _, err := io.ReadFull(br, buf); err != nil {
+			return nil, err
+		}
+		v := string(buf[:n])
+		return &v, nil
+	}
+	return nil, fmt.Errorf("unsupported reply %q", line)
+}
@@ -0,0 +1,206 @@
+package tokencache_test
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/erlorenz/bc-go/tokencache"
+)
+
+// fakeRedis answers AUTH, GET and SET on a local listener.
+type fakeRedis struct {
+	mu       sync.Mutex
+	values   map[string]string
+	commands []string
+	// password is checked by AUTH if set.
+	password string
+	// reply replaces the reply to GET and SET if set, e.g. an error reply.
+	reply string
+	conns int
+}
+
+func startFakeRedis(t *testing.T) (*fakeRedis, string) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("cannot listen: %s", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	fr := &fakeRedis{values: map[string]string{}}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			fr.mu.Lock()
+			fr.conns++
+			fr.mu.Unlock()
+			go fr.serve(conn)
+		}
+	}()
+	return fr, ln.Addr().String()
+}
+
+func (fr *fakeRedis) serve(conn net.Conn) {
+	defer conn.Close()
+	br := bufio.NewReader(conn)
+	for {
+		args, err := readCommand(br)
+		if err != nil {
+			return
+		}
+
+		fr.mu.Lock()
+		fr.commands = append(fr.commands, args[0])
+		switch {
+		case args[0] == "AUTH" && fr.password != "" && args[len(args)-1] != fr.password:
+			io.WriteString(conn, "-WRONGPASS invalid username-password pair or user is disabled.\r\n")
+		case args[0] == "AUTH":
+			io.WriteString(conn, "+OK\r\n")
+		case fr.reply != "":
+			io.WriteString(conn, fr.reply)
+		case args[0] == "SET":
+			fr.values[args[1]] = args[2]
+			io.WriteString(conn, "+OK\r\n")
+		case args[0] == "GET":
+			if v, ok := fr.values[args[1]]; ok {
+				fmt.Fprintf(conn, "$%d\r\n%s\r\n", len(v), v)
+			} else {
+				io.WriteString(conn, "$-1\r\n")
+			}
+		default:
+			io.WriteString(conn, "-ERR unknown command\r\n")
+		}
+		fr.mu.Unlock()
+	}
+}
+
+func readCommand(br *bufio.Reader) ([]string, error) {
+	line, err := br.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	n, _ := strconv.Atoi(strings.TrimSpace(line[1:]))
+	args := make([]string, n)
+	for i := range args {
+		if _, err := br.ReadString('\n'); err != nil {
+			return nil, err
+		}
+		arg, err := br.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		args[i] = strings.TrimSuffix(arg, "\r\n")
+	}
+	return args, nil
+}
+
+func TestRedis(t *testing.T) {
+	ctx := context.Background()
+	fr, addr := startFakeRedis(t)
+
+	cache, err := tokencache.NewRedis(addr, tokencache.RedisOptions{Password: "secret", Key: key})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := cache.Get(ctx, "a"); !errors.Is(err, bc.ErrTokenCacheMiss) {
+		t.Errorf("wanted ErrTokenCacheMiss, got %v", err)
+	}
+
+	if err := cache.Set(ctx, "a", "TOKEN", time.Now().Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+
+	token, _, err := cache.Get(ctx, "a")
+	if err != nil || token != "TOKEN" {
+		t.Errorf("wanted TOKEN, got %s %v", token, err)
+	}
+
+	fr.mu.Lock()
+	defer fr.mu.Unlock()
+	stored, ok := fr.values["bc:token:a"]
+	if !ok || strings.Contains(stored, "TOKEN") {
+		t.Errorf("wanted encrypted value at prefixed key, got %v", fr.values)
+	}
+	if fr.commands[0] != "AUTH" {
+		t.Errorf("wanted AUTH first, got %v", fr.commands)
+	}
+}
+
+func TestRedisReusesConnection(t *testing.T) {
+	ctx := context.Background()
+	fr, addr := startFakeRedis(t)
+	fr.password = "secret"
+
+	cache, err := tokencache.NewRedis(addr, tokencache.RedisOptions{Username: "bc", Password: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cache.Close()
+
+	if err := cache.Set(ctx, "a", "TOKEN", time.Now().Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	for range 3 {
+		if _, _, err := cache.Get(ctx, "a"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	fr.mu.Lock()
+	defer fr.mu.Unlock()
+	if fr.conns != 1 || fr.commands[0] != "AUTH" || strings.Count(strings.Join(fr.commands, " "), "AUTH") != 1 {
+		t.Errorf("wanted one connection authenticated once, got %d connections with %v", fr.conns, fr.commands)
+	}
+}
+
+func TestRedisErrorReply(t *testing.T) {
+	ctx := context.Background()
+	fr, addr := startFakeRedis(t)
+	fr.password = "secret"
+
+	cache, err := tokencache.NewRedis(addr, tokencache.RedisOptions{Password: "wrong"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := cache.Get(ctx, "a"); err == nil || !strings.Contains(err.Error(), "WRONGPASS") {
+		t.Errorf("wanted the AUTH error, got %v", err)
+	}
+
+	cache, err = tokencache.NewRedis(addr, tokencache.RedisOptions{Password: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cache.Close()
+	fr.mu.Lock()
+	fr.reply = "-ERR OOM command not allowed when used memory > 'maxmemory'.\r\n"
+	fr.mu.Unlock()
+
+	err = cache.Set(ctx, "a", "TOKEN", time.Now().Add(time.Hour))
+	if err == nil || !strings.Contains(err.Error(), "redis SET: ERR OOM") {
+		t.Errorf("wanted the error reply of SET, got %v", err)
+	}
+	_, _, err = cache.Get(ctx, "a")
+	if err == nil || errors.Is(err, bc.ErrTokenCacheMiss) {
+		t.Errorf("wanted the error reply of GET, got %v", err)
+	}
+
+	fr.mu.Lock()
+	defer fr.mu.Unlock()
+	if fr.conns != 2 {
+		t.Errorf("wanted the connection kept after an error reply, got %d connections", fr.conns)
+	}
+}
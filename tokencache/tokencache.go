@@ -0,0 +1,68 @@
+// Package tokencache has [bc.TokenCache] implementations for sharing access tokens
+// between processes: an AES-GCM encrypted [File] and a [Redis] cache.
+package tokencache
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/erlorenz/bc-go/bc"
+)
+
+// entry is how a token is stored.
+type entry struct {
+	Token     bc.AccessToken `json:"token"`
+	ExpiresOn time.Time      `json:"expiresOn"`
+}
+
+func (e entry) expired(now time.Time) bool {
+	return !now.Before(e.ExpiresOn)
+}
+
+// now returns the time of the clock, or of the [bc.SystemClock] if it is nil.
+func now(clock bc.Clock) time.Time {
+	if clock == nil {
+		return time.Now()
+	}
+	return clock.Now()
+}
+
+// newAEAD returns AES-GCM for a 16, 24 or 32 byte key.
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid key: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal encrypts the JSON of v with a random nonce prefix.
+func seal(aead cipher.AEAD, v any) ([]byte, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, b, nil), nil
+}
+
+// open decrypts data created by seal into v.
+func open(aead cipher.AEAD, data []byte, v any) error {
+	if len(data) < aead.NonceSize() {
+		return errors.New("encrypted data too short")
+	}
+	nonce, ciphertext := data[:aead.NonceSize()], data[aead.NonceSize():]
+	b, err := aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return fmt.Errorf("decrypt: %w", err)
+	}
+	return json.Unmarshal(b, v)
+}
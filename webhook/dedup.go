@@ -0,0 +1,144 @@
+package webhook
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/erlorenz/bc-go/bc"
+)
+
+// DedupStore remembers the changes that were handled. Share a durable store between
+// the instances of a service behind a load balancer.
+type DedupStore interface {
+	// Claim records the key and returns false if it was claimed before and not released.
+	Claim(ctx context.Context, key string) (bool, error)
+	// Release forgets a key whose handling failed.
+	Release(ctx context.Context, key string) error
+}
+
+// DefaultDedupTTL is how long a [MemoryDedupStore] remembers a key when TTL is 0.
+// BC stops retrying a notification well within it.
+const DefaultDedupTTL = 24 * time.Hour
+
+// MemoryDedupStore is an in-memory [DedupStore] for a single instance. Keys are
+// forgotten after the TTL. It is safe for concurrent use.
+type MemoryDedupStore struct {
+	TTL time.Duration
+	// Clock decides when a key is forgotten. Defaults to the [bc.SystemClock].
+	Clock bc.Clock
+
+	mu      sync.Mutex
+	claimed map[string]time.Time
+	purged  time.Time
+}
+
+// Claim implements [DedupStore].
+func (s *MemoryDedupStore) Claim(_ context.Context, key string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ttl := s.TTL
+	if ttl <= 0 {
+		ttl = DefaultDedupTTL
+	}
+	clock := s.Clock
+	if clock == nil {
+		clock = bc.SystemClock
+	}
+	now := clock.Now()
+	if s.claimed == nil {
+		s.claimed = map[string]time.Time{}
+	}
+	// Purge the expired keys at most once per TTL, so a claim is O(1) on average.
+	if now.Sub(s.purged) > ttl {
+		for k, t := range s.claimed {
+			if now.Sub(t) > ttl {
+				delete(s.claimed, k)
+			}
+		}
+		s.purged = now
+	}
+
+	if t, ok := s.claimed[key]; ok && now.Sub(t) <= ttl {
+		return false, nil
+	}
+	s.claimed[key] = now
+	return true, nil
+}
+
+// Release implements [DedupStore].
+func (s *MemoryDedupStore) Release(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.claimed, key)
+	return nil
+}
+
+// DedupSchema creates the table of a [SQLDedupStore] in SQLite or Postgres.
+const DedupSchema = `CREATE TABLE IF NOT EXISTS webhook_dedup (
+	key TEXT PRIMARY KEY,
+	claimed_at BIGINT NOT NULL
+);`
+
+// SQLDedupStore is a [DedupStore] in the table of [DedupSchema], with the claim times
+// in Unix milliseconds. It works with the SQLite and Postgres drivers. Delete the old
+// keys with Purge.
+type SQLDedupStore struct {
+	DB *sql.DB
+	// Postgres uses "$1" placeholders instead of "?".
+	Postgres bool
+}
+
+// bind replaces the "?" placeholders of the query for Postgres.
+func (s SQLDedupStore) bind(query string) string {
+	if !s.Postgres {
+		return query
+	}
+	var b strings.Builder
+	n := 0
+	for _, c := range query {
+		if c == '?' {
+			n++
+			fmt.Fprintf(&b, "$%d", n)
+			continue
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
+}
+
+// Claim implements [DedupStore].
+func (s SQLDedupStore) Claim(ctx context.Context, key string) (bool, error) {
+	res, err := s.DB.ExecContext(ctx, s.bind(`INSERT INTO webhook_dedup (key, claimed_at) VALUES (?, ?) ON CONFLICT (key) DO NOTHING`),
+		key, time.Now().UnixMilli())
+	if err != nil {
+		return false, fmt.Errorf("claim key: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("claim key: %w", err)
+	}
+	return n == 1, nil
+}
+
+// Release implements [DedupStore].
+func (s SQLDedupStore) Release(ctx context.Context, key string) error {
+	if _, err := s.DB.ExecContext(ctx, s.bind(`DELETE FROM webhook_dedup WHERE key = ?`), key); err != nil {
+		return fmt.Errorf("release key: %w", err)
+	}
+	return nil
+}
+
+// Purge deletes the keys claimed before the time.
+func (s SQLDedupStore) Purge(ctx context.Context, before time.Time) (int64, error) {
+	res, err := s.DB.ExecContext(ctx, s.bind(`DELETE FROM webhook_dedup WHERE claimed_at < ?`), before.UnixMilli())
+	if err != nil {
+		return 0, fmt.Errorf("purge keys: %w", err)
+	}
+	return res.RowsAffected()
+}
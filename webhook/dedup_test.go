@@ -0,0 +1,34 @@
+package webhook_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/erlorenz/bc-go/bcmock"
+	"github.com/erlorenz/bc-go/webhook"
+)
+
+func TestMemoryDedupStore(t *testing.T) {
+	ctx := context.Background()
+	clock := bcmock.NewClock(time.Now())
+	store := &webhook.MemoryDedupStore{TTL: time.Hour, Clock: clock}
+
+	if ok, _ := store.Claim(ctx, "a"); !ok {
+		t.Fatal("wanted the first claim to succeed")
+	}
+	if ok, _ := store.Claim(ctx, "a"); ok {
+		t.Fatal("wanted the second claim to fail")
+	}
+	if err := store.Release(ctx, "a"); err != nil {
+		t.Fatal(err)
+	}
+	if ok, _ := store.Claim(ctx, "a"); !ok {
+		t.Fatal("wanted a claim after the release to succeed")
+	}
+
+	clock.Advance(time.Hour + time.Second)
+	if ok, _ := store.Claim(ctx, "a"); !ok {
+		t.Error("wanted the key to expire after the TTL")
+	}
+}
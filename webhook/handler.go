@@ -0,0 +1,132 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// HandlerFunc handles a notification. An error makes BC deliver it again.
+type HandlerFunc func(ctx context.Context, n Notification) error
+
+// Handler is the http.Handler of the notification URL of the subscriptions.
+type Handler struct {
+	// ClientState is compared with the clientState of every notification, so
+	// requests that were not sent for the subscriptions are rejected with a 403.
+	// Empty accepts any.
+	ClientState string
+	// Dedup skips the changes that were already handled. Nil handles every delivery.
+	Dedup DedupStore
+	// Handle is called for each notification, e.g. [Router.Handle].
+	Handle HandlerFunc
+	// Workers is the most notifications of a request handled at once. The changes of
+	// the same resource are handled one at a time in order. Defaults to 1.
+	Workers int
+}
+
+// maxBodySize limits the request body. BC sends at most a few hundred changes per request.
+const maxBodySize = 4 << 20
+
+// ServeHTTP answers the validation request of a new subscription with its token, and
+// handles the notifications of other requests. If one fails it responds with a 500 so
+// BC delivers the request again, and the changes handled before are skipped by Dedup.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if token := r.URL.Query().Get("validationToken"); token != "" {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		io.WriteString(w, token)
+		return
+	}
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	notifications, err := Parse(http.MaxBytesReader(w, r.Body, maxBodySize))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if h.ClientState != "" {
+		for _, n := range notifications {
+			if n.ClientState != h.ClientState {
+				http.Error(w, "invalid clientState", http.StatusForbidden)
+				return
+			}
+		}
+	}
+
+	if err := h.handleAll(r.Context(), notifications); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// handleAll handles the notifications of each resource in order with up to Workers
+// goroutines. The remaining changes of a resource are skipped after one fails.
+func (h *Handler) handleAll(ctx context.Context, notifications []Notification) error {
+	var resources []string
+	byResource := map[string][]Notification{}
+	for _, n := range notifications {
+		if _, ok := byResource[n.Resource]; !ok {
+			resources = append(resources, n.Resource)
+		}
+		byResource[n.Resource] = append(byResource[n.Resource], n)
+	}
+
+	workers := max(h.Workers, 1)
+	sem := make(chan struct{}, workers)
+	errs := make([]error, len(resources))
+	var wg sync.WaitGroup
+	for i, resource := range resources {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			for _, n := range byResource[resource] {
+				if err := h.handle(ctx, n); err != nil {
+					errs[i] = err
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+func (h *Handler) handle(ctx context.Context, n Notification) error {
+	if h.Dedup == nil {
+		return h.Handle(ctx, n)
+	}
+	return Deduplicate(h.Dedup, h.Handle)(ctx, n)
+}
+
+// Deduplicate returns a HandlerFunc that calls handle at most once per [Notification.Key].
+// If handle fails the key is released, so the change is handled when it is delivered again.
+func Deduplicate(store DedupStore, handle HandlerFunc) HandlerFunc {
+	return func(ctx context.Context, n Notification) error {
+		key := n.Key()
+		claimed, err := store.Claim(ctx, key)
+		if err != nil {
+			return fmt.Errorf("claim %s: %w", key, err)
+		}
+		if !claimed {
+			return nil
+		}
+		if err := handle(ctx, n); err != nil {
+			if releaseErr := store.Release(ctx, key); releaseErr != nil {
+				return errors.Join(err, fmt.Errorf("release %s: %w", key, releaseErr))
+			}
+			return err
+		}
+		return nil
+	}
+}
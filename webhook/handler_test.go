@@ -0,0 +1,125 @@
+package webhook_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/erlorenz/bc-go/webhook"
+)
+
+func post(h http.Handler, body string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/notifications", strings.NewReader(body)))
+	return rec
+}
+
+func TestHandlerValidation(t *testing.T) {
+	h := &webhook.Handler{Handle: func(context.Context, webhook.Notification) error { return nil }}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/notifications?validationToken=abc%20123", nil))
+	if rec.Code != http.StatusOK || rec.Body.String() != "abc 123" {
+		t.Errorf("wanted the token echoed, got %d %q", rec.Code, rec.Body)
+	}
+}
+
+func TestHandlerDedup(t *testing.T) {
+	var handled []string
+	fail := true
+	h := &webhook.Handler{
+		ClientState: "secret",
+		Dedup:       &webhook.MemoryDedupStore{},
+		Handle: func(_ context.Context, n webhook.Notification) error {
+			if n.ChangeType == webhook.ChangeCollection && fail {
+				return errors.New("database is down")
+			}
+			handled = append(handled, string(n.ChangeType))
+			return nil
+		},
+	}
+
+	// The second change fails, so BC delivers both again
+	if rec := post(h, notifications); rec.Code != http.StatusInternalServerError {
+		t.Fatalf("wanted 500, got %d", rec.Code)
+	}
+	fail = false
+	if rec := post(h, notifications); rec.Code != http.StatusOK {
+		t.Fatalf("wanted 200, got %d", rec.Code)
+	}
+	// A duplicate delivery is skipped
+	if rec := post(h, notifications); rec.Code != http.StatusOK {
+		t.Fatalf("wanted 200, got %d", rec.Code)
+	}
+	if got := strings.Join(handled, " "); got != "updated collection" {
+		t.Errorf("wanted each change handled once, got %s", got)
+	}
+}
+
+func TestHandlerRejects(t *testing.T) {
+	h := &webhook.Handler{ClientState: "other", Handle: func(context.Context, webhook.Notification) error {
+		t.Error("wanted no notification to be handled")
+		return nil
+	}}
+	if rec := post(h, notifications); rec.Code != http.StatusForbidden {
+		t.Errorf("wanted 403 for another clientState, got %d", rec.Code)
+	}
+	if rec := post(h, "not json"); rec.Code != http.StatusBadRequest {
+		t.Errorf("wanted 400 for an invalid body, got %d", rec.Code)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/notifications", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("wanted 405 for a GET, got %d", rec.Code)
+	}
+}
+
+func TestHandlerWorkers(t *testing.T) {
+	var mu sync.Mutex
+	var running, most int
+	var order []string
+	h := &webhook.Handler{
+		Workers: 2,
+		Handle: func(_ context.Context, n webhook.Notification) error {
+			mu.Lock()
+			running++
+			most = max(most, running)
+			order = append(order, n.Resource+":"+string(n.ChangeType))
+			mu.Unlock()
+
+			time.Sleep(10 * time.Millisecond)
+
+			mu.Lock()
+			running--
+			mu.Unlock()
+			return nil
+		},
+	}
+
+	var value []string
+	for _, change := range []string{"a:created", "b:created", "c:created", "a:updated", "a:deleted"} {
+		resource, changeType, _ := strings.Cut(change, ":")
+		value = append(value, fmt.Sprintf(`{"resource": "api/v2.0/companies(1)/customers(%s)", "changeType": %q}`, resource, changeType))
+	}
+	if rec := post(h, `{"value": [`+strings.Join(value, ",")+`]}`); rec.Code != http.StatusOK {
+		t.Fatalf("wanted 200, got %d", rec.Code)
+	}
+
+	if most != 2 {
+		t.Errorf("wanted 2 notifications handled at once, got %d", most)
+	}
+	var a []string
+	for _, o := range order {
+		if strings.Contains(o, "customers(a)") {
+			a = append(a, o[strings.Index(o, ":")+1:])
+		}
+	}
+	if got := strings.Join(a, " "); got != "created updated deleted" {
+		t.Errorf("wanted the changes of a record in order, got %s", got)
+	}
+}
@@ -0,0 +1,60 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+	"time"
+)
+
+// Recover returns a Middleware that turns a panic of the handler into an error, so
+// BC delivers the notification again instead of the server crashing.
+func Recover() Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, n Notification) (err error) {
+			defer func() {
+				if p := recover(); p != nil {
+					err = fmt.Errorf("panic: %v\n%s", p, debug.Stack())
+				}
+			}()
+			return next(ctx, n)
+		}
+	}
+}
+
+// Logger returns a Middleware that logs every notification with its duration,
+// failures at the error level.
+func Logger(logger *slog.Logger) Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, n Notification) error {
+			start := time.Now()
+			err := next(ctx, n)
+			attrs := []any{
+				"entitySet", n.EntitySetName(),
+				"changeType", n.ChangeType,
+				"resource", n.Resource,
+				"duration", time.Since(start),
+			}
+			if err != nil {
+				logger.ErrorContext(ctx, "Failed to handle notification.", append(attrs, "error", err)...)
+			} else {
+				logger.InfoContext(ctx, "Handled notification.", attrs...)
+			}
+			return err
+		}
+	}
+}
+
+// Metrics returns a Middleware that calls observe after every notification, e.g. to
+// record a histogram by entity set and change type.
+func Metrics(observe func(n Notification, d time.Duration, err error)) Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, n Notification) error {
+			start := time.Now()
+			err := next(ctx, n)
+			observe(n, time.Since(start), err)
+			return err
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package webhook_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/erlorenz/bc-go/webhook"
+)
+
+func TestRecover(t *testing.T) {
+	router := webhook.NewRouter()
+	router.Use(webhook.Recover())
+	router.On("customers", func(context.Context, webhook.Notification) error { panic("boom") })
+
+	err := router.Handle(context.Background(), notification("customers"))
+	if err == nil || !strings.Contains(err.Error(), "panic: boom") {
+		t.Errorf("wanted the panic as an error, got %v", err)
+	}
+}
+
+func TestLoggerAndMetrics(t *testing.T) {
+	var buf bytes.Buffer
+	var observed []error
+	router := webhook.NewRouter()
+	router.Use(
+		webhook.Logger(slog.New(slog.NewTextHandler(&buf, nil))),
+		webhook.Metrics(func(_ webhook.Notification, _ time.Duration, err error) { observed = append(observed, err) }),
+	)
+	failed := errors.New("failed")
+	router.On("customers", func(context.Context, webhook.Notification) error { return nil })
+	router.On("items", func(context.Context, webhook.Notification) error { return failed })
+
+	router.Handle(context.Background(), notification("customers"))
+	router.Handle(context.Background(), notification("items"))
+
+	if len(observed) != 2 || observed[0] != nil || !errors.Is(observed[1], failed) {
+		t.Errorf("unexpected observations %v", observed)
+	}
+	logs := buf.String()
+	if !strings.Contains(logs, "level=INFO msg=\"Handled notification.\" entitySet=customers") ||
+		!strings.Contains(logs, "level=ERROR msg=\"Failed to handle notification.\" entitySet=items") {
+		t.Errorf("unexpected logs\n%s", logs)
+	}
+}
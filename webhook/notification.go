@@ -0,0 +1,92 @@
+// Package webhook receives the notifications of BC API subscriptions.
+// A [Handler] answers the validation request of a new subscription, parses the
+// notifications and calls the Handle function once per change, even though BC can
+// deliver a notification more than once:
+//
+//	http.Handle("/bc/notifications", &webhook.Handler{
+//		ClientState: secret,
+//		Dedup:       &webhook.MemoryDedupStore{},
+//		Handle: func(ctx context.Context, n webhook.Notification) error {
+//			id, _ := n.RecordID()
+//			return refresh(ctx, n.EntitySetName(), id)
+//		},
+//	})
+//
+// A [Router] dispatches the notifications to a handler per entity set, with [Middleware]
+// for logging, metrics and panic recovery. [Reconcile] creates, renews and prunes the
+// subscriptions on startup, since BC expires them after 3 days. Where BC cannot reach
+// the endpoint, a [Poller] sends the same notifications from delta or lastModified queries.
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ChangeType is the change of a [Notification].
+type ChangeType string
+
+const (
+	ChangeCreated ChangeType = "created"
+	ChangeUpdated ChangeType = "updated"
+	ChangeDeleted ChangeType = "deleted"
+	// ChangeCollection is sent instead of the single changes when many records changed
+	// at once. Read the entity set again, e.g. with a delta link.
+	ChangeCollection ChangeType = "collection"
+)
+
+// Notification is a change of a subscribed resource.
+type Notification struct {
+	SubscriptionID string `json:"subscriptionId"`
+	ClientState    string `json:"clientState"`
+	// ExpirationDateTime is when the subscription expires unless it is renewed.
+	ExpirationDateTime time.Time `json:"expirationDateTime"`
+	// Resource is the changed record, e.g. "api/v2.0/companies(id)/customers(id)",
+	// or the entity set for ChangeCollection.
+	Resource             string     `json:"resource"`
+	ChangeType           ChangeType `json:"changeType"`
+	LastModifiedDateTime time.Time  `json:"lastModifiedDateTime"`
+}
+
+// Key identifies the change, so the same notification delivered again has the same key.
+func (n Notification) Key() string {
+	return strings.Join([]string{n.SubscriptionID, string(n.ChangeType), n.Resource,
+		n.LastModifiedDateTime.UTC().Format(time.RFC3339Nano)}, "|")
+}
+
+// lastSegment returns the last path segment of the resource, e.g. "customers(id)".
+func (n Notification) lastSegment() string {
+	return n.Resource[strings.LastIndex(n.Resource, "/")+1:]
+}
+
+// EntitySetName returns the entity set of the resource, e.g. "customers".
+func (n Notification) EntitySetName() string {
+	name, _, _ := strings.Cut(n.lastSegment(), "(")
+	return name
+}
+
+// RecordID returns the id of the changed record. It is false for ChangeCollection.
+func (n Notification) RecordID() (uuid.UUID, bool) {
+	_, rest, ok := strings.Cut(n.lastSegment(), "(")
+	if !ok {
+		return uuid.Nil, false
+	}
+	id, err := uuid.Parse(strings.TrimSuffix(rest, ")"))
+	return id, err == nil
+}
+
+// Parse reads the notifications of a request body, in the order BC sent them.
+func Parse(r io.Reader) ([]Notification, error) {
+	var body struct {
+		Value []Notification `json:"value"`
+	}
+	if err := json.NewDecoder(r).Decode(&body); err != nil {
+		return nil, fmt.Errorf("parse notifications: %w", err)
+	}
+	return body.Value, nil
+}
@@ -0,0 +1,44 @@
+package webhook_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/erlorenz/bc-go/webhook"
+	"github.com/google/uuid"
+)
+
+const notifications = `{"value": [
+	{"subscriptionId": "customers", "clientState": "secret", "expirationDateTime": "2026-10-17T07:52:31Z",
+	 "resource": "api/v2.0/companies(7d8f8d8a-2f2e-4b7a-9b4e-4d8d1c3f6a11)/customers(26814998-936a-401c-81c1-0e848a64971d)",
+	 "changeType": "updated", "lastModifiedDateTime": "2026-10-14T12:54:20.467Z"},
+	{"subscriptionId": "customers", "clientState": "secret", "expirationDateTime": "2026-10-17T07:52:31Z",
+	 "resource": "api/v2.0/companies(7d8f8d8a-2f2e-4b7a-9b4e-4d8d1c3f6a11)/customers",
+	 "changeType": "collection", "lastModifiedDateTime": "2026-10-14T12:55:00Z"}
+]}`
+
+func TestParse(t *testing.T) {
+	got, err := webhook.Parse(strings.NewReader(notifications))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("wanted 2 notifications, got %+v", got)
+	}
+
+	n := got[0]
+	id, ok := n.RecordID()
+	if n.EntitySetName() != "customers" || !ok || id != uuid.MustParse("26814998-936a-401c-81c1-0e848a64971d") || n.ChangeType != webhook.ChangeUpdated {
+		t.Errorf("unexpected notification %+v", n)
+	}
+	if _, ok := got[1].RecordID(); ok || got[1].EntitySetName() != "customers" {
+		t.Errorf("wanted a collection without a record, got %+v", got[1])
+	}
+	if got[0].Key() == got[1].Key() {
+		t.Errorf("wanted different keys, got %s", got[0].Key())
+	}
+
+	if _, err := webhook.Parse(strings.NewReader(`[`)); err == nil {
+		t.Error("wanted error for invalid JSON")
+	}
+}
@@ -0,0 +1,258 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/erlorenz/bc-go/bcsync"
+	"github.com/google/uuid"
+)
+
+// DefaultPollInterval is the time between the polls of [Poller.Run].
+const DefaultPollInterval = time.Minute
+
+// PollEntity is an entity set watched by a [Poller].
+type PollEntity struct {
+	EntitySetName string
+	// Incremental polls by lastModifiedDateTime, for entity sets without delta links.
+	// Deletes are not detected.
+	Incremental bool
+	// ListOptions limit the watched records, e.g. with a filter. Select the id and
+	// lastModifiedDateTime to poll less data.
+	ListOptions bc.ListOptions
+}
+
+// PollerOptions configure a [Poller].
+type PollerOptions struct {
+	// Interval defaults to DefaultPollInterval.
+	Interval time.Duration
+	// MaxPageSize is the page size of the polls, unless set in the PollEntity
+	// ListOptions. Defaults to 1000.
+	MaxPageSize int
+	// SubscriptionID and ClientState are set in the notifications, so handlers that
+	// check them work unchanged. SubscriptionID defaults to "poll".
+	SubscriptionID string
+	ClientState    string
+	// Initial sends a notification for every existing record on the first poll of an
+	// entity set. By default the first poll only records the position, like a new
+	// subscription that is only notified of later changes.
+	Initial bool
+	// OnError is called with the errors of the polls in Run.
+	OnError func(entitySetName string, err error)
+}
+
+// Poller emulates the notifications of subscriptions by polling the entity sets, for
+// environments where BC cannot reach a webhook endpoint. Pass it the Handle of the
+// [Router], or of the [Handler], used with subscriptions and the handlers stay the same:
+//
+//	p, err := webhook.NewPoller(client, checkpoints, []webhook.PollEntity{
+//		{EntitySetName: "customers"},
+//		{EntitySetName: "items", Incremental: true},
+//	}, router.Handle, webhook.PollerOptions{})
+//	go p.Run(ctx)
+//
+// Records created or modified are notified as ChangeUpdated, since neither delta links
+// nor lastModifiedDateTime tell a create apart. The position is saved in the checkpoint
+// store after each page, so a page with a failed handler is polled and notified again,
+// like a notification that BC delivers again.
+type Poller struct {
+	checkpoints bcsync.CheckpointStore
+	opts        PollerOptions
+	entities    []*pollEntity
+}
+
+// pollEntity is the state of a watched entity set.
+type pollEntity struct {
+	name  string
+	key   string
+	pull  func(context.Context) (bcsync.PullResult, error)
+	store *pollStore
+}
+
+// record is a record of any entity set.
+type record map[string]any
+
+func (record) Validate() error { return nil }
+
+func recordID(r record) uuid.UUID {
+	s, _ := r["id"].(string)
+	id, _ := uuid.Parse(s)
+	return id
+}
+
+func lastModified(r record) time.Time {
+	s, _ := r["lastModifiedDateTime"].(string)
+	t, _ := time.Parse(time.RFC3339Nano, s)
+	return t
+}
+
+// pollStore is the [bcsync.Store] of an entity set, which turns the pulled changes
+// into notifications.
+type pollStore struct {
+	handle         HandlerFunc
+	resource       string
+	subscriptionID string
+	clientState    string
+	// quiet skips the notifications of the first poll.
+	quiet bool
+}
+
+func (s *pollStore) notify(ctx context.Context, id uuid.UUID, change ChangeType, modified time.Time) error {
+	if s.quiet {
+		return nil
+	}
+	return s.handle(ctx, Notification{
+		SubscriptionID:       s.subscriptionID,
+		ClientState:          s.clientState,
+		Resource:             fmt.Sprintf("%s(%s)", s.resource, id),
+		ChangeType:           change,
+		LastModifiedDateTime: modified,
+	})
+}
+
+func (s *pollStore) Upsert(ctx context.Context, r record) error {
+	id := recordID(r)
+	if id == uuid.Nil {
+		return fmt.Errorf("record has no id")
+	}
+	return s.notify(ctx, id, ChangeUpdated, lastModified(r))
+}
+
+func (s *pollStore) Remove(ctx context.Context, id uuid.UUID) error {
+	return s.notify(ctx, id, ChangeDeleted, time.Now().UTC())
+}
+
+func (*pollStore) Pending(context.Context) ([]bcsync.Change[record], error) {
+	return nil, nil
+}
+
+func (*pollStore) Ack(context.Context, bcsync.Change[record], record) error {
+	return nil
+}
+
+// NewPoller returns a Poller of the entity sets. Nothing is polled until [Poller.Poll]
+// or [Poller.Run].
+func NewPoller(client *bc.Client, checkpoints bcsync.CheckpointStore, entities []PollEntity, handle HandlerFunc, opts PollerOptions) (*Poller, error) {
+	if client == nil || checkpoints == nil || handle == nil {
+		return nil, errors.New("new poller: client, checkpoints and handle are required")
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultPollInterval
+	}
+	if opts.MaxPageSize <= 0 {
+		opts.MaxPageSize = 1000
+	}
+	if opts.SubscriptionID == "" {
+		opts.SubscriptionID = "poll"
+	}
+
+	p := &Poller{checkpoints: checkpoints, opts: opts}
+	seen := map[string]bool{}
+	for _, e := range entities {
+		if e.EntitySetName == "" {
+			return nil, errors.New("new poller: entity has no EntitySetName")
+		}
+		if seen[e.EntitySetName] {
+			return nil, fmt.Errorf("new poller: %s is repeated", e.EntitySetName)
+		}
+		seen[e.EntitySetName] = true
+		if e.ListOptions.MaxPageSize <= 0 {
+			e.ListOptions.MaxPageSize = opts.MaxPageSize
+		}
+		resource, err := Resource(client, e.EntitySetName)
+		if err != nil {
+			return nil, fmt.Errorf("new poller: %w", err)
+		}
+		p.entities = append(p.entities, newPollEntity(client, checkpoints, e, &pollStore{
+			handle:         handle,
+			resource:       resource,
+			subscriptionID: opts.SubscriptionID,
+			clientState:    opts.ClientState,
+		}))
+	}
+	return p, nil
+}
+
+func newPollEntity(client *bc.Client, checkpoints bcsync.CheckpointStore, e PollEntity, store *pollStore) *pollEntity {
+	page := bc.NewAPIPage[record](client, e.EntitySetName)
+	if e.Incremental {
+		inc := &bcsync.Incremental[record]{
+			Page:         page,
+			Store:        store,
+			Checkpoints:  checkpoints,
+			Key:          "poll:incremental:" + e.EntitySetName,
+			ID:           recordID,
+			LastModified: lastModified,
+			ListOptions:  e.ListOptions,
+		}
+		return &pollEntity{name: e.EntitySetName, key: inc.Key, pull: inc.Pull, store: store}
+	}
+	engine := &bcsync.Engine[record]{
+		Page:        page,
+		Store:       store,
+		Checkpoints: checkpoints,
+		Key:         "poll:" + e.EntitySetName,
+		ID:          recordID,
+		ETag: func(r record) string {
+			etag, _ := r["@odata.etag"].(string)
+			return etag
+		},
+		ListOptions: e.ListOptions,
+	}
+	return &pollEntity{name: e.EntitySetName, key: engine.Key, pull: engine.Pull, store: store}
+}
+
+// Poll notifies the changes of every entity set since the last poll, in order, and
+// returns the errors joined. It must not be called concurrently.
+func (p *Poller) Poll(ctx context.Context) error {
+	var errs []error
+	for _, e := range p.entities {
+		if err := p.poll(ctx, e); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (p *Poller) poll(ctx context.Context, e *pollEntity) error {
+	_, err := p.checkpoints.LoadCheckpoint(ctx, e.key)
+	switch {
+	case errors.Is(err, bcsync.ErrNoCheckpoint):
+		e.store.quiet = !p.opts.Initial
+	case err != nil:
+		return fmt.Errorf("poll %s: load checkpoint: %w", e.name, err)
+	default:
+		e.store.quiet = false
+	}
+
+	if _, err := e.pull(bc.WithPriority(ctx, bc.PriorityBulk)); err != nil {
+		return fmt.Errorf("poll %s: %w", e.name, err)
+	}
+	return nil
+}
+
+// Run polls every Interval until the context is done, and returns the context error.
+// The errors of the polls are passed to OnError and the entity set is polled again on
+// the next Interval.
+func (p *Poller) Run(ctx context.Context) error {
+	ticker := time.NewTicker(p.opts.Interval)
+	defer ticker.Stop()
+	for {
+		for _, e := range p.entities {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if err := p.poll(ctx, e); err != nil && p.opts.OnError != nil {
+				p.opts.OnError(e.name, err)
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
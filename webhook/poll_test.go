@@ -0,0 +1,119 @@
+package webhook_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/erlorenz/bc-go/bcsync"
+	"github.com/erlorenz/bc-go/internal/bctest"
+	"github.com/erlorenz/bc-go/webhook"
+	"github.com/google/uuid"
+)
+
+const deltaURL = "https://api.businesscentral.dynamics.com/v2.0/delta"
+
+func TestPoller(t *testing.T) {
+	id1, id2 := uuid.New(), uuid.New()
+	modified := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	st := &bctest.SequenceTransport{Responses: []*http.Response{
+		bctest.NewResponse(200, map[string]any{
+			"value":            []map[string]any{{"id": id1}, {"id": id2}},
+			"@odata.deltaLink": deltaURL + "1",
+		}),
+		bctest.NewResponse(200, map[string]any{
+			"value":            []map[string]any{{"id": id1, "lastModifiedDateTime": modified}, {"id": id2, "@removed": map[string]any{"reason": "deleted"}}},
+			"@odata.deltaLink": deltaURL + "2",
+		}),
+	}}
+
+	var got []webhook.Notification
+	router := webhook.NewRouter()
+	router.On("customers", func(_ context.Context, n webhook.Notification) error {
+		got = append(got, n)
+		return nil
+	})
+	p, err := webhook.NewPoller(newClient(t, st), &bcsync.MemoryCheckpoints{}, []webhook.PollEntity{{EntitySetName: "customers"}},
+		router.Handle, webhook.PollerOptions{ClientState: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The first poll only starts tracking the changes
+	if err := p.Poll(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 0 {
+		t.Fatalf("wanted no notifications, got %+v", got)
+	}
+
+	if err := p.Poll(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("wanted 2 notifications, got %+v", got)
+	}
+	if id, _ := got[0].RecordID(); id != id1 || got[0].ChangeType != webhook.ChangeUpdated ||
+		!got[0].LastModifiedDateTime.Equal(modified) || got[0].ClientState != "secret" || got[0].SubscriptionID != "poll" {
+		t.Errorf("unexpected notification %+v", got[0])
+	}
+	if id, _ := got[1].RecordID(); id != id2 || got[1].ChangeType != webhook.ChangeDeleted {
+		t.Errorf("unexpected notification %+v", got[1])
+	}
+	if got[0].Resource != "api/v2.0/companies("+companyID+")/customers("+id1.String()+")" {
+		t.Errorf("unexpected resource %s", got[0].Resource)
+	}
+	if st.Requests[1].URL.Path != "/v2.0/delta1" {
+		t.Errorf("expected poll from delta link, got %s", st.Requests[1].URL.Path)
+	}
+}
+
+func TestPollerIncrementalRetries(t *testing.T) {
+	id := uuid.New()
+	modified := time.Now().UTC()
+	st := &bctest.SequenceTransport{Responses: []*http.Response{
+		bctest.NewResponse(200, map[string]any{"value": []map[string]any{{"id": id, "lastModifiedDateTime": modified}}}),
+		bctest.NewResponse(200, map[string]any{"value": []map[string]any{{"id": id, "lastModifiedDateTime": modified}}}),
+		bctest.NewResponse(200, map[string]any{"value": []map[string]any{{"id": id, "lastModifiedDateTime": modified}}}),
+	}}
+
+	calls := 0
+	handle := func(_ context.Context, n webhook.Notification) error {
+		calls++
+		if calls == 1 {
+			return errors.New("unavailable")
+		}
+		return nil
+	}
+	p, err := webhook.NewPoller(newClient(t, st), &bcsync.MemoryCheckpoints{}, []webhook.PollEntity{{EntitySetName: "items", Incremental: true}},
+		handle, webhook.PollerOptions{Initial: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := p.Poll(context.Background()); err == nil {
+		t.Fatal("wanted the error of the handler")
+	}
+	// The failed change is notified again, and then not anymore
+	for range 2 {
+		if err := p.Poll(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if calls != 2 {
+		t.Errorf("wanted 2 calls, got %d", calls)
+	}
+	if filter := st.Requests[2].URL.Query().Get("$filter"); filter == "" {
+		t.Error("wanted a lastModifiedDateTime filter after the first successful poll")
+	}
+}
+
+func TestNewPollerRepeated(t *testing.T) {
+	entities := []webhook.PollEntity{{EntitySetName: "customers"}, {EntitySetName: "customers"}}
+	noop := func(context.Context, webhook.Notification) error { return nil }
+	if _, err := webhook.NewPoller(newClient(t, &bctest.SequenceTransport{}), &bcsync.MemoryCheckpoints{}, entities, noop, webhook.PollerOptions{}); err == nil {
+		t.Error("wanted an error for a repeated entity set")
+	}
+}
@@ -0,0 +1,116 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/erlorenz/bc-go/bc"
+)
+
+// ReconcileOptions configure [Reconcile].
+type ReconcileOptions struct {
+	// RenewBefore renews the subscriptions that expire within it. Defaults to 24 hours.
+	RenewBefore time.Duration
+	// Owned returns true for the existing subscriptions that are managed by the
+	// reconciler, which are deleted if they are not desired. Defaults to those with
+	// the NotificationURL of a desired subscription, so the subscriptions of other
+	// applications are kept.
+	Owned func(s Subscription) bool
+	// DryRun returns the changes without making them.
+	DryRun bool
+	// Clock decides which subscriptions expire within RenewBefore.
+	// Defaults to the [bc.SystemClock].
+	Clock bc.Clock
+}
+
+// ReconcileResult has the subscriptions changed by [Reconcile].
+type ReconcileResult struct {
+	Created []Subscription
+	Renewed []Subscription
+	Deleted []Subscription
+	// Unchanged is the number of desired subscriptions that were up to date.
+	Unchanged int
+}
+
+// Reconcile makes the subscriptions in BC match the desired ones, so it can run on
+// every start of a service. A desired subscription matches an existing one with the
+// same Resource and NotificationURL. It is created if there is none, and renewed if
+// it expires within RenewBefore or has another ClientState. Owned subscriptions that
+// are not desired are deleted. Every change is attempted and the errors are joined.
+func Reconcile(ctx context.Context, client *bc.Client, desired []Subscription, opts ReconcileOptions) (ReconcileResult, error) {
+	var result ReconcileResult
+	if opts.RenewBefore <= 0 {
+		opts.RenewBefore = 24 * time.Hour
+	}
+	if opts.Clock == nil {
+		opts.Clock = bc.SystemClock
+	}
+	owned := opts.Owned
+	if owned == nil {
+		owned = func(s Subscription) bool {
+			return slices.ContainsFunc(desired, func(d Subscription) bool { return d.NotificationURL == s.NotificationURL })
+		}
+	}
+
+	existing, err := ListSubscriptions(ctx, client)
+	if err != nil {
+		return result, fmt.Errorf("reconcile subscriptions: %w", err)
+	}
+
+	var errs []error
+	matched := make([]bool, len(existing))
+	for _, d := range desired {
+		i := slices.IndexFunc(existing, func(s Subscription) bool { return sameSubscription(s, d) })
+		if i < 0 {
+			if !opts.DryRun {
+				created, err := CreateSubscription(ctx, client, d)
+				if err != nil {
+					errs = append(errs, fmt.Errorf("create %s: %w", d.Resource, err))
+					continue
+				}
+				d = created
+			}
+			result.Created = append(result.Created, d)
+			continue
+		}
+
+		matched[i] = true
+		s := existing[i]
+		if s.ExpirationDateTime.Sub(opts.Clock.Now()) > opts.RenewBefore && s.ClientState == d.ClientState {
+			result.Unchanged++
+			continue
+		}
+		s.ClientState = d.ClientState
+		if !opts.DryRun {
+			renewed, err := RenewSubscription(ctx, client, s)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("renew %s: %w", d.Resource, err))
+				continue
+			}
+			s = renewed
+		}
+		result.Renewed = append(result.Renewed, s)
+	}
+
+	for i, s := range existing {
+		if matched[i] || !owned(s) {
+			continue
+		}
+		if !opts.DryRun {
+			if err := DeleteSubscription(ctx, client, s.SubscriptionID, s.ETag); err != nil {
+				errs = append(errs, fmt.Errorf("delete %s: %w", s.Resource, err))
+				continue
+			}
+		}
+		result.Deleted = append(result.Deleted, s)
+	}
+	return result, errors.Join(errs...)
+}
+
+func sameSubscription(a, b Subscription) bool {
+	return strings.EqualFold(a.Resource, b.Resource) && a.NotificationURL == b.NotificationURL
+}
@@ -0,0 +1,62 @@
+package webhook_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/erlorenz/bc-go/webhook"
+)
+
+func TestReconcile(t *testing.T) {
+	const hook = "https://example.com/hook"
+	server := &subscriptionServer{subscriptions: map[string]webhook.Subscription{}}
+	client := newSubscriptionClient(t, server)
+	customers, _ := webhook.Resource(client, "customers")
+	items, _ := webhook.Resource(client, "items")
+	vendors, _ := webhook.Resource(client, "vendors")
+	orders, _ := webhook.Resource(client, "salesOrders")
+
+	soon, later := time.Now().Add(time.Hour), time.Now().Add(48*time.Hour)
+	server.add(webhook.Subscription{NotificationURL: hook, Resource: customers, ExpirationDateTime: later})
+	server.add(webhook.Subscription{NotificationURL: hook, Resource: items, ExpirationDateTime: soon})
+	server.add(webhook.Subscription{NotificationURL: hook, Resource: vendors, ExpirationDateTime: later})
+	server.add(webhook.Subscription{NotificationURL: "https://other.example.com", Resource: vendors, ExpirationDateTime: later})
+
+	desired := []webhook.Subscription{
+		{NotificationURL: hook, Resource: customers},
+		{NotificationURL: hook, Resource: items},
+		{NotificationURL: hook, Resource: orders},
+	}
+
+	dry, err := webhook.Reconcile(context.Background(), client, desired, webhook.ReconcileOptions{DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dry.Created) != 1 || len(server.subscriptions) != 4 {
+		t.Fatalf("wanted a dry run not to change anything, got %+v", dry)
+	}
+
+	result, err := webhook.Reconcile(context.Background(), client, desired, webhook.ReconcileOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Created) != 1 || result.Created[0].Resource != orders ||
+		len(result.Renewed) != 1 || result.Renewed[0].Resource != items ||
+		len(result.Deleted) != 1 || result.Deleted[0].NotificationURL != hook || result.Unchanged != 1 {
+		t.Errorf("unexpected result %+v", result)
+	}
+	// The subscription of another application is kept
+	if len(server.subscriptions) != 4 {
+		t.Errorf("wanted 4 subscriptions, got %+v", server.subscriptions)
+	}
+
+	// Running again changes nothing
+	result, err = webhook.Reconcile(context.Background(), client, desired, webhook.ReconcileOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Created)+len(result.Renewed)+len(result.Deleted) != 0 || result.Unchanged != 3 {
+		t.Errorf("wanted no changes, got %+v", result)
+	}
+}
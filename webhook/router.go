@@ -0,0 +1,86 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Middleware wraps the HandlerFunc of every route of a [Router], e.g. to log or
+// recover from panics. Add them with [Router.Use].
+type Middleware func(next HandlerFunc) HandlerFunc
+
+// Router calls the HandlerFunc registered for the entity set of a notification,
+// like an http.ServeMux for BC changes:
+//
+//	router := webhook.NewRouter()
+//	router.Use(webhook.Recover(), webhook.Logger(logger))
+//	router.On("customers", syncCustomer)
+//	router.On("salesOrders", syncOrder)
+//	http.Handle("/bc/notifications", &webhook.Handler{Handle: router.Handle, Workers: 8})
+//
+// It is safe for concurrent use.
+type Router struct {
+	mu         sync.RWMutex
+	routes     map[string]HandlerFunc
+	middleware []Middleware
+	notFound   HandlerFunc
+}
+
+// NewRouter returns a Router without routes.
+func NewRouter() *Router {
+	return &Router{routes: map[string]HandlerFunc{}}
+}
+
+// On registers the handler for the notifications of the entity set. It panics if
+// the entity set already has a handler.
+func (r *Router) On(entitySetName string, handler HandlerFunc) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if handler == nil {
+		panic("webhook: nil handler for " + entitySetName)
+	}
+	if _, ok := r.routes[entitySetName]; ok {
+		panic("webhook: multiple registrations for " + entitySetName)
+	}
+	r.routes[entitySetName] = handler
+}
+
+// NotFound sets the handler for the entity sets without a route. By default their
+// notifications are ignored.
+func (r *Router) NotFound(handler HandlerFunc) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.notFound = handler
+}
+
+// Use adds middleware around every route, including NotFound. The first is the outermost.
+func (r *Router) Use(middleware ...Middleware) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.middleware = append(r.middleware, middleware...)
+}
+
+// Handle calls the routed handler of the notification wrapped by the middleware.
+// It is a [HandlerFunc].
+func (r *Router) Handle(ctx context.Context, n Notification) error {
+	r.mu.RLock()
+	handler, ok := r.routes[n.EntitySetName()]
+	if !ok {
+		handler = r.notFound
+	}
+	middleware := r.middleware
+	r.mu.RUnlock()
+
+	if handler == nil {
+		return nil
+	}
+	for i := len(middleware) - 1; i >= 0; i-- {
+		handler = middleware[i](handler)
+	}
+	if err := handler(ctx, n); err != nil {
+		return fmt.Errorf("handle %s %s: %w", n.ChangeType, n.Resource, err)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package webhook_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/erlorenz/bc-go/webhook"
+)
+
+func notification(entitySetName string) webhook.Notification {
+	return webhook.Notification{
+		Resource:   "api/v2.0/companies(7d8f8d8a-2f2e-4b7a-9b4e-4d8d1c3f6a11)/" + entitySetName + "(26814998-936a-401c-81c1-0e848a64971d)",
+		ChangeType: webhook.ChangeUpdated,
+	}
+}
+
+func TestRouter(t *testing.T) {
+	var calls []string
+	record := func(name string) webhook.HandlerFunc {
+		return func(_ context.Context, n webhook.Notification) error {
+			calls = append(calls, name+":"+n.EntitySetName())
+			return nil
+		}
+	}
+	trace := func(name string) webhook.Middleware {
+		return func(next webhook.HandlerFunc) webhook.HandlerFunc {
+			return func(ctx context.Context, n webhook.Notification) error {
+				calls = append(calls, name)
+				return next(ctx, n)
+			}
+		}
+	}
+
+	router := webhook.NewRouter()
+	router.Use(trace("outer"), trace("inner"))
+	router.On("customers", record("customers"))
+
+	ctx := context.Background()
+	if err := router.Handle(ctx, notification("customers")); err != nil {
+		t.Fatal(err)
+	}
+	// Without a NotFound handler other entity sets are ignored
+	if err := router.Handle(ctx, notification("items")); err != nil {
+		t.Fatal(err)
+	}
+	router.NotFound(record("notFound"))
+	if err := router.Handle(ctx, notification("items")); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "outer inner customers:customers outer inner notFound:items"
+	if got := strings.Join(calls, " "); got != want {
+		t.Errorf("wanted calls %s, got %s", want, got)
+	}
+}
+
+func TestRouterOnTwice(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("wanted a panic for a second handler of the entity set")
+		}
+	}()
+	router := webhook.NewRouter()
+	handler := func(context.Context, webhook.Notification) error { return nil }
+	router.On("customers", handler)
+	router.On("customers", handler)
+}
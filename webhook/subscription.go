@@ -0,0 +1,157 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/erlorenz/bc-go/bc"
+)
+
+// Subscription is a webhook subscription of the API. BC expires it after 3 days
+// unless it is renewed.
+type Subscription struct {
+	SubscriptionID  string `json:"subscriptionId,omitempty"`
+	NotificationURL string `json:"notificationUrl"`
+	// Resource is the entity set relative to the environment, see [Resource].
+	Resource           string    `json:"resource"`
+	ClientState        string    `json:"clientState,omitempty"`
+	ExpirationDateTime time.Time `json:"expirationDateTime"`
+	ETag               string    `json:"@odata.etag,omitempty"`
+}
+
+// Validate implements [bc.Validator].
+func (Subscription) Validate() error { return nil }
+
+// body is the request body of a create or renew, without the read-only fields.
+func (s Subscription) body() map[string]any {
+	body := map[string]any{"notificationUrl": s.NotificationURL, "resource": s.Resource}
+	if s.ClientState != "" {
+		body["clientState"] = s.ClientState
+	}
+	return body
+}
+
+type subscriptionList struct {
+	Value []Subscription `json:"value"`
+}
+
+func (subscriptionList) Validate() error { return nil }
+
+// Resource returns the resource of an entity set of the client's company for a
+// subscription, e.g. "api/v2.0/companies(id)/customers".
+func Resource(client *bc.Client, entitySetName string) (string, error) {
+	base, err := bc.BuildBaseURL(client.Config())
+	if err != nil {
+		return "", err
+	}
+	_, path, _ := strings.Cut(base.Path, "/api/")
+	return "api/" + path + "/" + entitySetName, nil
+}
+
+// subscriptionsURL returns the URL of the subscriptions, at the root of the API.
+func subscriptionsURL(client *bc.Client) (string, error) {
+	u, err := bc.BuildBaseURL(client.Config())
+	if err != nil {
+		return "", err
+	}
+	if i := strings.LastIndex(u.Path, "/companies("); i >= 0 {
+		u.Path = u.Path[:i]
+	}
+	u.Path += "/subscriptions"
+	return u.String(), nil
+}
+
+// ListSubscriptions returns the subscriptions of the API endpoint of the client.
+func ListSubscriptions(ctx context.Context, client *bc.Client) ([]Subscription, error) {
+	u, err := subscriptionsURL(client)
+	if err != nil {
+		return nil, err
+	}
+	req, err := client.NewRequestURL(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Request: %w", err)
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed during request: %w", err)
+	}
+	list, err := bc.Decode[subscriptionList](res)
+	if err != nil {
+		return nil, err
+	}
+	return list.Value, nil
+}
+
+// CreateSubscription creates the subscription. BC sends a validation request to the
+// NotificationURL first, which a [Handler] answers.
+func CreateSubscription(ctx context.Context, client *bc.Client, s Subscription) (Subscription, error) {
+	u, err := subscriptionsURL(client)
+	if err != nil {
+		return Subscription{}, err
+	}
+	req, err := client.NewRequestURL(ctx, http.MethodPost, u, s.body())
+	if err != nil {
+		return Subscription{}, fmt.Errorf("failed to create Request: %w", err)
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return Subscription{}, fmt.Errorf("failed during request: %w", err)
+	}
+	return bc.Decode[Subscription](res)
+}
+
+// RenewSubscription extends the expiration of the subscription by sending it again,
+// which is also how the NotificationURL or ClientState are changed. The ETag of s is
+// sent as If-Match, "*" if empty.
+func RenewSubscription(ctx context.Context, client *bc.Client, s Subscription) (Subscription, error) {
+	u, err := subscriptionURL(client, s.SubscriptionID)
+	if err != nil {
+		return Subscription{}, err
+	}
+	req, err := client.NewRequestURL(ctx, http.MethodPatch, u, s.body())
+	if err != nil {
+		return Subscription{}, fmt.Errorf("failed to create Request: %w", err)
+	}
+	req.Header.Set("If-Match", ifMatch(s.ETag))
+	res, err := client.Do(req)
+	if err != nil {
+		return Subscription{}, fmt.Errorf("failed during request: %w", err)
+	}
+	return bc.Decode[Subscription](res)
+}
+
+// DeleteSubscription deletes the subscription if it has the etag, or any version for "*" or "".
+func DeleteSubscription(ctx context.Context, client *bc.Client, id, etag string) error {
+	u, err := subscriptionURL(client, id)
+	if err != nil {
+		return err
+	}
+	req, err := client.NewRequestURL(ctx, http.MethodDelete, u, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create Request: %w", err)
+	}
+	req.Header.Set("If-Match", ifMatch(etag))
+	res, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed during request: %w", err)
+	}
+	return bc.DecodeNoContent(res)
+}
+
+func subscriptionURL(client *bc.Client, id string) (string, error) {
+	u, err := subscriptionsURL(client)
+	if err != nil {
+		return "", err
+	}
+	return u + "('" + strings.ReplaceAll(id, "'", "''") + "')", nil
+}
+
+func ifMatch(etag string) string {
+	if etag == "" {
+		return "*"
+	}
+	return etag
+}
@@ -0,0 +1,132 @@
+package webhook_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/erlorenz/bc-go/bc"
+	"github.com/erlorenz/bc-go/internal/bctest"
+	"github.com/erlorenz/bc-go/webhook"
+)
+
+// subscriptionServer is the subscriptions endpoint of the API in memory.
+type subscriptionServer struct {
+	mu            sync.Mutex
+	subscriptions map[string]webhook.Subscription
+	next          int
+	ifMatch       []string
+}
+
+func (s *subscriptionServer) add(sub webhook.Subscription) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.next++
+	sub.SubscriptionID = fmt.Sprint(s.next)
+	sub.ETag = fmt.Sprintf(`W/"%d"`, s.next)
+	s.subscriptions[sub.SubscriptionID] = sub
+}
+
+func (s *subscriptionServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, rest, _ := strings.Cut(r.URL.Path, "/api/v2.0/subscriptions")
+	id := strings.TrimSuffix(strings.TrimPrefix(rest, "('"), "')")
+	w.Header().Set("Content-Type", "application/json")
+
+	switch r.Method {
+	case http.MethodGet:
+		var list []webhook.Subscription
+		for _, sub := range s.subscriptions {
+			list = append(list, sub)
+		}
+		json.NewEncoder(w).Encode(map[string]any{"value": list})
+	case http.MethodPost, http.MethodPatch:
+		var sub webhook.Subscription
+		json.NewDecoder(r.Body).Decode(&sub)
+		s.next++
+		if r.Method == http.MethodPost {
+			id = fmt.Sprint(s.next)
+		} else {
+			s.ifMatch = append(s.ifMatch, r.Header.Get("If-Match"))
+		}
+		sub.SubscriptionID = id
+		sub.ETag = fmt.Sprintf(`W/"%d"`, s.next)
+		sub.ExpirationDateTime = time.Now().Add(72 * time.Hour).UTC()
+		s.subscriptions[id] = sub
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(sub)
+	case http.MethodDelete:
+		s.ifMatch = append(s.ifMatch, r.Header.Get("If-Match"))
+		delete(s.subscriptions, id)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+const companyID = "7d8f8d8a-2f2e-4b7a-9b4e-4d8d1c3f6a11"
+
+// newClient returns a client of the companyID.
+func newClient(t *testing.T, transport http.RoundTripper) *bc.Client {
+	t.Helper()
+	config := bctest.ClientConfig()
+	config.CompanyID = companyID
+	return bctest.NewClientConfig(t, config, transport)
+}
+
+func newSubscriptionClient(t *testing.T, server *subscriptionServer) *bc.Client {
+	t.Helper()
+	return newClient(t, bc.RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, r)
+		return rec.Result(), nil
+	}))
+}
+
+func TestSubscriptions(t *testing.T) {
+	server := &subscriptionServer{subscriptions: map[string]webhook.Subscription{}}
+	client := newSubscriptionClient(t, server)
+	ctx := context.Background()
+
+	resource, err := webhook.Resource(client, "customers")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resource != "api/v2.0/companies("+companyID+")/customers" {
+		t.Errorf("unexpected resource %s", resource)
+	}
+
+	created, err := webhook.CreateSubscription(ctx, client, webhook.Subscription{NotificationURL: "https://example.com/hook", Resource: resource})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.SubscriptionID == "" || created.ExpirationDateTime.IsZero() {
+		t.Errorf("unexpected subscription %+v", created)
+	}
+
+	created.ClientState = "secret"
+	renewed, err := webhook.RenewSubscription(ctx, client, created)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if renewed.ClientState != "secret" || renewed.ETag == created.ETag {
+		t.Errorf("unexpected renewed subscription %+v", renewed)
+	}
+
+	list, err := webhook.ListSubscriptions(ctx, client)
+	if err != nil || len(list) != 1 {
+		t.Fatalf("wanted 1 subscription, got %+v %v", list, err)
+	}
+	if err := webhook.DeleteSubscription(ctx, client, renewed.SubscriptionID, ""); err != nil {
+		t.Fatal(err)
+	}
+	if len(server.subscriptions) != 0 || strings.Join(server.ifMatch, " ") != created.ETag+" *" {
+		t.Errorf("unexpected If-Match headers %v", server.ifMatch)
+	}
+}